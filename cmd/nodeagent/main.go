@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	ctlcore "github.com/rancher/wrangler/v3/pkg/generated/controllers/core"
+	"github.com/rancher/wrangler/v3/pkg/kubeconfig"
+	"github.com/rancher/wrangler/v3/pkg/signals"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	"github.com/cloudweav/cloudweav/pkg/cmd"
+	"github.com/cloudweav/cloudweav/pkg/config"
+	ctlcloudweav "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io"
+	"github.com/cloudweav/cloudweav/pkg/util/nodehealth"
+	"github.com/cloudweav/cloudweav/pkg/util/numa"
+)
+
+type options struct {
+	NodeName         string
+	HealthInterval   time.Duration
+	TopologyInterval time.Duration
+}
+
+func main() {
+	var opts options
+
+	flags := []cli.Flag{
+		cli.StringFlag{
+			Name:        "node-name",
+			EnvVar:      "NODE_NAME",
+			Usage:       "The name of the node the agent runs on",
+			Destination: &opts.NodeName,
+			Required:    true,
+		},
+		cli.DurationFlag{
+			Name:        "health-interval",
+			EnvVar:      "HEALTH_INTERVAL",
+			Usage:       "How often the hardware health of the node is reported",
+			Value:       time.Minute,
+			Destination: &opts.HealthInterval,
+		},
+		cli.DurationFlag{
+			Name:        "topology-interval",
+			EnvVar:      "TOPOLOGY_INTERVAL",
+			Usage:       "How often the NUMA topology of the node is reported",
+			Value:       5 * time.Minute,
+			Destination: &opts.TopologyInterval,
+		},
+	}
+
+	app := cmd.NewApp("Cloudweav Node Agent", "Reports the hardware health and the NUMA topology of the node the agent runs on", flags, func(commonOptions *config.CommonOptions) error {
+		return run(commonOptions, &opts)
+	})
+	app.Run()
+}
+
+func run(commonOptions *config.CommonOptions, opts *options) error {
+	logrus.Infof("Starting node agent on node %s", opts.NodeName)
+
+	ctx := signals.SetupSignalContext()
+
+	restConfig, err := kubeconfig.GetNonInteractiveClientConfig(commonOptions.KubeConfig).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to find kubeconfig: %w", err)
+	}
+
+	cloudweavFactory, err := ctlcloudweav.NewFactoryFromConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	coreFactory, err := ctlcore.NewFactoryFromConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	go nodehealth.NewCollector().Run(ctx, cloudweavFactory.Cloudweavhci().V1beta1().NodeHealth(), opts.NodeName, opts.HealthInterval)
+	go numa.Run(ctx, coreFactory.Core().V1().Node(), opts.NodeName, "/sys", opts.TopologyInterval)
+
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,81 @@
+package cleanuporphans
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/cloudweav/cloudweav/cmd/upgradehelper/cmd"
+	"github.com/cloudweav/cloudweav/pkg/upgradehelper/diagnostics"
+)
+
+var cleanupOrphansCmd = &cobra.Command{
+	Use:   "cleanup-orphans",
+	Short: "Cleanup Orphans",
+	Long: `Deletes the repo VMs, the repo images and the plans left behind by upgrades
+
+An object is left behind if the upgrade it was created for is gone or completed.
+With --dry-run, the orphans are only listed.
+If any orphan can't be deleted, the command exits with code 1.
+	`,
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		ctx := context.Context(context.Background())
+		if err := run(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	cmd.RootCmd.AddCommand(cleanupOrphansCmd)
+}
+
+func run(ctx context.Context) error {
+	logrus.Infof("Starting Cleanup Orphans (dry run: %t)", cmd.DryRun)
+	clients, err := cmd.NewClients()
+	if err != nil {
+		return err
+	}
+	report, err := diagnostics.CleanupOrphans(ctx, clients, cmd.DryRun)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Output == cmd.OutputJSON {
+		err = cmd.PrintJSON(os.Stdout, report)
+	} else {
+		err = printTable(report)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, orphan := range report.Orphans {
+		if orphan.Error != "" {
+			return fmt.Errorf("some orphans can't be deleted")
+		}
+	}
+	return nil
+}
+
+func printTable(report *diagnostics.OrphansReport) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAMESPACE\tNAME\tREASON\tRESULT")
+	for _, orphan := range report.Orphans {
+		result := "deleted"
+		switch {
+		case report.DryRun:
+			result = "dry run"
+		case orphan.Error != "":
+			result = orphan.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", orphan.Kind, orphan.Namespace, orphan.Name, orphan.Reason, result)
+	}
+	return w.Flush()
+}
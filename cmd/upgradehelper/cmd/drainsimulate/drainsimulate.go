@@ -0,0 +1,76 @@
+package drainsimulate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/cloudweav/cloudweav/cmd/upgradehelper/cmd"
+	"github.com/cloudweav/cloudweav/pkg/upgradehelper/diagnostics"
+)
+
+var drainSimulateCmd = &cobra.Command{
+	Use:   "node-drain-simulate NODENAME",
+	Short: "Node Drain Simulation",
+	Long: `Tells what the drain of the upgrade does to the pods of a node without draining it
+
+Each pod is ignored, evicted, live migrated, shut down or blocked by a pod disruption budget.
+The simulation only reads the cluster, so it's always a dry run.
+If any eviction is blocked, the command exits with code 1.
+	`,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx := context.Context(context.Background())
+		if err := run(ctx, args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	cmd.RootCmd.AddCommand(drainSimulateCmd)
+}
+
+func run(ctx context.Context, nodeName string) error {
+	logrus.Infof("Simulating the drain of node %s", nodeName)
+	clients, err := cmd.NewClients()
+	if err != nil {
+		return err
+	}
+	input, err := diagnostics.CollectDrainInput(ctx, clients, nodeName)
+	if err != nil {
+		return err
+	}
+	report, err := diagnostics.SimulateDrain(input)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Output == cmd.OutputJSON {
+		err = cmd.PrintJSON(os.Stdout, report)
+	} else {
+		err = printTable(report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if report.Blocked {
+		return fmt.Errorf("the drain of node %s is blocked", nodeName)
+	}
+	return nil
+}
+
+func printTable(report *diagnostics.DrainReport) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tPOD\tACTION\tREASON")
+	for _, pod := range report.Pods {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", pod.Namespace, pod.Name, pod.Action, pod.Reason)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,70 @@
+package imagepreloadstatus
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/cloudweav/cloudweav/cmd/upgradehelper/cmd"
+	"github.com/cloudweav/cloudweav/pkg/upgradehelper/diagnostics"
+)
+
+var imagePreloadStatusCmd = &cobra.Command{
+	Use:   "image-preload-status [UPGRADE]",
+	Short: "Image Preload Status",
+	Long: `Shows the progress of the image preloading of an upgrade on each node
+
+The state of each node is merged with the latest job of the prepare plan on it.
+The latest upgrade is shown if no upgrade is specified.
+The command only reads the cluster, so it's always a dry run.
+	`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx := context.Context(context.Background())
+		var upgradeName string
+		if len(args) > 0 {
+			upgradeName = args[0]
+		}
+		if err := run(ctx, upgradeName); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	cmd.RootCmd.AddCommand(imagePreloadStatusCmd)
+}
+
+func run(ctx context.Context, upgradeName string) error {
+	logrus.Info("Collecting Image Preload Status")
+	clients, err := cmd.NewClients()
+	if err != nil {
+		return err
+	}
+	report, err := diagnostics.CollectPreloadStatus(ctx, clients, upgradeName)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Output == cmd.OutputJSON {
+		return cmd.PrintJSON(os.Stdout, report)
+	}
+
+	fmt.Printf("Upgrade %s: %d/%d nodes preloaded\n", report.Upgrade, report.Preloaded, len(report.Nodes))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tSTATE\tJOB\tJOB STATUS\tDURATION\tMESSAGE")
+	for _, node := range report.Nodes {
+		duration := "-"
+		if node.Duration != nil {
+			duration = (time.Duration(*node.Duration) * time.Second).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", node.Node, node.State, node.Job, node.JobStatus, duration, node.Message)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,113 @@
+package readiness
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudweav/cloudweav/cmd/upgradehelper/cmd"
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/upgradehelper/readiness"
+)
+
+const (
+	cloudweavSystemNamespace = "cloudweav-system"
+)
+
+var (
+	overrides []string
+
+	readinessCmd = &cobra.Command{
+		Use:   "upgrade-readiness [VERSION]",
+		Short: "Upgrade Readiness",
+		Long: `Runs the pre-flight checks of an upgrade, the same checks the upgrade webhook runs before an upgrade is created.
+
+Each check reports pass, warn or fail with a remediation. The version checks are skipped if no version is specified.
+If any check which isn't overridden fails, the command exits with code 1.
+If the cluster is ready, the command exits normally with code 0.
+	`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			ctx := context.Context(context.Background())
+			var versionName string
+			if len(args) > 0 {
+				versionName = args[0]
+			}
+			if err := run(ctx, versionName); err != nil {
+				fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func init() {
+	readinessCmd.Flags().StringSliceVar(&overrides, "override", nil, "The names of the checks whose failures are ignored")
+
+	cmd.RootCmd.AddCommand(readinessCmd)
+}
+
+func run(ctx context.Context, versionName string) error {
+	logrus.Info("Starting Upgrade Readiness")
+
+	clients, err := cmd.NewClients()
+	if err != nil {
+		return err
+	}
+	client := clients.Cloudweav
+
+	serverVersion, err := client.CloudweavhciV1beta1().Settings().Get(ctx, settings.ServerVersionSettingName, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	currentVersion := serverVersion.Value
+	if currentVersion == "" {
+		currentVersion = serverVersion.Default
+	}
+
+	var target *cloudweavv1.Version
+	if versionName != "" {
+		if target, err = client.CloudweavhciV1beta1().Versions(cloudweavSystemNamespace).Get(ctx, versionName, v1.GetOptions{}); err != nil {
+			return err
+		}
+	}
+
+	snapshot, err := readiness.NewSnapshot(ctx, clients.Kube, client, currentVersion, target)
+	if err != nil {
+		return err
+	}
+	checks, ready := readiness.Run(snapshot, overrides)
+
+	if cmd.Output == cmd.OutputJSON {
+		err = cmd.PrintJSON(os.Stdout, checks)
+	} else {
+		err = printTable(checks)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !ready {
+		return fmt.Errorf("the cluster isn't ready for the upgrade")
+	}
+	return nil
+}
+
+func printTable(checks []cloudweavv1.ReadinessCheck) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tRESULT\tMESSAGE\tREMEDIATION")
+	for _, check := range checks {
+		result := string(check.Result)
+		if check.Overridden {
+			result += " (overridden)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", check.Name, result, check.Message, check.Remediation)
+	}
+	return w.Flush()
+}
@@ -16,6 +16,16 @@ var (
 
 	KubeConfigPath string
 	KubeContext    string
+
+	// DryRun keeps the helpers from changing the cluster, they only report what they would do
+	DryRun bool
+	// Output is the format of the reports, table or json
+	Output string
+)
+
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
 )
 
 var RootCmd = &cobra.Command{
@@ -23,14 +33,23 @@ var RootCmd = &cobra.Command{
 	Short:   "Cloudweav Upgrade Helpers",
 	Long:    "A collection of upgrade helpers for Cloudweav",
 	Version: fmt.Sprintf("%s (%s)", version.Version, version.GitCommit),
-	PersistentPreRun: func(_ *cobra.Command, _ []string) {
-		logrus.SetOutput(os.Stdout)
+	PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+		if Output != OutputTable && Output != OutputJSON {
+			return fmt.Errorf("unsupported output format %q, it's either %s or %s", Output, OutputTable, OutputJSON)
+		}
+		// keep the logs out of the JSON reports
+		if Output == OutputJSON {
+			logrus.SetOutput(os.Stderr)
+		} else {
+			logrus.SetOutput(os.Stdout)
+		}
 		if logDebug {
 			logrus.SetLevel(logrus.DebugLevel)
 		}
 		if logTrace {
 			logrus.SetLevel(logrus.TraceLevel)
 		}
+		return nil
 	},
 }
 
@@ -42,4 +61,6 @@ func init() {
 	RootCmd.PersistentFlags().BoolVar(&logTrace, "trace", trace, "set logging level to trace")
 	RootCmd.PersistentFlags().StringVar(&KubeConfigPath, "kubeconfig", os.Getenv("KUBECONFIG"), "Path to the kubeconfig file")
 	RootCmd.PersistentFlags().StringVar(&KubeContext, "kubecontext", os.Getenv("KUBECONTEXT"), "Context name")
+	RootCmd.PersistentFlags().BoolVar(&DryRun, "dry-run", envGetBool("DRY_RUN", false), "Report what would be changed without changing the cluster")
+	RootCmd.PersistentFlags().StringVarP(&Output, "output", "o", OutputTable, "Output format, table or json")
 }
@@ -0,0 +1,82 @@
+package stuckupgrade
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/cloudweav/cloudweav/cmd/upgradehelper/cmd"
+	"github.com/cloudweav/cloudweav/pkg/upgradehelper/diagnostics"
+)
+
+var stuckUpgradeCmd = &cobra.Command{
+	Use:   "stuck-upgrade-diagnose [UPGRADE]",
+	Short: "Stuck Upgrade Diagnosis",
+	Long: `Looks for what keeps an upgrade from moving on
+
+The state and conditions of the upgrade, the states of its nodes, its plans and jobs, and the nodes waiting to reboot
+into the new OS or left cordoned are inspected. The latest upgrade is diagnosed if no upgrade is specified.
+The command only reads the cluster, so it's always a dry run.
+If any error is found, the command exits with code 1.
+	`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		ctx := context.Context(context.Background())
+		var upgradeName string
+		if len(args) > 0 {
+			upgradeName = args[0]
+		}
+		if err := run(ctx, upgradeName); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	cmd.RootCmd.AddCommand(stuckUpgradeCmd)
+}
+
+func run(ctx context.Context, upgradeName string) error {
+	logrus.Info("Starting Stuck Upgrade Diagnosis")
+	clients, err := cmd.NewClients()
+	if err != nil {
+		return err
+	}
+	input, err := diagnostics.CollectStuckInput(ctx, clients, upgradeName)
+	if err != nil {
+		return err
+	}
+	report := diagnostics.DiagnoseStuck(input, time.Now())
+
+	if cmd.Output == cmd.OutputJSON {
+		err = cmd.PrintJSON(os.Stdout, report)
+	} else {
+		err = printTable(report)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, finding := range report.Findings {
+		if finding.Severity == diagnostics.SeverityError {
+			return fmt.Errorf("upgrade %s has errors", report.Upgrade)
+		}
+	}
+	return nil
+}
+
+func printTable(report *diagnostics.StuckReport) error {
+	fmt.Printf("Upgrade %s: %s\n", report.Upgrade, report.State)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SEVERITY\tOBJECT\tMESSAGE")
+	for _, finding := range report.Findings {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", finding.Severity, finding.Object, finding.Message)
+	}
+	return w.Flush()
+}
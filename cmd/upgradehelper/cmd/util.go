@@ -1,8 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
+	"io"
 	"os"
 	"strconv"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned"
+	"github.com/cloudweav/cloudweav/pkg/upgradehelper/diagnostics"
 )
 
 func envGetBool(key string, defaultValue bool) bool {
@@ -11,3 +20,36 @@ func envGetBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// NewClients builds the clients of the cluster selected by the kubeconfig and kubecontext flags
+func NewClients() (*diagnostics.Clients, error) {
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{
+			ExplicitPath: KubeConfigPath,
+		},
+		&clientcmd.ConfigOverrides{
+			ClusterInfo:    clientcmdapi.Cluster{},
+			CurrentContext: KubeContext,
+		},
+	)
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	client, err := versioned.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &diagnostics.Clients{Kube: kubeClient, Cloudweav: client}, nil
+}
+
+// PrintJSON writes the report as indented JSON
+func PrintJSON(w io.Writer, report interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
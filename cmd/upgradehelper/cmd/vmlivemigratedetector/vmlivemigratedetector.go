@@ -22,7 +22,7 @@ var vmLiveMigrateDetectorCmd = &cobra.Command{
 	Long: `A simple VM detector and executor for Cloudweav upgrades
 
 The detector accepts a node name and inferences the possible places the VMs on top of it could be live migrated to.
-If there is no place to go, it can optionally shut down the VMs.
+If there is no place to go, it can optionally shut down the VMs, unless it's a dry run.
 	`,
 	Args: cobra.ExactArgs(1),
 	Run: func(_ *cobra.Command, args []string) {
@@ -30,7 +30,7 @@ If there is no place to go, it can optionally shut down the VMs.
 		options := vmlivemigratedetector.DetectorOptions{
 			KubeConfigPath: cmd.KubeConfigPath,
 			KubeContext:    cmd.KubeContext,
-			Shutdown:       shutdown,
+			Shutdown:       shutdown && !cmd.DryRun,
 			NodeName:       args[0],
 		}
 		if err := run(ctx, options); err != nil {
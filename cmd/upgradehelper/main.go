@@ -4,6 +4,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/cloudweav/cloudweav/cmd/upgradehelper/cmd"
+	_ "github.com/cloudweav/cloudweav/cmd/upgradehelper/cmd/cleanuporphans"
+	_ "github.com/cloudweav/cloudweav/cmd/upgradehelper/cmd/drainsimulate"
+	_ "github.com/cloudweav/cloudweav/cmd/upgradehelper/cmd/imagepreloadstatus"
+	_ "github.com/cloudweav/cloudweav/cmd/upgradehelper/cmd/readiness"
+	_ "github.com/cloudweav/cloudweav/cmd/upgradehelper/cmd/stuckupgrade"
 	_ "github.com/cloudweav/cloudweav/cmd/upgradehelper/cmd/versionguard"
 	_ "github.com/cloudweav/cloudweav/cmd/upgradehelper/cmd/vmlivemigratedetector"
 )
@@ -142,13 +142,16 @@ get_repo_vm_status()
 
 wait_repo()
 {
-  # Start upgrade repo VM in case it's shut down due to migration timeout or job failure
-  until [[ "$(get_repo_vm_status)" == "Running" ]]
-  do
-    echo "Try to bring up the upgrade repo VM..."
-    virtctl start $UPGRADE_REPO_VM_NAME -n $UPGRADE_NAMESPACE || true
-    sleep 10
-  done
+  # Start upgrade repo VM in case it's shut down due to migration timeout or job failure.
+  # There's no VM if the upgrade repo is served by the bundle server pods.
+  if kubectl get virtualmachines.kubevirt.io $UPGRADE_REPO_VM_NAME -n $UPGRADE_NAMESPACE >/dev/null 2>&1; then
+    until [[ "$(get_repo_vm_status)" == "Running" ]]
+    do
+      echo "Try to bring up the upgrade repo VM..."
+      virtctl start $UPGRADE_REPO_VM_NAME -n $UPGRADE_NAMESPACE || true
+      sleep 10
+    done
+  fi
 
   until curl -sfL $UPGRADE_REPO_RELEASE_FILE
   do
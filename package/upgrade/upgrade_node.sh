@@ -523,6 +523,7 @@ stages:
     initramfs:
         - commands:
             - rm -f /var/lib/kubelet/cpu_manager_state
+            - rm -f /var/lib/kubelet/memory_manager_state
 EOF
 }
 
@@ -18,6 +18,7 @@ import (
 	"github.com/sirupsen/logrus"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	apisv1beta1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
 	"github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
@@ -50,7 +51,11 @@ type Handler struct {
 	ImageCache                  v1beta1.VirtualMachineImageCache
 	BackingImageDataSources     ctllhv1.BackingImageDataSourceClient
 	BackingImageDataSourceCache ctllhv1.BackingImageDataSourceCache
+	BackingImages               ctllhv1.BackingImageClient
 	BackingImageCache           ctllhv1.BackingImageCache
+	Versions                    v1beta1.VersionClient
+	SettingCache                v1beta1.SettingCache
+	clientSet                   kubernetes.Clientset
 }
 
 func (h Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -153,6 +158,12 @@ func (h Handler) uploadImage(_ http.ResponseWriter, req *http.Request) error {
 		return err
 	}
 
+	if isUpgradeISO(image) {
+		if err := h.checkCreateVersion(req); err != nil {
+			return err
+		}
+	}
+
 	defer func() {
 		if err != nil {
 			if updateErr := h.updateImportedConditionOnConflict(image, "False", "UploadFailed", err.Error()); updateErr != nil {
@@ -171,8 +182,15 @@ func (h Handler) uploadImage(_ http.ResponseWriter, req *http.Request) error {
 		return err
 	}
 
+	var uploadBody io.Reader = req.Body
+	var upload *isoUpload
+	if isUpgradeISO(image) {
+		upload, uploadBody = newISOUpload(req.Body, req.Header.Get("Content-Type"))
+		defer upload.close()
+	}
+
 	uploadURL := fmt.Sprintf("%s/backingimages/%s", util.LonghornDefaultManagerURL, dsName)
-	uploadReq, err := http.NewRequestWithContext(req.Context(), http.MethodPost, uploadURL, req.Body)
+	uploadReq, err := http.NewRequestWithContext(req.Context(), http.MethodPost, uploadURL, uploadBody)
 	if err != nil {
 		return fmt.Errorf("failed to create the upload request: %w", err)
 	}
@@ -201,6 +219,24 @@ func (h Handler) uploadImage(_ http.ResponseWriter, req *http.Request) error {
 		return err
 	}
 
+	if upload != nil {
+		release, checksum, isoErr := upload.wait()
+		if isoErr == nil {
+			isoErr = h.verifyUpgradeISO(image, release, checksum)
+		}
+		if isoErr != nil {
+			// the unverified ISO must not be used, the image controller recreates an empty backing image to upload again
+			if deleteErr := h.deleteBackingImage(image); deleteErr != nil {
+				logrus.WithError(deleteErr).Errorf("failed to delete the backing image of VMImage %s/%s", namespace, name)
+			}
+			err = fmt.Errorf("invalid upgrade ISO: %w", isoErr)
+			return err
+		}
+		if err = h.createUpgradeVersion(image, release, checksum); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
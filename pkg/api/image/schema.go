@@ -18,7 +18,11 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server, _ config.Optio
 		ImageCache:                  scaled.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineImage().Cache(),
 		BackingImageDataSources:     scaled.LonghornFactory.Longhorn().V1beta2().BackingImageDataSource(),
 		BackingImageDataSourceCache: scaled.LonghornFactory.Longhorn().V1beta2().BackingImageDataSource().Cache(),
+		BackingImages:               scaled.LonghornFactory.Longhorn().V1beta2().BackingImage(),
 		BackingImageCache:           scaled.LonghornFactory.Longhorn().V1beta2().BackingImage().Cache(),
+		Versions:                    scaled.CloudweavFactory.Cloudweavhci().V1beta1().Version(),
+		SettingCache:                scaled.CloudweavFactory.Cloudweavhci().V1beta1().Setting().Cache(),
+		clientSet:                   *scaled.Management.ClientSet,
 	}
 
 	t := schema.Template{
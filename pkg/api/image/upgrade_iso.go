@@ -0,0 +1,206 @@
+package image
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	apiutil "github.com/cloudweav/cloudweav/pkg/api/util"
+	apisv1beta1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/controller/master/upgrade/repoinfo"
+	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/upgradehelper/versionguard"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+func isUpgradeISO(image *apisv1beta1.VirtualMachineImage) bool {
+	return image.Annotations[util.AnnotationUpgradeISO] == "true"
+}
+
+// isoUpload reads the release manifest and the checksum of an upgrade ISO while it's being uploaded, so the ISO
+// doesn't have to be read back from the backing image
+type isoUpload struct {
+	writer   *io.PipeWriter
+	done     chan struct{}
+	release  *repoinfo.CloudweavRelease
+	checksum string
+	err      error
+}
+
+// newISOUpload returns the upload and the body to forward, the upload reads whatever is read from the body
+func newISOUpload(body io.Reader, contentType string) (*isoUpload, io.Reader) {
+	reader, writer := io.Pipe()
+	upload := &isoUpload{
+		writer: writer,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(upload.done)
+		upload.release, upload.checksum, upload.err = readUploadedISO(reader, contentType)
+		// keep draining the pipe so the forwarded body is never blocked
+		_, _ = io.Copy(io.Discard, reader)
+	}()
+	return upload, io.TeeReader(body, writer)
+}
+
+// wait returns the result after the whole body is forwarded
+func (u *isoUpload) wait() (*repoinfo.CloudweavRelease, string, error) {
+	u.close()
+	<-u.done
+	return u.release, u.checksum, u.err
+}
+
+func (u *isoUpload) close() {
+	_ = u.writer.Close()
+}
+
+// readUploadedISO reads the ISO in the multipart upload request
+func readUploadedISO(r io.Reader, contentType string) (*repoinfo.CloudweavRelease, string, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse the content type: %w", err)
+	}
+	parts := multipart.NewReader(r, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, "", errors.New("no file is uploaded")
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if part.FileName() == "" {
+			continue
+		}
+
+		hash := sha512.New()
+		file := io.TeeReader(part, hash)
+		release, err := repoinfo.ReadISORelease(file)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.Copy(io.Discard, file); err != nil {
+			return nil, "", err
+		}
+		return release, hex.EncodeToString(hash.Sum(nil)), nil
+	}
+}
+
+// checkCreateVersion checks whether the user of the upload request is allowed to create versions, the upload of an
+// upgrade ISO creates the version of the ISO
+func (h Handler) checkCreateVersion(req *http.Request) error {
+	user, ok := request.UserFrom(req.Context())
+	if !ok {
+		return apierror.NewAPIError(validation.Unauthorized, "failed to get user from request")
+	}
+
+	allowed, err := apiutil.CanAccessResource(h.clientSet, user, &authorizationv1.ResourceAttributes{
+		Namespace: util.CloudweavSystemNamespaceName,
+		Verb:      "create",
+		Group:     apisv1beta1.SchemeGroupVersion.Group,
+		Version:   apisv1beta1.SchemeGroupVersion.Version,
+		Resource:  "versions",
+	})
+	if err != nil {
+		return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("Failed to check permission: %v", err))
+	}
+	if !allowed {
+		return apierror.NewAPIError(validation.PermissionDenied, fmt.Sprintf("User %s is not allowed to create versions, an upgrade ISO can't be uploaded", user.GetName()))
+	}
+	return nil
+}
+
+// verifyUpgradeISO verifies the checksum of the uploaded upgrade ISO and its signature against the trusted keys, and
+// that the cluster can be upgraded to its version
+func (h Handler) verifyUpgradeISO(image *apisv1beta1.VirtualMachineImage, release *repoinfo.CloudweavRelease, checksum string) error {
+	if image.Spec.Checksum != "" && image.Spec.Checksum != checksum {
+		return fmt.Errorf("checksum of the ISO %s doesn't match the expected checksum %s", checksum, image.Spec.Checksum)
+	}
+
+	trustedKeys, err := h.getSettingValue(settings.UpgradeISOTrustedKeysSettingName)
+	if err != nil {
+		return err
+	}
+	if err := repoinfo.VerifyISOSignature(trustedKeys, checksum, image.Annotations[util.AnnotationUpgradeISOSignature]); err != nil {
+		return fmt.Errorf("failed to verify the signature of the ISO: %w", err)
+	}
+
+	currentVersion, err := h.getSettingValue(settings.ServerVersionSettingName)
+	if err != nil {
+		return err
+	}
+	version := &apisv1beta1.Version{
+		ObjectMeta: metav1.ObjectMeta{Name: release.Cloudweav},
+		Spec:       apisv1beta1.VersionSpec{MinUpgradableVersion: release.MinUpgradableVersion},
+	}
+	if err := versionguard.CheckVersion(currentVersion, version, true); err != nil {
+		return fmt.Errorf("version %s can't be upgraded to %s: %w", currentVersion, version.Name, err)
+	}
+	return nil
+}
+
+// createUpgradeVersion creates the version of the verified upgrade ISO, so the cluster can be upgraded without
+// reaching the upgrade checker
+func (h Handler) createUpgradeVersion(image *apisv1beta1.VirtualMachineImage, release *repoinfo.CloudweavRelease, checksum string) error {
+	version := &apisv1beta1.Version{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      release.Cloudweav,
+			Namespace: util.CloudweavSystemNamespaceName,
+		},
+		Spec: apisv1beta1.VersionSpec{
+			ISOImage:             fmt.Sprintf("%s/%s", image.Namespace, image.Name),
+			ISOChecksum:          checksum,
+			MinUpgradableVersion: release.MinUpgradableVersion,
+		},
+	}
+
+	_, err := h.Versions.Create(version)
+	if apierrors.IsAlreadyExists(err) {
+		existing, err := h.Versions.Get(version.Namespace, version.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if existing.Spec.ISOImage != version.Spec.ISOImage {
+			return fmt.Errorf("version %s already exists", version.Name)
+		}
+		return nil
+	}
+	return err
+}
+
+func (h Handler) getSettingValue(name string) (string, error) {
+	setting, err := h.SettingCache.Get(name)
+	if err != nil {
+		return "", err
+	}
+	if setting.Value != "" {
+		return setting.Value, nil
+	}
+	return setting.Default, nil
+}
+
+func (h Handler) deleteBackingImage(image *apisv1beta1.VirtualMachineImage) error {
+	biName, err := util.GetBackingImageName(h.BackingImageCache, image)
+	if err != nil {
+		return err
+	}
+
+	propagation := metav1.DeletePropagationForeground
+	err = h.BackingImages.Delete(util.LonghornSystemNamespaceName, biName, &metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -51,12 +51,7 @@ func (h KeyGenActionHandler) do(rw http.ResponseWriter, req *http.Request) error
 	if input.Name == "" || input.Namespace == "" {
 		return apierror.NewAPIError(validation.InvalidBodyContent, "both name and namespace is required")
 	}
-	rsaKey, err := util.GeneratePrivateKey(2048)
-	if err != nil {
-		return err
-	}
-	privateKey := util.EncodePrivateKeyToPEM(rsaKey)
-	publicKey, err := util.GeneratePublicKey(&rsaKey.PublicKey)
+	privateKey, publicKey, err := generateKey(input.KeyType)
 	if err != nil {
 		return err
 	}
@@ -81,3 +76,22 @@ func (h KeyGenActionHandler) do(rw http.ResponseWriter, req *http.Request) error
 	_, err = rw.Write(privateKey)
 	return err
 }
+
+func generateKey(keyType string) ([]byte, []byte, error) {
+	switch keyType {
+	case "", keyTypeRSA:
+		rsaKey, err := util.GeneratePrivateKey(2048)
+		if err != nil {
+			return nil, nil, err
+		}
+		publicKey, err := util.GeneratePublicKey(&rsaKey.PublicKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		return util.EncodePrivateKeyToPEM(rsaKey), publicKey, nil
+	case keyTypeEd25519:
+		return util.GenerateEd25519KeyPair()
+	default:
+		return nil, nil, apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("unsupported key type %s, must be %s or %s", keyType, keyTypeRSA, keyTypeEd25519))
+	}
+}
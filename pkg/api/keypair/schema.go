@@ -14,6 +14,9 @@ import (
 
 const (
 	keygen = "keygen"
+
+	keyTypeRSA     = "rsa"
+	keyTypeEd25519 = "ed25519"
 )
 
 func RegisterSchema(scaled *config.Scaled, server *server.Server, _ config.Options) error {
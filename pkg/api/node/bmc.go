@@ -0,0 +1,69 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
+	"github.com/rancher/wrangler/v3/pkg/slice"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlnode "github.com/cloudweav/cloudweav/pkg/controller/master/node"
+	"github.com/cloudweav/cloudweav/pkg/util/bmc"
+)
+
+const (
+	bmcOperationTimeout = 2 * time.Minute
+)
+
+// bmcPowerActionPossible reports whether the node BMC can run power actions, it must have been reached by the last poll
+func (h ActionHandler) bmcPowerActionPossible(rw http.ResponseWriter, nodeBMC *cloudweavv1.NodeBMC) error {
+	if !cloudweavv1.NodeBMCReachable.IsTrue(nodeBMC) {
+		rw.WriteHeader(http.StatusConflict)
+		return fmt.Errorf("BMC of node %s is not reachable: %s", nodeBMC.Name, cloudweavv1.NodeBMCReachable.GetMessage(nodeBMC))
+	}
+	rw.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// bmcPowerAction runs the operation directly against the node BMC and records its result in the BMC status
+func (h ActionHandler) bmcPowerAction(nodeBMC *cloudweavv1.NodeBMC, input PowerActionInput) error {
+	if !slice.ContainsString(bmc.Operations, input.Operation) {
+		return apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("operation %s is not a valid BMC operation. valid values need to be in %v", input.Operation, bmc.Operations))
+	}
+
+	client, err := ctlnode.NewBMCClient(h.secretCache, nodeBMC, h.newBMCClient)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(h.ctx, bmcOperationTimeout)
+	defer cancel()
+	operationErr := bmc.Do(ctx, client, input.Operation, input.Image)
+
+	lastOperation := &cloudweavv1.NodeBMCOperation{
+		Operation: input.Operation,
+		Time:      time.Now().UTC().Format(time.RFC3339),
+		Succeeded: operationErr == nil,
+	}
+	if operationErr != nil {
+		lastOperation.Message = operationErr.Error()
+	}
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := h.nodeBMCs.Get(nodeBMC.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		toUpdate := current.DeepCopy()
+		toUpdate.Status.LastOperation = lastOperation
+		_, err = h.nodeBMCs.Update(toUpdate)
+		return err
+	}); err != nil {
+		return err
+	}
+	return operationErr
+}
@@ -0,0 +1,130 @@
+package node
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	cloudweavv1beta1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/fake"
+	"github.com/cloudweav/cloudweav/pkg/util/bmc"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+)
+
+// fakeBMCClient records the operations sent to the BMC
+type fakeBMCClient struct {
+	powerOperations []string
+	bootDevices     []bmc.BootDevice
+	images          []string
+}
+
+func (c *fakeBMCClient) Status(_ context.Context) (*bmc.Status, error) {
+	return &bmc.Status{PowerState: bmc.PowerStateOn}, nil
+}
+
+func (c *fakeBMCClient) Power(_ context.Context, operation string) error {
+	c.powerOperations = append(c.powerOperations, operation)
+	return nil
+}
+
+func (c *fakeBMCClient) SetBootOnce(_ context.Context, device bmc.BootDevice) error {
+	c.bootDevices = append(c.bootDevices, device)
+	return nil
+}
+
+func (c *fakeBMCClient) InsertVirtualMedia(_ context.Context, image string) error {
+	c.images = append(c.images, image)
+	return nil
+}
+
+func (c *fakeBMCClient) EjectVirtualMedia(_ context.Context) error {
+	c.images = nil
+	return nil
+}
+
+var (
+	testNodeBMC = &cloudweavv1beta1.NodeBMC{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testNode.Name,
+		},
+		Spec: cloudweavv1beta1.NodeBMCSpec{
+			Protocol: cloudweavv1beta1.NodeBMCProtocolRedfish,
+			Address:  "https://10.0.0.5",
+			CredentialsSecret: corev1.SecretReference{
+				Namespace: "cloudweav-system",
+				Name:      "bmc-credentials",
+			},
+		},
+	}
+
+	testBMCSecret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "cloudweav-system",
+			Name:      "bmc-credentials",
+		},
+		Data: map[string][]byte{
+			bmc.SecretKeyUsername: []byte("admin"),
+			bmc.SecretKeyPassword: []byte("secret"),
+		},
+	}
+)
+
+func newBMCActionHandler(bmcClient bmc.Client, nodeBMC *cloudweavv1beta1.NodeBMC) ActionHandler {
+	client := fake.NewSimpleClientset(nodeBMC)
+	k8sclientset := k8sfake.NewSimpleClientset(testNode, testBMCSecret)
+	return ActionHandler{
+		nodeBMCs:     fakeclients.NodeBMCClient(client.CloudweavhciV1beta1().NodeBMCs),
+		nodeBMCCache: fakeclients.NodeBMCCache(client.CloudweavhciV1beta1().NodeBMCs),
+		secretCache:  fakeclients.SecretCache(k8sclientset.CoreV1().Secrets),
+		newBMCClient: func(_ cloudweavv1beta1.NodeBMCSpec, _ *corev1.Secret) (bmc.Client, error) {
+			return bmcClient, nil
+		},
+		ctx: context.Background(),
+	}
+}
+
+func Test_bmcPowerActionPossible(t *testing.T) {
+	assert := require.New(t)
+
+	h := newBMCActionHandler(&fakeBMCClient{}, testNodeBMC)
+	fakeHTTP := httptest.NewRecorder()
+	assert.Error(h.bmcPowerActionPossible(fakeHTTP, testNodeBMC), "expected error for a BMC never reached")
+	assert.Equal(http.StatusConflict, fakeHTTP.Result().StatusCode)
+
+	reachable := testNodeBMC.DeepCopy()
+	cloudweavv1beta1.NodeBMCReachable.True(reachable)
+	fakeHTTP = httptest.NewRecorder()
+	assert.NoError(h.bmcPowerActionPossible(fakeHTTP, reachable))
+	assert.Equal(http.StatusNoContent, fakeHTTP.Result().StatusCode)
+}
+
+func Test_bmcPowerAction(t *testing.T) {
+	assert := require.New(t)
+
+	bmcClient := &fakeBMCClient{}
+	h := newBMCActionHandler(bmcClient, testNodeBMC)
+
+	err := h.bmcPowerAction(testNodeBMC, PowerActionInput{Operation: bmc.OperationPXEBoot})
+	assert.NoError(err, "expected no error booting from PXE")
+	assert.Equal([]bmc.BootDevice{bmc.BootDevicePXE}, bmcClient.bootDevices)
+	assert.Equal([]string{bmc.OperationReboot}, bmcClient.powerOperations)
+
+	nodeBMC, err := h.nodeBMCs.Get(testNodeBMC.Name, metav1.GetOptions{})
+	assert.NoError(err)
+	assert.NotNil(nodeBMC.Status.LastOperation)
+	assert.Equal(bmc.OperationPXEBoot, nodeBMC.Status.LastOperation.Operation)
+	assert.True(nodeBMC.Status.LastOperation.Succeeded)
+
+	err = h.bmcPowerAction(testNodeBMC, PowerActionInput{Operation: bmc.OperationMountVirtualMedia, Image: "http://10.0.0.1/installer.iso"})
+	assert.NoError(err, "expected no error mounting virtual media")
+	assert.Equal([]string{"http://10.0.0.1/installer.iso"}, bmcClient.images)
+
+	err = h.bmcPowerAction(testNodeBMC, PowerActionInput{Operation: "selfdestruct"})
+	assert.Error(err, "expected error for an unknown operation")
+}
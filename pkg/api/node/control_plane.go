@@ -0,0 +1,137 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	ctlnode "github.com/cloudweav/cloudweav/pkg/controller/master/node"
+)
+
+// controlPlaneStatus returns the etcd membership and the quorum health of the cluster
+func (h ActionHandler) controlPlaneStatus(rw http.ResponseWriter) error {
+	status, _, err := h.getControlPlaneStatus()
+	if err != nil {
+		return err
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	return json.NewEncoder(rw).Encode(status)
+}
+
+// requestPromotion requests the promote controller to promote the node once the control plane needs another member
+func (h ActionHandler) requestPromotion(node *corev1.Node) error {
+	nodes, err := h.nodeCache.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	if err := ctlnode.CheckPromotion(nodes, node); err != nil {
+		return apierror.NewAPIError(validation.Conflict, err.Error())
+	}
+	return h.requestRole(node, ctlnode.RoleRequestPromote)
+}
+
+// requestDemotion requests the promote controller to remove the node from etcd and promote another node
+func (h ActionHandler) requestDemotion(node *corev1.Node) error {
+	status, _, err := h.getControlPlaneStatus()
+	if err != nil {
+		return err
+	}
+	if err := ctlnode.CheckDemotion(status, node); err != nil {
+		return apierror.NewAPIError(validation.Conflict, err.Error())
+	}
+	return h.requestRole(node, ctlnode.RoleRequestDemote)
+}
+
+func (h ActionHandler) requestRole(node *corev1.Node, request string) error {
+	if node.Annotations[ctlnode.RoleRequestAnnotationKey] == request {
+		return nil
+	}
+	toUpdate := node.DeepCopy()
+	if toUpdate.Annotations == nil {
+		toUpdate.Annotations = make(map[string]string)
+	}
+	toUpdate.Annotations[ctlnode.RoleRequestAnnotationKey] = request
+	_, err := h.nodeClient.Update(toUpdate)
+	return err
+}
+
+func (h ActionHandler) setControlPlanePolicy(node *corev1.Node, input ControlPlanePolicyInput) error {
+	toUpdate := node.DeepCopy()
+	if err := ctlnode.SetControlPlanePolicy(toUpdate, input.Policy); err != nil {
+		return apierror.NewAPIError(validation.InvalidBodyContent, err.Error())
+	}
+	if input.Policy == ctlnode.ControlPlanePolicyExcluded && ctlnode.IsManagementRole(node) {
+		return apierror.NewAPIError(validation.Conflict, fmt.Sprintf("node %s is in the control plane, demote it before excluding it", node.Name))
+	}
+	if input.Policy == ctlnode.ControlPlanePolicyPinned && ctlnode.IsDemoting(node) {
+		return apierror.NewAPIError(validation.Conflict, fmt.Sprintf("node %s is being demoted", node.Name))
+	}
+	_, err := h.nodeClient.Update(toUpdate)
+	return err
+}
+
+// designateWitness makes the node the witness node of the cluster. The current witness is demoted if it's already
+// an etcd member, the promote controller then promotes the new witness node.
+func (h ActionHandler) designateWitness(node *corev1.Node) error {
+	if ctlnode.IsManagementRole(node) {
+		return apierror.NewAPIError(validation.Conflict, fmt.Sprintf("node %s is already in the control plane", node.Name))
+	}
+	if ctlnode.GetControlPlanePolicy(node) != ctlnode.ControlPlanePolicyAuto {
+		return apierror.NewAPIError(validation.Conflict, fmt.Sprintf("node %s is %s, set its control plane policy to %s first",
+			node.Name, ctlnode.GetControlPlanePolicy(node), ctlnode.ControlPlanePolicyAuto))
+	}
+
+	status, nodes, err := h.getControlPlaneStatus()
+	if err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		if n.Name == node.Name {
+			continue
+		}
+		if _, found := n.Labels[ctlnode.CloudweavWitnessNodeLabelKey]; !found || ctlnode.IsDemoting(n) {
+			continue
+		}
+		if ctlnode.IsWitnessNode(n, ctlnode.IsManagementRole(n)) {
+			if err := ctlnode.CheckDemotion(status, n); err != nil {
+				return apierror.NewAPIError(validation.Conflict, fmt.Sprintf("can't replace witness node %s: %v", n.Name, err))
+			}
+			if err := h.requestRole(n, ctlnode.RoleRequestDemote); err != nil {
+				return err
+			}
+			continue
+		}
+		// the designated witness is not promoted yet
+		toUpdate := n.DeepCopy()
+		delete(toUpdate.Labels, ctlnode.CloudweavWitnessNodeLabelKey)
+		if _, err := h.nodeClient.Update(toUpdate); err != nil {
+			return err
+		}
+	}
+
+	toUpdate := node.DeepCopy()
+	if toUpdate.Labels == nil {
+		toUpdate.Labels = make(map[string]string)
+	}
+	toUpdate.Labels[ctlnode.CloudweavWitnessNodeLabelKey] = "true"
+	_, err = h.nodeClient.Update(toUpdate)
+	return err
+}
+
+func (h ActionHandler) getControlPlaneStatus() (*ctlnode.ControlPlaneStatus, []*corev1.Node, error) {
+	nodes, err := h.nodeCache.List(labels.Everything())
+	if err != nil {
+		return nil, nil, err
+	}
+	etcdPods, err := h.podCache.List(ctlnode.EtcdNamespace, ctlnode.EtcdPodSelector)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ctlnode.GetControlPlaneStatus(nodes, etcdPods), nodes, nil
+}
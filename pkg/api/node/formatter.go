@@ -27,17 +27,20 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/retry"
 
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
 	ctlnode "github.com/cloudweav/cloudweav/pkg/controller/master/node"
 	"github.com/cloudweav/cloudweav/pkg/controller/master/nodedrain"
 	cloudweavctlv1beta1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
 	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
 	ctllhv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/longhorn.io/v1beta2"
 	"github.com/cloudweav/cloudweav/pkg/util"
+	"github.com/cloudweav/cloudweav/pkg/util/bmc"
 	"github.com/cloudweav/cloudweav/pkg/util/drainhelper"
+	"github.com/cloudweav/cloudweav/pkg/util/numa"
+	"github.com/cloudweav/cloudweav/pkg/util/virtualmachineinstance"
 )
 
 const (
-	drainKey                     = "kubevirt.io/drain"
 	enableMaintenanceModeAction  = "enableMaintenanceMode"
 	disableMaintenanceModeAction = "disableMaintenanceMode"
 	cordonAction                 = "cordon"
@@ -46,11 +49,20 @@ const (
 	maintenancePossible          = "maintenancePossible"
 	powerAction                  = "powerAction"
 	powerActionPossible          = "powerActionPossible"
+	evacuationPlanAction         = "evacuationPlan"
 	seederAddonName              = "cloudweav-seeder"
 	defaultAddonNamespace        = "cloudweav-system"
 	nodeReady                    = "inventoryNodeReady"
 	enableCPUManager             = "enableCPUManager"
 	disableCPUManager            = "disableCPUManager"
+	enableNUMAAwareScheduling    = "enableNUMAAwareScheduling"
+	disableNUMAAwareScheduling   = "disableNUMAAwareScheduling"
+	numaTopologyAction           = "numaTopology"
+	controlPlaneStatusAction     = "controlPlaneStatus"
+	promoteAction                = "promote"
+	demoteAction                 = "demote"
+	setControlPlanePolicyAction  = "setControlPlanePolicy"
+	designateWitnessAction       = "designateWitness"
 )
 
 var (
@@ -62,9 +74,23 @@ func Formatter(request *types.APIRequest, resource *types.RawResource) {
 	resource.Actions = make(map[string]string, 3)
 	resource.AddAction(request, listUnhealthyVM)
 	resource.AddAction(request, maintenancePossible)
+	resource.AddAction(request, evacuationPlanAction)
 	resource.AddAction(request, powerActionPossible)
 	resource.AddAction(request, enableCPUManager)
 	resource.AddAction(request, disableCPUManager)
+	resource.AddAction(request, enableNUMAAwareScheduling)
+	resource.AddAction(request, disableNUMAAwareScheduling)
+	resource.AddAction(request, numaTopologyAction)
+	resource.AddAction(request, controlPlaneStatusAction)
+
+	if healthActions := resource.APIObject.Data().String("metadata", "annotations", ctlnode.HealthActionsAnnotationKey); healthActions != "" {
+		var actions []ctlnode.NodeHealthAction
+		if err := json.Unmarshal([]byte(healthActions), &actions); err != nil {
+			logrus.Warnf("failed to parse the health actions of node %s: %v", resource.ID, err)
+		} else {
+			resource.APIObject.Data().SetNested(convertHealthActions(actions), "healthActions")
+		}
+	}
 
 	if request.AccessControl.CanUpdate(request, resource.APIObject, resource.Schema) != nil {
 		return
@@ -82,18 +108,43 @@ func Formatter(request *types.APIRequest, resource *types.RawResource) {
 	} else {
 		resource.AddAction(request, "cordon")
 	}
+
+	resource.AddAction(request, setControlPlanePolicyAction)
+	if resource.APIObject.Data().String("metadata", "annotations", ctlnode.RoleRequestAnnotationKey) == "" {
+		resource.AddAction(request, promoteAction)
+		resource.AddAction(request, demoteAction)
+		resource.AddAction(request, designateWitnessAction)
+	}
+}
+
+// convertHealthActions converts the health actions to the generic values of the API object
+func convertHealthActions(actions []ctlnode.NodeHealthAction) []interface{} {
+	result := make([]interface{}, 0, len(actions))
+	for _, action := range actions {
+		result = append(result, map[string]interface{}{
+			"time":    action.Time,
+			"action":  action.Action,
+			"message": action.Message,
+		})
+	}
+	return result
 }
 
 type ActionHandler struct {
 	jobCache                    ctlbatchv1.JobCache
 	nodeCache                   ctlcorev1.NodeCache
 	nodeClient                  ctlcorev1.NodeClient
+	podCache                    ctlcorev1.PodCache
 	longhornVolumeCache         ctllhv1.VolumeCache
 	longhornReplicaCache        ctllhv1.ReplicaCache
 	virtualMachineClient        ctlkubevirtv1.VirtualMachineClient
 	virtualMachineCache         ctlkubevirtv1.VirtualMachineCache
 	virtualMachineInstanceCache ctlkubevirtv1.VirtualMachineInstanceCache
 	addonCache                  cloudweavctlv1beta1.AddonCache
+	nodeBMCs                    cloudweavctlv1beta1.NodeBMCClient
+	nodeBMCCache                cloudweavctlv1beta1.NodeBMCCache
+	secretCache                 ctlcorev1.SecretCache
+	newBMCClient                func(spec cloudweavv1.NodeBMCSpec, secret *corev1.Secret) (bmc.Client, error)
 	dynamicClient               dynamic.Interface
 	virtSubresourceRestClient   rest.Interface
 	ctx                         context.Context
@@ -136,18 +187,57 @@ func (h ActionHandler) do(rw http.ResponseWriter, req *http.Request) error {
 		return h.listUnhealthyVM(rw, toUpdate)
 	case maintenancePossible:
 		return h.maintenancePossible(toUpdate)
+	case evacuationPlanAction:
+		var input EvacuationPlanInput
+		if req.ContentLength != 0 {
+			if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+				return apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to decode request body: %v ", err))
+			}
+		}
+		return h.evacuationPlan(rw, node, input)
 	case powerActionPossible:
+		// a node BMC takes precedence over the seeder inventory
+		if nodeBMC, err := h.nodeBMCCache.Get(name); err == nil {
+			return h.bmcPowerActionPossible(rw, nodeBMC)
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
 		return h.powerActionPossible(rw, name)
 	case powerAction:
 		var input PowerActionInput
 		if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
 			return apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to decode request body: %v ", err))
 		}
+		if nodeBMC, err := h.nodeBMCCache.Get(name); err == nil {
+			return h.bmcPowerAction(nodeBMC, input)
+		} else if !apierrors.IsNotFound(err) {
+			return err
+		}
 		return h.powerAction(toUpdate, input.Operation)
 	case enableCPUManager:
 		return h.enableCPUManager(toUpdate)
 	case disableCPUManager:
 		return h.disableCPUManager(toUpdate)
+	case enableNUMAAwareScheduling:
+		return h.requestNUMAAwareScheduling(toUpdate, true)
+	case disableNUMAAwareScheduling:
+		return h.requestNUMAAwareScheduling(toUpdate, false)
+	case numaTopologyAction:
+		return h.numaTopology(rw, node)
+	case controlPlaneStatusAction:
+		return h.controlPlaneStatus(rw)
+	case promoteAction:
+		return h.requestPromotion(toUpdate)
+	case demoteAction:
+		return h.requestDemotion(toUpdate)
+	case setControlPlanePolicyAction:
+		var input ControlPlanePolicyInput
+		if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+			return apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to decode request body: %v ", err))
+		}
+		return h.setControlPlanePolicy(toUpdate, input)
+	case designateWitnessAction:
+		return h.designateWitness(toUpdate)
 	default:
 		return apierror.NewAPIError(validation.InvalidAction, "Unsupported action")
 	}
@@ -162,16 +252,29 @@ func (h ActionHandler) disableCPUManager(node *corev1.Node) error {
 }
 
 func (h ActionHandler) requestCPUManager(node *corev1.Node, policy ctlnode.CPUManagerPolicy) error {
+	return h.requestCPUManagerUpdate(node, &ctlnode.CPUManagerUpdateStatus{
+		Status: ctlnode.CPUManagerRequestedStatus,
+		Policy: policy,
+	})
+}
+
+// requestNUMAAwareScheduling enables or disables the static memory manager and the single-numa-node topology manager
+// of the node. They both require the static CPU manager policy, which stays enabled.
+func (h ActionHandler) requestNUMAAwareScheduling(node *corev1.Node, enabled bool) error {
+	return h.requestCPUManagerUpdate(node, &ctlnode.CPUManagerUpdateStatus{
+		Status:    ctlnode.CPUManagerRequestedStatus,
+		Policy:    ctlnode.CPUManagerStaticPolicy,
+		NUMAAware: enabled,
+	})
+}
+
+// requestCPUManagerUpdate requests the update of the kubelet managers, the CPU manager controller runs the update job
+func (h ActionHandler) requestCPUManagerUpdate(node *corev1.Node, updateStatus *ctlnode.CPUManagerUpdateStatus) error {
 	newNode := node.DeepCopy()
 	if newNode.Annotations == nil {
 		newNode.Annotations = make(map[string]string)
 	}
 
-	updateStatus := &ctlnode.CPUManagerUpdateStatus{
-		Status: ctlnode.CPUManagerRequestedStatus,
-		Policy: policy,
-	}
-
 	bytes, err := json.Marshal(updateStatus)
 	if err != nil {
 		return err
@@ -231,20 +334,7 @@ func (h ActionHandler) enableMaintenanceMode(req *http.Request, node *corev1.Nod
 type maintenanceModeUpdateFunc func(node *corev1.Node)
 
 func (h ActionHandler) disableMaintenanceMode(nodeName string) error {
-	disableMaintaenanceModeFunc := func(node *corev1.Node) {
-		node.Spec.Unschedulable = false
-		for i, taint := range node.Spec.Taints {
-			if taint.Key == drainKey {
-				node.Spec.Taints = append(node.Spec.Taints[:i], node.Spec.Taints[i+1:]...)
-				break
-			}
-		}
-		delete(node.Annotations, drainhelper.DrainAnnotation)
-		delete(node.Annotations, drainhelper.ForcedDrain)
-		delete(node.Annotations, ctlnode.MaintainStatusAnnotationKey)
-	}
-
-	err := h.retryMaintenanceModeUpdate(nodeName, disableMaintaenanceModeFunc, "disable")
+	err := h.retryMaintenanceModeUpdate(nodeName, drainhelper.DisableMaintenanceMode, "disable")
 	if err != nil {
 		return err
 	}
@@ -252,43 +342,7 @@ func (h ActionHandler) disableMaintenanceMode(nodeName string) error {
 	// Restart those VMs that have been labeled to be shut down before
 	// maintenance mode and that should be restarted when the maintenance
 	// mode has been disabled again.
-	node, err := h.nodeCache.Get(nodeName)
-	if err != nil {
-		return err
-	}
-	selector := labels.Set{util.LabelMaintainModeStrategy: util.MaintainModeStrategyShutdownAndRestartAfterDisable}.AsSelector()
-	vmList, err := h.virtualMachineCache.List(node.Namespace, selector)
-	if err != nil {
-		return fmt.Errorf("failed to list VMs with labels %s: %w", selector.String(), err)
-	}
-	for _, vm := range vmList {
-		// Make sure that this VM was shut down as part of the maintenance
-		// mode of the given node.
-		if vm.Annotations[util.AnnotationMaintainModeStrategyNodeName] != nodeName {
-			continue
-		}
-
-		logrus.WithFields(logrus.Fields{
-			"namespace":           vm.Namespace,
-			"virtualmachine_name": vm.Name,
-		}).Info("restarting the VM that was shut down in maintenance mode")
-
-		err := h.virtSubresourceRestClient.Put().Namespace(vm.Namespace).Resource("virtualmachines").SubResource("start").Name(vm.Name).Do(h.ctx).Error()
-		if err != nil {
-			return fmt.Errorf("failed to start VM %s/%s: %w", vm.Namespace, vm.Name, err)
-		}
-
-		// Remove the annotation that was previously set when the node
-		// went into maintenance mode.
-		vmCopy := vm.DeepCopy()
-		delete(vmCopy.Annotations, util.AnnotationMaintainModeStrategyNodeName)
-		_, err = h.virtualMachineClient.Update(vmCopy)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return drainhelper.RestartMaintenanceModeVMs(h.ctx, nodeName, h.virtualMachineCache, h.virtualMachineClient, h.virtSubresourceRestClient)
 }
 
 func (h ActionHandler) retryMaintenanceModeUpdate(nodeName string, updateFunc maintenanceModeUpdateFunc, actionName string) error {
@@ -334,6 +388,48 @@ func (h ActionHandler) listUnhealthyVM(rw http.ResponseWriter, node *corev1.Node
 	return json.NewEncoder(rw).Encode(&respObj)
 }
 
+// evacuationPlan simulates the maintenance mode of the node, and of the other nodes of the input put in maintenance
+// mode with it
+func (h ActionHandler) evacuationPlan(rw http.ResponseWriter, node *corev1.Node, input EvacuationPlanInput) error {
+	nodes := []*corev1.Node{node}
+	for _, name := range input.Nodes {
+		if name == node.Name {
+			continue
+		}
+		other, err := h.nodeCache.Get(name)
+		if apierrors.IsNotFound(err) {
+			return apierror.NewAPIError(validation.NotFound, fmt.Sprintf("Node %s not found", name))
+		} else if err != nil {
+			return err
+		}
+		nodes = append(nodes, other)
+	}
+
+	ndc := nodedrain.EvacuationHelper(h.nodeCache, h.podCache, h.virtualMachineInstanceCache, h.longhornVolumeCache, h.longhornReplicaCache)
+	plan, err := ndc.PlanEvacuation(nodes, input.Force)
+	if err != nil {
+		return err
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	return json.NewEncoder(rw).Encode(plan)
+}
+
+// numaTopology returns the NUMA topology of the node, with the pinned CPUs and hugepages left for VMs
+func (h ActionHandler) numaTopology(rw http.ResponseWriter, node *corev1.Node) error {
+	vmis, err := virtualmachineinstance.ListByNode(node, labels.NewSelector(), h.virtualMachineInstanceCache)
+	if err != nil {
+		return err
+	}
+	summary, err := numa.Summarize(node, vmis)
+	if err != nil {
+		return err
+	}
+
+	rw.WriteHeader(http.StatusOK)
+	return json.NewEncoder(rw).Encode(summary)
+}
+
 func (h ActionHandler) maintenancePossible(node *corev1.Node) error {
 	return drainhelper.DrainPossible(h.nodeCache, node)
 }
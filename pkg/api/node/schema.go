@@ -14,6 +14,7 @@ import (
 
 	"github.com/cloudweav/cloudweav/pkg/config"
 	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/scheme"
+	"github.com/cloudweav/cloudweav/pkg/util/bmc"
 )
 
 type MaintenanceModeInput struct {
@@ -25,8 +26,21 @@ type ListUnhealthyVM struct {
 	VMs     []string `json:"vms"`
 }
 
+type EvacuationPlanInput struct {
+	// Nodes are the other nodes put in maintenance mode with the node
+	Nodes []string `json:"nodes,omitempty"`
+	Force bool     `json:"force,omitempty"`
+}
+
+type ControlPlanePolicyInput struct {
+	// Policy is one of pinned, excluded and auto
+	Policy string `json:"policy"`
+}
+
 type PowerActionInput struct {
 	Operation string `json:"operation"`
+	// Image is the URL of the image to mount with the mountvirtualmedia operation of a node BMC
+	Image string `json:"image,omitempty"`
 }
 
 func RegisterSchema(scaled *config.Scaled, server *server.Server, _ config.Options) error {
@@ -49,18 +63,26 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server, _ config.Optio
 		jobCache:                    scaled.Management.BatchFactory.Batch().V1().Job().Cache(),
 		nodeClient:                  scaled.Management.CoreFactory.Core().V1().Node(),
 		nodeCache:                   scaled.Management.CoreFactory.Core().V1().Node().Cache(),
+		podCache:                    scaled.Management.CoreFactory.Core().V1().Pod().Cache(),
 		longhornReplicaCache:        scaled.Management.LonghornFactory.Longhorn().V1beta2().Replica().Cache(),
 		longhornVolumeCache:         scaled.Management.LonghornFactory.Longhorn().V1beta2().Volume().Cache(),
 		virtualMachineClient:        scaled.Management.VirtFactory.Kubevirt().V1().VirtualMachine(),
 		virtualMachineCache:         scaled.Management.VirtFactory.Kubevirt().V1().VirtualMachine().Cache(),
 		virtualMachineInstanceCache: scaled.Management.VirtFactory.Kubevirt().V1().VirtualMachineInstance().Cache(),
 		addonCache:                  scaled.Management.CloudweavFactory.Cloudweavhci().V1beta1().Addon().Cache(),
+		nodeBMCs:                    scaled.Management.CloudweavFactory.Cloudweavhci().V1beta1().NodeBMC(),
+		nodeBMCCache:                scaled.Management.CloudweavFactory.Cloudweavhci().V1beta1().NodeBMC().Cache(),
+		secretCache:                 scaled.Management.CoreFactory.Core().V1().Secret().Cache(),
+		newBMCClient:                bmc.NewClient,
 		dynamicClient:               dynamicClient,
 		virtSubresourceRestClient:   virtSubresourceClient,
 		ctx:                         scaled.Ctx,
 	}
 
 	server.BaseSchemas.MustImportAndCustomize(MaintenanceModeInput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(PowerActionInput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(EvacuationPlanInput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(ControlPlanePolicyInput{}, nil)
 
 	t := schema.Template{
 		ID: "node",
@@ -75,12 +97,25 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server, _ config.Optio
 				uncordonAction:               {},
 				listUnhealthyVM:              {},
 				maintenancePossible:          {},
+				evacuationPlanAction: {
+					Input: "evacuationPlanInput",
+				},
 				powerAction: {
 					Input: "powerActionInput",
 				},
-				powerActionPossible: {},
-				enableCPUManager:    {},
-				disableCPUManager:   {},
+				powerActionPossible:        {},
+				enableCPUManager:           {},
+				disableCPUManager:          {},
+				enableNUMAAwareScheduling:  {},
+				disableNUMAAwareScheduling: {},
+				numaTopologyAction:         {},
+				controlPlaneStatusAction:   {},
+				promoteAction:              {},
+				demoteAction:               {},
+				setControlPlanePolicyAction: {
+					Input: "controlPlanePolicyInput",
+				},
+				designateWitnessAction: {},
 			}
 			s.ActionHandlers = map[string]http.Handler{
 				enableMaintenanceModeAction:  nodeHandler,
@@ -89,10 +124,19 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server, _ config.Optio
 				uncordonAction:               nodeHandler,
 				listUnhealthyVM:              nodeHandler,
 				maintenancePossible:          nodeHandler,
+				evacuationPlanAction:         nodeHandler,
 				powerAction:                  nodeHandler,
 				powerActionPossible:          nodeHandler,
 				enableCPUManager:             nodeHandler,
 				disableCPUManager:            nodeHandler,
+				enableNUMAAwareScheduling:    nodeHandler,
+				disableNUMAAwareScheduling:   nodeHandler,
+				numaTopologyAction:           nodeHandler,
+				controlPlaneStatusAction:     nodeHandler,
+				promoteAction:                nodeHandler,
+				demoteAction:                 nodeHandler,
+				setControlPlanePolicyAction:  nodeHandler,
+				designateWitnessAction:       nodeHandler,
 			}
 		},
 	}
@@ -10,6 +10,8 @@ import (
 	"github.com/cloudweav/cloudweav/pkg/api/keypair"
 	"github.com/cloudweav/cloudweav/pkg/api/namespace"
 	"github.com/cloudweav/cloudweav/pkg/api/node"
+	"github.com/cloudweav/cloudweav/pkg/api/upgrade"
+	"github.com/cloudweav/cloudweav/pkg/api/upgradehistory"
 	"github.com/cloudweav/cloudweav/pkg/api/upgradelog"
 	"github.com/cloudweav/cloudweav/pkg/api/vm"
 	"github.com/cloudweav/cloudweav/pkg/api/vmtemplate"
@@ -37,7 +39,9 @@ func Setup(ctx context.Context, server *server.Server, _ *server.Controllers, op
 		vmtemplate.RegisterSchema,
 		vm.RegisterSchema,
 		node.RegisterSchema,
+		upgrade.RegisterSchema,
 		upgradelog.RegisterSchema,
+		upgradehistory.RegisterSchema,
 		volume.RegisterSchema,
 		volumesnapshot.RegisterSchema,
 		cluster.RegisterSchema,
@@ -0,0 +1,42 @@
+package upgrade
+
+import (
+	"github.com/rancher/apiserver/pkg/types"
+	"github.com/rancher/wrangler/v3/pkg/data/convert"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlupgrade "github.com/cloudweav/cloudweav/pkg/controller/master/upgrade"
+)
+
+const (
+	actionPause  = "pause"
+	actionResume = "resume"
+
+	upgradeStateLabel = "cloudweavhci.io/upgradeState"
+)
+
+func Formatter(request *types.APIRequest, resource *types.RawResource) {
+	resource.Actions = make(map[string]string, 1)
+	if request.AccessControl.CanUpdate(request, resource.APIObject, resource.Schema) != nil {
+		return
+	}
+
+	upgrade := &cloudweavv1.Upgrade{}
+	if err := convert.ToObj(resource.APIObject.Data(), upgrade); err != nil {
+		return
+	}
+	if isCompleted(upgrade) {
+		return
+	}
+
+	if upgrade.Spec.Paused {
+		resource.AddAction(request, actionResume)
+	} else {
+		resource.AddAction(request, actionPause)
+	}
+}
+
+func isCompleted(upgrade *cloudweavv1.Upgrade) bool {
+	state := upgrade.Labels[upgradeStateLabel]
+	return state == ctlupgrade.StateSucceeded || state == ctlupgrade.StateFailed
+}
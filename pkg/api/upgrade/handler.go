@@ -0,0 +1,66 @@
+package upgrade
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
+
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+type ActionHandler struct {
+	upgradeClient ctlcloudweavv1.UpgradeClient
+	upgradeCache  ctlcloudweavv1.UpgradeCache
+}
+
+func (h ActionHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if err := h.do(req); err != nil {
+		status := http.StatusInternalServerError
+		if e, ok := err.(*apierror.APIError); ok {
+			status = e.Code.Status
+		}
+		rw.WriteHeader(status)
+		_, _ = rw.Write([]byte(err.Error()))
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (h ActionHandler) do(r *http.Request) error {
+	vars := util.EncodeVars(mux.Vars(r))
+	action := vars["action"]
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	switch action {
+	case actionPause:
+		return h.setPaused(namespace, name, true)
+	case actionResume:
+		return h.setPaused(namespace, name, false)
+	default:
+		return apierror.NewAPIError(validation.InvalidAction, "Unsupported action")
+	}
+}
+
+// setPaused pauses or resumes the upgrade, the nodes being upgraded aren't interrupted by a pause
+func (h ActionHandler) setPaused(namespace, name string, paused bool) error {
+	upgrade, err := h.upgradeCache.Get(namespace, name)
+	if err != nil {
+		return err
+	}
+	if isCompleted(upgrade) {
+		return apierror.NewAPIError(validation.Conflict, fmt.Sprintf("upgrade %s/%s is completed", namespace, name))
+	}
+	if upgrade.Spec.Paused == paused {
+		return nil
+	}
+
+	toUpdate := upgrade.DeepCopy()
+	toUpdate.Spec.Paused = paused
+	_, err = h.upgradeClient.Update(toUpdate)
+	return err
+}
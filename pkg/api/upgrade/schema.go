@@ -0,0 +1,39 @@
+package upgrade
+
+import (
+	"net/http"
+
+	"github.com/rancher/apiserver/pkg/types"
+	"github.com/rancher/steve/pkg/schema"
+	"github.com/rancher/steve/pkg/server"
+	"github.com/rancher/wrangler/v3/pkg/schemas"
+
+	"github.com/cloudweav/cloudweav/pkg/config"
+)
+
+const (
+	upgradeSchemaID = "cloudweavhci.io.upgrade"
+)
+
+func RegisterSchema(scaled *config.Scaled, server *server.Server, _ config.Options) error {
+	actionHandler := ActionHandler{
+		upgradeClient: scaled.CloudweavFactory.Cloudweavhci().V1beta1().Upgrade(),
+		upgradeCache:  scaled.CloudweavFactory.Cloudweavhci().V1beta1().Upgrade().Cache(),
+	}
+	t := schema.Template{
+		ID: upgradeSchemaID,
+		Customize: func(s *types.APISchema) {
+			s.ResourceActions = map[string]schemas.Action{
+				actionPause:  {},
+				actionResume: {},
+			}
+			s.ActionHandlers = map[string]http.Handler{
+				actionPause:  actionHandler,
+				actionResume: actionHandler,
+			}
+		},
+		Formatter: Formatter,
+	}
+	server.SchemaFactory.AddTemplate(t)
+	return nil
+}
@@ -0,0 +1,66 @@
+package upgradehistory
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+const (
+	reportLink = "report"
+
+	reportFormatJSON     = "json"
+	reportFormatMarkdown = "markdown"
+)
+
+type Handler struct {
+	historyCache ctlcloudweavv1.UpgradeHistoryCache
+}
+
+func (h Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if err := h.do(rw, req); err != nil {
+		status := http.StatusInternalServerError
+		if e, ok := err.(*apierror.APIError); ok {
+			status = e.Code.Status
+		}
+		rw.WriteHeader(status)
+		_, _ = rw.Write([]byte(err.Error()))
+		return
+	}
+}
+
+func (h Handler) do(rw http.ResponseWriter, req *http.Request) error {
+	vars := util.EncodeVars(mux.Vars(req))
+	if req.Method != http.MethodGet || vars["link"] != reportLink {
+		return apierror.NewAPIError(validation.InvalidAction, fmt.Sprintf("Unsupported %s link %s", req.Method, vars["link"]))
+	}
+
+	history, err := h.historyCache.Get(vars["namespace"], vars["name"])
+	if apierrors.IsNotFound(err) {
+		return apierror.NewAPIError(validation.NotFound, err.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get the upgrade history %s/%s: %w", vars["namespace"], vars["name"], err)
+	}
+	report := NewReport(history)
+
+	switch format := req.URL.Query().Get("format"); format {
+	case "", reportFormatJSON:
+		rw.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(rw).Encode(report)
+	case reportFormatMarkdown:
+		rw.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		_, err := rw.Write([]byte(report.Markdown()))
+		return err
+	default:
+		return apierror.NewAPIError(validation.InvalidFormat, fmt.Sprintf("Unsupported report format %s, it must be %s or %s", format, reportFormatJSON, reportFormatMarkdown))
+	}
+}
@@ -0,0 +1,140 @@
+package upgradehistory
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+// Report is the post-mortem report of an upgrade. The durations are in seconds so the reports of upgrades can be
+// compared, they're omitted if the start or the end isn't recorded.
+type Report struct {
+	Name            string                          `json:"name"`
+	UpgradeName     string                          `json:"upgradeName"`
+	PreviousVersion string                          `json:"previousVersion,omitempty"`
+	Version         string                          `json:"version,omitempty"`
+	Outcome         string                          `json:"outcome,omitempty"`
+	StartTime       string                          `json:"startTime,omitempty"`
+	EndTime         string                          `json:"endTime,omitempty"`
+	Duration        *int64                          `json:"durationSeconds,omitempty"`
+	Phases          []PhaseReport                   `json:"phases"`
+	Nodes           []NodeReport                    `json:"nodes"`
+	VMMigrations    *cloudweavv1.VMMigrationHistory `json:"vmMigrations,omitempty"`
+	Warnings        []string                        `json:"warnings"`
+}
+
+type PhaseReport struct {
+	Name     string `json:"name"`
+	Result   string `json:"result,omitempty"`
+	Duration *int64 `json:"durationSeconds,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+type NodeReport struct {
+	Name            string `json:"name"`
+	State           string `json:"state,omitempty"`
+	PreloadDuration *int64 `json:"preloadDurationSeconds,omitempty"`
+	UpgradeDuration *int64 `json:"upgradeDurationSeconds,omitempty"`
+	Message         string `json:"message,omitempty"`
+}
+
+func NewReport(history *cloudweavv1.UpgradeHistory) *Report {
+	report := &Report{
+		Name:            history.Name,
+		UpgradeName:     history.Spec.UpgradeName,
+		PreviousVersion: history.Spec.PreviousVersion,
+		Version:         history.Spec.Version,
+		Outcome:         history.Status.Outcome,
+		StartTime:       history.Status.StartTime,
+		EndTime:         history.Status.EndTime,
+		Duration:        duration(history.Status.StartTime, history.Status.EndTime),
+		Phases:          make([]PhaseReport, 0, len(history.Status.Phases)),
+		Nodes:           make([]NodeReport, 0, len(history.Status.Nodes)),
+		VMMigrations:    history.Status.VMMigrations,
+		Warnings:        append([]string{}, history.Status.Warnings...),
+	}
+	for _, phase := range history.Status.Phases {
+		report.Phases = append(report.Phases, PhaseReport{
+			Name:     phase.Name,
+			Result:   phase.Result,
+			Duration: duration(phase.StartTime, phase.EndTime),
+			Message:  phase.Message,
+		})
+	}
+	for _, node := range history.Status.Nodes {
+		report.Nodes = append(report.Nodes, NodeReport{
+			Name:            node.Name,
+			State:           node.State,
+			PreloadDuration: duration(node.PreloadStartTime, node.PreloadEndTime),
+			UpgradeDuration: duration(node.UpgradeStartTime, node.EndTime),
+			Message:         node.Message,
+		})
+	}
+	return report
+}
+
+// Markdown renders the report in Markdown, e.g. for change management tickets
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Upgrade %s\n\n", r.UpgradeName)
+	fmt.Fprintf(&b, "| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| From | %s |\n", markdownValue(r.PreviousVersion))
+	fmt.Fprintf(&b, "| To | %s |\n", markdownValue(r.Version))
+	fmt.Fprintf(&b, "| Outcome | %s |\n", markdownValue(r.Outcome))
+	fmt.Fprintf(&b, "| Start | %s |\n", markdownValue(r.StartTime))
+	fmt.Fprintf(&b, "| End | %s |\n", markdownValue(r.EndTime))
+	fmt.Fprintf(&b, "| Duration | %s |\n", formatDuration(r.Duration))
+	if r.VMMigrations != nil {
+		fmt.Fprintf(&b, "| VM migrations | %d succeeded, %d failed |\n", r.VMMigrations.Succeeded, r.VMMigrations.Failed)
+	}
+
+	b.WriteString("\n## Phases\n\n| Phase | Result | Duration | Message |\n|---|---|---|---|\n")
+	for _, phase := range r.Phases {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", phase.Name, markdownValue(phase.Result), formatDuration(phase.Duration), markdownValue(phase.Message))
+	}
+
+	b.WriteString("\n## Nodes\n\n| Node | State | Image preload | Upgrade | Message |\n|---|---|---|---|---|\n")
+	for _, node := range r.Nodes {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", node.Name, markdownValue(node.State), formatDuration(node.PreloadDuration),
+			formatDuration(node.UpgradeDuration), markdownValue(node.Message))
+	}
+
+	if len(r.Warnings) > 0 {
+		b.WriteString("\n## Warnings\n\n")
+		for _, warning := range r.Warnings {
+			fmt.Fprintf(&b, "- %s\n", markdownValue(warning))
+		}
+	}
+	return b.String()
+}
+
+func duration(start, end string) *int64 {
+	startTime, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return nil
+	}
+	endTime, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return nil
+	}
+	seconds := int64(endTime.Sub(startTime) / time.Second)
+	return &seconds
+}
+
+func formatDuration(seconds *int64) string {
+	if seconds == nil {
+		return "-"
+	}
+	return (time.Duration(*seconds) * time.Second).String()
+}
+
+// markdownValue keeps the value in its table cell
+func markdownValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.Join(strings.Fields(value), " ")
+}
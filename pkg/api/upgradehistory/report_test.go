@@ -0,0 +1,84 @@
+package upgradehistory
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+func newTestHistory() *cloudweavv1.UpgradeHistory {
+	return &cloudweavv1.UpgradeHistory{
+		ObjectMeta: metav1.ObjectMeta{Name: "hvst-upgrade-abcde", Namespace: "cloudweav-system"},
+		Spec: cloudweavv1.UpgradeHistorySpec{
+			UpgradeName:     "hvst-upgrade-abcde",
+			PreviousVersion: "v1.4.0",
+			Version:         "v1.5.0",
+		},
+		Status: cloudweavv1.UpgradeHistoryStatus{
+			StartTime: "2026-10-01T10:00:00Z",
+			EndTime:   "2026-10-01T11:30:00Z",
+			Outcome:   "Failed",
+			Phases: []cloudweavv1.UpgradePhaseHistory{
+				{Name: "ImageReady", StartTime: "2026-10-01T10:00:00Z", EndTime: "2026-10-01T10:05:00Z", Result: "True"},
+				{Name: "NodesUpgraded", StartTime: "2026-10-01T10:20:00Z", EndTime: "2026-10-01T11:30:00Z", Result: "False", Message: "job | failed"},
+				{Name: "RepoReady", StartTime: "2026-10-01T10:05:00Z"},
+			},
+			Nodes: []cloudweavv1.NodeUpgradeHistory{
+				{
+					Name:             "node1",
+					PreloadStartTime: "2026-10-01T10:06:00Z",
+					PreloadEndTime:   "2026-10-01T10:16:30Z",
+					UpgradeStartTime: "2026-10-01T10:20:00Z",
+					EndTime:          "2026-10-01T10:50:00Z",
+					State:            "Succeeded",
+				},
+				{Name: "node2", State: "Failed", Message: "job failed"},
+			},
+			VMMigrations: &cloudweavv1.VMMigrationHistory{Succeeded: 3, Failed: 1},
+			Warnings:     []string{"node node2 failed: job failed"},
+		},
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	report := NewReport(newTestHistory())
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.EqualValues(t, 5400, decoded["durationSeconds"])
+
+	require.Len(t, report.Phases, 3)
+	assert.EqualValues(t, 300, *report.Phases[0].Duration)
+	assert.Nil(t, report.Phases[2].Duration, "phase isn't ended")
+
+	require.Len(t, report.Nodes, 2)
+	assert.EqualValues(t, 630, *report.Nodes[0].PreloadDuration)
+	assert.EqualValues(t, 1800, *report.Nodes[0].UpgradeDuration)
+	assert.Nil(t, report.Nodes[1].PreloadDuration)
+}
+
+func TestReportMarkdown(t *testing.T) {
+	markdown := NewReport(newTestHistory()).Markdown()
+
+	for _, expected := range []string{
+		"# Upgrade hvst-upgrade-abcde\n",
+		"| From | v1.4.0 |\n",
+		"| Duration | 1h30m0s |\n",
+		"| VM migrations | 3 succeeded, 1 failed |\n",
+		"| ImageReady | True | 5m0s | - |\n",
+		"| NodesUpgraded | False | 1h10m0s | job \\| failed |\n",
+		"| RepoReady | - | - | - |\n",
+		"| node1 | Succeeded | 10m30s | 30m0s | - |\n",
+		"| node2 | Failed | - | - | job failed |\n",
+		"## Warnings\n\n- node node2 failed: job failed\n",
+	} {
+		assert.Contains(t, markdown, expected)
+	}
+}
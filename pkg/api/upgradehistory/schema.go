@@ -0,0 +1,31 @@
+package upgradehistory
+
+import (
+	"net/http"
+
+	"github.com/rancher/apiserver/pkg/types"
+	"github.com/rancher/steve/pkg/schema"
+	"github.com/rancher/steve/pkg/server"
+
+	"github.com/cloudweav/cloudweav/pkg/config"
+)
+
+const (
+	upgradeHistorySchemaID = "cloudweavhci.io.upgradehistory"
+)
+
+func RegisterSchema(scaled *config.Scaled, server *server.Server, _ config.Options) error {
+	handler := Handler{
+		historyCache: scaled.CloudweavFactory.Cloudweavhci().V1beta1().UpgradeHistory().Cache(),
+	}
+	t := schema.Template{
+		ID: upgradeHistorySchemaID,
+		Customize: func(s *types.APISchema) {
+			s.LinkHandlers = map[string]http.Handler{
+				reportLink: handler,
+			}
+		},
+	}
+	server.SchemaFactory.AddTemplate(t)
+	return nil
+}
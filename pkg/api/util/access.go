@@ -0,0 +1,45 @@
+package util
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CanAccessResource checks whether the user of the API request is allowed to access the resource.
+// Actions are not authorized by the API server beyond reading the object they are called on,
+// so they must check the access to the resources they modify.
+func CanAccessResource(clientSet kubernetes.Clientset, userInfo user.Info, attributes *authorizationv1.ResourceAttributes) (bool, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(userInfo.GetExtra()))
+	for key, value := range userInfo.GetExtra() {
+		extra[key] = value
+	}
+
+	review, err := clientSet.AuthorizationV1().SubjectAccessReviews().Create(
+		context.TODO(),
+		&authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				ResourceAttributes: attributes,
+				User:               userInfo.GetName(),
+				Groups:             userInfo.GetGroups(),
+				UID:                userInfo.GetUID(),
+				Extra:              extra,
+			},
+		},
+		metav1.CreateOptions{},
+	)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"namespace": attributes.Namespace,
+			"resource":  attributes.Resource,
+			"verb":      attributes.Verb,
+			"user":      userInfo.GetName(),
+		}).Error("Failed to check resource access")
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
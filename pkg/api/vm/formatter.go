@@ -14,6 +14,7 @@ import (
 	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
 	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
 	"github.com/cloudweav/cloudweav/pkg/indexeres"
+	"github.com/cloudweav/cloudweav/pkg/settings"
 	"github.com/cloudweav/cloudweav/pkg/util"
 )
 
@@ -38,6 +39,8 @@ const (
 	dismissInsufficientResourceQuota = "dismissInsufficientResourceQuota"
 	updateResourceQuotaAction        = "updateResourceQuota"
 	deleteResourceQuotaAction        = "deleteResourceQuota"
+	issueSSHCertificate              = "issueSSHCertificate"
+	generateSSHHostConfig            = "generateSSHHostConfig"
 )
 
 type vmformatter struct {
@@ -131,6 +134,11 @@ func (vf *vmformatter) formatter(request *types.APIRequest, resource *types.RawR
 	if canDismissInsufficientResourceQuota(vm) {
 		resource.AddAction(request, dismissInsufficientResourceQuota)
 	}
+
+	if canUseSSHCA() {
+		resource.AddAction(request, issueSSHCertificate)
+		resource.AddAction(request, generateSSHHostConfig)
+	}
 }
 
 func canEjectCdRom(vm *kubevirtv1.VirtualMachine) bool {
@@ -347,3 +355,8 @@ func canDismissInsufficientResourceQuota(vm *kubevirtv1.VirtualMachine) bool {
 	}
 	return true
 }
+
+func canUseSSHCA() bool {
+	config, err := settings.DecodeSSHCAConfig(settings.SSHCASet.Get())
+	return err == nil && config.Enabled
+}
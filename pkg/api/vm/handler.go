@@ -46,6 +46,8 @@ import (
 	"github.com/cloudweav/cloudweav/pkg/settings"
 	"github.com/cloudweav/cloudweav/pkg/util"
 	"github.com/cloudweav/cloudweav/pkg/util/drainhelper"
+	"github.com/cloudweav/cloudweav/pkg/util/numa"
+	"github.com/cloudweav/cloudweav/pkg/util/virtualmachineinstance"
 )
 
 const (
@@ -262,6 +264,29 @@ func (h *vmActionHandler) doAction(rw http.ResponseWriter, r *http.Request) erro
 			return apierror.NewAPIError(validation.PermissionDenied, "User does not have permission to update resource quota")
 		}
 		return h.deleteResourceQuota(namespace, name)
+	case issueSSHCertificate:
+		var input IssueSSHCertificateInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			return apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to decode request body: %v ", err))
+		}
+		if input.PublicKey == "" {
+			return apierror.NewAPIError(validation.InvalidBodyContent, "Parameter publicKey is required")
+		}
+		output, err := h.issueSSHCertificate(user, namespace, name, input)
+		if err != nil {
+			return err
+		}
+		util.ResponseOKWithBody(rw, output)
+	case generateSSHHostConfig:
+		var input GenerateSSHHostConfigInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			return apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to decode request body: %v ", err))
+		}
+		output, err := h.generateSSHHostConfig(user, namespace, name, input)
+		if err != nil {
+			return err
+		}
+		util.ResponseOKWithBody(rw, output)
 	default:
 		return apierror.NewAPIError(validation.InvalidAction, "Unsupported action")
 	}
@@ -546,6 +571,7 @@ func (h *vmActionHandler) findMigratableNodesByVMI(vmi *kubevirtv1.VirtualMachin
 	}
 
 	// ignore the node where the VM is running
+	requirements := numa.GetRequirements(&vmi.Spec)
 	migratableNodes := make([]string, 0, len(nodes)-1)
 	for _, node := range nodes {
 		if vmi.Status.NodeName == node.Name {
@@ -556,11 +582,39 @@ func (h *vmActionHandler) findMigratableNodesByVMI(vmi *kubevirtv1.VirtualMachin
 			continue
 		}
 
+		fits, err := h.fitsNUMAPlacement(node, vmi, requirements)
+		if err != nil {
+			return nil, err
+		}
+		if !fits {
+			continue
+		}
+
 		migratableNodes = append(migratableNodes, node.Name)
 	}
 	return migratableNodes, nil
 }
 
+// fitsNUMAPlacement checks the pinned CPUs, hugepages and NUMA topology left on the node for a latency-sensitive VM
+func (h *vmActionHandler) fitsNUMAPlacement(node *corev1.Node, vmi *kubevirtv1.VirtualMachineInstance, requirements numa.Requirements) (bool, error) {
+	if requirements.IsEmpty() {
+		return true, nil
+	}
+	vmis, err := virtualmachineinstance.ListByNode(node, labels.NewSelector(), h.vmiCache)
+	if err != nil {
+		return false, err
+	}
+	reasons, err := numa.Fits(node, vmis, requirements)
+	if err != nil {
+		return false, err
+	}
+	if len(reasons) > 0 {
+		logrus.Debugf("vm %s/%s can't be migrated to node %s: %s", vmi.Namespace, vmi.Name, node.Name, strings.Join(reasons, ", "))
+		return false, nil
+	}
+	return true, nil
+}
+
 func isDrained(node *corev1.Node) bool {
 	if _, ok := node.Annotations[nodecontroller.MaintainStatusAnnotationKey]; ok {
 		return ok
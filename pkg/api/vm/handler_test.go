@@ -2,12 +2,14 @@ package vm
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	corefake "k8s.io/client-go/kubernetes/fake"
@@ -716,3 +718,47 @@ func Test_vmActionHandler_findMigratableNodesByVMI(t *testing.T) {
 		})
 	}
 }
+
+func Test_vmActionHandler_findMigratableNodesByVMIWithDedicatedCPUs(t *testing.T) {
+	newNode := func(name string, cpuManager bool) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{kubevirtv1.CPUManager: strconv.FormatBool(cpuManager)},
+			},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")},
+			},
+		}
+	}
+	newVMI := func(name, nodeName string, cores uint32) *kubevirtv1.VirtualMachineInstance {
+		return &kubevirtv1.VirtualMachineInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{util.LabelNodeNameKey: nodeName},
+			},
+			Spec: kubevirtv1.VirtualMachineInstanceSpec{
+				Domain: kubevirtv1.DomainSpec{
+					CPU: &kubevirtv1.CPU{Cores: cores, DedicatedCPUPlacement: true},
+				},
+			},
+			Status: kubevirtv1.VirtualMachineInstanceStatus{
+				NodeName: nodeName,
+				Phase:    kubevirtv1.Running,
+			},
+		}
+	}
+
+	coreclientset := corefake.NewSimpleClientset(newNode("busy", true), newNode("free", true), newNode("shared", false), newNode("source", true))
+	vmi := newVMI("test", "source", 4)
+	clientset := fake.NewSimpleClientset(vmi, newVMI("busy", "busy", 6))
+	h := &vmActionHandler{
+		nodeCache: fakeclients.NodeCache(coreclientset.CoreV1().Nodes),
+		vmiCache:  fakeclients.VirtualMachineInstanceCache(clientset.KubevirtV1().VirtualMachineInstances),
+	}
+
+	nodes, err := h.findMigratableNodesByVMI(vmi)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"free"}, nodes, "the other nodes lack the CPU manager or free pinned CPUs")
+}
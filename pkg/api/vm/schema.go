@@ -36,6 +36,8 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server, options config
 	server.BaseSchemas.MustImportAndCustomize(AddVolumeInput{}, nil)
 	server.BaseSchemas.MustImportAndCustomize(RemoveVolumeInput{}, nil)
 	server.BaseSchemas.MustImportAndCustomize(CloneInput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(IssueSSHCertificateInput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(GenerateSSHHostConfigInput{}, nil)
 
 	vms := scaled.VirtFactory.Kubevirt().V1().VirtualMachine()
 	vmis := scaled.VirtFactory.Kubevirt().V1().VirtualMachineInstance()
@@ -133,6 +135,8 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server, options config
 				dismissInsufficientResourceQuota: &actionHandler,
 				updateResourceQuotaAction:        &actionHandler,
 				deleteResourceQuotaAction:        &actionHandler,
+				issueSSHCertificate:              &actionHandler,
+				generateSSHHostConfig:            &actionHandler,
 			}
 			apiSchema.ResourceActions = map[string]schemas.Action{
 				startVM:    {},
@@ -173,6 +177,12 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server, options config
 					Input: "updateResourceQuotaInput",
 				},
 				deleteResourceQuotaAction: {},
+				issueSSHCertificate: {
+					Input: "issueSSHCertificateInput",
+				},
+				generateSSHHostConfig: {
+					Input: "generateSSHHostConfigInput",
+				},
 			}
 		},
 		Formatter: vmformatter.formatter,
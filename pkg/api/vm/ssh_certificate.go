@@ -0,0 +1,145 @@
+package vm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
+	"golang.org/x/crypto/ssh"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/authentication/user"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	apiutil "github.com/cloudweav/cloudweav/pkg/api/util"
+	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/util/sshca"
+)
+
+// loadSSHCA returns the cluster SSH certificate authority and its settings, or an error
+// when the authority is not enabled.
+func (h *vmActionHandler) loadSSHCA() (ssh.Signer, []byte, *settings.SSHCAConfig, error) {
+	config, err := settings.DecodeSSHCAConfig(settings.SSHCASet.Get())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !config.Enabled {
+		return nil, nil, nil, apierror.NewAPIError(validation.ActionNotAvailable, fmt.Sprintf("setting %s is not enabled", settings.SSHCASettingName))
+	}
+
+	secret, err := h.secretCache.Get(h.namespace, sshca.SecretName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil, apierror.NewAPIError(validation.ActionNotAvailable, "SSH certificate authority is not ready")
+		}
+		return nil, nil, nil, err
+	}
+	ca, err := sshca.LoadCA(secret)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return ca, secret.Data[sshca.SecretKeyPublicKey], config, nil
+}
+
+// issueSSHCertificate signs a short-lived user certificate for the requester. The certificate is
+// bound to the platform user by its key ID and only grants the principal of this VM, so it can't
+// be used on other VMs, and access ends when it expires instead of requiring key removal.
+func (h *vmActionHandler) issueSSHCertificate(userInfo user.Info, namespace, name string, input IssueSSHCertificateInput) (*IssueSSHCertificateOutput, error) {
+	// issuing a login certificate is as sensitive as opening the VM console
+	if ok, err := apiutil.CanAccessResource(h.clientSet, userInfo, &authorizationv1.ResourceAttributes{
+		Namespace:   namespace,
+		Verb:        "get",
+		Group:       kubevirtSubResouceGroupVersion.Group,
+		Resource:    vmiResource,
+		Subresource: "console",
+		Name:        name,
+	}); err != nil {
+		return nil, apierror.NewAPIError(validation.ServerError, fmt.Sprintf("Failed to check permission: %v", err))
+	} else if !ok {
+		return nil, apierror.NewAPIError(validation.PermissionDenied, fmt.Sprintf("User does not have permission to access the console of virtual machine %s/%s", namespace, name))
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(input.PublicKey))
+	if err != nil {
+		return nil, apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Invalid public key: %v", err))
+	}
+	if _, ok := publicKey.(*ssh.Certificate); ok {
+		return nil, apierror.NewAPIError(validation.InvalidBodyContent, "Public key must not be a certificate")
+	}
+
+	if _, err := h.vmCache.Get(namespace, name); err != nil {
+		return nil, err
+	}
+
+	ca, _, config, err := h.loadSSHCA()
+	if err != nil {
+		return nil, err
+	}
+
+	validMinutes := input.ValidMinutes
+	if validMinutes <= 0 {
+		validMinutes = config.DefaultValidMinutes
+	}
+	if validMinutes > config.MaxValidMinutes {
+		return nil, apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("validMinutes should not be greater than %d", config.MaxValidMinutes))
+	}
+
+	cert, err := sshca.SignUserCertificate(ca, publicKey, userInfo.GetName(), []string{sshca.VMPrincipal(namespace, name)},
+		time.Duration(validMinutes)*time.Minute, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &IssueSSHCertificateOutput{
+		Certificate: string(ssh.MarshalAuthorizedKey(cert)),
+		Principal:   sshca.VMPrincipal(namespace, name),
+		ValidBefore: time.Unix(int64(cert.ValidBefore), 0).UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// generateSSHHostConfig returns the cloud-config to add to the user data of the VM, so the VM
+// trusts the user certificates of the cluster and presents a host certificate signed by it.
+func (h *vmActionHandler) generateSSHHostConfig(userInfo user.Info, namespace, name string, input GenerateSSHHostConfigInput) (*GenerateSSHHostConfigOutput, error) {
+	if ok, err := apiutil.CanAccessResource(h.clientSet, userInfo, &authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      "update",
+		Group:     kubevirtv1.SchemeGroupVersion.Group,
+		Resource:  vmResource,
+		Name:      name,
+	}); err != nil {
+		return nil, apierror.NewAPIError(validation.ServerError, fmt.Sprintf("Failed to check permission: %v", err))
+	} else if !ok {
+		return nil, apierror.NewAPIError(validation.PermissionDenied, fmt.Sprintf("User does not have permission to update virtual machine %s/%s", namespace, name))
+	}
+
+	for _, user := range input.Users {
+		if err := sshca.ValidateUser(user); err != nil {
+			return nil, apierror.NewAPIError(validation.InvalidBodyContent, err.Error())
+		}
+	}
+
+	if _, err := h.vmCache.Get(namespace, name); err != nil {
+		return nil, err
+	}
+
+	ca, caPublicKey, config, err := h.loadSSHCA()
+	if err != nil {
+		return nil, err
+	}
+
+	hostNames := input.HostNames
+	if len(hostNames) == 0 {
+		hostNames = []string{name}
+	}
+	cloudConfig, err := sshca.HostCloudConfig(ca, caPublicKey, namespace, name, hostNames, input.Users,
+		time.Duration(config.HostValidDays)*24*time.Hour, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &GenerateSSHHostConfigOutput{
+		CloudConfig: cloudConfig,
+		CAPublicKey: string(caPublicKey),
+	}, nil
+}
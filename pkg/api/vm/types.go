@@ -50,3 +50,24 @@ type FindMigratableNodesOutput struct {
 type UpdateResourceQuotaInput struct {
 	TotalSnapshotSizeQuota string `json:"totalSnapshotSizeQuota"`
 }
+
+type IssueSSHCertificateInput struct {
+	PublicKey    string `json:"publicKey"`
+	ValidMinutes int    `json:"validMinutes,omitempty"`
+}
+
+type IssueSSHCertificateOutput struct {
+	Certificate string `json:"certificate"`
+	Principal   string `json:"principal"`
+	ValidBefore string `json:"validBefore"`
+}
+
+type GenerateSSHHostConfigInput struct {
+	HostNames []string `json:"hostNames,omitempty"`
+	Users     []string `json:"users,omitempty"`
+}
+
+type GenerateSSHHostConfigOutput struct {
+	CloudConfig string `json:"cloudConfig"`
+	CAPublicKey string `json:"caPublicKey"`
+}
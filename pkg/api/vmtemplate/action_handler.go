@@ -0,0 +1,421 @@
+package vmtemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rancher/apiserver/pkg/apierror"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	wranglername "github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
+	"github.com/sirupsen/logrus"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	apiutil "github.com/cloudweav/cloudweav/pkg/api/util"
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
+	"github.com/cloudweav/cloudweav/pkg/ref"
+	"github.com/cloudweav/cloudweav/pkg/util"
+	vmtemplateutil "github.com/cloudweav/cloudweav/pkg/util/vmtemplate"
+	"github.com/cloudweav/cloudweav/pkg/webhook/types"
+)
+
+const (
+	actionInstantiate = "instantiate"
+	actionDeprecate   = "deprecate"
+	actionUndeprecate = "undeprecate"
+	actionPublish     = "publish"
+	actionUnpublish   = "unpublish"
+	linkDiff          = "diff"
+	linkDrift         = "drift"
+
+	vmResource              = "virtualmachines"
+	secretResource          = "secrets"
+	templateResource        = "virtualmachinetemplates"
+	templateVersionResource = "virtualmachinetemplateversions"
+)
+
+type templateActionHandler struct {
+	templates            ctlcloudweavv1.VirtualMachineTemplateClient
+	templateCache        ctlcloudweavv1.VirtualMachineTemplateCache
+	templateVersions     ctlcloudweavv1.VirtualMachineTemplateVersionClient
+	templateVersionCache ctlcloudweavv1.VirtualMachineTemplateVersionCache
+	vms                  ctlkubevirtv1.VirtualMachineClient
+	vmCache              ctlkubevirtv1.VirtualMachineCache
+	secrets              ctlcorev1.SecretClient
+	secretCache          ctlcorev1.SecretCache
+	clientSet            kubernetes.Clientset
+	// vmMutator is the VM mutating webhook, used to render the VMs as they are created
+	vmMutator types.Mutator
+}
+
+// secretCopy is a secret referenced by the template version that is copied for the new VM
+type secretCopy struct {
+	sourceName string
+	targetName string
+	render     bool
+}
+
+func (h templateActionHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	result, err := h.do(req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if e, ok := err.(*apierror.APIError); ok {
+			status = e.Code.Status
+		}
+		util.ResponseErrorMsg(rw, status, err.Error())
+		return
+	}
+	util.ResponseOKWithBody(rw, result)
+}
+
+func (h *templateActionHandler) do(r *http.Request) (interface{}, error) {
+	vars := util.EncodeVars(mux.Vars(r))
+	namespace := vars["namespace"]
+	name := vars["name"]
+
+	if r.Method == http.MethodGet {
+		switch link := vars["link"]; link {
+		case linkDiff:
+			return h.diff(namespace, name, r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+		case linkDrift:
+			return h.drift(namespace, name)
+		default:
+			return nil, apierror.NewAPIError(validation.InvalidAction, fmt.Sprintf("Unsupported GET action %s", link))
+		}
+	}
+
+	user, ok := request.UserFrom(r.Context())
+	if !ok {
+		return nil, apierror.NewAPIError(validation.Unauthorized, "failed to get user from request")
+	}
+
+	switch action := vars["action"]; action {
+	case actionInstantiate:
+		var input InstantiateInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			return nil, apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to decode request body: %v", err))
+		}
+		if input.Name == "" {
+			return nil, apierror.NewAPIError(validation.InvalidBodyContent, "Parameter name is required")
+		}
+		return h.instantiate(user, namespace, name, input)
+	case actionPublish:
+		var input PublishInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			return nil, apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to decode request body: %v", err))
+		}
+		if !input.ClusterWide && len(input.Namespaces) == 0 {
+			return nil, apierror.NewAPIError(validation.InvalidBodyContent, "Parameter namespaces is required unless the template is published cluster-wide")
+		}
+		if err := h.checkAccess(user, namespace, templateResource, name, "update"); err != nil {
+			return nil, err
+		}
+		return h.setSharing(namespace, name, &cloudweavv1.VirtualMachineTemplateSharing{
+			ClusterWide: input.ClusterWide,
+			Namespaces:  input.Namespaces,
+		})
+	case actionUnpublish:
+		if err := h.checkAccess(user, namespace, templateResource, name, "update"); err != nil {
+			return nil, err
+		}
+		return h.setSharing(namespace, name, nil)
+	case actionDeprecate:
+		if err := h.checkAccess(user, namespace, templateVersionResource, name, "update"); err != nil {
+			return nil, err
+		}
+		var input DeprecateInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			return nil, apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Failed to decode request body: %v", err))
+		}
+		if input.Reason == "" {
+			return nil, apierror.NewAPIError(validation.InvalidBodyContent, "Parameter reason is required")
+		}
+		return h.setDeprecation(namespace, name, &cloudweavv1.VirtualMachineTemplateVersionDeprecation{
+			Reason:         input.Reason,
+			DeprecatedTime: time.Now().UTC().Format(time.RFC3339),
+		})
+	case actionUndeprecate:
+		if err := h.checkAccess(user, namespace, templateVersionResource, name, "update"); err != nil {
+			return nil, err
+		}
+		return h.setDeprecation(namespace, name, nil)
+	default:
+		return nil, apierror.NewAPIError(validation.InvalidAction, "Unsupported action")
+	}
+}
+
+// instantiate renders the template version with the input parameters, then creates the VM and its secrets.
+// The VM is created in the template namespace, or in a namespace the template is shared with.
+func (h *templateActionHandler) instantiate(userInfo user.Info, namespace, name string, input InstantiateInput) (*kubevirtv1.VirtualMachine, error) {
+	template, err := h.templateCache.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	targetNamespace := input.Namespace
+	if targetNamespace == "" {
+		targetNamespace = namespace
+	}
+	if targetNamespace != namespace && !isSharedWith(template, targetNamespace) {
+		return nil, apierror.NewAPIError(validation.PermissionDenied, fmt.Sprintf("Template %s/%s is not shared with namespace %s", namespace, name, targetNamespace))
+	}
+	if err := h.checkAccess(userInfo, targetNamespace, vmResource, "", "create"); err != nil {
+		return nil, err
+	}
+
+	versionID := input.VersionID
+	if versionID == "" {
+		versionID = template.Spec.DefaultVersionID
+	}
+	if versionID == "" {
+		return nil, apierror.NewAPIError(validation.InvalidBodyContent, "Template has no default version, parameter versionId is required")
+	}
+	version, err := h.getTemplateVersion(namespace, name, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := vmtemplateutil.ResolveParameters(version.Spec.Parameters, input.Parameters)
+	if err != nil {
+		return nil, apierror.NewAPIError(validation.InvalidBodyContent, err.Error())
+	}
+
+	vm, secrets, err := renderVM(version, targetNamespace, input.Name, values)
+	if err != nil {
+		return nil, err
+	}
+	// the secrets are copied with the server's privileges, the user must be able to read them
+	for _, secret := range secrets {
+		if err := h.checkAccess(userInfo, version.Namespace, secretResource, secret.sourceName, "get"); err != nil {
+			return nil, err
+		}
+	}
+
+	vm, err = h.vms.Create(vm)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.createSecrets(version.Namespace, vm, secrets, values); err != nil {
+		// the secrets already created are garbage collected with the VM
+		if deleteErr := h.vms.Delete(vm.Namespace, vm.Name, &metav1.DeleteOptions{}); deleteErr != nil {
+			logrus.WithError(deleteErr).Errorf("failed to clean up VM %s/%s", vm.Namespace, vm.Name)
+		}
+		return nil, err
+	}
+	return vm, nil
+}
+
+// renderVM builds the VM from the template version. The volume claims and secrets are renamed after
+// the VM, so that several VMs can be instantiated from the same template version.
+func renderVM(version *cloudweavv1.VirtualMachineTemplateVersion, vmNamespace, vmName string, values map[string]string) (*kubevirtv1.VirtualMachine, []secretCopy, error) {
+	source, err := vmtemplateutil.RenderVMSource(version.Spec.VM, values)
+	if err != nil {
+		return nil, nil, apierror.NewAPIError(validation.InvalidBodyContent, err.Error())
+	}
+	if source.Spec.Template == nil {
+		return nil, nil, apierror.NewAPIError(validation.ServerError, fmt.Sprintf("Template version %s/%s has no VM template", version.Namespace, version.Name))
+	}
+
+	vm := &kubevirtv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        vmName,
+			Namespace:   vmNamespace,
+			Labels:      source.ObjectMeta.Labels,
+			Annotations: source.ObjectMeta.Annotations,
+		},
+		Spec: source.Spec,
+	}
+	if vm.Annotations == nil {
+		vm.Annotations = map[string]string{}
+	}
+	vm.Annotations[util.AnnotationTemplateVersionID] = ref.Construct(version.Namespace, version.Name)
+	if len(values) > 0 {
+		parameters, err := json.Marshal(values)
+		if err != nil {
+			return nil, nil, err
+		}
+		vm.Annotations[util.AnnotationTemplateParameters] = string(parameters)
+	}
+	if vm.Spec.Template.ObjectMeta.Labels == nil {
+		vm.Spec.Template.ObjectMeta.Labels = map[string]string{}
+	}
+	vm.Spec.Template.ObjectMeta.Labels[util.LabelVMName] = vmName
+
+	if err := renameVolumeClaims(vm); err != nil {
+		return nil, nil, err
+	}
+	return vm, renameSecrets(vm), nil
+}
+
+func renameVolumeClaims(vm *kubevirtv1.VirtualMachine) error {
+	volumeClaimTemplatesStr, ok := vm.Annotations[util.AnnotationVolumeClaimTemplates]
+	if !ok || volumeClaimTemplatesStr == "" {
+		return nil
+	}
+
+	var volumeClaimTemplates []corev1.PersistentVolumeClaim
+	if err := json.Unmarshal([]byte(volumeClaimTemplatesStr), &volumeClaimTemplates); err != nil {
+		return fmt.Errorf("can't unmarshal %s, err: %w", util.AnnotationVolumeClaimTemplates, err)
+	}
+
+	claimNames := make(map[string]string, len(volumeClaimTemplates))
+	for _, volume := range vm.Spec.Template.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		newName := wranglername.SafeConcatName(vm.Name, volume.Name, rand.String(5))
+		claimNames[volume.PersistentVolumeClaim.ClaimName] = newName
+		volume.PersistentVolumeClaim.ClaimName = newName
+	}
+	for i := range volumeClaimTemplates {
+		if newName, ok := claimNames[volumeClaimTemplates[i].Name]; ok {
+			volumeClaimTemplates[i].Name = newName
+		}
+	}
+
+	data, err := json.Marshal(volumeClaimTemplates)
+	if err != nil {
+		return err
+	}
+	vm.Annotations[util.AnnotationVolumeClaimTemplates] = string(data)
+	return nil
+}
+
+// renameSecrets points the VM to its own copies of the access credential and cloud-init secrets.
+// Only the cloud-init secrets are rendered with the parameters.
+func renameSecrets(vm *kubevirtv1.VirtualMachine) []secretCopy {
+	var secrets []secretCopy
+	for index, credential := range vm.Spec.Template.Spec.AccessCredentials {
+		if sshPublicKey := credential.SSHPublicKey; sshPublicKey != nil && sshPublicKey.Source.Secret != nil {
+			targetName := wranglername.SafeConcatName(vm.Name, fmt.Sprintf("credential-%d", index), "sshpublickey")
+			secrets = append(secrets, secretCopy{sourceName: sshPublicKey.Source.Secret.SecretName, targetName: targetName})
+			sshPublicKey.Source.Secret.SecretName = targetName
+		}
+		if userPassword := credential.UserPassword; userPassword != nil && userPassword.Source.Secret != nil {
+			targetName := wranglername.SafeConcatName(vm.Name, fmt.Sprintf("credential-%d", index), "userpassword")
+			secrets = append(secrets, secretCopy{sourceName: userPassword.Source.Secret.SecretName, targetName: targetName})
+			userPassword.Source.Secret.SecretName = targetName
+		}
+	}
+	for _, volume := range vm.Spec.Template.Spec.Volumes {
+		if volume.CloudInitNoCloud == nil {
+			continue
+		}
+		if secretRef := volume.CloudInitNoCloud.UserDataSecretRef; secretRef != nil {
+			targetName := wranglername.SafeConcatName(vm.Name, volume.Name, "userdata")
+			secrets = append(secrets, secretCopy{sourceName: secretRef.Name, targetName: targetName, render: true})
+			secretRef.Name = targetName
+		}
+		if secretRef := volume.CloudInitNoCloud.NetworkDataSecretRef; secretRef != nil && secretRef.Name != "" {
+			targetName := wranglername.SafeConcatName(vm.Name, volume.Name, "networkdata")
+			// the user data and network data may share the same secret
+			if len(secrets) == 0 || secrets[len(secrets)-1].sourceName != secretRef.Name {
+				secrets = append(secrets, secretCopy{sourceName: secretRef.Name, targetName: targetName, render: true})
+			} else {
+				targetName = secrets[len(secrets)-1].targetName
+			}
+			secretRef.Name = targetName
+		}
+	}
+	return secrets
+}
+
+func (h *templateActionHandler) createSecrets(sourceNamespace string, vm *kubevirtv1.VirtualMachine, secrets []secretCopy, values map[string]string) error {
+	for _, s := range secrets {
+		source, err := h.secretCache.Get(sourceNamespace, s.sourceName)
+		if err != nil {
+			return err
+		}
+
+		data := make(map[string][]byte, len(source.Data))
+		for key, value := range source.Data {
+			if s.render {
+				rendered, err := vmtemplateutil.RenderYAML(string(value), values)
+				if err != nil {
+					return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("Failed to render secret %s/%s: %v", sourceNamespace, s.sourceName, err))
+				}
+				value = []byte(rendered)
+			}
+			data[key] = value
+		}
+
+		toCreate := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.targetName,
+				Namespace: vm.Namespace,
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: kubevirtv1.SchemeGroupVersion.String(),
+						Kind:       kubevirtv1.VirtualMachineGroupVersionKind.Kind,
+						Name:       vm.Name,
+						UID:        vm.UID,
+					},
+				},
+			},
+			Type: source.Type,
+			Data: data,
+		}
+		if _, err := h.secrets.Create(toCreate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *templateActionHandler) setSharing(namespace, name string, sharing *cloudweavv1.VirtualMachineTemplateSharing) (*cloudweavv1.VirtualMachineTemplate, error) {
+	template, err := h.templateCache.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	toUpdate := template.DeepCopy()
+	toUpdate.Spec.SharedWith = sharing
+	return h.templates.Update(toUpdate)
+}
+
+func (h *templateActionHandler) checkAccess(userInfo user.Info, namespace, resource, name, verb string) error {
+	group := cloudweavv1.SchemeGroupVersion
+	switch resource {
+	case vmResource:
+		group = kubevirtv1.SchemeGroupVersion
+	case secretResource:
+		group = corev1.SchemeGroupVersion
+	}
+	allowed, err := apiutil.CanAccessResource(h.clientSet, userInfo, &authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      verb,
+		Group:     group.Group,
+		Version:   group.Version,
+		Resource:  resource,
+		Name:      name,
+	})
+	if err != nil {
+		return apierror.NewAPIError(validation.ServerError, fmt.Sprintf("Failed to check permission: %v", err))
+	}
+	if !allowed {
+		return apierror.NewAPIError(validation.PermissionDenied, fmt.Sprintf("User %s is not allowed to %s %s in namespace %s", userInfo.GetName(), verb, resource, namespace))
+	}
+	return nil
+}
+
+func isSharedWith(template *cloudweavv1.VirtualMachineTemplate, namespace string) bool {
+	sharing := template.Spec.SharedWith
+	if sharing == nil {
+		return false
+	}
+	return sharing.ClusterWide || slices.Contains(sharing.Namespaces, namespace)
+}
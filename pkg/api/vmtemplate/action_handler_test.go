@@ -0,0 +1,80 @@
+package vmtemplate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+func TestRenderVM(t *testing.T) {
+	volumeClaimTemplates, _ := json.Marshal([]corev1.PersistentVolumeClaim{{
+		ObjectMeta: metav1.ObjectMeta{Name: "templateversion-v1-disk-0"},
+	}})
+	version := &cloudweavv1.VirtualMachineTemplateVersion{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "v1"},
+		Spec: cloudweavv1.VirtualMachineTemplateVersionSpec{
+			VM: cloudweavv1.VirtualMachineSourceSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{util.AnnotationVolumeClaimTemplates: string(volumeClaimTemplates)},
+				},
+				Spec: kubevirtv1.VirtualMachineSpec{
+					Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+						Spec: kubevirtv1.VirtualMachineInstanceSpec{
+							Hostname: "${{ hostname }}",
+							Volumes: []kubevirtv1.Volume{
+								{
+									Name: "rootdisk",
+									VolumeSource: kubevirtv1.VolumeSource{
+										PersistentVolumeClaim: &kubevirtv1.PersistentVolumeClaimVolumeSource{
+											PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{ClaimName: "templateversion-v1-disk-0"},
+										},
+									},
+								},
+								{
+									Name: "cloudinitdisk",
+									VolumeSource: kubevirtv1.VolumeSource{
+										CloudInitNoCloud: &kubevirtv1.CloudInitNoCloudSource{
+											UserDataSecretRef:    &corev1.LocalObjectReference{Name: "templateversion-v1-cloudinitdisk-userdata"},
+											NetworkDataSecretRef: &corev1.LocalObjectReference{Name: "templateversion-v1-cloudinitdisk-userdata"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	vm, secrets, err := renderVM(version, "default", "web", map[string]string{"hostname": "web-1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "web", vm.Name)
+	assert.Equal(t, "default", vm.Namespace)
+	assert.Equal(t, "web-1", vm.Spec.Template.Spec.Hostname)
+	assert.Equal(t, "web", vm.Spec.Template.ObjectMeta.Labels[util.LabelVMName])
+	assert.Equal(t, "default/v1", vm.Annotations[util.AnnotationTemplateVersionID])
+	assert.Equal(t, `{"hostname":"web-1"}`, vm.Annotations[util.AnnotationTemplateParameters])
+
+	claimName := vm.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName
+	assert.Contains(t, claimName, "web-rootdisk-")
+	var renamed []corev1.PersistentVolumeClaim
+	assert.Nil(t, json.Unmarshal([]byte(vm.Annotations[util.AnnotationVolumeClaimTemplates]), &renamed))
+	assert.Equal(t, claimName, renamed[0].Name)
+
+	assert.Equal(t, []secretCopy{{
+		sourceName: "templateversion-v1-cloudinitdisk-userdata",
+		targetName: "web-cloudinitdisk-userdata",
+		render:     true,
+	}}, secrets)
+	cloudInit := vm.Spec.Template.Spec.Volumes[1].CloudInitNoCloud
+	assert.Equal(t, "web-cloudinitdisk-userdata", cloudInit.UserDataSecretRef.Name)
+	assert.Equal(t, "web-cloudinitdisk-userdata", cloudInit.NetworkDataSecretRef.Name)
+}
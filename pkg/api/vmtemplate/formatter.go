@@ -6,8 +6,20 @@ import (
 
 func formatter(request *types.APIRequest, resource *types.RawResource) {
 	resource.Links["versions"] = request.URLBuilder.Link(resource.Schema, resource.ID, "versions")
+	resource.Links[linkDiff] = request.URLBuilder.Link(resource.Schema, resource.ID, linkDiff)
+	resource.Links[linkDrift] = request.URLBuilder.Link(resource.Schema, resource.ID, linkDrift)
 }
 
-func versionFormatter(_ *types.APIRequest, resource *types.RawResource) {
+func versionFormatter(request *types.APIRequest, resource *types.RawResource) {
 	delete(resource.Links, "update")
+
+	resource.Actions = make(map[string]string, 1)
+	if request.AccessControl.CanUpdate(request, resource.APIObject, resource.Schema) != nil {
+		return
+	}
+	if resource.APIObject.Data().Map("spec").Map("deprecation") == nil {
+		resource.AddAction(request, actionDeprecate)
+	} else {
+		resource.AddAction(request, actionUndeprecate)
+	}
 }
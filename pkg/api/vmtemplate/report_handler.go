@@ -0,0 +1,190 @@
+package vmtemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/rancher/apiserver/pkg/apierror"
+	"github.com/rancher/wrangler/v3/pkg/schemas/validation"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/controller/master/template"
+	"github.com/cloudweav/cloudweav/pkg/indexeres"
+	"github.com/cloudweav/cloudweav/pkg/ref"
+	"github.com/cloudweav/cloudweav/pkg/util"
+	vmtemplateutil "github.com/cloudweav/cloudweav/pkg/util/vmtemplate"
+)
+
+// diff compares two versions of the template. The default version is used when from is empty.
+func (h *templateActionHandler) diff(namespace, name, from, to string) (*VersionDiffOutput, error) {
+	vmTemplate, err := h.templateCache.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if from == "" {
+		from = vmTemplate.Spec.DefaultVersionID
+	}
+	if from == "" || to == "" {
+		return nil, apierror.NewAPIError(validation.InvalidBodyContent, "Parameters from and to are required")
+	}
+
+	fromVersion, err := h.getTemplateVersion(namespace, name, from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := h.getTemplateVersion(namespace, name, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := vmtemplateutil.Diff(fromVersion.Spec, toVersion.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &VersionDiffOutput{
+		From:    from,
+		To:      to,
+		Changes: changes,
+	}, nil
+}
+
+// drift reports the VMs created from the template which drifted from their template version,
+// or whose template version is deprecated. The VMs are found by their templateVersionId annotation,
+// which is only set by the instantiate action. The VMs created otherwise are not reported unless
+// the annotation is added to them.
+func (h *templateActionHandler) drift(namespace, name string) (*DriftReportOutput, error) {
+	versions, err := h.templateVersionCache.List(namespace, labels.Set{
+		template.TemplateLabel: name,
+	}.AsSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DriftReportOutput{
+		VirtualMachines: []VMDrift{},
+	}
+	for _, version := range versions {
+		versionID := ref.Construct(version.Namespace, version.Name)
+		vms, err := h.vmCache.GetByIndex(indexeres.VMByTemplateVersionIndex, versionID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, vm := range vms {
+			values, err := getTemplateParameters(version, vm.Annotations[util.AnnotationTemplateParameters])
+			if err != nil {
+				logrus.WithError(err).Warnf("failed to get template parameters of VM %s/%s", vm.Namespace, vm.Name)
+			}
+			expected, err := h.expectedVM(version, vm, values)
+			if err != nil {
+				return nil, err
+			}
+			changes, err := vmtemplateutil.Drift(expected, vm)
+			if err != nil {
+				return nil, err
+			}
+
+			vmDrift := VMDrift{
+				VirtualMachine: ref.Construct(vm.Namespace, vm.Name),
+				VersionID:      versionID,
+				Version:        version.Status.Version,
+				Drifted:        len(changes) > 0,
+				Changes:        changes,
+			}
+			if version.Spec.Deprecation != nil {
+				vmDrift.Deprecated = true
+				vmDrift.DeprecationReason = version.Spec.Deprecation.Reason
+			}
+			if vmDrift.Drifted || vmDrift.Deprecated {
+				report.VirtualMachines = append(report.VirtualMachines, vmDrift)
+			}
+		}
+	}
+
+	sort.Slice(report.VirtualMachines, func(i, j int) bool {
+		return report.VirtualMachines[i].VirtualMachine < report.VirtualMachines[j].VirtualMachine
+	})
+	return report, nil
+}
+
+// expectedVM renders the template version for the VM, then applies the VM mutating webhook, so that
+// the defaults the webhook adds on creation are not reported as drifts.
+func (h *templateActionHandler) expectedVM(version *cloudweavv1.VirtualMachineTemplateVersion, vm *kubevirtv1.VirtualMachine, values map[string]string) (*kubevirtv1.VirtualMachine, error) {
+	expected, _, err := renderVM(version, vm.Namespace, vm.Name, values)
+	if err != nil {
+		return nil, err
+	}
+
+	patchOps, err := h.vmMutator.Create(nil, expected.DeepCopy())
+	if err != nil {
+		return nil, err
+	}
+	if len(patchOps) == 0 {
+		return expected, nil
+	}
+	patch, err := jsonpatch.DecodePatch([]byte("[" + strings.Join(patchOps, ",") + "]"))
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(expected)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = patch.Apply(data); err != nil {
+		return nil, fmt.Errorf("failed to mutate the VM rendered for %s/%s: %w", vm.Namespace, vm.Name, err)
+	}
+	mutated := &kubevirtv1.VirtualMachine{}
+	if err := json.Unmarshal(data, mutated); err != nil {
+		return nil, err
+	}
+	return mutated, nil
+}
+
+func (h *templateActionHandler) setDeprecation(namespace, name string, deprecation *cloudweavv1.VirtualMachineTemplateVersionDeprecation) (*cloudweavv1.VirtualMachineTemplateVersion, error) {
+	version, err := h.templateVersionCache.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	toUpdate := version.DeepCopy()
+	toUpdate.Spec.Deprecation = deprecation
+	return h.templateVersions.Update(toUpdate)
+}
+
+func (h *templateActionHandler) getTemplateVersion(templateNamespace, templateName, versionID string) (*cloudweavv1.VirtualMachineTemplateVersion, error) {
+	versionNamespace, versionName := ref.Parse(versionID)
+	version, err := h.templateVersionCache.Get(versionNamespace, versionName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Template version %s is not found", versionID))
+		}
+		return nil, err
+	}
+	if version.Spec.TemplateID != ref.Construct(templateNamespace, templateName) {
+		return nil, apierror.NewAPIError(validation.InvalidBodyContent, fmt.Sprintf("Template version %s does not belong to template %s/%s", versionID, templateNamespace, templateName))
+	}
+	return version, nil
+}
+
+// getTemplateParameters returns the parameter values a VM was instantiated with, the defaults
+// apply to the parameters without a recorded value.
+func getTemplateParameters(version *cloudweavv1.VirtualMachineTemplateVersion, annotation string) (map[string]string, error) {
+	inputs := map[string]string{}
+	if annotation != "" {
+		if err := json.Unmarshal([]byte(annotation), &inputs); err != nil {
+			return nil, err
+		}
+	}
+	values, err := vmtemplateutil.ResolveParameters(version.Spec.Parameters, inputs)
+	if err != nil {
+		return inputs, err
+	}
+	return values, nil
+}
@@ -0,0 +1,72 @@
+package vmtemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/fake"
+	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+	vmtemplateutil "github.com/cloudweav/cloudweav/pkg/util/vmtemplate"
+	"github.com/cloudweav/cloudweav/pkg/webhook/resources/virtualmachine"
+)
+
+func TestExpectedVM(t *testing.T) {
+	clientSet := fake.NewSimpleClientset(&cloudweavv1.Setting{
+		ObjectMeta: metav1.ObjectMeta{Name: settings.DefaultVMTerminationGracePeriodSecondsSettingName},
+		Default:    "120",
+	})
+	h := &templateActionHandler{
+		vmMutator: virtualmachine.NewMutator(fakeclients.CloudweavSettingCache(clientSet.CloudweavhciV1beta1().Settings),
+			fakeclients.NetworkAttachmentDefinitionCache(clientSet.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+			fakeclients.VirtualMachineImageCache(clientSet.CloudweavhciV1beta1().VirtualMachineImages)),
+	}
+
+	version := &cloudweavv1.VirtualMachineTemplateVersion{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "v1"},
+		Spec: cloudweavv1.VirtualMachineTemplateVersionSpec{
+			VM: cloudweavv1.VirtualMachineSourceSpec{
+				Spec: kubevirtv1.VirtualMachineSpec{
+					Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+						Spec: kubevirtv1.VirtualMachineInstanceSpec{
+							Hostname: "${{ hostname }}",
+							Domain: kubevirtv1.DomainSpec{
+								CPU: &kubevirtv1.CPU{Cores: 2},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	terminationGracePeriodSeconds := int64(120)
+	vm := &kubevirtv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec: kubevirtv1.VirtualMachineSpec{
+			Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+				Spec: kubevirtv1.VirtualMachineInstanceSpec{
+					Hostname:                      "web-1",
+					TerminationGracePeriodSeconds: &terminationGracePeriodSeconds,
+					Domain: kubevirtv1.DomainSpec{
+						CPU: &kubevirtv1.CPU{Cores: 2},
+					},
+				},
+			},
+		},
+	}
+
+	expected, err := h.expectedVM(version, vm, map[string]string{"hostname": "web-1"})
+	assert.Nil(t, err)
+	changes, err := vmtemplateutil.Drift(expected, vm)
+	assert.Nil(t, err)
+	assert.Empty(t, changes, "defaults added by the webhook are not drifts")
+
+	vm.Spec.Template.Spec.Domain.CPU.Cores = 4
+	changes, err = vmtemplateutil.Drift(expected, vm)
+	assert.Nil(t, err)
+	assert.Equal(t, []vmtemplateutil.Change{{Path: "domain.cpu.cores", From: float64(2), To: float64(4)}}, changes)
+}
@@ -1,11 +1,15 @@
 package vmtemplate
 
 import (
+	"net/http"
+
 	"github.com/rancher/apiserver/pkg/types"
 	"github.com/rancher/steve/pkg/schema"
 	"github.com/rancher/steve/pkg/server"
+	"github.com/rancher/wrangler/v3/pkg/schemas"
 
 	"github.com/cloudweav/cloudweav/pkg/config"
+	"github.com/cloudweav/cloudweav/pkg/webhook/resources/virtualmachine"
 )
 
 const (
@@ -14,10 +18,35 @@ const (
 )
 
 func RegisterSchema(scaled *config.Scaled, server *server.Server, _ config.Options) error {
-	templateVersionCache := scaled.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineTemplateVersion().Cache()
+	server.BaseSchemas.MustImportAndCustomize(InstantiateInput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(DeprecateInput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(PublishInput{}, nil)
+
+	templates := scaled.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineTemplate()
+	templateVersions := scaled.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineTemplateVersion()
+	templateVersionCache := templateVersions.Cache()
+	vms := scaled.VirtFactory.Kubevirt().V1().VirtualMachine()
+	secrets := scaled.CoreFactory.Core().V1().Secret()
+	vmMutator := virtualmachine.NewMutator(
+		scaled.CloudweavFactory.Cloudweavhci().V1beta1().Setting().Cache(),
+		scaled.CniFactory.K8s().V1().NetworkAttachmentDefinition().Cache(),
+		scaled.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineImage().Cache(),
+	)
 	th := &templateLinkHandler{
 		templateVersionCache: templateVersionCache,
 	}
+	actionHandler := &templateActionHandler{
+		templates:            templates,
+		templateCache:        templates.Cache(),
+		templateVersions:     templateVersions,
+		templateVersionCache: templateVersionCache,
+		vms:                  vms,
+		vmCache:              vms.Cache(),
+		secrets:              secrets,
+		secretCache:          secrets.Cache(),
+		clientSet:            *scaled.Management.ClientSet,
+		vmMutator:            vmMutator,
+	}
 
 	t := []schema.Template{
 		{
@@ -25,11 +54,41 @@ func RegisterSchema(scaled *config.Scaled, server *server.Server, _ config.Optio
 			Formatter: formatter,
 			Customize: func(apiSchema *types.APISchema) {
 				apiSchema.ByIDHandler = th.byIDHandler
+				apiSchema.ActionHandlers = map[string]http.Handler{
+					actionInstantiate: actionHandler,
+					actionPublish:     actionHandler,
+					actionUnpublish:   actionHandler,
+				}
+				apiSchema.ResourceActions = map[string]schemas.Action{
+					actionInstantiate: {
+						Input: "instantiateInput",
+					},
+					actionPublish: {
+						Input: "publishInput",
+					},
+					actionUnpublish: {},
+				}
+				apiSchema.LinkHandlers = map[string]http.Handler{
+					linkDiff:  actionHandler,
+					linkDrift: actionHandler,
+				}
 			},
 		},
 		{
 			ID:        templateVersionSchemaID,
 			Formatter: versionFormatter,
+			Customize: func(apiSchema *types.APISchema) {
+				apiSchema.ActionHandlers = map[string]http.Handler{
+					actionDeprecate:   actionHandler,
+					actionUndeprecate: actionHandler,
+				}
+				apiSchema.ResourceActions = map[string]schemas.Action{
+					actionDeprecate: {
+						Input: "deprecateInput",
+					},
+					actionUndeprecate: {},
+				}
+			},
 		},
 	}
 
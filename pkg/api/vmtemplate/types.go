@@ -0,0 +1,46 @@
+package vmtemplate
+
+import (
+	vmtemplateutil "github.com/cloudweav/cloudweav/pkg/util/vmtemplate"
+)
+
+type InstantiateInput struct {
+	Name string `json:"name"`
+	// Namespace of the VM, it defaults to the template namespace and must be a namespace the template is shared with otherwise
+	Namespace string `json:"namespace,omitempty"`
+	// VersionID is the template version to instantiate, the default version is used when it is empty
+	VersionID  string            `json:"versionId,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+type PublishInput struct {
+	ClusterWide bool     `json:"clusterWide,omitempty"`
+	Namespaces  []string `json:"namespaces,omitempty"`
+}
+
+type DeprecateInput struct {
+	Reason string `json:"reason"`
+}
+
+type VersionDiffOutput struct {
+	From    string                  `json:"from"`
+	To      string                  `json:"to"`
+	Changes []vmtemplateutil.Change `json:"changes"`
+}
+
+// VMDrift reports a VM created from the template whose spec drifted from its template version,
+// or whose template version is deprecated.
+type VMDrift struct {
+	VirtualMachine    string                  `json:"virtualMachine"`
+	VersionID         string                  `json:"versionId"`
+	Version           int                     `json:"version"`
+	Deprecated        bool                    `json:"deprecated"`
+	DeprecationReason string                  `json:"deprecationReason,omitempty"`
+	Drifted           bool                    `json:"drifted"`
+	Changes           []vmtemplateutil.Change `json:"changes,omitempty"`
+}
+
+// DriftReportOutput lists the VMs annotated with a version of the template, see drift.
+type DriftReportOutput struct {
+	VirtualMachines []VMDrift `json:"virtualMachines"`
+}
@@ -12,6 +12,8 @@ var (
 	ImageRetryLimitExceeded condition.Cond = "RetryLimitExceeded"
 	BackingImageMissing     condition.Cond = "BackingImageMissing"
 	MetadataReady           condition.Cond = "MetadataReady"
+	ImageUnused             condition.Cond = "Unused"
+	ImageNodeCacheReady     condition.Cond = "NodeCacheReady"
 )
 
 // +genclient
@@ -65,6 +67,17 @@ type VirtualMachineImageSpec struct {
 
 	// +optional
 	SecurityParameters *VirtualMachineImageSecurityParameters `json:"securityParameters,omitempty"`
+
+	// +optional
+	NodeCache *VirtualMachineImageNodeCache `json:"nodeCache,omitempty"`
+}
+
+// VirtualMachineImageNodeCache places copies of the image on nodes ahead of VM creation,
+// so that volumes created from the image do not wait for the image to be transferred.
+type VirtualMachineImageNodeCache struct {
+	// NodeSelector selects the nodes that should keep a local copy of the image.
+	// +kubebuilder:validation:Required
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector"`
 }
 
 type VirtualMachineImageSecurityParameters struct {
@@ -127,6 +140,61 @@ type VirtualMachineImageStatus struct {
 
 	// +optional
 	Conditions []Condition `json:"conditions,omitempty"`
+
+	// +optional
+	Usage *VirtualMachineImageUsage `json:"usage,omitempty"`
+
+	// +optional
+	NodeCache []VirtualMachineImageNodeCacheStatus `json:"nodeCache,omitempty"`
+}
+
+// VirtualMachineImageNodeCacheStatus is the state of the image copy on a node selected by the node cache.
+type VirtualMachineImageNodeCacheStatus struct {
+	NodeName string `json:"nodeName"`
+
+	// DiskUUID is the Longhorn disk holding the copy on the node.
+	// +optional
+	DiskUUID string `json:"diskUUID,omitempty"`
+
+	// Ready is true when the copy on the node can be used by new volumes.
+	Ready bool `json:"ready"`
+
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// +optional
+	Progress int `json:"progress,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// VirtualMachineImageUsage lists the resources that reference an image.
+type VirtualMachineImageUsage struct {
+	// PVCs provisioned from the image storage class, in namespace/name form.
+	// +optional
+	PVCs []string `json:"pvcs,omitempty"`
+
+	// VirtualMachines that use the image through a volume or a volume claim template, in namespace/name form.
+	// +optional
+	VirtualMachines []string `json:"virtualMachines,omitempty"`
+
+	// TemplateVersions whose volume claim templates reference the image, in namespace/name form.
+	// +optional
+	TemplateVersions []string `json:"templateVersions,omitempty"`
+
+	// +optional
+	PVCCount int `json:"pvcCount"`
+
+	// +optional
+	VirtualMachineCount int `json:"virtualMachineCount"`
+
+	// +optional
+	TemplateVersionCount int `json:"templateVersionCount"`
+
+	// LastUsedTime is the last time the image was seen with at least one reference.
+	// +optional
+	LastUsedTime string `json:"lastUsedTime,omitempty"`
 }
 
 type Condition struct {
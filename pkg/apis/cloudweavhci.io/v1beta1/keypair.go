@@ -6,13 +6,25 @@ import (
 )
 
 var (
-	KeyPairValidated condition.Cond = "validated"
+	KeyPairValidated  condition.Cond = "validated"
+	KeyPairPropagated condition.Cond = "propagated"
+)
+
+type KeyPairPropagationState string
+
+const (
+	// KeyPairPropagationNotConfigured means the VM doesn't use the guest agent to receive the key updates
+	KeyPairPropagationNotConfigured KeyPairPropagationState = "NotConfigured"
+	KeyPairPropagationPending       KeyPairPropagationState = "Pending"
+	KeyPairPropagationSynchronized  KeyPairPropagationState = "Synchronized"
+	KeyPairPropagationFailed        KeyPairPropagationState = "Failed"
 )
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:resource:shortName=kp;kps,scope=Namespaced
 // +kubebuilder:printcolumn:name="FINGER_PRINT",type=string,JSONPath=`.status.fingerPrint`
+// +kubebuilder:printcolumn:name="SHA256_FINGER_PRINT",type=string,JSONPath=`.status.sha256FingerPrint`,priority=1
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=`.metadata.creationTimestamp`
 
 type KeyPair struct {
@@ -29,14 +41,43 @@ type KeyPairSpec struct {
 }
 
 type KeyPairStatus struct {
+	// FingerPrint is the legacy MD5 fingerprint of the public key
 	// +optional
 	FingerPrint string `json:"fingerPrint,omitempty"`
 
+	// SHA256FingerPrint is the SHA256 fingerprint of the public key, as printed by OpenSSH
+	// +optional
+	SHA256FingerPrint string `json:"sha256FingerPrint,omitempty"`
+
+	// KeyType is the algorithm of the public key, e.g. ssh-ed25519 or ssh-rsa
+	// +optional
+	KeyType string `json:"keyType,omitempty"`
+
+	// VirtualMachines reports the propagation of the public key to the VMs referencing the key pair
+	// +optional
+	VirtualMachines []KeyPairVirtualMachineStatus `json:"virtualMachines,omitempty"`
+
 	// +optional
 	Conditions []Condition `json:"conditions,omitempty"`
 }
 
+type KeyPairVirtualMachineStatus struct {
+	// Namespace is the namespace of the VM
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the VM
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Enum=NotConfigured;Pending;Synchronized;Failed
+	State KeyPairPropagationState `json:"state"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 type KeyGenInput struct {
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
+	// KeyType is the algorithm of the generated key, rsa or ed25519, defaults to rsa
+	KeyType string `json:"keyType,omitempty"`
 }
@@ -0,0 +1,132 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type MaintenancePlanPhase string
+
+const (
+	MaintenancePlanPhasePending   MaintenancePlanPhase = "Pending"
+	MaintenancePlanPhaseRunning   MaintenancePlanPhase = "Running"
+	MaintenancePlanPhaseSucceeded MaintenancePlanPhase = "Succeeded"
+	MaintenancePlanPhaseFailed    MaintenancePlanPhase = "Failed"
+)
+
+type MaintenancePlanNodePhase string
+
+const (
+	MaintenancePlanNodePhasePending             MaintenancePlanNodePhase = "Pending"
+	MaintenancePlanNodePhaseEnteringMaintenance MaintenancePlanNodePhase = "EnteringMaintenance"
+	MaintenancePlanNodePhaseRunningHook         MaintenancePlanNodePhase = "RunningHook"
+	MaintenancePlanNodePhaseWaitingForReboot    MaintenancePlanNodePhase = "WaitingForReboot"
+	MaintenancePlanNodePhaseExitingMaintenance  MaintenancePlanNodePhase = "ExitingMaintenance"
+	MaintenancePlanNodePhaseWaitingForVolumes   MaintenancePlanNodePhase = "WaitingForVolumes"
+	MaintenancePlanNodePhaseSucceeded           MaintenancePlanNodePhase = "Succeeded"
+	MaintenancePlanNodePhaseFailed              MaintenancePlanNodePhase = "Failed"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=mp;mps,scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="PHASE",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="MAX_UNAVAILABLE",type=integer,JSONPath=`.spec.maxUnavailable`
+// +kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// MaintenancePlan puts a pool of nodes in maintenance mode one after the other. Each node is drained,
+// an optional hook job runs on it, then the node exits maintenance mode and the plan waits for the
+// Longhorn volumes to be healthy again before it moves to the next node. The plan stops on the first failure.
+type MaintenancePlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MaintenancePlanSpec   `json:"spec"`
+	Status MaintenancePlanStatus `json:"status,omitempty"`
+}
+
+type MaintenancePlanSpec struct {
+	// Nodes are the nodes to maintain, in this order
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
+
+	// NodeSelector selects more nodes to maintain after Nodes, in name order
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// MaxUnavailable is how many nodes can be in maintenance at the same time
+	// +optional
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	MaxUnavailable int `json:"maxUnavailable,omitempty"`
+
+	// Force shuts down the VMs which can't be migrated, like the force option of the maintenance mode
+	// +optional
+	Force bool `json:"force,omitempty"`
+
+	// Hook is a job to run on each node while it is in maintenance mode, e.g. a firmware update
+	// +optional
+	Hook *MaintenancePlanHook `json:"hook,omitempty"`
+
+	// Paused stops the plan from moving to the next node
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+type MaintenancePlanHook struct {
+	// Image is the image of the hook container. The container is privileged and runs on the node with host PID
+	// and host network, the root filesystem of the node is mounted at /host.
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// WaitForReboot waits for the node to reboot after the hook job succeeded, for hooks scheduling a reboot
+	// +optional
+	WaitForReboot bool `json:"waitForReboot,omitempty"`
+
+	// TimeoutSeconds fails the node when the hook, and the reboot, take longer
+	// +optional
+	// +kubebuilder:default:=3600
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+}
+
+type MaintenancePlanStatus struct {
+	// +optional
+	Phase MaintenancePlanPhase `json:"phase,omitempty"`
+
+	// Nodes is the progress of each node of the plan
+	// +optional
+	Nodes []MaintenancePlanNodeStatus `json:"nodes,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type MaintenancePlanNodeStatus struct {
+	Name string `json:"name"`
+
+	Phase MaintenancePlanNodePhase `json:"phase"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+
+	// PhaseStartTime is when the node got its current phase
+	// +optional
+	PhaseStartTime string `json:"phaseStartTime,omitempty"`
+
+	// +optional
+	EndTime string `json:"endTime,omitempty"`
+
+	// BootID is the boot ID of the node when the hook started, to detect its reboot
+	// +optional
+	BootID string `json:"bootID,omitempty"`
+}
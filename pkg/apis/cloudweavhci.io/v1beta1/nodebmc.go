@@ -0,0 +1,117 @@
+package v1beta1
+
+import (
+	"github.com/rancher/wrangler/v3/pkg/condition"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	NodeBMCReachable condition.Cond = "Reachable"
+)
+
+type NodeBMCProtocol string
+
+const (
+	NodeBMCProtocolRedfish NodeBMCProtocol = "redfish"
+	NodeBMCProtocolIPMI    NodeBMCProtocol = "ipmi"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=nodebmc;nodebmcs,scope=Cluster
+// +kubebuilder:printcolumn:name="PROTOCOL",type=string,JSONPath=`.spec.protocol`
+// +kubebuilder:printcolumn:name="ADDRESS",type=string,JSONPath=`.spec.address`
+// +kubebuilder:printcolumn:name="POWER",type=string,JSONPath=`.status.powerState`
+// +kubebuilder:printcolumn:name="HEALTH",type=string,JSONPath=`.status.health`
+// +kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NodeBMC is the baseboard management controller of a node, it is named after the node.
+type NodeBMC struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeBMCSpec   `json:"spec"`
+	Status NodeBMCStatus `json:"status,omitempty"`
+}
+
+type NodeBMCSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=redfish;ipmi
+	Protocol NodeBMCProtocol `json:"protocol"`
+
+	// Address is the URL of the Redfish service, e.g. https://10.0.0.5, or the host[:port] of the IPMI interface
+	// +kubebuilder:validation:Required
+	Address string `json:"address"`
+
+	// CredentialsSecret references the secret holding the username and password keys of the BMC
+	// +kubebuilder:validation:Required
+	CredentialsSecret corev1.SecretReference `json:"credentialsSecret"`
+
+	// InsecureSkipVerify skips the verification of the Redfish service certificate
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// SystemID is the Redfish computer system of the node, the first system of the service is used if empty
+	// +optional
+	SystemID string `json:"systemID,omitempty"`
+
+	// PollIntervalSeconds is the interval between two reads of the power state and sensors
+	// +optional
+	// +kubebuilder:default:=60
+	// +kubebuilder:validation:Minimum=10
+	PollIntervalSeconds int `json:"pollIntervalSeconds,omitempty"`
+}
+
+type NodeBMCStatus struct {
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// PowerState is the power state reported by the BMC, e.g. On or Off
+	// +optional
+	PowerState string `json:"powerState,omitempty"`
+
+	// Health is the overall health reported by the BMC, OK, Warning or Critical
+	// +optional
+	Health string `json:"health,omitempty"`
+
+	// +optional
+	Sensors []NodeBMCSensor `json:"sensors,omitempty"`
+
+	// +optional
+	LastPollTime string `json:"lastPollTime,omitempty"`
+
+	// LastOperation is the last operation requested to the BMC
+	// +optional
+	LastOperation *NodeBMCOperation `json:"lastOperation,omitempty"`
+
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+type NodeBMCSensor struct {
+	Name string `json:"name"`
+
+	// +optional
+	Reading string `json:"reading,omitempty"`
+
+	// +optional
+	Units string `json:"units,omitempty"`
+
+	// +optional
+	Health string `json:"health,omitempty"`
+}
+
+type NodeBMCOperation struct {
+	Operation string `json:"operation"`
+
+	// +optional
+	Time string `json:"time,omitempty"`
+
+	// +optional
+	Succeeded bool `json:"succeeded,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,99 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=nh;nhs,scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="LAST_UPDATE",type=string,JSONPath=`.status.lastUpdateTime`
+// +kubebuilder:printcolumn:name="AGE",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NodeHealth is the hardware health of the node with the same name, reported by the node agent. The node controller
+// turns it into node conditions, and evacuates the node when the node-health-policy setting allows it.
+type NodeHealth struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status NodeHealthStatus `json:"status,omitempty"`
+}
+
+type NodeHealthStatus struct {
+	// LastUpdateTime is when the node agent last reported the health
+	// +optional
+	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
+
+	// Disks are the SMART data of the disks
+	// +optional
+	Disks []DiskHealth `json:"disks,omitempty"`
+
+	// Memory are the EDAC error counters of the memory controllers
+	// +optional
+	Memory MemoryHealth `json:"memory,omitempty"`
+
+	// Links are the physical network interfaces
+	// +optional
+	Links []LinkHealth `json:"links,omitempty"`
+
+	// Temperatures are the hardware monitoring sensors
+	// +optional
+	Temperatures []TemperatureHealth `json:"temperatures,omitempty"`
+}
+
+type DiskHealth struct {
+	Name string `json:"name"`
+
+	// +optional
+	Model string `json:"model,omitempty"`
+
+	// +optional
+	Serial string `json:"serial,omitempty"`
+
+	// SMARTPassed is the overall SMART self-assessment
+	SMARTPassed bool `json:"smartPassed"`
+
+	// +optional
+	ReallocatedSectors int64 `json:"reallocatedSectors,omitempty"`
+
+	// +optional
+	PendingSectors int64 `json:"pendingSectors,omitempty"`
+
+	// MediaErrors are the unrecovered data integrity errors of a NVMe disk
+	// +optional
+	MediaErrors int64 `json:"mediaErrors,omitempty"`
+
+	// +optional
+	TemperatureCelsius int64 `json:"temperatureCelsius,omitempty"`
+}
+
+type MemoryHealth struct {
+	// +optional
+	CorrectableErrors int64 `json:"correctableErrors,omitempty"`
+
+	// +optional
+	UncorrectableErrors int64 `json:"uncorrectableErrors,omitempty"`
+}
+
+type LinkHealth struct {
+	Name string `json:"name"`
+
+	// +optional
+	OperState string `json:"operState,omitempty"`
+
+	// CarrierChanges is the number of link state changes since the interface was created
+	// +optional
+	CarrierChanges int64 `json:"carrierChanges,omitempty"`
+
+	// Flaps is the number of link state changes since the previous report
+	// +optional
+	Flaps int64 `json:"flaps,omitempty"`
+}
+
+type TemperatureHealth struct {
+	Name string `json:"name"`
+
+	Celsius int64 `json:"celsius"`
+}
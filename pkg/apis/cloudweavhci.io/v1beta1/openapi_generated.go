@@ -65,18 +65,43 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.AddonStatus":                                                      schema_pkg_apis_cloudweavhciio_v1beta1_AddonStatus(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Archive":                                                          schema_pkg_apis_cloudweavhciio_v1beta1_Archive(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.BackupTarget":                                                     schema_pkg_apis_cloudweavhciio_v1beta1_BackupTarget(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.CanaryProbe":                                                      schema_pkg_apis_cloudweavhciio_v1beta1_CanaryProbe(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition":                                                        schema_pkg_apis_cloudweavhciio_v1beta1_Condition(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.DiskHealth":                                                       schema_pkg_apis_cloudweavhciio_v1beta1_DiskHealth(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Error":                                                            schema_pkg_apis_cloudweavhciio_v1beta1_Error(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ErrorResponse":                                                    schema_pkg_apis_cloudweavhciio_v1beta1_ErrorResponse(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ImagePreloadStatus":                                               schema_pkg_apis_cloudweavhciio_v1beta1_ImagePreloadStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ImagePreloadThroughputSample":                                     schema_pkg_apis_cloudweavhciio_v1beta1_ImagePreloadThroughputSample(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.KeyGenInput":                                                      schema_pkg_apis_cloudweavhciio_v1beta1_KeyGenInput(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.KeyPair":                                                          schema_pkg_apis_cloudweavhciio_v1beta1_KeyPair(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.KeyPairList":                                                      schema_pkg_apis_cloudweavhciio_v1beta1_KeyPairList(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.KeyPairSpec":                                                      schema_pkg_apis_cloudweavhciio_v1beta1_KeyPairSpec(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.KeyPairStatus":                                                    schema_pkg_apis_cloudweavhciio_v1beta1_KeyPairStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.KeyPairVirtualMachineStatus":                                      schema_pkg_apis_cloudweavhciio_v1beta1_KeyPairVirtualMachineStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.LinkHealth":                                                       schema_pkg_apis_cloudweavhciio_v1beta1_LinkHealth(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlan":                                                  schema_pkg_apis_cloudweavhciio_v1beta1_MaintenancePlan(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlanHook":                                              schema_pkg_apis_cloudweavhciio_v1beta1_MaintenancePlanHook(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlanList":                                              schema_pkg_apis_cloudweavhciio_v1beta1_MaintenancePlanList(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlanNodeStatus":                                        schema_pkg_apis_cloudweavhciio_v1beta1_MaintenancePlanNodeStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlanSpec":                                              schema_pkg_apis_cloudweavhciio_v1beta1_MaintenancePlanSpec(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlanStatus":                                            schema_pkg_apis_cloudweavhciio_v1beta1_MaintenancePlanStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MemoryHealth":                                                     schema_pkg_apis_cloudweavhciio_v1beta1_MemoryHealth(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMC":                                                          schema_pkg_apis_cloudweavhciio_v1beta1_NodeBMC(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMCList":                                                      schema_pkg_apis_cloudweavhciio_v1beta1_NodeBMCList(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMCOperation":                                                 schema_pkg_apis_cloudweavhciio_v1beta1_NodeBMCOperation(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMCSensor":                                                    schema_pkg_apis_cloudweavhciio_v1beta1_NodeBMCSensor(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMCSpec":                                                      schema_pkg_apis_cloudweavhciio_v1beta1_NodeBMCSpec(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMCStatus":                                                    schema_pkg_apis_cloudweavhciio_v1beta1_NodeBMCStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeHealth":                                                       schema_pkg_apis_cloudweavhciio_v1beta1_NodeHealth(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeHealthList":                                                   schema_pkg_apis_cloudweavhciio_v1beta1_NodeHealthList(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeHealthStatus":                                                 schema_pkg_apis_cloudweavhciio_v1beta1_NodeHealthStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeImagePreloadStatus":                                           schema_pkg_apis_cloudweavhciio_v1beta1_NodeImagePreloadStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeUpgradeHistory":                                               schema_pkg_apis_cloudweavhciio_v1beta1_NodeUpgradeHistory(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeUpgradeStatus":                                                schema_pkg_apis_cloudweavhciio_v1beta1_NodeUpgradeStatus(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.PersistentVolumeClaimSourceSpec":                                  schema_pkg_apis_cloudweavhciio_v1beta1_PersistentVolumeClaimSourceSpec(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Preference":                                                       schema_pkg_apis_cloudweavhciio_v1beta1_Preference(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.PreferenceList":                                                   schema_pkg_apis_cloudweavhciio_v1beta1_PreferenceList(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ReadinessCheck":                                                   schema_pkg_apis_cloudweavhciio_v1beta1_ReadinessCheck(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuota":                                                    schema_pkg_apis_cloudweavhciio_v1beta1_ResourceQuota(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuotaList":                                                schema_pkg_apis_cloudweavhciio_v1beta1_ResourceQuotaList(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuotaSpec":                                                schema_pkg_apis_cloudweavhciio_v1beta1_ResourceQuotaSpec(ref),
@@ -95,15 +120,31 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundleList":                                                schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundleList(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundleSpec":                                                schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundleSpec(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundleStatus":                                              schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundleStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.TemperatureHealth":                                                schema_pkg_apis_cloudweavhciio_v1beta1_TemperatureHealth(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Upgrade":                                                          schema_pkg_apis_cloudweavhciio_v1beta1_Upgrade(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeCanary":                                                    schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeCanary(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeCanaryStatus":                                              schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeCanaryStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeHistory":                                                   schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeHistory(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeHistoryList":                                               schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeHistoryList(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeHistorySpec":                                               schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeHistorySpec(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeHistoryStatus":                                             schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeHistoryStatus(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeList":                                                      schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeList(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLog":                                                       schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLog(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLogList":                                                   schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLogList(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLogSpec":                                                   schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLogSpec(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLogStatus":                                                 schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLogStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeNodeBatch":                                                 schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeNodeBatch(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradePhaseHistory":                                              schema_pkg_apis_cloudweavhciio_v1beta1_UpgradePhaseHistory(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeReadiness":                                                 schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeReadiness(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeReadinessList":                                             schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeReadinessList(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeReadinessSpec":                                             schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeReadinessSpec(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeReadinessStatus":                                           schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeReadinessStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeRollbackStatus":                                            schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeRollbackStatus(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeSpec":                                                      schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeSpec(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeStatus":                                                    schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeWindow":                                                    schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeWindow(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VMBackupInfo":                                                     schema_pkg_apis_cloudweavhciio_v1beta1_VMBackupInfo(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VMMigrationHistory":                                               schema_pkg_apis_cloudweavhciio_v1beta1_VMMigrationHistory(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Version":                                                          schema_pkg_apis_cloudweavhciio_v1beta1_Version(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VersionList":                                                      schema_pkg_apis_cloudweavhciio_v1beta1_VersionList(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VersionSpec":                                                      schema_pkg_apis_cloudweavhciio_v1beta1_VersionSpec(ref),
@@ -113,19 +154,26 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineBackupStatus":                                       schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineBackupStatus(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImage":                                              schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImage(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageList":                                          schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImageList(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageNodeCache":                                     schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImageNodeCache(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageNodeCacheStatus":                               schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImageNodeCacheStatus(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageSecurityParameters":                            schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImageSecurityParameters(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageSpec":                                          schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImageSpec(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageStatus":                                        schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImageStatus(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageUsage":                                         schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImageUsage(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineRestore":                                            schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineRestore(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineRestoreList":                                        schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineRestoreList(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineRestoreSpec":                                        schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineRestoreSpec(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineRestoreStatus":                                      schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineRestoreStatus(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineSourceSpec":                                         schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineSourceSpec(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplate":                                           schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplate(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateConsumer":                                   schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateConsumer(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateList":                                       schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateList(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateParameter":                                  schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateParameter(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateSharing":                                    schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateSharing(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateSpec":                                       schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateSpec(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateStatus":                                     schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateStatus(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateVersion":                                    schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateVersion(ref),
+		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateVersionDeprecation":                         schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateVersionDeprecation(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateVersionList":                                schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateVersionList(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateVersionSpec":                                schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateVersionSpec(ref),
 		"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateVersionStatus":                              schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateVersionStatus(ref),
@@ -2281,6 +2329,62 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_BackupTarget(ref common.ReferenceCal
 	}
 }
 
+func schema_pkg_apis_cloudweavhciio_v1beta1_CanaryProbe(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CanaryProbe is a health probe of the canary node sampled during the soak period",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"passed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Passed is true if the latest sample passed and no sample failed after the probe converged",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is the message of the latest failed sample",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"converged": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Converged is true once the probe passed, the probes which wait for the volumes to be rebuilt and the sample VMs to be migrated may fail before then",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"failedSamples": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FailedSamples counts the samples which failed after the probe converged",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"lastFailedTime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"name", "passed"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_cloudweavhciio_v1beta1_Condition(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2338,6 +2442,71 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_Condition(ref common.ReferenceCallba
 	}
 }
 
+func schema_pkg_apis_cloudweavhciio_v1beta1_DiskHealth(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"model": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"serial": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"smartPassed": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SMARTPassed is the overall SMART self-assessment",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"reallocatedSectors": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"pendingSectors": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"mediaErrors": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MediaErrors are the unrecovered data integrity errors of a NVMe disk",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"temperatureCelsius": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+				},
+				Required: []string{"name", "smartPassed"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_cloudweavhciio_v1beta1_Error(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2391,6 +2560,115 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_ErrorResponse(ref common.ReferenceCa
 	}
 }
 
+func schema_pkg_apis_cloudweavhciio_v1beta1_ImagePreloadStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"strategy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Strategy is the preload strategy type of the upgrade config",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"concurrency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Concurrency is how many nodes preload images at the same time, the adaptive strategy scales it",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"maxConcurrency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxConcurrency is the highest concurrency the adaptive strategy scales up to",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"lastScaleTime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"lastDiskCheckTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastDiskCheckTime is when the adaptive strategy last checked the free disk space of the nodes",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"throughputSamples": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ThroughputSamples are the latest image pull throughputs sampled by the adaptive strategy",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ImagePreloadThroughputSample"),
+									},
+								},
+							},
+						},
+					},
+					"nodes": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeImagePreloadStatus"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"strategy", "concurrency"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ImagePreloadThroughputSample", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeImagePreloadStatus"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_ImagePreloadThroughputSample(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImagePreloadThroughputSample is the image pull throughput of all the preloading nodes between two disk checks",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"concurrency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Concurrency is how many nodes were preloading",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"bytesPerSecond": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BytesPerSecond is how fast the image filesystems of the preloading nodes grew in total",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+				Required: []string{"concurrency", "bytesPerSecond"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_cloudweavhciio_v1beta1_KeyGenInput(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2411,6 +2689,13 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_KeyGenInput(ref common.ReferenceCall
 							Format:  "",
 						},
 					},
+					"keyType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KeyType is the algorithm of the generated key, rsa or ed25519, defaults to rsa",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"name", "namespace"},
 			},
@@ -2542,8 +2827,37 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_KeyPairStatus(ref common.ReferenceCa
 				Properties: map[string]spec.Schema{
 					"fingerPrint": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "FingerPrint is the legacy MD5 fingerprint of the public key",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"sha256FingerPrint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SHA256FingerPrint is the SHA256 fingerprint of the public key, as printed by OpenSSH",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"keyType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KeyType is the algorithm of the public key, e.g. ssh-ed25519 or ssh-rsa",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"virtualMachines": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VirtualMachines reports the propagation of the public key to the VMs referencing the key pair",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.KeyPairVirtualMachineStatus"),
+									},
+								},
+							},
 						},
 					},
 					"conditions": {
@@ -2563,26 +2877,37 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_KeyPairStatus(ref common.ReferenceCa
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.KeyPairVirtualMachineStatus"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_NodeUpgradeStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_KeyPairVirtualMachineStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"state": {
+					"namespace": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Namespace is the namespace of the VM",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"reason": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Name is the name of the VM",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
 						},
 					},
 					"message": {
@@ -2592,42 +2917,58 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_NodeUpgradeStatus(ref common.Referen
 						},
 					},
 				},
+				Required: []string{"namespace", "name", "state"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_PersistentVolumeClaimSourceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_LinkHealth(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"metadata": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
 						},
 					},
-					"spec": {
+					"operState": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/api/core/v1.PersistentVolumeClaimSpec"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"k8s.io/api/core/v1.PersistentVolumeClaimSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+					"carrierChanges": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CarrierChanges is the number of link state changes since the interface was created",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"flaps": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Flaps is the number of link state changes since the previous report",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_Preference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_MaintenancePlan(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "MaintenancePlan puts a pool of nodes in maintenance mode one after the other. Each node is drained, an optional hook job runs on it, then the node exits maintenance mode and the plan waits for the Longhorn volumes to be healthy again before it moves to the next node. The plan stops on the first failure.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
 						SchemaProps: spec.SchemaProps{
@@ -2649,27 +2990,95 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_Preference(ref common.ReferenceCallb
 							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"value": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlanSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlanStatus"),
 						},
 					},
 				},
-				Required: []string{"value"},
+				Required: []string{"spec"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlanSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlanStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_PreferenceList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_MaintenancePlanHook(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "PreferenceList is a list of Preference resources",
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image is the image of the hook container. The container is privileged and runs on the node with host PID and host network, the root filesystem of the node is mounted at /host.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"command": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"args": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"waitForReboot": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WaitForReboot waits for the node to reboot after the hook job succeeded, for hooks scheduling a reboot",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"timeoutSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TimeoutSeconds fails the node when the hook, and the reboot, take longer",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+				Required: []string{"image"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_MaintenancePlanList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "MaintenancePlanList is a list of MaintenancePlan resources",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -2699,7 +3108,7 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_PreferenceList(ref common.ReferenceC
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Preference"),
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlan"),
 									},
 								},
 							},
@@ -2710,150 +3119,201 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_PreferenceList(ref common.ReferenceC
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Preference", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlan", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_ResourceQuota(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_MaintenancePlanNodeStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
 						},
 					},
-					"apiVersion": {
+					"phase": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
 						},
 					},
-					"metadata": {
+					"message": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"spec": {
+					"startTime": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuotaSpec"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"status": {
+					"phaseStartTime": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuotaStatus"),
+							Description: "PhaseStartTime is when the node got its current phase",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"endTime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"bootID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BootID is the boot ID of the node when the hook started, to detect its reboot",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
+				Required: []string{"name", "phase"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuotaSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuotaStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_ResourceQuotaList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_MaintenancePlanSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ResourceQuotaList is a list of ResourceQuota resources",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"nodes": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "Nodes are the nodes to maintain, in this order",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
-					"apiVersion": {
+					"nodeSelector": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
+							Description: "NodeSelector selects more nodes to maintain after Nodes, in name order",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"maxUnavailable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxUnavailable is how many nodes can be in maintenance at the same time",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"force": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Force shuts down the VMs which can't be migrated, like the force option of the maintenance mode",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"metadata": {
+					"hook": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Description: "Hook is a job to run on each node while it is in maintenance mode, e.g. a firmware update",
+							Ref:         ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlanHook"),
 						},
 					},
-					"items": {
+					"paused": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuota"),
-									},
-								},
-							},
+							Description: "Paused stops the plan from moving to the next node",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"metadata", "items"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuota", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlanHook", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_ResourceQuotaSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_MaintenancePlanStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"snapshotLimit": {
+					"phase": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SnapshotLimit"),
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"nodes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Nodes is the progress of each node of the plan",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlanNodeStatus"),
+									},
+								},
+							},
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SnapshotLimit"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MaintenancePlanNodeStatus"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_ResourceQuotaStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_MemoryHealth(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"snapshotLimitStatus": {
+					"correctableErrors": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SnapshotLimitStatus"),
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"uncorrectableErrors": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
 						},
 					},
 				},
 			},
 		},
-		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SnapshotLimitStatus"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_ScheduleVMBackup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_NodeBMC(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "NodeBMC is the baseboard management controller of a node, it is named after the node.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
 						SchemaProps: spec.SchemaProps{
@@ -2878,13 +3338,13 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_ScheduleVMBackup(ref common.Referenc
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ScheduleVMBackupSpec"),
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMCSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ScheduleVMBackupStatus"),
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMCStatus"),
 						},
 					},
 				},
@@ -2892,15 +3352,15 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_ScheduleVMBackup(ref common.Referenc
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ScheduleVMBackupSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ScheduleVMBackupStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMCSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMCStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_ScheduleVMBackupList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_NodeBMCList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ScheduleVMBackupList is a list of ScheduleVMBackup resources",
+				Description: "NodeBMCList is a list of NodeBMC resources",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -2930,7 +3390,7 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_ScheduleVMBackupList(ref common.Refe
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ScheduleVMBackup"),
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMC"),
 									},
 								},
 							},
@@ -2941,149 +3401,1609 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_ScheduleVMBackupList(ref common.Refe
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ScheduleVMBackup", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMC", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_ScheduleVMBackupSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_NodeBMCOperation(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"cron": {
+					"operation": {
 						SchemaProps: spec.SchemaProps{
 							Default: "",
 							Type:    []string{"string"},
 							Format:  "",
 						},
 					},
-					"retain": {
+					"time": {
 						SchemaProps: spec.SchemaProps{
-							Default: 0,
-							Type:    []string{"integer"},
-							Format:  "int32",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"maxFailure": {
+					"succeeded": {
 						SchemaProps: spec.SchemaProps{
-							Default: 0,
-							Type:    []string{"integer"},
-							Format:  "int32",
-						},
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"operation"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_NodeBMCSensor(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"reading": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"units": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"health": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_NodeBMCSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"protocol": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"address": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Address is the URL of the Redfish service, e.g. https://10.0.0.5, or the host[:port] of the IPMI interface",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"credentialsSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialsSecret references the secret holding the username and password keys of the BMC",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/api/core/v1.SecretReference"),
+						},
+					},
+					"insecureSkipVerify": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InsecureSkipVerify skips the verification of the Redfish service certificate",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"systemID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SystemID is the Redfish computer system of the node, the first system of the service is used if empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"pollIntervalSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PollIntervalSeconds is the interval between two reads of the power state and sensors",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"protocol", "address", "credentialsSecret"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.SecretReference"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_NodeBMCStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"powerState": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PowerState is the power state reported by the BMC, e.g. On or Off",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"health": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Health is the overall health reported by the BMC, OK, Warning or Critical",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"sensors": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMCSensor"),
+									},
+								},
+							},
+						},
+					},
+					"lastPollTime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"lastOperation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastOperation is the last operation requested to the BMC",
+							Ref:         ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMCOperation"),
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMCOperation", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeBMCSensor"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_NodeHealth(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeHealth is the hardware health of the node with the same name, reported by the node agent. The node controller turns it into node conditions, and evacuates the node when the node-health-policy setting allows it.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeHealthStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeHealthStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_NodeHealthList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NodeHealthList is a list of NodeHealth resources",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeHealth"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeHealth", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_NodeHealthStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"lastUpdateTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastUpdateTime is when the node agent last reported the health",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"disks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Disks are the SMART data of the disks",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.DiskHealth"),
+									},
+								},
+							},
+						},
+					},
+					"memory": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Memory are the EDAC error counters of the memory controllers",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MemoryHealth"),
+						},
+					},
+					"links": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Links are the physical network interfaces",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.LinkHealth"),
+									},
+								},
+							},
+						},
+					},
+					"temperatures": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Temperatures are the hardware monitoring sensors",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.TemperatureHealth"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.DiskHealth", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.LinkHealth", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.MemoryHealth", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.TemperatureHealth"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_NodeImagePreloadStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "State is Pending, Preloading, Paused, Preloaded or Failed",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"endTime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"concurrency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Concurrency is the concurrency when the node started preloading",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"availableDiskBytes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AvailableDiskBytes is the free disk space of the node last seen by the adaptive strategy",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"imageFsUsedBytes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImageFsUsedBytes is the used space of the image filesystem last sampled while the node is preloading",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"pausedTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PausedTime is when the adaptive strategy paused the node, the upgrade fails if it stays paused too long",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"state"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_NodeUpgradeHistory(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"preloadStartTime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"preloadEndTime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"upgradeStartTime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"endTime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_NodeUpgradeStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_PersistentVolumeClaimSourceSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/api/core/v1.PersistentVolumeClaimSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.PersistentVolumeClaimSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_Preference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+				},
+				Required: []string{"value"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_PreferenceList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PreferenceList is a list of Preference resources",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Preference"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Preference", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_ReadinessCheck(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"result": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"remediation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Remediation tells how to fix a warning or a failure",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"overridden": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Overridden is true if the check is overridden, its failure doesn't block the upgrade",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "result"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_ResourceQuota(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuotaSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuotaStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuotaSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuotaStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_ResourceQuotaList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ResourceQuotaList is a list of ResourceQuota resources",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuota"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ResourceQuota", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_ResourceQuotaSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"snapshotLimit": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SnapshotLimit"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SnapshotLimit"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_ResourceQuotaStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"snapshotLimitStatus": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SnapshotLimitStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SnapshotLimitStatus"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_ScheduleVMBackup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ScheduleVMBackupSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ScheduleVMBackupStatus"),
+						},
+					},
+				},
+				Required: []string{"spec"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ScheduleVMBackupSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ScheduleVMBackupStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_ScheduleVMBackupList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ScheduleVMBackupList is a list of ScheduleVMBackup resources",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ScheduleVMBackup"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ScheduleVMBackup", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_ScheduleVMBackupSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"cron": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"retain": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int32",
+						},
+					},
+					"maxFailure": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int32",
+						},
+					},
+					"suspend": {
+						SchemaProps: spec.SchemaProps{
+							Default: false,
+							Type:    []string{"boolean"},
+							Format:  "",
+						},
+					},
+					"vmbackup": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineBackupSpec"),
+						},
+					},
+				},
+				Required: []string{"cron", "retain", "maxFailure", "vmbackup"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineBackupSpec"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_ScheduleVMBackupStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"vmbackupInfo": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VMBackupInfo"),
+									},
+								},
+							},
+						},
+					},
+					"failure": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"suspended": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VMBackupInfo"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_SecretBackup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SecretBackup contains the secret data need to restore a secret referenced by the VM",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"data": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "byte",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_Setting(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"value": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"default": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"customized": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"source": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SettingStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SettingStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_SettingList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SettingList is a list of Setting resources",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Setting"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Setting", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_SettingStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"),
+									},
+								},
+							},
+						},
 					},
-					"suspend": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_SnapshotLimit(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespaceTotalSnapshotSizeQuota": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"vmTotalSnapshotSizeQuota": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: 0,
+										Type:    []string{"integer"},
+										Format:  "int64",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_SnapshotLimitStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespaceTotalSnapshotSizeUsage": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"vmTotalSnapshotSizeUsage": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: 0,
+										Type:    []string{"integer"},
+										Format:  "int64",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundle(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundleSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundleStatus"),
+						},
+					},
+				},
+				Required: []string{"spec"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundleSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundleStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundleList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SupportBundleList is a list of SupportBundle resources",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundle"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundle", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundleSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"issueURL": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+				},
+				Required: []string{"description"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundleStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"progress": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"filename": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"filesize": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_TemperatureHealth(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Default: false,
-							Type:    []string{"boolean"},
+							Default: "",
+							Type:    []string{"string"},
 							Format:  "",
 						},
 					},
-					"vmbackup": {
+					"celsius": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineBackupSpec"),
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int64",
 						},
 					},
 				},
-				Required: []string{"cron", "retain", "maxFailure", "vmbackup"},
+				Required: []string{"name", "celsius"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineBackupSpec"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_ScheduleVMBackupStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_Upgrade(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"vmbackupInfo": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VMBackupInfo"),
-									},
-								},
-							},
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"failure": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"integer"},
-							Format: "int32",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"suspended": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"boolean"},
-							Format: "",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"conditions": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeStatus"),
+						},
+					},
+				},
+				Required: []string{"spec"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeCanary(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"node": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Node is the node upgraded first, it must be a control plane node since Rancher upgrades the control plane nodes before the worker nodes",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"soakSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SoakSeconds is how long the health probes run after the node is upgraded, 30 minutes if it's not set",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"sampleVMs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SampleVMs are the VMs in the format of namespace/name migrated to the node after it's upgraded. The VMs must be running on the node and their guest agents must answer for the probes to pass.",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"),
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
 									},
 								},
 							},
 						},
 					},
 				},
+				Required: []string{"node"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VMBackupInfo"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_SecretBackup(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeCanaryStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "SecretBackup contains the secret data need to restore a secret referenced by the VM",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "State is Soaking while the health probes run, then Passed or Failed",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"soakStartTime": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
-					"data": {
+					"lastProbeTime": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"probes": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Type:   []string{"string"},
-										Format: "byte",
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.CanaryProbe"),
 									},
 								},
 							},
 						},
 					},
 				},
+				Required: []string{"state"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.CanaryProbe"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_Setting(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeHistory(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "UpgradeHistory records the timings and the outcome of an upgrade. It isn't owned by the upgrade, so it's kept after the upgrade is deleted. The latest 20 completed histories are kept.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
 						SchemaProps: spec.SchemaProps{
@@ -3105,49 +5025,32 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_Setting(ref common.ReferenceCallback
 							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"value": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"default": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"customized": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"boolean"},
-							Format: "",
-						},
-					},
-					"source": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeHistorySpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SettingStatus"),
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeHistoryStatus"),
 						},
 					},
 				},
+				Required: []string{"spec"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SettingStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeHistorySpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeHistoryStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_SettingList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeHistoryList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "SettingList is a list of Setting resources",
+				Description: "UpgradeHistoryList is a list of UpgradeHistory resources",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -3177,7 +5080,7 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_SettingList(ref common.ReferenceCall
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Setting"),
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeHistory"),
 									},
 								},
 							},
@@ -3188,24 +5091,109 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_SettingList(ref common.ReferenceCall
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Setting", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeHistory", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_SettingStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeHistorySpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"conditions": {
+					"upgradeName": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"previousVersion": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"upgradeName"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeHistoryStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"endTime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"outcome": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Outcome is Succeeded or Failed after the upgrade completes, or Deleted if the upgrade is deleted before it completes",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"phases": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phases are the phases of the upgrade in the order they started",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradePhaseHistory"),
+									},
+								},
+							},
+						},
+					},
+					"nodes": {
 						SchemaProps: spec.SchemaProps{
 							Type: []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"),
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeUpgradeHistory"),
+									},
+								},
+							},
+						},
+					},
+					"vmMigrations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VMMigrations counts the VM migrations during the upgrade, they're counted after the upgrade completes",
+							Ref:         ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VMMigrationHistory"),
+						},
+					},
+					"warnings": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
 									},
 								},
 							},
@@ -3215,77 +5203,60 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_SettingStatus(ref common.ReferenceCa
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeUpgradeHistory", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradePhaseHistory", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VMMigrationHistory"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_SnapshotLimit(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "UpgradeList is a list of Upgrade resources",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"namespaceTotalSnapshotSizeQuota": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"integer"},
-							Format: "int64",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"vmTotalSnapshotSizeQuota": {
-						SchemaProps: spec.SchemaProps{
-							Type: []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: 0,
-										Type:    []string{"integer"},
-										Format:  "int64",
-									},
-								},
-							},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-				},
-			},
-		},
-	}
-}
-
-func schema_pkg_apis_cloudweavhciio_v1beta1_SnapshotLimitStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"namespaceTotalSnapshotSizeUsage": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"integer"},
-							Format: "int64",
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
 						},
 					},
-					"vmTotalSnapshotSizeUsage": {
+					"items": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: 0,
-										Type:    []string{"integer"},
-										Format:  "int64",
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Upgrade"),
 									},
 								},
 							},
 						},
 					},
 				},
+				Required: []string{"metadata", "items"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Upgrade", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundle(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLog(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
@@ -3314,13 +5285,13 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundle(ref common.ReferenceCa
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundleSpec"),
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLogSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundleStatus"),
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLogStatus"),
 						},
 					},
 				},
@@ -3328,15 +5299,15 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundle(ref common.ReferenceCa
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundleSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundleStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLogSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLogStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundleList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLogList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "SupportBundleList is a list of SupportBundle resources",
+				Description: "UpgradeLogList is a list of UpgradeLog resources",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -3366,7 +5337,7 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundleList(ref common.Referen
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundle"),
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLog"),
 									},
 								},
 							},
@@ -3377,65 +5348,46 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundleList(ref common.Referen
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.SupportBundle", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLog", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundleSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLogSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"issueURL": {
-						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
-						},
-					},
-					"description": {
+					"upgradeName": {
 						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
 				},
-				Required: []string{"description"},
 			},
 		},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundleStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLogStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"state": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"progress": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"integer"},
-							Format: "int32",
-						},
-					},
-					"filename": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"filesize": {
+					"archives": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"integer"},
-							Format: "int64",
+							Type: []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Archive"),
+									},
+								},
+							},
 						},
 					},
 					"conditions": {
@@ -3455,111 +5407,88 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_SupportBundleStatus(ref common.Refer
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Archive", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_Upgrade(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeNodeBatch(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"apiVersion": {
-						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
-						},
-					},
-					"metadata": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
-						},
-					},
-					"spec": {
+					"nodes": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeSpec"),
-						},
-					},
-					"status": {
-						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeStatus"),
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
 						},
 					},
 				},
-				Required: []string{"spec"},
+				Required: []string{"nodes"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradePhaseHistory(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "UpgradeList is a list of Upgrade resources",
-				Type:        []string{"object"},
+				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"kind": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
-							Type:        []string{"string"},
-							Format:      "",
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
 						},
 					},
-					"apiVersion": {
+					"startTime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
-							Type:        []string{"string"},
-							Format:      "",
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"metadata": {
+					"endTime": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
-					"items": {
+					"result": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Upgrade"),
-									},
-								},
-							},
+							Description: "Result is the status of the condition of the phase after it ends",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
 						},
 					},
 				},
-				Required: []string{"metadata", "items"},
+				Required: []string{"name"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Upgrade", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLog(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeReadiness(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "UpgradeReadiness runs the pre-flight checks of an upgrade without starting it. The upgrade webhook runs the same checks and rejects an upgrade with failed checks, unless they are overridden in the upgrade annotation.",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
 						SchemaProps: spec.SchemaProps{
@@ -3584,29 +5513,28 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLog(ref common.ReferenceCallb
 					"spec": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLogSpec"),
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeReadinessSpec"),
 						},
 					},
 					"status": {
 						SchemaProps: spec.SchemaProps{
 							Default: map[string]interface{}{},
-							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLogStatus"),
+							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeReadinessStatus"),
 						},
 					},
 				},
-				Required: []string{"spec"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLogSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLogStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeReadinessSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeReadinessStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLogList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeReadinessList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "UpgradeLogList is a list of UpgradeLog resources",
+				Description: "UpgradeReadinessList is a list of UpgradeReadiness resources",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"kind": {
@@ -3636,7 +5564,7 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLogList(ref common.ReferenceC
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLog"),
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeReadiness"),
 									},
 								},
 							},
@@ -3647,66 +5575,160 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLogList(ref common.ReferenceC
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeLog", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeReadiness", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLogSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeReadinessSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"upgradeName": {
+					"version": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Version is the name of the version to upgrade to, the version checks are skipped if it's empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"overrides": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Overrides are the names of the checks whose failures don't block the upgrade",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeReadinessStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"ready": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ready is true if no check fails, except the overridden ones",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"lastCheckTime": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"string"},
 							Format: "",
 						},
 					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int64",
+						},
+					},
+					"checks": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ReadinessCheck"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ReadinessCheck"},
 	}
 }
 
-func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeLogStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeRollbackStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
-					"archives": {
+					"state": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"object"},
-							AdditionalProperties: &spec.SchemaOrBool{
-								Allows: true,
+							Description: "State is RollingBack until the restored system services are ready, then RolledBack if everything is rolled back, or ManualActionRequired",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"time": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"rolledBack": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RolledBack lists what is rolled back",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Archive"),
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
 									},
 								},
 							},
 						},
 					},
-					"conditions": {
+					"skippedNodes": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "SkippedNodes are the nodes which haven't started upgrading",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"),
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"manualActions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ManualActions list what can't be rolled back and needs to be fixed manually",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
 									},
 								},
 							},
 						},
 					},
 				},
+				Required: []string{"state"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Archive", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"},
 	}
 }
 
@@ -3737,9 +5759,66 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeSpec(ref common.ReferenceCall
 							Format:  "",
 						},
 					},
+					"paused": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Paused holds the nodes which haven't started upgrading, the nodes being upgraded aren't interrupted",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"pauseAfterEachNode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PauseAfterEachNode pauses the upgrade after each upgraded node until it's resumed",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"windows": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Windows are the time windows in which nodes can start upgrading, nodes can start upgrading at any time if it's empty",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeWindow"),
+									},
+								},
+							},
+						},
+					},
+					"nodeBatches": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeBatches are the nodes upgraded batch by batch. The nodes of a batch don't start upgrading until the nodes of the previous batches are upgraded, the nodes which aren't in any batch are upgraded after all batches.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeNodeBatch"),
+									},
+								},
+							},
+						},
+					},
+					"rollbackPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RollbackPolicy tells whether a failed upgrade rolls back the phases which are safe to roll back. The system services are restored if they fail to upgrade, the nodes which haven't started upgrading are skipped.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"canary": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Canary upgrades a node first, the other nodes don't start upgrading until the health probes of the node pass for the soak period",
+							Ref:         ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeCanary"),
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeCanary", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeNodeBatch", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeWindow"},
 	}
 }
 
@@ -3806,11 +5885,85 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeStatus(ref common.ReferenceCa
 							Format: "",
 						},
 					},
+					"rollbackSnapshot": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RollbackSnapshot is the config map saving the managed charts before the system services are upgraded",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"rollback": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeRollbackStatus"),
+						},
+					},
+					"canary": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeCanaryStatus"),
+						},
+					},
+					"imagePreload": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ImagePreload is the progress of the image preloading on the nodes",
+							Ref:         ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ImagePreloadStatus"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeUpgradeStatus"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.ImagePreloadStatus", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.NodeUpgradeStatus", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeCanaryStatus", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.UpgradeRollbackStatus"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_UpgradeWindow(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"days": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Days are the days of the week the window opens, like Sat or Sun. The window opens every day if it's empty.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"start": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Start is the time the window opens, in the format of 15:04",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"end": {
+						SchemaProps: spec.SchemaProps{
+							Description: "End is the time the window closes, in the format of 15:04. The window closes on the next day if it's not after Start.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"timeZone": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TimeZone is the IANA time zone of the window, UTC if it's empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"start", "end"},
+			},
+		},
 	}
 }
 
@@ -3858,6 +6011,33 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_VMBackupInfo(ref common.ReferenceCal
 	}
 }
 
+func schema_pkg_apis_cloudweavhciio_v1beta1_VMMigrationHistory(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"succeeded": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int32",
+						},
+					},
+					"failed": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int32",
+						},
+					},
+				},
+				Required: []string{"succeeded", "failed"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_cloudweavhciio_v1beta1_Version(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3956,9 +6136,17 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_VersionSpec(ref common.ReferenceCall
 				Properties: map[string]spec.Schema{
 					"isoURL": {
 						SchemaProps: spec.SchemaProps{
-							Default: "",
-							Type:    []string{"string"},
-							Format:  "",
+							Description: "ISOURL is where the ISO is downloaded from, it's not needed if the ISO is uploaded as an image",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"isoImage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ISOImage is the uploaded image of the ISO in the namespace/name format, upgrades to the version use the image instead of downloading the ISO",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 					"isoChecksum": {
@@ -3996,7 +6184,6 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_VersionSpec(ref common.ReferenceCall
 						},
 					},
 				},
-				Required: []string{"isoURL"},
 			},
 		},
 	}
@@ -4320,11 +6507,87 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImageList(ref common.R
 						},
 					},
 				},
-				Required: []string{"metadata", "items"},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImage", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImageNodeCache(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VirtualMachineImageNodeCache places copies of the image on nodes ahead of VM creation, so that volumes created from the image do not wait for the image to be transferred.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"nodeSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeSelector selects the nodes that should keep a local copy of the image.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+				},
+				Required: []string{"nodeSelector"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImageNodeCacheStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VirtualMachineImageNodeCacheStatus is the state of the image copy on a node selected by the node cache.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"nodeName": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"diskUUID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DiskUUID is the Longhorn disk holding the copy on the node.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ready": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ready is true when the copy on the node can be used by new volumes.",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"progress": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"integer"},
+							Format: "int32",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"nodeName", "ready"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImage", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
@@ -4444,12 +6707,17 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImageSpec(ref common.R
 							Ref: ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageSecurityParameters"),
 						},
 					},
+					"nodeCache": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageNodeCache"),
+						},
+					},
 				},
 				Required: []string{"displayName", "sourceType"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageSecurityParameters"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageNodeCache", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageSecurityParameters"},
 	}
 }
 
@@ -4520,11 +6788,115 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImageStatus(ref common
 							},
 						},
 					},
+					"usage": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageUsage"),
+						},
+					},
+					"nodeCache": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageNodeCacheStatus"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.BackupTarget", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.BackupTarget", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.Condition", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageNodeCacheStatus", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineImageUsage"},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineImageUsage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "VirtualMachineImageUsage lists the resources that reference an image.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"pvcs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PVCs provisioned from the image storage class, in namespace/name form.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"virtualMachines": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VirtualMachines that use the image through a volume or a volume claim template, in namespace/name form.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"templateVersions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TemplateVersions whose volume claim templates reference the image, in namespace/name form.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"pvcCount": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int32",
+						},
+					},
+					"virtualMachineCount": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int32",
+						},
+					},
+					"templateVersionCount": {
+						SchemaProps: spec.SchemaProps{
+							Default: 0,
+							Type:    []string{"integer"},
+							Format:  "int32",
+						},
+					},
+					"lastUsedTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastUsedTime is the last time the image was seen with at least one reference.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -4829,6 +7201,40 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplate(ref common.Re
 	}
 }
 
+func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateConsumer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"virtualMachines": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"namespace"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -4878,6 +7284,89 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateList(ref commo
 	}
 }
 
+func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateParameter(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"description": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"default": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"pattern": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Pattern is a regular expression the whole value must match",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"required": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateSharing(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"clusterWide": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
+					"namespaces": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -4897,9 +7386,17 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateSpec(ref commo
 							Format: "",
 						},
 					},
+					"sharedWith": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SharedWith publishes the template and its images read-only to other namespaces",
+							Ref:         ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateSharing"),
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateSharing"},
 	}
 }
 
@@ -4921,9 +7418,25 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateStatus(ref com
 							Format: "int32",
 						},
 					},
+					"consumers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Consumers lists the VMs created from the template versions, grouped by namespace",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateConsumer"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateConsumer"},
 	}
 }
 
@@ -4974,6 +7487,32 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateVersion(ref co
 	}
 }
 
+func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateVersionDeprecation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Default: "",
+							Type:    []string{"string"},
+							Format:  "",
+						},
+					},
+					"deprecatedTime": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+				Required: []string{"reason"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateVersionList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -5068,12 +7607,32 @@ func schema_pkg_apis_cloudweavhciio_v1beta1_VirtualMachineTemplateVersionSpec(re
 							Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineSourceSpec"),
 						},
 					},
+					"parameters": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Parameters declares the inputs of the template version, which are referenced by placeholders in the string fields of the VM spec and in the cloud-init secrets.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateParameter"),
+									},
+								},
+							},
+						},
+					},
+					"deprecation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Deprecation marks the template version as deprecated, VMs created from it are reported by the template drift report.",
+							Ref:         ref("github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateVersionDeprecation"),
+						},
+					},
 				},
 				Required: []string{"templateId"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineSourceSpec"},
+			"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineSourceSpec", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateParameter", "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1.VirtualMachineTemplateVersionDeprecation"},
 	}
 }
 
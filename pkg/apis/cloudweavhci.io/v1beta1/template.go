@@ -35,6 +35,18 @@ type VirtualMachineTemplateSpec struct {
 
 	// +optional
 	Description string `json:"description,omitempty"`
+
+	// SharedWith publishes the template and its images read-only to other namespaces
+	// +optional
+	SharedWith *VirtualMachineTemplateSharing `json:"sharedWith,omitempty"`
+}
+
+type VirtualMachineTemplateSharing struct {
+	// +optional
+	ClusterWide bool `json:"clusterWide,omitempty"`
+
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
 }
 
 type VirtualMachineTemplateStatus struct {
@@ -43,6 +55,17 @@ type VirtualMachineTemplateStatus struct {
 
 	// +optional
 	LatestVersion int `json:"latestVersion,omitempty"`
+
+	// Consumers lists the VMs created from the template versions, grouped by namespace
+	// +optional
+	Consumers []VirtualMachineTemplateConsumer `json:"consumers,omitempty"`
+}
+
+type VirtualMachineTemplateConsumer struct {
+	Namespace string `json:"namespace"`
+
+	// +optional
+	VirtualMachines []string `json:"virtualMachines,omitempty"`
 }
 
 // +genclient
@@ -51,6 +74,7 @@ type VirtualMachineTemplateStatus struct {
 // +kubebuilder:printcolumn:name="TEMPLATE_ID",type=string,JSONPath=`.spec.templatedId`
 // +kubebuilder:printcolumn:name="DESCRIPTION",type=string,priority=10,JSONPath=`.spec.description`
 // +kubebuilder:printcolumn:name="VERSION",type=integer,JSONPath=`.status.version`
+// +kubebuilder:printcolumn:name="DEPRECATED",type=string,priority=10,JSONPath=`.spec.deprecation.reason`
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=`.metadata.creationTimestamp`
 
 type VirtualMachineTemplateVersion struct {
@@ -76,6 +100,56 @@ type VirtualMachineTemplateVersionSpec struct {
 
 	// +optional
 	VM VirtualMachineSourceSpec `json:"vm,omitempty"`
+
+	// Parameters declares the inputs of the template version, which are referenced by placeholders
+	// in the string fields of the VM spec and in the cloud-init secrets.
+	// +optional
+	Parameters []VirtualMachineTemplateParameter `json:"parameters,omitempty"`
+
+	// Deprecation marks the template version as deprecated, VMs created from it are reported
+	// by the template drift report.
+	// +optional
+	Deprecation *VirtualMachineTemplateVersionDeprecation `json:"deprecation,omitempty"`
+}
+
+type VirtualMachineTemplateVersionDeprecation struct {
+	// +kubebuilder:validation:Required
+	Reason string `json:"reason"`
+
+	// +optional
+	DeprecatedTime string `json:"deprecatedTime,omitempty"`
+}
+
+type TemplateParameterType string
+
+const (
+	TemplateParameterTypeString  TemplateParameterType = "string"
+	TemplateParameterTypeInteger TemplateParameterType = "integer"
+	TemplateParameterTypeBoolean TemplateParameterType = "boolean"
+)
+
+type VirtualMachineTemplateParameter struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// +optional
+	// +kubebuilder:default:="string"
+	// +kubebuilder:validation:Enum=string;integer;boolean
+	Type TemplateParameterType `json:"type,omitempty"`
+
+	// +optional
+	Default string `json:"default,omitempty"`
+
+	// Pattern is a regular expression the whole value must match
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+
+	// +optional
+	Required bool `json:"required,omitempty"`
 }
 
 type VirtualMachineSourceSpec struct {
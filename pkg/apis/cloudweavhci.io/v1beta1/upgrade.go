@@ -19,6 +19,33 @@ var (
 	NodesUpgraded condition.Cond = "NodesUpgraded"
 	// SystemServicesUpgraded is true when Cloudweav chart is upgraded
 	SystemServicesUpgraded condition.Cond = "SystemServicesUpgraded"
+	// CanaryFailed is true when the health probes of the canary node fail, the upgrade is paused
+	CanaryFailed condition.Cond = "CanaryFailed"
+	// ImagePreloadPaused is true while the adaptive image preload strategy pauses nodes short of disk space
+	ImagePreloadPaused condition.Cond = "ImagePreloadPaused"
+)
+
+type RollbackPolicy string
+
+const (
+	// RollbackPolicyNone leaves a failed upgrade as it is
+	RollbackPolicyNone RollbackPolicy = "None"
+	// RollbackPolicyAuto rolls back the phases of a failed upgrade which are safe to roll back
+	RollbackPolicyAuto RollbackPolicy = "Auto"
+
+	RollbackStateRollingBack          = "RollingBack"
+	RollbackStateRolledBack           = "RolledBack"
+	RollbackStateManualActionRequired = "ManualActionRequired"
+
+	CanaryStateSoaking = "Soaking"
+	CanaryStatePassed  = "Passed"
+	CanaryStateFailed  = "Failed"
+
+	ImagePreloadStatePending    = "Pending"
+	ImagePreloadStatePreloading = "Preloading"
+	ImagePreloadStatePaused     = "Paused"
+	ImagePreloadStatePreloaded  = "Preloaded"
+	ImagePreloadStateFailed     = "Failed"
 )
 
 // +genclient
@@ -43,6 +70,70 @@ type UpgradeSpec struct {
 	// +optional
 	// +kubebuilder:default:=true
 	LogEnabled bool `json:"logEnabled" default:"true"`
+
+	// Paused holds the nodes which haven't started upgrading, the nodes being upgraded aren't interrupted
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// PauseAfterEachNode pauses the upgrade after each upgraded node until it's resumed
+	// +optional
+	PauseAfterEachNode bool `json:"pauseAfterEachNode,omitempty"`
+
+	// Windows are the time windows in which nodes can start upgrading, nodes can start upgrading at any time if it's empty
+	// +optional
+	Windows []UpgradeWindow `json:"windows,omitempty"`
+
+	// NodeBatches are the nodes upgraded batch by batch. The nodes of a batch don't start upgrading until the nodes of
+	// the previous batches are upgraded, the nodes which aren't in any batch are upgraded after all batches.
+	// +optional
+	NodeBatches []UpgradeNodeBatch `json:"nodeBatches,omitempty"`
+
+	// RollbackPolicy tells whether a failed upgrade rolls back the phases which are safe to roll back. The system
+	// services are restored if they fail to upgrade, the nodes which haven't started upgrading are skipped.
+	// +optional
+	// +kubebuilder:validation:Enum:=None;Auto
+	RollbackPolicy RollbackPolicy `json:"rollbackPolicy,omitempty"`
+
+	// Canary upgrades a node first, the other nodes don't start upgrading until the health probes of the node pass
+	// for the soak period
+	// +optional
+	Canary *UpgradeCanary `json:"canary,omitempty"`
+}
+
+type UpgradeWindow struct {
+	// Days are the days of the week the window opens, like Sat or Sun. The window opens every day if it's empty.
+	// +optional
+	Days []string `json:"days,omitempty"`
+
+	// Start is the time the window opens, in the format of 15:04
+	Start string `json:"start"`
+
+	// End is the time the window closes, in the format of 15:04. The window closes on the next day if it's not after Start.
+	End string `json:"end"`
+
+	// TimeZone is the IANA time zone of the window, UTC if it's empty
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+type UpgradeNodeBatch struct {
+	Nodes []string `json:"nodes"`
+}
+
+type UpgradeCanary struct {
+	// Node is the node upgraded first, it must be a control plane node since Rancher upgrades the control plane nodes
+	// before the worker nodes
+	Node string `json:"node"`
+
+	// SoakSeconds is how long the health probes run after the node is upgraded, 30 minutes if it's not set
+	// +optional
+	// +kubebuilder:validation:Minimum:=0
+	SoakSeconds int64 `json:"soakSeconds,omitempty"`
+
+	// SampleVMs are the VMs in the format of namespace/name migrated to the node after it's upgraded. The VMs must be
+	// running on the node and their guest agents must answer for the probes to pass.
+	// +optional
+	SampleVMs []string `json:"sampleVMs,omitempty"`
 }
 
 type UpgradeStatus struct {
@@ -60,6 +151,114 @@ type UpgradeStatus struct {
 	Conditions []Condition `json:"conditions,omitempty"`
 	// +optional
 	UpgradeLog string `json:"upgradeLog,omitempty"`
+	// RollbackSnapshot is the config map saving the managed charts before the system services are upgraded
+	// +optional
+	RollbackSnapshot string `json:"rollbackSnapshot,omitempty"`
+	// +optional
+	Rollback *UpgradeRollbackStatus `json:"rollback,omitempty"`
+	// +optional
+	Canary *UpgradeCanaryStatus `json:"canary,omitempty"`
+	// ImagePreload is the progress of the image preloading on the nodes
+	// +optional
+	ImagePreload *ImagePreloadStatus `json:"imagePreload,omitempty"`
+}
+
+type ImagePreloadStatus struct {
+	// Strategy is the preload strategy type of the upgrade config
+	Strategy string `json:"strategy"`
+	// Concurrency is how many nodes preload images at the same time, the adaptive strategy scales it
+	Concurrency int `json:"concurrency"`
+	// MaxConcurrency is the highest concurrency the adaptive strategy scales up to
+	// +optional
+	MaxConcurrency int `json:"maxConcurrency,omitempty"`
+	// +optional
+	LastScaleTime string `json:"lastScaleTime,omitempty"`
+	// LastDiskCheckTime is when the adaptive strategy last checked the free disk space of the nodes
+	// +optional
+	LastDiskCheckTime string `json:"lastDiskCheckTime,omitempty"`
+	// ThroughputSamples are the latest image pull throughputs sampled by the adaptive strategy
+	// +optional
+	ThroughputSamples []ImagePreloadThroughputSample `json:"throughputSamples,omitempty"`
+	// +optional
+	Nodes map[string]NodeImagePreloadStatus `json:"nodes,omitempty"`
+}
+
+type NodeImagePreloadStatus struct {
+	// State is Pending, Preloading, Paused, Preloaded or Failed
+	State string `json:"state"`
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+	// +optional
+	EndTime string `json:"endTime,omitempty"`
+	// Concurrency is the concurrency when the node started preloading
+	// +optional
+	Concurrency int `json:"concurrency,omitempty"`
+	// AvailableDiskBytes is the free disk space of the node last seen by the adaptive strategy
+	// +optional
+	AvailableDiskBytes int64 `json:"availableDiskBytes,omitempty"`
+	// ImageFsUsedBytes is the used space of the image filesystem last sampled while the node is preloading
+	// +optional
+	ImageFsUsedBytes int64 `json:"imageFsUsedBytes,omitempty"`
+	// PausedTime is when the adaptive strategy paused the node, the upgrade fails if it stays paused too long
+	// +optional
+	PausedTime string `json:"pausedTime,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ImagePreloadThroughputSample is the image pull throughput of all the preloading nodes between two disk checks
+type ImagePreloadThroughputSample struct {
+	// Concurrency is how many nodes were preloading
+	Concurrency int `json:"concurrency"`
+	// BytesPerSecond is how fast the image filesystems of the preloading nodes grew in total
+	BytesPerSecond int64 `json:"bytesPerSecond"`
+}
+
+type UpgradeCanaryStatus struct {
+	// State is Soaking while the health probes run, then Passed or Failed
+	State string `json:"state"`
+	// +optional
+	SoakStartTime string `json:"soakStartTime,omitempty"`
+	// +optional
+	LastProbeTime string `json:"lastProbeTime,omitempty"`
+	// +optional
+	Probes []CanaryProbe `json:"probes,omitempty"`
+}
+
+// CanaryProbe is a health probe of the canary node sampled during the soak period
+type CanaryProbe struct {
+	Name string `json:"name"`
+	// Passed is true if the latest sample passed and no sample failed after the probe converged
+	Passed bool `json:"passed"`
+	// Message is the message of the latest failed sample
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Converged is true once the probe passed, the probes which wait for the volumes to be rebuilt and the sample VMs
+	// to be migrated may fail before then
+	// +optional
+	Converged bool `json:"converged,omitempty"`
+	// FailedSamples counts the samples which failed after the probe converged
+	// +optional
+	FailedSamples int `json:"failedSamples,omitempty"`
+	// +optional
+	LastFailedTime string `json:"lastFailedTime,omitempty"`
+}
+
+type UpgradeRollbackStatus struct {
+	// State is RollingBack until the restored system services are ready, then RolledBack if everything is rolled
+	// back, or ManualActionRequired
+	State string `json:"state"`
+	// +optional
+	Time string `json:"time,omitempty"`
+	// RolledBack lists what is rolled back
+	// +optional
+	RolledBack []string `json:"rolledBack,omitempty"`
+	// SkippedNodes are the nodes which haven't started upgrading
+	// +optional
+	SkippedNodes []string `json:"skippedNodes,omitempty"`
+	// ManualActions list what can't be rolled back and needs to be fixed manually
+	// +optional
+	ManualActions []string `json:"manualActions,omitempty"`
 }
 
 type NodeUpgradeStatus struct {
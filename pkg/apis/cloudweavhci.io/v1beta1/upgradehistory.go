@@ -0,0 +1,107 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=uh;uhs,scope=Namespaced
+// +kubebuilder:printcolumn:name="UPGRADE",type=string,JSONPath=`.spec.upgradeName`
+// +kubebuilder:printcolumn:name="FROM",type=string,JSONPath=`.spec.previousVersion`
+// +kubebuilder:printcolumn:name="TO",type=string,JSONPath=`.spec.version`
+// +kubebuilder:printcolumn:name="OUTCOME",type=string,JSONPath=`.status.outcome`
+// +kubebuilder:printcolumn:name="START",type=string,JSONPath=`.status.startTime`
+// +kubebuilder:printcolumn:name="END",type=string,JSONPath=`.status.endTime`
+
+// UpgradeHistory records the timings and the outcome of an upgrade. It isn't owned by the upgrade, so it's kept
+// after the upgrade is deleted. The latest 20 completed histories are kept.
+type UpgradeHistory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UpgradeHistorySpec   `json:"spec"`
+	Status UpgradeHistoryStatus `json:"status,omitempty"`
+}
+
+type UpgradeHistorySpec struct {
+	// +kubebuilder:validation:Required
+	UpgradeName string `json:"upgradeName"`
+
+	// +optional
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+type UpgradeHistoryStatus struct {
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+
+	// +optional
+	EndTime string `json:"endTime,omitempty"`
+
+	// Outcome is Succeeded or Failed after the upgrade completes, or Deleted if the upgrade is deleted before it
+	// completes
+	// +optional
+	Outcome string `json:"outcome,omitempty"`
+
+	// Phases are the phases of the upgrade in the order they started
+	// +optional
+	Phases []UpgradePhaseHistory `json:"phases,omitempty"`
+
+	// +optional
+	Nodes []NodeUpgradeHistory `json:"nodes,omitempty"`
+
+	// VMMigrations counts the VM migrations during the upgrade, they're counted after the upgrade completes
+	// +optional
+	VMMigrations *VMMigrationHistory `json:"vmMigrations,omitempty"`
+
+	// +optional
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type UpgradePhaseHistory struct {
+	Name string `json:"name"`
+
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+
+	// +optional
+	EndTime string `json:"endTime,omitempty"`
+
+	// Result is the status of the condition of the phase after it ends
+	// +optional
+	Result string `json:"result,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type NodeUpgradeHistory struct {
+	Name string `json:"name"`
+
+	// +optional
+	PreloadStartTime string `json:"preloadStartTime,omitempty"`
+
+	// +optional
+	PreloadEndTime string `json:"preloadEndTime,omitempty"`
+
+	// +optional
+	UpgradeStartTime string `json:"upgradeStartTime,omitempty"`
+
+	// +optional
+	EndTime string `json:"endTime,omitempty"`
+
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type VMMigrationHistory struct {
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
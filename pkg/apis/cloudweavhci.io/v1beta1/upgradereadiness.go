@@ -0,0 +1,74 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ReadinessResult string
+
+const (
+	ReadinessPass ReadinessResult = "pass"
+	ReadinessWarn ReadinessResult = "warn"
+	ReadinessFail ReadinessResult = "fail"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=ur;urs,scope=Namespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="VERSION",type=string,JSONPath=`.spec.version`
+// +kubebuilder:printcolumn:name="READY",type=boolean,JSONPath=`.status.ready`
+// +kubebuilder:printcolumn:name="LAST_CHECK",type=string,JSONPath=`.status.lastCheckTime`
+
+// UpgradeReadiness runs the pre-flight checks of an upgrade without starting it. The upgrade webhook runs the same
+// checks and rejects an upgrade with failed checks, unless they are overridden in the upgrade annotation.
+type UpgradeReadiness struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UpgradeReadinessSpec   `json:"spec,omitempty"`
+	Status UpgradeReadinessStatus `json:"status,omitempty"`
+}
+
+type UpgradeReadinessSpec struct {
+	// Version is the name of the version to upgrade to, the version checks are skipped if it's empty
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Overrides are the names of the checks whose failures don't block the upgrade
+	// +optional
+	Overrides []string `json:"overrides,omitempty"`
+}
+
+type UpgradeReadinessStatus struct {
+	// Ready is true if no check fails, except the overridden ones
+	// +optional
+	Ready bool `json:"ready"`
+
+	// +optional
+	LastCheckTime string `json:"lastCheckTime,omitempty"`
+
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +optional
+	Checks []ReadinessCheck `json:"checks,omitempty"`
+}
+
+type ReadinessCheck struct {
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Enum:=pass;warn;fail
+	Result ReadinessResult `json:"result"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Remediation tells how to fix a warning or a failure
+	// +optional
+	Remediation string `json:"remediation,omitempty"`
+
+	// Overridden is true if the check is overridden, its failure doesn't block the upgrade
+	// +optional
+	Overridden bool `json:"overridden,omitempty"`
+}
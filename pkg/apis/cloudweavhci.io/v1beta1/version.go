@@ -8,6 +8,7 @@ import (
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:resource:scope=Namespaced
 // +kubebuilder:printcolumn:name="ISO-URL",type=string,JSONPath=`.spec.isoURL`
+// +kubebuilder:printcolumn:name="ISO-Image",type=string,JSONPath=`.spec.isoImage`,priority=1
 // +kubebuilder:printcolumn:name="ReleaseDate",type=string,JSONPath=`.spec.releaseDate`
 // +kubebuilder:printcolumn:name="MinUpgradableVersion",type="string",JSONPath=`.spec.minUpgradableVersion`
 
@@ -19,9 +20,15 @@ type Version struct {
 }
 
 type VersionSpec struct {
-	// +kubebuilder:validation:Required
+	// ISOURL is where the ISO is downloaded from, it's not needed if the ISO is uploaded as an image
+	// +optional
 	ISOURL string `json:"isoURL"`
 
+	// ISOImage is the uploaded image of the ISO in the namespace/name format, upgrades to the version use the image
+	// instead of downloading the ISO
+	// +optional
+	ISOImage string `json:"isoImage,omitempty"`
+
 	// +optional
 	ISOChecksum string `json:"isoChecksum"`
 
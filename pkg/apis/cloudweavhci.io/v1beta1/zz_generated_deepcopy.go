@@ -22,6 +22,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	types "k8s.io/apimachinery/pkg/types"
 )
@@ -156,6 +157,22 @@ func (in *BackupTarget) DeepCopy() *BackupTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryProbe) DeepCopyInto(out *CanaryProbe) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryProbe.
+func (in *CanaryProbe) DeepCopy() *CanaryProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -172,6 +189,22 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskHealth) DeepCopyInto(out *DiskHealth) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskHealth.
+func (in *DiskHealth) DeepCopy() *DiskHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Error) DeepCopyInto(out *Error) {
 	*out = *in
@@ -218,6 +251,50 @@ func (in *ErrorResponse) DeepCopy() *ErrorResponse {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePreloadStatus) DeepCopyInto(out *ImagePreloadStatus) {
+	*out = *in
+	if in.ThroughputSamples != nil {
+		in, out := &in.ThroughputSamples, &out.ThroughputSamples
+		*out = make([]ImagePreloadThroughputSample, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make(map[string]NodeImagePreloadStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePreloadStatus.
+func (in *ImagePreloadStatus) DeepCopy() *ImagePreloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePreloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePreloadThroughputSample) DeepCopyInto(out *ImagePreloadThroughputSample) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePreloadThroughputSample.
+func (in *ImagePreloadThroughputSample) DeepCopy() *ImagePreloadThroughputSample {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePreloadThroughputSample)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeyGenInput) DeepCopyInto(out *KeyGenInput) {
 	*out = *in
@@ -314,6 +391,11 @@ func (in *KeyPairSpec) DeepCopy() *KeyPairSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeyPairStatus) DeepCopyInto(out *KeyPairStatus) {
 	*out = *in
+	if in.VirtualMachines != nil {
+		in, out := &in.VirtualMachines, &out.VirtualMachines
+		*out = make([]KeyPairVirtualMachineStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]Condition, len(*in))
@@ -333,59 +415,59 @@ func (in *KeyPairStatus) DeepCopy() *KeyPairStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeUpgradeStatus) DeepCopyInto(out *NodeUpgradeStatus) {
+func (in *KeyPairVirtualMachineStatus) DeepCopyInto(out *KeyPairVirtualMachineStatus) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgradeStatus.
-func (in *NodeUpgradeStatus) DeepCopy() *NodeUpgradeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyPairVirtualMachineStatus.
+func (in *KeyPairVirtualMachineStatus) DeepCopy() *KeyPairVirtualMachineStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeUpgradeStatus)
+	out := new(KeyPairVirtualMachineStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PersistentVolumeClaimSourceSpec) DeepCopyInto(out *PersistentVolumeClaimSourceSpec) {
+func (in *LinkHealth) DeepCopyInto(out *LinkHealth) {
 	*out = *in
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimSourceSpec.
-func (in *PersistentVolumeClaimSourceSpec) DeepCopy() *PersistentVolumeClaimSourceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkHealth.
+func (in *LinkHealth) DeepCopy() *LinkHealth {
 	if in == nil {
 		return nil
 	}
-	out := new(PersistentVolumeClaimSourceSpec)
+	out := new(LinkHealth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Preference) DeepCopyInto(out *Preference) {
+func (in *MaintenancePlan) DeepCopyInto(out *MaintenancePlan) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Preference.
-func (in *Preference) DeepCopy() *Preference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenancePlan.
+func (in *MaintenancePlan) DeepCopy() *MaintenancePlan {
 	if in == nil {
 		return nil
 	}
-	out := new(Preference)
+	out := new(MaintenancePlan)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Preference) DeepCopyObject() runtime.Object {
+func (in *MaintenancePlan) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -393,13 +475,39 @@ func (in *Preference) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PreferenceList) DeepCopyInto(out *PreferenceList) {
+func (in *MaintenancePlanHook) DeepCopyInto(out *MaintenancePlanHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenancePlanHook.
+func (in *MaintenancePlanHook) DeepCopy() *MaintenancePlanHook {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenancePlanHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenancePlanList) DeepCopyInto(out *MaintenancePlanList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Preference, len(*in))
+		*out = make([]MaintenancePlan, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -407,18 +515,18 @@ func (in *PreferenceList) DeepCopyInto(out *PreferenceList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreferenceList.
-func (in *PreferenceList) DeepCopy() *PreferenceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenancePlanList.
+func (in *MaintenancePlanList) DeepCopy() *MaintenancePlanList {
 	if in == nil {
 		return nil
 	}
-	out := new(PreferenceList)
+	out := new(MaintenancePlanList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PreferenceList) DeepCopyObject() runtime.Object {
+func (in *MaintenancePlanList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -426,122 +534,111 @@ func (in *PreferenceList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceQuota) DeepCopyInto(out *ResourceQuota) {
+func (in *MaintenancePlanNodeStatus) DeepCopyInto(out *MaintenancePlanNodeStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuota.
-func (in *ResourceQuota) DeepCopy() *ResourceQuota {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenancePlanNodeStatus.
+func (in *MaintenancePlanNodeStatus) DeepCopy() *MaintenancePlanNodeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceQuota)
+	out := new(MaintenancePlanNodeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ResourceQuota) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceQuotaList) DeepCopyInto(out *ResourceQuotaList) {
+func (in *MaintenancePlanSpec) DeepCopyInto(out *MaintenancePlanSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ResourceQuota, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hook != nil {
+		in, out := &in.Hook, &out.Hook
+		*out = new(MaintenancePlanHook)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaList.
-func (in *ResourceQuotaList) DeepCopy() *ResourceQuotaList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenancePlanSpec.
+func (in *MaintenancePlanSpec) DeepCopy() *MaintenancePlanSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceQuotaList)
+	out := new(MaintenancePlanSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ResourceQuotaList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceQuotaSpec) DeepCopyInto(out *ResourceQuotaSpec) {
+func (in *MaintenancePlanStatus) DeepCopyInto(out *MaintenancePlanStatus) {
 	*out = *in
-	in.SnapshotLimit.DeepCopyInto(&out.SnapshotLimit)
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]MaintenancePlanNodeStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaSpec.
-func (in *ResourceQuotaSpec) DeepCopy() *ResourceQuotaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenancePlanStatus.
+func (in *MaintenancePlanStatus) DeepCopy() *MaintenancePlanStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceQuotaSpec)
+	out := new(MaintenancePlanStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceQuotaStatus) DeepCopyInto(out *ResourceQuotaStatus) {
+func (in *MemoryHealth) DeepCopyInto(out *MemoryHealth) {
 	*out = *in
-	in.SnapshotLimitStatus.DeepCopyInto(&out.SnapshotLimitStatus)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaStatus.
-func (in *ResourceQuotaStatus) DeepCopy() *ResourceQuotaStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryHealth.
+func (in *MemoryHealth) DeepCopy() *MemoryHealth {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceQuotaStatus)
+	out := new(MemoryHealth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduleVMBackup) DeepCopyInto(out *ScheduleVMBackup) {
+func (in *NodeBMC) DeepCopyInto(out *NodeBMC) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Spec = in.Spec
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleVMBackup.
-func (in *ScheduleVMBackup) DeepCopy() *ScheduleVMBackup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeBMC.
+func (in *NodeBMC) DeepCopy() *NodeBMC {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduleVMBackup)
+	out := new(NodeBMC)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScheduleVMBackup) DeepCopyObject() runtime.Object {
+func (in *NodeBMC) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -549,13 +646,13 @@ func (in *ScheduleVMBackup) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduleVMBackupList) DeepCopyInto(out *ScheduleVMBackupList) {
+func (in *NodeBMCList) DeepCopyInto(out *NodeBMCList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ScheduleVMBackup, len(*in))
+		*out = make([]NodeBMC, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -563,18 +660,18 @@ func (in *ScheduleVMBackupList) DeepCopyInto(out *ScheduleVMBackupList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleVMBackupList.
-func (in *ScheduleVMBackupList) DeepCopy() *ScheduleVMBackupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeBMCList.
+func (in *NodeBMCList) DeepCopy() *NodeBMCList {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduleVMBackupList)
+	out := new(NodeBMCList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScheduleVMBackupList) DeepCopyObject() runtime.Object {
+func (in *NodeBMCList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -582,83 +679,87 @@ func (in *ScheduleVMBackupList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduleVMBackupSpec) DeepCopyInto(out *ScheduleVMBackupSpec) {
+func (in *NodeBMCOperation) DeepCopyInto(out *NodeBMCOperation) {
 	*out = *in
-	in.VMBackupSpec.DeepCopyInto(&out.VMBackupSpec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleVMBackupSpec.
-func (in *ScheduleVMBackupSpec) DeepCopy() *ScheduleVMBackupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeBMCOperation.
+func (in *NodeBMCOperation) DeepCopy() *NodeBMCOperation {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduleVMBackupSpec)
+	out := new(NodeBMCOperation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScheduleVMBackupStatus) DeepCopyInto(out *ScheduleVMBackupStatus) {
+func (in *NodeBMCSensor) DeepCopyInto(out *NodeBMCSensor) {
 	*out = *in
-	if in.VMBackupInfo != nil {
-		in, out := &in.VMBackupInfo, &out.VMBackupInfo
-		*out = make([]VMBackupInfo, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
-		copy(*out, *in)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeBMCSensor.
+func (in *NodeBMCSensor) DeepCopy() *NodeBMCSensor {
+	if in == nil {
+		return nil
 	}
+	out := new(NodeBMCSensor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeBMCSpec) DeepCopyInto(out *NodeBMCSpec) {
+	*out = *in
+	out.CredentialsSecret = in.CredentialsSecret
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleVMBackupStatus.
-func (in *ScheduleVMBackupStatus) DeepCopy() *ScheduleVMBackupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeBMCSpec.
+func (in *NodeBMCSpec) DeepCopy() *NodeBMCSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ScheduleVMBackupStatus)
+	out := new(NodeBMCSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretBackup) DeepCopyInto(out *SecretBackup) {
+func (in *NodeBMCStatus) DeepCopyInto(out *NodeBMCStatus) {
 	*out = *in
-	if in.Data != nil {
-		in, out := &in.Data, &out.Data
-		*out = make(map[string][]byte, len(*in))
-		for key, val := range *in {
-			var outVal []byte
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]byte, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
+	if in.Sensors != nil {
+		in, out := &in.Sensors, &out.Sensors
+		*out = make([]NodeBMCSensor, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastOperation != nil {
+		in, out := &in.LastOperation, &out.LastOperation
+		*out = new(NodeBMCOperation)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretBackup.
-func (in *SecretBackup) DeepCopy() *SecretBackup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeBMCStatus.
+func (in *NodeBMCStatus) DeepCopy() *NodeBMCStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretBackup)
+	out := new(NodeBMCStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Setting) DeepCopyInto(out *Setting) {
+func (in *NodeHealth) DeepCopyInto(out *NodeHealth) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -666,18 +767,18 @@ func (in *Setting) DeepCopyInto(out *Setting) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Setting.
-func (in *Setting) DeepCopy() *Setting {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeHealth.
+func (in *NodeHealth) DeepCopy() *NodeHealth {
 	if in == nil {
 		return nil
 	}
-	out := new(Setting)
+	out := new(NodeHealth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Setting) DeepCopyObject() runtime.Object {
+func (in *NodeHealth) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -685,13 +786,13 @@ func (in *Setting) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SettingList) DeepCopyInto(out *SettingList) {
+func (in *NodeHealthList) DeepCopyInto(out *NodeHealthList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Setting, len(*in))
+		*out = make([]NodeHealth, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -699,18 +800,18 @@ func (in *SettingList) DeepCopyInto(out *SettingList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SettingList.
-func (in *SettingList) DeepCopy() *SettingList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeHealthList.
+func (in *NodeHealthList) DeepCopy() *NodeHealthList {
 	if in == nil {
 		return nil
 	}
-	out := new(SettingList)
+	out := new(NodeHealthList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SettingList) DeepCopyObject() runtime.Object {
+func (in *NodeHealthList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -718,53 +819,518 @@ func (in *SettingList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SettingStatus) DeepCopyInto(out *SettingStatus) {
+func (in *NodeHealthStatus) DeepCopyInto(out *NodeHealthStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DiskHealth, len(*in))
+		copy(*out, *in)
+	}
+	out.Memory = in.Memory
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = make([]LinkHealth, len(*in))
+		copy(*out, *in)
+	}
+	if in.Temperatures != nil {
+		in, out := &in.Temperatures, &out.Temperatures
+		*out = make([]TemperatureHealth, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SettingStatus.
-func (in *SettingStatus) DeepCopy() *SettingStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeHealthStatus.
+func (in *NodeHealthStatus) DeepCopy() *NodeHealthStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SettingStatus)
+	out := new(NodeHealthStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SnapshotLimit) DeepCopyInto(out *SnapshotLimit) {
+func (in *NodeImagePreloadStatus) DeepCopyInto(out *NodeImagePreloadStatus) {
 	*out = *in
-	if in.VMTotalSnapshotSizeQuota != nil {
-		in, out := &in.VMTotalSnapshotSizeQuota, &out.VMTotalSnapshotSizeQuota
-		*out = make(map[string]int64, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotLimit.
-func (in *SnapshotLimit) DeepCopy() *SnapshotLimit {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeImagePreloadStatus.
+func (in *NodeImagePreloadStatus) DeepCopy() *NodeImagePreloadStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SnapshotLimit)
+	out := new(NodeImagePreloadStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SnapshotLimitStatus) DeepCopyInto(out *SnapshotLimitStatus) {
+func (in *NodeUpgradeHistory) DeepCopyInto(out *NodeUpgradeHistory) {
 	*out = *in
-	if in.VMTotalSnapshotSizeUsage != nil {
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgradeHistory.
+func (in *NodeUpgradeHistory) DeepCopy() *NodeUpgradeHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeUpgradeHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeUpgradeStatus) DeepCopyInto(out *NodeUpgradeStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgradeStatus.
+func (in *NodeUpgradeStatus) DeepCopy() *NodeUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentVolumeClaimSourceSpec) DeepCopyInto(out *PersistentVolumeClaimSourceSpec) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimSourceSpec.
+func (in *PersistentVolumeClaimSourceSpec) DeepCopy() *PersistentVolumeClaimSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumeClaimSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Preference) DeepCopyInto(out *Preference) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Preference.
+func (in *Preference) DeepCopy() *Preference {
+	if in == nil {
+		return nil
+	}
+	out := new(Preference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Preference) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreferenceList) DeepCopyInto(out *PreferenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Preference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreferenceList.
+func (in *PreferenceList) DeepCopy() *PreferenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(PreferenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PreferenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessCheck) DeepCopyInto(out *ReadinessCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessCheck.
+func (in *ReadinessCheck) DeepCopy() *ReadinessCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuota) DeepCopyInto(out *ResourceQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuota.
+func (in *ResourceQuota) DeepCopy() *ResourceQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ResourceQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuotaList) DeepCopyInto(out *ResourceQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ResourceQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaList.
+func (in *ResourceQuotaList) DeepCopy() *ResourceQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ResourceQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuotaSpec) DeepCopyInto(out *ResourceQuotaSpec) {
+	*out = *in
+	in.SnapshotLimit.DeepCopyInto(&out.SnapshotLimit)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaSpec.
+func (in *ResourceQuotaSpec) DeepCopy() *ResourceQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuotaStatus) DeepCopyInto(out *ResourceQuotaStatus) {
+	*out = *in
+	in.SnapshotLimitStatus.DeepCopyInto(&out.SnapshotLimitStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaStatus.
+func (in *ResourceQuotaStatus) DeepCopy() *ResourceQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleVMBackup) DeepCopyInto(out *ScheduleVMBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleVMBackup.
+func (in *ScheduleVMBackup) DeepCopy() *ScheduleVMBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleVMBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduleVMBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleVMBackupList) DeepCopyInto(out *ScheduleVMBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ScheduleVMBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleVMBackupList.
+func (in *ScheduleVMBackupList) DeepCopy() *ScheduleVMBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleVMBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduleVMBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleVMBackupSpec) DeepCopyInto(out *ScheduleVMBackupSpec) {
+	*out = *in
+	in.VMBackupSpec.DeepCopyInto(&out.VMBackupSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleVMBackupSpec.
+func (in *ScheduleVMBackupSpec) DeepCopy() *ScheduleVMBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleVMBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleVMBackupStatus) DeepCopyInto(out *ScheduleVMBackupStatus) {
+	*out = *in
+	if in.VMBackupInfo != nil {
+		in, out := &in.VMBackupInfo, &out.VMBackupInfo
+		*out = make([]VMBackupInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleVMBackupStatus.
+func (in *ScheduleVMBackupStatus) DeepCopy() *ScheduleVMBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleVMBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretBackup) DeepCopyInto(out *SecretBackup) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string][]byte, len(*in))
+		for key, val := range *in {
+			var outVal []byte
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]byte, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretBackup.
+func (in *SecretBackup) DeepCopy() *SecretBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Setting) DeepCopyInto(out *Setting) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Setting.
+func (in *Setting) DeepCopy() *Setting {
+	if in == nil {
+		return nil
+	}
+	out := new(Setting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Setting) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SettingList) DeepCopyInto(out *SettingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Setting, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SettingList.
+func (in *SettingList) DeepCopy() *SettingList {
+	if in == nil {
+		return nil
+	}
+	out := new(SettingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SettingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SettingStatus) DeepCopyInto(out *SettingStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SettingStatus.
+func (in *SettingStatus) DeepCopy() *SettingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SettingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotLimit) DeepCopyInto(out *SnapshotLimit) {
+	*out = *in
+	if in.VMTotalSnapshotSizeQuota != nil {
+		in, out := &in.VMTotalSnapshotSizeQuota, &out.VMTotalSnapshotSizeQuota
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotLimit.
+func (in *SnapshotLimit) DeepCopy() *SnapshotLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotLimitStatus) DeepCopyInto(out *SnapshotLimitStatus) {
+	*out = *in
+	if in.VMTotalSnapshotSizeUsage != nil {
 		in, out := &in.VMTotalSnapshotSizeUsage, &out.VMTotalSnapshotSizeUsage
 		*out = make(map[string]int64, len(*in))
 		for key, val := range *in {
@@ -774,18 +1340,348 @@ func (in *SnapshotLimitStatus) DeepCopyInto(out *SnapshotLimitStatus) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotLimitStatus.
-func (in *SnapshotLimitStatus) DeepCopy() *SnapshotLimitStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotLimitStatus.
+func (in *SnapshotLimitStatus) DeepCopy() *SnapshotLimitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotLimitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundle) DeepCopyInto(out *SupportBundle) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundle.
+func (in *SupportBundle) DeepCopy() *SupportBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SupportBundle) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundleList) DeepCopyInto(out *SupportBundleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SupportBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleList.
+func (in *SupportBundleList) DeepCopy() *SupportBundleList {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportBundleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SupportBundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundleSpec) DeepCopyInto(out *SupportBundleSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleSpec.
+func (in *SupportBundleSpec) DeepCopy() *SupportBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundleStatus) DeepCopyInto(out *SupportBundleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleStatus.
+func (in *SupportBundleStatus) DeepCopy() *SupportBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemperatureHealth) DeepCopyInto(out *TemperatureHealth) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemperatureHealth.
+func (in *TemperatureHealth) DeepCopy() *TemperatureHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(TemperatureHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Upgrade) DeepCopyInto(out *Upgrade) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Upgrade.
+func (in *Upgrade) DeepCopy() *Upgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(Upgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Upgrade) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeCanary) DeepCopyInto(out *UpgradeCanary) {
+	*out = *in
+	if in.SampleVMs != nil {
+		in, out := &in.SampleVMs, &out.SampleVMs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeCanary.
+func (in *UpgradeCanary) DeepCopy() *UpgradeCanary {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeCanary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeCanaryStatus) DeepCopyInto(out *UpgradeCanaryStatus) {
+	*out = *in
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]CanaryProbe, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeCanaryStatus.
+func (in *UpgradeCanaryStatus) DeepCopy() *UpgradeCanaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeCanaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeHistory) DeepCopyInto(out *UpgradeHistory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeHistory.
+func (in *UpgradeHistory) DeepCopy() *UpgradeHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UpgradeHistory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeHistoryList) DeepCopyInto(out *UpgradeHistoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]UpgradeHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeHistoryList.
+func (in *UpgradeHistoryList) DeepCopy() *UpgradeHistoryList {
 	if in == nil {
 		return nil
 	}
-	out := new(SnapshotLimitStatus)
+	out := new(UpgradeHistoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UpgradeHistoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeHistorySpec) DeepCopyInto(out *UpgradeHistorySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeHistorySpec.
+func (in *UpgradeHistorySpec) DeepCopy() *UpgradeHistorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeHistorySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SupportBundle) DeepCopyInto(out *SupportBundle) {
+func (in *UpgradeHistoryStatus) DeepCopyInto(out *UpgradeHistoryStatus) {
+	*out = *in
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]UpgradePhaseHistory, len(*in))
+		copy(*out, *in)
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeUpgradeHistory, len(*in))
+		copy(*out, *in)
+	}
+	if in.VMMigrations != nil {
+		in, out := &in.VMMigrations, &out.VMMigrations
+		*out = new(VMMigrationHistory)
+		**out = **in
+	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeHistoryStatus.
+func (in *UpgradeHistoryStatus) DeepCopy() *UpgradeHistoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeHistoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeList) DeepCopyInto(out *UpgradeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Upgrade, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeList.
+func (in *UpgradeList) DeepCopy() *UpgradeList {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UpgradeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeLog) DeepCopyInto(out *UpgradeLog) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -794,18 +1690,18 @@ func (in *SupportBundle) DeepCopyInto(out *SupportBundle) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundle.
-func (in *SupportBundle) DeepCopy() *SupportBundle {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeLog.
+func (in *UpgradeLog) DeepCopy() *UpgradeLog {
 	if in == nil {
 		return nil
 	}
-	out := new(SupportBundle)
+	out := new(UpgradeLog)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SupportBundle) DeepCopyObject() runtime.Object {
+func (in *UpgradeLog) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -813,13 +1709,13 @@ func (in *SupportBundle) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SupportBundleList) DeepCopyInto(out *SupportBundleList) {
+func (in *UpgradeLogList) DeepCopyInto(out *UpgradeLogList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]SupportBundle, len(*in))
+		*out = make([]UpgradeLog, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -827,18 +1723,18 @@ func (in *SupportBundleList) DeepCopyInto(out *SupportBundleList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleList.
-func (in *SupportBundleList) DeepCopy() *SupportBundleList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeLogList.
+func (in *UpgradeLogList) DeepCopy() *UpgradeLogList {
 	if in == nil {
 		return nil
 	}
-	out := new(SupportBundleList)
+	out := new(UpgradeLogList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SupportBundleList) DeepCopyObject() runtime.Object {
+func (in *UpgradeLogList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -846,24 +1742,31 @@ func (in *SupportBundleList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SupportBundleSpec) DeepCopyInto(out *SupportBundleSpec) {
+func (in *UpgradeLogSpec) DeepCopyInto(out *UpgradeLogSpec) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleSpec.
-func (in *SupportBundleSpec) DeepCopy() *SupportBundleSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeLogSpec.
+func (in *UpgradeLogSpec) DeepCopy() *UpgradeLogSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SupportBundleSpec)
+	out := new(UpgradeLogSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SupportBundleStatus) DeepCopyInto(out *SupportBundleStatus) {
+func (in *UpgradeLogStatus) DeepCopyInto(out *UpgradeLogStatus) {
 	*out = *in
+	if in.Archives != nil {
+		in, out := &in.Archives, &out.Archives
+		*out = make(map[string]Archive, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]Condition, len(*in))
@@ -872,99 +1775,75 @@ func (in *SupportBundleStatus) DeepCopyInto(out *SupportBundleStatus) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleStatus.
-func (in *SupportBundleStatus) DeepCopy() *SupportBundleStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeLogStatus.
+func (in *UpgradeLogStatus) DeepCopy() *UpgradeLogStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SupportBundleStatus)
+	out := new(UpgradeLogStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Upgrade) DeepCopyInto(out *Upgrade) {
+func (in *UpgradeNodeBatch) DeepCopyInto(out *UpgradeNodeBatch) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Upgrade.
-func (in *Upgrade) DeepCopy() *Upgrade {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeNodeBatch.
+func (in *UpgradeNodeBatch) DeepCopy() *UpgradeNodeBatch {
 	if in == nil {
 		return nil
 	}
-	out := new(Upgrade)
+	out := new(UpgradeNodeBatch)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Upgrade) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *UpgradeList) DeepCopyInto(out *UpgradeList) {
+func (in *UpgradePhaseHistory) DeepCopyInto(out *UpgradePhaseHistory) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Upgrade, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeList.
-func (in *UpgradeList) DeepCopy() *UpgradeList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePhaseHistory.
+func (in *UpgradePhaseHistory) DeepCopy() *UpgradePhaseHistory {
 	if in == nil {
 		return nil
 	}
-	out := new(UpgradeList)
+	out := new(UpgradePhaseHistory)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *UpgradeList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *UpgradeLog) DeepCopyInto(out *UpgradeLog) {
+func (in *UpgradeReadiness) DeepCopyInto(out *UpgradeReadiness) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeLog.
-func (in *UpgradeLog) DeepCopy() *UpgradeLog {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeReadiness.
+func (in *UpgradeReadiness) DeepCopy() *UpgradeReadiness {
 	if in == nil {
 		return nil
 	}
-	out := new(UpgradeLog)
+	out := new(UpgradeReadiness)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *UpgradeLog) DeepCopyObject() runtime.Object {
+func (in *UpgradeReadiness) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -972,13 +1851,13 @@ func (in *UpgradeLog) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *UpgradeLogList) DeepCopyInto(out *UpgradeLogList) {
+func (in *UpgradeReadinessList) DeepCopyInto(out *UpgradeReadinessList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]UpgradeLog, len(*in))
+		*out = make([]UpgradeReadiness, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -986,18 +1865,18 @@ func (in *UpgradeLogList) DeepCopyInto(out *UpgradeLogList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeLogList.
-func (in *UpgradeLogList) DeepCopy() *UpgradeLogList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeReadinessList.
+func (in *UpgradeReadinessList) DeepCopy() *UpgradeReadinessList {
 	if in == nil {
 		return nil
 	}
-	out := new(UpgradeLogList)
+	out := new(UpgradeReadinessList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *UpgradeLogList) DeepCopyObject() runtime.Object {
+func (in *UpgradeReadinessList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1005,45 +1884,74 @@ func (in *UpgradeLogList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *UpgradeLogSpec) DeepCopyInto(out *UpgradeLogSpec) {
+func (in *UpgradeReadinessSpec) DeepCopyInto(out *UpgradeReadinessSpec) {
 	*out = *in
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeLogSpec.
-func (in *UpgradeLogSpec) DeepCopy() *UpgradeLogSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeReadinessSpec.
+func (in *UpgradeReadinessSpec) DeepCopy() *UpgradeReadinessSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(UpgradeLogSpec)
+	out := new(UpgradeReadinessSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *UpgradeLogStatus) DeepCopyInto(out *UpgradeLogStatus) {
+func (in *UpgradeReadinessStatus) DeepCopyInto(out *UpgradeReadinessStatus) {
 	*out = *in
-	if in.Archives != nil {
-		in, out := &in.Archives, &out.Archives
-		*out = make(map[string]Archive, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]ReadinessCheck, len(*in))
+		copy(*out, *in)
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeReadinessStatus.
+func (in *UpgradeReadinessStatus) DeepCopy() *UpgradeReadinessStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeReadinessStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeRollbackStatus) DeepCopyInto(out *UpgradeRollbackStatus) {
+	*out = *in
+	if in.RolledBack != nil {
+		in, out := &in.RolledBack, &out.RolledBack
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SkippedNodes != nil {
+		in, out := &in.SkippedNodes, &out.SkippedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManualActions != nil {
+		in, out := &in.ManualActions, &out.ManualActions
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeLogStatus.
-func (in *UpgradeLogStatus) DeepCopy() *UpgradeLogStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeRollbackStatus.
+func (in *UpgradeRollbackStatus) DeepCopy() *UpgradeRollbackStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(UpgradeLogStatus)
+	out := new(UpgradeRollbackStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1051,6 +1959,25 @@ func (in *UpgradeLogStatus) DeepCopy() *UpgradeLogStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpgradeSpec) DeepCopyInto(out *UpgradeSpec) {
 	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]UpgradeWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeBatches != nil {
+		in, out := &in.NodeBatches, &out.NodeBatches
+		*out = make([]UpgradeNodeBatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(UpgradeCanary)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1079,6 +2006,21 @@ func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
 		*out = make([]Condition, len(*in))
 		copy(*out, *in)
 	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(UpgradeRollbackStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(UpgradeCanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePreload != nil {
+		in, out := &in.ImagePreload, &out.ImagePreload
+		*out = new(ImagePreloadStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1092,6 +2034,27 @@ func (in *UpgradeStatus) DeepCopy() *UpgradeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeWindow) DeepCopyInto(out *UpgradeWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeWindow.
+func (in *UpgradeWindow) DeepCopy() *UpgradeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VMBackupInfo) DeepCopyInto(out *VMBackupInfo) {
 	*out = *in
@@ -1125,6 +2088,22 @@ func (in *VMBackupInfo) DeepCopy() *VMBackupInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMMigrationHistory) DeepCopyInto(out *VMMigrationHistory) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMMigrationHistory.
+func (in *VMMigrationHistory) DeepCopy() *VMMigrationHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(VMMigrationHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Version) DeepCopyInto(out *Version) {
 	*out = *in
@@ -1420,6 +2399,43 @@ func (in *VirtualMachineImageList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImageNodeCache) DeepCopyInto(out *VirtualMachineImageNodeCache) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImageNodeCache.
+func (in *VirtualMachineImageNodeCache) DeepCopy() *VirtualMachineImageNodeCache {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImageNodeCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImageNodeCacheStatus) DeepCopyInto(out *VirtualMachineImageNodeCacheStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImageNodeCacheStatus.
+func (in *VirtualMachineImageNodeCacheStatus) DeepCopy() *VirtualMachineImageNodeCacheStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImageNodeCacheStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineImageSecurityParameters) DeepCopyInto(out *VirtualMachineImageSecurityParameters) {
 	*out = *in
@@ -1451,6 +2467,11 @@ func (in *VirtualMachineImageSpec) DeepCopyInto(out *VirtualMachineImageSpec) {
 		*out = new(VirtualMachineImageSecurityParameters)
 		**out = **in
 	}
+	if in.NodeCache != nil {
+		in, out := &in.NodeCache, &out.NodeCache
+		*out = new(VirtualMachineImageNodeCache)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1477,6 +2498,16 @@ func (in *VirtualMachineImageStatus) DeepCopyInto(out *VirtualMachineImageStatus
 		*out = make([]Condition, len(*in))
 		copy(*out, *in)
 	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(VirtualMachineImageUsage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeCache != nil {
+		in, out := &in.NodeCache, &out.NodeCache
+		*out = make([]VirtualMachineImageNodeCacheStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1490,6 +2521,37 @@ func (in *VirtualMachineImageStatus) DeepCopy() *VirtualMachineImageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineImageUsage) DeepCopyInto(out *VirtualMachineImageUsage) {
+	*out = *in
+	if in.PVCs != nil {
+		in, out := &in.PVCs, &out.PVCs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VirtualMachines != nil {
+		in, out := &in.VirtualMachines, &out.VirtualMachines
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TemplateVersions != nil {
+		in, out := &in.TemplateVersions, &out.TemplateVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineImageUsage.
+func (in *VirtualMachineImageUsage) DeepCopy() *VirtualMachineImageUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineImageUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineRestore) DeepCopyInto(out *VirtualMachineRestore) {
 	*out = *in
@@ -1642,8 +2704,8 @@ func (in *VirtualMachineTemplate) DeepCopyInto(out *VirtualMachineTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -1665,6 +2727,27 @@ func (in *VirtualMachineTemplate) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineTemplateConsumer) DeepCopyInto(out *VirtualMachineTemplateConsumer) {
+	*out = *in
+	if in.VirtualMachines != nil {
+		in, out := &in.VirtualMachines, &out.VirtualMachines
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineTemplateConsumer.
+func (in *VirtualMachineTemplateConsumer) DeepCopy() *VirtualMachineTemplateConsumer {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineTemplateConsumer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineTemplateList) DeepCopyInto(out *VirtualMachineTemplateList) {
 	*out = *in
@@ -1698,9 +2781,51 @@ func (in *VirtualMachineTemplateList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineTemplateParameter) DeepCopyInto(out *VirtualMachineTemplateParameter) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineTemplateParameter.
+func (in *VirtualMachineTemplateParameter) DeepCopy() *VirtualMachineTemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineTemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineTemplateSharing) DeepCopyInto(out *VirtualMachineTemplateSharing) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineTemplateSharing.
+func (in *VirtualMachineTemplateSharing) DeepCopy() *VirtualMachineTemplateSharing {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineTemplateSharing)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineTemplateSpec) DeepCopyInto(out *VirtualMachineTemplateSpec) {
 	*out = *in
+	if in.SharedWith != nil {
+		in, out := &in.SharedWith, &out.SharedWith
+		*out = new(VirtualMachineTemplateSharing)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1717,6 +2842,13 @@ func (in *VirtualMachineTemplateSpec) DeepCopy() *VirtualMachineTemplateSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineTemplateStatus) DeepCopyInto(out *VirtualMachineTemplateStatus) {
 	*out = *in
+	if in.Consumers != nil {
+		in, out := &in.Consumers, &out.Consumers
+		*out = make([]VirtualMachineTemplateConsumer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -1758,6 +2890,22 @@ func (in *VirtualMachineTemplateVersion) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualMachineTemplateVersionDeprecation) DeepCopyInto(out *VirtualMachineTemplateVersionDeprecation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualMachineTemplateVersionDeprecation.
+func (in *VirtualMachineTemplateVersionDeprecation) DeepCopy() *VirtualMachineTemplateVersionDeprecation {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualMachineTemplateVersionDeprecation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualMachineTemplateVersionList) DeepCopyInto(out *VirtualMachineTemplateVersionList) {
 	*out = *in
@@ -1800,6 +2948,16 @@ func (in *VirtualMachineTemplateVersionSpec) DeepCopyInto(out *VirtualMachineTem
 		copy(*out, *in)
 	}
 	in.VM.DeepCopyInto(&out.VM)
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]VirtualMachineTemplateParameter, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deprecation != nil {
+		in, out := &in.Deprecation, &out.Deprecation
+		*out = new(VirtualMachineTemplateVersionDeprecation)
+		**out = **in
+	}
 	return
 }
 
@@ -278,3 +278,88 @@ func NewScheduleVMBackup(namespace, name string, obj ScheduleVMBackup) *Schedule
 	obj.Namespace = namespace
 	return &obj
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeBMCList is a list of NodeBMC resources
+type NodeBMCList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NodeBMC `json:"items"`
+}
+
+func NewNodeBMC(namespace, name string, obj NodeBMC) *NodeBMC {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("NodeBMC").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MaintenancePlanList is a list of MaintenancePlan resources
+type MaintenancePlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []MaintenancePlan `json:"items"`
+}
+
+func NewMaintenancePlan(namespace, name string, obj MaintenancePlan) *MaintenancePlan {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("MaintenancePlan").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeHealthList is a list of NodeHealth resources
+type NodeHealthList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NodeHealth `json:"items"`
+}
+
+func NewNodeHealth(namespace, name string, obj NodeHealth) *NodeHealth {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("NodeHealth").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// UpgradeReadinessList is a list of UpgradeReadiness resources
+type UpgradeReadinessList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []UpgradeReadiness `json:"items"`
+}
+
+func NewUpgradeReadiness(namespace, name string, obj UpgradeReadiness) *UpgradeReadiness {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("UpgradeReadiness").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// UpgradeHistoryList is a list of UpgradeHistory resources
+type UpgradeHistoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []UpgradeHistory `json:"items"`
+}
+
+func NewUpgradeHistory(namespace, name string, obj UpgradeHistory) *UpgradeHistory {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("UpgradeHistory").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
@@ -30,13 +30,18 @@ import (
 var (
 	AddonResourceName                         = "addons"
 	KeyPairResourceName                       = "keypairs"
+	MaintenancePlanResourceName               = "maintenanceplans"
+	NodeBMCResourceName                       = "nodebmcs"
+	NodeHealthResourceName                    = "nodehealths"
 	PreferenceResourceName                    = "preferences"
 	ResourceQuotaResourceName                 = "resourcequotas"
 	ScheduleVMBackupResourceName              = "schedulevmbackups"
 	SettingResourceName                       = "settings"
 	SupportBundleResourceName                 = "supportbundles"
 	UpgradeResourceName                       = "upgrades"
+	UpgradeHistoryResourceName                = "upgradehistories"
 	UpgradeLogResourceName                    = "upgradelogs"
+	UpgradeReadinessResourceName              = "upgradereadinesses"
 	VersionResourceName                       = "versions"
 	VirtualMachineBackupResourceName          = "virtualmachinebackups"
 	VirtualMachineImageResourceName           = "virtualmachineimages"
@@ -70,6 +75,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&AddonList{},
 		&KeyPair{},
 		&KeyPairList{},
+		&MaintenancePlan{},
+		&MaintenancePlanList{},
+		&NodeBMC{},
+		&NodeBMCList{},
+		&NodeHealth{},
+		&NodeHealthList{},
 		&Preference{},
 		&PreferenceList{},
 		&ResourceQuota{},
@@ -82,8 +93,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&SupportBundleList{},
 		&Upgrade{},
 		&UpgradeList{},
+		&UpgradeHistory{},
+		&UpgradeHistoryList{},
 		&UpgradeLog{},
 		&UpgradeLogList{},
+		&UpgradeReadiness{},
+		&UpgradeReadinessList{},
 		&Version{},
 		&VersionList{},
 		&VirtualMachineBackup{},
@@ -53,6 +53,11 @@ func main() {
 					cloudweavv1.Addon{},
 					cloudweavv1.ResourceQuota{},
 					cloudweavv1.ScheduleVMBackup{},
+					cloudweavv1.NodeBMC{},
+					cloudweavv1.MaintenancePlan{},
+					cloudweavv1.NodeHealth{},
+					cloudweavv1.UpgradeReadiness{},
+					cloudweavv1.UpgradeHistory{},
 				},
 				GenerateTypes:   true,
 				GenerateClients: true,
@@ -117,6 +122,7 @@ func main() {
 					longhornv1.Replica{},
 					longhornv1.Engine{},
 					longhornv1.Snapshot{},
+					longhornv1.Node{},
 				},
 				GenerateClients: true,
 			},
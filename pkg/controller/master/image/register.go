@@ -5,12 +5,17 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+
 	"github.com/cloudweav/cloudweav/pkg/config"
 )
 
 const (
-	vmImageControllerName      = "vm-image-controller"
-	backingImageControllerName = "backing-image-controller"
+	vmImageControllerName          = "vm-image-controller"
+	vmImageUsageControllerName     = "vm-image-usage-controller"
+	vmImageGCPolicyControllerName  = "vm-image-gc-policy-controller"
+	vmImageNodeCacheControllerName = "vm-image-node-cache-controller"
+	backingImageControllerName     = "backing-image-controller"
 )
 
 func Register(ctx context.Context, management *config.Management, _ config.Options) error {
@@ -19,6 +24,11 @@ func Register(ctx context.Context, management *config.Management, _ config.Optio
 	storageClasses := management.StorageFactory.Storage().V1().StorageClass()
 	pvcs := management.CoreFactory.Core().V1().PersistentVolumeClaim()
 	secrets := management.CoreFactory.Core().V1().Secret()
+	vms := management.VirtFactory.Kubevirt().V1().VirtualMachine()
+	templateVersions := management.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineTemplateVersion()
+	cloudweavSettings := management.CloudweavFactory.Cloudweavhci().V1beta1().Setting()
+	nodes := management.CoreFactory.Core().V1().Node()
+	lhNodes := management.LonghornFactory.Longhorn().V1beta2().Node()
 	vmImageHandler := &vmImageHandler{
 		backingImages:     backingImages,
 		backingImageCache: backingImages.Cache(),
@@ -39,9 +49,38 @@ func Register(ctx context.Context, management *config.Management, _ config.Optio
 		backingImageCache: backingImages.Cache(),
 	}
 
+	vmImageUsageHandler := &vmImageUsageHandler{
+		images:               images,
+		imageCache:           images.Cache(),
+		imageController:      images,
+		pvcCache:             pvcs.Cache(),
+		vmCache:              vms.Cache(),
+		templateVersionCache: templateVersions.Cache(),
+	}
+
+	vmImageNodeCacheHandler := &vmImageNodeCacheHandler{
+		images:            images,
+		imageCache:        images.Cache(),
+		imageController:   images,
+		backingImages:     backingImages,
+		backingImageCache: backingImages.Cache(),
+		nodeCache:         nodes.Cache(),
+		lhNodeCache:       lhNodes.Cache(),
+	}
+
 	images.OnChange(ctx, vmImageControllerName, vmImageHandler.OnChanged)
 	images.OnRemove(ctx, vmImageControllerName, vmImageHandler.OnRemove)
 
+	images.OnChange(ctx, vmImageUsageControllerName, vmImageUsageHandler.OnChanged)
+	cloudweavSettings.OnChange(ctx, vmImageGCPolicyControllerName, vmImageUsageHandler.OnImageGCPolicyChanged)
+	relatedresource.Watch(ctx, "watch-image-pvcs", vmImageUsageHandler.ResolvePVC, images, pvcs)
+	relatedresource.Watch(ctx, "watch-image-vms", vmImageUsageHandler.ResolveVM, images, vms)
+	relatedresource.Watch(ctx, "watch-image-templateversions", vmImageUsageHandler.ResolveTemplateVersion, images, templateVersions)
+
+	images.OnChange(ctx, vmImageNodeCacheControllerName, vmImageNodeCacheHandler.OnChanged)
+	relatedresource.Watch(ctx, "watch-image-cache-backingimages", vmImageNodeCacheHandler.ResolveBackingImage, images, backingImages)
+	relatedresource.Watch(ctx, "watch-image-cache-nodes", vmImageNodeCacheHandler.ResolveNode, images, nodes)
+
 	backingImages.OnChange(ctx, backingImageControllerName, backingImageHandler.OnChanged)
 	return nil
 }
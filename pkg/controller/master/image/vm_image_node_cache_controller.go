@@ -0,0 +1,300 @@
+package image
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	lhv1beta2 "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	"github.com/rancher/wrangler/v3/pkg/condition"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	ctllhv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/longhorn.io/v1beta2"
+	"github.com/cloudweav/cloudweav/pkg/ref"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+const (
+	nodeCacheRecheckInterval = 1 * time.Minute
+)
+
+// vmImageNodeCacheHandler places copies of the backing image on the disks of the nodes
+// selected by the image node cache, and reports the per-node cache readiness
+type vmImageNodeCacheHandler struct {
+	images            ctlcloudweavv1.VirtualMachineImageClient
+	imageCache        ctlcloudweavv1.VirtualMachineImageCache
+	imageController   ctlcloudweavv1.VirtualMachineImageController
+	backingImages     ctllhv1.BackingImageClient
+	backingImageCache ctllhv1.BackingImageCache
+	nodeCache         ctlcorev1.NodeCache
+	lhNodeCache       ctllhv1.NodeCache
+}
+
+func (h *vmImageNodeCacheHandler) OnChanged(_ string, image *cloudweavv1.VirtualMachineImage) (*cloudweavv1.VirtualMachineImage, error) {
+	if image == nil || image.DeletionTimestamp != nil || !cloudweavv1.ImageImported.IsTrue(image) {
+		return image, nil
+	}
+
+	if image.Spec.NodeCache == nil {
+		if err := h.pruneNodeCacheDisks(image, nil); err != nil {
+			return image, err
+		}
+		if len(image.Status.NodeCache) == 0 && cloudweavv1.ImageNodeCacheReady.GetStatus(image) == "" {
+			return image, nil
+		}
+		toUpdate := image.DeepCopy()
+		toUpdate.Status.NodeCache = nil
+		toUpdate.Status.Conditions = removeCondition(toUpdate.Status.Conditions, cloudweavv1.ImageNodeCacheReady)
+		return h.images.Update(toUpdate)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(image.Spec.NodeCache.NodeSelector)
+	if err != nil {
+		return image, err
+	}
+	nodes, err := h.nodeCache.List(selector)
+	if err != nil {
+		return image, err
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	bi, err := util.GetBackingImage(h.backingImageCache, image)
+	if err != nil {
+		return image, err
+	}
+
+	biToUpdate := bi.DeepCopy()
+
+	statuses := make([]cloudweavv1.VirtualMachineImageNodeCacheStatus, 0, len(nodes))
+	disks := make(map[string]bool, len(nodes))
+	readyCount := 0
+	for _, node := range nodes {
+		status, err := h.syncNodeCopy(biToUpdate, node)
+		if err != nil {
+			return image, err
+		}
+		if status.DiskUUID != "" {
+			disks[status.DiskUUID] = true
+		}
+		if status.Ready {
+			readyCount++
+		}
+		statuses = append(statuses, status)
+	}
+	pruneNodeCacheDisks(biToUpdate, disks)
+
+	if !reflect.DeepEqual(bi.Spec.DiskFileSpecMap, biToUpdate.Spec.DiskFileSpecMap) ||
+		bi.Annotations[util.AnnotationNodeCacheDisks] != biToUpdate.Annotations[util.AnnotationNodeCacheDisks] {
+		if _, err := h.backingImages.Update(biToUpdate); err != nil {
+			return image, err
+		}
+	}
+
+	toUpdate := image.DeepCopy()
+	toUpdate.Status.NodeCache = statuses
+	if readyCount == len(statuses) {
+		cloudweavv1.ImageNodeCacheReady.True(toUpdate)
+		cloudweavv1.ImageNodeCacheReady.Reason(toUpdate, "")
+	} else {
+		cloudweavv1.ImageNodeCacheReady.False(toUpdate)
+		cloudweavv1.ImageNodeCacheReady.Reason(toUpdate, "Caching")
+		// Longhorn nodes and disks may show up after the node is selected, recheck until all copies are ready
+		h.imageController.EnqueueAfter(image.Namespace, image.Name, nodeCacheRecheckInterval)
+	}
+	cloudweavv1.ImageNodeCacheReady.Message(toUpdate, fmt.Sprintf("%d/%d nodes are ready", readyCount, len(statuses)))
+
+	if !reflect.DeepEqual(image.Status, toUpdate.Status) {
+		return h.images.Update(toUpdate)
+	}
+	return image, nil
+}
+
+// syncNodeCopy makes sure the backing image has a copy requested on one disk of the node,
+// and returns the state of that copy.
+func (h *vmImageNodeCacheHandler) syncNodeCopy(bi *lhv1beta2.BackingImage, node *corev1.Node) (cloudweavv1.VirtualMachineImageNodeCacheStatus, error) {
+	status := cloudweavv1.VirtualMachineImageNodeCacheStatus{
+		NodeName: node.Name,
+	}
+
+	lhNode, err := h.lhNodeCache.Get(util.LonghornSystemNamespaceName, node.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			status.Message = "Longhorn node is not found"
+			return status, nil
+		}
+		return status, err
+	}
+
+	diskUUID := pickNodeCacheDisk(lhNode, bi)
+	if diskUUID == "" {
+		status.Message = "no schedulable disk found on the node"
+		return status, nil
+	}
+	status.DiskUUID = diskUUID
+
+	if _, ok := bi.Spec.DiskFileSpecMap[diskUUID]; !ok {
+		if bi.Spec.DiskFileSpecMap == nil {
+			bi.Spec.DiskFileSpecMap = map[string]*lhv1beta2.BackingImageDiskFileSpec{}
+		}
+		bi.Spec.DiskFileSpecMap[diskUUID] = &lhv1beta2.BackingImageDiskFileSpec{}
+		// remember the copies requested by the node cache, so they are the only ones ever pruned
+		addNodeCacheDisk(bi, diskUUID)
+	}
+
+	fileStatus, ok := bi.Status.DiskFileStatusMap[diskUUID]
+	if !ok || fileStatus == nil {
+		status.State = "pending"
+		return status, nil
+	}
+	status.State = string(fileStatus.State)
+	status.Progress = fileStatus.Progress
+	status.Message = fileStatus.Message
+	status.Ready = fileStatus.State == lhv1beta2.BackingImageStateReady
+	return status, nil
+}
+
+// pickNodeCacheDisk returns the disk of the node already holding the backing image, or else
+// the schedulable filesystem disk with the most available storage.
+func pickNodeCacheDisk(lhNode *lhv1beta2.Node, bi *lhv1beta2.BackingImage) string {
+	var candidate string
+	var available int64
+	for diskName, diskStatus := range lhNode.Status.DiskStatus {
+		if diskStatus == nil || diskStatus.DiskUUID == "" {
+			continue
+		}
+		if _, ok := bi.Spec.DiskFileSpecMap[diskStatus.DiskUUID]; ok {
+			return diskStatus.DiskUUID
+		}
+		if _, ok := bi.Status.DiskFileStatusMap[diskStatus.DiskUUID]; ok {
+			return diskStatus.DiskUUID
+		}
+
+		diskSpec, ok := lhNode.Spec.Disks[diskName]
+		if !ok || !diskSpec.AllowScheduling || diskSpec.EvictionRequested || diskSpec.Type != lhv1beta2.DiskTypeFilesystem {
+			continue
+		}
+		if candidate == "" || diskStatus.StorageAvailable > available {
+			candidate = diskStatus.DiskUUID
+			available = diskStatus.StorageAvailable
+		}
+	}
+	return candidate
+}
+
+// pruneNodeCacheDisks drops the copies requested by the node cache on the disks that are not in use
+// anymore, e.g. when the node is removed, no longer matches the node selector, or the node cache is
+// disabled. When the image itself is removed, its backing image and all the copies go with it.
+func (h *vmImageNodeCacheHandler) pruneNodeCacheDisks(image *cloudweavv1.VirtualMachineImage, disks map[string]bool) error {
+	bi, err := util.GetBackingImage(h.backingImageCache, image)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if bi.Annotations[util.AnnotationNodeCacheDisks] == "" {
+		return nil
+	}
+
+	biToUpdate := bi.DeepCopy()
+	pruneNodeCacheDisks(biToUpdate, disks)
+	_, err = h.backingImages.Update(biToUpdate)
+	return err
+}
+
+func pruneNodeCacheDisks(bi *lhv1beta2.BackingImage, disks map[string]bool) {
+	var kept []string
+	for _, diskUUID := range nodeCacheDisks(bi) {
+		if disks[diskUUID] {
+			kept = append(kept, diskUUID)
+			continue
+		}
+		delete(bi.Spec.DiskFileSpecMap, diskUUID)
+	}
+
+	if len(kept) == 0 {
+		delete(bi.Annotations, util.AnnotationNodeCacheDisks)
+		return
+	}
+	bi.Annotations[util.AnnotationNodeCacheDisks] = strings.Join(kept, ",")
+}
+
+func nodeCacheDisks(bi *lhv1beta2.BackingImage) []string {
+	if bi.Annotations[util.AnnotationNodeCacheDisks] == "" {
+		return nil
+	}
+	return strings.Split(bi.Annotations[util.AnnotationNodeCacheDisks], ",")
+}
+
+func addNodeCacheDisk(bi *lhv1beta2.BackingImage, diskUUID string) {
+	disks := nodeCacheDisks(bi)
+	if slices.Contains(disks, diskUUID) {
+		return
+	}
+	if bi.Annotations == nil {
+		bi.Annotations = map[string]string{}
+	}
+	bi.Annotations[util.AnnotationNodeCacheDisks] = strings.Join(append(disks, diskUUID), ",")
+}
+
+func removeCondition(conditions []cloudweavv1.Condition, cond condition.Cond) []cloudweavv1.Condition {
+	result := make([]cloudweavv1.Condition, 0, len(conditions))
+	for _, c := range conditions {
+		if c.Type != cond {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// ResolveBackingImage enqueues the image owning the backing image when it has a node cache.
+func (h *vmImageNodeCacheHandler) ResolveBackingImage(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	bi, ok := obj.(*lhv1beta2.BackingImage)
+	if !ok || bi.Annotations[util.AnnotationImageID] == "" {
+		return nil, nil
+	}
+
+	namespace, name := ref.Parse(bi.Annotations[util.AnnotationImageID])
+	image, err := h.imageCache.Get(namespace, name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if image.Spec.NodeCache == nil {
+		return nil, nil
+	}
+	return []relatedresource.Key{relatedresource.NewKey(namespace, name)}, nil
+}
+
+// ResolveNode enqueues the images with a node cache when a node changes, since its labels may
+// now match or no longer match their node selectors.
+func (h *vmImageNodeCacheHandler) ResolveNode(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	if _, ok := obj.(*corev1.Node); !ok {
+		return nil, nil
+	}
+
+	images, err := h.imageCache.List(metav1.NamespaceAll, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var keys []relatedresource.Key
+	for _, image := range images {
+		if image.Spec.NodeCache != nil {
+			keys = append(keys, relatedresource.NewKey(image.Namespace, image.Name))
+		}
+	}
+	return keys, nil
+}
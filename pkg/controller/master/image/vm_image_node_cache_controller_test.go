@@ -0,0 +1,128 @@
+package image
+
+import (
+	"testing"
+
+	lhv1beta2 "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+func TestPickNodeCacheDisk(t *testing.T) {
+	lhNode := &lhv1beta2.Node{
+		Spec: lhv1beta2.NodeSpec{
+			Disks: map[string]lhv1beta2.DiskSpec{
+				"disk-a": {Type: lhv1beta2.DiskTypeFilesystem, AllowScheduling: true},
+				"disk-b": {Type: lhv1beta2.DiskTypeFilesystem, AllowScheduling: true},
+				"disk-c": {Type: lhv1beta2.DiskTypeFilesystem, AllowScheduling: false},
+				"disk-d": {Type: lhv1beta2.DiskTypeBlock, AllowScheduling: true},
+			},
+		},
+		Status: lhv1beta2.NodeStatus{
+			DiskStatus: map[string]*lhv1beta2.DiskStatus{
+				"disk-a": {DiskUUID: "uuid-a", StorageAvailable: 100},
+				"disk-b": {DiskUUID: "uuid-b", StorageAvailable: 200},
+				"disk-c": {DiskUUID: "uuid-c", StorageAvailable: 500},
+				"disk-d": {DiskUUID: "uuid-d", StorageAvailable: 800},
+			},
+		},
+	}
+
+	var testCases = []struct {
+		name     string
+		bi       *lhv1beta2.BackingImage
+		expected string
+	}{
+		{
+			name:     "pick the schedulable filesystem disk with the most available storage",
+			bi:       &lhv1beta2.BackingImage{},
+			expected: "uuid-b",
+		},
+		{
+			name: "keep the disk already requested in the spec",
+			bi: &lhv1beta2.BackingImage{
+				Spec: lhv1beta2.BackingImageSpec{
+					DiskFileSpecMap: map[string]*lhv1beta2.BackingImageDiskFileSpec{"uuid-a": {}},
+				},
+			},
+			expected: "uuid-a",
+		},
+		{
+			name: "keep the disk already holding a copy",
+			bi: &lhv1beta2.BackingImage{
+				Status: lhv1beta2.BackingImageStatus{
+					DiskFileStatusMap: map[string]*lhv1beta2.BackingImageDiskFileStatus{"uuid-c": {}},
+				},
+			},
+			expected: "uuid-c",
+		},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, pickNodeCacheDisk(lhNode, tc.bi), tc.name)
+	}
+}
+
+func TestPruneNodeCacheDisks(t *testing.T) {
+	newBackingImage := func() *lhv1beta2.BackingImage {
+		return &lhv1beta2.BackingImage{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{util.AnnotationNodeCacheDisks: "uuid-b,uuid-c"},
+			},
+			Spec: lhv1beta2.BackingImageSpec{
+				DiskFileSpecMap: map[string]*lhv1beta2.BackingImageDiskFileSpec{
+					"uuid-a": {},
+					"uuid-b": {},
+					"uuid-c": {},
+				},
+			},
+		}
+	}
+
+	var testCases = []struct {
+		name                string
+		disks               map[string]bool
+		expectedDisks       []string
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:                "keep the disks still selected",
+			disks:               map[string]bool{"uuid-b": true, "uuid-c": true},
+			expectedDisks:       []string{"uuid-a", "uuid-b", "uuid-c"},
+			expectedAnnotations: map[string]string{util.AnnotationNodeCacheDisks: "uuid-b,uuid-c"},
+		},
+		{
+			name:                "prune the disk of a removed node",
+			disks:               map[string]bool{"uuid-c": true},
+			expectedDisks:       []string{"uuid-a", "uuid-c"},
+			expectedAnnotations: map[string]string{util.AnnotationNodeCacheDisks: "uuid-c"},
+		},
+		{
+			name:                "prune all the node cache disks but not the other copies",
+			disks:               nil,
+			expectedDisks:       []string{"uuid-a"},
+			expectedAnnotations: map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		bi := newBackingImage()
+		pruneNodeCacheDisks(bi, tc.disks)
+		var disks []string
+		for diskUUID := range bi.Spec.DiskFileSpecMap {
+			disks = append(disks, diskUUID)
+		}
+		assert.ElementsMatch(t, tc.expectedDisks, disks, tc.name)
+		assert.Equal(t, tc.expectedAnnotations, bi.Annotations, tc.name)
+	}
+}
+
+func TestAddNodeCacheDisk(t *testing.T) {
+	bi := &lhv1beta2.BackingImage{}
+	addNodeCacheDisk(bi, "uuid-a")
+	addNodeCacheDisk(bi, "uuid-b")
+	addNodeCacheDisk(bi, "uuid-a")
+	assert.Equal(t, "uuid-a,uuid-b", bi.Annotations[util.AnnotationNodeCacheDisks])
+}
@@ -0,0 +1,324 @@
+package image
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/rancher/wrangler/v3/pkg/slice"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
+	"github.com/cloudweav/cloudweav/pkg/indexeres"
+	"github.com/cloudweav/cloudweav/pkg/ref"
+	"github.com/cloudweav/cloudweav/pkg/settings"
+	indexeresutil "github.com/cloudweav/cloudweav/pkg/util/indexeres"
+)
+
+const (
+	imageUnusedReasonUnreferenced = "Unreferenced"
+	imageUnusedReasonGCDryRun     = "GCDryRun"
+)
+
+// vmImageUsageHandler records the PVCs, VMs and template versions referencing a vm image,
+// and deletes images left unused according to the image-gc-policy setting
+type vmImageUsageHandler struct {
+	images               ctlcloudweavv1.VirtualMachineImageClient
+	imageCache           ctlcloudweavv1.VirtualMachineImageCache
+	imageController      ctlcloudweavv1.VirtualMachineImageController
+	pvcCache             ctlcorev1.PersistentVolumeClaimCache
+	vmCache              ctlkubevirtv1.VirtualMachineCache
+	templateVersionCache ctlcloudweavv1.VirtualMachineTemplateVersionCache
+}
+
+func (h *vmImageUsageHandler) OnChanged(_ string, image *cloudweavv1.VirtualMachineImage) (*cloudweavv1.VirtualMachineImage, error) {
+	if image == nil || image.DeletionTimestamp != nil || !cloudweavv1.ImageImported.IsTrue(image) {
+		return image, nil
+	}
+
+	usage, err := h.getUsage(image)
+	if err != nil {
+		return image, err
+	}
+
+	policy := currentImageGCPolicy()
+	now := time.Now()
+	toUpdate := image.DeepCopy()
+	toUpdate.Status.Usage = updateUsage(image.Status.Usage, usage, now)
+
+	if isImageReferenced(toUpdate.Status.Usage) {
+		cloudweavv1.ImageUnused.False(toUpdate)
+		cloudweavv1.ImageUnused.Reason(toUpdate, "")
+		cloudweavv1.ImageUnused.Message(toUpdate, "")
+	} else {
+		since := unusedSince(toUpdate)
+		cloudweavv1.ImageUnused.True(toUpdate)
+		cloudweavv1.ImageUnused.Reason(toUpdate, imageUnusedReasonUnreferenced)
+		cloudweavv1.ImageUnused.Message(toUpdate, fmt.Sprintf("image is not referenced since %s", since.Format(time.RFC3339)))
+
+		switch action, after := imageGCActionFor(image, policy, since, now); action {
+		case imageGCRequeue:
+			h.imageController.EnqueueAfter(image.Namespace, image.Name, after)
+		case imageGCDryRun:
+			cloudweavv1.ImageUnused.Reason(toUpdate, imageUnusedReasonGCDryRun)
+			cloudweavv1.ImageUnused.Message(toUpdate, fmt.Sprintf("image is not referenced since %s and would be deleted by %s", since.Format(time.RFC3339), settings.ImageGCPolicySettingName))
+		case imageGCDelete:
+			return h.deleteUnusedImage(image, since)
+		}
+	}
+
+	if !reflect.DeepEqual(image.Status, toUpdate.Status) {
+		return h.images.Update(toUpdate)
+	}
+	return image, nil
+}
+
+type imageGCAction int
+
+const (
+	imageGCNone imageGCAction = iota
+	imageGCRequeue
+	imageGCDryRun
+	imageGCDelete
+)
+
+// currentImageGCPolicy returns the image-gc-policy setting, or nil if it's invalid. The webhook rejects invalid
+// policies, an invalid one which made it in anyway only disables the collection instead of failing every image.
+func currentImageGCPolicy() *settings.ImageGCPolicy {
+	policy, err := settings.DecodeImageGCPolicy(settings.ImageGCPolicySet.Get())
+	if err != nil {
+		logrus.WithError(err).Warnf("skip image garbage collection, invalid %s setting", settings.ImageGCPolicySettingName)
+		return nil
+	}
+	return policy
+}
+
+// imageGCActionFor returns what the policy does with an image unused since the given time, and how long to wait
+// before checking the image again if it's not expired yet.
+func imageGCActionFor(image *cloudweavv1.VirtualMachineImage, policy *settings.ImageGCPolicy, since, now time.Time) (imageGCAction, time.Duration) {
+	if policy == nil || !policy.Enable {
+		return imageGCNone, 0
+	}
+	if len(policy.Namespaces) > 0 && !slice.ContainsString(policy.Namespaces, image.Namespace) {
+		return imageGCNone, 0
+	}
+
+	expiredAt := since.Add(time.Duration(policy.UnusedDays) * 24 * time.Hour)
+	switch {
+	case now.Before(expiredAt):
+		return imageGCRequeue, expiredAt.Sub(now)
+	case policy.DryRun:
+		return imageGCDryRun, 0
+	default:
+		return imageGCDelete, 0
+	}
+}
+
+func (h *vmImageUsageHandler) deleteUnusedImage(image *cloudweavv1.VirtualMachineImage, since time.Time) (*cloudweavv1.VirtualMachineImage, error) {
+	logrus.WithFields(logrus.Fields{
+		"namespace":   image.Namespace,
+		"name":        image.Name,
+		"unusedSince": since.Format(time.RFC3339),
+	}).Info("delete unused vmimage by image garbage collection")
+	// the deletion goes through the image validator, which rejects it if the image is still referenced
+	if err := h.images.Delete(image.Namespace, image.Name, &metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return image, err
+	}
+	return image, nil
+}
+
+func (h *vmImageUsageHandler) getUsage(image *cloudweavv1.VirtualMachineImage) (*cloudweavv1.VirtualMachineImageUsage, error) {
+	usage := &cloudweavv1.VirtualMachineImageUsage{}
+	vms := map[string]bool{}
+
+	if image.Status.StorageClassName != "" {
+		pvcs, err := h.pvcCache.GetByIndex(indexeres.PVCByStorageClassIndex, image.Status.StorageClassName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pvcs by storage class %s: %w", image.Status.StorageClassName, err)
+		}
+		for _, pvc := range pvcs {
+			pvcID := ref.Construct(pvc.Namespace, pvc.Name)
+			usage.PVCs = append(usage.PVCs, pvcID)
+
+			pvcVMs, err := h.vmCache.GetByIndex(indexeresutil.VMByPVCIndex, pvcID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get vms by pvc %s: %w", pvcID, err)
+			}
+			for _, vm := range pvcVMs {
+				vms[ref.Construct(vm.Namespace, vm.Name)] = true
+			}
+		}
+	}
+
+	imageID := ref.Construct(image.Namespace, image.Name)
+	imageVMs, err := h.vmCache.GetByIndex(indexeresutil.VMByImageIDIndex, imageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vms by image %s: %w", imageID, err)
+	}
+	for _, vm := range imageVMs {
+		vms[ref.Construct(vm.Namespace, vm.Name)] = true
+	}
+	for vm := range vms {
+		usage.VirtualMachines = append(usage.VirtualMachines, vm)
+	}
+
+	templateVersions, err := h.templateVersionCache.GetByIndex(indexeres.VMTemplateVersionByImageIDIndex, imageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template versions by image %s: %w", imageID, err)
+	}
+	for _, templateVersion := range templateVersions {
+		usage.TemplateVersions = append(usage.TemplateVersions, ref.Construct(templateVersion.Namespace, templateVersion.Name))
+	}
+
+	sort.Strings(usage.PVCs)
+	sort.Strings(usage.VirtualMachines)
+	sort.Strings(usage.TemplateVersions)
+	return usage, nil
+}
+
+// updateUsage returns the usage to record, with the counts filled in. The last used time is
+// refreshed whenever the image is referenced and the references changed, and when the last
+// reference goes away.
+func updateUsage(old, current *cloudweavv1.VirtualMachineImageUsage, now time.Time) *cloudweavv1.VirtualMachineImageUsage {
+	usage := current.DeepCopy()
+	usage.PVCCount = len(usage.PVCs)
+	usage.VirtualMachineCount = len(usage.VirtualMachines)
+	usage.TemplateVersionCount = len(usage.TemplateVersions)
+
+	if old == nil {
+		if isImageReferenced(usage) {
+			usage.LastUsedTime = now.Format(time.RFC3339)
+		}
+		return usage
+	}
+
+	usage.LastUsedTime = old.LastUsedTime
+	changed := !reflect.DeepEqual(old.PVCs, usage.PVCs) ||
+		!reflect.DeepEqual(old.VirtualMachines, usage.VirtualMachines) ||
+		!reflect.DeepEqual(old.TemplateVersions, usage.TemplateVersions)
+	if changed && (isImageReferenced(usage) || isImageReferenced(old)) {
+		usage.LastUsedTime = now.Format(time.RFC3339)
+	}
+	return usage
+}
+
+func isImageReferenced(usage *cloudweavv1.VirtualMachineImageUsage) bool {
+	return usage != nil && (len(usage.PVCs) > 0 || len(usage.VirtualMachines) > 0 || len(usage.TemplateVersions) > 0)
+}
+
+// unusedSince returns the last used time of an image, or its creation time if it was never used.
+func unusedSince(image *cloudweavv1.VirtualMachineImage) time.Time {
+	if image.Status.Usage != nil && image.Status.Usage.LastUsedTime != "" {
+		if ts, err := time.Parse(time.RFC3339, image.Status.Usage.LastUsedTime); err == nil {
+			return ts
+		}
+	}
+	return image.CreationTimestamp.Time
+}
+
+// OnImageGCPolicyChanged re-evaluates all images when the image-gc-policy setting changes.
+func (h *vmImageUsageHandler) OnImageGCPolicyChanged(_ string, setting *cloudweavv1.Setting) (*cloudweavv1.Setting, error) {
+	if setting == nil || setting.DeletionTimestamp != nil || setting.Name != settings.ImageGCPolicySettingName {
+		return setting, nil
+	}
+
+	images, err := h.imageCache.List(metav1.NamespaceAll, labels.Everything())
+	if err != nil {
+		return setting, err
+	}
+	for _, image := range images {
+		h.imageController.Enqueue(image.Namespace, image.Name)
+	}
+	return setting, nil
+}
+
+func (h *vmImageUsageHandler) imageKeysByStorageClass(storageClassName string) ([]relatedresource.Key, error) {
+	images, err := h.imageCache.GetByIndex(indexeres.ImageByStorageClassNameIndex, storageClassName)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]relatedresource.Key, 0, len(images))
+	for _, image := range images {
+		keys = append(keys, relatedresource.NewKey(image.Namespace, image.Name))
+	}
+	return keys, nil
+}
+
+func imageKeysByID(imageIDs []string) []relatedresource.Key {
+	keys := make([]relatedresource.Key, 0, len(imageIDs))
+	for _, imageID := range imageIDs {
+		keys = append(keys, relatedresource.NewKey(ref.Parse(imageID)))
+	}
+	return keys
+}
+
+// ResolvePVC enqueues the image whose storage class provisioned the PVC.
+func (h *vmImageUsageHandler) ResolvePVC(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	pvc, ok := obj.(*corev1.PersistentVolumeClaim)
+	if !ok || pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return nil, nil
+	}
+	return h.imageKeysByStorageClass(*pvc.Spec.StorageClassName)
+}
+
+// ResolveVM enqueues the images referenced by the VM volume claim templates and volumes.
+func (h *vmImageUsageHandler) ResolveVM(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	vm, ok := obj.(*kubevirtv1.VirtualMachine)
+	if !ok {
+		return nil, nil
+	}
+
+	imageIDs, err := indexeresutil.VMByImageID(vm)
+	if err != nil {
+		return nil, err
+	}
+	keys := imageKeysByID(imageIDs)
+
+	pvcIDs, err := indexeresutil.VMByPVC(vm)
+	if err != nil {
+		return nil, err
+	}
+	for _, pvcID := range pvcIDs {
+		pvc, err := h.pvcCache.Get(ref.Parse(pvcID))
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+			continue
+		}
+		pvcKeys, err := h.imageKeysByStorageClass(*pvc.Spec.StorageClassName)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, pvcKeys...)
+	}
+	return keys, nil
+}
+
+// ResolveTemplateVersion enqueues the images referenced by the template version volume claim templates.
+func (h *vmImageUsageHandler) ResolveTemplateVersion(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	templateVersion, ok := obj.(*cloudweavv1.VirtualMachineTemplateVersion)
+	if !ok {
+		return nil, nil
+	}
+
+	imageIDs, err := indexeres.VMTemplateVersionByImageID(templateVersion)
+	if err != nil {
+		return nil, err
+	}
+	return imageKeysByID(imageIDs), nil
+}
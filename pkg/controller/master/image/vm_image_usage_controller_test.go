@@ -0,0 +1,162 @@
+package image
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/settings"
+)
+
+func TestUpdateUsage(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	earlier := now.Add(-48 * time.Hour).Format(time.RFC3339)
+
+	var testCases = []struct {
+		name             string
+		old              *cloudweavv1.VirtualMachineImageUsage
+		current          *cloudweavv1.VirtualMachineImageUsage
+		expectedCounts   [3]int
+		expectedLastUsed string
+	}{
+		{
+			name:             "never used image has no last used time",
+			old:              nil,
+			current:          &cloudweavv1.VirtualMachineImageUsage{},
+			expectedLastUsed: "",
+		},
+		{
+			name: "first reference sets last used time",
+			old:  nil,
+			current: &cloudweavv1.VirtualMachineImageUsage{
+				PVCs:            []string{"default/vm-disk-0"},
+				VirtualMachines: []string{"default/vm"},
+			},
+			expectedCounts:   [3]int{1, 1, 0},
+			expectedLastUsed: now.Format(time.RFC3339),
+		},
+		{
+			name: "unchanged references keep last used time",
+			old: &cloudweavv1.VirtualMachineImageUsage{
+				TemplateVersions: []string{"default/template-1"},
+				LastUsedTime:     earlier,
+			},
+			current: &cloudweavv1.VirtualMachineImageUsage{
+				TemplateVersions: []string{"default/template-1"},
+			},
+			expectedCounts:   [3]int{0, 0, 1},
+			expectedLastUsed: earlier,
+		},
+		{
+			name: "removing the last reference refreshes last used time",
+			old: &cloudweavv1.VirtualMachineImageUsage{
+				PVCs:         []string{"default/vm-disk-0"},
+				LastUsedTime: earlier,
+			},
+			current:          &cloudweavv1.VirtualMachineImageUsage{},
+			expectedLastUsed: now.Format(time.RFC3339),
+		},
+		{
+			name: "unused image keeps last used time",
+			old: &cloudweavv1.VirtualMachineImageUsage{
+				LastUsedTime: earlier,
+			},
+			current:          &cloudweavv1.VirtualMachineImageUsage{},
+			expectedLastUsed: earlier,
+		},
+	}
+
+	for _, tc := range testCases {
+		usage := updateUsage(tc.old, tc.current, now)
+		assert.Equal(t, tc.expectedCounts, [3]int{usage.PVCCount, usage.VirtualMachineCount, usage.TemplateVersionCount}, tc.name)
+		assert.Equal(t, tc.expectedLastUsed, usage.LastUsedTime, tc.name)
+	}
+}
+
+func TestUnusedSince(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lastUsed := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	image := &cloudweavv1.VirtualMachineImage{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+	assert.True(t, created.Equal(unusedSince(image)))
+
+	image.Status.Usage = &cloudweavv1.VirtualMachineImageUsage{LastUsedTime: lastUsed.Format(time.RFC3339)}
+	assert.True(t, lastUsed.Equal(unusedSince(image)))
+}
+
+func TestImageGCActionFor(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	image := &cloudweavv1.VirtualMachineImage{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "image"}}
+
+	var testCases = []struct {
+		name          string
+		policy        *settings.ImageGCPolicy
+		since         time.Time
+		expected      imageGCAction
+		expectedAfter time.Duration
+	}{
+		{
+			name:     "invalid policy skips the collection",
+			policy:   nil,
+			since:    now.AddDate(0, 0, -60),
+			expected: imageGCNone,
+		},
+		{
+			name:     "disabled policy skips the collection",
+			policy:   &settings.ImageGCPolicy{UnusedDays: 30},
+			since:    now.AddDate(0, 0, -60),
+			expected: imageGCNone,
+		},
+		{
+			name:     "images out of the policy namespaces are kept",
+			policy:   &settings.ImageGCPolicy{Enable: true, UnusedDays: 30, Namespaces: []string{"team-a"}},
+			since:    now.AddDate(0, 0, -60),
+			expected: imageGCNone,
+		},
+		{
+			name:          "image is checked again once it expires",
+			policy:        &settings.ImageGCPolicy{Enable: true, UnusedDays: 30},
+			since:         now.AddDate(0, 0, -29),
+			expected:      imageGCRequeue,
+			expectedAfter: 24 * time.Hour,
+		},
+		{
+			name:     "expired image is only reported in dry run",
+			policy:   &settings.ImageGCPolicy{Enable: true, UnusedDays: 30, DryRun: true, Namespaces: []string{"default"}},
+			since:    now.AddDate(0, 0, -30),
+			expected: imageGCDryRun,
+		},
+		{
+			name:     "expired image is deleted",
+			policy:   &settings.ImageGCPolicy{Enable: true, UnusedDays: 30},
+			since:    now.AddDate(0, 0, -31),
+			expected: imageGCDelete,
+		},
+	}
+
+	for _, tc := range testCases {
+		action, after := imageGCActionFor(image, tc.policy, tc.since, now)
+		assert.Equal(t, tc.expected, action, tc.name)
+		assert.Equal(t, tc.expectedAfter, after, tc.name)
+	}
+}
+
+func TestCurrentImageGCPolicy(t *testing.T) {
+	original := settings.ImageGCPolicySet.Get()
+	defer func() {
+		assert.NoError(t, settings.ImageGCPolicySet.Set(original))
+	}()
+
+	assert.NoError(t, settings.ImageGCPolicySet.Set(`{"enable":true,"unusedDays":7}`))
+	assert.Equal(t, &settings.ImageGCPolicy{Enable: true, UnusedDays: 7}, currentImageGCPolicy())
+
+	assert.NoError(t, settings.ImageGCPolicySet.Set(`{"enable":true,"unusedDays":0}`))
+	assert.Nil(t, currentImageGCPolicy(), "invalid policy doesn't fail the image reconcile")
+}
@@ -2,6 +2,7 @@ package keypair
 
 import (
 	"fmt"
+	"reflect"
 
 	"golang.org/x/crypto/ssh"
 
@@ -14,12 +15,14 @@ type Handler struct {
 	keyPairClient ctlcloudweavv1.KeyPairClient
 }
 
+// OnKeyPairChanged computes the fingerprints of the public key. They are computed again whenever
+// the key is rotated, so the status always describes the current key.
 func (h *Handler) OnKeyPairChanged(_ string, keyPair *cloudweavv1.KeyPair) (*cloudweavv1.KeyPair, error) {
 	if keyPair == nil || keyPair.DeletionTimestamp != nil {
 		return keyPair, nil
 	}
 
-	if keyPair.Spec.PublicKey == "" || keyPair.Status.FingerPrint != "" {
+	if keyPair.Spec.PublicKey == "" {
 		return keyPair, nil
 	}
 
@@ -27,12 +30,21 @@ func (h *Handler) OnKeyPairChanged(_ string, keyPair *cloudweavv1.KeyPair) (*clo
 	publicKey := []byte(keyPair.Spec.PublicKey)
 	pk, _, _, _, err := ssh.ParseAuthorizedKey(publicKey)
 	if err != nil {
+		toUpdate.Status.FingerPrint = ""
+		toUpdate.Status.SHA256FingerPrint = ""
+		toUpdate.Status.KeyType = ""
 		cloudweavv1.KeyPairValidated.False(toUpdate)
 		cloudweavv1.KeyPairValidated.Reason(toUpdate, fmt.Sprintf("failed to parse the public key, error: %v", err))
 	} else {
-		fingerPrint := ssh.FingerprintLegacyMD5(pk)
-		toUpdate.Status.FingerPrint = fingerPrint
+		toUpdate.Status.FingerPrint = ssh.FingerprintLegacyMD5(pk)
+		toUpdate.Status.SHA256FingerPrint = ssh.FingerprintSHA256(pk)
+		toUpdate.Status.KeyType = pk.Type()
 		cloudweavv1.KeyPairValidated.True(toUpdate)
+		cloudweavv1.KeyPairValidated.Reason(toUpdate, "")
+	}
+
+	if reflect.DeepEqual(keyPair.Status, toUpdate.Status) {
+		return keyPair, nil
 	}
 	return h.keyPairClient.Update(toUpdate)
 }
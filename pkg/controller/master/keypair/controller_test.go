@@ -2,6 +2,7 @@ package keypair
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"fmt"
@@ -31,8 +32,10 @@ func TestHandler_OnKeyPairChanged(t *testing.T) {
 		err     error
 	}
 
-	var testPublicKey, testPublicKeyFingerprint, err = generateSSHPublicKey()
+	var testPublicKey, testPublicKeyFingerprint, testPublicKeySHA256Fingerprint, err = generateSSHPublicKey()
 	assert.Nil(t, err, "mock SSH public key should be created")
+	var testEd25519PublicKey, testEd25519PublicKeyFingerprint, testEd25519PublicKeySHA256Fingerprint, ed25519Err = generateEd25519SSHPublicKey()
+	assert.Nil(t, ed25519Err, "mock Ed25519 SSH public key should be created")
 	var testCases = []struct {
 		name     string
 		given    input
@@ -100,7 +103,7 @@ func TestHandler_OnKeyPairChanged(t *testing.T) {
 			},
 		},
 		{
-			name: "not blank fingerprint",
+			name: "rotated public key",
 			given: input{
 				key: "default/test",
 				keyPair: &cloudweavv1.KeyPair{
@@ -109,7 +112,7 @@ func TestHandler_OnKeyPairChanged(t *testing.T) {
 						Name:      "test",
 					},
 					Spec: cloudweavv1.KeyPairSpec{
-						PublicKey: "FAKE_PUBLIC_KEY",
+						PublicKey: testPublicKey,
 					},
 					Status: cloudweavv1.KeyPairStatus{
 						FingerPrint: "FAKE_FINGER_PRINT",
@@ -123,10 +126,67 @@ func TestHandler_OnKeyPairChanged(t *testing.T) {
 						Name:      "test",
 					},
 					Spec: cloudweavv1.KeyPairSpec{
-						PublicKey: "FAKE_PUBLIC_KEY",
+						PublicKey: testPublicKey,
 					},
 					Status: cloudweavv1.KeyPairStatus{
-						FingerPrint: "FAKE_FINGER_PRINT",
+						Conditions: []cloudweavv1.Condition{
+							{
+								Type:   cloudweavv1.KeyPairValidated,
+								Status: corev1.ConditionTrue,
+							},
+						},
+						FingerPrint:       testPublicKeyFingerprint,
+						SHA256FingerPrint: testPublicKeySHA256Fingerprint,
+						KeyType:           ssh.KeyAlgoRSA,
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "up to date fingerprint",
+			given: input{
+				key: "default/test",
+				keyPair: &cloudweavv1.KeyPair{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "default",
+						Name:      "test",
+					},
+					Spec: cloudweavv1.KeyPairSpec{
+						PublicKey: testEd25519PublicKey,
+					},
+					Status: cloudweavv1.KeyPairStatus{
+						Conditions: []cloudweavv1.Condition{
+							{
+								Type:   cloudweavv1.KeyPairValidated,
+								Status: corev1.ConditionTrue,
+							},
+						},
+						FingerPrint:       testEd25519PublicKeyFingerprint,
+						SHA256FingerPrint: testEd25519PublicKeySHA256Fingerprint,
+						KeyType:           ssh.KeyAlgoED25519,
+					},
+				},
+			},
+			expected: output{
+				keyPair: &cloudweavv1.KeyPair{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "default",
+						Name:      "test",
+					},
+					Spec: cloudweavv1.KeyPairSpec{
+						PublicKey: testEd25519PublicKey,
+					},
+					Status: cloudweavv1.KeyPairStatus{
+						Conditions: []cloudweavv1.Condition{
+							{
+								Type:   cloudweavv1.KeyPairValidated,
+								Status: corev1.ConditionTrue,
+							},
+						},
+						FingerPrint:       testEd25519PublicKeyFingerprint,
+						SHA256FingerPrint: testEd25519PublicKeySHA256Fingerprint,
+						KeyType:           ssh.KeyAlgoED25519,
 					},
 				},
 				err: nil,
@@ -198,7 +258,9 @@ func TestHandler_OnKeyPairChanged(t *testing.T) {
 								Status: corev1.ConditionTrue,
 							},
 						},
-						FingerPrint: testPublicKeyFingerprint,
+						FingerPrint:       testPublicKeyFingerprint,
+						SHA256FingerPrint: testPublicKeySHA256Fingerprint,
+						KeyType:           ssh.KeyAlgoRSA,
 					},
 				},
 				err: nil,
@@ -230,18 +292,30 @@ func TestHandler_OnKeyPairChanged(t *testing.T) {
 	}
 }
 
-func generateSSHPublicKey() (pk string, fingerprint string, err error) {
+func generateSSHPublicKey() (pk string, fingerprint string, sha256Fingerprint string, err error) {
 	key, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to generate RSA key, %v", err)
+		return "", "", "", fmt.Errorf("failed to generate RSA key, %v", err)
 	}
 	pubKey, err := ssh.NewPublicKey(&key.PublicKey)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to create SSH public key, %v", err)
+		return "", "", "", fmt.Errorf("failed to create SSH public key, %v", err)
+	}
+	pk = string(ssh.MarshalAuthorizedKey(pubKey))
+	return pk, ssh.FingerprintLegacyMD5(pubKey), ssh.FingerprintSHA256(pubKey), nil
+}
+
+func generateEd25519SSHPublicKey() (pk string, fingerprint string, sha256Fingerprint string, err error) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to generate Ed25519 key, %v", err)
+	}
+	pubKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to create SSH public key, %v", err)
 	}
 	pk = string(ssh.MarshalAuthorizedKey(pubKey))
-	fingerprint = ssh.FingerprintLegacyMD5(pubKey)
-	return pk, fingerprint, nil
+	return pk, ssh.FingerprintLegacyMD5(pubKey), ssh.FingerprintSHA256(pubKey), nil
 }
 
 type fakeKeyPairClient func(string) typeharv1.KeyPairInterface
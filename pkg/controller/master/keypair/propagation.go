@@ -0,0 +1,281 @@
+package keypair
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/rancher/wrangler/v3/pkg/condition"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
+	"github.com/cloudweav/cloudweav/pkg/ref"
+	"github.com/cloudweav/cloudweav/pkg/util"
+	indexeresutil "github.com/cloudweav/cloudweav/pkg/util/indexeres"
+)
+
+// propagationHandler keeps the key pair secrets of the VMs up to date, so that the guest agent pushes
+// rotated keys to the running VMs, and reports the per-VM propagation state in the key pair status.
+type propagationHandler struct {
+	keyPairs     ctlcloudweavv1.KeyPairClient
+	keyPairCache ctlcloudweavv1.KeyPairCache
+	vmCache      ctlkubevirtv1.VirtualMachineCache
+	vmiCache     ctlkubevirtv1.VirtualMachineInstanceCache
+	secrets      ctlcorev1.SecretClient
+	secretCache  ctlcorev1.SecretCache
+}
+
+// OnVMChanged writes the public keys of the key pairs referenced by the VM to the secret read by the
+// guest agent propagation. The secret is owned by the VM and removed with it.
+func (h *propagationHandler) OnVMChanged(_ string, vm *kubevirtv1.VirtualMachine) (*kubevirtv1.VirtualMachine, error) {
+	if vm == nil || vm.DeletionTimestamp != nil || !isPropagationConfigured(vm) {
+		return vm, nil
+	}
+
+	keyPairIDs, err := indexeresutil.VMByKeyPair(vm)
+	if err != nil {
+		return vm, err
+	}
+	data := make(map[string][]byte, len(keyPairIDs))
+	for _, keyPairID := range keyPairIDs {
+		namespace, name := ref.Parse(keyPairID)
+		keyPair, err := h.keyPairCache.Get(namespace, name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return vm, err
+		}
+		data[secretKey(keyPair)] = []byte(keyPair.Spec.PublicKey)
+	}
+
+	secretName := util.KeyPairPropagationSecretName(vm.Name)
+	secret, err := h.secretCache.Get(vm.Namespace, secretName)
+	if apierrors.IsNotFound(err) {
+		_, err = h.secrets.Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: vm.Namespace,
+				Labels: map[string]string{
+					util.LabelVMName: vm.Name,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: kubevirtv1.SchemeGroupVersion.String(),
+						Kind:       kubevirtv1.VirtualMachineGroupVersionKind.Kind,
+						Name:       vm.Name,
+						UID:        vm.UID,
+					},
+				},
+			},
+			Data: data,
+		})
+		return vm, err
+	} else if err != nil {
+		return vm, err
+	}
+
+	if reflect.DeepEqual(secret.Data, data) || (len(secret.Data) == 0 && len(data) == 0) {
+		return vm, nil
+	}
+	toUpdate := secret.DeepCopy()
+	toUpdate.Data = data
+	_, err = h.secrets.Update(toUpdate)
+	return vm, err
+}
+
+// OnKeyPairChanged reports the propagation of the current public key to every VM referencing the key pair.
+func (h *propagationHandler) OnKeyPairChanged(_ string, keyPair *cloudweavv1.KeyPair) (*cloudweavv1.KeyPair, error) {
+	if keyPair == nil || keyPair.DeletionTimestamp != nil {
+		return keyPair, nil
+	}
+
+	vms, err := h.vmCache.GetByIndex(indexeresutil.VMByKeyPairIndex, ref.Construct(keyPair.Namespace, keyPair.Name))
+	if err != nil {
+		return keyPair, err
+	}
+	sort.Slice(vms, func(i, j int) bool {
+		return ref.Construct(vms[i].Namespace, vms[i].Name) < ref.Construct(vms[j].Namespace, vms[j].Name)
+	})
+
+	var statuses []cloudweavv1.KeyPairVirtualMachineStatus
+	configured, synchronized := 0, 0
+	for _, vm := range vms {
+		status, err := h.getVMStatus(keyPair, vm)
+		if err != nil {
+			return keyPair, err
+		}
+		if status.State != cloudweavv1.KeyPairPropagationNotConfigured {
+			configured++
+		}
+		if status.State == cloudweavv1.KeyPairPropagationSynchronized {
+			synchronized++
+		}
+		statuses = append(statuses, status)
+	}
+
+	toUpdate := keyPair.DeepCopy()
+	toUpdate.Status.VirtualMachines = statuses
+	if configured == 0 {
+		toUpdate.Status.Conditions = removeCondition(toUpdate.Status.Conditions, cloudweavv1.KeyPairPropagated)
+	} else {
+		if synchronized == configured {
+			cloudweavv1.KeyPairPropagated.True(toUpdate)
+		} else {
+			cloudweavv1.KeyPairPropagated.False(toUpdate)
+		}
+		cloudweavv1.KeyPairPropagated.Message(toUpdate, fmt.Sprintf("%d/%d virtual machines are synchronized", synchronized, configured))
+	}
+
+	if reflect.DeepEqual(keyPair.Status, toUpdate.Status) {
+		return keyPair, nil
+	}
+	return h.keyPairs.Update(toUpdate)
+}
+
+func (h *propagationHandler) getVMStatus(keyPair *cloudweavv1.KeyPair, vm *kubevirtv1.VirtualMachine) (cloudweavv1.KeyPairVirtualMachineStatus, error) {
+	status := cloudweavv1.KeyPairVirtualMachineStatus{
+		Namespace: vm.Namespace,
+		Name:      vm.Name,
+	}
+	if !isPropagationConfigured(vm) {
+		status.State = cloudweavv1.KeyPairPropagationNotConfigured
+		status.Message = "the key is only applied by cloud-init when the VM is created"
+		return status, nil
+	}
+
+	secret, err := h.secretCache.Get(vm.Namespace, util.KeyPairPropagationSecretName(vm.Name))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return status, err
+	}
+	if secret == nil || string(secret.Data[secretKey(keyPair)]) != keyPair.Spec.PublicKey {
+		status.State = cloudweavv1.KeyPairPropagationPending
+		status.Message = "waiting for the key pair secret of the VM to be updated"
+		return status, nil
+	}
+
+	vmi, err := h.vmiCache.Get(vm.Namespace, vm.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			status.State = cloudweavv1.KeyPairPropagationPending
+			status.Message = "the VM is not running, the key is propagated when it starts"
+			return status, nil
+		}
+		return status, err
+	}
+
+	status.State = cloudweavv1.KeyPairPropagationPending
+	status.Message = "waiting for the guest agent to apply the key"
+	for _, cond := range vmi.Status.Conditions {
+		if cond.Type != kubevirtv1.VirtualMachineInstanceAccessCredentialsSynchronized {
+			continue
+		}
+		if cond.Status == corev1.ConditionTrue {
+			status.State = cloudweavv1.KeyPairPropagationSynchronized
+			status.Message = ""
+		} else if cond.Status == corev1.ConditionFalse {
+			status.State = cloudweavv1.KeyPairPropagationFailed
+			status.Message = cond.Message
+		}
+	}
+	return status, nil
+}
+
+// isPropagationConfigured returns true if the guest agent of the VM propagates the keys of the key pair secret.
+func isPropagationConfigured(vm *kubevirtv1.VirtualMachine) bool {
+	if vm.Spec.Template == nil {
+		return false
+	}
+	secretName := util.KeyPairPropagationSecretName(vm.Name)
+	for _, credential := range vm.Spec.Template.Spec.AccessCredentials {
+		if credential.SSHPublicKey == nil || credential.SSHPublicKey.PropagationMethod.QemuGuestAgent == nil {
+			continue
+		}
+		if source := credential.SSHPublicKey.Source.Secret; source != nil && source.SecretName == secretName {
+			return true
+		}
+	}
+	return false
+}
+
+// secretKey returns the key of the public key in the secret, key pairs of other namespaces may share the same name.
+func secretKey(keyPair *cloudweavv1.KeyPair) string {
+	return fmt.Sprintf("%s.%s", keyPair.Namespace, keyPair.Name)
+}
+
+func removeCondition(conditions []cloudweavv1.Condition, cond condition.Cond) []cloudweavv1.Condition {
+	result := make([]cloudweavv1.Condition, 0, len(conditions))
+	for _, c := range conditions {
+		if c.Type != cond {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// ResolveKeyPairToVMs enqueues the VMs referencing the key pair to update their secrets.
+func (h *propagationHandler) ResolveKeyPairToVMs(namespace, name string, obj runtime.Object) ([]relatedresource.Key, error) {
+	if _, ok := obj.(*cloudweavv1.KeyPair); !ok {
+		return nil, nil
+	}
+	vms, err := h.vmCache.GetByIndex(indexeresutil.VMByKeyPairIndex, ref.Construct(namespace, name))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]relatedresource.Key, 0, len(vms))
+	for _, vm := range vms {
+		keys = append(keys, relatedresource.NewKey(vm.Namespace, vm.Name))
+	}
+	return keys, nil
+}
+
+// ResolveToKeyPairs enqueues the key pairs referenced by the VM when the VM, its instance or its key pair
+// secret changes, to report the propagation state.
+func (h *propagationHandler) ResolveToKeyPairs(namespace, name string, obj runtime.Object) ([]relatedresource.Key, error) {
+	var vm *kubevirtv1.VirtualMachine
+	switch o := obj.(type) {
+	case *kubevirtv1.VirtualMachine:
+		vm = o
+	case *kubevirtv1.VirtualMachineInstance:
+		var err error
+		if vm, err = h.vmCache.Get(namespace, name); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+	case *corev1.Secret:
+		vmName := o.Labels[util.LabelVMName]
+		if vmName == "" || name != util.KeyPairPropagationSecretName(vmName) {
+			return nil, nil
+		}
+		var err error
+		if vm, err = h.vmCache.Get(namespace, vmName); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	keyPairIDs, err := indexeresutil.VMByKeyPair(vm)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]relatedresource.Key, 0, len(keyPairIDs))
+	for _, keyPairID := range keyPairIDs {
+		keyPairNamespace, keyPairName := ref.Parse(keyPairID)
+		keys = append(keys, relatedresource.NewKey(keyPairNamespace, keyPairName))
+	}
+	return keys, nil
+}
@@ -3,19 +3,38 @@ package keypair
 import (
 	"context"
 
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+
 	"github.com/cloudweav/cloudweav/pkg/config"
 )
 
 const (
-	controllerAgentName = "vm-keypair-controller"
+	controllerAgentName            = "vm-keypair-controller"
+	propagationControllerAgentName = "vm-keypair-propagation-controller"
+	vmSecretControllerAgentName    = "vm-keypair-secret-controller"
 )
 
 func Register(ctx context.Context, management *config.Management, _ config.Options) error {
 	keyPairs := management.CloudweavFactory.Cloudweavhci().V1beta1().KeyPair()
+	vms := management.VirtFactory.Kubevirt().V1().VirtualMachine()
+	vmis := management.VirtFactory.Kubevirt().V1().VirtualMachineInstance()
+	secrets := management.CoreFactory.Core().V1().Secret()
 	controller := &Handler{
 		keyPairClient: keyPairs,
 	}
+	propagationController := &propagationHandler{
+		keyPairs:     keyPairs,
+		keyPairCache: keyPairs.Cache(),
+		vmCache:      vms.Cache(),
+		vmiCache:     vmis.Cache(),
+		secrets:      secrets,
+		secretCache:  secrets.Cache(),
+	}
 
 	keyPairs.OnChange(ctx, controllerAgentName, controller.OnKeyPairChanged)
+	keyPairs.OnChange(ctx, propagationControllerAgentName, propagationController.OnKeyPairChanged)
+	vms.OnChange(ctx, vmSecretControllerAgentName, propagationController.OnVMChanged)
+	relatedresource.Watch(ctx, "keypair-propagation-vms", propagationController.ResolveKeyPairToVMs, vms, keyPairs)
+	relatedresource.Watch(ctx, "keypair-propagation-status", propagationController.ResolveToKeyPairs, keyPairs, vms, vmis, secrets)
 	return nil
 }
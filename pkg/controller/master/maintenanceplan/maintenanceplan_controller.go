@@ -0,0 +1,551 @@
+package maintenanceplan
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	lhv1beta2 "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	ctlbatchv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/batch/v1"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/config"
+	ctlnode "github.com/cloudweav/cloudweav/pkg/controller/master/node"
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/scheme"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
+	ctllhv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/longhorn.io/v1beta2"
+	"github.com/cloudweav/cloudweav/pkg/util"
+	"github.com/cloudweav/cloudweav/pkg/util/drainhelper"
+)
+
+const (
+	controllerName = "maintenance-plan-controller"
+
+	// LabelMaintenancePlan is the name of the plan of a hook job
+	LabelMaintenancePlan = "cloudweavhci.io/maintenance-plan"
+	// LabelMaintenancePlanNode is the node of a hook job
+	LabelMaintenancePlanNode = "cloudweavhci.io/maintenance-plan-node"
+	// AnnotationMaintenancePlan is the plan that put a node in maintenance mode
+	AnnotationMaintenancePlan = "cloudweavhci.io/maintenance-plan"
+
+	defaultHookTimeout = time.Hour
+	// the plan is checked periodically for the progress that doesn't trigger it, like the volume rebuilds
+	resyncInterval = 15 * time.Second
+)
+
+var hookBackoffLimit = int32(0)
+
+// Handler moves the nodes of the maintenance plans through maintenance mode one after the other
+type Handler struct {
+	ctx                       context.Context
+	namespace                 string
+	plans                     ctlcloudweavv1.MaintenancePlanClient
+	planController            ctlcloudweavv1.MaintenancePlanController
+	planCache                 ctlcloudweavv1.MaintenancePlanCache
+	nodes                     ctlcorev1.NodeClient
+	nodeCache                 ctlcorev1.NodeCache
+	jobs                      ctlbatchv1.JobClient
+	jobCache                  ctlbatchv1.JobCache
+	lhVolumeCache             ctllhv1.VolumeCache
+	vms                       ctlkubevirtv1.VirtualMachineClient
+	vmCache                   ctlkubevirtv1.VirtualMachineCache
+	virtSubresourceRestClient rest.Interface
+	now                       func() time.Time
+}
+
+// Register registers the maintenance plan controller
+func Register(ctx context.Context, management *config.Management, options config.Options) error {
+	plans := management.CloudweavFactory.Cloudweavhci().V1beta1().MaintenancePlan()
+	nodes := management.CoreFactory.Core().V1().Node()
+	jobs := management.BatchFactory.Batch().V1().Job()
+	lhVolumes := management.LonghornFactory.Longhorn().V1beta2().Volume()
+	vms := management.VirtFactory.Kubevirt().V1().VirtualMachine()
+
+	virtSubresourceConfig := rest.CopyConfig(management.RestConfig)
+	virtSubresourceConfig.GroupVersion = &k8sschema.GroupVersion{Group: "subresources.kubevirt.io", Version: "v1"}
+	virtSubresourceConfig.APIPath = "/apis"
+	virtSubresourceConfig.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	virtSubresourceClient, err := rest.RESTClientFor(virtSubresourceConfig)
+	if err != nil {
+		return err
+	}
+
+	h := &Handler{
+		ctx:                       ctx,
+		namespace:                 options.Namespace,
+		plans:                     plans,
+		planController:            plans,
+		planCache:                 plans.Cache(),
+		nodes:                     nodes,
+		nodeCache:                 nodes.Cache(),
+		jobs:                      jobs,
+		jobCache:                  jobs.Cache(),
+		lhVolumeCache:             lhVolumes.Cache(),
+		vms:                       vms,
+		vmCache:                   vms.Cache(),
+		virtSubresourceRestClient: virtSubresourceClient,
+		now:                       time.Now,
+	}
+
+	plans.OnChange(ctx, controllerName, h.OnChanged)
+	relatedresource.WatchClusterScoped(ctx, controllerName, h.ResolveRunningPlans, plans, nodes, jobs)
+	return nil
+}
+
+// OnChanged starts the nodes of the plan, at most MaxUnavailable at a time, and moves each started node through
+// its phases. The plan fails as soon as one node fails, the nodes already in maintenance mode are left as they are.
+func (h *Handler) OnChanged(_ string, plan *cloudweavv1.MaintenancePlan) (*cloudweavv1.MaintenancePlan, error) {
+	if plan == nil || plan.DeletionTimestamp != nil {
+		return plan, nil
+	}
+	if plan.Status.Phase == cloudweavv1.MaintenancePlanPhaseSucceeded || plan.Status.Phase == cloudweavv1.MaintenancePlanPhaseFailed {
+		return plan, nil
+	}
+
+	toUpdate := plan.DeepCopy()
+	if toUpdate.Status.Phase == "" {
+		nodeNames, err := h.resolvePlanNodes(plan)
+		if err != nil {
+			return plan, err
+		}
+		toUpdate.Status.Phase = cloudweavv1.MaintenancePlanPhaseRunning
+		if len(nodeNames) == 0 {
+			toUpdate.Status.Phase = cloudweavv1.MaintenancePlanPhaseFailed
+			toUpdate.Status.Message = "no node matches the plan"
+		}
+		for _, nodeName := range nodeNames {
+			toUpdate.Status.Nodes = append(toUpdate.Status.Nodes, cloudweavv1.MaintenancePlanNodeStatus{
+				Name:  nodeName,
+				Phase: cloudweavv1.MaintenancePlanNodePhasePending,
+			})
+		}
+	}
+
+	if toUpdate.Status.Phase == cloudweavv1.MaintenancePlanPhaseRunning {
+		if err := h.syncPlan(toUpdate); err != nil {
+			return plan, err
+		}
+		h.planController.EnqueueAfter(plan.Name, resyncInterval)
+	}
+
+	if reflect.DeepEqual(plan.Status, toUpdate.Status) {
+		return plan, nil
+	}
+	return h.plans.UpdateStatus(toUpdate)
+}
+
+func (h *Handler) syncPlan(plan *cloudweavv1.MaintenancePlan) error {
+	active := 0
+	succeeded := 0
+	for i := range plan.Status.Nodes {
+		nodeStatus := &plan.Status.Nodes[i]
+		switch nodeStatus.Phase {
+		case cloudweavv1.MaintenancePlanNodePhasePending:
+			continue
+		case cloudweavv1.MaintenancePlanNodePhaseSucceeded:
+			succeeded++
+			continue
+		case cloudweavv1.MaintenancePlanNodePhaseFailed:
+			plan.Status.Phase = cloudweavv1.MaintenancePlanPhaseFailed
+			plan.Status.Message = fmt.Sprintf("node %s failed: %s", nodeStatus.Name, nodeStatus.Message)
+			return nil
+		}
+
+		if err := h.syncNode(plan, nodeStatus); err != nil {
+			return err
+		}
+		switch nodeStatus.Phase {
+		case cloudweavv1.MaintenancePlanNodePhaseSucceeded:
+			succeeded++
+		case cloudweavv1.MaintenancePlanNodePhaseFailed:
+			plan.Status.Phase = cloudweavv1.MaintenancePlanPhaseFailed
+			plan.Status.Message = fmt.Sprintf("node %s failed: %s", nodeStatus.Name, nodeStatus.Message)
+			return nil
+		default:
+			active++
+		}
+	}
+
+	if succeeded == len(plan.Status.Nodes) {
+		plan.Status.Phase = cloudweavv1.MaintenancePlanPhaseSucceeded
+		plan.Status.Message = ""
+		return nil
+	}
+	if plan.Spec.Paused {
+		plan.Status.Message = "paused"
+		return nil
+	}
+
+	maxUnavailable := plan.Spec.MaxUnavailable
+	if maxUnavailable <= 0 {
+		maxUnavailable = 1
+	}
+	if active >= maxUnavailable {
+		plan.Status.Message = ""
+		return nil
+	}
+
+	// only one node is started per sync, the next one waits until the volumes are healthy again
+	degraded, err := h.degradedVolumes()
+	if err != nil {
+		return err
+	}
+	if len(degraded) > 0 {
+		plan.Status.Message = fmt.Sprintf("waiting for %d degraded volumes to rebuild", len(degraded))
+		return nil
+	}
+	plan.Status.Message = ""
+	for i := range plan.Status.Nodes {
+		if plan.Status.Nodes[i].Phase == cloudweavv1.MaintenancePlanNodePhasePending {
+			plan.Status.Nodes[i].StartTime = h.timestamp()
+			h.setNodePhase(&plan.Status.Nodes[i], cloudweavv1.MaintenancePlanNodePhaseEnteringMaintenance, "")
+			return h.syncNode(plan, &plan.Status.Nodes[i])
+		}
+	}
+	return nil
+}
+
+func (h *Handler) syncNode(plan *cloudweavv1.MaintenancePlan, nodeStatus *cloudweavv1.MaintenancePlanNodeStatus) error {
+	node, err := h.nodeCache.Get(nodeStatus.Name)
+	if apierrors.IsNotFound(err) {
+		h.setNodePhase(nodeStatus, cloudweavv1.MaintenancePlanNodePhaseFailed, "node not found")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	switch nodeStatus.Phase {
+	case cloudweavv1.MaintenancePlanNodePhaseEnteringMaintenance:
+		return h.enterMaintenance(plan, node, nodeStatus)
+	case cloudweavv1.MaintenancePlanNodePhaseRunningHook:
+		return h.syncHook(plan, node, nodeStatus)
+	case cloudweavv1.MaintenancePlanNodePhaseWaitingForReboot:
+		if node.Status.NodeInfo.BootID != nodeStatus.BootID && isNodeReady(node) {
+			h.setNodePhase(nodeStatus, cloudweavv1.MaintenancePlanNodePhaseExitingMaintenance, "")
+			return h.syncNode(plan, nodeStatus)
+		}
+		h.checkHookTimeout(plan, nodeStatus)
+	case cloudweavv1.MaintenancePlanNodePhaseExitingMaintenance:
+		if err := h.exitMaintenance(node); err != nil {
+			return err
+		}
+		h.setNodePhase(nodeStatus, cloudweavv1.MaintenancePlanNodePhaseWaitingForVolumes, "")
+	case cloudweavv1.MaintenancePlanNodePhaseWaitingForVolumes:
+		if !isNodeReady(node) {
+			nodeStatus.Message = "waiting for the node to be ready"
+			return nil
+		}
+		degraded, err := h.degradedVolumes()
+		if err != nil {
+			return err
+		}
+		if len(degraded) > 0 {
+			nodeStatus.Message = fmt.Sprintf("waiting for %d degraded volumes to rebuild", len(degraded))
+			return nil
+		}
+		h.setNodePhase(nodeStatus, cloudweavv1.MaintenancePlanNodePhaseSucceeded, "")
+		nodeStatus.EndTime = h.timestamp()
+	}
+	return nil
+}
+
+// enterMaintenance requests the maintenance mode of the node like the enableMaintenanceMode action, and waits
+// for the maintenance controller to complete it
+func (h *Handler) enterMaintenance(plan *cloudweavv1.MaintenancePlan, node *corev1.Node, nodeStatus *cloudweavv1.MaintenancePlanNodeStatus) error {
+	switch node.Annotations[ctlnode.MaintainStatusAnnotationKey] {
+	case ctlnode.MaintainStatusComplete:
+		if plan.Spec.Hook == nil {
+			h.setNodePhase(nodeStatus, cloudweavv1.MaintenancePlanNodePhaseExitingMaintenance, "")
+		} else {
+			h.setNodePhase(nodeStatus, cloudweavv1.MaintenancePlanNodePhaseRunningHook, "")
+		}
+		return h.syncNode(plan, nodeStatus)
+	case ctlnode.MaintainStatusRunning:
+		nodeStatus.Message = "migrating the VMs"
+		return nil
+	}
+	if _, ok := node.Annotations[drainhelper.DrainAnnotation]; ok {
+		nodeStatus.Message = "draining the node"
+		return nil
+	}
+
+	if err := drainhelper.DrainPossible(h.nodeCache, node); err != nil {
+		h.setNodePhase(nodeStatus, cloudweavv1.MaintenancePlanNodePhaseFailed, err.Error())
+		return nil
+	}
+	toUpdate := node.DeepCopy()
+	if toUpdate.Annotations == nil {
+		toUpdate.Annotations = map[string]string{}
+	}
+	toUpdate.Annotations[drainhelper.DrainAnnotation] = "true"
+	if plan.Spec.Force {
+		toUpdate.Annotations[drainhelper.ForcedDrain] = "true"
+	}
+	toUpdate.Annotations[AnnotationMaintenancePlan] = plan.Name
+	if _, err := h.nodes.Update(toUpdate); err != nil {
+		if apierrors.IsInvalid(err) || apierrors.IsBadRequest(err) {
+			// the node webhook refuses the maintenance mode, e.g. of the last available node
+			h.setNodePhase(nodeStatus, cloudweavv1.MaintenancePlanNodePhaseFailed, err.Error())
+			return nil
+		}
+		return err
+	}
+	nodeStatus.Message = "draining the node"
+	return nil
+}
+
+// syncHook runs the hook job of the plan on the node and waits for it
+func (h *Handler) syncHook(plan *cloudweavv1.MaintenancePlan, node *corev1.Node, nodeStatus *cloudweavv1.MaintenancePlanNodeStatus) error {
+	job, err := h.jobCache.Get(h.namespace, hookJobName(plan.Name, node.Name))
+	if apierrors.IsNotFound(err) {
+		nodeStatus.BootID = node.Status.NodeInfo.BootID
+		if _, err := h.jobs.Create(buildHookJob(h.namespace, plan, node)); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		nodeStatus.Message = "hook job created"
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			if plan.Spec.Hook.WaitForReboot {
+				h.setNodePhase(nodeStatus, cloudweavv1.MaintenancePlanNodePhaseWaitingForReboot, "waiting for the node to reboot")
+			} else {
+				h.setNodePhase(nodeStatus, cloudweavv1.MaintenancePlanNodePhaseExitingMaintenance, "")
+			}
+			return nil
+		case batchv1.JobFailed:
+			h.setNodePhase(nodeStatus, cloudweavv1.MaintenancePlanNodePhaseFailed, fmt.Sprintf("hook job %s/%s failed: %s", job.Namespace, job.Name, cond.Message))
+			return nil
+		}
+	}
+	nodeStatus.Message = "hook job running"
+	h.checkHookTimeout(plan, nodeStatus)
+	return nil
+}
+
+// checkHookTimeout fails the node when its hook phase lasts longer than the hook timeout
+func (h *Handler) checkHookTimeout(plan *cloudweavv1.MaintenancePlan, nodeStatus *cloudweavv1.MaintenancePlanNodeStatus) {
+	timeout := defaultHookTimeout
+	if plan.Spec.Hook != nil && plan.Spec.Hook.TimeoutSeconds > 0 {
+		timeout = time.Duration(plan.Spec.Hook.TimeoutSeconds) * time.Second
+	}
+	phaseStart, err := time.Parse(time.RFC3339, nodeStatus.PhaseStartTime)
+	if err != nil || h.now().Sub(phaseStart) < timeout {
+		return
+	}
+	h.setNodePhase(nodeStatus, cloudweavv1.MaintenancePlanNodePhaseFailed, fmt.Sprintf("%s timed out after %s", nodeStatus.Phase, timeout))
+}
+
+// exitMaintenance disables the maintenance mode of the node like the disableMaintenanceMode action, and restarts the
+// VMs shut down until the maintenance mode is disabled
+func (h *Handler) exitMaintenance(node *corev1.Node) error {
+	toUpdate := node.DeepCopy()
+	drainhelper.DisableMaintenanceMode(toUpdate)
+	delete(toUpdate.Annotations, AnnotationMaintenancePlan)
+	if !reflect.DeepEqual(node, toUpdate) {
+		if _, err := h.nodes.Update(toUpdate); err != nil {
+			return err
+		}
+	}
+	return drainhelper.RestartMaintenanceModeVMs(h.ctx, node.Name, h.vmCache, h.vms, h.virtSubresourceRestClient)
+}
+
+// resolvePlanNodes returns the nodes of the plan, the listed nodes first then the selected ones in name order
+func (h *Handler) resolvePlanNodes(plan *cloudweavv1.MaintenancePlan) ([]string, error) {
+	nodeNames := make([]string, 0, len(plan.Spec.Nodes))
+	seen := make(map[string]bool)
+	for _, nodeName := range plan.Spec.Nodes {
+		if !seen[nodeName] {
+			seen[nodeName] = true
+			nodeNames = append(nodeNames, nodeName)
+		}
+	}
+	if plan.Spec.NodeSelector == nil {
+		return nodeNames, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(plan.Spec.NodeSelector)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := h.nodeCache.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, node := range nodes {
+		if !seen[node.Name] {
+			seen[node.Name] = true
+			nodeNames = append(nodeNames, node.Name)
+		}
+	}
+	return nodeNames, nil
+}
+
+func (h *Handler) degradedVolumes() ([]string, error) {
+	volumes, err := h.lhVolumeCache.List(util.LonghornSystemNamespaceName, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var degraded []string
+	for _, volume := range volumes {
+		if volume.Status.Robustness == lhv1beta2.VolumeRobustnessDegraded {
+			degraded = append(degraded, volume.Name)
+		}
+	}
+	return degraded, nil
+}
+
+func (h *Handler) setNodePhase(nodeStatus *cloudweavv1.MaintenancePlanNodeStatus, phase cloudweavv1.MaintenancePlanNodePhase, message string) {
+	nodeStatus.Phase = phase
+	nodeStatus.Message = message
+	nodeStatus.PhaseStartTime = h.timestamp()
+	if phase == cloudweavv1.MaintenancePlanNodePhaseFailed {
+		nodeStatus.EndTime = nodeStatus.PhaseStartTime
+	}
+}
+
+func (h *Handler) timestamp() string {
+	return h.now().UTC().Format(time.RFC3339)
+}
+
+// ResolveRunningPlans enqueues the running plans when one of their nodes or hook jobs changes
+func (h *Handler) ResolveRunningPlans(_, name string, obj runtime.Object) ([]relatedresource.Key, error) {
+	if job, ok := obj.(*batchv1.Job); ok {
+		if planName := job.Labels[LabelMaintenancePlan]; planName != "" {
+			return []relatedresource.Key{{Name: planName}}, nil
+		}
+		return nil, nil
+	}
+	if _, ok := obj.(*corev1.Node); !ok {
+		return nil, nil
+	}
+
+	plans, err := h.planCache.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var keys []relatedresource.Key
+	for _, plan := range plans {
+		if plan.Status.Phase != cloudweavv1.MaintenancePlanPhaseRunning {
+			continue
+		}
+		for _, nodeStatus := range plan.Status.Nodes {
+			if nodeStatus.Name == name {
+				keys = append(keys, relatedresource.Key{Name: plan.Name})
+				break
+			}
+		}
+	}
+	return keys, nil
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func hookJobName(planName, nodeName string) string {
+	return name.SafeConcatName(planName, "hook", nodeName)
+}
+
+func buildHookJob(namespace string, plan *cloudweavv1.MaintenancePlan, node *corev1.Node) *batchv1.Job {
+	hostPathDirectory := corev1.HostPathDirectory
+	jobLabels := labels.Set{
+		LabelMaintenancePlan:     plan.Name,
+		LabelMaintenancePlanNode: node.Name,
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      hookJobName(plan.Name, node.Name),
+			Namespace: namespace,
+			Labels:    jobLabels,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: cloudweavv1.SchemeGroupVersion.String(),
+					Kind:       "MaintenancePlan",
+					Name:       plan.Name,
+					UID:        plan.UID,
+				},
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &hookBackoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					HostPID:     true,
+					HostNetwork: true,
+					DNSPolicy:   corev1.DNSClusterFirstWithHostNet,
+					NodeName:    node.Name,
+					Tolerations: []corev1.Toleration{
+						{
+							Operator: corev1.TolerationOpExists,
+							Effect:   corev1.TaintEffectNoSchedule,
+						},
+						{
+							Operator: corev1.TolerationOpExists,
+							Effect:   corev1.TaintEffectNoExecute,
+						},
+					},
+					RestartPolicy: corev1.RestartPolicyNever,
+					Volumes: []corev1.Volume{{
+						Name: "host-root",
+						VolumeSource: corev1.VolumeSource{
+							HostPath: &corev1.HostPathVolumeSource{
+								Path: "/", Type: &hostPathDirectory,
+							},
+						},
+					}},
+					Containers: []corev1.Container{{
+						Name:    "hook",
+						Image:   plan.Spec.Hook.Image,
+						Command: plan.Spec.Hook.Command,
+						Args:    plan.Spec.Hook.Args,
+						Env: []corev1.EnvVar{
+							{Name: "HOST_DIR", Value: "/host"},
+							{Name: "NODE_NAME", Value: node.Name},
+						},
+						SecurityContext: &corev1.SecurityContext{
+							Privileged: ptr.To(true),
+						},
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "host-root",
+							MountPath: "/host",
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
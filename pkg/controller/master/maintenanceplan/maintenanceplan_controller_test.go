@@ -0,0 +1,210 @@
+package maintenanceplan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	lhv1beta2 "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlnode "github.com/cloudweav/cloudweav/pkg/controller/master/node"
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/fake"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/util"
+	"github.com/cloudweav/cloudweav/pkg/util/drainhelper"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+)
+
+type fakePlanController struct {
+	ctlcloudweavv1.MaintenancePlanController
+}
+
+func (c *fakePlanController) EnqueueAfter(_ string, _ time.Duration) {}
+
+func newNode(name string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{
+				Type:   corev1.NodeReady,
+				Status: corev1.ConditionTrue,
+			}},
+			NodeInfo: corev1.NodeSystemInfo{BootID: "boot-1"},
+		},
+	}
+}
+
+func newHandler(now time.Time, plan *cloudweavv1.MaintenancePlan, coreObjects []runtime.Object, volumes ...*lhv1beta2.Volume) *Handler {
+	objects := []runtime.Object{plan}
+	for _, volume := range volumes {
+		objects = append(objects, volume)
+	}
+	clientset := fake.NewSimpleClientset(objects...)
+	k8sclientset := k8sfake.NewSimpleClientset(coreObjects...)
+	return &Handler{
+		ctx:            context.TODO(),
+		namespace:      util.CloudweavSystemNamespaceName,
+		plans:          fakeclients.MaintenancePlanClient(clientset.CloudweavhciV1beta1().MaintenancePlans),
+		planController: &fakePlanController{},
+		planCache:      fakeclients.MaintenancePlanCache(clientset.CloudweavhciV1beta1().MaintenancePlans),
+		nodes:          fakeclients.NodeClient(k8sclientset.CoreV1().Nodes),
+		nodeCache:      fakeclients.NodeCache(k8sclientset.CoreV1().Nodes),
+		jobs:           fakeclients.JobClient(k8sclientset.BatchV1().Jobs),
+		jobCache:       fakeclients.JobCache(k8sclientset.BatchV1().Jobs),
+		lhVolumeCache:  fakeclients.LonghornVolumeCache(clientset.LonghornV1beta2().Volumes),
+		vms:            fakeclients.VirtualMachineClient(clientset.KubevirtV1().VirtualMachines),
+		vmCache:        fakeclients.VirtualMachineCache(clientset.KubevirtV1().VirtualMachines),
+		now:            func() time.Time { return now },
+	}
+}
+
+func setMaintainStatus(t *testing.T, h *Handler, nodeName, status string) {
+	node, err := h.nodeCache.Get(nodeName)
+	require.NoError(t, err)
+	node = node.DeepCopy()
+	node.Annotations[ctlnode.MaintainStatusAnnotationKey] = status
+	node.Spec.Unschedulable = true
+	_, err = h.nodes.Update(node)
+	require.NoError(t, err)
+}
+
+func nodePhases(plan *cloudweavv1.MaintenancePlan) map[string]cloudweavv1.MaintenancePlanNodePhase {
+	phases := make(map[string]cloudweavv1.MaintenancePlanNodePhase, len(plan.Status.Nodes))
+	for _, nodeStatus := range plan.Status.Nodes {
+		phases[nodeStatus.Name] = nodeStatus.Phase
+	}
+	return phases
+}
+
+func Test_OnChanged_RollingMaintenance(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	plan := &cloudweavv1.MaintenancePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "firmware"},
+		Spec: cloudweavv1.MaintenancePlanSpec{
+			Nodes:          []string{"node-2"},
+			NodeSelector:   &metav1.LabelSelector{MatchLabels: map[string]string{"rack": "a"}},
+			MaxUnavailable: 1,
+		},
+	}
+	h := newHandler(now, plan, []runtime.Object{
+		newNode("node-1", map[string]string{"rack": "a"}),
+		newNode("node-2", nil),
+		newNode("node-3", map[string]string{"rack": "b"}),
+	})
+
+	plan, err := h.OnChanged(plan.Name, plan)
+	require.NoError(t, err)
+	assert.Equal(t, cloudweavv1.MaintenancePlanPhaseRunning, plan.Status.Phase)
+	require.Len(t, plan.Status.Nodes, 2)
+	assert.Equal(t, "node-2", plan.Status.Nodes[0].Name, "the listed nodes go first")
+	assert.Equal(t, map[string]cloudweavv1.MaintenancePlanNodePhase{
+		"node-2": cloudweavv1.MaintenancePlanNodePhaseEnteringMaintenance,
+		"node-1": cloudweavv1.MaintenancePlanNodePhasePending,
+	}, nodePhases(plan))
+	node, err := h.nodeCache.Get("node-2")
+	require.NoError(t, err)
+	assert.Equal(t, "true", node.Annotations[drainhelper.DrainAnnotation])
+	assert.Equal(t, plan.Name, node.Annotations[AnnotationMaintenancePlan])
+
+	// the node exits maintenance mode once it's drained
+	setMaintainStatus(t, h, "node-2", ctlnode.MaintainStatusComplete)
+	plan, err = h.OnChanged(plan.Name, plan)
+	require.NoError(t, err)
+	assert.Equal(t, cloudweavv1.MaintenancePlanNodePhaseWaitingForVolumes, plan.Status.Nodes[0].Phase)
+	node, err = h.nodeCache.Get("node-2")
+	require.NoError(t, err)
+	assert.False(t, node.Spec.Unschedulable)
+	assert.NotContains(t, node.Annotations, drainhelper.DrainAnnotation)
+	assert.NotContains(t, node.Annotations, ctlnode.MaintainStatusAnnotationKey)
+
+	plan, err = h.OnChanged(plan.Name, plan)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]cloudweavv1.MaintenancePlanNodePhase{
+		"node-2": cloudweavv1.MaintenancePlanNodePhaseSucceeded,
+		"node-1": cloudweavv1.MaintenancePlanNodePhaseEnteringMaintenance,
+	}, nodePhases(plan))
+
+	setMaintainStatus(t, h, "node-1", ctlnode.MaintainStatusComplete)
+	plan, err = h.OnChanged(plan.Name, plan)
+	require.NoError(t, err)
+	plan, err = h.OnChanged(plan.Name, plan)
+	require.NoError(t, err)
+	assert.Equal(t, cloudweavv1.MaintenancePlanPhaseSucceeded, plan.Status.Phase)
+}
+
+func Test_OnChanged_WaitForDegradedVolumes(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	plan := &cloudweavv1.MaintenancePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "firmware"},
+		Spec: cloudweavv1.MaintenancePlanSpec{
+			Nodes: []string{"node-1"},
+		},
+	}
+	volume := &lhv1beta2.Volume{
+		ObjectMeta: metav1.ObjectMeta{Namespace: util.LonghornSystemNamespaceName, Name: "pvc-1"},
+		Status:     lhv1beta2.VolumeStatus{Robustness: lhv1beta2.VolumeRobustnessDegraded},
+	}
+	h := newHandler(now, plan, []runtime.Object{newNode("node-1", nil)}, volume)
+
+	plan, err := h.OnChanged(plan.Name, plan)
+	require.NoError(t, err)
+	assert.Equal(t, cloudweavv1.MaintenancePlanNodePhasePending, plan.Status.Nodes[0].Phase)
+	assert.Equal(t, "waiting for 1 degraded volumes to rebuild", plan.Status.Message)
+}
+
+func Test_OnChanged_Hook(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	plan := &cloudweavv1.MaintenancePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "firmware"},
+		Spec: cloudweavv1.MaintenancePlanSpec{
+			Nodes: []string{"node-1", "node-2"},
+			Hook: &cloudweavv1.MaintenancePlanHook{
+				Image:          "firmware:v2",
+				WaitForReboot:  true,
+				TimeoutSeconds: 600,
+			},
+		},
+	}
+	h := newHandler(now, plan, []runtime.Object{newNode("node-1", nil), newNode("node-2", nil)})
+
+	plan, err := h.OnChanged(plan.Name, plan)
+	require.NoError(t, err)
+	setMaintainStatus(t, h, "node-1", ctlnode.MaintainStatusComplete)
+	plan, err = h.OnChanged(plan.Name, plan)
+	require.NoError(t, err)
+	assert.Equal(t, cloudweavv1.MaintenancePlanNodePhaseRunningHook, plan.Status.Nodes[0].Phase)
+	assert.Equal(t, "boot-1", plan.Status.Nodes[0].BootID)
+	job, err := h.jobCache.Get(h.namespace, hookJobName(plan.Name, "node-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "node-1", job.Spec.Template.Spec.NodeName)
+	assert.Equal(t, "firmware:v2", job.Spec.Template.Spec.Containers[0].Image)
+
+	job = job.DeepCopy()
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	_, err = h.jobs.Update(job)
+	require.NoError(t, err)
+	plan, err = h.OnChanged(plan.Name, plan)
+	require.NoError(t, err)
+	assert.Equal(t, cloudweavv1.MaintenancePlanNodePhaseWaitingForReboot, plan.Status.Nodes[0].Phase)
+
+	// the node doesn't reboot in time, the plan stops
+	h.now = func() time.Time { return now.Add(11 * time.Minute) }
+	plan, err = h.OnChanged(plan.Name, plan)
+	require.NoError(t, err)
+	assert.Equal(t, cloudweavv1.MaintenancePlanNodePhaseFailed, plan.Status.Nodes[0].Phase)
+	plan, err = h.OnChanged(plan.Name, plan)
+	require.NoError(t, err)
+	assert.Equal(t, cloudweavv1.MaintenancePlanPhaseFailed, plan.Status.Phase)
+	assert.Equal(t, cloudweavv1.MaintenancePlanNodePhasePending, plan.Status.Nodes[1].Phase)
+}
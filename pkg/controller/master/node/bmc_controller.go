@@ -0,0 +1,137 @@
+package node
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/config"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/util/bmc"
+)
+
+const (
+	bmcControllerName = "node-bmc-controller"
+
+	defaultBMCPollInterval = 60 * time.Second
+	bmcPollTimeout         = 60 * time.Second
+)
+
+// nodeBMCHandler polls the power state and the health sensors of the node BMCs
+type nodeBMCHandler struct {
+	nodeBMCs          ctlcloudweavv1.NodeBMCClient
+	nodeBMCController ctlcloudweavv1.NodeBMCController
+	secretCache       ctlcorev1.SecretCache
+	newClient         func(spec cloudweavv1.NodeBMCSpec, secret *corev1.Secret) (bmc.Client, error)
+	now               func() time.Time
+}
+
+// BMCRegister registers the node BMC controller
+func BMCRegister(ctx context.Context, management *config.Management, _ config.Options) error {
+	nodeBMCs := management.CloudweavFactory.Cloudweavhci().V1beta1().NodeBMC()
+	secrets := management.CoreFactory.Core().V1().Secret()
+	handler := &nodeBMCHandler{
+		nodeBMCs:          nodeBMCs,
+		nodeBMCController: nodeBMCs,
+		secretCache:       secrets.Cache(),
+		newClient:         bmc.NewClient,
+		now:               time.Now,
+	}
+
+	nodeBMCs.OnChange(ctx, bmcControllerName, handler.OnChanged)
+	relatedresource.WatchClusterScoped(ctx, "node-bmc-credentials", handler.ResolveSecret, nodeBMCs, secrets)
+	return nil
+}
+
+// OnChanged reads the BMC status once per poll interval. The status update triggers the handler again,
+// so the BMC is only read when the spec changed or the last poll is older than the interval.
+func (h *nodeBMCHandler) OnChanged(_ string, nodeBMC *cloudweavv1.NodeBMC) (*cloudweavv1.NodeBMC, error) {
+	if nodeBMC == nil || nodeBMC.DeletionTimestamp != nil {
+		return nodeBMC, nil
+	}
+
+	interval := pollInterval(nodeBMC)
+	now := h.now()
+	if nodeBMC.Status.ObservedGeneration == nodeBMC.Generation && nodeBMC.Status.LastPollTime != "" {
+		if lastPoll, err := time.Parse(time.RFC3339, nodeBMC.Status.LastPollTime); err == nil && now.Sub(lastPoll) < interval {
+			h.nodeBMCController.EnqueueAfter(nodeBMC.Name, interval-now.Sub(lastPoll))
+			return nodeBMC, nil
+		}
+	}
+
+	toUpdate := nodeBMC.DeepCopy()
+	toUpdate.Status.ObservedGeneration = nodeBMC.Generation
+	toUpdate.Status.LastPollTime = now.UTC().Format(time.RFC3339)
+	status, err := h.poll(nodeBMC)
+	if err != nil {
+		cloudweavv1.NodeBMCReachable.False(toUpdate)
+		cloudweavv1.NodeBMCReachable.Message(toUpdate, err.Error())
+		toUpdate.Status.PowerState = ""
+		toUpdate.Status.Health = ""
+		toUpdate.Status.Sensors = nil
+	} else {
+		cloudweavv1.NodeBMCReachable.True(toUpdate)
+		cloudweavv1.NodeBMCReachable.Message(toUpdate, "")
+		toUpdate.Status.PowerState = status.PowerState
+		toUpdate.Status.Health = status.Health
+		toUpdate.Status.Sensors = status.Sensors
+	}
+
+	h.nodeBMCController.EnqueueAfter(nodeBMC.Name, interval)
+	if reflect.DeepEqual(nodeBMC.Status, toUpdate.Status) {
+		return nodeBMC, nil
+	}
+	return h.nodeBMCs.Update(toUpdate)
+}
+
+func (h *nodeBMCHandler) poll(nodeBMC *cloudweavv1.NodeBMC) (*bmc.Status, error) {
+	client, err := NewBMCClient(h.secretCache, nodeBMC, h.newClient)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), bmcPollTimeout)
+	defer cancel()
+	return client.Status(ctx)
+}
+
+// NewBMCClient returns the client of the node BMC with its credentials
+func NewBMCClient(secretCache ctlcorev1.SecretCache, nodeBMC *cloudweavv1.NodeBMC,
+	newClient func(spec cloudweavv1.NodeBMCSpec, secret *corev1.Secret) (bmc.Client, error)) (bmc.Client, error) {
+	secret, err := secretCache.Get(nodeBMC.Spec.CredentialsSecret.Namespace, nodeBMC.Spec.CredentialsSecret.Name)
+	if err != nil {
+		return nil, err
+	}
+	return newClient(nodeBMC.Spec, secret)
+}
+
+func pollInterval(nodeBMC *cloudweavv1.NodeBMC) time.Duration {
+	if nodeBMC.Spec.PollIntervalSeconds <= 0 {
+		return defaultBMCPollInterval
+	}
+	return time.Duration(nodeBMC.Spec.PollIntervalSeconds) * time.Second
+}
+
+// ResolveSecret enqueues the node BMCs using the secret as credentials, to poll them with the new credentials
+func (h *nodeBMCHandler) ResolveSecret(namespace, name string, obj runtime.Object) ([]relatedresource.Key, error) {
+	if _, ok := obj.(*corev1.Secret); !ok {
+		return nil, nil
+	}
+	nodeBMCs, err := h.nodeBMCController.Cache().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var keys []relatedresource.Key
+	for _, nodeBMC := range nodeBMCs {
+		if nodeBMC.Spec.CredentialsSecret.Namespace == namespace && nodeBMC.Spec.CredentialsSecret.Name == name {
+			keys = append(keys, relatedresource.Key{Name: nodeBMC.Name})
+		}
+	}
+	return keys, nil
+}
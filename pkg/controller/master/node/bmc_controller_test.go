@@ -0,0 +1,173 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/fake"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/util"
+	"github.com/cloudweav/cloudweav/pkg/util/bmc"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+)
+
+// fakeNodeBMCController lists the node BMCs of a fake clientset and records the requeue delays
+type fakeNodeBMCController struct {
+	ctlcloudweavv1.NodeBMCController
+	clientset *fake.Clientset
+	enqueued  map[string]time.Duration
+}
+
+func (c *fakeNodeBMCController) Cache() generic.NonNamespacedCacheInterface[*cloudweavv1.NodeBMC] {
+	return fakeclients.NodeBMCCache(c.clientset.CloudweavhciV1beta1().NodeBMCs)
+}
+
+func (c *fakeNodeBMCController) EnqueueAfter(name string, duration time.Duration) {
+	c.enqueued[name] = duration
+}
+
+func newTestNodeBMC(name string, status cloudweavv1.NodeBMCStatus) *cloudweavv1.NodeBMC {
+	return &cloudweavv1.NodeBMC{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1},
+		Spec: cloudweavv1.NodeBMCSpec{
+			Protocol:            cloudweavv1.NodeBMCProtocolRedfish,
+			Address:             "https://10.0.0.5",
+			CredentialsSecret:   corev1.SecretReference{Namespace: util.CloudweavSystemNamespaceName, Name: name + "-bmc"},
+			PollIntervalSeconds: 30,
+		},
+		Status: status,
+	}
+}
+
+func TestNodeBMCHandler_OnChanged(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	recentPoll := now.Add(-10 * time.Second).Format(time.RFC3339)
+
+	var testCases = []struct {
+		name             string
+		nodeBMC          *cloudweavv1.NodeBMC
+		secret           bool
+		expectedPolls    int
+		expectedEnqueue  time.Duration
+		expectedMessage  string
+		expectReachable  bool
+		expectStatusSkip bool
+	}{
+		{
+			name:            "poll a new BMC",
+			nodeBMC:         newTestNodeBMC("node-1", cloudweavv1.NodeBMCStatus{}),
+			secret:          true,
+			expectedPolls:   1,
+			expectedEnqueue: 30 * time.Second,
+			expectReachable: true,
+		},
+		{
+			name: "wait for the poll interval",
+			nodeBMC: newTestNodeBMC("node-1", cloudweavv1.NodeBMCStatus{
+				ObservedGeneration: 1,
+				LastPollTime:       recentPoll,
+				PowerState:         bmc.PowerStateOn,
+			}),
+			secret:           true,
+			expectedPolls:    0,
+			expectedEnqueue:  20 * time.Second,
+			expectStatusSkip: true,
+		},
+		{
+			name: "poll again when the spec changed",
+			nodeBMC: newTestNodeBMC("node-1", cloudweavv1.NodeBMCStatus{
+				ObservedGeneration: 0,
+				LastPollTime:       recentPoll,
+			}),
+			secret:          true,
+			expectedPolls:   1,
+			expectedEnqueue: 30 * time.Second,
+			expectReachable: true,
+		},
+		{
+			name: "BMC is unreachable without its credentials",
+			nodeBMC: newTestNodeBMC("node-1", cloudweavv1.NodeBMCStatus{
+				PowerState: bmc.PowerStateOn,
+				Health:     bmc.HealthOK,
+			}),
+			secret:          false,
+			expectedPolls:   0,
+			expectedEnqueue: 30 * time.Second,
+			expectedMessage: `secrets "node-1-bmc" not found`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(tc.nodeBMC)
+			k8sclientset := k8sfake.NewSimpleClientset()
+			if tc.secret {
+				_, err := k8sclientset.CoreV1().Secrets(util.CloudweavSystemNamespaceName).Create(context.TODO(), &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: util.CloudweavSystemNamespaceName, Name: "node-1-bmc"},
+				}, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			polls := 0
+			controller := &fakeNodeBMCController{clientset: clientset, enqueued: map[string]time.Duration{}}
+			h := &nodeBMCHandler{
+				nodeBMCs:          fakeclients.NodeBMCClient(clientset.CloudweavhciV1beta1().NodeBMCs),
+				nodeBMCController: controller,
+				secretCache:       fakeclients.SecretCache(k8sclientset.CoreV1().Secrets),
+				newClient: func(_ cloudweavv1.NodeBMCSpec, _ *corev1.Secret) (bmc.Client, error) {
+					polls++
+					return &fakeBMCClient{powerState: bmc.PowerStateOn}, nil
+				},
+				now: func() time.Time { return now },
+			}
+
+			result, err := h.OnChanged(tc.nodeBMC.Name, tc.nodeBMC)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPolls, polls)
+			assert.Equal(t, tc.expectedEnqueue, controller.enqueued[tc.nodeBMC.Name])
+			if tc.expectStatusSkip {
+				assert.Equal(t, tc.nodeBMC, result)
+				return
+			}
+
+			assert.Equal(t, int64(1), result.Status.ObservedGeneration)
+			assert.Equal(t, now.Format(time.RFC3339), result.Status.LastPollTime)
+			assert.Equal(t, tc.expectReachable, cloudweavv1.NodeBMCReachable.IsTrue(result))
+			assert.Equal(t, tc.expectedMessage, cloudweavv1.NodeBMCReachable.GetMessage(result))
+			if tc.expectReachable {
+				assert.Equal(t, bmc.PowerStateOn, result.Status.PowerState)
+			} else {
+				assert.Empty(t, result.Status.PowerState, "stale power state is cleared")
+				assert.Empty(t, result.Status.Health)
+			}
+		})
+	}
+}
+
+func TestNodeBMCHandler_ResolveSecret(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		newTestNodeBMC("node-1", cloudweavv1.NodeBMCStatus{}),
+		newTestNodeBMC("node-2", cloudweavv1.NodeBMCStatus{}),
+	)
+	h := &nodeBMCHandler{
+		nodeBMCController: &fakeNodeBMCController{clientset: clientset},
+	}
+
+	keys, err := h.ResolveSecret(util.CloudweavSystemNamespaceName, "node-2-bmc", &corev1.Secret{})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "node-2", keys[0].Name)
+
+	keys, err = h.ResolveSecret("default", "node-2-bmc", &corev1.Secret{})
+	require.NoError(t, err)
+	assert.Empty(t, keys, "secrets in other namespaces are not used")
+}
@@ -0,0 +1,242 @@
+package node
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// RoleRequestAnnotationKey requests the promote controller to promote or demote the node explicitly
+	RoleRequestAnnotationKey = CloudweavLabelAnnotationPrefix + "role-request"
+	RoleRequestPromote       = "promote"
+	RoleRequestDemote        = "demote"
+
+	CloudweavDemoteNodeLabelKey         = CloudweavLabelAnnotationPrefix + "demote-node"
+	CloudweavDemoteStatusAnnotationKey  = CloudweavLabelAnnotationPrefix + "demote-status"
+	CloudweavDemoteAttemptAnnotationKey = CloudweavLabelAnnotationPrefix + "demote-attempt"
+
+	// the node is put into maintenance mode before it leaves etcd, its VMs are migrated away
+	DemoteStatusDraining = "draining"
+
+	// a pinned node is promoted first and never demoted, an excluded node is never promoted.
+	// The policies are kept in the role labels the installer sets.
+	ControlPlanePolicyPinned   = "pinned"
+	ControlPlanePolicyExcluded = "excluded"
+	ControlPlanePolicyAuto     = "auto"
+
+	EtcdNamespace = "kube-system"
+)
+
+// EtcdPodSelector selects the etcd static pods of the control plane nodes
+var EtcdPodSelector = labels.SelectorFromSet(labels.Set{
+	"component": "etcd",
+	"tier":      "control-plane",
+})
+
+// EtcdMember is a control plane node running etcd
+type EtcdMember struct {
+	Node string `json:"node"`
+	// Witness is true if the node only runs etcd
+	Witness      bool   `json:"witness"`
+	Healthy      bool   `json:"healthy"`
+	Policy       string `json:"policy"`
+	DemoteStatus string `json:"demoteStatus,omitempty"`
+}
+
+// ControlPlaneStatus is the etcd membership and the quorum health of the cluster
+type ControlPlaneStatus struct {
+	Members []EtcdMember `json:"members"`
+	// Quorum is the number of healthy members etcd needs to accept writes
+	Quorum         int  `json:"quorum"`
+	HealthyMembers int  `json:"healthyMembers"`
+	QuorumHealthy  bool `json:"quorumHealthy"`
+	// FailureTolerance is the number of members which can still fail without losing the quorum
+	FailureTolerance int `json:"failureTolerance"`
+	// Promoting and Demoting are the nodes whose promotion or demotion is not finished
+	Promoting []string `json:"promoting,omitempty"`
+	Demoting  []string `json:"demoting,omitempty"`
+}
+
+// GetControlPlanePolicy returns the control plane policy of the node from its role labels
+func GetControlPlanePolicy(node *corev1.Node) string {
+	if _, found := node.Labels[CloudweavMgmtNodeLabelKey]; found {
+		return ControlPlanePolicyPinned
+	}
+	if _, found := node.Labels[CloudweavWorkerNodeLabelKey]; found {
+		return ControlPlanePolicyExcluded
+	}
+	return ControlPlanePolicyAuto
+}
+
+// SetControlPlanePolicy sets the role labels of the node for the control plane policy
+func SetControlPlanePolicy(node *corev1.Node, policy string) error {
+	if node.Labels == nil {
+		node.Labels = make(map[string]string)
+	}
+	switch policy {
+	case ControlPlanePolicyPinned:
+		delete(node.Labels, CloudweavWorkerNodeLabelKey)
+		node.Labels[CloudweavMgmtNodeLabelKey] = "true"
+	case ControlPlanePolicyExcluded:
+		delete(node.Labels, CloudweavMgmtNodeLabelKey)
+		node.Labels[CloudweavWorkerNodeLabelKey] = "true"
+	case ControlPlanePolicyAuto:
+		delete(node.Labels, CloudweavMgmtNodeLabelKey)
+		delete(node.Labels, CloudweavWorkerNodeLabelKey)
+	default:
+		return fmt.Errorf("invalid control plane policy %q, must be one of %s, %s and %s", policy,
+			ControlPlanePolicyPinned, ControlPlanePolicyExcluded, ControlPlanePolicyAuto)
+	}
+	return nil
+}
+
+// GetControlPlaneStatus returns the etcd membership of the nodes, a member is healthy if its node is ready and its etcd pod is ready
+func GetControlPlaneStatus(nodes []*corev1.Node, etcdPods []*corev1.Pod) *ControlPlaneStatus {
+	readyPods := make(map[string]bool, len(etcdPods))
+	for _, pod := range etcdPods {
+		readyPods[pod.Spec.NodeName] = readyPods[pod.Spec.NodeName] || isPodReady(pod)
+	}
+
+	status := &ControlPlaneStatus{Members: []EtcdMember{}}
+	for _, node := range nodes {
+		if isPromoteStatusIn(node, PromoteStatusRunning) {
+			status.Promoting = append(status.Promoting, node.Name)
+		}
+		if IsDemoting(node) {
+			status.Demoting = append(status.Demoting, node.Name)
+		}
+		if !IsManagementRole(node) {
+			continue
+		}
+
+		member := EtcdMember{
+			Node:         node.Name,
+			Witness:      isEtcdOnly(node),
+			Healthy:      isNodeReady(node) && readyPods[node.Name],
+			Policy:       GetControlPlanePolicy(node),
+			DemoteStatus: node.Annotations[CloudweavDemoteStatusAnnotationKey],
+		}
+		if member.Healthy {
+			status.HealthyMembers++
+		}
+		status.Members = append(status.Members, member)
+	}
+	sort.Slice(status.Members, func(i, j int) bool {
+		return status.Members[i].Node < status.Members[j].Node
+	})
+
+	status.Quorum = len(status.Members)/2 + 1
+	status.QuorumHealthy = status.HealthyMembers >= status.Quorum
+	status.FailureTolerance = max(status.HealthyMembers-status.Quorum, 0)
+	return status
+}
+
+// CheckDemotion returns an error if the node can't be removed from etcd without losing the quorum
+func CheckDemotion(status *ControlPlaneStatus, node *corev1.Node) error {
+	var target *EtcdMember
+	for i := range status.Members {
+		if status.Members[i].Node == node.Name {
+			target = &status.Members[i]
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("node %s is not an etcd member", node.Name)
+	}
+	if target.Policy == ControlPlanePolicyPinned {
+		return fmt.Errorf("node %s is pinned to the control plane", node.Name)
+	}
+	if others := otherNodes(append(status.Promoting, status.Demoting...), node.Name); len(others) > 0 {
+		return fmt.Errorf("the control plane is changing on nodes %s", strings.Join(others, ", "))
+	}
+
+	remaining := len(status.Members) - 1
+	if remaining == 0 {
+		return fmt.Errorf("node %s is the last etcd member", node.Name)
+	}
+	healthyRemaining := status.HealthyMembers
+	if target.Healthy {
+		healthyRemaining--
+	}
+	if healthyRemaining < remaining/2+1 {
+		return fmt.Errorf("removing node %s would lose the etcd quorum, only %d of the other %d members are healthy",
+			node.Name, healthyRemaining, remaining)
+	}
+	return nil
+}
+
+// CheckPromotion returns an error if the node can't be requested to join the control plane
+func CheckPromotion(nodes []*corev1.Node, node *corev1.Node) error {
+	if IsManagementRole(node) {
+		return fmt.Errorf("node %s is already in the control plane", node.Name)
+	}
+	if GetControlPlanePolicy(node) == ControlPlanePolicyExcluded {
+		return fmt.Errorf("node %s is excluded from the control plane", node.Name)
+	}
+	if !isCloudweavNode(node) {
+		return fmt.Errorf("node %s is not managed by cloudweav", node.Name)
+	}
+	if !isHealthyNode(node) {
+		return fmt.Errorf("node %s is not healthy", node.Name)
+	}
+	for _, n := range nodes {
+		if n.Name != node.Name && n.Annotations[RoleRequestAnnotationKey] == RoleRequestPromote && !IsManagementRole(n) {
+			return fmt.Errorf("the promotion of node %s is already requested", n.Name)
+		}
+	}
+	return nil
+}
+
+// IsDemoting returns whether the demotion of the node is requested or not finished, the demote annotations are
+// removed once the node left the control plane
+func IsDemoting(node *corev1.Node) bool {
+	return node.Annotations[RoleRequestAnnotationKey] == RoleRequestDemote || node.Annotations[CloudweavDemoteStatusAnnotationKey] != ""
+}
+
+func isDemoteStatusIn(node *corev1.Node, statuses ...string) bool {
+	status, ok := node.Annotations[CloudweavDemoteStatusAnnotationKey]
+	if !ok {
+		return false
+	}
+
+	for _, s := range statuses {
+		if status == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isEtcdOnly returns whether the node is a promoted witness node, which runs etcd without the other control plane components
+func isEtcdOnly(node *corev1.Node) bool {
+	return node.Labels[KubeEtcdNodeLabelKey] == "true" &&
+		node.Labels[KubeMasterNodeLabelKey] != "true" && node.Labels[KubeControlPlaneNodeLabelKey] != "true"
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	cond := getNodeCondition(node.Status.Conditions, corev1.NodeReady)
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func otherNodes(nodeNames []string, name string) []string {
+	var others []string
+	for _, n := range nodeNames {
+		if n != name {
+			others = append(others, n)
+		}
+	}
+	return others
+}
@@ -0,0 +1,136 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newEtcdMemberNode(name string, ready bool, labels map[string]string) *corev1.Node {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	nodeLabels := map[string]string{KubeControlPlaneNodeLabelKey: "true", KubeEtcdNodeLabelKey: "true"}
+	for k, v := range labels {
+		nodeLabels[k] = v
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels, Annotations: map[string]string{}},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func newEtcdPod(nodeName string, ready bool) *corev1.Pod {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: EtcdNamespace, Name: "etcd-" + nodeName},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestGetControlPlaneStatus(t *testing.T) {
+	witness := newEtcdMemberNode("node-3", true, nil)
+	delete(witness.Labels, KubeControlPlaneNodeLabelKey)
+	demoted := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-4", Labels: map[string]string{CloudweavWorkerNodeLabelKey: "true"}}}
+	worker := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-5"}}
+	nodes := []*corev1.Node{
+		newEtcdMemberNode("node-2", true, map[string]string{CloudweavMgmtNodeLabelKey: "true"}),
+		newEtcdMemberNode("node-1", false, nil),
+		witness,
+		demoted,
+		worker,
+	}
+	pods := []*corev1.Pod{newEtcdPod("node-1", true), newEtcdPod("node-2", true), newEtcdPod("node-3", false)}
+
+	status := GetControlPlaneStatus(nodes, pods)
+	assert.Equal(t, &ControlPlaneStatus{
+		Members: []EtcdMember{
+			{Node: "node-1", Policy: ControlPlanePolicyAuto},
+			{Node: "node-2", Healthy: true, Policy: ControlPlanePolicyPinned},
+			{Node: "node-3", Witness: true, Policy: ControlPlanePolicyAuto},
+		},
+		Quorum:         2,
+		HealthyMembers: 1,
+		QuorumHealthy:  false,
+	}, status)
+}
+
+func TestCheckDemotion(t *testing.T) {
+	healthy := []*corev1.Pod{newEtcdPod("node-1", true), newEtcdPod("node-2", true), newEtcdPod("node-3", true)}
+	failed := newEtcdMemberNode("node-3", false, nil)
+	pinned := newEtcdMemberNode("node-1", true, map[string]string{CloudweavMgmtNodeLabelKey: "true"})
+	demoting := newEtcdMemberNode("node-2", true, nil)
+	demoting.Annotations[CloudweavDemoteStatusAnnotationKey] = PromoteStatusRunning
+
+	tests := []struct {
+		name   string
+		nodes  []*corev1.Node
+		pods   []*corev1.Pod
+		demote *corev1.Node
+		errMsg string
+	}{
+		{
+			name:   "replace a failed member of three",
+			nodes:  []*corev1.Node{newEtcdMemberNode("node-1", true, nil), newEtcdMemberNode("node-2", true, nil), failed},
+			pods:   healthy,
+			demote: failed,
+		},
+		{
+			name:   "demote a healthy member while another member failed",
+			nodes:  []*corev1.Node{newEtcdMemberNode("node-1", true, nil), newEtcdMemberNode("node-2", true, nil), failed},
+			pods:   healthy,
+			demote: newEtcdMemberNode("node-1", true, nil),
+			errMsg: "removing node node-1 would lose the etcd quorum, only 1 of the other 2 members are healthy",
+		},
+		{
+			name:   "demote a pinned member",
+			nodes:  []*corev1.Node{pinned, newEtcdMemberNode("node-2", true, nil), newEtcdMemberNode("node-3", true, nil)},
+			pods:   healthy,
+			demote: pinned,
+			errMsg: "node node-1 is pinned to the control plane",
+		},
+		{
+			name:   "demote while another member is being demoted",
+			nodes:  []*corev1.Node{newEtcdMemberNode("node-1", true, nil), demoting, newEtcdMemberNode("node-3", true, nil)},
+			pods:   healthy,
+			demote: newEtcdMemberNode("node-1", true, nil),
+			errMsg: "the control plane is changing on nodes node-2",
+		},
+		{
+			name:   "demote the last member",
+			nodes:  []*corev1.Node{newEtcdMemberNode("node-1", true, nil)},
+			pods:   healthy,
+			demote: newEtcdMemberNode("node-1", true, nil),
+			errMsg: "node node-1 is the last etcd member",
+		},
+		{
+			name:   "demote a worker",
+			nodes:  []*corev1.Node{newEtcdMemberNode("node-1", true, nil)},
+			pods:   healthy,
+			demote: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-4"}},
+			errMsg: "node node-4 is not an etcd member",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckDemotion(GetControlPlaneStatus(tc.nodes, tc.pods), tc.demote)
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.errMsg)
+		})
+	}
+}
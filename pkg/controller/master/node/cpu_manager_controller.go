@@ -53,9 +53,11 @@ type CPUManagerPolicy string
 type CPUManagerStatus string
 
 type CPUManagerUpdateStatus struct {
-	Policy  CPUManagerPolicy `json:"policy"`
-	Status  CPUManagerStatus `json:"status"`
-	JobName string           `json:"jobName,omitempty"`
+	Policy CPUManagerPolicy `json:"policy"`
+	// NUMAAware also enables the static memory manager and the single-numa-node topology manager with the static policy
+	NUMAAware bool             `json:"numaAware,omitempty"`
+	Status    CPUManagerStatus `json:"status"`
+	JobName   string           `json:"jobName,omitempty"`
 }
 
 // cpuManagerNodeHandler updates cpu manager status of a node in its annotations, so that
@@ -239,9 +241,10 @@ func (h *cpuManagerNodeHandler) OnPodChanged(_ string, pod *corev1.Pod) (*corev1
 
 func (h *cpuManagerNodeHandler) updateCPUManagerStatus(node *corev1.Node, job *batchv1.Job, status CPUManagerStatus) error {
 	updateStatus := &CPUManagerUpdateStatus{
-		Status:  status,
-		Policy:  CPUManagerPolicy(job.Labels[util.LabelCPUManagerUpdatePolicy]),
-		JobName: job.Name,
+		Status:    status,
+		Policy:    CPUManagerPolicy(job.Labels[util.LabelCPUManagerUpdatePolicy]),
+		NUMAAware: job.Labels[util.LabelCPUManagerUpdateNUMA] == "true",
+		JobName:   job.Name,
 	}
 
 	logrus.WithFields(logrus.Fields{
@@ -274,6 +277,9 @@ func GetCPUManagerUpdateStatus(jsonString string) (*CPUManagerUpdateStatus, erro
 	if cpuManagerStatus.Policy != CPUManagerNonePolicy && cpuManagerStatus.Policy != CPUManagerStaticPolicy {
 		return nil, errors.New("invalid policy")
 	}
+	if cpuManagerStatus.NUMAAware && cpuManagerStatus.Policy != CPUManagerStaticPolicy {
+		return nil, errors.New("NUMA-aware scheduling requires the static policy")
+	}
 	return cpuManagerStatus, nil
 }
 
@@ -299,11 +305,14 @@ func setUpdateStatus(node *corev1.Node, updateStatus *CPUManagerUpdateStatus) *c
 	return node
 }
 
-func (h *cpuManagerNodeHandler) getJob(policy CPUManagerPolicy, node *corev1.Node, image string) *batchv1.Job {
+func (h *cpuManagerNodeHandler) getJob(updateStatus *CPUManagerUpdateStatus, node *corev1.Node, image string) *batchv1.Job {
 	hostPathDirectory := corev1.HostPathDirectory
+	policy := updateStatus.Policy
+	numaAware := strconv.FormatBool(updateStatus.NUMAAware)
 	labels := map[string]string{
 		util.LabelCPUManagerUpdateNode:   node.Name,
 		util.LabelCPUManagerUpdatePolicy: string(policy),
+		util.LabelCPUManagerUpdateNUMA:   numaAware,
 	}
 	return &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -333,7 +342,7 @@ func (h *cpuManagerNodeHandler) getJob(policy CPUManagerPolicy, node *corev1.Nod
 							Name:    "update-cpu-manager",
 							Image:   image,
 							Command: []string{"sh"},
-							Args:    []string{"-e", CPUManagerScriptPath, node.Name, string(policy)},
+							Args:    []string{"-e", CPUManagerScriptPath, node.Name, string(policy), numaAware},
 							VolumeMounts: []corev1.VolumeMount{
 								{Name: "host-root", MountPath: CPUManagerRootMountPath},
 								{Name: "helpers", MountPath: CPUManagerScriptMountPath},
@@ -399,7 +408,7 @@ func (h *cpuManagerNodeHandler) submitJob(updateStatus *CPUManagerUpdateStatus,
 		return nil, fmt.Errorf("failed to get cloudweav image (%s): %v", image.ImageName(), err)
 	}
 
-	job, err := h.jobClient.Create(h.getJob(updateStatus.Policy, node, image.ImageName()))
+	job, err := h.jobClient.Create(h.getJob(updateStatus, node, image.ImageName()))
 	if err != nil {
 		return nil, err
 	}
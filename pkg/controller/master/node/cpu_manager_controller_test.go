@@ -4,6 +4,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudweav/cloudweav/pkg/util"
 )
 
 func Test_GetCPUManagerUpdateStatus(t *testing.T) {
@@ -18,3 +22,17 @@ func Test_GetCPUManagerUpdateStatus(t *testing.T) {
 	assert.Equal(t, updateStatus.Policy, CPUManagerStaticPolicy)
 	assert.Equal(t, updateStatus.Status, CPUManagerRequestedStatus)
 }
+
+func Test_GetCPUManagerUpdateStatusNUMAAware(t *testing.T) {
+	_, err := GetCPUManagerUpdateStatus(`{"policy":"none","numaAware":true,"status":"requested"}`)
+	assert.Equal(t, "NUMA-aware scheduling requires the static policy", err.Error())
+
+	updateStatus, err := GetCPUManagerUpdateStatus(`{"policy":"static","numaAware":true,"status":"requested"}`)
+	assert.Nil(t, err)
+	assert.True(t, updateStatus.NUMAAware)
+
+	h := &cpuManagerNodeHandler{namespace: "cloudweav-system"}
+	job := h.getJob(updateStatus, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}, "cloudweav:latest")
+	assert.Equal(t, "true", job.Labels[util.LabelCPUManagerUpdateNUMA])
+	assert.Equal(t, []string{"-e", CPUManagerScriptPath, "node-1", "static", "true"}, job.Spec.Template.Spec.Containers[0].Args)
+}
@@ -21,6 +21,8 @@ const (
 	MaintainStatusAnnotationKey = "cloudweavhci.io/maintain-status"
 	MaintainStatusComplete      = "completed"
 	MaintainStatusRunning       = "running"
+	// DrainRequestedAnnotationKey requests the node drain controller to put the node into maintenance mode
+	DrainRequestedAnnotationKey = "cloudweavhci.io/drain-requested"
 )
 
 // maintainNodeHandler updates maintenance status of a node in its annotations, so that we can tell whether the node is
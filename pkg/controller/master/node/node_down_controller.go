@@ -3,6 +3,7 @@ package node
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	longhorntypes "github.com/longhorn/longhorn-manager/types"
@@ -13,14 +14,17 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/set"
 	kubevirtv1 "kubevirt.io/api/core/v1"
 
 	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
 	"github.com/cloudweav/cloudweav/pkg/config"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
 	v1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
 	ctlstoragev1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/storage.k8s.io/v1"
 	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/util/bmc"
 )
 
 const (
@@ -36,6 +40,14 @@ type nodeDownHandler struct {
 	vas                         ctlstoragev1.VolumeAttachmentClient
 	vaCache                     ctlstoragev1.VolumeAttachmentCache
 	virtualMachineInstanceCache v1.VirtualMachineInstanceCache
+	virtualMachines             v1.VirtualMachineClient
+	virtualMachineCache         v1.VirtualMachineCache
+	nodeBMCCache                ctlcloudweavv1.NodeBMCCache
+	secretCache                 ctlcorev1.SecretCache
+	newBMCClient                func(spec cloudweavv1.NodeBMCSpec, secret *corev1.Secret) (bmc.Client, error)
+	httpClient                  *http.Client
+	recorder                    record.EventRecorder
+	now                         func() time.Time
 }
 
 // DownRegister registers a controller to delete VMI when node is down
@@ -44,8 +56,11 @@ func DownRegister(ctx context.Context, management *config.Management, _ config.O
 	pods := management.CoreFactory.Core().V1().Pod()
 	setting := management.CloudweavFactory.Cloudweavhci().V1beta1().Setting()
 	vmis := management.VirtFactory.Kubevirt().V1().VirtualMachineInstance()
+	vms := management.VirtFactory.Kubevirt().V1().VirtualMachine()
 	pvcs := management.CoreFactory.Core().V1().PersistentVolumeClaim()
 	vas := management.CloudweavStorageFactory.Storage().V1().VolumeAttachment()
+	nodeBMCs := management.CloudweavFactory.Cloudweavhci().V1beta1().NodeBMC()
+	secrets := management.CoreFactory.Core().V1().Secret()
 	nodeDownHandler := &nodeDownHandler{
 		nodes:                       nodes,
 		nodeCache:                   nodes.Cache(),
@@ -54,6 +69,14 @@ func DownRegister(ctx context.Context, management *config.Management, _ config.O
 		vas:                         vas,
 		vaCache:                     vas.Cache(),
 		virtualMachineInstanceCache: vmis.Cache(),
+		virtualMachines:             vms,
+		virtualMachineCache:         vms.Cache(),
+		nodeBMCCache:                nodeBMCs.Cache(),
+		secretCache:                 secrets.Cache(),
+		newBMCClient:                bmc.NewClient,
+		httpClient:                  &http.Client{Timeout: fencingTimeout},
+		recorder:                    management.NewRecorder("cloudweav-"+nodeDownControllerName, "", ""),
+		now:                         time.Now,
 	}
 
 	nodes.OnChange(ctx, nodeDownControllerName, nodeDownHandler.OnNodeChanged)
@@ -68,6 +91,8 @@ func DownRegister(ctx context.Context, management *config.Management, _ config.O
 // 2. A node has been down for more than VMForceResetPolicy.Period seconds
 // 3. The owner of Pod is VirtualMachineInstance.
 // 4. The Pod is on a down node.
+// 5. The node is fenced, or the fencing failed and VMForceResetPolicy.Fencing allows to reset the VMs anyway.
+// The pods are deleted in the order of the HA priority of their VMs, see planVMRestarts.
 func (h *nodeDownHandler) OnNodeChanged(_ string, node *corev1.Node) (*corev1.Node, error) {
 	if node == nil || node.DeletionTimestamp != nil {
 		return node, nil
@@ -81,7 +106,7 @@ func (h *nodeDownHandler) OnNodeChanged(_ string, node *corev1.Node) (*corev1.No
 
 	// check whether node is healthy
 	if cond.Status == corev1.ConditionTrue {
-		return node, nil
+		return h.clearFencing(node)
 	}
 
 	// get VMForceResetPolicy setting
@@ -102,6 +127,11 @@ func (h *nodeDownHandler) OnNodeChanged(_ string, node *corev1.Node) (*corev1.No
 		return node, nil
 	}
 
+	node, fenced, err := h.fence(node, cond.LastTransitionTime.Time, vmForceResetPolicy.Fencing)
+	if err != nil || !fenced {
+		return node, err
+	}
+
 	// get VMI pods on unhealthy node
 	pods, err := h.pods.List(corev1.NamespaceAll, metav1.ListOptions{
 		LabelSelector: labels.Set{
@@ -116,10 +146,27 @@ func (h *nodeDownHandler) OnNodeChanged(_ string, node *corev1.Node) (*corev1.No
 	var errs error
 	pvcSet := set.New[string]()
 	gracePeriod := int64(0)
-	for i := range pods.Items {
-		pod := pods.Items[i]
-		logrus.Debugf("force delete pod %s/%s and VolumeAttachments", pod.Namespace, pod.Name)
+	now := h.now()
+	resetStart := vmResetStart(node, cond.LastTransitionTime.Time, time.Duration(vmForceResetPolicy.Period)*time.Second)
+	var nextRestart time.Time
+	for _, restart := range h.planVMRestarts(pods.Items, vmForceResetPolicy, resetStart) {
+		pod := restart.pod
+		if restart.at.After(now) {
+			if nextRestart.IsZero() || restart.at.Before(nextRestart) {
+				nextRestart = restart.at
+			}
+			if err := h.setHAPending(restart, node.Name); err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("failed to set HA status of VM %s/%s: %w", restart.vm.Namespace, restart.vm.Name, err))
+			}
+			continue
+		}
+
+		if _, err := h.applyHAPolicy(restart, node.Name, resetStart); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to apply HA policy of VM %s/%s: %w", restart.vm.Namespace, restart.vm.Name, err))
+			continue
+		}
 
+		logrus.Debugf("force delete pod %s/%s and VolumeAttachments", pod.Namespace, pod.Name)
 		if err := h.pods.Delete(
 			pod.Namespace,
 			pod.Name,
@@ -130,7 +177,7 @@ func (h *nodeDownHandler) OnNodeChanged(_ string, node *corev1.Node) (*corev1.No
 			continue
 		}
 
-		names, err := h.getPVCsName(&pod)
+		names, err := h.getPVCsName(pod)
 		if err != nil {
 			errs = multierr.Append(errs, fmt.Errorf("failed to get PVCs name of pod %s/%s: %w", pod.Namespace, pod.Name, err))
 			continue
@@ -145,6 +192,11 @@ func (h *nodeDownHandler) OnNodeChanged(_ string, node *corev1.Node) (*corev1.No
 		return node, errs
 	}
 
+	// the heartbeat makes KubeVirt fail all the VMIs of the node, it waits for the VMs restarted later
+	if !nextRestart.IsZero() {
+		h.nodes.EnqueueAfter(node.Name, nextRestart.Sub(now))
+		return node, nil
+	}
 	return h.resetHeartbeat(node)
 }
 
@@ -0,0 +1,195 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/util"
+	vmutil "github.com/cloudweav/cloudweav/pkg/util/virtualmachine"
+)
+
+// vmRestart is the force reset of the virt-launcher pod of a VM on a down node
+type vmRestart struct {
+	pod    *corev1.Pod
+	vm     *kubevirtv1.VirtualMachine
+	policy *vmutil.HAPolicy
+	at     time.Time
+}
+
+// planVMRestarts schedules the reset of the pods by the priority of their VMs. The VMs of each lower priority are reset
+// PriorityIntervalSeconds after the previous one, and each VM is further delayed by the restart delay of its HA policy.
+// The restarts are returned in the order of their reset time, the higher priority first at the same time.
+func (h *nodeDownHandler) planVMRestarts(pods []corev1.Pod, policy *settings.VMForceResetPolicy, start time.Time) []*vmRestart {
+	restarts := make([]*vmRestart, 0, len(pods))
+	priorities := make(map[*vmRestart]int, len(pods))
+	for i := range pods {
+		restart := &vmRestart{
+			pod:    &pods[i],
+			policy: &vmutil.HAPolicy{},
+		}
+		if vm, err := h.getPodVM(&pods[i]); err != nil {
+			logrus.Warnf("failed to get the VM of pod %s/%s, restarting it with the default HA policy: %v", pods[i].Namespace, pods[i].Name, err)
+		} else if vm != nil {
+			restart.vm = vm
+			if vmPolicy, err := vmutil.GetHAPolicy(vm); err != nil {
+				logrus.Warnf("VM %s/%s is restarted with the default HA policy: %v", vm.Namespace, vm.Name, err)
+			} else {
+				restart.policy = vmPolicy
+			}
+		}
+		priorities[restart] = policy.DefaultPriority
+		if restart.policy.Priority != nil {
+			priorities[restart] = *restart.policy.Priority
+		}
+		restarts = append(restarts, restart)
+	}
+
+	sort.SliceStable(restarts, func(i, j int) bool {
+		return priorities[restarts[i]] > priorities[restarts[j]]
+	})
+
+	rank := 0
+	for i, restart := range restarts {
+		if i > 0 && priorities[restart] != priorities[restarts[i-1]] {
+			rank++
+		}
+		restart.at = start.Add(time.Duration(int64(rank)*policy.PriorityIntervalSeconds) * time.Second).
+			Add(time.Duration(restart.policy.RestartDelaySeconds) * time.Second)
+	}
+
+	sort.SliceStable(restarts, func(i, j int) bool {
+		if !restarts[i].at.Equal(restarts[j].at) {
+			return restarts[i].at.Before(restarts[j].at)
+		}
+		return priorities[restarts[i]] > priorities[restarts[j]]
+	})
+	return restarts
+}
+
+// getPodVM returns the VM owning the VMI of the virt-launcher pod, nil for a VMI without VM
+func (h *nodeDownHandler) getPodVM(pod *corev1.Pod) (*kubevirtv1.VirtualMachine, error) {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind != kubevirtv1.VirtualMachineInstanceGroupVersionKind.Kind {
+			continue
+		}
+		vm, err := h.virtualMachineCache.Get(pod.Namespace, owner.Name)
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return vm, err
+	}
+	return nil, nil
+}
+
+// applyHAPolicy records the restart of the VM in its HA status, or stops the VM if its HA policy doesn't allow
+// the restart. It returns whether the VM is restarted.
+func (h *nodeDownHandler) applyHAPolicy(restart *vmRestart, nodeName string, resetStart time.Time) (bool, error) {
+	if restart.vm == nil {
+		return true, nil
+	}
+
+	now := h.now()
+	status := vmutil.GetHAStatus(restart.vm)
+	// the policy was already applied for this failure, but the pod couldn't be deleted
+	if t, err := time.Parse(time.RFC3339, status.Time); err == nil && status.NodeName == nodeName &&
+		status.State != vmutil.HAStatePending && !t.Before(resetStart) {
+		return status.State == vmutil.HAStateRestarted, nil
+	}
+	status.NodeName = nodeName
+	status.Time = now.UTC().Format(time.RFC3339)
+	status.Restarts = status.RestartsSince(now.Add(-restart.policy.MaxRestartsWindow()))
+
+	toUpdate := restart.vm.DeepCopy()
+	restarted := true
+	switch {
+	case restart.policy.RestartPolicy == vmutil.HARestartPolicyDoNotRestart:
+		restarted = false
+		status.State = vmutil.HAStateNotRestarted
+		status.Message = fmt.Sprintf("restart policy is %s", vmutil.HARestartPolicyDoNotRestart)
+	case restart.policy.MaxRestarts > 0 && len(status.Restarts) >= restart.policy.MaxRestarts:
+		restarted = false
+		status.State = vmutil.HAStateNotRestarted
+		status.Message = fmt.Sprintf("restarted %d times in the last %s", len(status.Restarts), restart.policy.MaxRestartsWindow())
+	default:
+		status.State = vmutil.HAStateRestarted
+		status.Message = ""
+		status.Restarts = append(status.Restarts, now.UTC().Format(time.RFC3339))
+	}
+
+	if !restarted {
+		runStrategy := kubevirtv1.RunStrategyHalted
+		toUpdate.Spec.RunStrategy = &runStrategy
+		toUpdate.Spec.Running = nil
+	}
+	if err := setHAStatus(toUpdate, status); err != nil {
+		return false, err
+	}
+	if _, err := h.virtualMachines.Update(toUpdate); err != nil {
+		return false, err
+	}
+
+	if restarted {
+		h.recorder.Eventf(restart.vm, corev1.EventTypeNormal, "HARestart", "Restart VM after failure of node %s", nodeName)
+	} else {
+		h.recorder.Eventf(restart.vm, corev1.EventTypeWarning, "HANotRestarted", "Stop VM after failure of node %s: %s", nodeName, status.Message)
+	}
+	return restarted, nil
+}
+
+// setHAPending shows in the HA status of the VM when it will be restarted
+func (h *nodeDownHandler) setHAPending(restart *vmRestart, nodeName string) error {
+	if restart.vm == nil {
+		return nil
+	}
+	status := vmutil.GetHAStatus(restart.vm)
+	if status.State != vmutil.HAStatePending || status.NodeName != nodeName {
+		status.Time = h.now().UTC().Format(time.RFC3339)
+	}
+	status.State = vmutil.HAStatePending
+	status.NodeName = nodeName
+	status.Message = fmt.Sprintf("restart at %s", restart.at.UTC().Format(time.RFC3339))
+
+	toUpdate := restart.vm.DeepCopy()
+	if err := setHAStatus(toUpdate, status); err != nil {
+		return err
+	}
+	if toUpdate.Annotations[util.AnnotationHAStatus] == restart.vm.Annotations[util.AnnotationHAStatus] {
+		return nil
+	}
+	_, err := h.virtualMachines.Update(toUpdate)
+	return err
+}
+
+func setHAStatus(vm *kubevirtv1.VirtualMachine, status *vmutil.HAStatus) error {
+	value, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if vm.Annotations == nil {
+		vm.Annotations = map[string]string{}
+	}
+	vm.Annotations[util.AnnotationHAStatus] = string(value)
+	return nil
+}
+
+// vmResetStart returns when the VMs of the down node can be reset, when the node is down for the force reset
+// period, or later when it's fenced
+func vmResetStart(node *corev1.Node, downSince time.Time, period time.Duration) time.Time {
+	start := downSince.Add(period)
+	status := getFencingStatus(node, downSince)
+	if !status.Fenced {
+		return start
+	}
+	if fencedAt, err := time.Parse(time.RFC3339, status.LastAttemptTime); err == nil && fencedAt.After(start) {
+		return fencedAt
+	}
+	return start
+}
@@ -0,0 +1,197 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/fake"
+	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/util"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+	vmutil "github.com/cloudweav/cloudweav/pkg/util/virtualmachine"
+)
+
+func newHAVM(name, haPolicy string) *kubevirtv1.VirtualMachine {
+	vm := &kubevirtv1.VirtualMachine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+		},
+	}
+	if haPolicy != "" {
+		vm.Annotations = map[string]string{util.AnnotationHAPolicy: haPolicy}
+	}
+	return vm
+}
+
+func newLauncherPod(vmName string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "virt-launcher-" + vmName,
+			OwnerReferences: []metav1.OwnerReference{{
+				Kind: kubevirtv1.VirtualMachineInstanceGroupVersionKind.Kind,
+				Name: vmName,
+			}},
+		},
+	}
+}
+
+func newHAHandler(now time.Time, vms ...*kubevirtv1.VirtualMachine) *nodeDownHandler {
+	objects := make([]runtime.Object, 0, len(vms))
+	for _, vm := range vms {
+		objects = append(objects, vm)
+	}
+	clientset := fake.NewSimpleClientset(objects...)
+	return &nodeDownHandler{
+		virtualMachines:     fakeclients.VirtualMachineClient(clientset.KubevirtV1().VirtualMachines),
+		virtualMachineCache: fakeclients.VirtualMachineCache(clientset.KubevirtV1().VirtualMachines),
+		recorder:            record.NewFakeRecorder(10),
+		now:                 func() time.Time { return now },
+	}
+}
+
+func Test_planVMRestarts(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC)
+	h := newHAHandler(start,
+		newHAVM("critical", `{"priority":100}`),
+		newHAVM("database", `{"priority":100,"restartDelaySeconds":30}`),
+		newHAVM("web", ""),
+		newHAVM("test", `{"priority":-10}`),
+	)
+	pods := []corev1.Pod{
+		newLauncherPod("test"),
+		newLauncherPod("web"),
+		newLauncherPod("database"),
+		newLauncherPod("critical"),
+		newLauncherPod("standalone"),
+	}
+	policy := &settings.VMForceResetPolicy{
+		Enable:                  true,
+		Period:                  300,
+		DefaultPriority:         10,
+		PriorityIntervalSeconds: 60,
+	}
+
+	restarts := h.planVMRestarts(pods, policy, start)
+	var names []string
+	var delays []time.Duration
+	for _, restart := range restarts {
+		names = append(names, restart.pod.Name)
+		delays = append(delays, restart.at.Sub(start))
+	}
+	assert.Equal(t, []string{
+		"virt-launcher-critical",
+		"virt-launcher-database",
+		"virt-launcher-web",
+		"virt-launcher-standalone",
+		"virt-launcher-test",
+	}, names)
+	assert.Equal(t, []time.Duration{0, 30 * time.Second, time.Minute, time.Minute, 2 * time.Minute}, delays)
+	assert.Nil(t, restarts[3].vm, "a VMI without VM has no HA policy")
+}
+
+func Test_applyHAPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		haPolicy      string
+		restarts      []string
+		wantRestarted bool
+		wantState     string
+	}{
+		{
+			name:          "restart by default",
+			wantRestarted: true,
+			wantState:     vmutil.HAStateRestarted,
+		},
+		{
+			name:          "do not restart",
+			haPolicy:      `{"restartPolicy":"DoNotRestart"}`,
+			wantRestarted: false,
+			wantState:     vmutil.HAStateNotRestarted,
+		},
+		{
+			name:          "max restarts reached in the window",
+			haPolicy:      `{"maxRestarts":2,"maxRestartsWindowSeconds":3600}`,
+			restarts:      []string{"2024-01-01T00:00:00Z", "2024-01-01T00:30:00Z"},
+			wantRestarted: false,
+			wantState:     vmutil.HAStateNotRestarted,
+		},
+		{
+			name:          "old restarts are out of the window",
+			haPolicy:      `{"maxRestarts":2,"maxRestartsWindowSeconds":3600}`,
+			restarts:      []string{"2023-12-31T00:00:00Z", "2024-01-01T00:30:00Z"},
+			wantRestarted: true,
+			wantState:     vmutil.HAStateRestarted,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Date(2024, 1, 1, 0, 45, 0, 0, time.UTC)
+			vm := newHAVM("vm", tc.haPolicy)
+			if tc.restarts != nil {
+				require.NoError(t, setHAStatus(vm, &vmutil.HAStatus{State: vmutil.HAStateRestarted, Restarts: tc.restarts}))
+			}
+			h := newHAHandler(now, vm)
+			policy, err := vmutil.GetHAPolicy(vm)
+			require.NoError(t, err)
+			restart := &vmRestart{vm: vm, policy: policy, at: now}
+
+			restarted, err := h.applyHAPolicy(restart, "node-1", now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRestarted, restarted)
+
+			updated, err := h.virtualMachineCache.Get(vm.Namespace, vm.Name)
+			require.NoError(t, err)
+			status := vmutil.GetHAStatus(updated)
+			assert.Equal(t, tc.wantState, status.State)
+			assert.Equal(t, "node-1", status.NodeName)
+			if tc.wantRestarted {
+				assert.Nil(t, updated.Spec.RunStrategy)
+			} else {
+				require.NotNil(t, updated.Spec.RunStrategy)
+				assert.Equal(t, kubevirtv1.RunStrategyHalted, *updated.Spec.RunStrategy)
+			}
+
+			// the policy is applied once per failure
+			restart.vm = updated
+			restartedAgain, err := h.applyHAPolicy(restart, "node-1", now)
+			require.NoError(t, err)
+			assert.Equal(t, restarted, restartedAgain)
+			again, err := h.virtualMachineCache.Get(vm.Namespace, vm.Name)
+			require.NoError(t, err)
+			assert.Equal(t, updated.Annotations[util.AnnotationHAStatus], again.Annotations[util.AnnotationHAStatus])
+		})
+	}
+}
+
+func Test_setHAPending(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC)
+	vm := newHAVM("vm", `{"restartDelaySeconds":120}`)
+	h := newHAHandler(now, vm)
+
+	restart := &vmRestart{vm: vm, at: now.Add(2 * time.Minute)}
+	require.NoError(t, h.setHAPending(restart, "node-1"))
+	updated, err := h.virtualMachines.Get(vm.Namespace, vm.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	status := vmutil.GetHAStatus(updated)
+	assert.Equal(t, vmutil.HAStatePending, status.State)
+	assert.Equal(t, "restart at 2024-01-01T00:07:00Z", status.Message)
+
+	// nothing changes while the VM waits
+	h.now = func() time.Time { return now.Add(time.Minute) }
+	restart.vm = updated
+	require.NoError(t, h.setHAPending(restart, "node-1"))
+	again, err := h.virtualMachines.Get(vm.Namespace, vm.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, updated.Annotations[util.AnnotationHAStatus], again.Annotations[util.AnnotationHAStatus])
+}
@@ -0,0 +1,262 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/util/bmc"
+)
+
+const (
+	// NodeFencingStatusAnnotationKey records the fencing attempts of a down node
+	NodeFencingStatusAnnotationKey = CloudweavLabelAnnotationPrefix + "fencing-status"
+	// NodeFencingFailed is true when a down node could not be fenced and needs a manual action
+	NodeFencingFailed corev1.NodeConditionType = "FencingFailed"
+
+	defaultFencingRetryInterval = 30 * time.Second
+	fencingTimeout              = 2 * time.Minute
+)
+
+// NodeFencingStatus is the fencing progress of a node during one outage
+type NodeFencingStatus struct {
+	// DownSince is the time the node became not ready, the attempts of a previous outage are ignored
+	DownSince       string `json:"downSince"`
+	Attempts        int    `json:"attempts"`
+	LastAttemptTime string `json:"lastAttemptTime,omitempty"`
+	Fenced          bool   `json:"fenced"`
+	Message         string `json:"message,omitempty"`
+}
+
+// fencingWebhookRequest is the body posted to the fencing webhook
+type fencingWebhookRequest struct {
+	Node      string   `json:"node"`
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+// fence makes sure the down node can't run its VMs anymore, it returns whether the VMs can be force reset.
+// A failed attempt is retried after the retry interval, when all the attempts failed the node gets the
+// FencingFailed condition and the VMs are only reset if the policy allows it.
+func (h *nodeDownHandler) fence(node *corev1.Node, downSince time.Time, policy *settings.NodeFencingPolicy) (*corev1.Node, bool, error) {
+	if policy == nil || policy.Mode == settings.NodeFencingModeNone {
+		return node, true, nil
+	}
+
+	status := getFencingStatus(node, downSince)
+	if status.Fenced {
+		return node, true, nil
+	}
+	if status.Attempts > policy.Retries {
+		node, err := h.setFencingFailed(node, status.Message)
+		return node, policy.ResetWithoutFencing, err
+	}
+
+	now := h.now()
+	interval := fencingRetryInterval(policy)
+	if lastAttempt, err := time.Parse(time.RFC3339, status.LastAttemptTime); err == nil && now.Sub(lastAttempt) < interval {
+		h.nodes.EnqueueAfter(node.Name, interval-now.Sub(lastAttempt))
+		return node, false, nil
+	}
+
+	status.Attempts++
+	status.LastAttemptTime = now.UTC().Format(time.RFC3339)
+	if err := h.fenceNode(node, policy); err != nil {
+		status.Message = err.Error()
+		logrus.Warnf("attempt %d to fence node %s failed: %v", status.Attempts, node.Name, err)
+		h.recordFencingEvent(node, corev1.EventTypeWarning, "NodeFencingAttemptFailed",
+			fmt.Sprintf("Attempt %d/%d to fence node %s with %s failed: %v", status.Attempts, policy.Retries+1, node.Name, policy.Mode, err))
+		if status.Attempts > policy.Retries {
+			h.nodes.Enqueue(node.Name)
+		} else {
+			h.nodes.EnqueueAfter(node.Name, interval)
+		}
+	} else {
+		status.Fenced = true
+		status.Message = ""
+		h.recordFencingEvent(node, corev1.EventTypeNormal, "NodeFenced",
+			fmt.Sprintf("Node %s is fenced with %s, its VMs will be force reset", node.Name, policy.Mode))
+	}
+
+	node, err := h.setFencingStatus(node, status)
+	if err != nil {
+		return node, false, err
+	}
+	return node, status.Fenced, nil
+}
+
+func (h *nodeDownHandler) fenceNode(node *corev1.Node, policy *settings.NodeFencingPolicy) error {
+	ctx, cancel := context.WithTimeout(context.Background(), fencingTimeout)
+	defer cancel()
+
+	switch policy.Mode {
+	case settings.NodeFencingModeBMC:
+		return h.fenceWithBMC(ctx, node.Name)
+	case settings.NodeFencingModeWebhook:
+		return h.fenceWithWebhook(ctx, node, policy.WebhookURL)
+	default:
+		return fmt.Errorf("unknown fencing mode %s", policy.Mode)
+	}
+}
+
+// fenceWithBMC powers off the node and checks the BMC reports it off
+func (h *nodeDownHandler) fenceWithBMC(ctx context.Context, nodeName string) error {
+	nodeBMC, err := h.nodeBMCCache.Get(nodeName)
+	if err != nil {
+		return fmt.Errorf("failed to get the BMC of node %s: %w", nodeName, err)
+	}
+	client, err := NewBMCClient(h.secretCache, nodeBMC, h.newBMCClient)
+	if err != nil {
+		return err
+	}
+	if err := bmc.Do(ctx, client, bmc.OperationPowerOff, ""); err != nil {
+		return err
+	}
+	status, err := client.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.PowerState != bmc.PowerStateOff {
+		return fmt.Errorf("power state of node %s is %s after power off", nodeName, status.PowerState)
+	}
+	return nil
+}
+
+// fenceWithWebhook delegates the fencing to an external service, any 2xx response means the node is fenced
+func (h *nodeDownHandler) fenceWithWebhook(ctx context.Context, node *corev1.Node, webhookURL string) error {
+	request := fencingWebhookRequest{Node: node.Name}
+	for _, address := range node.Status.Addresses {
+		request.Addresses = append(request.Addresses, address.Address)
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("fencing webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func getFencingStatus(node *corev1.Node, downSince time.Time) *NodeFencingStatus {
+	downSinceStr := downSince.UTC().Format(time.RFC3339)
+	status := &NodeFencingStatus{}
+	if value := node.Annotations[NodeFencingStatusAnnotationKey]; value != "" {
+		if err := json.Unmarshal([]byte(value), status); err != nil {
+			logrus.Warnf("invalid fencing status of node %s: %v", node.Name, err)
+		}
+	}
+	if status.DownSince != downSinceStr {
+		return &NodeFencingStatus{DownSince: downSinceStr}
+	}
+	return status
+}
+
+func (h *nodeDownHandler) setFencingStatus(node *corev1.Node, status *NodeFencingStatus) (*corev1.Node, error) {
+	value, err := json.Marshal(status)
+	if err != nil {
+		return node, err
+	}
+	toUpdate := node.DeepCopy()
+	if toUpdate.Annotations == nil {
+		toUpdate.Annotations = map[string]string{}
+	}
+	toUpdate.Annotations[NodeFencingStatusAnnotationKey] = string(value)
+	return h.nodes.Update(toUpdate)
+}
+
+// setFencingFailed sets the FencingFailed condition and records an event the first time all the attempts failed
+func (h *nodeDownHandler) setFencingFailed(node *corev1.Node, message string) (*corev1.Node, error) {
+	message = "fencing failed, manual action required: " + message
+	if cond := getNodeCondition(node.Status.Conditions, NodeFencingFailed); cond != nil &&
+		cond.Status == corev1.ConditionTrue && cond.Message == message {
+		return node, nil
+	}
+
+	h.recordFencingEvent(node, corev1.EventTypeWarning, "NodeFencingFailed", fmt.Sprintf("Node %s: %s", node.Name, message))
+	toUpdate := node.DeepCopy()
+	setNodeCondition(toUpdate, corev1.NodeCondition{
+		Type:    NodeFencingFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  "FencingFailed",
+		Message: message,
+	}, h.now())
+	return h.nodes.UpdateStatus(toUpdate)
+}
+
+// clearFencing forgets the fencing of a node which is ready again
+func (h *nodeDownHandler) clearFencing(node *corev1.Node) (*corev1.Node, error) {
+	if _, ok := node.Annotations[NodeFencingStatusAnnotationKey]; ok {
+		toUpdate := node.DeepCopy()
+		delete(toUpdate.Annotations, NodeFencingStatusAnnotationKey)
+		updated, err := h.nodes.Update(toUpdate)
+		if err != nil {
+			return node, err
+		}
+		node = updated
+	}
+
+	if cond := getNodeCondition(node.Status.Conditions, NodeFencingFailed); cond != nil && cond.Status == corev1.ConditionTrue {
+		toUpdate := node.DeepCopy()
+		setNodeCondition(toUpdate, corev1.NodeCondition{
+			Type:   NodeFencingFailed,
+			Status: corev1.ConditionFalse,
+			Reason: "NodeReady",
+		}, h.now())
+		return h.nodes.UpdateStatus(toUpdate)
+	}
+	return node, nil
+}
+
+func setNodeCondition(node *corev1.Node, cond corev1.NodeCondition, now time.Time) {
+	cond.LastHeartbeatTime = metav1.NewTime(now)
+	cond.LastTransitionTime = metav1.NewTime(now)
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type != cond.Type {
+			continue
+		}
+		if node.Status.Conditions[i].Status == cond.Status {
+			cond.LastTransitionTime = node.Status.Conditions[i].LastTransitionTime
+		}
+		node.Status.Conditions[i] = cond
+		return
+	}
+	node.Status.Conditions = append(node.Status.Conditions, cond)
+}
+
+func (h *nodeDownHandler) recordFencingEvent(node *corev1.Node, eventType, reason, message string) {
+	nodeReference := &corev1.ObjectReference{
+		Name: node.Name,
+		UID:  types.UID(node.Name),
+		Kind: "Node",
+	}
+	h.recorder.Event(nodeReference, eventType, reason, message)
+}
+
+func fencingRetryInterval(policy *settings.NodeFencingPolicy) time.Duration {
+	if policy.RetryIntervalSeconds <= 0 {
+		return defaultFencingRetryInterval
+	}
+	return time.Duration(policy.RetryIntervalSeconds) * time.Second
+}
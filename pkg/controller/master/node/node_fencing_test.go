@@ -0,0 +1,247 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/fake"
+	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/util/bmc"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+)
+
+// fakeNodeController updates the nodes of a fake clientset and records the enqueued nodes
+type fakeNodeController struct {
+	ctlcorev1.NodeController
+	clientset *k8sfake.Clientset
+	enqueued  []string
+}
+
+func (c *fakeNodeController) Update(node *corev1.Node) (*corev1.Node, error) {
+	return c.clientset.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+}
+
+func (c *fakeNodeController) UpdateStatus(node *corev1.Node) (*corev1.Node, error) {
+	return c.clientset.CoreV1().Nodes().UpdateStatus(context.TODO(), node, metav1.UpdateOptions{})
+}
+
+func (c *fakeNodeController) Enqueue(name string) {
+	c.enqueued = append(c.enqueued, name)
+}
+
+func (c *fakeNodeController) EnqueueAfter(name string, _ time.Duration) {
+	c.enqueued = append(c.enqueued, name)
+}
+
+// fakeBMCClient powers off the node unless it fails
+type fakeBMCClient struct {
+	bmc.Client
+	powerState string
+	err        error
+}
+
+func (c *fakeBMCClient) Status(_ context.Context) (*bmc.Status, error) {
+	return &bmc.Status{PowerState: c.powerState}, nil
+}
+
+func (c *fakeBMCClient) Power(_ context.Context, operation string) error {
+	if c.err != nil {
+		return c.err
+	}
+	if operation == bmc.OperationPowerOff {
+		c.powerState = bmc.PowerStateOff
+	}
+	return nil
+}
+
+var (
+	downSince = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	downNode = &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1",
+		},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "10.0.0.11"}},
+			Conditions: []corev1.NodeCondition{{
+				Type:               corev1.NodeReady,
+				Status:             corev1.ConditionUnknown,
+				LastTransitionTime: metav1.NewTime(downSince),
+			}},
+		},
+	}
+
+	downNodeBMC = &cloudweavv1.NodeBMC{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1",
+		},
+		Spec: cloudweavv1.NodeBMCSpec{
+			Protocol: cloudweavv1.NodeBMCProtocolRedfish,
+			Address:  "https://10.0.1.11",
+			CredentialsSecret: corev1.SecretReference{
+				Namespace: "cloudweav-system",
+				Name:      "bmc-credentials",
+			},
+		},
+	}
+
+	bmcSecret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "cloudweav-system",
+			Name:      "bmc-credentials",
+		},
+	}
+)
+
+func newFencingHandler(bmcClient bmc.Client, now time.Time) (*nodeDownHandler, *fakeNodeController) {
+	k8sclientset := k8sfake.NewSimpleClientset(downNode.DeepCopy(), bmcSecret)
+	clientset := fake.NewSimpleClientset(downNodeBMC)
+	nodes := &fakeNodeController{clientset: k8sclientset}
+	return &nodeDownHandler{
+		nodes:        nodes,
+		nodeBMCCache: fakeclients.NodeBMCCache(clientset.CloudweavhciV1beta1().NodeBMCs),
+		secretCache:  fakeclients.SecretCache(k8sclientset.CoreV1().Secrets),
+		newBMCClient: func(_ cloudweavv1.NodeBMCSpec, _ *corev1.Secret) (bmc.Client, error) {
+			return bmcClient, nil
+		},
+		httpClient: http.DefaultClient,
+		recorder:   record.NewFakeRecorder(10),
+		now:        func() time.Time { return now },
+	}, nodes
+}
+
+func Test_fenceWithBMC(t *testing.T) {
+	now := downSince.Add(10 * time.Minute)
+	h, _ := newFencingHandler(&fakeBMCClient{powerState: bmc.PowerStateOn}, now)
+	policy := &settings.NodeFencingPolicy{Mode: settings.NodeFencingModeBMC}
+
+	node, fenced, err := h.fence(downNode.DeepCopy(), downSince, policy)
+	require.NoError(t, err)
+	assert.True(t, fenced)
+	status := getFencingStatus(node, downSince)
+	assert.True(t, status.Fenced)
+	assert.Equal(t, 1, status.Attempts)
+
+	// a fenced node is not fenced again during the same outage
+	node, fenced, err = h.fence(node, downSince, policy)
+	require.NoError(t, err)
+	assert.True(t, fenced)
+	assert.Equal(t, 1, getFencingStatus(node, downSince).Attempts)
+
+	// a new outage needs a new fencing
+	assert.False(t, getFencingStatus(node, downSince.Add(time.Hour)).Fenced)
+}
+
+func Test_fenceFailed(t *testing.T) {
+	tests := []struct {
+		name                string
+		resetWithoutFencing bool
+	}{
+		{
+			name:                "VMs are not reset without fencing",
+			resetWithoutFencing: false,
+		},
+		{
+			name:                "VMs are reset without fencing",
+			resetWithoutFencing: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			now := downSince.Add(10 * time.Minute)
+			h, nodes := newFencingHandler(&fakeBMCClient{powerState: bmc.PowerStateOn, err: errors.New("connection refused")}, now)
+			policy := &settings.NodeFencingPolicy{
+				Mode:                 settings.NodeFencingModeBMC,
+				Retries:              1,
+				RetryIntervalSeconds: 60,
+				ResetWithoutFencing:  tc.resetWithoutFencing,
+			}
+
+			node, fenced, err := h.fence(downNode.DeepCopy(), downSince, policy)
+			require.NoError(t, err)
+			assert.False(t, fenced)
+			assert.Equal(t, 1, getFencingStatus(node, downSince).Attempts)
+
+			// the retry waits for the retry interval
+			node, fenced, err = h.fence(node, downSince, policy)
+			require.NoError(t, err)
+			assert.False(t, fenced)
+			assert.Equal(t, 1, getFencingStatus(node, downSince).Attempts)
+
+			h.now = func() time.Time { return now.Add(time.Minute) }
+			node, fenced, err = h.fence(node, downSince, policy)
+			require.NoError(t, err)
+			assert.False(t, fenced)
+			assert.Equal(t, 2, getFencingStatus(node, downSince).Attempts)
+
+			// all the attempts failed
+			node, fenced, err = h.fence(node, downSince, policy)
+			require.NoError(t, err)
+			assert.Equal(t, tc.resetWithoutFencing, fenced)
+			cond := getNodeCondition(node.Status.Conditions, NodeFencingFailed)
+			require.NotNil(t, cond)
+			assert.Equal(t, corev1.ConditionTrue, cond.Status)
+			assert.Contains(t, cond.Message, "manual action required")
+			assert.Contains(t, cond.Message, "connection refused")
+			assert.NotEmpty(t, nodes.enqueued)
+
+			// the node is back
+			node, err = h.clearFencing(node)
+			require.NoError(t, err)
+			assert.NotContains(t, node.Annotations, NodeFencingStatusAnnotationKey)
+			assert.Equal(t, corev1.ConditionFalse, getNodeCondition(node.Status.Conditions, NodeFencingFailed).Status)
+		})
+	}
+}
+
+func Test_fenceWithWebhook(t *testing.T) {
+	var requests []fencingWebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		request := fencingWebhookRequest{}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&request))
+		requests = append(requests, request)
+		if len(requests) == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	now := downSince.Add(10 * time.Minute)
+	h, _ := newFencingHandler(nil, now)
+	policy := &settings.NodeFencingPolicy{
+		Mode:       settings.NodeFencingModeWebhook,
+		WebhookURL: server.URL,
+		Retries:    3,
+	}
+
+	node, fenced, err := h.fence(downNode.DeepCopy(), downSince, policy)
+	require.NoError(t, err)
+	assert.False(t, fenced)
+	assert.Contains(t, getFencingStatus(node, downSince).Message, "503")
+
+	h.now = func() time.Time { return now.Add(defaultFencingRetryInterval) }
+	_, fenced, err = h.fence(node, downSince, policy)
+	require.NoError(t, err)
+	assert.True(t, fenced)
+	assert.Equal(t, []fencingWebhookRequest{
+		{Node: "node-1", Addresses: []string{"10.0.0.11"}},
+		{Node: "node-1", Addresses: []string{"10.0.0.11"}},
+	}, requests)
+}
@@ -0,0 +1,375 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/config"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
+	"github.com/cloudweav/cloudweav/pkg/settings"
+)
+
+const (
+	nodeHealthControllerName = "node-health-controller"
+
+	NodeDiskFailing         corev1.NodeConditionType = "DiskFailing"
+	NodeMemoryErrors        corev1.NodeConditionType = "MemoryErrors"
+	NodeNetworkLinkFlapping corev1.NodeConditionType = "NetworkLinkFlapping"
+	NodeOverheating         corev1.NodeConditionType = "Overheating"
+
+	nodeHealthReasonThresholdExceeded = "ThresholdExceeded"
+	nodeHealthReasonHealthy           = "Healthy"
+
+	// HealthActionsAnnotationKey records the actions taken on the node because of its hardware health
+	HealthActionsAnnotationKey = "cloudweavhci.io/node-health-actions"
+	// healthEvacuatedAnnotationKey is the unhealthy conditions the node has been evacuated for
+	healthEvacuatedAnnotationKey = "cloudweavhci.io/node-health-evacuated"
+
+	HealthActionCordon          = "Cordon"
+	HealthActionMigrate         = "Migrate"
+	HealthActionMigrationFailed = "MigrationFailed"
+	HealthActionSkip            = "Skip"
+
+	maxHealthActions = 20
+)
+
+var nodeHealthConditionTypes = []corev1.NodeConditionType{
+	NodeDiskFailing,
+	NodeMemoryErrors,
+	NodeNetworkLinkFlapping,
+	NodeOverheating,
+}
+
+// NodeHealthAction is an action taken on the node because of its hardware health
+type NodeHealthAction struct {
+	Time    string `json:"time"`
+	Action  string `json:"action"`
+	Message string `json:"message"`
+}
+
+// nodeHealthHandler turns the NodeHealth reported by the node agent into node conditions, and evacuates the unhealthy
+// nodes when the node-health-policy setting allows it
+type nodeHealthHandler struct {
+	nodeHealthController        ctlcloudweavv1.NodeHealthController
+	nodeHealthCache             ctlcloudweavv1.NodeHealthCache
+	nodes                       ctlcorev1.NodeClient
+	nodeCache                   ctlcorev1.NodeCache
+	virtualMachineInstanceCache ctlkubevirtv1.VirtualMachineInstanceCache
+	migrations                  ctlkubevirtv1.VirtualMachineInstanceMigrationClient
+	recorder                    record.EventRecorder
+	now                         func() time.Time
+}
+
+// HealthRegister registers the node health controller
+func HealthRegister(ctx context.Context, management *config.Management, _ config.Options) error {
+	nodeHealths := management.CloudweavFactory.Cloudweavhci().V1beta1().NodeHealth()
+	nodes := management.CoreFactory.Core().V1().Node()
+	setting := management.CloudweavFactory.Cloudweavhci().V1beta1().Setting()
+	vmis := management.VirtFactory.Kubevirt().V1().VirtualMachineInstance()
+	migrations := management.VirtFactory.Kubevirt().V1().VirtualMachineInstanceMigration()
+	handler := &nodeHealthHandler{
+		nodeHealthController:        nodeHealths,
+		nodeHealthCache:             nodeHealths.Cache(),
+		nodes:                       nodes,
+		nodeCache:                   nodes.Cache(),
+		virtualMachineInstanceCache: vmis.Cache(),
+		migrations:                  migrations,
+		recorder:                    management.NewRecorder("cloudweav-"+nodeHealthControllerName, "", ""),
+		now:                         time.Now,
+	}
+
+	nodeHealths.OnChange(ctx, nodeHealthControllerName, handler.OnChanged)
+	setting.OnChange(ctx, nodeHealthControllerName, handler.OnNodeHealthPolicyChanged)
+	return nil
+}
+
+// OnChanged updates the health conditions of the node. If the policy allows it, an unhealthy node is cordoned and its
+// VMs are live migrated once for each set of unhealthy conditions. The node is never uncordoned automatically.
+func (h *nodeHealthHandler) OnChanged(_ string, nodeHealth *cloudweavv1.NodeHealth) (*cloudweavv1.NodeHealth, error) {
+	if nodeHealth == nil || nodeHealth.DeletionTimestamp != nil {
+		return nodeHealth, nil
+	}
+
+	node, err := h.nodeCache.Get(nodeHealth.Name)
+	if apierrors.IsNotFound(err) {
+		return nodeHealth, nil
+	} else if err != nil {
+		return nodeHealth, err
+	}
+
+	policy, err := settings.DecodeNodeHealthPolicy(settings.NodeHealthPolicySet.Get())
+	if err != nil {
+		return nodeHealth, err
+	}
+
+	problems := checkNodeHealth(&nodeHealth.Status, policy)
+	if node, err = h.updateHealthConditions(node, problems); err != nil {
+		return nodeHealth, err
+	}
+
+	if _, err := h.evacuate(node, problems, policy); err != nil {
+		return nodeHealth, err
+	}
+	return nodeHealth, nil
+}
+
+// OnNodeHealthPolicyChanged checks the health of all the nodes again with the new thresholds
+func (h *nodeHealthHandler) OnNodeHealthPolicyChanged(_ string, setting *cloudweavv1.Setting) (*cloudweavv1.Setting, error) {
+	if setting == nil || setting.DeletionTimestamp != nil || setting.Name != settings.NodeHealthPolicySettingName {
+		return setting, nil
+	}
+
+	nodeHealths, err := h.nodeHealthCache.List(labels.Everything())
+	if err != nil {
+		return setting, err
+	}
+	for _, nodeHealth := range nodeHealths {
+		h.nodeHealthController.Enqueue(nodeHealth.Name)
+	}
+	return setting, nil
+}
+
+// checkNodeHealth returns the problems of each health condition, no problems means the condition is healthy
+func checkNodeHealth(status *cloudweavv1.NodeHealthStatus, policy *settings.NodeHealthPolicy) map[corev1.NodeConditionType][]string {
+	problems := make(map[corev1.NodeConditionType][]string)
+
+	for _, disk := range status.Disks {
+		if !disk.SMARTPassed {
+			problems[NodeDiskFailing] = append(problems[NodeDiskFailing], fmt.Sprintf("disk %s failed the SMART self-assessment", disk.Name))
+		}
+		if exceeds(disk.ReallocatedSectors, policy.ReallocatedSectors) {
+			problems[NodeDiskFailing] = append(problems[NodeDiskFailing], fmt.Sprintf("disk %s has %d reallocated sectors", disk.Name, disk.ReallocatedSectors))
+		}
+		if exceeds(disk.PendingSectors, policy.PendingSectors) {
+			problems[NodeDiskFailing] = append(problems[NodeDiskFailing], fmt.Sprintf("disk %s has %d pending sectors", disk.Name, disk.PendingSectors))
+		}
+		if exceeds(disk.MediaErrors, policy.MediaErrors) {
+			problems[NodeDiskFailing] = append(problems[NodeDiskFailing], fmt.Sprintf("disk %s has %d media errors", disk.Name, disk.MediaErrors))
+		}
+		if exceeds(disk.TemperatureCelsius, policy.TemperatureCelsius) {
+			problems[NodeOverheating] = append(problems[NodeOverheating], fmt.Sprintf("disk %s is %d°C", disk.Name, disk.TemperatureCelsius))
+		}
+	}
+
+	if exceeds(status.Memory.CorrectableErrors, policy.CorrectableMemoryErrors) {
+		problems[NodeMemoryErrors] = append(problems[NodeMemoryErrors], fmt.Sprintf("%d correctable memory errors", status.Memory.CorrectableErrors))
+	}
+	if exceeds(status.Memory.UncorrectableErrors, policy.UncorrectableMemoryErrors) {
+		problems[NodeMemoryErrors] = append(problems[NodeMemoryErrors], fmt.Sprintf("%d uncorrectable memory errors", status.Memory.UncorrectableErrors))
+	}
+
+	for _, link := range status.Links {
+		if exceeds(link.Flaps, policy.LinkFlaps) {
+			problems[NodeNetworkLinkFlapping] = append(problems[NodeNetworkLinkFlapping], fmt.Sprintf("link %s flapped %d times", link.Name, link.Flaps))
+		}
+	}
+
+	for _, temperature := range status.Temperatures {
+		if exceeds(temperature.Celsius, policy.TemperatureCelsius) {
+			problems[NodeOverheating] = append(problems[NodeOverheating], fmt.Sprintf("sensor %s is %d°C", temperature.Name, temperature.Celsius))
+		}
+	}
+	return problems
+}
+
+// exceeds returns whether the value reaches the threshold, a zero threshold disables the check
+func exceeds(value, threshold int64) bool {
+	return threshold > 0 && value >= threshold
+}
+
+func (h *nodeHealthHandler) updateHealthConditions(node *corev1.Node, problems map[corev1.NodeConditionType][]string) (*corev1.Node, error) {
+	toUpdate := node.DeepCopy()
+	changed := false
+	for _, conditionType := range nodeHealthConditionTypes {
+		cond := corev1.NodeCondition{
+			Type:   conditionType,
+			Status: corev1.ConditionFalse,
+			Reason: nodeHealthReasonHealthy,
+		}
+		if len(problems[conditionType]) > 0 {
+			cond.Status = corev1.ConditionTrue
+			cond.Reason = nodeHealthReasonThresholdExceeded
+			cond.Message = strings.Join(problems[conditionType], ", ")
+		}
+
+		current := getNodeCondition(node.Status.Conditions, conditionType)
+		if current != nil && current.Status == cond.Status && current.Reason == cond.Reason && current.Message == cond.Message {
+			continue
+		}
+		setNodeCondition(toUpdate, cond, h.now())
+		changed = true
+	}
+
+	if !changed {
+		return node, nil
+	}
+	return h.nodes.UpdateStatus(toUpdate)
+}
+
+// evacuate cordons the unhealthy node and live migrates its VMs. The node is cordoned before the migrations are
+// created, and the migrations are named after the evacuation, so a retry after a failure doesn't migrate a VM twice.
+func (h *nodeHealthHandler) evacuate(node *corev1.Node, problems map[corev1.NodeConditionType][]string, policy *settings.NodeHealthPolicy) (*corev1.Node, error) {
+	unhealthy := make([]string, 0, len(problems))
+	for conditionType := range problems {
+		unhealthy = append(unhealthy, string(conditionType))
+	}
+	sort.Strings(unhealthy)
+	signature := strings.Join(unhealthy, ",")
+
+	evacuated, ok := node.Annotations[healthEvacuatedAnnotationKey]
+	if signature == "" {
+		// the node recovered, it's evacuated again if it becomes unhealthy
+		if !ok {
+			return node, nil
+		}
+		toUpdate := node.DeepCopy()
+		delete(toUpdate.Annotations, healthEvacuatedAnnotationKey)
+		return h.nodes.Update(toUpdate)
+	}
+	if !policy.Evacuate || evacuated == signature {
+		return node, nil
+	}
+
+	var err error
+	actions := getHealthActions(node)
+	if !node.Spec.Unschedulable {
+		actions = h.recordHealthAction(node, actions, corev1.EventTypeWarning, HealthActionCordon,
+			fmt.Sprintf("cordon the node because of %s", signature))
+		if node, err = h.updateHealthActions(node, actions, func(toUpdate *corev1.Node) {
+			toUpdate.Spec.Unschedulable = true
+		}); err != nil {
+			return node, err
+		}
+	}
+
+	vmis, err := h.virtualMachineInstanceCache.List(corev1.NamespaceAll, labels.SelectorFromSet(map[string]string{
+		kubevirtv1.NodeNameLabel: node.Name,
+	}))
+	if err != nil {
+		return node, err
+	}
+	sort.Slice(vmis, func(i, j int) bool {
+		return vmis[i].Namespace+"/"+vmis[i].Name < vmis[j].Namespace+"/"+vmis[j].Name
+	})
+	for _, vmi := range vmis {
+		vmName := vmi.Namespace + "/" + vmi.Name
+		if reason := migrationBlocker(vmi); reason != "" {
+			actions = h.recordHealthAction(node, actions, corev1.EventTypeWarning, HealthActionSkip,
+				fmt.Sprintf("VM %s can't be migrated: %s", vmName, reason))
+			continue
+		}
+		if vmi.Status.MigrationState != nil && !vmi.Status.MigrationState.Completed {
+			continue
+		}
+		_, err := h.migrations.Create(&kubevirtv1.VirtualMachineInstanceMigration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      healthMigrationName(vmi, node.Name, signature),
+				Namespace: vmi.Namespace,
+			},
+			Spec: kubevirtv1.VirtualMachineInstanceMigrationSpec{
+				VMIName: vmi.Name,
+			},
+		})
+		if apierrors.IsAlreadyExists(err) {
+			// created by a previous attempt of the same evacuation
+			continue
+		} else if err != nil {
+			logrus.Errorf("failed to migrate VM %s from unhealthy node %s: %v", vmName, node.Name, err)
+			actions = h.recordHealthAction(node, actions, corev1.EventTypeWarning, HealthActionMigrationFailed,
+				fmt.Sprintf("failed to migrate VM %s: %v", vmName, err))
+			continue
+		}
+		actions = h.recordHealthAction(node, actions, corev1.EventTypeNormal, HealthActionMigrate,
+			fmt.Sprintf("migrate VM %s", vmName))
+	}
+
+	return h.updateHealthActions(node, actions, func(toUpdate *corev1.Node) {
+		toUpdate.Annotations[healthEvacuatedAnnotationKey] = signature
+	})
+}
+
+// healthMigrationName returns the name of the migration evacuating the VMI from the unhealthy node. The name changes
+// with the unhealthy conditions and with the last migration of the VMI, a VM migrated back to the node is evacuated
+// again.
+func healthMigrationName(vmi *kubevirtv1.VirtualMachineInstance, nodeName, signature string) string {
+	key := nodeName + "/" + signature
+	if vmi.Status.MigrationState != nil {
+		key += "/" + string(vmi.Status.MigrationState.MigrationUID)
+	}
+	return name.SafeConcatName(vmi.Name, "health", name.Hex(key, 8))
+}
+
+// updateHealthActions updates the node with the actions taken on it and the mutation
+func (h *nodeHealthHandler) updateHealthActions(node *corev1.Node, actions []NodeHealthAction, mutate func(toUpdate *corev1.Node)) (*corev1.Node, error) {
+	actionsStr, err := json.Marshal(actions)
+	if err != nil {
+		return node, err
+	}
+	toUpdate := node.DeepCopy()
+	if toUpdate.Annotations == nil {
+		toUpdate.Annotations = make(map[string]string)
+	}
+	toUpdate.Annotations[HealthActionsAnnotationKey] = string(actionsStr)
+	mutate(toUpdate)
+	return h.nodes.Update(toUpdate)
+}
+
+// migrationBlocker returns why the VMI can't be live migrated
+func migrationBlocker(vmi *kubevirtv1.VirtualMachineInstance) string {
+	for _, cond := range vmi.Status.Conditions {
+		if cond.Type == kubevirtv1.VirtualMachineInstanceIsMigratable && cond.Status == corev1.ConditionFalse {
+			if cond.Reason != "" {
+				return cond.Reason
+			}
+			return cond.Message
+		}
+	}
+	return ""
+}
+
+func getHealthActions(node *corev1.Node) []NodeHealthAction {
+	var actions []NodeHealthAction
+	if value := node.Annotations[HealthActionsAnnotationKey]; value != "" {
+		if err := json.Unmarshal([]byte(value), &actions); err != nil {
+			logrus.Warnf("failed to parse the health actions of node %s: %v", node.Name, err)
+		}
+	}
+	return actions
+}
+
+// recordHealthAction emits an event of the action and appends it to the latest actions of the node
+func (h *nodeHealthHandler) recordHealthAction(node *corev1.Node, actions []NodeHealthAction, eventType, action, message string) []NodeHealthAction {
+	nodeReference := &corev1.ObjectReference{
+		Name: node.Name,
+		UID:  types.UID(node.Name),
+		Kind: "Node",
+	}
+	h.recorder.Event(nodeReference, eventType, "NodeHealth"+action, message)
+
+	actions = append(actions, NodeHealthAction{
+		Time:    h.now().UTC().Format(time.RFC3339),
+		Action:  action,
+		Message: message,
+	})
+	if len(actions) > maxHealthActions {
+		actions = actions[len(actions)-maxHealthActions:]
+	}
+	return actions
+}
@@ -0,0 +1,191 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/fake"
+	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+)
+
+var healthCheckTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newHealthVMI(name string, migratable bool) *kubevirtv1.VirtualMachineInstance {
+	vmi := &kubevirtv1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{kubevirtv1.NodeNameLabel: "node-1"},
+		},
+		Status: kubevirtv1.VirtualMachineInstanceStatus{
+			NodeName: "node-1",
+		},
+	}
+	if !migratable {
+		vmi.Status.Conditions = []kubevirtv1.VirtualMachineInstanceCondition{{
+			Type:   kubevirtv1.VirtualMachineInstanceIsMigratable,
+			Status: corev1.ConditionFalse,
+			Reason: kubevirtv1.VirtualMachineInstanceReasonDisksNotMigratable,
+		}}
+	}
+	return vmi
+}
+
+func newHealthHandler() (*nodeHealthHandler, *k8sfake.Clientset, *fake.Clientset) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	k8sclientset := k8sfake.NewSimpleClientset(node)
+	clientset := fake.NewSimpleClientset(newHealthVMI("vm-1", true), newHealthVMI("vm-2", false))
+	return &nodeHealthHandler{
+		nodes:                       &fakeNodeController{clientset: k8sclientset},
+		nodeCache:                   fakeclients.NodeCache(k8sclientset.CoreV1().Nodes),
+		virtualMachineInstanceCache: fakeclients.VirtualMachineInstanceCache(clientset.KubevirtV1().VirtualMachineInstances),
+		migrations:                  fakeclients.VirtualMachineInstanceMigrationClient(clientset.KubevirtV1().VirtualMachineInstanceMigrations),
+		recorder:                    record.NewFakeRecorder(10),
+		now:                         func() time.Time { return healthCheckTime },
+	}, k8sclientset, clientset
+}
+
+func Test_checkNodeHealth(t *testing.T) {
+	policy, err := settings.DecodeNodeHealthPolicy(settings.InitNodeHealthPolicy())
+	require.NoError(t, err)
+
+	status := &cloudweavv1.NodeHealthStatus{
+		Disks: []cloudweavv1.DiskHealth{
+			{Name: "sda", SMARTPassed: true, ReallocatedSectors: 48, TemperatureCelsius: 38},
+			{Name: "sdb", SMARTPassed: true, ReallocatedSectors: 2},
+		},
+		Memory: cloudweavv1.MemoryHealth{CorrectableErrors: 5},
+		Links: []cloudweavv1.LinkHealth{
+			{Name: "eno1", Flaps: 12},
+			{Name: "eno2", Flaps: 1},
+		},
+		Temperatures: []cloudweavv1.TemperatureHealth{
+			{Name: "coretemp/Package id 0", Celsius: 92},
+		},
+	}
+	assert.Equal(t, map[corev1.NodeConditionType][]string{
+		NodeDiskFailing:         {"disk sda has 48 reallocated sectors"},
+		NodeNetworkLinkFlapping: {"link eno1 flapped 12 times"},
+		NodeOverheating:         {"sensor coretemp/Package id 0 is 92°C"},
+	}, checkNodeHealth(status, policy))
+
+	// a zero threshold disables the check
+	policy.ReallocatedSectors = 0
+	policy.LinkFlaps = 0
+	policy.TemperatureCelsius = 0
+	assert.Empty(t, checkNodeHealth(status, policy))
+}
+
+func Test_nodeHealthEvacuation(t *testing.T) {
+	defer settings.NodeHealthPolicySet.Set(settings.InitNodeHealthPolicy())
+	nodeHealth := &cloudweavv1.NodeHealth{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: cloudweavv1.NodeHealthStatus{
+			Disks: []cloudweavv1.DiskHealth{{Name: "sda", SMARTPassed: false}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		evacuate bool
+	}{
+		{
+			name:     "the node is only reported without evacuation",
+			evacuate: false,
+		},
+		{
+			name:     "the node is cordoned and its VMs are migrated",
+			evacuate: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := settings.DecodeNodeHealthPolicy(settings.InitNodeHealthPolicy())
+			require.NoError(t, err)
+			policy.Evacuate = tc.evacuate
+			policyStr, err := json.Marshal(policy)
+			require.NoError(t, err)
+			require.NoError(t, settings.NodeHealthPolicySet.Set(string(policyStr)))
+
+			h, k8sclientset, clientset := newHealthHandler()
+			_, err = h.OnChanged(nodeHealth.Name, nodeHealth)
+			require.NoError(t, err)
+
+			node, err := k8sclientset.CoreV1().Nodes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+			require.NoError(t, err)
+			cond := getNodeCondition(node.Status.Conditions, NodeDiskFailing)
+			require.NotNil(t, cond)
+			assert.Equal(t, corev1.ConditionTrue, cond.Status)
+			assert.Equal(t, "disk sda failed the SMART self-assessment", cond.Message)
+			cond = getNodeCondition(node.Status.Conditions, NodeMemoryErrors)
+			require.NotNil(t, cond)
+			assert.Equal(t, corev1.ConditionFalse, cond.Status)
+			assert.Equal(t, tc.evacuate, node.Spec.Unschedulable)
+
+			migrations, err := clientset.KubevirtV1().VirtualMachineInstanceMigrations("default").List(context.TODO(), metav1.ListOptions{})
+			require.NoError(t, err)
+			if !tc.evacuate {
+				assert.Empty(t, migrations.Items)
+				assert.Empty(t, node.Annotations[HealthActionsAnnotationKey])
+				return
+			}
+			require.Len(t, migrations.Items, 1)
+			assert.Equal(t, "vm-1", migrations.Items[0].Spec.VMIName)
+
+			var actions []NodeHealthAction
+			require.NoError(t, json.Unmarshal([]byte(node.Annotations[HealthActionsAnnotationKey]), &actions))
+			assert.Equal(t, []NodeHealthAction{
+				{Time: "2024-01-01T00:00:00Z", Action: HealthActionCordon, Message: "cordon the node because of DiskFailing"},
+				{Time: "2024-01-01T00:00:00Z", Action: HealthActionMigrate, Message: "migrate VM default/vm-1"},
+				{Time: "2024-01-01T00:00:00Z", Action: HealthActionSkip, Message: "VM default/vm-2 can't be migrated: " + kubevirtv1.VirtualMachineInstanceReasonDisksNotMigratable},
+			}, actions)
+
+			// the node is evacuated once for the same problems
+			evacuatedActions := node.Annotations[HealthActionsAnnotationKey]
+			_, err = h.OnChanged(nodeHealth.Name, nodeHealth)
+			require.NoError(t, err)
+			node, err = k8sclientset.CoreV1().Nodes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, evacuatedActions, node.Annotations[HealthActionsAnnotationKey])
+			migrations, err = clientset.KubevirtV1().VirtualMachineInstanceMigrations("default").List(context.TODO(), metav1.ListOptions{})
+			require.NoError(t, err)
+			assert.Len(t, migrations.Items, 1)
+
+			// a retry after failing to record the evacuation doesn't migrate the VMs twice
+			node = node.DeepCopy()
+			delete(node.Annotations, healthEvacuatedAnnotationKey)
+			_, err = k8sclientset.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+			require.NoError(t, err)
+			_, err = h.OnChanged(nodeHealth.Name, nodeHealth)
+			require.NoError(t, err)
+			migrations, err = clientset.KubevirtV1().VirtualMachineInstanceMigrations("default").List(context.TODO(), metav1.ListOptions{})
+			require.NoError(t, err)
+			assert.Len(t, migrations.Items, 1)
+			node, err = k8sclientset.CoreV1().Nodes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, "DiskFailing", node.Annotations[healthEvacuatedAnnotationKey])
+
+			// the recovered node stays cordoned
+			_, err = h.OnChanged(nodeHealth.Name, &cloudweavv1.NodeHealth{ObjectMeta: nodeHealth.ObjectMeta})
+			require.NoError(t, err)
+			node, err = k8sclientset.CoreV1().Nodes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.True(t, node.Spec.Unschedulable)
+			assert.Equal(t, corev1.ConditionFalse, getNodeCondition(node.Status.Conditions, NodeDiskFailing).Status)
+			assert.NotContains(t, node.Annotations, healthEvacuatedAnnotationKey)
+		})
+	}
+}
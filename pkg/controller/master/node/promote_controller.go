@@ -3,6 +3,7 @@ package node
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -56,10 +57,14 @@ const (
 
 	defaultSpecManagementNumber = 3
 
+	// a failed or interrupted demotion is retried with a new job until the attempts run out
+	demoteMaxAttempts = 3
+
 	promoteRootMountPath = "/host"
 
 	promoteScriptsMountPath = "/cloudweav-helpers"
 	promoteScript           = "/cloudweav-helpers/promote.sh"
+	demoteScript            = "/cloudweav-helpers/demote.sh"
 	helperConfigMapName     = "cloudweav-helpers"
 	releaseAppCloudweavName = "cloudweav"
 )
@@ -75,6 +80,7 @@ var (
 type PromoteHandler struct {
 	nodes     ctlcorev1.NodeController
 	nodeCache ctlcorev1.NodeCache
+	podCache  ctlcorev1.PodCache
 	jobs      ctlbatchv1.JobClient
 	jobCache  ctlbatchv1.JobCache
 	recorder  record.EventRecorder
@@ -85,12 +91,14 @@ type PromoteHandler struct {
 // PromoteRegister registers the node controller
 func PromoteRegister(ctx context.Context, management *config.Management, options config.Options) error {
 	nodes := management.CoreFactory.Core().V1().Node()
+	pods := management.CoreFactory.Core().V1().Pod()
 	jobs := management.BatchFactory.Batch().V1().Job()
 	appCache := management.CatalogFactory.Catalog().V1().App().Cache()
 
 	promoteController := &PromoteHandler{
 		nodes:     nodes,
 		nodeCache: nodes.Cache(),
+		podCache:  pods.Cache(),
 		jobs:      jobs,
 		jobCache:  jobs.Cache(),
 		appCache:  appCache,
@@ -108,11 +116,30 @@ func PromoteRegister(ctx context.Context, management *config.Management, options
 // OnNodeChanged automate the upgrade of node roles
 // If the number of managements in the cluster is less than spec number,
 // the cloudweav oldest node will be automatically promoted to be management.
+// An explicit role request of the node is served first.
 func (h *PromoteHandler) OnNodeChanged(_ string, node *corev1.Node) (*corev1.Node, error) {
 	if node == nil || node.DeletionTimestamp != nil {
 		return node, nil
 	}
 
+	// the demote request is withdrawn, a running demote job is not interrupted
+	if node.Annotations[RoleRequestAnnotationKey] != RoleRequestDemote &&
+		isDemoteStatusIn(node, DemoteStatusDraining, PromoteStatusFailed, PromoteStatusUnknown) {
+		return h.cancelDemote(node)
+	}
+
+	switch node.Annotations[RoleRequestAnnotationKey] {
+	case RoleRequestDemote:
+		return h.demote(node)
+	case RoleRequestPromote:
+		// the promotion is served by selectPromoteNode, clear the request once the node joins the control plane
+		if IsManagementRole(node) {
+			toUpdate := node.DeepCopy()
+			delete(toUpdate.Annotations, RoleRequestAnnotationKey)
+			return h.nodes.Update(toUpdate)
+		}
+	}
+
 	nodeList, err := h.nodeCache.List(labels.Everything())
 	if err != nil {
 		return nil, err
@@ -152,6 +179,10 @@ func (h *PromoteHandler) OnJobChanged(_ string, job *batchv1.Job) (*batchv1.Job,
 		return job, nil
 	}
 
+	if nodeName, ok := job.Labels[CloudweavDemoteNodeLabelKey]; ok {
+		return h.onDemoteJobChanged(job, nodeName)
+	}
+
 	nodeName, ok := job.Labels[CloudweavPromoteNodeLabelKey]
 	if !ok {
 		return job, nil
@@ -184,12 +215,16 @@ func (h *PromoteHandler) OnJobRemove(_ string, job *batchv1.Job) (*batchv1.Job,
 	}
 
 	nodeName, ok := job.Labels[CloudweavPromoteNodeLabelKey]
-	if !ok {
+	demoteNodeName, isDemote := job.Labels[CloudweavDemoteNodeLabelKey]
+	if !ok && !isDemote {
 		return job, nil
 	}
 	if ConditionJobFailed.IsTrue(job) || ConditionJobComplete.IsTrue(job) {
 		return job, nil
 	}
+	if isDemote {
+		nodeName = demoteNodeName
+	}
 
 	node, err := h.nodeCache.Get(nodeName)
 	switch {
@@ -199,7 +234,10 @@ func (h *PromoteHandler) OnJobRemove(_ string, job *batchv1.Job) (*batchv1.Job,
 		return job, err
 	}
 
-	if isPromoteStatusIn(node, PromoteStatusRunning) {
+	if isDemote && isCurrentDemoteJob(job, node) {
+		return h.setDemoteResult(job, node, PromoteStatusUnknown)
+	}
+	if !isDemote && isPromoteStatusIn(node, PromoteStatusRunning) {
 		return h.setPromoteResult(job, node, PromoteStatusUnknown)
 	}
 
@@ -221,19 +259,187 @@ func (h *PromoteHandler) promote(node *corev1.Node) (*corev1.Node, error) {
 	return startedNode, nil
 }
 
-func (h *PromoteHandler) logPromoteEvent(node *corev1.Node, status string) {
-	preStatus := node.Annotations[CloudweavPromoteStatusAnnotationKey]
+// demote removes the node from etcd with a job if the other members keep the quorum, the promotion then runs elsewhere.
+// The node enters maintenance mode first, its VMs are migrated before it leaves the control plane.
+func (h *PromoteHandler) demote(node *corev1.Node) (*corev1.Node, error) {
+	switch node.Annotations[CloudweavDemoteStatusAnnotationKey] {
+	case "":
+		return h.startDemote(node)
+	case DemoteStatusDraining:
+		// wait until the maintenance controller migrated the VMs
+		if node.Annotations[MaintainStatusAnnotationKey] != MaintainStatusComplete {
+			return node, nil
+		}
+		return h.createDemoteJob(node, 1)
+	case PromoteStatusFailed, PromoteStatusUnknown:
+		if attempt := getDemoteAttempt(node); attempt < demoteMaxAttempts {
+			return h.createDemoteJob(node, attempt+1)
+		}
+	}
+	return node, nil
+}
+
+// startDemote checks the etcd quorum and puts the node into maintenance mode
+func (h *PromoteHandler) startDemote(node *corev1.Node) (*corev1.Node, error) {
+	nodeList, err := h.nodeCache.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	etcdPods, err := h.podCache.List(EtcdNamespace, EtcdPodSelector)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckDemotion(GetControlPlaneStatus(nodeList, etcdPods), node); err != nil {
+		h.recorder.Event(nodeReference(node), corev1.EventTypeWarning, "NodeDemoteRejected", err.Error())
+		toUpdate := node.DeepCopy()
+		delete(toUpdate.Annotations, RoleRequestAnnotationKey)
+		return h.nodes.Update(toUpdate)
+	}
+
+	h.logDemoteEvent(node, DemoteStatusDraining)
+	toUpdate := node.DeepCopy()
+	toUpdate.Annotations[CloudweavDemoteStatusAnnotationKey] = DemoteStatusDraining
+	if _, ok := node.Annotations[MaintainStatusAnnotationKey]; !ok {
+		toUpdate.Annotations[DrainRequestedAnnotationKey] = "true"
+	}
+	return h.nodes.Update(toUpdate)
+}
+
+// createDemoteJob marks the node into demote status and creates the demote job of the attempt on another node
+func (h *PromoteHandler) createDemoteJob(node *corev1.Node, attempt int) (*corev1.Node, error) {
+	// wait until node metadata show up, the job is owned by the node
+	if node.Kind == "" || node.APIVersion == "" {
+		h.nodes.EnqueueAfter(node.Name, time.Second*10)
+		return node, nil
+	}
+
+	image, err := utilCatalog.FetchAppChartImage(h.appCache, h.namespace, releaseAppCloudweavName, []string{"generalJob", "image"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cloudweav image (%s): %v", image.ImageName(), err)
+	}
+
+	h.logDemoteEvent(node, PromoteStatusRunning)
+	toUpdate := node.DeepCopy()
+	toUpdate.Annotations[CloudweavDemoteStatusAnnotationKey] = PromoteStatusRunning
+	toUpdate.Annotations[CloudweavDemoteAttemptAnnotationKey] = strconv.Itoa(attempt)
+	updated, err := h.nodes.Update(toUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := h.jobs.Create(buildDemoteJob(h.namespace, node, image.ImageName(), attempt)); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// cancelDemote clears the demote status of a withdrawn request, the node stays in maintenance mode
+func (h *PromoteHandler) cancelDemote(node *corev1.Node) (*corev1.Node, error) {
+	h.recorder.Event(nodeReference(node), corev1.EventTypeNormal, "NodeDemoteCanceled",
+		fmt.Sprintf("Node %s demotion is canceled", node.Name))
+	toUpdate := node.DeepCopy()
+	delete(toUpdate.Annotations, CloudweavDemoteStatusAnnotationKey)
+	delete(toUpdate.Annotations, CloudweavDemoteAttemptAnnotationKey)
+	return h.nodes.Update(toUpdate)
+}
+
+func (h *PromoteHandler) onDemoteJobChanged(job *batchv1.Job, nodeName string) (*batchv1.Job, error) {
+	node, err := h.nodeCache.Get(nodeName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return job, h.deleteJob(job, metav1.DeletePropagationBackground)
+		}
+		return job, err
+	}
+
+	// the result of a previous attempt is already recorded
+	if !isCurrentDemoteJob(job, node) {
+		return job, nil
+	}
+
+	if ConditionJobComplete.IsTrue(job) {
+		return h.setDemoteResult(job, node, PromoteStatusComplete)
+	}
+
+	if ConditionJobFailed.IsTrue(job) {
+		return h.setDemoteResult(job, node, PromoteStatusFailed)
+	}
+
+	return job, nil
+}
+
+// setDemoteResult updates the demote status of a failed attempt, which the next attempt retries. Once the demotion
+// is complete, the node is kept as a worker and the demote annotations are removed.
+func (h *PromoteHandler) setDemoteResult(job *batchv1.Job, node *corev1.Node, status string) (*batchv1.Job, error) {
+	h.logDemoteEvent(node, status)
+	toUpdate := node.DeepCopy()
+	if status == PromoteStatusComplete {
+		delete(toUpdate.Annotations, RoleRequestAnnotationKey)
+		delete(toUpdate.Annotations, CloudweavDemoteStatusAnnotationKey)
+		delete(toUpdate.Annotations, CloudweavDemoteAttemptAnnotationKey)
+		// the node is not promoted again until its control plane policy is changed
+		if err := SetControlPlanePolicy(toUpdate, ControlPlanePolicyExcluded); err != nil {
+			return job, err
+		}
+	} else {
+		toUpdate.Annotations[CloudweavDemoteStatusAnnotationKey] = status
+		if getDemoteAttempt(node) >= demoteMaxAttempts {
+			h.recorder.Event(nodeReference(node), corev1.EventTypeWarning, "NodeDemoteAborted",
+				fmt.Sprintf("Node %s demotion failed %d times, withdraw the role request to cancel it", node.Name, demoteMaxAttempts))
+		}
+	}
+	if _, err := h.nodes.Update(toUpdate); err != nil {
+		return job, err
+	}
+
+	// re-run the promotion on the other nodes
+	if status == PromoteStatusComplete {
+		h.nodes.Enqueue(node.Name)
+	}
+	return job, nil
+}
+
+// isCurrentDemoteJob returns whether the job runs the current demote attempt of the node
+func isCurrentDemoteJob(job *batchv1.Job, node *corev1.Node) bool {
+	return isDemoteStatusIn(node, PromoteStatusRunning) && job.Name == buildDemoteJobName(node.Name, getDemoteAttempt(node))
+}
+
+func getDemoteAttempt(node *corev1.Node) int {
+	attempt, err := strconv.Atoi(node.Annotations[CloudweavDemoteAttemptAnnotationKey])
+	if err != nil {
+		return 0
+	}
+	return attempt
+}
+
+func (h *PromoteHandler) logDemoteEvent(node *corev1.Node, status string) {
+	preStatus := node.Annotations[CloudweavDemoteStatusAnnotationKey]
 	eventType := corev1.EventTypeNormal
 	switch status {
 	case PromoteStatusUnknown, PromoteStatusFailed:
 		eventType = corev1.EventTypeWarning
 	}
-	nodeReference := &corev1.ObjectReference{
+	h.recorder.Event(nodeReference(node), eventType,
+		fmt.Sprintf("NodeDemote%s", strings.Title(status)),
+		fmt.Sprintf("Node %s demote status change: %s => %s", node.Name, preStatus, status))
+}
+
+func nodeReference(node *corev1.Node) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
 		Name: node.Name,
 		UID:  types.UID(node.Name),
 		Kind: "Node",
 	}
-	h.recorder.Event(nodeReference, eventType,
+}
+
+func (h *PromoteHandler) logPromoteEvent(node *corev1.Node, status string) {
+	preStatus := node.Annotations[CloudweavPromoteStatusAnnotationKey]
+	eventType := corev1.EventTypeNormal
+	switch status {
+	case PromoteStatusUnknown, PromoteStatusFailed:
+		eventType = corev1.EventTypeWarning
+	}
+	h.recorder.Event(nodeReference(node), eventType,
 		fmt.Sprintf("NodePromote%s", strings.Title(status)),
 		fmt.Sprintf("Node %s promote status change: %s => %s", node.Name, preStatus, status))
 }
@@ -273,6 +479,7 @@ func selectPromoteNode(nodeList []*corev1.Node) *corev1.Node {
 	var (
 		promoteNode                             *corev1.Node
 		healthyCloudweavWorkers                 []*corev1.Node
+		promoteRequested                        []*corev1.Node
 		managementPreferred                     []*corev1.Node
 		witnessPreferred                        []*corev1.Node
 		managementOrHealthyCloudweavWorkerZones = make(map[string]bool)
@@ -281,6 +488,11 @@ func selectPromoteNode(nodeList []*corev1.Node) *corev1.Node {
 		witnessPromoted                         bool
 	)
 
+	// wait until the demotion is completed or canceled, the demoted nodes are no longer etcd members.
+	if hasDemotingNode(nodeList) {
+		return nil
+	}
+
 	nodeNumber := len(nodeList)
 	canBeManagementNodeCount := nodeNumber
 	for _, node := range nodeList {
@@ -323,7 +535,9 @@ func selectPromoteNode(nodeList []*corev1.Node) *corev1.Node {
 			if zone != "" {
 				managementOrHealthyCloudweavWorkerZones[zone] = true
 			}
-			if _, found := node.Labels[CloudweavMgmtNodeLabelKey]; found {
+			if node.Annotations[RoleRequestAnnotationKey] == RoleRequestPromote {
+				promoteRequested = append(promoteRequested, node)
+			} else if _, found := node.Labels[CloudweavMgmtNodeLabelKey]; found {
 				managementPreferred = append(managementPreferred, node)
 			} else if _, found := node.Labels[CloudweavWitnessNodeLabelKey]; found {
 				witnessPreferred = append(witnessPreferred, node)
@@ -353,9 +567,11 @@ func selectPromoteNode(nodeList []*corev1.Node) *corev1.Node {
 
 	promoteNode = nil
 
-	// promote the management preferred node first
+	// promote the requested node first, then the management preferred node
 	getCandidate := func() []*corev1.Node {
-		if len(managementPreferred) > 0 {
+		if len(promoteRequested) > 0 {
+			return promoteRequested
+		} else if len(managementPreferred) > 0 {
 			return managementPreferred
 		} else if len(witnessPreferred) > 0 {
 			return witnessPreferred
@@ -378,6 +594,16 @@ func selectPromoteNode(nodeList []*corev1.Node) *corev1.Node {
 	return promoteNode
 }
 
+// hasDemotingNode returns whether the demotion of any node is not finished
+func hasDemotingNode(nodeList []*corev1.Node) bool {
+	for _, node := range nodeList {
+		if IsDemoting(node) {
+			return true
+		}
+	}
+	return false
+}
+
 func IsWitnessNode(node *corev1.Node, isManagement bool) bool {
 	_, found := node.Labels[CloudweavWitnessNodeLabelKey]
 	if !found {
@@ -616,3 +842,96 @@ func buildPromoteJob(namespace string, node *corev1.Node, promoteImage string) *
 func buildPromoteJobName(nodeName string) string {
 	return name.SafeConcatName("cloudweav", "promote", nodeName)
 }
+
+// buildDemoteJob builds the job removing the node from etcd and keeping it as a worker, it runs on another node
+// since the node may be down
+func buildDemoteJob(namespace string, node *corev1.Node, demoteImage string, attempt int) *batchv1.Job {
+	nodeName := node.Name
+	hostPathDirectory := corev1.HostPathDirectory
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      buildDemoteJobName(nodeName, attempt),
+			Namespace: namespace,
+			Labels: labels.Set{
+				CloudweavDemoteNodeLabelKey: nodeName,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: node.APIVersion,
+					Kind:       node.Kind,
+					Name:       nodeName,
+					UID:        node.UID,
+				},
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &promoteBackoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels.Set{
+						CloudweavDemoteNodeLabelKey: nodeName,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Affinity: &corev1.Affinity{
+						NodeAffinity: &corev1.NodeAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+								NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+									MatchExpressions: []corev1.NodeSelectorRequirement{{
+										Key:      corev1.LabelHostname,
+										Operator: corev1.NodeSelectorOpNotIn,
+										Values: []string{
+											nodeName,
+										},
+									}},
+								}},
+							},
+						},
+					},
+					RestartPolicy: corev1.RestartPolicyNever,
+					Volumes: []corev1.Volume{{
+						Name: `host-root`,
+						VolumeSource: corev1.VolumeSource{
+							HostPath: &corev1.HostPathVolumeSource{
+								Path: "/", Type: &hostPathDirectory,
+							},
+						},
+					}, {
+						Name: "helpers",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: helperConfigMapName,
+								},
+							},
+						},
+					}},
+					ServiceAccountName: "cloudweav",
+					Containers: []corev1.Container{
+						{
+							Name:    "demote",
+							Image:   demoteImage,
+							Command: []string{"sh"},
+							Args:    []string{"-e", demoteScript},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "host-root", MountPath: promoteRootMountPath, ReadOnly: true},
+								{Name: "helpers", MountPath: promoteScriptsMountPath},
+							},
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Env: []corev1.EnvVar{
+								{
+									Name:  "CLOUDWEAV_DEMOTE_NODE_NAME",
+									Value: nodeName,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func buildDemoteJobName(nodeName string, attempt int) string {
+	return name.SafeConcatName("cloudweav", "demote", nodeName, strconv.Itoa(attempt))
+}
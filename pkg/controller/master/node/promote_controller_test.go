@@ -1,12 +1,21 @@
 package node
 
 import (
+	"context"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
 )
 
 type NodeBuilder struct {
@@ -96,6 +105,16 @@ func (n *NodeBuilder) Unknown() *NodeBuilder {
 	return n
 }
 
+func (n *NodeBuilder) RoleRequest(request string) *NodeBuilder {
+	n.node.Annotations[RoleRequestAnnotationKey] = request
+	return n
+}
+
+func (n *NodeBuilder) Demoted(status string) *NodeBuilder {
+	n.node.Annotations[CloudweavDemoteStatusAnnotationKey] = status
+	return n
+}
+
 func (n *NodeBuilder) NotReady() *NodeBuilder {
 	ready := corev1.NodeCondition{
 		Type:   corev1.NodeReady,
@@ -166,6 +185,12 @@ var (
 	w5z2rm  = NewDefaultNodeBuilder().Name("w-5-z2-mgmt").Zone("zone2").Cloudweav().RoleMgmt().Worker()
 	w6z2rwk = NewDefaultNodeBuilder().Name("w-6-z2-worker").Zone("zone2").Cloudweav().RoleWorker().Worker()
 	w7z1    = NewDefaultNodeBuilder().Name("w-7-z1").Zone("zone1").Cloudweav().Worker()
+
+	// role requested nodes
+	mdc1 = NewDefaultNodeBuilder().Name("m-demoted-1").Cloudweav().RoleWorker().Worker()
+	mdr1 = NewDefaultNodeBuilder().Name("m-demoting-1").Cloudweav().Demoted(PromoteStatusRunning).Management()
+	mrd1 = NewDefaultNodeBuilder().Name("m-demote-requested-1").Cloudweav().RoleRequest(RoleRequestDemote).Management()
+	wrp1 = NewDefaultNodeBuilder().Name("w-promote-requested-1").Cloudweav().RoleRequest(RoleRequestPromote).Worker()
 )
 
 func Test_selectPromoteNode(t *testing.T) {
@@ -758,6 +783,41 @@ func Test_selectPromoteNode(t *testing.T) {
 			},
 			want: w2z2,
 		},
+		{
+			name: "two management one worker one worker with promote request",
+			args: args{
+				nodeList: []*corev1.Node{m1, m2, w1, wrp1},
+			},
+			want: wrp1,
+		},
+		{
+			name: "two management one worker with role management one worker with promote request",
+			args: args{
+				nodeList: []*corev1.Node{m1, m2, w1rm, wrp1},
+			},
+			want: wrp1,
+		},
+		{
+			name: "three management with one demoted and one worker",
+			args: args{
+				nodeList: []*corev1.Node{m1, m2, mdc1, w1},
+			},
+			want: w1,
+		},
+		{
+			name: "three management with one demoting and one worker",
+			args: args{
+				nodeList: []*corev1.Node{m1, m2, mdr1, w1},
+			},
+			want: nil,
+		},
+		{
+			name: "three management with one demote requested and one worker",
+			args: args{
+				nodeList: []*corev1.Node{m1, m2, mrd1, w1},
+			},
+			want: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -767,3 +827,148 @@ func Test_selectPromoteNode(t *testing.T) {
 		})
 	}
 }
+
+func newDemoteHandler(nodes ...*corev1.Node) (*PromoteHandler, *k8sfake.Clientset) {
+	var objects []runtime.Object
+	for _, node := range nodes {
+		pod := newEtcdPod(node.Name, true)
+		pod.Labels = map[string]string{"component": "etcd", "tier": "control-plane"}
+		objects = append(objects, pod)
+	}
+	for _, node := range nodes {
+		objects = append(objects, node)
+	}
+	clientset := k8sfake.NewSimpleClientset(objects...)
+	return &PromoteHandler{
+		nodes:     &fakeNodeController{clientset: clientset},
+		nodeCache: fakeclients.NodeCache(clientset.CoreV1().Nodes),
+		podCache:  fakeclients.PodCache(clientset.CoreV1().Pods),
+		jobs:      fakeclients.JobClient(clientset.BatchV1().Jobs),
+		recorder:  record.NewFakeRecorder(10),
+	}, clientset
+}
+
+func newDemoteJob(nodeName string, attempt int, conditionType batchv1.JobConditionType) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   buildDemoteJobName(nodeName, attempt),
+			Labels: map[string]string{CloudweavDemoteNodeLabelKey: nodeName},
+		},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func Test_PromoteHandler_demote(t *testing.T) {
+	demoting := func(status string, attempt string) *corev1.Node {
+		node := NewDefaultNodeBuilder().Name("m-3").Cloudweav().RoleRequest(RoleRequestDemote).Demoted(status).Management()
+		node.Annotations[CloudweavDemoteAttemptAnnotationKey] = attempt
+		return node
+	}
+
+	tests := []struct {
+		name            string
+		node            *corev1.Node
+		job             *batchv1.Job
+		wantAnnotations map[string]string
+		wantPolicy      string
+	}{
+		{
+			name: "enter maintenance mode before leaving etcd",
+			node: NewDefaultNodeBuilder().Name("m-3").Cloudweav().RoleRequest(RoleRequestDemote).Management(),
+			wantAnnotations: map[string]string{
+				RoleRequestAnnotationKey:           RoleRequestDemote,
+				CloudweavDemoteStatusAnnotationKey: DemoteStatusDraining,
+				DrainRequestedAnnotationKey:        "true",
+			},
+			wantPolicy: ControlPlanePolicyAuto,
+		},
+		{
+			name: "wait for the VMs to be migrated",
+			node: NewDefaultNodeBuilder().Name("m-3").Cloudweav().RoleRequest(RoleRequestDemote).Demoted(DemoteStatusDraining).Management(),
+			wantAnnotations: map[string]string{
+				RoleRequestAnnotationKey:           RoleRequestDemote,
+				CloudweavDemoteStatusAnnotationKey: DemoteStatusDraining,
+			},
+			wantPolicy: ControlPlanePolicyAuto,
+		},
+		{
+			name: "record the failed attempt",
+			node: demoting(PromoteStatusRunning, "1"),
+			job:  newDemoteJob("m-3", 1, batchv1.JobFailed),
+			wantAnnotations: map[string]string{
+				RoleRequestAnnotationKey:            RoleRequestDemote,
+				CloudweavDemoteStatusAnnotationKey:  PromoteStatusFailed,
+				CloudweavDemoteAttemptAnnotationKey: "1",
+			},
+			wantPolicy: ControlPlanePolicyAuto,
+		},
+		{
+			name: "ignore the job of a previous attempt",
+			node: demoting(PromoteStatusRunning, "2"),
+			job:  newDemoteJob("m-3", 1, batchv1.JobFailed),
+			wantAnnotations: map[string]string{
+				RoleRequestAnnotationKey:            RoleRequestDemote,
+				CloudweavDemoteStatusAnnotationKey:  PromoteStatusRunning,
+				CloudweavDemoteAttemptAnnotationKey: "2",
+			},
+			wantPolicy: ControlPlanePolicyAuto,
+		},
+		{
+			name:            "keep the demoted node as a worker",
+			node:            demoting(PromoteStatusRunning, "2"),
+			job:             newDemoteJob("m-3", 2, batchv1.JobComplete),
+			wantAnnotations: map[string]string{},
+			wantPolicy:      ControlPlanePolicyExcluded,
+		},
+		{
+			name: "stop retrying once the attempts run out",
+			node: demoting(PromoteStatusFailed, "3"),
+			wantAnnotations: map[string]string{
+				RoleRequestAnnotationKey:            RoleRequestDemote,
+				CloudweavDemoteStatusAnnotationKey:  PromoteStatusFailed,
+				CloudweavDemoteAttemptAnnotationKey: "3",
+			},
+			wantPolicy: ControlPlanePolicyAuto,
+		},
+		{
+			name: "cancel a withdrawn request",
+			node: func() *corev1.Node {
+				node := demoting(PromoteStatusFailed, "3")
+				delete(node.Annotations, RoleRequestAnnotationKey)
+				return node
+			}(),
+			wantAnnotations: map[string]string{},
+			wantPolicy:      ControlPlanePolicyAuto,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			nodes := []*corev1.Node{
+				NewDefaultNodeBuilder().Name("m-1").Cloudweav().Management(),
+				NewDefaultNodeBuilder().Name("m-2").Cloudweav().Management(),
+				tc.node,
+			}
+			for _, node := range nodes {
+				node.Labels[KubeEtcdNodeLabelKey] = "true"
+				node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+			}
+			handler, clientset := newDemoteHandler(nodes...)
+
+			var err error
+			if tc.job != nil {
+				_, err = handler.OnJobChanged(tc.job.Name, tc.job)
+			} else {
+				_, err = handler.OnNodeChanged(tc.node.Name, tc.node)
+			}
+			require.NoError(t, err)
+
+			node, err := clientset.CoreV1().Nodes().Get(context.TODO(), tc.node.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantAnnotations, node.Annotations)
+			assert.Equal(t, tc.wantPolicy, GetControlPlanePolicy(node))
+		})
+	}
+}
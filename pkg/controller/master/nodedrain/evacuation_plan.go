@@ -0,0 +1,634 @@
+package nodedrain
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	lhv1beta2 "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
+	ctllhv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/longhorn.io/v1beta2"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+type EvacuationAction string
+
+const (
+	// EvacuationActionMigrate is a VM live migrated to TargetNode
+	EvacuationActionMigrate EvacuationAction = "Migrate"
+	// EvacuationActionShutDown is a VM shut down by the maintenance mode
+	EvacuationActionShutDown EvacuationAction = "ShutDown"
+	// EvacuationActionBlocked is a VM which can't leave the node, it blocks the maintenance mode
+	EvacuationActionBlocked EvacuationAction = "Blocked"
+	// EvacuationActionStay is a VM on another node affected by the volumes losing replicas
+	EvacuationActionStay EvacuationAction = "Stay"
+
+	// VolumeImpactDegraded is a volume losing some of its healthy replicas
+	VolumeImpactDegraded = "Degraded"
+	// VolumeImpactUnavailable is a volume losing its last healthy replicas
+	VolumeImpactUnavailable = "Unavailable"
+)
+
+// EvacuationPlan is the simulated outcome of putting the nodes in maintenance mode
+type EvacuationPlan struct {
+	Nodes   []string           `json:"nodes"`
+	Force   bool               `json:"force"`
+	VMs     []VMEvacuation     `json:"vms"`
+	Volumes []VolumeEvacuation `json:"volumes"`
+	Targets []EvacuationTarget `json:"targets"`
+}
+
+// VMEvacuation is what happens to a VM
+type VMEvacuation struct {
+	Namespace  string           `json:"namespace"`
+	Name       string           `json:"name"`
+	Node       string           `json:"node"`
+	Action     EvacuationAction `json:"action"`
+	TargetNode string           `json:"targetNode,omitempty"`
+	// Degraded is a VM with volumes losing replicas
+	Degraded bool     `json:"degraded,omitempty"`
+	Reasons  []string `json:"reasons,omitempty"`
+}
+
+// VolumeEvacuation is a volume with replicas on the evacuated nodes
+type VolumeEvacuation struct {
+	Name            string   `json:"name"`
+	Impact          string   `json:"impact"`
+	HealthyReplicas int      `json:"healthyReplicas"`
+	LostReplicas    int      `json:"lostReplicas"`
+	Rebuildable     bool     `json:"rebuildable"`
+	VMs             []string `json:"vms,omitempty"`
+}
+
+// EvacuationTarget is the resource usage of a remaining node once the VMs are migrated
+type EvacuationTarget struct {
+	Node        string              `json:"node"`
+	Allocatable corev1.ResourceList `json:"allocatable"`
+	Requested   corev1.ResourceList `json:"requested"`
+	VMs         []string            `json:"vms,omitempty"`
+}
+
+// EvacuationHelper is called by the action handler to simulate the evacuation of nodes
+func EvacuationHelper(nodeCache ctlcorev1.NodeCache, podCache ctlcorev1.PodCache, virtualMachineInstanceCache ctlkubevirtv1.VirtualMachineInstanceCache,
+	longhornVolumeCache ctllhv1.VolumeCache, longhornReplicaCache ctllhv1.ReplicaCache) *ControllerHandler {
+	ndc := ActionHelper(nodeCache, virtualMachineInstanceCache, longhornVolumeCache, longhornReplicaCache)
+	ndc.podCache = podCache
+	return ndc
+}
+
+// simulatedPod is a pod on a target node, for the resources and the pod (anti-)affinity of the simulation
+type simulatedPod struct {
+	namespace string
+	labels    labels.Set
+	requests  corev1.ResourceList
+}
+
+type simulatedNode struct {
+	node *corev1.Node
+	pods []*simulatedPod
+	vms  []string
+}
+
+func (n *simulatedNode) requested() corev1.ResourceList {
+	requested := corev1.ResourceList{}
+	for _, pod := range n.pods {
+		addResources(requested, pod.requests)
+	}
+	return requested
+}
+
+// vmToEvacuate is a VMI on an evacuated node and the pod it needs on the target node
+type vmToEvacuate struct {
+	evacuation *VMEvacuation
+	vmi        *kubevirtv1.VirtualMachineInstance
+	pod        *launcherPod
+}
+
+// PlanEvacuation simulates where the VMs of the nodes would be migrated, without changing anything. The target pod of
+// a migration is scheduled like the current virt-launcher pod, whose requests already account for the overcommit
+// setting, CPU pinning and host devices. The replicas on the nodes are lost for the volumes.
+func (ndc *ControllerHandler) PlanEvacuation(nodes []*corev1.Node, force bool) (*EvacuationPlan, error) {
+	plan := &EvacuationPlan{
+		Force:   force,
+		VMs:     []VMEvacuation{},
+		Volumes: []VolumeEvacuation{},
+		Targets: []EvacuationTarget{},
+	}
+	evacuated := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		evacuated[node.Name] = true
+		plan.Nodes = append(plan.Nodes, node.Name)
+	}
+
+	targets, err := ndc.simulatedTargets(evacuated)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := ndc.simulateVolumes(evacuated, targets)
+	if err != nil {
+		return nil, err
+	}
+	plan.Volumes = volumes
+	// the VMs losing a volume replica, by namespace/VMI name
+	degraded := make(map[string][]string)
+	unavailable := make(map[string][]string)
+	for _, volume := range volumes {
+		for _, vm := range volume.VMs {
+			if volume.Impact == VolumeImpactUnavailable {
+				unavailable[vm] = append(unavailable[vm], fmt.Sprintf("volume %s loses its last healthy replica", volume.Name))
+			} else {
+				degraded[vm] = append(degraded[vm], fmt.Sprintf("volume %s is degraded", volume.Name))
+			}
+		}
+	}
+
+	var toEvacuate []*vmToEvacuate
+	seen := make(map[string]bool)
+	for _, node := range nodes {
+		vmis, err := ndc.virtualMachineInstanceCache.List(corev1.NamespaceAll, labels.SelectorFromSet(map[string]string{
+			kubevirtv1.NodeNameLabel: node.Name,
+		}))
+		if err != nil {
+			return nil, fmt.Errorf("error listing VMI: %w", err)
+		}
+		sort.Slice(vmis, func(i, j int) bool {
+			return namespacedVMName(vmis[i]) < namespacedVMName(vmis[j])
+		})
+		blockers, err := ndc.FindNonMigratableVMS(node)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, vmi := range vmis {
+			key := namespacedVMName(vmi)
+			seen[key] = true
+			evacuation := &VMEvacuation{
+				Namespace: vmi.Namespace,
+				Name:      vmName(vmi),
+				Node:      node.Name,
+			}
+			reasons := append([]string(nil), unavailable[key]...)
+			for condition, vms := range blockers {
+				if condition == util.NodeSchedulingRequirementsNotMetKey {
+					// the simulation below checks the scheduling against the remaining nodes
+					continue
+				}
+				for _, vm := range vms {
+					if vm == key || vm == fmt.Sprintf("%s/%s", evacuation.Namespace, evacuation.Name) {
+						reasons = append(reasons, condition)
+						break
+					}
+				}
+			}
+			sort.Strings(reasons)
+			reasons = slices.Compact(reasons)
+
+			strategy := vmi.Labels[util.LabelMaintainModeStrategy]
+			switch {
+			case len(reasons) > 0 && force:
+				evacuation.Action = EvacuationActionShutDown
+				evacuation.Reasons = reasons
+			case len(reasons) > 0:
+				evacuation.Action = EvacuationActionBlocked
+				evacuation.Reasons = reasons
+			case !force && isShutdownStrategy(strategy):
+				evacuation.Action = EvacuationActionShutDown
+				evacuation.Reasons = []string{fmt.Sprintf("%s is %s", util.LabelMaintainModeStrategy, strategy)}
+			default:
+				pod, err := ndc.getLauncherPod(vmi)
+				if err != nil {
+					return nil, err
+				}
+				toEvacuate = append(toEvacuate, &vmToEvacuate{evacuation: evacuation, vmi: vmi, pod: pod})
+			}
+			if reasons := degraded[key]; len(reasons) > 0 && evacuation.Action != EvacuationActionShutDown {
+				evacuation.Degraded = true
+				evacuation.Reasons = append(evacuation.Reasons, reasons...)
+			}
+			if evacuation.Action != "" {
+				plan.VMs = append(plan.VMs, *evacuation)
+			}
+		}
+	}
+
+	// place the largest VMs first, on the node with the most free memory
+	sort.SliceStable(toEvacuate, func(i, j int) bool {
+		memoryI, memoryJ := toEvacuate[i].pod.resources[corev1.ResourceMemory], toEvacuate[j].pod.resources[corev1.ResourceMemory]
+		return memoryI.Cmp(memoryJ) > 0
+	})
+	for _, vm := range toEvacuate {
+		ndc.placeVM(vm, targets)
+		plan.VMs = append(plan.VMs, *vm.evacuation)
+	}
+
+	// the VMs on the other nodes are affected by the volumes too
+	for _, affected := range []map[string][]string{unavailable, degraded} {
+		keys := make([]string, 0, len(affected))
+		for key := range affected {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			ns, name := splitNamespacedName(key)
+			evacuation := VMEvacuation{
+				Namespace: ns,
+				Name:      name,
+				Action:    EvacuationActionStay,
+				Reasons:   affected[key],
+			}
+			vmi, err := ndc.virtualMachineInstanceCache.Get(ns, name)
+			switch {
+			case apierrors.IsNotFound(err):
+				// the volume status still lists the VMI of a stopped VM
+				continue
+			case err != nil:
+				evacuation.Reasons = append(evacuation.Reasons, fmt.Sprintf("failed to get VMI: %v", err))
+			default:
+				evacuation.Name = vmName(vmi)
+				evacuation.Node = vmi.Status.NodeName
+			}
+			if _, ok := unavailable[key]; ok {
+				evacuation.Action = EvacuationActionBlocked
+				if force {
+					evacuation.Action = EvacuationActionShutDown
+				}
+			} else {
+				evacuation.Degraded = true
+			}
+			plan.VMs = append(plan.VMs, evacuation)
+		}
+	}
+
+	sort.SliceStable(plan.VMs, func(i, j int) bool {
+		if plan.VMs[i].Namespace != plan.VMs[j].Namespace {
+			return plan.VMs[i].Namespace < plan.VMs[j].Namespace
+		}
+		return plan.VMs[i].Name < plan.VMs[j].Name
+	})
+	for _, target := range targets {
+		plan.Targets = append(plan.Targets, EvacuationTarget{
+			Node:        target.node.Name,
+			Allocatable: target.node.Status.Allocatable,
+			Requested:   target.requested(),
+			VMs:         target.vms,
+		})
+	}
+	return plan, nil
+}
+
+// simulatedTargets returns the nodes the VMs can be migrated to, with their current pods
+func (ndc *ControllerHandler) simulatedTargets(evacuated map[string]bool) ([]*simulatedNode, error) {
+	nodes, err := ndc.nodeCache.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes from nodeCache: %w", err)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	targets := make([]*simulatedNode, 0, len(nodes))
+	targetByName := make(map[string]*simulatedNode, len(nodes))
+	for _, node := range nodes {
+		if evacuated[node.Name] || !isNodeReady(node) {
+			continue
+		}
+		target := &simulatedNode{node: node}
+		targets = append(targets, target)
+		targetByName[node.Name] = target
+	}
+
+	pods, err := ndc.podCache.List(corev1.NamespaceAll, labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing pods: %w", err)
+	}
+	for _, pod := range pods {
+		target, ok := targetByName[pod.Spec.NodeName]
+		if !ok || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		target.pods = append(target.pods, &simulatedPod{
+			namespace: pod.Namespace,
+			labels:    pod.Labels,
+			requests:  podRequests(pod),
+		})
+	}
+	return targets, nil
+}
+
+// simulateVolumes returns the volumes losing healthy replicas on the evacuated nodes, attached or not. A lost replica
+// can be rebuilt when a remaining node has no replica of the volume yet.
+func (ndc *ControllerHandler) simulateVolumes(evacuated map[string]bool, targets []*simulatedNode) ([]VolumeEvacuation, error) {
+	replicas, err := ndc.longhornReplicaCache.List(util.LonghornSystemNamespaceName, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	type volumeReplicas struct {
+		healthy int
+		lost    int
+		nodes   map[string]bool
+	}
+	byVolume := make(map[string]*volumeReplicas)
+	for _, replica := range replicas {
+		v, ok := byVolume[replica.Spec.VolumeName]
+		if !ok {
+			v = &volumeReplicas{nodes: make(map[string]bool)}
+			byVolume[replica.Spec.VolumeName] = v
+		}
+		v.nodes[replica.Spec.NodeID] = true
+		if !isHealthyReplica(replica) {
+			continue
+		}
+		v.healthy++
+		if evacuated[replica.Spec.NodeID] {
+			v.lost++
+		}
+	}
+
+	names := make([]string, 0, len(byVolume))
+	for name, v := range byVolume {
+		if v.lost > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	result := make([]VolumeEvacuation, 0, len(names))
+	for _, name := range names {
+		v := byVolume[name]
+		evacuation := VolumeEvacuation{
+			Name:            name,
+			Impact:          VolumeImpactDegraded,
+			HealthyReplicas: v.healthy,
+			LostReplicas:    v.lost,
+		}
+		if v.lost >= v.healthy {
+			evacuation.Impact = VolumeImpactUnavailable
+		} else {
+			freeNodes := 0
+			for _, target := range targets {
+				if !v.nodes[target.node.Name] {
+					freeNodes++
+				}
+			}
+			evacuation.Rebuildable = freeNodes >= v.lost
+		}
+
+		volume, err := ndc.longhornVolumeCache.Get(util.LonghornSystemNamespaceName, name)
+		if err != nil {
+			return nil, err
+		}
+		evacuation.VMs = volumeVMIs(volume)
+		result = append(result, evacuation)
+	}
+	return result, nil
+}
+
+// isHealthyReplica returns whether the replica has usable data, the replicas of a detached volume aren't started
+// but stay healthy until they fail
+func isHealthyReplica(replica *lhv1beta2.Replica) bool {
+	return replica.Spec.HealthyAt != "" && replica.Spec.FailedAt == ""
+}
+
+// placeVM picks the target node of the VM, the node with the most free memory among the nodes fitting the VM
+func (ndc *ControllerHandler) placeVM(vm *vmToEvacuate, targets []*simulatedNode) {
+	requests := vm.pod.resources
+	var best *simulatedNode
+	var bestFree resource.Quantity
+	var reasons []string
+	for _, target := range targets {
+		if reason := fits(vm.pod, requests, target, targets); reason != "" {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", target.node.Name, reason))
+			continue
+		}
+		free := target.node.Status.Allocatable.Memory().DeepCopy()
+		free.Sub(target.requested()[corev1.ResourceMemory])
+		if best == nil || free.Cmp(bestFree) > 0 {
+			best = target
+			bestFree = free
+		}
+	}
+
+	if best == nil {
+		vm.evacuation.Action = EvacuationActionBlocked
+		if len(reasons) == 0 {
+			reasons = []string{"no node is available"}
+		}
+		vm.evacuation.Reasons = append(reasons, vm.evacuation.Reasons...)
+		return
+	}
+	vm.evacuation.Action = EvacuationActionMigrate
+	vm.evacuation.TargetNode = best.node.Name
+	best.pods = append(best.pods, &simulatedPod{
+		namespace: vm.pod.namespace,
+		labels:    vm.pod.labels,
+		requests:  requests,
+	})
+	best.vms = append(best.vms, fmt.Sprintf("%s/%s", vm.evacuation.Namespace, vm.evacuation.Name))
+}
+
+// fits returns why the pod can't run on the node, an empty string if it can
+func fits(pod *launcherPod, requests corev1.ResourceList, target *simulatedNode, targets []*simulatedNode) string {
+	if match, err := pod.nodeAffinity.Match(target.node); err != nil || !match {
+		return "node selector or affinity not matched"
+	}
+	if taint, untolerated := corev1helpers.FindMatchingUntoleratedTaint(target.node.Spec.Taints, pod.tolerations, func(t *corev1.Taint) bool {
+		return t.Effect == corev1.TaintEffectNoSchedule || t.Effect == corev1.TaintEffectNoExecute
+	}); untolerated {
+		return fmt.Sprintf("taint %s not tolerated", taint.Key)
+	}
+
+	requested := target.requested()
+	for name, quantity := range requests {
+		if quantity.IsZero() {
+			continue
+		}
+		free := target.node.Status.Allocatable[name].DeepCopy()
+		free.Sub(requested[name])
+		if free.Cmp(quantity) < 0 {
+			return fmt.Sprintf("insufficient %s", name)
+		}
+	}
+
+	if pod.affinity == nil {
+		return ""
+	}
+	if pod.affinity.PodAntiAffinity != nil {
+		for _, term := range pod.affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			if podsInTopology(pod, term, target, targets) > 0 {
+				return fmt.Sprintf("pod anti-affinity on %s not satisfied", term.TopologyKey)
+			}
+		}
+	}
+	if pod.affinity.PodAffinity != nil {
+		for _, term := range pod.affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			if podsInTopology(pod, term, target, targets) == 0 {
+				return fmt.Sprintf("pod affinity on %s not satisfied", term.TopologyKey)
+			}
+		}
+	}
+	return ""
+}
+
+// podsInTopology counts the pods matching the affinity term in the topology domain of the target
+func podsInTopology(pod *launcherPod, term corev1.PodAffinityTerm, target *simulatedNode, targets []*simulatedNode) int {
+	domain, ok := target.node.Labels[term.TopologyKey]
+	if !ok {
+		return 0
+	}
+	selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+	if err != nil {
+		return 0
+	}
+	namespaces := term.Namespaces
+	if len(namespaces) == 0 && term.NamespaceSelector == nil {
+		namespaces = []string{pod.namespace}
+	}
+
+	count := 0
+	for _, node := range targets {
+		if node.node.Labels[term.TopologyKey] != domain {
+			continue
+		}
+		for _, p := range node.pods {
+			if len(namespaces) > 0 && !slices.Contains(namespaces, p.namespace) {
+				continue
+			}
+			if selector.Matches(p.labels) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// launcherPod is the scheduling of the target pod of a VM migration
+type launcherPod struct {
+	namespace    string
+	labels       labels.Set
+	resources    corev1.ResourceList
+	nodeAffinity nodeaffinity.RequiredNodeAffinity
+	tolerations  []corev1.Toleration
+	affinity     *corev1.Affinity
+}
+
+// getLauncherPod returns the scheduling of the VMI's virt-launcher pod, or of the VMI spec if the pod isn't found
+func (ndc *ControllerHandler) getLauncherPod(vmi *kubevirtv1.VirtualMachineInstance) (*launcherPod, error) {
+	pods, err := ndc.podCache.List(vmi.Namespace, labels.SelectorFromSet(map[string]string{
+		kubevirtv1.CreatedByLabel: string(vmi.UID),
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("error listing pods of VMI %s: %w", namespacedVMName(vmi), err)
+	}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != vmi.Status.NodeName || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		return &launcherPod{
+			namespace:    pod.Namespace,
+			labels:       pod.Labels,
+			resources:    podRequests(pod),
+			nodeAffinity: nodeaffinity.GetRequiredNodeAffinity(pod),
+			tolerations:  pod.Spec.Tolerations,
+			affinity:     pod.Spec.Affinity,
+		}, nil
+	}
+
+	nodeSelector := make(map[string]string, len(vmi.Spec.NodeSelector)+1)
+	for key, value := range vmi.Spec.NodeSelector {
+		nodeSelector[key] = value
+	}
+	if vmi.Spec.Domain.CPU != nil && vmi.Spec.Domain.CPU.DedicatedCPUPlacement {
+		nodeSelector[kubevirtv1.CPUManager] = "true"
+	}
+	resources := vmi.Spec.Domain.Resources.Requests.DeepCopy()
+	if resources == nil {
+		resources = corev1.ResourceList{}
+	}
+	for _, device := range vmi.Spec.Domain.Devices.HostDevices {
+		addResources(resources, corev1.ResourceList{corev1.ResourceName(device.DeviceName): resource.MustParse("1")})
+	}
+	for _, gpu := range vmi.Spec.Domain.Devices.GPUs {
+		addResources(resources, corev1.ResourceList{corev1.ResourceName(gpu.DeviceName): resource.MustParse("1")})
+	}
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			NodeSelector: nodeSelector,
+			Affinity:     vmi.Spec.Affinity,
+			Tolerations:  vmi.Spec.Tolerations,
+		},
+	}
+	return &launcherPod{
+		namespace:    vmi.Namespace,
+		labels:       vmi.Labels,
+		resources:    resources,
+		nodeAffinity: nodeaffinity.GetRequiredNodeAffinity(pod),
+		tolerations:  vmi.Spec.Tolerations,
+		affinity:     vmi.Spec.Affinity,
+	}, nil
+}
+
+// podRequests returns the resources requested by the pod, like the scheduler computes them
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(requests, container.Resources.Requests)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current, ok := requests[name]; !ok || quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	addResources(requests, pod.Spec.Overhead)
+	return requests
+}
+
+func addResources(total, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		current := total[name]
+		current.Add(quantity)
+		total[name] = current
+	}
+}
+
+// volumeVMIs returns the VMIs using the volume, by namespace/name
+func volumeVMIs(volume *lhv1beta2.Volume) []string {
+	var vmis []string
+	for _, workload := range volume.Status.KubernetesStatus.WorkloadsStatus {
+		if workload.WorkloadType == defaultWorkloadType {
+			vmis = append(vmis, fmt.Sprintf("%s/%s", volume.Status.KubernetesStatus.Namespace, workload.WorkloadName))
+		}
+	}
+	return vmis
+}
+
+func vmName(vmi *kubevirtv1.VirtualMachineInstance) string {
+	if name, err := findVM(vmi); err == nil {
+		return name
+	}
+	return vmi.Name
+}
+
+func isShutdownStrategy(strategy string) bool {
+	switch strategy {
+	case util.MaintainModeStrategyShutdown,
+		util.MaintainModeStrategyShutdownAndRestartAfterEnable,
+		util.MaintainModeStrategyShutdownAndRestartAfterDisable:
+		return true
+	}
+	return false
+}
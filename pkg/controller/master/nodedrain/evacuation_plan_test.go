@@ -0,0 +1,221 @@
+package nodedrain
+
+import (
+	"testing"
+
+	lhv1beta2 "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/fake"
+	"github.com/cloudweav/cloudweav/pkg/util"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+)
+
+func newEvacuationNode(name, memory string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+			Conditions: []corev1.NodeCondition{{
+				Type:   corev1.NodeReady,
+				Status: corev1.ConditionTrue,
+			}},
+		},
+	}
+}
+
+// newEvacuationVMI returns a VMI of a VM and its virt-launcher pod
+func newEvacuationVMI(name, nodeName, memory string, nodeSelector map[string]string) (*kubevirtv1.VirtualMachineInstance, *corev1.Pod) {
+	vmi := &kubevirtv1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID("uid-" + name),
+			Labels:    map[string]string{kubevirtv1.NodeNameLabel: nodeName},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: kubevirtv1.SchemeGroupVersion.String(),
+				Kind:       kubevirtv1.VirtualMachineGroupVersionKind.Kind,
+				Name:       name,
+			}},
+		},
+		Spec: kubevirtv1.VirtualMachineInstanceSpec{
+			NodeSelector: nodeSelector,
+		},
+		Status: kubevirtv1.VirtualMachineInstanceStatus{
+			NodeName: nodeName,
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "virt-launcher-" + name,
+			Labels:    map[string]string{kubevirtv1.CreatedByLabel: string(vmi.UID)},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:     nodeName,
+			NodeSelector: nodeSelector,
+			Containers: []corev1.Container{{
+				Name: "compute",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	return vmi, pod
+}
+
+func newEvacuationReplica(volumeName, nodeName string) *lhv1beta2.Replica {
+	return &lhv1beta2.Replica{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      volumeName + "-r-" + nodeName,
+			Namespace: util.LonghornSystemNamespaceName,
+		},
+		Spec: lhv1beta2.ReplicaSpec{
+			InstanceSpec: lhv1beta2.InstanceSpec{
+				VolumeName: volumeName,
+				NodeID:     nodeName,
+			},
+			HealthyAt: "2024-01-01T00:00:00Z",
+		},
+		Status: lhv1beta2.ReplicaStatus{
+			InstanceStatus: lhv1beta2.InstanceStatus{Started: true},
+		},
+	}
+}
+
+// detachedReplica is a healthy replica of a detached volume
+func detachedReplica(volumeName, nodeName string) *lhv1beta2.Replica {
+	replica := newEvacuationReplica(volumeName, nodeName)
+	replica.Status.Started = false
+	return replica
+}
+
+func newEvacuationVolume(name, vmiName string) *lhv1beta2.Volume {
+	return &lhv1beta2.Volume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: util.LonghornSystemNamespaceName,
+		},
+		Status: lhv1beta2.VolumeStatus{
+			KubernetesStatus: lhv1beta2.KubernetesStatus{
+				Namespace: "default",
+				WorkloadsStatus: []lhv1beta2.WorkloadStatus{{
+					WorkloadName: vmiName,
+					WorkloadType: defaultWorkloadType,
+				}},
+			},
+		},
+	}
+}
+
+func Test_PlanEvacuation(t *testing.T) {
+	node1 := newEvacuationNode("node-1", "64Gi", nil)
+	node2 := newEvacuationNode("node-2", "8Gi", nil)
+	node3 := newEvacuationNode("node-3", "2Gi", map[string]string{kubevirtv1.CPUManager: "true"})
+
+	big, bigPod := newEvacuationVMI("big", node1.Name, "6Gi", nil)
+	pinned, pinnedPod := newEvacuationVMI("pinned", node1.Name, "1Gi", map[string]string{kubevirtv1.CPUManager: "true"})
+	huge, hugePod := newEvacuationVMI("huge", node1.Name, "16Gi", nil)
+	cdrom, cdromPod := newEvacuationVMI("cdrom", node1.Name, "1Gi", nil)
+	cdrom.Status.Conditions = []kubevirtv1.VirtualMachineInstanceCondition{{
+		Type:   kubevirtv1.VirtualMachineInstanceIsMigratable,
+		Status: corev1.ConditionFalse,
+		Reason: kubevirtv1.VirtualMachineInstanceReasonDisksNotMigratable,
+	}}
+	remote, remotePod := newEvacuationVMI("remote", node2.Name, "1Gi", nil)
+
+	clientset := fake.NewSimpleClientset(big, pinned, huge, cdrom, remote,
+		newEvacuationVolume("vol-big", big.Name),
+		newEvacuationVolume("vol-remote", remote.Name),
+		newEvacuationReplica("vol-big", node1.Name),
+		newEvacuationReplica("vol-big", node2.Name),
+		newEvacuationReplica("vol-remote", node1.Name),
+		newEvacuationVolume("vol-stopped", "stopped"),
+		detachedReplica("vol-stopped", node1.Name),
+		detachedReplica("vol-stopped", node2.Name),
+	)
+	k8sclientset := k8sfake.NewSimpleClientset(node1, node2, node3, bigPod, pinnedPod, hugePod, cdromPod, remotePod)
+	ndc := EvacuationHelper(
+		fakeclients.NodeCache(k8sclientset.CoreV1().Nodes),
+		fakeclients.PodCache(k8sclientset.CoreV1().Pods),
+		fakeclients.VirtualMachineInstanceCache(clientset.KubevirtV1().VirtualMachineInstances),
+		fakeclients.LonghornVolumeCache(clientset.LonghornV1beta2().Volumes),
+		fakeclients.LonghornReplicaCache(clientset.LonghornV1beta2().Replicas),
+	)
+
+	plan, err := ndc.PlanEvacuation([]*corev1.Node{node1}, false)
+	require.NoError(t, err)
+	vms := make(map[string]VMEvacuation, len(plan.VMs))
+	for _, vm := range plan.VMs {
+		vms[vm.Name] = vm
+	}
+	require.Len(t, vms, 5)
+
+	assert.Equal(t, EvacuationActionMigrate, vms["big"].Action)
+	assert.Equal(t, node2.Name, vms["big"].TargetNode)
+	assert.True(t, vms["big"].Degraded, "vol-big loses its replica on node-1")
+
+	assert.Equal(t, EvacuationActionMigrate, vms["pinned"].Action)
+	assert.Equal(t, node3.Name, vms["pinned"].TargetNode)
+
+	assert.Equal(t, EvacuationActionBlocked, vms["huge"].Action)
+	assert.Equal(t, []string{
+		"node-2: insufficient memory",
+		"node-3: insufficient memory",
+	}, vms["huge"].Reasons)
+
+	assert.Equal(t, EvacuationActionBlocked, vms["cdrom"].Action)
+	assert.Equal(t, []string{kubevirtv1.VirtualMachineInstanceReasonDisksNotMigratable}, vms["cdrom"].Reasons)
+
+	assert.Equal(t, EvacuationActionBlocked, vms["remote"].Action)
+	assert.Equal(t, node2.Name, vms["remote"].Node)
+
+	require.Len(t, plan.Volumes, 3)
+	assert.Equal(t, VolumeEvacuation{
+		Name:            "vol-big",
+		Impact:          VolumeImpactDegraded,
+		HealthyReplicas: 2,
+		LostReplicas:    1,
+		Rebuildable:     true,
+		VMs:             []string{"default/big"},
+	}, plan.Volumes[0])
+	assert.Equal(t, VolumeImpactUnavailable, plan.Volumes[1].Impact)
+	assert.Equal(t, VolumeEvacuation{
+		Name:            "vol-stopped",
+		Impact:          VolumeImpactDegraded,
+		HealthyReplicas: 2,
+		LostReplicas:    1,
+		Rebuildable:     true,
+		VMs:             []string{"default/stopped"},
+	}, plan.Volumes[2], "the replicas of a detached volume are lost too, the VMI of the stopped VM is skipped")
+
+	// the force option shuts down the VMs which can't be migrated
+	plan, err = ndc.PlanEvacuation([]*corev1.Node{node1}, true)
+	require.NoError(t, err)
+	for _, vm := range plan.VMs {
+		switch vm.Name {
+		case "cdrom", "remote":
+			assert.Equal(t, EvacuationActionShutDown, vm.Action, vm.Name)
+		case "huge":
+			assert.Equal(t, EvacuationActionBlocked, vm.Action, vm.Name)
+		}
+	}
+}
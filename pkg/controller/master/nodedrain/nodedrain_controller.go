@@ -39,6 +39,7 @@ const (
 type ControllerHandler struct {
 	nodes                        ctlcorev1.NodeClient
 	nodeCache                    ctlcorev1.NodeCache
+	podCache                     ctlcorev1.PodCache
 	virtualMachineInstanceCache  ctlkubevirtv1.VirtualMachineInstanceCache
 	virtualMachineInstanceClient ctlkubevirtv1.VirtualMachineInstanceClient
 	virtualMachineClient         ctlkubevirtv1.VirtualMachineClient
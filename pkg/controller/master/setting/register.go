@@ -92,6 +92,7 @@ func Register(ctx context.Context, management *config.Management, options config
 		"auto-rotate-rke2-certs":                     controller.syncAutoRotateRKE2Certs,
 		harvSettings.KubeconfigDefaultTokenTTLMinutesSettingName: controller.syncKubeconfigTTL,
 		harvSettings.AdditionalGuestMemoryOverheadRatioName:      controller.syncAdditionalGuestMemoryOverheadRatio,
+		harvSettings.SSHCASettingName:                            controller.syncSSHCA,
 		// for "backup-target" syncer, please check cloudweav-backup-target-controller
 		// for "storage-network" syncer, please check cloudweav-storage-network-controller
 	}
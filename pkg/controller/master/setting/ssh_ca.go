@@ -0,0 +1,54 @@
+package setting
+
+import (
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	harvSettings "github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/util/sshca"
+)
+
+// syncSSHCA generates the cluster SSH CA key pair the first time the CA is enabled. The key pair is kept
+// when the CA is disabled, so that the VMs configured to trust it keep working when it is enabled again.
+func (h *Handler) syncSSHCA(setting *cloudweavv1.Setting) error {
+	value := setting.Value
+	if value == "" {
+		value = setting.Default
+	}
+	config, err := harvSettings.DecodeSSHCAConfig(value)
+	if err != nil {
+		return err
+	}
+	if !config.Enabled {
+		return nil
+	}
+
+	if _, err := h.secretCache.Get(h.namespace, sshca.SecretName); err == nil {
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	privateKey, publicKey, err := sshca.GenerateCA()
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sshca.SecretName,
+			Namespace: h.namespace,
+		},
+		Data: map[string][]byte{
+			sshca.SecretKeyPrivateKey: privateKey,
+			sshca.SecretKeyPublicKey:  publicKey,
+		},
+	}
+	if _, err := h.secrets.Create(secret); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	logrus.Infof("SSH certificate authority is generated in secret %s/%s", h.namespace, sshca.SecretName)
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/cloudweav/cloudweav/pkg/controller/master/image"
 	"github.com/cloudweav/cloudweav/pkg/controller/master/keypair"
 	"github.com/cloudweav/cloudweav/pkg/controller/master/machine"
+	"github.com/cloudweav/cloudweav/pkg/controller/master/maintenanceplan"
 	"github.com/cloudweav/cloudweav/pkg/controller/master/mcmsettings"
 	"github.com/cloudweav/cloudweav/pkg/controller/master/migration"
 	"github.com/cloudweav/cloudweav/pkg/controller/master/node"
@@ -39,6 +40,9 @@ var registerFuncs = []registerFunc{
 	node.RemoveRegister,
 	node.VolumeDetachRegister,
 	node.CPUManagerRegister,
+	node.BMCRegister,
+	node.HealthRegister,
+	maintenanceplan.Register,
 	machine.ControlPlaneRegister,
 	setting.Register,
 	template.Register,
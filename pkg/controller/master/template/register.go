@@ -3,6 +3,8 @@ package template
 import (
 	"context"
 
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+
 	"github.com/cloudweav/cloudweav/pkg/config"
 )
 
@@ -10,12 +12,15 @@ const (
 	templateControllerAgentName        = "template-controller"
 	templateVersionControllerAgentName = "template-version-controller"
 	vmImageControllerAgentName         = "vm-image-in-template-controller"
+	templateSharingControllerAgentName = "template-sharing-controller"
 )
 
 func Register(ctx context.Context, management *config.Management, _ config.Options) error {
 	templates := management.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineTemplate()
 	templateVersions := management.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineTemplateVersion()
 	vmImages := management.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineImage()
+	vms := management.VirtFactory.Kubevirt().V1().VirtualMachine()
+	roleBindings := management.RbacFactory.Rbac().V1().RoleBinding()
 
 	templateController := &templateHandler{
 		templates:            templates,
@@ -36,7 +41,21 @@ func Register(ctx context.Context, management *config.Management, _ config.Optio
 		templateVersionController: templateVersions,
 	}
 
+	templateSharingController := &templateSharingHandler{
+		apply:                management.Apply,
+		templates:            templates,
+		templateCache:        templates.Cache(),
+		templateVersionCache: templateVersions.Cache(),
+		vmCache:              vms.Cache(),
+		roleBindingCache:     roleBindings.Cache(),
+		subjectAccessReviews: management.ClientSet.AuthorizationV1().SubjectAccessReviews(),
+	}
+
 	templates.OnChange(ctx, templateControllerAgentName, templateController.OnChanged)
+	templates.OnChange(ctx, templateSharingControllerAgentName, templateSharingController.OnChanged)
+	relatedresource.Watch(ctx, "template-sharing-versions", templateSharingController.ResolveTemplateVersion, templates, templateVersions)
+	relatedresource.Watch(ctx, "template-sharing-vms", templateSharingController.ResolveVM, templates, vms)
+	relatedresource.Watch(ctx, "template-sharing-rolebindings", templateSharingController.ResolveRoleBinding, templates, roleBindings)
 	templateVersions.OnChange(ctx, templateVersionControllerAgentName, templateVersionController.OnChanged)
 	vmImages.OnChange(ctx, vmImageControllerAgentName, vmImageController.OnChanged)
 	return nil
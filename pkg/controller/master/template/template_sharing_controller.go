@@ -0,0 +1,365 @@
+package template
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/rancher/wrangler/v3/pkg/apply"
+	ctlrbacv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/rbac/v1"
+	wranglername "github.com/rancher/wrangler/v3/pkg/name"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
+	"github.com/cloudweav/cloudweav/pkg/indexeres"
+	"github.com/cloudweav/cloudweav/pkg/ref"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+const (
+	sharedTemplateSetID = "shared-template"
+	// groupAllAuthenticated and groupServiceAccountsPrefix are the built-in Kubernetes groups
+	// the shared template is granted to, groupServiceAccounts is the group of all service accounts.
+	groupAllAuthenticated      = "system:authenticated"
+	groupServiceAccounts       = "system:serviceaccounts"
+	groupServiceAccountsPrefix = "system:serviceaccounts:"
+	// serviceAccountUsernamePrefix is the prefix of the user names of the service accounts
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
+)
+
+// templateSharingHandler grants read access on a shared template, its versions and its images to the
+// namespaces it is shared with, and reports the VMs created from the template in its status.
+type templateSharingHandler struct {
+	apply                apply.Apply
+	templates            ctlcloudweavv1.VirtualMachineTemplateClient
+	templateCache        ctlcloudweavv1.VirtualMachineTemplateCache
+	templateVersionCache ctlcloudweavv1.VirtualMachineTemplateVersionCache
+	vmCache              ctlkubevirtv1.VirtualMachineCache
+	roleBindingCache     ctlrbacv1.RoleBindingCache
+	subjectAccessReviews authorizationv1client.SubjectAccessReviewInterface
+}
+
+func (h *templateSharingHandler) OnChanged(_ string, tp *cloudweavv1.VirtualMachineTemplate) (*cloudweavv1.VirtualMachineTemplate, error) {
+	if tp == nil || tp.DeletionTimestamp != nil {
+		return tp, nil
+	}
+
+	versions, err := h.templateVersionCache.List(tp.Namespace, labels.Set{
+		TemplateLabel: tp.Name,
+	}.AsSelector())
+	if err != nil {
+		return tp, err
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Name < versions[j].Name })
+
+	roleBindings, err := h.getSharedNamespaceRoleBindings(tp.Spec.SharedWith)
+	if err != nil {
+		return tp, err
+	}
+	subjects, err := sharedTemplateSubjects(tp.Spec.SharedWith, roleBindings, h.canCreateVMs)
+	if err != nil {
+		return tp, err
+	}
+	objects, err := sharedTemplateRBAC(tp, versions, subjects)
+	if err != nil {
+		return tp, err
+	}
+	if err := h.apply.WithOwner(tp).WithSetID(sharedTemplateSetID).ApplyObjects(objects...); err != nil {
+		return tp, err
+	}
+
+	consumers, err := h.getConsumers(versions)
+	if err != nil {
+		return tp, err
+	}
+	if reflect.DeepEqual(tp.Status.Consumers, consumers) {
+		return tp, nil
+	}
+	toUpdate := tp.DeepCopy()
+	toUpdate.Status.Consumers = consumers
+	return h.templates.UpdateStatus(toUpdate)
+}
+
+// sharedTemplateRBAC returns the role and role binding giving read access on the template, its versions
+// and the images they use to the subjects the template is shared with. Nothing is returned for a template
+// that is not shared, so that applying the objects removes the previous grant.
+func sharedTemplateRBAC(tp *cloudweavv1.VirtualMachineTemplate, versions []*cloudweavv1.VirtualMachineTemplateVersion, subjects []rbacv1.Subject) ([]runtime.Object, error) {
+	if len(subjects) == 0 {
+		return nil, nil
+	}
+
+	versionNames := make([]string, 0, len(versions))
+	var imageNames []string
+	for _, version := range versions {
+		versionNames = append(versionNames, version.Name)
+		imageIDs, err := indexeres.VMTemplateVersionByImageID(version)
+		if err != nil {
+			return nil, err
+		}
+		for _, imageID := range imageIDs {
+			// images in other namespaces are shared by their own templates
+			if imageNamespace, imageName := ref.Parse(imageID); imageNamespace == tp.Namespace {
+				imageNames = append(imageNames, imageName)
+			}
+		}
+	}
+
+	rules := []rbacv1.PolicyRule{
+		{
+			APIGroups:     []string{cloudweavv1.SchemeGroupVersion.Group},
+			Resources:     []string{"virtualmachinetemplates"},
+			ResourceNames: []string{tp.Name},
+			Verbs:         []string{"get"},
+		},
+	}
+	if len(versionNames) > 0 {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     []string{cloudweavv1.SchemeGroupVersion.Group},
+			Resources:     []string{"virtualmachinetemplateversions"},
+			ResourceNames: versionNames,
+			Verbs:         []string{"get"},
+		})
+	}
+	if len(imageNames) > 0 {
+		sort.Strings(imageNames)
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     []string{cloudweavv1.SchemeGroupVersion.Group},
+			Resources:     []string{"virtualmachineimages"},
+			ResourceNames: imageNames,
+			Verbs:         []string{"get"},
+		})
+	}
+
+	name := wranglername.SafeConcatName("shared-template", tp.Name)
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: tp.Namespace,
+		},
+		Rules: rules,
+	}
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: tp.Namespace,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+		Subjects: subjects,
+	}
+	return []runtime.Object{role, roleBinding}, nil
+}
+
+// sharedTemplateSubjects returns the subjects of the namespaces the template is shared with which can
+// create VMs in them: their service accounts, and the users and groups bound to a role in them, e.g. the
+// members of their Rancher project. A binding to a role that can't create VMs doesn't grant the template.
+func sharedTemplateSubjects(sharing *cloudweavv1.VirtualMachineTemplateSharing, roleBindings []*rbacv1.RoleBinding,
+	canCreateVMs func(namespace string, subject rbacv1.Subject) (bool, error)) ([]rbacv1.Subject, error) {
+	if sharing == nil {
+		return nil, nil
+	}
+	if sharing.ClusterWide {
+		return []rbacv1.Subject{{
+			APIGroup: rbacv1.GroupName,
+			Kind:     rbacv1.GroupKind,
+			Name:     groupAllAuthenticated,
+		}}, nil
+	}
+
+	subjects := make([]rbacv1.Subject, 0, len(sharing.Namespaces))
+	serviceAccountGroups := map[string]bool{}
+	for _, namespace := range sharing.Namespaces {
+		group := rbacv1.Subject{
+			APIGroup: rbacv1.GroupName,
+			Kind:     rbacv1.GroupKind,
+			Name:     groupServiceAccountsPrefix + namespace,
+		}
+		allowed, err := canCreateVMs(namespace, group)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			subjects = append(subjects, group)
+			serviceAccountGroups[namespace] = true
+		}
+	}
+
+	var members []rbacv1.Subject
+	for _, roleBinding := range roleBindings {
+		if !slices.Contains(sharing.Namespaces, roleBinding.Namespace) || isSharedTemplateRoleBinding(roleBinding) {
+			continue
+		}
+		for _, subject := range roleBinding.Subjects {
+			// the service accounts are already granted by their namespace group
+			if subject.Kind == rbacv1.ServiceAccountKind && serviceAccountGroups[subject.Namespace] || slices.Contains(members, subject) {
+				continue
+			}
+			allowed, err := canCreateVMs(roleBinding.Namespace, subject)
+			if err != nil {
+				return nil, err
+			}
+			if allowed {
+				members = append(members, subject)
+			}
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Kind != members[j].Kind {
+			return members[i].Kind < members[j].Kind
+		}
+		if members[i].Namespace != members[j].Namespace {
+			return members[i].Namespace < members[j].Namespace
+		}
+		return members[i].Name < members[j].Name
+	})
+	return append(subjects, members...), nil
+}
+
+// canCreateVMs tells whether the subject can create VMs in the namespace.
+func (h *templateSharingHandler) canCreateVMs(namespace string, subject rbacv1.Subject) (bool, error) {
+	spec := authorizationv1.SubjectAccessReviewSpec{
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace: namespace,
+			Verb:      "create",
+			Group:     kubevirtv1.SchemeGroupVersion.Group,
+			Version:   kubevirtv1.SchemeGroupVersion.Version,
+			Resource:  "virtualmachines",
+		},
+	}
+	switch subject.Kind {
+	case rbacv1.GroupKind:
+		spec.Groups = []string{subject.Name}
+	case rbacv1.ServiceAccountKind:
+		spec.User = serviceAccountUsernamePrefix + subject.Namespace + ":" + subject.Name
+		spec.Groups = []string{groupServiceAccounts, groupServiceAccountsPrefix + subject.Namespace}
+	default:
+		spec.User = subject.Name
+	}
+	review, err := h.subjectAccessReviews.Create(context.TODO(), &authorizationv1.SubjectAccessReview{Spec: spec}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// isSharedTemplateRoleBinding tells whether the role binding is one granting a shared template, the
+// subjects of a namespace must not be granted the templates shared with that namespace in turn.
+func isSharedTemplateRoleBinding(roleBinding *rbacv1.RoleBinding) bool {
+	templateGVK := cloudweavv1.SchemeGroupVersion.WithKind("VirtualMachineTemplate")
+	for _, owner := range roleBinding.OwnerReferences {
+		if owner.Kind == templateGVK.Kind && owner.APIVersion == templateGVK.GroupVersion().String() {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *templateSharingHandler) getSharedNamespaceRoleBindings(sharing *cloudweavv1.VirtualMachineTemplateSharing) ([]*rbacv1.RoleBinding, error) {
+	if sharing == nil || sharing.ClusterWide {
+		return nil, nil
+	}
+
+	var roleBindings []*rbacv1.RoleBinding
+	for _, namespace := range sharing.Namespaces {
+		namespaceRoleBindings, err := h.roleBindingCache.List(namespace, labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		roleBindings = append(roleBindings, namespaceRoleBindings...)
+	}
+	return roleBindings, nil
+}
+
+func (h *templateSharingHandler) getConsumers(versions []*cloudweavv1.VirtualMachineTemplateVersion) ([]cloudweavv1.VirtualMachineTemplateConsumer, error) {
+	vmsByNamespace := map[string][]string{}
+	for _, version := range versions {
+		vms, err := h.vmCache.GetByIndex(indexeres.VMByTemplateVersionIndex, ref.Construct(version.Namespace, version.Name))
+		if err != nil {
+			return nil, err
+		}
+		for _, vm := range vms {
+			vmsByNamespace[vm.Namespace] = append(vmsByNamespace[vm.Namespace], vm.Name)
+		}
+	}
+	return groupConsumers(vmsByNamespace), nil
+}
+
+func groupConsumers(vmsByNamespace map[string][]string) []cloudweavv1.VirtualMachineTemplateConsumer {
+	if len(vmsByNamespace) == 0 {
+		return nil
+	}
+
+	consumers := make([]cloudweavv1.VirtualMachineTemplateConsumer, 0, len(vmsByNamespace))
+	for namespace, vms := range vmsByNamespace {
+		sort.Strings(vms)
+		consumers = append(consumers, cloudweavv1.VirtualMachineTemplateConsumer{
+			Namespace:       namespace,
+			VirtualMachines: vms,
+		})
+	}
+	sort.Slice(consumers, func(i, j int) bool { return consumers[i].Namespace < consumers[j].Namespace })
+	return consumers
+}
+
+// ResolveTemplateVersion enqueues the template of a version, so that the shared versions are kept in sync.
+func (h *templateSharingHandler) ResolveTemplateVersion(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	version, ok := obj.(*cloudweavv1.VirtualMachineTemplateVersion)
+	if !ok || version.Spec.TemplateID == "" {
+		return nil, nil
+	}
+	namespace, name := ref.Parse(version.Spec.TemplateID)
+	return []relatedresource.Key{relatedresource.NewKey(namespace, name)}, nil
+}
+
+// ResolveVM enqueues the template a VM was created from, to refresh the template consumers.
+func (h *templateSharingHandler) ResolveVM(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	vm, ok := obj.(*kubevirtv1.VirtualMachine)
+	if !ok || vm.Annotations[util.AnnotationTemplateVersionID] == "" {
+		return nil, nil
+	}
+
+	versionNamespace, versionName := ref.Parse(vm.Annotations[util.AnnotationTemplateVersionID])
+	version, err := h.templateVersionCache.Get(versionNamespace, versionName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	namespace, name := ref.Parse(version.Spec.TemplateID)
+	return []relatedresource.Key{relatedresource.NewKey(namespace, name)}, nil
+}
+
+// ResolveRoleBinding enqueues the templates shared with the namespace of a role binding, so that the
+// users and groups bound in the namespace are kept in sync.
+func (h *templateSharingHandler) ResolveRoleBinding(namespace, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	if roleBinding, ok := obj.(*rbacv1.RoleBinding); !ok || isSharedTemplateRoleBinding(roleBinding) {
+		return nil, nil
+	}
+
+	templates, err := h.templateCache.List(metav1.NamespaceAll, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var keys []relatedresource.Key
+	for _, tp := range templates {
+		if sharing := tp.Spec.SharedWith; sharing != nil && !sharing.ClusterWide && slices.Contains(sharing.Namespaces, namespace) {
+			keys = append(keys, relatedresource.NewKey(tp.Namespace, tp.Name))
+		}
+	}
+	return keys, nil
+}
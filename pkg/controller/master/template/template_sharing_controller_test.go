@@ -0,0 +1,170 @@
+package template
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+func TestSharedTemplateRBAC(t *testing.T) {
+	volumeClaimTemplates, _ := json.Marshal([]corev1.PersistentVolumeClaim{
+		{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{util.AnnotationImageID: "default/windows"}}},
+		{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{util.AnnotationImageID: "other/data"}}},
+	})
+	versions := []*cloudweavv1.VirtualMachineTemplateVersion{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "windows-v1"},
+		Spec: cloudweavv1.VirtualMachineTemplateVersionSpec{
+			VM: cloudweavv1.VirtualMachineSourceSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{util.AnnotationVolumeClaimTemplates: string(volumeClaimTemplates)},
+				},
+			},
+		},
+	}}
+	tp := &cloudweavv1.VirtualMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "windows"},
+	}
+
+	objects, err := sharedTemplateRBAC(tp, versions, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, objects, "template is not shared")
+
+	subjects := []rbacv1.Subject{
+		{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:team-a"},
+		{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:team-b"},
+	}
+	objects, err = sharedTemplateRBAC(tp, versions, subjects)
+	assert.Nil(t, err)
+	assert.Len(t, objects, 2)
+
+	role := objects[0].(*rbacv1.Role)
+	assert.Equal(t, "default", role.Namespace)
+	assert.Len(t, role.Rules, 3)
+	assert.Equal(t, []string{"windows"}, role.Rules[0].ResourceNames)
+	assert.Equal(t, []string{"windows-v1"}, role.Rules[1].ResourceNames)
+	assert.Equal(t, []string{"windows"}, role.Rules[2].ResourceNames, "images in other namespaces are not granted")
+	for _, rule := range role.Rules {
+		assert.Equal(t, []string{"get"}, rule.Verbs)
+	}
+
+	roleBinding := objects[1].(*rbacv1.RoleBinding)
+	assert.Equal(t, role.Name, roleBinding.RoleRef.Name)
+	assert.Equal(t, subjects, roleBinding.Subjects)
+}
+
+func TestSharedTemplateSubjects(t *testing.T) {
+	user := rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: "u-abcde"}
+	group := rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: "github_team://1234"}
+	auditor := rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: "u-auditor"}
+	serviceAccount := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: "team-b", Name: "deployer"}
+	roleBindings := []*rbacv1.RoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "project-member"},
+			Subjects:   []rbacv1.Subject{user, {Kind: rbacv1.ServiceAccountKind, Namespace: "team-a", Name: "default"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "project-owner"},
+			Subjects:   []rbacv1.Subject{group, user},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "read-only"},
+			Subjects:   []rbacv1.Subject{auditor},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "deployer"},
+			Subjects:   []rbacv1.Subject{serviceAccount},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "team-a",
+				Name:      "shared-template-linux",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: cloudweavv1.SchemeGroupVersion.String(),
+					Kind:       "VirtualMachineTemplate",
+					Name:       "linux",
+				}},
+			},
+			Subjects: []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: "u-other"}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-c", Name: "project-member"},
+			Subjects:   []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: "u-team-c"}},
+		},
+	}
+	// the auditor and the service accounts of team-b can't create VMs
+	canCreateVMs := func(namespace string, subject rbacv1.Subject) (bool, error) {
+		return subject != auditor && subject.Name != "system:serviceaccounts:team-b", nil
+	}
+
+	subjects, err := sharedTemplateSubjects(nil, roleBindings, canCreateVMs)
+	assert.Nil(t, err)
+	assert.Nil(t, subjects)
+
+	subjects, err = sharedTemplateSubjects(&cloudweavv1.VirtualMachineTemplateSharing{ClusterWide: true}, roleBindings, canCreateVMs)
+	assert.Nil(t, err)
+	assert.Equal(t, []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: "system:authenticated"}}, subjects)
+
+	subjects, err = sharedTemplateSubjects(&cloudweavv1.VirtualMachineTemplateSharing{Namespaces: []string{"team-a", "team-b"}}, roleBindings, canCreateVMs)
+	assert.Nil(t, err)
+	assert.Equal(t, []rbacv1.Subject{
+		{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:team-a"},
+		group,
+		serviceAccount,
+		user,
+	}, subjects)
+}
+
+func TestTemplateSharingHandler_canCreateVMs(t *testing.T) {
+	clientSet := k8sfake.NewSimpleClientset()
+	var reviews []authorizationv1.SubjectAccessReviewSpec
+	clientSet.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		reviews = append(reviews, review.Spec)
+		review.Status.Allowed = review.Spec.User == "u-abcde"
+		return true, review, nil
+	})
+	handler := &templateSharingHandler{subjectAccessReviews: clientSet.AuthorizationV1().SubjectAccessReviews()}
+
+	allowed, err := handler.canCreateVMs("team-a", rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: "u-abcde"})
+	assert.Nil(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, &authorizationv1.ResourceAttributes{
+		Namespace: "team-a",
+		Verb:      "create",
+		Group:     "kubevirt.io",
+		Version:   "v1",
+		Resource:  "virtualmachines",
+	}, reviews[0].ResourceAttributes)
+
+	allowed, err = handler.canCreateVMs("team-a", rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: "github_team://1234"})
+	assert.Nil(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, []string{"github_team://1234"}, reviews[1].Groups)
+
+	_, err = handler.canCreateVMs("team-a", rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: "team-a", Name: "default"})
+	assert.Nil(t, err)
+	assert.Equal(t, "system:serviceaccount:team-a:default", reviews[2].User)
+	assert.Equal(t, []string{"system:serviceaccounts", "system:serviceaccounts:team-a"}, reviews[2].Groups)
+}
+
+func TestGroupConsumers(t *testing.T) {
+	assert.Nil(t, groupConsumers(map[string][]string{}))
+	assert.Equal(t, []cloudweavv1.VirtualMachineTemplateConsumer{
+		{Namespace: "team-a", VirtualMachines: []string{"vm-1", "vm-2"}},
+		{Namespace: "team-b", VirtualMachines: []string{"vm-3"}},
+	}, groupConsumers(map[string][]string{
+		"team-b": {"vm-3"},
+		"team-a": {"vm-2", "vm-1"},
+	}))
+}
@@ -0,0 +1,420 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	lhv1beta2 "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
+	ctllhv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/longhorn.io/v1beta2"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+const (
+	defaultCanarySoakPeriod = 30 * time.Minute
+	canaryProbeInterval     = time.Minute
+
+	canaryProbeNodeReady   = "nodeReady"
+	canaryProbeReplicas    = "replicasRebuilt"
+	canaryProbeSampleVMs   = "sampleVMs"
+	canaryProbeErrorEvents = "errorEvents"
+
+	canaryGuestAgentTimeout = 10 * time.Second
+)
+
+// convergingCanaryProbes are the probes which fail until the volumes are rebuilt and the sample VMs are migrated after
+// the canary node is upgraded, their samples count once they pass
+var convergingCanaryProbes = map[string]bool{
+	canaryProbeReplicas:  true,
+	canaryProbeSampleVMs: true,
+}
+
+// canaryHandler runs the health probes of the canary node for the soak period after the node is upgraded. The other
+// nodes are held by the schedule until the probes pass, the upgrade is paused if they fail.
+type canaryHandler struct {
+	namespace             string
+	upgradeClient         ctlcloudweavv1.UpgradeClient
+	upgradeController     ctlcloudweavv1.UpgradeController
+	nodeCache             ctlcorev1.NodeCache
+	volumeCache           ctllhv1.VolumeCache
+	vmiCache              ctlkubevirtv1.VirtualMachineInstanceCache
+	vmimClient            ctlkubevirtv1.VirtualMachineInstanceMigrationClient
+	eventClient           ctlcorev1.EventClient
+	virtRestClient        rest.Interface
+	virtSubresourceClient rest.Interface
+	now                   func() time.Time
+}
+
+func (h *canaryHandler) OnChanged(_ string, upgrade *cloudweavv1.Upgrade) (*cloudweavv1.Upgrade, error) {
+	if upgrade == nil || upgrade.DeletionTimestamp != nil || upgrade.Spec.Canary == nil ||
+		upgrade.Labels[upgradeStateLabel] != StateUpgradingNodes {
+		return upgrade, nil
+	}
+	canary := upgrade.Spec.Canary
+	status := upgrade.Status.Canary
+	now := h.now()
+
+	if status == nil {
+		if upgrade.Status.NodeStatuses[canary.Node].State != StateSucceeded {
+			return upgrade, nil
+		}
+		logrus.Infof("Canary node %s is upgraded, start the soak period", canary.Node)
+		for _, sampleVM := range canary.SampleVMs {
+			if err := h.migrateSampleVM(sampleVM, canary.Node); err != nil {
+				logrus.Warnf("Failed to migrate the sample VM %s to the canary node %s: %v", sampleVM, canary.Node, err)
+			}
+		}
+		toUpdate := upgrade.DeepCopy()
+		toUpdate.Status.Canary = &cloudweavv1.UpgradeCanaryStatus{
+			State:         cloudweavv1.CanaryStateSoaking,
+			SoakStartTime: now.UTC().Format(time.RFC3339),
+		}
+		h.upgradeController.EnqueueAfter(upgrade.Namespace, upgrade.Name, canaryProbeInterval)
+		return h.upgradeClient.Update(toUpdate)
+	}
+	if status.State != cloudweavv1.CanaryStateSoaking {
+		return upgrade, nil
+	}
+
+	soakStart, err := time.Parse(time.RFC3339, status.SoakStartTime)
+	if err != nil {
+		return upgrade, fmt.Errorf("invalid soak start time of the canary: %w", err)
+	}
+	soakEnd := soakStart.Add(canarySoakPeriod(canary))
+	// the status update triggers the handler again, so the probes don't run more often than the interval otherwise
+	if lastProbe, err := time.Parse(time.RFC3339, status.LastProbeTime); err == nil &&
+		now.Sub(lastProbe) < canaryProbeInterval && now.Before(soakEnd) {
+		h.upgradeController.EnqueueAfter(upgrade.Namespace, upgrade.Name, canaryProbeInterval-now.Sub(lastProbe))
+		return upgrade, nil
+	}
+
+	samples, err := h.probe(canary, soakStart)
+	if err != nil {
+		return upgrade, err
+	}
+	toUpdate := upgrade.DeepCopy()
+	probes := recordProbeSamples(status.Probes, samples, now.UTC().Format(time.RFC3339))
+	toUpdate.Status.Canary.Probes = probes
+	toUpdate.Status.Canary.LastProbeTime = now.UTC().Format(time.RFC3339)
+	if now.Before(soakEnd) {
+		next := canaryProbeInterval
+		if remaining := soakEnd.Sub(now); remaining < next {
+			next = remaining
+		}
+		h.upgradeController.EnqueueAfter(upgrade.Namespace, upgrade.Name, next)
+	} else {
+		finishCanary(toUpdate, probes)
+	}
+	return h.upgradeClient.Update(toUpdate)
+}
+
+func canarySoakPeriod(canary *cloudweavv1.UpgradeCanary) time.Duration {
+	if canary.SoakSeconds <= 0 {
+		return defaultCanarySoakPeriod
+	}
+	return time.Duration(canary.SoakSeconds) * time.Second
+}
+
+// recordProbeSamples records the samples in the probes of the soak period. A sample which fails after the probe
+// converged fails the probe for the rest of the soak period, even if the later samples pass.
+func recordProbeSamples(probes, samples []cloudweavv1.CanaryProbe, now string) []cloudweavv1.CanaryProbe {
+	recorded := make([]cloudweavv1.CanaryProbe, 0, len(samples))
+	for _, sample := range samples {
+		probe := cloudweavv1.CanaryProbe{Name: sample.Name, Converged: !convergingCanaryProbes[sample.Name]}
+		for _, previous := range probes {
+			if previous.Name == sample.Name {
+				probe = previous
+				break
+			}
+		}
+
+		switch {
+		case sample.Passed:
+			probe.Converged = true
+		case probe.Converged:
+			probe.FailedSamples++
+			probe.LastFailedTime = now
+			probe.Message = sample.Message
+		default:
+			probe.Message = sample.Message
+		}
+		probe.Passed = sample.Passed && probe.FailedSamples == 0
+		if probe.Passed {
+			probe.Message = ""
+		}
+		recorded = append(recorded, probe)
+	}
+	return recorded
+}
+
+// finishCanary decides the canary by the probes of the whole soak period. The upgrade is paused if the canary fails,
+// resuming it upgrades the remaining nodes anyway.
+func finishCanary(upgrade *cloudweavv1.Upgrade, probes []cloudweavv1.CanaryProbe) {
+	var failed []string
+	for _, probe := range probes {
+		switch {
+		case probe.FailedSamples > 0:
+			failed = append(failed, fmt.Sprintf("%s failed %d times during the soak period, the last time at %s: %s",
+				probe.Name, probe.FailedSamples, probe.LastFailedTime, probe.Message))
+		case !probe.Passed:
+			failed = append(failed, fmt.Sprintf("%s: %s", probe.Name, probe.Message))
+		}
+	}
+	if len(failed) == 0 {
+		logrus.Infof("Canary node %s passed the health probes", upgrade.Spec.Canary.Node)
+		upgrade.Status.Canary.State = cloudweavv1.CanaryStatePassed
+		cloudweavv1.CanaryFailed.False(upgrade)
+		cloudweavv1.CanaryFailed.Reason(upgrade, "")
+		cloudweavv1.CanaryFailed.Message(upgrade, "")
+		return
+	}
+
+	message := fmt.Sprintf("Canary node %s failed the health probes, %s", upgrade.Spec.Canary.Node, strings.Join(failed, "; "))
+	logrus.Warn(message)
+	upgrade.Status.Canary.State = cloudweavv1.CanaryStateFailed
+	upgrade.Spec.Paused = true
+	cloudweavv1.CanaryFailed.True(upgrade)
+	cloudweavv1.CanaryFailed.Reason(upgrade, "ProbesFailed")
+	cloudweavv1.CanaryFailed.Message(upgrade, message)
+}
+
+func (h *canaryHandler) probe(canary *cloudweavv1.UpgradeCanary, since time.Time) ([]cloudweavv1.CanaryProbe, error) {
+	node, err := h.nodeCache.Get(canary.Node)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	volumes, err := h.volumeCache.List(util.LonghornSystemNamespaceName, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	vmis := make(map[string]*kubevirtv1.VirtualMachineInstance, len(canary.SampleVMs))
+	agentErrs := make(map[string]error, len(canary.SampleVMs))
+	for _, sampleVM := range canary.SampleVMs {
+		namespace, name, err := cache.SplitMetaNamespaceKey(sampleVM)
+		if err != nil {
+			return nil, err
+		}
+		vmi, err := h.vmiCache.Get(namespace, name)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		vmis[sampleVM] = vmi
+		if vmi.Status.NodeName == canary.Node {
+			agentErrs[sampleVM] = h.queryGuestAgent(vmi)
+		}
+	}
+
+	events, err := h.listWarningEvents(canary)
+	if err != nil {
+		return nil, err
+	}
+
+	return []cloudweavv1.CanaryProbe{
+		probeNodeReady(canary.Node, node),
+		probeReplicas(volumes),
+		probeSampleVMs(canary, vmis, agentErrs),
+		probeErrorEvents(events, since),
+	}, nil
+}
+
+// listWarningEvents lists the warning events of the canary node and the sample VMs
+func (h *canaryHandler) listWarningEvents(canary *cloudweavv1.UpgradeCanary) ([]corev1.Event, error) {
+	type involvedObject struct {
+		kind, namespace, name string
+	}
+	objects := []involvedObject{{kind: "Node", namespace: metav1.NamespaceAll, name: canary.Node}}
+	for _, sampleVM := range canary.SampleVMs {
+		namespace, name, err := cache.SplitMetaNamespaceKey(sampleVM)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, involvedObject{kind: kubevirtv1.VirtualMachineInstanceGroupVersionKind.Kind, namespace: namespace, name: name})
+	}
+
+	var events []corev1.Event
+	for _, object := range objects {
+		selector := fields.Set{
+			"involvedObject.kind": object.kind,
+			"involvedObject.name": object.name,
+			"type":                corev1.EventTypeWarning,
+		}
+		list, err := h.eventClient.List(object.namespace, metav1.ListOptions{FieldSelector: selector.String()})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, list.Items...)
+	}
+	return events, nil
+}
+
+// queryGuestAgent queries the guest agent of the VM through virt-handler and virt-launcher on the canary node, so the
+// VM is checked end to end instead of by its AgentConnected condition, which is only updated periodically
+func (h *canaryHandler) queryGuestAgent(vmi *kubevirtv1.VirtualMachineInstance) error {
+	ctx, cancel := context.WithTimeout(context.Background(), canaryGuestAgentTimeout)
+	defer cancel()
+
+	var info kubevirtv1.VirtualMachineInstanceGuestAgentInfo
+	if err := h.virtSubresourceClient.Get().
+		Namespace(vmi.Namespace).
+		Resource("virtualmachineinstances").
+		Name(vmi.Name).
+		SubResource("guestosinfo").
+		Do(ctx).
+		Into(&info); err != nil {
+		return err
+	}
+	if info.GAVersion == "" {
+		return fmt.Errorf("guest agent of %s/%s doesn't answer", vmi.Namespace, vmi.Name)
+	}
+	return nil
+}
+
+// migrateSampleVM migrates the sample VM to the canary node, the migration target is set the same way as the migrate
+// action of VMs
+func (h *canaryHandler) migrateSampleVM(sampleVM, nodeName string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(sampleVM)
+	if err != nil {
+		return err
+	}
+	vmi, err := h.vmiCache.Get(namespace, name)
+	if err != nil {
+		return err
+	}
+	if !vmi.IsRunning() || vmi.Status.NodeName == nodeName {
+		return nil
+	}
+
+	toUpdate := vmi.DeepCopy()
+	if toUpdate.Annotations == nil {
+		toUpdate.Annotations = make(map[string]string)
+	}
+	if toUpdate.Spec.NodeSelector == nil {
+		toUpdate.Spec.NodeSelector = make(map[string]string)
+	}
+	toUpdate.Annotations[util.AnnotationMigrationTarget] = nodeName
+	toUpdate.Spec.NodeSelector[corev1.LabelHostname] = nodeName
+	if err := util.VirtClientUpdateVmi(context.Background(), h.virtRestClient, h.namespace, namespace, name, toUpdate); err != nil {
+		return err
+	}
+
+	_, err = h.vmimClient.Create(&kubevirtv1.VirtualMachineInstanceMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: name + "-",
+			Namespace:    namespace,
+		},
+		Spec: kubevirtv1.VirtualMachineInstanceMigrationSpec{
+			VMIName: name,
+		},
+	})
+	return err
+}
+
+func probeNodeReady(nodeName string, node *corev1.Node) cloudweavv1.CanaryProbe {
+	probe := cloudweavv1.CanaryProbe{Name: canaryProbeNodeReady}
+	if node == nil {
+		probe.Message = fmt.Sprintf("node %s is not found", nodeName)
+		return probe
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
+			probe.Passed = true
+			return probe
+		}
+	}
+	probe.Message = fmt.Sprintf("node %s is not ready", nodeName)
+	return probe
+}
+
+// probeReplicas passes if the replicas of the attached volumes are rebuilt after the node is back
+func probeReplicas(volumes []*lhv1beta2.Volume) cloudweavv1.CanaryProbe {
+	var unhealthy []string
+	for _, volume := range volumes {
+		if volume.Status.State == lhv1beta2.VolumeStateAttached && volume.Status.Robustness != lhv1beta2.VolumeRobustnessHealthy {
+			unhealthy = append(unhealthy, volume.Name)
+		}
+	}
+	if len(unhealthy) == 0 {
+		return cloudweavv1.CanaryProbe{Name: canaryProbeReplicas, Passed: true}
+	}
+	sort.Strings(unhealthy)
+	return cloudweavv1.CanaryProbe{
+		Name:    canaryProbeReplicas,
+		Message: fmt.Sprintf("volumes %s are not healthy", strings.Join(unhealthy, ", ")),
+	}
+}
+
+// probeSampleVMs passes if the sample VMs run on the canary node and their guest agents answer the queries
+func probeSampleVMs(canary *cloudweavv1.UpgradeCanary, vmis map[string]*kubevirtv1.VirtualMachineInstance, agentErrs map[string]error) cloudweavv1.CanaryProbe {
+	var problems []string
+	for _, sampleVM := range canary.SampleVMs {
+		vmi, ok := vmis[sampleVM]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s is not running", sampleVM))
+		case vmi.Status.NodeName != canary.Node:
+			problems = append(problems, fmt.Sprintf("%s runs on %s", sampleVM, vmi.Status.NodeName))
+		case !isVMIAgentConnected(vmi):
+			problems = append(problems, fmt.Sprintf("%s doesn't respond", sampleVM))
+		case agentErrs[sampleVM] != nil:
+			problems = append(problems, fmt.Sprintf("%s doesn't respond: %v", sampleVM, agentErrs[sampleVM]))
+		}
+	}
+	if len(problems) == 0 {
+		return cloudweavv1.CanaryProbe{Name: canaryProbeSampleVMs, Passed: true}
+	}
+	return cloudweavv1.CanaryProbe{Name: canaryProbeSampleVMs, Message: strings.Join(problems, ", ")}
+}
+
+func isVMIAgentConnected(vmi *kubevirtv1.VirtualMachineInstance) bool {
+	for _, cond := range vmi.Status.Conditions {
+		if cond.Type == kubevirtv1.VirtualMachineInstanceAgentConnected {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// probeErrorEvents passes if there's no new warning event since the soak period started
+func probeErrorEvents(events []corev1.Event, since time.Time) cloudweavv1.CanaryProbe {
+	var messages []string
+	for _, event := range events {
+		if eventTime(&event).Before(since) {
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("%s %s: %s", event.InvolvedObject.Name, event.Reason, event.Message))
+	}
+	if len(messages) == 0 {
+		return cloudweavv1.CanaryProbe{Name: canaryProbeErrorEvents, Passed: true}
+	}
+	return cloudweavv1.CanaryProbe{Name: canaryProbeErrorEvents, Message: strings.Join(messages, "; ")}
+}
+
+func eventTime(event *corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
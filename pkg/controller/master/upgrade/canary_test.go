@@ -0,0 +1,183 @@
+package upgrade
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	lhv1beta2 "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+func TestCanarySchedule(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+	spec := cloudweavv1.UpgradeSpec{
+		Canary: &cloudweavv1.UpgradeCanary{Node: "node3"},
+		NodeBatches: []cloudweavv1.UpgradeNodeBatch{
+			{Nodes: []string{"node1", "node2"}},
+			{Nodes: []string{"node3"}},
+		},
+	}
+	upgrade := newScheduledUpgrade(spec, map[string]string{
+		"node1": nodeStateImagesPreloaded,
+		"node2": nodeStateImagesPreloaded,
+		"node3": nodeStateImagesPreloaded,
+	})
+
+	state, _, _ := checkNodeSchedule(upgrade, "node1", now)
+	assert.Equal(t, nodeStateWaitingCanary, state, "other nodes wait for the canary")
+	state, _, _ = checkNodeSchedule(upgrade, "node3", now)
+	assert.Empty(t, state, "canary node goes first whatever its batch is")
+
+	upgrade.Status.NodeStatuses["node3"] = cloudweavv1.NodeUpgradeStatus{State: StateSucceeded}
+	upgrade.Status.Canary = &cloudweavv1.UpgradeCanaryStatus{State: cloudweavv1.CanaryStateSoaking}
+	state, _, _ = checkNodeSchedule(upgrade, "node1", now)
+	assert.Equal(t, nodeStateWaitingCanary, state, "other nodes wait for the soak period")
+
+	upgrade.Status.Canary.State = cloudweavv1.CanaryStatePassed
+	state, _, _ = checkNodeSchedule(upgrade, "node1", now)
+	assert.Empty(t, state)
+}
+
+func TestFinishCanary(t *testing.T) {
+	newCanaryUpgrade := func() *cloudweavv1.Upgrade {
+		upgrade := newScheduledUpgrade(cloudweavv1.UpgradeSpec{Canary: &cloudweavv1.UpgradeCanary{Node: "node1"}}, nil)
+		upgrade.Status.Canary = &cloudweavv1.UpgradeCanaryStatus{State: cloudweavv1.CanaryStateSoaking}
+		return upgrade
+	}
+
+	upgrade := newCanaryUpgrade()
+	finishCanary(upgrade, []cloudweavv1.CanaryProbe{{Name: canaryProbeNodeReady, Passed: true}})
+	assert.Equal(t, cloudweavv1.CanaryStatePassed, upgrade.Status.Canary.State)
+	assert.True(t, cloudweavv1.CanaryFailed.IsFalse(upgrade))
+	assert.False(t, upgrade.Spec.Paused)
+
+	upgrade = newCanaryUpgrade()
+	finishCanary(upgrade, []cloudweavv1.CanaryProbe{
+		{Name: canaryProbeNodeReady, Passed: true},
+		{Name: canaryProbeReplicas, Message: "volumes pvc-1 are not healthy"},
+	})
+	assert.Equal(t, cloudweavv1.CanaryStateFailed, upgrade.Status.Canary.State)
+	assert.True(t, cloudweavv1.CanaryFailed.IsTrue(upgrade))
+	assert.Contains(t, cloudweavv1.CanaryFailed.GetMessage(upgrade), "volumes pvc-1 are not healthy")
+	assert.True(t, upgrade.Spec.Paused)
+	assert.False(t, cloudweavv1.UpgradeCompleted.IsFalse(upgrade), "a failed canary doesn't fail the upgrade")
+}
+
+func TestRecordProbeSamples(t *testing.T) {
+	sample := func(nodeReady, replicas bool) []cloudweavv1.CanaryProbe {
+		samples := []cloudweavv1.CanaryProbe{
+			{Name: canaryProbeNodeReady, Passed: nodeReady},
+			{Name: canaryProbeReplicas, Passed: replicas},
+		}
+		for i := range samples {
+			if !samples[i].Passed {
+				samples[i].Message = samples[i].Name + " failed"
+			}
+		}
+		return samples
+	}
+
+	// the replicas are rebuilt after the node is back, the samples before then don't count
+	probes := recordProbeSamples(nil, sample(true, false), "t1")
+	assert.False(t, probes[1].Passed)
+	assert.False(t, probes[1].Converged)
+	assert.Zero(t, probes[1].FailedSamples)
+	probes = recordProbeSamples(probes, sample(true, true), "t2")
+	assert.True(t, probes[0].Passed)
+	assert.True(t, probes[1].Passed)
+	assert.Empty(t, probes[1].Message)
+
+	// a failed sample in the middle of the soak period fails the probe even if it passes again
+	probes = recordProbeSamples(probes, sample(false, true), "t3")
+	probes = recordProbeSamples(probes, sample(true, false), "t4")
+	probes = recordProbeSamples(probes, sample(true, true), "t5")
+	assert.Equal(t, cloudweavv1.CanaryProbe{
+		Name:           canaryProbeNodeReady,
+		Message:        "nodeReady failed",
+		Converged:      true,
+		FailedSamples:  1,
+		LastFailedTime: "t3",
+	}, probes[0])
+	assert.False(t, probes[1].Passed)
+	assert.Equal(t, 1, probes[1].FailedSamples)
+	assert.Equal(t, "t4", probes[1].LastFailedTime)
+
+	upgrade := newScheduledUpgrade(cloudweavv1.UpgradeSpec{Canary: &cloudweavv1.UpgradeCanary{Node: "node1"}}, nil)
+	upgrade.Status.Canary = &cloudweavv1.UpgradeCanaryStatus{State: cloudweavv1.CanaryStateSoaking}
+	finishCanary(upgrade, probes)
+	assert.Equal(t, cloudweavv1.CanaryStateFailed, upgrade.Status.Canary.State)
+	assert.Contains(t, cloudweavv1.CanaryFailed.GetMessage(upgrade), "nodeReady failed 1 times during the soak period, the last time at t3")
+}
+
+func TestCanaryProbes(t *testing.T) {
+	soakStart := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+	canary := &cloudweavv1.UpgradeCanary{Node: "node1", SampleVMs: []string{"default/vm1", "default/vm2", "default/vm3"}}
+
+	node := &corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+		{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+	}}}
+	assert.True(t, probeNodeReady("node1", node).Passed)
+	assert.False(t, probeNodeReady("node1", nil).Passed)
+
+	newVolume := func(name string, state lhv1beta2.VolumeState, robustness lhv1beta2.VolumeRobustness) *lhv1beta2.Volume {
+		return &lhv1beta2.Volume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     lhv1beta2.VolumeStatus{State: state, Robustness: robustness},
+		}
+	}
+	probe := probeReplicas([]*lhv1beta2.Volume{
+		newVolume("pvc-1", lhv1beta2.VolumeStateAttached, lhv1beta2.VolumeRobustnessHealthy),
+		newVolume("pvc-2", lhv1beta2.VolumeStateDetached, lhv1beta2.VolumeRobustnessUnknown),
+		newVolume("pvc-3", lhv1beta2.VolumeStateAttached, lhv1beta2.VolumeRobustnessDegraded),
+	})
+	assert.False(t, probe.Passed)
+	assert.Equal(t, "volumes pvc-3 are not healthy", probe.Message)
+
+	newVMI := func(nodeName string, agentConnected corev1.ConditionStatus) *kubevirtv1.VirtualMachineInstance {
+		return &kubevirtv1.VirtualMachineInstance{Status: kubevirtv1.VirtualMachineInstanceStatus{
+			NodeName: nodeName,
+			Conditions: []kubevirtv1.VirtualMachineInstanceCondition{
+				{Type: kubevirtv1.VirtualMachineInstanceAgentConnected, Status: agentConnected},
+			},
+		}}
+	}
+	probe = probeSampleVMs(canary, map[string]*kubevirtv1.VirtualMachineInstance{
+		"default/vm1": newVMI("node1", corev1.ConditionTrue),
+		"default/vm2": newVMI("node2", corev1.ConditionTrue),
+	}, map[string]error{"default/vm1": nil})
+	assert.False(t, probe.Passed)
+	assert.Equal(t, "default/vm2 runs on node2, default/vm3 is not running", probe.Message)
+	probe = probeSampleVMs(&cloudweavv1.UpgradeCanary{Node: "node1", SampleVMs: []string{"default/vm1"}}, map[string]*kubevirtv1.VirtualMachineInstance{
+		"default/vm1": newVMI("node1", corev1.ConditionFalse),
+	}, nil)
+	assert.Equal(t, "default/vm1 doesn't respond", probe.Message)
+	probe = probeSampleVMs(&cloudweavv1.UpgradeCanary{Node: "node1", SampleVMs: []string{"default/vm1"}}, map[string]*kubevirtv1.VirtualMachineInstance{
+		"default/vm1": newVMI("node1", corev1.ConditionTrue),
+	}, map[string]error{"default/vm1": errors.New("guest agent of default/vm1 doesn't answer")})
+	assert.Equal(t, "default/vm1 doesn't respond: guest agent of default/vm1 doesn't answer", probe.Message)
+
+	events := []corev1.Event{
+		{
+			InvolvedObject: corev1.ObjectReference{Name: "node1"},
+			Reason:         "Rebooted",
+			Message:        "Node node1 has been rebooted",
+			LastTimestamp:  metav1.NewTime(soakStart.Add(-time.Minute)),
+		},
+	}
+	assert.True(t, probeErrorEvents(events, soakStart).Passed, "events before the soak period are ignored")
+	events = append(events, corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Name: "node1"},
+		Reason:         "SystemOOM",
+		Message:        "System OOM encountered",
+		LastTimestamp:  metav1.NewTime(soakStart.Add(time.Minute)),
+	})
+	probe = probeErrorEvents(events, soakStart)
+	assert.False(t, probe.Passed)
+	assert.Equal(t, "node1 SystemOOM: System OOM encountered", probe.Message)
+}
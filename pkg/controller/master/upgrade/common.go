@@ -675,6 +675,15 @@ func (p *upgradeBuilder) ChartUpgradeStatus(status corev1.ConditionStatus, reaso
 	return p
 }
 
+func (p *upgradeBuilder) ImagePreloadStatus(strategy string, concurrency, maxConcurrency int) *upgradeBuilder {
+	p.upgrade.Status.ImagePreload = &cloudweavv1.ImagePreloadStatus{
+		Strategy:       strategy,
+		Concurrency:    concurrency,
+		MaxConcurrency: maxConcurrency,
+	}
+	return p
+}
+
 func (p *upgradeBuilder) InitStatus() *upgradeBuilder {
 	initStatus(p.upgrade)
 	return p
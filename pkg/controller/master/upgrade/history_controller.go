@@ -0,0 +1,347 @@
+package upgrade
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
+)
+
+const (
+	// historyOutcomeDeleted is the outcome of the upgrades which are deleted before they complete
+	historyOutcomeDeleted = "Deleted"
+
+	// maxUpgradeHistories is how many completed upgrade histories are kept, the oldest are deleted
+	maxUpgradeHistories = 20
+)
+
+// historyHandler records the timings and the outcome of upgrades in UpgradeHistory objects
+type historyHandler struct {
+	historyClient ctlcloudweavv1.UpgradeHistoryClient
+	historyCache  ctlcloudweavv1.UpgradeHistoryCache
+	vmimCache     ctlkubevirtv1.VirtualMachineInstanceMigrationCache
+	now           func() time.Time
+}
+
+func (h *historyHandler) OnChanged(_ string, upgrade *cloudweavv1.Upgrade) (*cloudweavv1.Upgrade, error) {
+	if upgrade == nil || upgrade.DeletionTimestamp != nil {
+		return upgrade, nil
+	}
+
+	history, err := h.historyCache.Get(upgrade.Namespace, upgrade.Name)
+	if apierrors.IsNotFound(err) {
+		history, err = h.historyClient.Create(&cloudweavv1.UpgradeHistory{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      upgrade.Name,
+				Namespace: upgrade.Namespace,
+				Labels: map[string]string{
+					cloudweavUpgradeLabel: upgrade.Name,
+				},
+			},
+			Spec: cloudweavv1.UpgradeHistorySpec{
+				UpgradeName: upgrade.Name,
+				Version:     upgrade.Spec.Version,
+			},
+		})
+		if err == nil {
+			err = h.pruneHistories(upgrade.Namespace)
+		}
+	}
+	if err != nil {
+		return upgrade, err
+	}
+
+	return upgrade, h.updateHistory(history, upgrade, recordHistory)
+}
+
+// OnRemove records the upgrade which is deleted before it completes, so the history doesn't stay in progress
+func (h *historyHandler) OnRemove(_ string, upgrade *cloudweavv1.Upgrade) (*cloudweavv1.Upgrade, error) {
+	if upgrade == nil {
+		return upgrade, nil
+	}
+
+	history, err := h.historyCache.Get(upgrade.Namespace, upgrade.Name)
+	if apierrors.IsNotFound(err) {
+		return upgrade, nil
+	} else if err != nil {
+		return upgrade, err
+	}
+
+	return upgrade, h.updateHistory(history, upgrade, func(history *cloudweavv1.UpgradeHistory, upgrade *cloudweavv1.Upgrade, now time.Time) {
+		recordHistory(history, upgrade, now)
+		if history.Status.Outcome == "" {
+			history.Status.EndTime = now.UTC().Format(time.RFC3339)
+			history.Status.Outcome = historyOutcomeDeleted
+			addHistoryWarning(history, "the upgrade is deleted before it completes")
+		}
+	})
+}
+
+// updateHistory records the upgrade in the history, and counts the VM migrations once the outcome is recorded
+func (h *historyHandler) updateHistory(history *cloudweavv1.UpgradeHistory, upgrade *cloudweavv1.Upgrade,
+	record func(*cloudweavv1.UpgradeHistory, *cloudweavv1.Upgrade, time.Time)) error {
+	if history.Status.Outcome != "" && history.Status.VMMigrations != nil {
+		return nil
+	}
+
+	toUpdate := history.DeepCopy()
+	record(toUpdate, upgrade, h.now())
+	if toUpdate.Status.Outcome != "" && toUpdate.Status.VMMigrations == nil {
+		migrations, err := h.countVMMigrations(toUpdate)
+		if err != nil {
+			return err
+		}
+		toUpdate.Status.VMMigrations = migrations
+	}
+
+	if reflect.DeepEqual(history, toUpdate) {
+		return nil
+	}
+	_, err := h.historyClient.Update(toUpdate)
+	return err
+}
+
+// pruneHistories deletes the oldest completed histories in the namespace over maxUpgradeHistories
+func (h *historyHandler) pruneHistories(namespace string) error {
+	histories, err := h.historyCache.List(namespace, labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	completed := make([]*cloudweavv1.UpgradeHistory, 0, len(histories))
+	for _, history := range histories {
+		if history.Status.Outcome != "" && history.DeletionTimestamp == nil {
+			completed = append(completed, history)
+		}
+	}
+	if len(completed) <= maxUpgradeHistories {
+		return nil
+	}
+
+	sort.Slice(completed, func(i, j int) bool {
+		return completed[i].CreationTimestamp.Before(&completed[j].CreationTimestamp)
+	})
+	for _, history := range completed[:len(completed)-maxUpgradeHistories] {
+		if err := h.historyClient.Delete(history.Namespace, history.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// countVMMigrations counts the VM migrations which evacuate the nodes being upgraded, the migrations are matched by
+// their source node and the time the node is upgraded. The migrations which fail before they start have no source
+// node and aren't counted.
+func (h *historyHandler) countVMMigrations(history *cloudweavv1.UpgradeHistory) (*cloudweavv1.VMMigrationHistory, error) {
+	end, err := time.Parse(time.RFC3339, history.Status.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end time of upgrade history %s/%s: %w", history.Namespace, history.Name, err)
+	}
+
+	type upgradeWindow struct {
+		start, end time.Time
+	}
+	windows := make(map[string]upgradeWindow, len(history.Status.Nodes))
+	for _, node := range history.Status.Nodes {
+		start, err := time.Parse(time.RFC3339, node.UpgradeStartTime)
+		if err != nil {
+			// the node isn't drained
+			continue
+		}
+		window := upgradeWindow{start: start, end: end}
+		if nodeEnd, err := time.Parse(time.RFC3339, node.EndTime); err == nil {
+			window.end = nodeEnd
+		}
+		windows[node.Name] = window
+	}
+	migrations := &cloudweavv1.VMMigrationHistory{}
+	if len(windows) == 0 {
+		return migrations, nil
+	}
+
+	vmims, err := h.vmimCache.List(metav1.NamespaceAll, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, vmim := range vmims {
+		if vmim.Status.MigrationState == nil {
+			continue
+		}
+		window, ok := windows[vmim.Status.MigrationState.SourceNode]
+		created := vmim.CreationTimestamp.Time
+		if !ok || created.Before(window.start) || created.After(window.end) {
+			continue
+		}
+		switch vmim.Status.Phase {
+		case kubevirtv1.MigrationSucceeded:
+			migrations.Succeeded++
+		case kubevirtv1.MigrationFailed:
+			migrations.Failed++
+		}
+	}
+	return migrations, nil
+}
+
+// historyPhases are the conditions recorded as phases
+var historyPhases = []string{
+	string(cloudweavv1.LogReady),
+	string(cloudweavv1.ImageReady),
+	string(cloudweavv1.RepoProvisioned),
+	string(cloudweavv1.NodesPrepared),
+	string(cloudweavv1.SystemServicesUpgraded),
+	string(cloudweavv1.NodesUpgraded),
+}
+
+// recordHistory records what changed in the upgrade since the last time. The phases use the update time of their
+// conditions, the node states don't have one so the node timings are the time the transitions are seen.
+func recordHistory(history *cloudweavv1.UpgradeHistory, upgrade *cloudweavv1.Upgrade, now time.Time) {
+	nowTime := now.UTC().Format(time.RFC3339)
+	if history.Spec.PreviousVersion == "" {
+		history.Spec.PreviousVersion = upgrade.Status.PreviousVersion
+	}
+	if history.Status.StartTime == "" {
+		history.Status.StartTime = upgrade.CreationTimestamp.UTC().Format(time.RFC3339)
+	}
+
+	for _, phaseName := range historyPhases {
+		cond := findUpgradeCondition(upgrade, phaseName)
+		if cond == nil {
+			continue
+		}
+		phase := findPhaseHistory(history, phaseName)
+		if phase == nil {
+			history.Status.Phases = append(history.Status.Phases, cloudweavv1.UpgradePhaseHistory{
+				Name:      phaseName,
+				StartTime: conditionTime(cond, nowTime),
+			})
+			phase = &history.Status.Phases[len(history.Status.Phases)-1]
+		}
+		if phase.EndTime != "" || cond.Status == corev1.ConditionUnknown {
+			continue
+		}
+		phase.EndTime = conditionTime(cond, nowTime)
+		phase.Result = string(cond.Status)
+		phase.Message = cond.Message
+		// the log phase is false when the upgrade log is disabled
+		if cond.Status == corev1.ConditionFalse && cond.Reason != logReadyDisabledReason {
+			addHistoryWarning(history, fmt.Sprintf("%s: %s", phaseName, cond.Message))
+		}
+	}
+
+	nodeNames := make([]string, 0, len(upgrade.Status.NodeStatuses))
+	for nodeName := range upgrade.Status.NodeStatuses {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		nodeStatus := upgrade.Status.NodeStatuses[nodeName]
+		node := findNodeHistory(history, nodeName)
+		if node == nil {
+			history.Status.Nodes = append(history.Status.Nodes, cloudweavv1.NodeUpgradeHistory{Name: nodeName})
+			node = &history.Status.Nodes[len(history.Status.Nodes)-1]
+		}
+		if node.State == nodeStatus.State {
+			continue
+		}
+		node.State = nodeStatus.State
+		node.Message = nodeStatus.Message
+		recordNodeTransition(node, nowTime)
+		if nodeStatus.State == StateFailed {
+			addHistoryWarning(history, fmt.Sprintf("node %s failed: %s", nodeName, nodeStatus.Message))
+		}
+	}
+
+	if upgrade.Status.Rollback != nil {
+		for _, action := range upgrade.Status.Rollback.ManualActions {
+			addHistoryWarning(history, fmt.Sprintf("rollback: %s", action))
+		}
+	}
+
+	if history.Status.Outcome != "" {
+		return
+	}
+	completed := findUpgradeCondition(upgrade, string(cloudweavv1.UpgradeCompleted))
+	if completed == nil || completed.Status == corev1.ConditionUnknown {
+		return
+	}
+	history.Status.EndTime = conditionTime(completed, nowTime)
+	if completed.Status == corev1.ConditionTrue {
+		history.Status.Outcome = StateSucceeded
+	} else {
+		history.Status.Outcome = StateFailed
+	}
+}
+
+func recordNodeTransition(node *cloudweavv1.NodeUpgradeHistory, nowTime string) {
+	switch node.State {
+	case nodeStateImagesPreloading:
+		if node.PreloadStartTime == "" {
+			node.PreloadStartTime = nowTime
+		}
+	case nodeStateImagesPreloaded:
+		if node.PreloadEndTime == "" {
+			node.PreloadEndTime = nowTime
+		}
+	case nodeStatePreDraining, nodeStatePreDrained, nodeStatePostDraining, nodeStateWatingReboot:
+		if node.UpgradeStartTime == "" {
+			node.UpgradeStartTime = nowTime
+		}
+	case StateSucceeded, StateFailed, nodeStateSkipped:
+		if node.EndTime == "" {
+			node.EndTime = nowTime
+		}
+	}
+}
+
+func conditionTime(cond *cloudweavv1.Condition, nowTime string) string {
+	if _, err := time.Parse(time.RFC3339, cond.LastUpdateTime); err == nil {
+		return cond.LastUpdateTime
+	}
+	return nowTime
+}
+
+func findUpgradeCondition(upgrade *cloudweavv1.Upgrade, conditionType string) *cloudweavv1.Condition {
+	for i := range upgrade.Status.Conditions {
+		if string(upgrade.Status.Conditions[i].Type) == conditionType {
+			return &upgrade.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func findPhaseHistory(history *cloudweavv1.UpgradeHistory, name string) *cloudweavv1.UpgradePhaseHistory {
+	for i := range history.Status.Phases {
+		if history.Status.Phases[i].Name == name {
+			return &history.Status.Phases[i]
+		}
+	}
+	return nil
+}
+
+func findNodeHistory(history *cloudweavv1.UpgradeHistory, name string) *cloudweavv1.NodeUpgradeHistory {
+	for i := range history.Status.Nodes {
+		if history.Status.Nodes[i].Name == name {
+			return &history.Status.Nodes[i]
+		}
+	}
+	return nil
+}
+
+func addHistoryWarning(history *cloudweavv1.UpgradeHistory, warning string) {
+	for _, existing := range history.Status.Warnings {
+		if existing == warning {
+			return
+		}
+	}
+	history.Status.Warnings = append(history.Status.Warnings, warning)
+}
@@ -0,0 +1,213 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rancher/wrangler/v3/pkg/condition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/fake"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+)
+
+func setTestConditionTime(upgrade *cloudweavv1.Upgrade, conditionType condition.Cond, ts string) {
+	for i := range upgrade.Status.Conditions {
+		if upgrade.Status.Conditions[i].Type == conditionType {
+			upgrade.Status.Conditions[i].LastUpdateTime = ts
+		}
+	}
+}
+
+func TestRecordHistory(t *testing.T) {
+	start := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	ts := func(minutes int) string { return at(minutes).Format(time.RFC3339) }
+
+	upgrade := newTestUpgradeBuilder().WithLogEnabled(false).InitStatus().
+		LogReadyCondition(corev1.ConditionFalse, logReadyDisabledReason, "Upgrade observability is administratively disabled").Build()
+	upgrade.CreationTimestamp = metav1.NewTime(start)
+	upgrade.Status.PreviousVersion = "v1.4.0"
+	setTestConditionTime(upgrade, cloudweavv1.LogReady, ts(0))
+	history := &cloudweavv1.UpgradeHistory{}
+
+	recordHistory(history, upgrade, at(0))
+	assert.Equal(t, "v1.4.0", history.Spec.PreviousVersion)
+	assert.Equal(t, ts(0), history.Status.StartTime)
+	require.Len(t, history.Status.Phases, 1)
+	assert.Empty(t, history.Status.Warnings, "disabled log isn't a warning")
+
+	setImageReadyCondition(upgrade, corev1.ConditionUnknown, "", "")
+	setTestConditionTime(upgrade, cloudweavv1.ImageReady, ts(1))
+	recordHistory(history, upgrade, at(1))
+	setImageReadyCondition(upgrade, corev1.ConditionTrue, "", "")
+	setTestConditionTime(upgrade, cloudweavv1.ImageReady, ts(5))
+	upgrade.Status.NodeStatuses = map[string]cloudweavv1.NodeUpgradeStatus{
+		"node1": {State: nodeStateImagesPreloading},
+		"node2": {State: nodeStateImagesPreloading},
+	}
+	recordHistory(history, upgrade, at(5))
+	upgrade.Status.NodeStatuses = map[string]cloudweavv1.NodeUpgradeStatus{
+		"node1": {State: nodeStateImagesPreloaded},
+		"node2": {State: nodeStateImagesPreloaded},
+	}
+	recordHistory(history, upgrade, at(15))
+	upgrade.Status.NodeStatuses = map[string]cloudweavv1.NodeUpgradeStatus{
+		"node1": {State: nodeStatePreDraining},
+		"node2": {State: nodeStateImagesPreloaded},
+	}
+	recordHistory(history, upgrade, at(20))
+	upgrade.Status.NodeStatuses = map[string]cloudweavv1.NodeUpgradeStatus{
+		"node1": {State: StateSucceeded},
+		"node2": {State: StateFailed, Message: "job failed"},
+	}
+	setNodesUpgradedCondition(upgrade, corev1.ConditionFalse, "", "job failed")
+	setTestConditionTime(upgrade, cloudweavv1.NodesUpgraded, ts(40))
+	cloudweavv1.UpgradeCompleted.False(upgrade)
+	setTestConditionTime(upgrade, cloudweavv1.UpgradeCompleted, ts(40))
+	recordHistory(history, upgrade, at(41))
+
+	assert.Equal(t, StateFailed, history.Status.Outcome)
+	assert.Equal(t, ts(40), history.Status.EndTime)
+
+	phase := findPhaseHistory(history, string(cloudweavv1.ImageReady))
+	require.NotNil(t, phase)
+	assert.Equal(t, ts(1), phase.StartTime)
+	assert.Equal(t, ts(5), phase.EndTime)
+	assert.Equal(t, string(corev1.ConditionTrue), phase.Result)
+
+	node1 := findNodeHistory(history, "node1")
+	require.NotNil(t, node1)
+	assert.Equal(t, cloudweavv1.NodeUpgradeHistory{
+		Name:             "node1",
+		PreloadStartTime: ts(5),
+		PreloadEndTime:   ts(15),
+		UpgradeStartTime: ts(20),
+		EndTime:          ts(41),
+		State:            StateSucceeded,
+	}, *node1)
+	node2 := findNodeHistory(history, "node2")
+	require.NotNil(t, node2)
+	assert.Empty(t, node2.UpgradeStartTime)
+
+	assert.Equal(t, []string{"NodesUpgraded: job failed", "node node2 failed: job failed"}, history.Status.Warnings)
+
+	// the history doesn't change once the outcome is recorded
+	expected := history.DeepCopy()
+	recordHistory(history, upgrade, at(60))
+	assert.Equal(t, expected, history)
+}
+
+func TestCountVMMigrations(t *testing.T) {
+	start := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	newMigration := func(name, sourceNode string, created time.Time, phase kubevirtv1.VirtualMachineInstanceMigrationPhase) *kubevirtv1.VirtualMachineInstanceMigration {
+		vmim := &kubevirtv1.VirtualMachineInstanceMigration{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(created)},
+			Status:     kubevirtv1.VirtualMachineInstanceMigrationStatus{Phase: phase},
+		}
+		if sourceNode != "" {
+			vmim.Status.MigrationState = &kubevirtv1.VirtualMachineInstanceMigrationState{SourceNode: sourceNode}
+		}
+		return vmim
+	}
+	clientset := fake.NewSimpleClientset(
+		newMigration("before", "node1", start.Add(-time.Hour), kubevirtv1.MigrationSucceeded),
+		newMigration("succeeded1", "node1", start.Add(10*time.Minute), kubevirtv1.MigrationSucceeded),
+		newMigration("succeeded2", "node2", start.Add(40*time.Minute), kubevirtv1.MigrationSucceeded),
+		newMigration("failed", "node2", start.Add(50*time.Minute), kubevirtv1.MigrationFailed),
+		newMigration("not-started", "", start.Add(50*time.Minute), kubevirtv1.MigrationFailed),
+		newMigration("node1-after-upgrade", "node1", start.Add(40*time.Minute), kubevirtv1.MigrationSucceeded),
+		newMigration("not-drained", "node3", start.Add(10*time.Minute), kubevirtv1.MigrationSucceeded),
+		newMigration("after", "node2", start.Add(2*time.Hour), kubevirtv1.MigrationFailed),
+	)
+	handler := &historyHandler{
+		vmimCache: fakeclients.VirtualMachineInstanceMigrationCache(clientset.KubevirtV1().VirtualMachineInstanceMigrations),
+	}
+	history := &cloudweavv1.UpgradeHistory{
+		Status: cloudweavv1.UpgradeHistoryStatus{
+			StartTime: start.Format(time.RFC3339),
+			EndTime:   start.Add(time.Hour).Format(time.RFC3339),
+			Nodes: []cloudweavv1.NodeUpgradeHistory{
+				{
+					Name:             "node1",
+					UpgradeStartTime: start.Format(time.RFC3339),
+					EndTime:          start.Add(30 * time.Minute).Format(time.RFC3339),
+				},
+				{
+					Name:             "node2",
+					UpgradeStartTime: start.Add(30 * time.Minute).Format(time.RFC3339),
+				},
+				{
+					Name: "node3",
+				},
+			},
+		},
+	}
+	migrations, err := handler.countVMMigrations(history)
+	require.NoError(t, err)
+	assert.Equal(t, &cloudweavv1.VMMigrationHistory{Succeeded: 2, Failed: 1}, migrations)
+}
+
+func TestHistoryHandler_OnRemove(t *testing.T) {
+	now := time.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC)
+	upgrade := newTestUpgradeBuilder().InitStatus().Build()
+	upgrade.CreationTimestamp = metav1.NewTime(now.Add(-time.Hour))
+	clientset := fake.NewSimpleClientset(&cloudweavv1.UpgradeHistory{
+		ObjectMeta: metav1.ObjectMeta{Name: upgrade.Name, Namespace: upgrade.Namespace},
+		Spec:       cloudweavv1.UpgradeHistorySpec{UpgradeName: upgrade.Name},
+	})
+	handler := &historyHandler{
+		historyClient: fakeclients.UpgradeHistoryClient(clientset.CloudweavhciV1beta1().UpgradeHistories),
+		historyCache:  fakeclients.UpgradeHistoryCache(clientset.CloudweavhciV1beta1().UpgradeHistories),
+		vmimCache:     fakeclients.VirtualMachineInstanceMigrationCache(clientset.KubevirtV1().VirtualMachineInstanceMigrations),
+		now:           func() time.Time { return now },
+	}
+
+	_, err := handler.OnRemove(upgrade.Name, upgrade)
+	require.NoError(t, err)
+	history, err := clientset.CloudweavhciV1beta1().UpgradeHistories(upgrade.Namespace).Get(context.TODO(), upgrade.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, historyOutcomeDeleted, history.Status.Outcome)
+	assert.Equal(t, now.Format(time.RFC3339), history.Status.EndTime)
+	assert.Equal(t, &cloudweavv1.VMMigrationHistory{}, history.Status.VMMigrations)
+}
+
+func TestPruneHistories(t *testing.T) {
+	created := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	var objs []runtime.Object
+	for i := 0; i < maxUpgradeHistories+2; i++ {
+		objs = append(objs, &cloudweavv1.UpgradeHistory{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("hvst-upgrade-%02d", i),
+				Namespace:         upgradeNamespace,
+				CreationTimestamp: metav1.NewTime(created.Add(time.Duration(i) * time.Hour)),
+			},
+			Status: cloudweavv1.UpgradeHistoryStatus{Outcome: StateSucceeded},
+		})
+	}
+	// the upgrade in progress isn't pruned
+	objs = append(objs, &cloudweavv1.UpgradeHistory{
+		ObjectMeta: metav1.ObjectMeta{Name: "hvst-upgrade-in-progress", Namespace: upgradeNamespace, CreationTimestamp: metav1.NewTime(created)},
+	})
+	clientset := fake.NewSimpleClientset(objs...)
+	handler := &historyHandler{
+		historyClient: fakeclients.UpgradeHistoryClient(clientset.CloudweavhciV1beta1().UpgradeHistories),
+		historyCache:  fakeclients.UpgradeHistoryCache(clientset.CloudweavhciV1beta1().UpgradeHistories),
+	}
+
+	require.NoError(t, handler.pruneHistories(upgradeNamespace))
+	histories, err := clientset.CloudweavhciV1beta1().UpgradeHistories(upgradeNamespace).List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, histories.Items, maxUpgradeHistories+1)
+	for _, history := range histories.Items {
+		assert.NotContains(t, []string{"hvst-upgrade-00", "hvst-upgrade-01"}, history.Name)
+	}
+}
@@ -39,6 +39,10 @@ const (
 	nodeStatePreDrained             = "Pre-drained"
 	nodeStatePostDraining           = "Post-draining"
 	nodeStateWatingReboot           = "Waiting Reboot"
+	nodeStatePaused                 = "Paused"
+	nodeStateWaitingWindow          = "Waiting window"
+	nodeStateWaitingBatch           = "Waiting batch"
+	nodeStateWaitingCanary          = "Waiting canary"
 	upgradePlanLabel                = "upgrade.cattle.io/plan"
 	upgradeNodeLabel                = "upgrade.cattle.io/node"
 	upgradeStateLabel               = "cloudweavhci.io/upgradeState"
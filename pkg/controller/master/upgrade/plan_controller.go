@@ -43,7 +43,7 @@ func (h *planHandler) OnChanged(_ string, plan *upgradev1.Plan) (*upgradev1.Plan
 	if err != nil {
 		return plan, err
 	}
-	selector, err := metav1.LabelSelectorAsSelector(plan.Spec.NodeSelector)
+	selector, err := metav1.LabelSelectorAsSelector(planNodeSelector(plan))
 	if err != nil {
 		return plan, err
 	}
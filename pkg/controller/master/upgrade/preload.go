@@ -0,0 +1,508 @@
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	upgradev1 "github.com/rancher/system-upgrade-controller/pkg/apis/upgrade.cattle.io/v1"
+	ctlbatchv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/batch/v1"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	kubeletstatsv1 "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	ctlupgradev1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/upgrade.cattle.io/v1"
+	"github.com/cloudweav/cloudweav/pkg/settings"
+)
+
+const (
+	// preloadPausedLabel keeps the prepare plan of the adaptive strategy off a node, the value is the upgrade name
+	preloadPausedLabel = "cloudweavhci.io/imagePreloadPaused"
+
+	preloadDiskCheckInterval  = time.Minute
+	defaultPreloadMinFreeDisk = 30 * 1024 * 1024 * 1024 // 30GB
+	// a node which started preloading is paused before it fills up, once it has less free disk space than this
+	preloadReservedDisk = 5 * 1024 * 1024 * 1024 // 5GB
+	// the prepare plan can't complete without the paused nodes, the upgrade fails if one stays paused longer than this
+	preloadPauseTimeout = time.Hour
+	// the adaptive strategy scales once it has this many throughput samples at the current concurrency
+	preloadMinThroughputSamples = 3
+	// the adaptive strategy keeps this many of the latest throughput samples of each concurrency
+	preloadMaxThroughputSamples = 10
+	// the adaptive strategy scales down if the throughput drops below this ratio of the throughput one node lower
+	preloadThroughputTolerance = 0.9
+)
+
+// preloadHandler tracks the image preloading of each node in the upgrade status. With the adaptive strategy, it also
+// pauses the nodes short of disk space and scales the concurrency of the prepare plan with the image pull throughput.
+type preloadHandler struct {
+	namespace         string
+	upgradeClient     ctlcloudweavv1.UpgradeClient
+	upgradeController ctlcloudweavv1.UpgradeController
+	planClient        ctlupgradev1.PlanClient
+	planCache         ctlupgradev1.PlanCache
+	jobClient         ctlbatchv1.JobClient
+	jobCache          ctlbatchv1.JobCache
+	nodeClient        ctlcorev1.NodeClient
+	nodeCache         ctlcorev1.NodeCache
+	nodeStats         func(nodeName string) (*kubeletstatsv1.Summary, error)
+	now               func() time.Time
+}
+
+func (h *preloadHandler) OnChanged(_ string, upgrade *cloudweavv1.Upgrade) (*cloudweavv1.Upgrade, error) {
+	if upgrade == nil || upgrade.Status.ImagePreload == nil {
+		return upgrade, nil
+	}
+	if upgrade.DeletionTimestamp != nil || upgrade.Labels[upgradeStateLabel] != StatePreparingNodes {
+		return upgrade, h.resumeNodes(upgrade.Name)
+	}
+
+	plan, err := h.planCache.Get(sucNamespace, fmt.Sprintf("%s-prepare", upgrade.Name))
+	if apierrors.IsNotFound(err) {
+		return upgrade, nil
+	} else if err != nil {
+		return upgrade, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(planNodeSelector(plan))
+	if err != nil {
+		return upgrade, err
+	}
+	nodes, err := h.nodeCache.List(selector)
+	if err != nil {
+		return upgrade, err
+	}
+	jobs, err := h.jobCache.List(sucNamespace, labels.SelectorFromSet(labels.Set{upgradePlanLabel: plan.Name}))
+	if err != nil {
+		return upgrade, err
+	}
+
+	toUpdate := upgrade.DeepCopy()
+	status := toUpdate.Status.ImagePreload
+	latestJobs := syncPreloadNodes(status, nodes, jobs)
+
+	if status.Strategy == string(settings.AdaptiveType) {
+		now := h.now()
+		lastCheck, err := time.Parse(time.RFC3339, status.LastDiskCheckTime)
+		if err != nil || now.Sub(lastCheck) >= preloadDiskCheckInterval {
+			if err := h.checkNodes(toUpdate, nodes, latestJobs, lastCheck, now); err != nil {
+				return upgrade, err
+			}
+			status.LastDiskCheckTime = now.UTC().Format(time.RFC3339)
+			h.upgradeController.EnqueueAfter(upgrade.Namespace, upgrade.Name, preloadDiskCheckInterval)
+		}
+
+		if message := preloadPauseTimeoutMessage(status, now); message != "" {
+			logrus.Warnf("Upgrade %s failed: %s", upgrade.Name, message)
+			setNodesPreparedCondition(toUpdate, corev1.ConditionFalse, "", message)
+			setUpgradeCompletedCondition(toUpdate, StateFailed, corev1.ConditionFalse, message, "")
+			return h.upgradeClient.Update(toUpdate)
+		}
+
+		if concurrency := scalePreloadConcurrency(status); concurrency != status.Concurrency {
+			logrus.Infof("Scale the image preload concurrency of upgrade %s from %d to %d", upgrade.Name, status.Concurrency, concurrency)
+			planUpdate := plan.DeepCopy()
+			planUpdate.Spec.Concurrency = int64(concurrency)
+			if _, err := h.planClient.Update(planUpdate); err != nil {
+				return upgrade, err
+			}
+			status.Concurrency = concurrency
+			status.LastScaleTime = now.UTC().Format(time.RFC3339)
+		}
+	}
+
+	if reflect.DeepEqual(upgrade.Status, toUpdate.Status) {
+		return upgrade, nil
+	}
+	return h.upgradeClient.Update(toUpdate)
+}
+
+// checkNodes checks the disk space of the nodes which haven't finished preloading. A node which hasn't started is
+// paused if it's under disk pressure or short of free disk space, a preloading node is paused and its job deleted once
+// it's under disk pressure or about to fill up. The paused nodes are resumed once there's enough space again.
+// The growth of the image filesystems of the preloading nodes since the last check is recorded as a throughput sample.
+func (h *preloadHandler) checkNodes(upgrade *cloudweavv1.Upgrade, nodes []*corev1.Node, jobs map[string]*batchv1.Job, lastCheck, now time.Time) error {
+	status := upgrade.Status.ImagePreload
+	minFree, err := preloadMinFreeDisk()
+	if err != nil {
+		return err
+	}
+
+	var preloading, sampled int
+	var pulledBytes int64
+	var pausedMessages []string
+	for _, node := range nodes {
+		nodeStatus := status.Nodes[node.Name]
+		if nodeStatus.State != cloudweavv1.ImagePreloadStatePending && nodeStatus.State != cloudweavv1.ImagePreloadStatePreloading &&
+			nodeStatus.State != cloudweavv1.ImagePreloadStatePaused {
+			nodeStatus.ImageFsUsedBytes = 0
+			status.Nodes[node.Name] = nodeStatus
+			continue
+		}
+
+		summary, statsErr := h.nodeStats(node.Name)
+		if statsErr != nil {
+			summary = nil
+		}
+
+		started := nodeStatus.State == cloudweavv1.ImagePreloadStatePreloading
+		previousUsed := nodeStatus.ImageFsUsedBytes
+		nodeStatus.ImageFsUsedBytes = 0
+		if started {
+			preloading++
+			if used, ok := imageFsUsedBytes(summary); ok {
+				nodeStatus.ImageFsUsedBytes = used
+				if previousUsed > 0 && !lastCheck.IsZero() {
+					sampled++
+					if used > previousUsed {
+						pulledBytes += used - previousUsed
+					}
+				}
+			}
+		}
+
+		minAvailable := minFree
+		if started {
+			minAvailable = preloadReservedDisk
+		}
+		available, message, ok := checkNodeDiskSpace(node, summary, minAvailable)
+		if !ok {
+			// the node keeps its state until the stats are back
+			if statsErr != nil {
+				logrus.Warnf("Failed to get the filesystem stats of node %s: %v", node.Name, statsErr)
+			} else {
+				logrus.Warnf("The filesystem stats of node %s are incomplete", node.Name)
+			}
+			if nodeStatus.State == cloudweavv1.ImagePreloadStatePaused {
+				pausedMessages = append(pausedMessages, fmt.Sprintf("%s: %s", node.Name, nodeStatus.Message))
+			}
+			status.Nodes[node.Name] = nodeStatus
+			continue
+		}
+
+		nodeStatus.AvailableDiskBytes = available
+		nodeStatus.Message = message
+		if message == "" {
+			if nodeStatus.State == cloudweavv1.ImagePreloadStatePaused {
+				logrus.Infof("Resume image preloading on node %s", node.Name)
+				nodeStatus.State = cloudweavv1.ImagePreloadStatePending
+				nodeStatus.PausedTime = ""
+			}
+			if err := h.setNodePaused(node, upgrade.Name, false); err != nil {
+				return err
+			}
+		} else {
+			if nodeStatus.State != cloudweavv1.ImagePreloadStatePaused {
+				logrus.Infof("Pause image preloading on node %s: %s", node.Name, message)
+				nodeStatus.PausedTime = now.UTC().Format(time.RFC3339)
+			}
+			nodeStatus.State = cloudweavv1.ImagePreloadStatePaused
+			if err := h.setNodePaused(node, upgrade.Name, true); err != nil {
+				return err
+			}
+			// the node is off the plan now, the job is created again once the node is resumed
+			if job, ok := jobs[node.Name]; ok && started {
+				propagation := metav1.DeletePropagationBackground
+				if err := h.jobClient.Delete(job.Namespace, job.Name, &metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !apierrors.IsNotFound(err) {
+					return err
+				}
+			}
+			pausedMessages = append(pausedMessages, fmt.Sprintf("%s: %s", node.Name, message))
+		}
+		status.Nodes[node.Name] = nodeStatus
+	}
+
+	// a sample is only taken if every preloading node was sampled twice, so it matches the concurrency
+	if preloading > 0 && sampled == preloading {
+		elapsed := now.Sub(lastCheck).Seconds()
+		appendThroughputSample(status, cloudweavv1.ImagePreloadThroughputSample{
+			Concurrency:    preloading,
+			BytesPerSecond: int64(float64(pulledBytes) / elapsed),
+		})
+	}
+	setImagePreloadPausedCondition(upgrade, pausedMessages)
+	return nil
+}
+
+// checkNodeDiskSpace returns the free disk space of the node and why it should be paused, if any. It's not ok if the
+// node isn't under disk pressure and its filesystem stats are missing.
+func checkNodeDiskSpace(node *corev1.Node, summary *kubeletstatsv1.Summary, minAvailable int64) (int64, string, bool) {
+	if isUnderDiskPressure(node) {
+		return 0, "node is under disk pressure", true
+	}
+	if summary == nil || summary.Node.Fs == nil || summary.Node.Fs.AvailableBytes == nil {
+		return 0, "", false
+	}
+	available := int64(*summary.Node.Fs.AvailableBytes)
+	if available < minAvailable {
+		return available, fmt.Sprintf("free disk space %s is less than %s", units.BytesSize(float64(available)), units.BytesSize(float64(minAvailable))), true
+	}
+	return available, "", true
+}
+
+func imageFsUsedBytes(summary *kubeletstatsv1.Summary) (int64, bool) {
+	if summary == nil || summary.Node.Runtime == nil || summary.Node.Runtime.ImageFs == nil || summary.Node.Runtime.ImageFs.UsedBytes == nil {
+		return 0, false
+	}
+	return int64(*summary.Node.Runtime.ImageFs.UsedBytes), true
+}
+
+func setImagePreloadPausedCondition(upgrade *cloudweavv1.Upgrade, messages []string) {
+	if len(messages) == 0 {
+		if cloudweavv1.ImagePreloadPaused.GetStatus(upgrade) != "" {
+			cloudweavv1.ImagePreloadPaused.False(upgrade)
+			cloudweavv1.ImagePreloadPaused.Reason(upgrade, "")
+			cloudweavv1.ImagePreloadPaused.Message(upgrade, "")
+		}
+		return
+	}
+	sort.Strings(messages)
+	cloudweavv1.ImagePreloadPaused.True(upgrade)
+	cloudweavv1.ImagePreloadPaused.Reason(upgrade, "InsufficientDiskSpace")
+	cloudweavv1.ImagePreloadPaused.Message(upgrade, strings.Join(messages, "; "))
+}
+
+// preloadPauseTimeoutMessage returns why the upgrade fails if any node stays paused longer than preloadPauseTimeout
+func preloadPauseTimeoutMessage(status *cloudweavv1.ImagePreloadStatus, now time.Time) string {
+	var nodeNames []string
+	for nodeName, nodeStatus := range status.Nodes {
+		if nodeStatus.State != cloudweavv1.ImagePreloadStatePaused {
+			continue
+		}
+		pausedTime, err := time.Parse(time.RFC3339, nodeStatus.PausedTime)
+		if err == nil && now.Sub(pausedTime) > preloadPauseTimeout {
+			nodeNames = append(nodeNames, nodeName)
+		}
+	}
+	if len(nodeNames) == 0 {
+		return ""
+	}
+	sort.Strings(nodeNames)
+	return fmt.Sprintf("image preloading is paused for more than %s on node %s, free up disk space and upgrade again",
+		preloadPauseTimeout, strings.Join(nodeNames, ", "))
+}
+
+func (h *preloadHandler) setNodePaused(node *corev1.Node, upgradeName string, paused bool) error {
+	_, labelled := node.Labels[preloadPausedLabel]
+	if paused == labelled {
+		return nil
+	}
+	toUpdate := node.DeepCopy()
+	if paused {
+		if toUpdate.Labels == nil {
+			toUpdate.Labels = make(map[string]string)
+		}
+		toUpdate.Labels[preloadPausedLabel] = upgradeName
+	} else {
+		delete(toUpdate.Labels, preloadPausedLabel)
+	}
+	_, err := h.nodeClient.Update(toUpdate)
+	return err
+}
+
+// resumeNodes removes the pause label of the upgrade from the nodes once the preloading is over
+func (h *preloadHandler) resumeNodes(upgradeName string) error {
+	nodes, err := h.nodeCache.List(labels.SelectorFromSet(labels.Set{preloadPausedLabel: upgradeName}))
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if err := h.setNodePaused(node, upgradeName, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isUnderDiskPressure(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeDiskPressure {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func preloadMinFreeDisk() (int64, error) {
+	upgradeConfig, err := settings.DecodeConfig[settings.UpgradeConfig](settings.UpgradeConfigSet.Get())
+	if err != nil {
+		return 0, err
+	}
+	if gb := upgradeConfig.PreloadOption.Strategy.MinFreeDiskSpaceGB; gb > 0 {
+		return int64(gb) * 1024 * 1024 * 1024, nil
+	}
+	return defaultPreloadMinFreeDisk, nil
+}
+
+// syncPreloadNodes sets the state of each node from the latest prepare job on it and returns these jobs by node name.
+// The nodes without a job keep their state, they're pending unless the adaptive strategy paused them. The paused
+// nodes keep their state until they're resumed, the jobs of the nodes paused while preloading are being deleted.
+func syncPreloadNodes(status *cloudweavv1.ImagePreloadStatus, nodes []*corev1.Node, jobs []*batchv1.Job) map[string]*batchv1.Job {
+	latestJobs := make(map[string]*batchv1.Job, len(jobs))
+	for _, job := range jobs {
+		if job.DeletionTimestamp != nil {
+			continue
+		}
+		nodeName := job.Labels[upgradeNodeLabel]
+		if latest, ok := latestJobs[nodeName]; !ok || latest.CreationTimestamp.Before(&job.CreationTimestamp) {
+			latestJobs[nodeName] = job
+		}
+	}
+
+	if status.Nodes == nil {
+		status.Nodes = make(map[string]cloudweavv1.NodeImagePreloadStatus, len(nodes))
+	}
+	for _, node := range nodes {
+		nodeStatus := status.Nodes[node.Name]
+		job, ok := latestJobs[node.Name]
+		if !ok || nodeStatus.State == cloudweavv1.ImagePreloadStatePaused {
+			if nodeStatus.State == "" {
+				nodeStatus.State = cloudweavv1.ImagePreloadStatePending
+			}
+			status.Nodes[node.Name] = nodeStatus
+			continue
+		}
+
+		nodeStatus.State = cloudweavv1.ImagePreloadStatePreloading
+		nodeStatus.Message = ""
+		for _, condition := range job.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch condition.Type {
+			case batchv1.JobComplete:
+				nodeStatus.State = cloudweavv1.ImagePreloadStatePreloaded
+			case batchv1.JobFailed:
+				nodeStatus.State = cloudweavv1.ImagePreloadStateFailed
+				nodeStatus.Message = condition.Message
+			}
+		}
+		if job.Status.StartTime != nil {
+			nodeStatus.StartTime = job.Status.StartTime.UTC().Format(time.RFC3339)
+		}
+		if job.Status.CompletionTime != nil {
+			nodeStatus.EndTime = job.Status.CompletionTime.UTC().Format(time.RFC3339)
+		}
+		if nodeStatus.Concurrency == 0 {
+			nodeStatus.Concurrency = status.Concurrency
+		}
+		status.Nodes[node.Name] = nodeStatus
+	}
+	return latestJobs
+}
+
+// appendThroughputSample records a throughput sample, dropping the oldest sample of the same concurrency if there are
+// already preloadMaxThroughputSamples of it
+func appendThroughputSample(status *cloudweavv1.ImagePreloadStatus, sample cloudweavv1.ImagePreloadThroughputSample) {
+	count := 0
+	for _, s := range status.ThroughputSamples {
+		if s.Concurrency == sample.Concurrency {
+			count++
+		}
+	}
+	samples := make([]cloudweavv1.ImagePreloadThroughputSample, 0, len(status.ThroughputSamples)+1)
+	for _, s := range status.ThroughputSamples {
+		if s.Concurrency == sample.Concurrency && count >= preloadMaxThroughputSamples {
+			count--
+			continue
+		}
+		samples = append(samples, s)
+	}
+	status.ThroughputSamples = append(samples, sample)
+}
+
+// preloadThroughputs returns the image pull throughput of each concurrency in bytes per second. It's the average of
+// the samples of that concurrency, the concurrencies with less than preloadMinThroughputSamples aren't measured yet.
+func preloadThroughputs(status *cloudweavv1.ImagePreloadStatus) map[int]float64 {
+	samples := make(map[int][]int64)
+	for _, sample := range status.ThroughputSamples {
+		samples[sample.Concurrency] = append(samples[sample.Concurrency], sample.BytesPerSecond)
+	}
+
+	throughputs := make(map[int]float64, len(samples))
+	for concurrency, bytesPerSecond := range samples {
+		if len(bytesPerSecond) < preloadMinThroughputSamples {
+			continue
+		}
+		var total int64
+		for _, sample := range bytesPerSecond {
+			total += sample
+		}
+		throughputs[concurrency] = float64(total) / float64(len(bytesPerSecond))
+	}
+	return throughputs
+}
+
+// scalePreloadConcurrency returns the next concurrency of the adaptive strategy. It waits for the throughput of the
+// current concurrency to be measured, then scales down if it dropped compared to one node lower, which happens when
+// the uplink is saturated, or scales up unless one node higher was already found slower.
+func scalePreloadConcurrency(status *cloudweavv1.ImagePreloadStatus) int {
+	current := status.Concurrency
+	throughputs := preloadThroughputs(status)
+	throughput, ok := throughputs[current]
+	if !ok {
+		return current
+	}
+	if lower, ok := throughputs[current-1]; ok && throughput < lower*preloadThroughputTolerance {
+		return current - 1
+	}
+	if current < status.MaxConcurrency {
+		if higher, ok := throughputs[current+1]; !ok || higher > throughput {
+			return current + 1
+		}
+	}
+	return current
+}
+
+// planNodeSelector returns the node selector of the plan without the pause label, the paused nodes are still to be
+// prepared before the plan completes
+func planNodeSelector(plan *upgradev1.Plan) *metav1.LabelSelector {
+	selector := plan.Spec.NodeSelector.DeepCopy()
+	if selector == nil {
+		return nil
+	}
+	var expressions []metav1.LabelSelectorRequirement
+	for _, expression := range selector.MatchExpressions {
+		if expression.Key != preloadPausedLabel {
+			expressions = append(expressions, expression)
+		}
+	}
+	selector.MatchExpressions = expressions
+	return selector
+}
+
+// excludePausedNodes keeps the plan off the nodes paused by the adaptive strategy
+func excludePausedNodes(plan *upgradev1.Plan) {
+	plan.Spec.NodeSelector.MatchExpressions = append(plan.Spec.NodeSelector.MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      preloadPausedLabel,
+		Operator: metav1.LabelSelectorOpDoesNotExist,
+	})
+}
+
+func newNodeStatsGetter(ctx context.Context, clientSet kubernetes.Interface) func(string) (*kubeletstatsv1.Summary, error) {
+	return func(nodeName string) (*kubeletstatsv1.Summary, error) {
+		body, err := clientSet.CoreV1().RESTClient().Get().
+			Resource("nodes").Name(nodeName).SubResource("proxy").Suffix("stats/summary").
+			DoRaw(ctx)
+		if err != nil {
+			return nil, err
+		}
+		summary := &kubeletstatsv1.Summary{}
+		if err := json.Unmarshal(body, summary); err != nil {
+			return nil, err
+		}
+		return summary, nil
+	}
+}
@@ -0,0 +1,290 @@
+package upgrade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	kubeletstatsv1 "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+)
+
+func TestSyncPreloadNodes(t *testing.T) {
+	start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	newJob := func(nodeName string, created time.Time, status batchv1.JobStatus) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:            map[string]string{upgradeNodeLabel: nodeName},
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Status: status,
+		}
+	}
+	startTime := metav1.NewTime(start)
+	completionTime := metav1.NewTime(start.Add(10 * time.Minute))
+
+	status := &cloudweavv1.ImagePreloadStatus{
+		Strategy:    "adaptive",
+		Concurrency: 2,
+		Nodes: map[string]cloudweavv1.NodeImagePreloadStatus{
+			"node1": {State: cloudweavv1.ImagePreloadStatePreloading, Concurrency: 1},
+			"node4": {State: cloudweavv1.ImagePreloadStatePaused, Message: "node is under disk pressure"},
+			"node5": {State: cloudweavv1.ImagePreloadStatePaused, Message: "node is under disk pressure"},
+		},
+	}
+	nodes := []*corev1.Node{
+		newNodeBuilder("node1").Build(),
+		newNodeBuilder("node2").Build(),
+		newNodeBuilder("node3").Build(),
+		newNodeBuilder("node4").Build(),
+		newNodeBuilder("node5").Build(),
+	}
+	jobs := []*batchv1.Job{
+		newJob("node1", start, batchv1.JobStatus{
+			StartTime:      &startTime,
+			CompletionTime: &completionTime,
+			Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+		}),
+		newJob("node2", start.Add(-time.Hour), batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "old failure"}},
+		}),
+		newJob("node2", start, batchv1.JobStatus{StartTime: &startTime, Active: 1}),
+		newJob("node5", start, batchv1.JobStatus{StartTime: &startTime, Active: 1}),
+	}
+	deleted := newJob("node3", start, batchv1.JobStatus{StartTime: &startTime, Active: 1})
+	deleted.DeletionTimestamp = &completionTime
+	jobs = append(jobs, deleted)
+
+	latestJobs := syncPreloadNodes(status, nodes, jobs)
+	assert.Equal(t, jobs[2], latestJobs["node2"])
+	assert.NotContains(t, latestJobs, "node3", "deleted jobs are ignored")
+	assert.Equal(t, map[string]cloudweavv1.NodeImagePreloadStatus{
+		"node1": {
+			State:       cloudweavv1.ImagePreloadStatePreloaded,
+			StartTime:   "2026-10-19T10:00:00Z",
+			EndTime:     "2026-10-19T10:10:00Z",
+			Concurrency: 1,
+		},
+		"node2": {
+			State:       cloudweavv1.ImagePreloadStatePreloading,
+			StartTime:   "2026-10-19T10:00:00Z",
+			Concurrency: 2,
+		},
+		"node3": {State: cloudweavv1.ImagePreloadStatePending},
+		"node4": {State: cloudweavv1.ImagePreloadStatePaused, Message: "node is under disk pressure"},
+		"node5": {State: cloudweavv1.ImagePreloadStatePaused, Message: "node is under disk pressure"},
+	}, status.Nodes)
+}
+
+func TestScalePreloadConcurrency(t *testing.T) {
+	mb := int64(1024 * 1024)
+	samples := func(concurrency int, bytesPerSecond ...int64) []cloudweavv1.ImagePreloadThroughputSample {
+		var result []cloudweavv1.ImagePreloadThroughputSample
+		for _, sample := range bytesPerSecond {
+			result = append(result, cloudweavv1.ImagePreloadThroughputSample{Concurrency: concurrency, BytesPerSecond: sample})
+		}
+		return result
+	}
+	join := func(sampleLists ...[]cloudweavv1.ImagePreloadThroughputSample) []cloudweavv1.ImagePreloadThroughputSample {
+		var result []cloudweavv1.ImagePreloadThroughputSample
+		for _, sampleList := range sampleLists {
+			result = append(result, sampleList...)
+		}
+		return result
+	}
+
+	var testCases = []struct {
+		name     string
+		status   *cloudweavv1.ImagePreloadStatus
+		expected int
+	}{
+		{
+			name:     "wait for enough samples at the current concurrency",
+			status:   &cloudweavv1.ImagePreloadStatus{Concurrency: 1, MaxConcurrency: 3, ThroughputSamples: samples(1, 10*mb, 10*mb)},
+			expected: 1,
+		},
+		{
+			name:     "scale up once the current concurrency is measured",
+			status:   &cloudweavv1.ImagePreloadStatus{Concurrency: 1, MaxConcurrency: 3, ThroughputSamples: samples(1, 10*mb, 12*mb, 8*mb)},
+			expected: 2,
+		},
+		{
+			name: "keep scaling up while the throughput grows",
+			status: &cloudweavv1.ImagePreloadStatus{Concurrency: 2, MaxConcurrency: 3, ThroughputSamples: join(
+				samples(1, 10*mb, 10*mb, 10*mb), samples(2, 18*mb, 20*mb, 19*mb))},
+			expected: 3,
+		},
+		{
+			name: "don't scale beyond the max concurrency",
+			status: &cloudweavv1.ImagePreloadStatus{Concurrency: 2, MaxConcurrency: 2, ThroughputSamples: join(
+				samples(1, 10*mb, 10*mb, 10*mb), samples(2, 18*mb, 20*mb, 19*mb))},
+			expected: 2,
+		},
+		{
+			name: "scale down if the throughput drops",
+			status: &cloudweavv1.ImagePreloadStatus{Concurrency: 3, MaxConcurrency: 3, ThroughputSamples: join(
+				samples(1, 10*mb, 10*mb, 10*mb), samples(2, 18*mb, 20*mb, 19*mb), samples(3, 15*mb, 14*mb, 16*mb))},
+			expected: 2,
+		},
+		{
+			name: "a single slow sample doesn't scale down",
+			status: &cloudweavv1.ImagePreloadStatus{Concurrency: 3, MaxConcurrency: 3, ThroughputSamples: join(
+				samples(1, 10*mb, 10*mb, 10*mb), samples(2, 18*mb, 20*mb, 19*mb), samples(3, 27*mb, 28*mb, 12*mb))},
+			expected: 3,
+		},
+		{
+			name: "stay if one node higher was found slower",
+			status: &cloudweavv1.ImagePreloadStatus{Concurrency: 2, MaxConcurrency: 3, ThroughputSamples: join(
+				samples(1, 10*mb, 10*mb, 10*mb), samples(2, 18*mb, 20*mb, 19*mb), samples(3, 15*mb, 14*mb, 16*mb))},
+			expected: 2,
+		},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, scalePreloadConcurrency(tc.status), "case %q", tc.name)
+	}
+}
+
+func TestAppendThroughputSample(t *testing.T) {
+	status := &cloudweavv1.ImagePreloadStatus{}
+	for i := 0; i < preloadMaxThroughputSamples; i++ {
+		appendThroughputSample(status, cloudweavv1.ImagePreloadThroughputSample{Concurrency: 1, BytesPerSecond: int64(i)})
+	}
+	appendThroughputSample(status, cloudweavv1.ImagePreloadThroughputSample{Concurrency: 2, BytesPerSecond: 100})
+	appendThroughputSample(status, cloudweavv1.ImagePreloadThroughputSample{Concurrency: 1, BytesPerSecond: 200})
+
+	require.Len(t, status.ThroughputSamples, preloadMaxThroughputSamples+1)
+	assert.Equal(t, int64(1), status.ThroughputSamples[0].BytesPerSecond, "the oldest sample of the concurrency is dropped")
+	assert.Equal(t, cloudweavv1.ImagePreloadThroughputSample{Concurrency: 2, BytesPerSecond: 100}, status.ThroughputSamples[preloadMaxThroughputSamples-1])
+	assert.Equal(t, cloudweavv1.ImagePreloadThroughputSample{Concurrency: 1, BytesPerSecond: 200}, status.ThroughputSamples[preloadMaxThroughputSamples])
+}
+
+func TestPreloadHandler_checkNodes(t *testing.T) {
+	gb := uint64(1024 * 1024 * 1024)
+	mb := uint64(1024 * 1024)
+	lastCheck := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	now := lastCheck.Add(time.Minute)
+	freeSpace := map[string]uint64{
+		"node1": 100 * gb,
+		"node2": 10 * gb,
+		"node3": 100 * gb,
+		"node4": 2 * gb,
+		"node5": 10 * gb,
+	}
+	imageFsUsed := map[string]uint64{
+		"node4": 20*gb + 600*mb,
+		"node5": 20*gb + 1200*mb,
+	}
+	nodes := []*corev1.Node{
+		newNodeBuilder("node1").WithLabel(preloadPausedLabel, testUpgradeName).Build(),
+		newNodeBuilder("node2").Build(),
+		newNodeBuilder("node3").Build(),
+		newNodeBuilder("node4").Build(),
+		newNodeBuilder("node5").Build(),
+	}
+	nodes[2].Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue}}
+	newJob := func(nodeName string) *batchv1.Job {
+		return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:      "apply-" + nodeName,
+			Namespace: sucNamespace,
+			Labels:    map[string]string{upgradeNodeLabel: nodeName},
+		}}
+	}
+	jobs := map[string]*batchv1.Job{"node4": newJob("node4"), "node5": newJob("node5")}
+	k8sclientset := k8sfake.NewSimpleClientset(nodes[0], nodes[1], nodes[2], nodes[3], nodes[4], jobs["node4"], jobs["node5"])
+
+	handler := &preloadHandler{
+		jobClient:  fakeclients.JobClient(k8sclientset.BatchV1().Jobs),
+		nodeClient: fakeclients.NodeClient(k8sclientset.CoreV1().Nodes),
+		nodeStats: func(nodeName string) (*kubeletstatsv1.Summary, error) {
+			available := freeSpace[nodeName]
+			used := imageFsUsed[nodeName]
+			return &kubeletstatsv1.Summary{Node: kubeletstatsv1.NodeStats{
+				Fs:      &kubeletstatsv1.FsStats{AvailableBytes: &available},
+				Runtime: &kubeletstatsv1.RuntimeStats{ImageFs: &kubeletstatsv1.FsStats{UsedBytes: &used}},
+			}}, nil
+		},
+	}
+	upgrade := newTestUpgradeBuilder().ImagePreloadStatus("adaptive", 2, 5).Build()
+	upgrade.Status.ImagePreload.Nodes = map[string]cloudweavv1.NodeImagePreloadStatus{
+		"node1": {State: cloudweavv1.ImagePreloadStatePaused, PausedTime: lastCheck.Format(time.RFC3339)},
+		"node2": {State: cloudweavv1.ImagePreloadStatePending},
+		"node3": {State: cloudweavv1.ImagePreloadStatePending},
+		"node4": {State: cloudweavv1.ImagePreloadStatePreloading, ImageFsUsedBytes: int64(20 * gb)},
+		"node5": {State: cloudweavv1.ImagePreloadStatePreloading, ImageFsUsedBytes: int64(20 * gb)},
+	}
+
+	require.NoError(t, handler.checkNodes(upgrade, nodes, jobs, lastCheck, now))
+	status := upgrade.Status.ImagePreload.Nodes
+	assert.Equal(t, cloudweavv1.ImagePreloadStatePending, status["node1"].State, "node with enough space is resumed")
+	assert.Empty(t, status["node1"].PausedTime)
+	assert.EqualValues(t, 100*gb, status["node1"].AvailableDiskBytes)
+	assert.Equal(t, cloudweavv1.ImagePreloadStatePaused, status["node2"].State)
+	assert.Equal(t, "free disk space 10GiB is less than 30GiB", status["node2"].Message)
+	assert.Equal(t, now.Format(time.RFC3339), status["node2"].PausedTime)
+	assert.Equal(t, cloudweavv1.ImagePreloadStatePaused, status["node3"].State)
+	assert.Equal(t, "node is under disk pressure", status["node3"].Message)
+	assert.Equal(t, cloudweavv1.ImagePreloadStatePaused, status["node4"].State, "preloading node about to fill up is paused")
+	assert.Equal(t, "free disk space 2GiB is less than 5GiB", status["node4"].Message)
+	assert.Equal(t, cloudweavv1.ImagePreloadStatePreloading, status["node5"].State, "preloading node with enough space goes on")
+	assert.EqualValues(t, imageFsUsed["node5"], status["node5"].ImageFsUsedBytes)
+
+	for nodeName, paused := range map[string]bool{"node1": false, "node2": true, "node3": true, "node4": true, "node5": false} {
+		node, err := k8sclientset.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+		require.NoError(t, err)
+		_, labelled := node.Labels[preloadPausedLabel]
+		assert.Equal(t, paused, labelled, "node %s", nodeName)
+	}
+	_, err := k8sclientset.BatchV1().Jobs(sucNamespace).Get(context.TODO(), "apply-node4", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "the job of the paused node is deleted")
+	_, err = k8sclientset.BatchV1().Jobs(sucNamespace).Get(context.TODO(), "apply-node5", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// node4 and node5 pulled 1.8GB in a minute
+	assert.Equal(t, []cloudweavv1.ImagePreloadThroughputSample{{Concurrency: 2, BytesPerSecond: int64(1800*mb) / 60}},
+		upgrade.Status.ImagePreload.ThroughputSamples)
+	assert.True(t, cloudweavv1.ImagePreloadPaused.IsTrue(upgrade))
+	assert.Equal(t, "node2: free disk space 10GiB is less than 30GiB; node3: node is under disk pressure; node4: free disk space 2GiB is less than 5GiB",
+		cloudweavv1.ImagePreloadPaused.GetMessage(upgrade))
+
+	// node5 has no previous sample after it's restarted, so no throughput is sampled
+	freeSpace["node2"], freeSpace["node4"] = 100*gb, 100*gb
+	nodes[2].Status.Conditions = nil
+	upgrade.Status.ImagePreload.Nodes["node5"] = cloudweavv1.NodeImagePreloadStatus{State: cloudweavv1.ImagePreloadStatePreloading}
+	require.NoError(t, handler.checkNodes(upgrade, nodes, jobs, now, now.Add(time.Minute)))
+	assert.Len(t, upgrade.Status.ImagePreload.ThroughputSamples, 1)
+	assert.True(t, cloudweavv1.ImagePreloadPaused.IsFalse(upgrade), "all the nodes are resumed")
+	assert.Empty(t, cloudweavv1.ImagePreloadPaused.GetMessage(upgrade))
+}
+
+func TestPreloadPauseTimeoutMessage(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	status := &cloudweavv1.ImagePreloadStatus{Nodes: map[string]cloudweavv1.NodeImagePreloadStatus{
+		"node1": {State: cloudweavv1.ImagePreloadStatePaused, PausedTime: now.Add(-30 * time.Minute).Format(time.RFC3339)},
+		"node2": {State: cloudweavv1.ImagePreloadStatePreloading},
+	}}
+	assert.Empty(t, preloadPauseTimeoutMessage(status, now))
+
+	status.Nodes["node3"] = cloudweavv1.NodeImagePreloadStatus{State: cloudweavv1.ImagePreloadStatePaused, PausedTime: now.Add(-2 * time.Hour).Format(time.RFC3339)}
+	assert.Equal(t, "image preloading is paused for more than 1h0m0s on node node3, free up disk space and upgrade again",
+		preloadPauseTimeoutMessage(status, now))
+}
+
+func TestPlanNodeSelector(t *testing.T) {
+	plan := preparePlan(newTestUpgradeBuilder().Build(), 1)
+	original := plan.Spec.NodeSelector.DeepCopy()
+
+	excludePausedNodes(plan)
+	require.Len(t, plan.Spec.NodeSelector.MatchExpressions, len(original.MatchExpressions)+1)
+	assert.Equal(t, metav1.LabelSelectorRequirement{Key: preloadPausedLabel, Operator: metav1.LabelSelectorOpDoesNotExist},
+		plan.Spec.NodeSelector.MatchExpressions[len(original.MatchExpressions)])
+	assert.Equal(t, original, planNodeSelector(plan), "paused nodes are still selected")
+}
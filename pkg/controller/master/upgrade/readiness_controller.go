@@ -0,0 +1,98 @@
+package upgrade
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/upgradehelper/readiness"
+)
+
+const (
+	readinessCheckInterval = 5 * time.Minute
+)
+
+// readinessHandler runs the readiness checks of the UpgradeReadiness objects
+type readinessHandler struct {
+	readinessClient     ctlcloudweavv1.UpgradeReadinessClient
+	readinessController ctlcloudweavv1.UpgradeReadinessController
+	versionCache        ctlcloudweavv1.VersionCache
+	caches              *readiness.Caches
+	now                 func() time.Time
+}
+
+// OnChanged runs the checks when the spec changed or the last check is older than the check interval. The status
+// update triggers the handler again, so the checks don't run more often than the interval otherwise.
+func (h *readinessHandler) OnChanged(_ string, upgradeReadiness *cloudweavv1.UpgradeReadiness) (*cloudweavv1.UpgradeReadiness, error) {
+	if upgradeReadiness == nil || upgradeReadiness.DeletionTimestamp != nil {
+		return upgradeReadiness, nil
+	}
+
+	now := h.now()
+	if upgradeReadiness.Status.ObservedGeneration == upgradeReadiness.Generation && upgradeReadiness.Status.LastCheckTime != "" {
+		if lastCheck, err := time.Parse(time.RFC3339, upgradeReadiness.Status.LastCheckTime); err == nil && now.Sub(lastCheck) < readinessCheckInterval {
+			h.readinessController.EnqueueAfter(upgradeReadiness.Namespace, upgradeReadiness.Name, readinessCheckInterval-now.Sub(lastCheck))
+			return upgradeReadiness, nil
+		}
+	}
+
+	checks, ready, err := h.check(upgradeReadiness)
+	if err != nil {
+		return upgradeReadiness, err
+	}
+
+	toUpdate := upgradeReadiness.DeepCopy()
+	toUpdate.Status.ObservedGeneration = upgradeReadiness.Generation
+	toUpdate.Status.LastCheckTime = now.UTC().Format(time.RFC3339)
+	toUpdate.Status.Ready = ready
+	toUpdate.Status.Checks = checks
+
+	h.readinessController.EnqueueAfter(upgradeReadiness.Namespace, upgradeReadiness.Name, readinessCheckInterval)
+	if reflect.DeepEqual(upgradeReadiness.Status, toUpdate.Status) {
+		return upgradeReadiness, nil
+	}
+	return h.readinessClient.UpdateStatus(toUpdate)
+}
+
+func (h *readinessHandler) check(upgradeReadiness *cloudweavv1.UpgradeReadiness) ([]cloudweavv1.ReadinessCheck, bool, error) {
+	var target *cloudweavv1.Version
+	var versionErr error
+	if upgradeReadiness.Spec.Version != "" {
+		version, err := h.versionCache.Get(upgradeReadiness.Namespace, upgradeReadiness.Spec.Version)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, false, err
+		}
+		if err != nil {
+			versionErr = fmt.Errorf("version %s is not found", upgradeReadiness.Spec.Version)
+		}
+		target = version
+	}
+
+	snapshot, err := h.caches.Snapshot(settings.ServerVersion.Get(), target)
+	if err != nil {
+		return nil, false, err
+	}
+	checks, ready := readiness.Run(snapshot, upgradeReadiness.Spec.Overrides)
+	if versionErr == nil {
+		return checks, ready, nil
+	}
+
+	// the version check can't run without the version, report it as failed
+	for i := range checks {
+		if checks[i].Name != readiness.CheckVersion {
+			continue
+		}
+		checks[i].Result = cloudweavv1.ReadinessFail
+		checks[i].Message = versionErr.Error()
+		checks[i].Remediation = "wait for the version to be synced or create it"
+		if !checks[i].Overridden {
+			ready = false
+		}
+	}
+	return checks, ready, nil
+}
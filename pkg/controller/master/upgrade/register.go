@@ -2,23 +2,31 @@ package upgrade
 
 import (
 	"context"
+	"time"
 
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 
 	"github.com/cloudweav/cloudweav/pkg/config"
 	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/scheme"
+	virtv1 "github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/typed/kubevirt.io/v1"
+	"github.com/cloudweav/cloudweav/pkg/upgradehelper/readiness"
 )
 
 const (
-	upgradeControllerName = "cloudweav-upgrade-controller"
-	planControllerName    = "cloudweav-plan-controller"
-	jobControllerName     = "cloudweav-upgrade-job-controller"
-	podControllerName     = "cloudweav-upgrade-pod-controller"
-	settingControllerName = "cloudweav-version-setting-controller"
-	vmImageControllerName = "cloudweav-upgrade-vm-image-controller"
-	secretControllerName  = "cloudweav-upgrade-secret-controller"
-	nodeControllerName    = "cloudweav-upgrade-node-controller"
+	upgradeControllerName   = "cloudweav-upgrade-controller"
+	planControllerName      = "cloudweav-plan-controller"
+	jobControllerName       = "cloudweav-upgrade-job-controller"
+	podControllerName       = "cloudweav-upgrade-pod-controller"
+	settingControllerName   = "cloudweav-version-setting-controller"
+	vmImageControllerName   = "cloudweav-upgrade-vm-image-controller"
+	secretControllerName    = "cloudweav-upgrade-secret-controller"
+	nodeControllerName      = "cloudweav-upgrade-node-controller"
+	readinessControllerName = "cloudweav-upgrade-readiness-controller"
+	historyControllerName   = "cloudweav-upgrade-history-controller"
+	canaryControllerName    = "cloudweav-upgrade-canary-controller"
+	preloadControllerName   = "cloudweav-upgrade-preload-controller"
 )
 
 func Register(ctx context.Context, management *config.Management, options config.Options) error {
@@ -42,8 +50,13 @@ func Register(ctx context.Context, management *config.Management, options config
 	machines := management.ClusterFactory.Cluster().V1beta1().Machine()
 	secrets := management.CoreFactory.Core().V1().Secret()
 	pvcs := management.CoreFactory.Core().V1().PersistentVolumeClaim()
+	deployments := management.AppsFactory.Apps().V1().Deployment()
 	lhSettings := management.LonghornFactory.Longhorn().V1beta2().Setting()
 	kubeVirt := management.VirtFactory.Kubevirt().V1().KubeVirt()
+	upgradeReadinesses := management.CloudweavFactory.Cloudweavhci().V1beta1().UpgradeReadiness()
+	managedCharts := management.RancherManagementFactory.Management().V3().ManagedChart()
+	configMaps := management.CoreFactory.Core().V1().ConfigMap()
+	upgradeHistories := management.CloudweavFactory.Cloudweavhci().V1beta1().UpgradeHistory()
 
 	virtSubsrcConfig := rest.CopyConfig(management.RestConfig)
 	virtSubsrcConfig.GroupVersion = &schema.GroupVersion{Group: "subresources.kubevirt.io", Version: "v1"}
@@ -53,36 +66,85 @@ func Register(ctx context.Context, management *config.Management, options config
 	if err != nil {
 		return err
 	}
+	virtv1Client, err := virtv1.NewForConfig(rest.CopyConfig(management.RestConfig))
+	if err != nil {
+		return err
+	}
 
 	controller := &upgradeHandler{
-		ctx:               ctx,
-		jobClient:         jobs,
-		jobCache:          jobs.Cache(),
-		nodeCache:         nodes.Cache(),
+		ctx:                ctx,
+		jobClient:          jobs,
+		jobCache:           jobs.Cache(),
+		nodeCache:          nodes.Cache(),
+		namespace:          options.Namespace,
+		upgradeClient:      upgrades,
+		upgradeCache:       upgrades.Cache(),
+		upgradeController:  upgrades,
+		upgradeLogClient:   upgradeLogs,
+		upgradeLogCache:    upgradeLogs.Cache(),
+		versionCache:       versions.Cache(),
+		planClient:         plans,
+		planCache:          plans.Cache(),
+		vmImageClient:      vmImages,
+		vmImageCache:       vmImages.Cache(),
+		vmClient:           vms,
+		vmCache:            vms.Cache(),
+		serviceClient:      services,
+		pvcClient:          pvcs,
+		deploymentClient:   deployments,
+		pdbClient:          management.ClientSet.PolicyV1(),
+		clusterClient:      clusters,
+		clusterCache:       clusters.Cache(),
+		lhSettingClient:    lhSettings,
+		lhSettingCache:     lhSettings.Cache(),
+		kubeVirtCache:      kubeVirt.Cache(),
+		managedChartClient: managedCharts,
+		managedChartCache:  managedCharts.Cache(),
+		configMapClient:    configMaps,
+		configMapCache:     configMaps.Cache(),
+		vmRestClient:       virtSubresourceClient,
+	}
+	upgrades.OnChange(ctx, upgradeControllerName, controller.OnChanged)
+	upgrades.OnRemove(ctx, upgradeControllerName, controller.OnRemove)
+
+	historyHandler := &historyHandler{
+		historyClient: upgradeHistories,
+		historyCache:  upgradeHistories.Cache(),
+		vmimCache:     management.VirtFactory.Kubevirt().V1().VirtualMachineInstanceMigration().Cache(),
+		now:           time.Now,
+	}
+	upgrades.OnChange(ctx, historyControllerName, historyHandler.OnChanged)
+	upgrades.OnRemove(ctx, historyControllerName, historyHandler.OnRemove)
+
+	canaryHandler := &canaryHandler{
+		namespace:             options.Namespace,
+		upgradeClient:         upgrades,
+		upgradeController:     upgrades,
+		nodeCache:             nodes.Cache(),
+		volumeCache:           management.LonghornFactory.Longhorn().V1beta2().Volume().Cache(),
+		vmiCache:              management.VirtFactory.Kubevirt().V1().VirtualMachineInstance().Cache(),
+		vmimClient:            management.VirtFactory.Kubevirt().V1().VirtualMachineInstanceMigration(),
+		eventClient:           management.CoreFactory.Core().V1().Event(),
+		virtRestClient:        virtv1Client.RESTClient(),
+		virtSubresourceClient: virtSubresourceClient,
+		now:                   time.Now,
+	}
+	upgrades.OnChange(ctx, canaryControllerName, canaryHandler.OnChanged)
+
+	preloadHandler := &preloadHandler{
 		namespace:         options.Namespace,
 		upgradeClient:     upgrades,
-		upgradeCache:      upgrades.Cache(),
 		upgradeController: upgrades,
-		upgradeLogClient:  upgradeLogs,
-		upgradeLogCache:   upgradeLogs.Cache(),
-		versionCache:      versions.Cache(),
 		planClient:        plans,
 		planCache:         plans.Cache(),
-		vmImageClient:     vmImages,
-		vmImageCache:      vmImages.Cache(),
-		vmClient:          vms,
-		vmCache:           vms.Cache(),
-		serviceClient:     services,
-		pvcClient:         pvcs,
-		clusterClient:     clusters,
-		clusterCache:      clusters.Cache(),
-		lhSettingClient:   lhSettings,
-		lhSettingCache:    lhSettings.Cache(),
-		kubeVirtCache:     kubeVirt.Cache(),
-		vmRestClient:      virtSubresourceClient,
+		jobClient:         jobs,
+		jobCache:          jobs.Cache(),
+		nodeClient:        nodes,
+		nodeCache:         nodes.Cache(),
+		nodeStats:         newNodeStatsGetter(ctx, management.ClientSet),
+		now:               time.Now,
 	}
-	upgrades.OnChange(ctx, upgradeControllerName, controller.OnChanged)
-	upgrades.OnRemove(ctx, upgradeControllerName, controller.OnRemove)
+	upgrades.OnChange(ctx, preloadControllerName, preloadHandler.OnChanged)
 
 	planHandler := &planHandler{
 		namespace:     options.Namespace,
@@ -121,14 +183,18 @@ func Register(ctx context.Context, management *config.Management, options config
 	vmImages.OnChange(ctx, vmImageControllerName, vmImageHandler.OnChanged)
 
 	secretHandler := &secretHandler{
-		namespace:     options.Namespace,
-		upgradeClient: upgrades,
-		upgradeCache:  upgrades.Cache(),
-		jobClient:     jobs,
-		jobCache:      jobs.Cache(),
-		machineCache:  machines.Cache(),
+		namespace:        options.Namespace,
+		upgradeClient:    upgrades,
+		upgradeCache:     upgrades.Cache(),
+		jobClient:        jobs,
+		jobCache:         jobs.Cache(),
+		machineCache:     machines.Cache(),
+		secretController: secrets,
+		secretCache:      secrets.Cache(),
+		now:              time.Now,
 	}
 	secrets.OnChange(ctx, secretControllerName, secretHandler.OnChanged)
+	relatedresource.Watch(ctx, "watch-upgrade-plan-secrets", secretHandler.ResolveUpgrade, secrets, upgrades)
 
 	nodeHandler := &nodeHandler{
 		namespace:     options.Namespace,
@@ -140,6 +206,23 @@ func Register(ctx context.Context, management *config.Management, options config
 	}
 	nodes.OnChange(ctx, nodeControllerName, nodeHandler.OnChanged)
 
+	readinessHandler := &readinessHandler{
+		readinessClient:     upgradeReadinesses,
+		readinessController: upgradeReadinesses,
+		versionCache:        versions.Cache(),
+		caches: &readiness.Caches{
+			Nodes:         nodes.Cache(),
+			Volumes:       management.LonghornFactory.Longhorn().V1beta2().Volume().Cache(),
+			LonghornNodes: management.LonghornFactory.Longhorn().V1beta2().Node().Cache(),
+			VMIs:          management.VirtFactory.Kubevirt().V1().VirtualMachineInstance().Cache(),
+			Jobs:          jobs.Cache(),
+			Addons:        management.CloudweavFactory.Cloudweavhci().V1beta1().Addon().Cache(),
+			Secrets:       secrets.Cache(),
+		},
+		now: time.Now,
+	}
+	upgradeReadinesses.OnChange(ctx, readinessControllerName, readinessHandler.OnChanged)
+
 	versionSyncer := newVersionSyncer(ctx, options.Namespace, versions, nodes, namespaces)
 
 	settingHandler := settingHandler{
@@ -0,0 +1,260 @@
+package repoinfo
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// ReleaseFileName is the release manifest at the root of the ISO
+	ReleaseFileName = "cloudweav-release.yaml"
+
+	isoSectorSize            = 2048
+	isoFirstDescriptorSector = 16
+	isoMaxDescriptors        = 32
+	isoRootRecordOffset      = 156
+	isoDirectoryFlag         = 0x02
+	maxReleaseFileSize       = 1 << 20
+
+	volumeDescriptorPrimary    = 1
+	volumeDescriptorTerminator = 255
+)
+
+var (
+	ErrReleaseNotFound     = errors.New(ReleaseFileName + " is not found in the ISO")
+	ErrNoTrustedKeys       = errors.New("no trusted key is configured to verify the ISO")
+	ErrSignatureNotTrusted = errors.New("the signature of the ISO isn't signed by a trusted key")
+)
+
+type isoRecord struct {
+	name      string
+	extent    int64
+	size      int64
+	directory bool
+}
+
+// isoReader reads an ISO 9660 image sequentially, so the image can be read while it's being uploaded
+type isoReader struct {
+	r   io.Reader
+	pos int64
+}
+
+func (i *isoReader) readAt(offset, length int64) ([]byte, error) {
+	if offset < i.pos {
+		return nil, fmt.Errorf("offset %d is already read", offset)
+	}
+	if _, err := io.CopyN(io.Discard, i.r, offset-i.pos); err != nil {
+		return nil, err
+	}
+	data := make([]byte, length)
+	n, err := io.ReadFull(i.r, data)
+	i.pos = offset + int64(n)
+	return data, err
+}
+
+// ReadISORelease reads the release manifest at the root of the ISO. The ISO is read sequentially and only until the
+// manifest is found, the caller reads the rest of r if needed.
+func ReadISORelease(r io.Reader) (*CloudweavRelease, error) {
+	iso := &isoReader{r: r}
+
+	root, err := iso.rootRecord()
+	if err != nil {
+		return nil, err
+	}
+	records, err := iso.readDirectory(root)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if record.directory || !matchISOName(record.name, ReleaseFileName) {
+			continue
+		}
+		if record.size > maxReleaseFileSize {
+			return nil, fmt.Errorf("%s is too large: %d bytes", ReleaseFileName, record.size)
+		}
+		data, err := iso.readAt(record.extent*isoSectorSize, record.size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", ReleaseFileName, err)
+		}
+		var release CloudweavRelease
+		if err := yaml.Unmarshal(data, &release); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", ReleaseFileName, err)
+		}
+		if release.Cloudweav == "" {
+			return nil, fmt.Errorf("%s doesn't have the version", ReleaseFileName)
+		}
+		return &release, nil
+	}
+	return nil, ErrReleaseNotFound
+}
+
+// rootRecord returns the root directory of the primary volume descriptor
+func (i *isoReader) rootRecord() (*isoRecord, error) {
+	for sector := int64(isoFirstDescriptorSector); sector < isoFirstDescriptorSector+isoMaxDescriptors; sector++ {
+		descriptor, err := i.readAt(sector*isoSectorSize, isoSectorSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the volume descriptors: %w", err)
+		}
+		if string(descriptor[1:6]) != "CD001" {
+			return nil, errors.New("not an ISO 9660 image")
+		}
+		switch descriptor[0] {
+		case volumeDescriptorPrimary:
+			record, _, err := parseISORecord(descriptor[isoRootRecordOffset:])
+			return record, err
+		case volumeDescriptorTerminator:
+			return nil, errors.New("primary volume descriptor is not found")
+		}
+	}
+	return nil, errors.New("primary volume descriptor is not found")
+}
+
+func (i *isoReader) readDirectory(directory *isoRecord) ([]*isoRecord, error) {
+	data, err := i.readAt(directory.extent*isoSectorSize, directory.size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the root directory: %w", err)
+	}
+
+	var records []*isoRecord
+	for offset := 0; offset < len(data); {
+		// records don't cross sectors, a zero length pads to the next sector
+		if data[offset] == 0 {
+			offset = (offset/isoSectorSize + 1) * isoSectorSize
+			continue
+		}
+		record, length, err := parseISORecord(data[offset:])
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+		offset += length
+	}
+	return records, nil
+}
+
+// parseISORecord parses a directory record and returns it with its length. The Rock Ridge name is preferred to the
+// ISO 9660 name if any.
+func parseISORecord(data []byte) (*isoRecord, int, error) {
+	if len(data) < 34 || int(data[0]) > len(data) || data[0] < 34 {
+		return nil, 0, errors.New("invalid directory record")
+	}
+	length := int(data[0])
+	nameLength := int(data[32])
+	if 33+nameLength > length {
+		return nil, 0, errors.New("invalid directory record")
+	}
+
+	record := &isoRecord{
+		name:      string(data[33 : 33+nameLength]),
+		extent:    int64(binary.LittleEndian.Uint32(data[2:6])),
+		size:      int64(binary.LittleEndian.Uint32(data[10:14])),
+		directory: data[25]&isoDirectoryFlag != 0,
+	}
+
+	systemUse := 33 + nameLength
+	if nameLength%2 == 0 {
+		systemUse++
+	}
+	if systemUse < length {
+		if name := rockRidgeName(data[systemUse:length]); name != "" {
+			record.name = name
+		}
+	}
+	return record, length, nil
+}
+
+// rockRidgeName returns the name of the NM entries in the system use area
+func rockRidgeName(data []byte) string {
+	var name bytes.Buffer
+	for offset := 0; offset+4 <= len(data); {
+		length := int(data[offset+2])
+		if length < 4 || offset+length > len(data) {
+			break
+		}
+		if string(data[offset:offset+2]) == "NM" && length > 5 {
+			name.Write(data[offset+5 : offset+length])
+		}
+		offset += length
+	}
+	return name.String()
+}
+
+// matchISOName matches the file name with the ISO 9660 name, which is upper case, may replace '-' with '_' and ends
+// with the file version
+func matchISOName(isoName, fileName string) bool {
+	if i := strings.LastIndex(isoName, ";"); i >= 0 {
+		isoName = isoName[:i]
+	}
+	normalize := func(name string) string {
+		return strings.ReplaceAll(strings.ToLower(name), "-", "_")
+	}
+	return normalize(isoName) == normalize(fileName)
+}
+
+// ParseTrustedKeys parses the PEM encoded Ed25519 public keys which sign the upgrade ISOs
+func ParseTrustedKeys(data string) ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the public key: %w", err)
+		}
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key %T isn't an Ed25519 key", key)
+		}
+		keys = append(keys, publicKey)
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return nil, errors.New("trusted keys must be PEM encoded public keys")
+	}
+	return keys, nil
+}
+
+// VerifyISOSignature verifies the base64 encoded Ed25519ph signature of the ISO, which signs the SHA-512 checksum of
+// the ISO, against the trusted keys
+func VerifyISOSignature(trustedKeys, checksum, signature string) error {
+	keys, err := ParseTrustedKeys(trustedKeys)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return ErrNoTrustedKeys
+	}
+
+	digest, err := hex.DecodeString(checksum)
+	if err != nil || len(digest) != sha512.Size {
+		return fmt.Errorf("invalid SHA-512 checksum %s", checksum)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return fmt.Errorf("failed to decode the signature: %w", err)
+	}
+
+	for _, key := range keys {
+		if ed25519.VerifyWithOptions(key, digest, sig, &ed25519.Options{Hash: crypto.SHA512}) == nil {
+			return nil
+		}
+	}
+	return ErrSignatureNotTrusted
+}
@@ -0,0 +1,190 @@
+package repoinfo
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRelease = `cloudweav: v1.5.0
+os: Cloudweav v1.5.0
+kubernetes: v1.31.4+rke2r1
+minUpgradableVersion: v1.4.0
+`
+
+func newTestISORecord(name string, extent, size uint32, directory bool, rockRidgeName string) []byte {
+	var systemUse []byte
+	if rockRidgeName != "" {
+		systemUse = append([]byte{'N', 'M', byte(5 + len(rockRidgeName)), 1, 0}, rockRidgeName...)
+	}
+	padding := 0
+	if len(name)%2 == 0 {
+		padding = 1
+	}
+	record := make([]byte, 33+len(name)+padding+len(systemUse))
+	record[0] = byte(len(record))
+	binary.LittleEndian.PutUint32(record[2:6], extent)
+	binary.LittleEndian.PutUint32(record[10:14], size)
+	if directory {
+		record[25] = isoDirectoryFlag
+	}
+	record[32] = byte(len(name))
+	copy(record[33:], name)
+	copy(record[33+len(name)+padding:], systemUse)
+	return record
+}
+
+// newTestISO builds an image with the primary volume descriptor at sector 16, the terminator at sector 17, the root
+// directory at sector 18 and the files from sector 19
+func newTestISO(files map[string]string, rockRidge bool) []byte {
+	const rootSector = 18
+	sector := func(i int) int { return i * isoSectorSize }
+
+	var directory bytes.Buffer
+	directory.Write(newTestISORecord("\x00", rootSector, isoSectorSize, true, ""))
+	directory.Write(newTestISORecord("\x01", rootSector, isoSectorSize, true, ""))
+	var data bytes.Buffer
+	next := rootSector + 1
+	for name, content := range files {
+		isoName := name
+		rockRidgeName := ""
+		if rockRidge {
+			isoName = "CLOUDWEA.YAM;1"
+			rockRidgeName = name
+		}
+		directory.Write(newTestISORecord(isoName, uint32(next), uint32(len(content)), false, rockRidgeName))
+		block := make([]byte, (len(content)/isoSectorSize+1)*isoSectorSize)
+		copy(block, content)
+		data.Write(block)
+		next += len(block) / isoSectorSize
+	}
+
+	image := make([]byte, sector(rootSector+1))
+	primary := image[sector(16):]
+	primary[0] = volumeDescriptorPrimary
+	copy(primary[1:6], "CD001")
+	copy(primary[isoRootRecordOffset:], newTestISORecord("\x00", rootSector, isoSectorSize, true, ""))
+	terminator := image[sector(17):]
+	terminator[0] = volumeDescriptorTerminator
+	copy(terminator[1:6], "CD001")
+	copy(image[sector(rootSector):], directory.Bytes())
+	return append(image, data.Bytes()...)
+}
+
+func TestReadISORelease(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		iso       []byte
+		expectErr bool
+	}{
+		{
+			name: "ISO 9660 name",
+			iso:  newTestISO(map[string]string{"CLOUDWEAV_RELEASE.YAML;1": testRelease}, false),
+		},
+		{
+			name: "Rock Ridge name",
+			iso:  newTestISO(map[string]string{ReleaseFileName: testRelease}, true),
+		},
+		{
+			name:      "no release file",
+			iso:       newTestISO(map[string]string{"README.TXT;1": "hello"}, false),
+			expectErr: true,
+		},
+		{
+			name:      "release without version",
+			iso:       newTestISO(map[string]string{"CLOUDWEAV_RELEASE.YAML;1": "os: Cloudweav\n"}, false),
+			expectErr: true,
+		},
+		{
+			name:      "not an ISO",
+			iso:       make([]byte, 20*isoSectorSize),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		release, err := ReadISORelease(bytes.NewReader(tc.iso))
+		if tc.expectErr {
+			assert.Error(t, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, "v1.5.0", release.Cloudweav, tc.name)
+		assert.Equal(t, "v1.4.0", release.MinUpgradableVersion, tc.name)
+	}
+}
+
+func newTestTrustedKey(t *testing.T) (string, ed25519.PrivateKey) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), privateKey
+}
+
+func TestVerifyISOSignature(t *testing.T) {
+	trustedKey, privateKey := newTestTrustedKey(t)
+	otherKey, otherPrivateKey := newTestTrustedKey(t)
+
+	digest := sha512.Sum512(newTestISO(map[string]string{ReleaseFileName: testRelease}, true))
+	checksum := hex.EncodeToString(digest[:])
+	sign := func(key ed25519.PrivateKey) string {
+		sig, err := key.Sign(nil, digest[:], &ed25519.Options{Hash: crypto.SHA512})
+		require.NoError(t, err)
+		return base64.StdEncoding.EncodeToString(sig)
+	}
+
+	var testCases = []struct {
+		name        string
+		trustedKeys string
+		signature   string
+		expectErr   error
+	}{
+		{
+			name:        "signed by the trusted key",
+			trustedKeys: trustedKey,
+			signature:   sign(privateKey),
+		},
+		{
+			name:        "signed by one of the trusted keys",
+			trustedKeys: otherKey + trustedKey,
+			signature:   sign(privateKey),
+		},
+		{
+			name:        "signed by an untrusted key",
+			trustedKeys: trustedKey,
+			signature:   sign(otherPrivateKey),
+			expectErr:   ErrSignatureNotTrusted,
+		},
+		{
+			name:        "no trusted keys",
+			trustedKeys: "",
+			signature:   sign(privateKey),
+			expectErr:   ErrNoTrustedKeys,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := VerifyISOSignature(tc.trustedKeys, checksum, tc.signature)
+		if tc.expectErr != nil {
+			assert.ErrorIs(t, err, tc.expectErr, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+	}
+
+	assert.Error(t, VerifyISOSignature(trustedKey, checksum, "not a signature"), "invalid signature")
+	_, err := ParseTrustedKeys("not a key")
+	assert.Error(t, err, "invalid trusted keys")
+}
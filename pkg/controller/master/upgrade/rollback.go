@@ -0,0 +1,280 @@
+package upgrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	mgmtv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/wrangler/v3/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+const (
+	managedChartNamespace = "fleet-local"
+
+	// the cluster repo serves the charts of the managed charts, it's restored with them
+	clusterRepoNamespace      = "cattle-system"
+	clusterRepoDeploymentName = "cloudweav-cluster-repo"
+	clusterRepoSnapshotKey    = "deployment." + clusterRepoDeploymentName
+
+	nodeStateSkipped = "Skipped"
+
+	// rollbackCheckInterval is how often the rollback checks whether the restored system services are ready, they're
+	// listed as a manual action if they aren't ready in rollbackTimeout
+	rollbackCheckInterval = 30 * time.Second
+	rollbackTimeout       = 30 * time.Minute
+)
+
+// snapshotSystemServices saves the specs of the managed charts and the images of the cluster repo before the system
+// services are upgraded, so they can be restored if the system services fail to upgrade
+func (h *upgradeHandler) snapshotSystemServices(upgrade *cloudweavv1.Upgrade) error {
+	managedCharts, err := h.managedChartCache.List(managedChartNamespace, labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name.SafeConcatName(upgrade.Name, "rollback"),
+			Namespace: upgradeNamespace,
+			Labels: map[string]string{
+				cloudweavUpgradeLabel: upgrade.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				upgradeReference(upgrade),
+			},
+		},
+		Data: make(map[string]string, len(managedCharts)+1),
+	}
+	for _, managedChart := range managedCharts {
+		spec, err := json.Marshal(managedChart.Spec)
+		if err != nil {
+			return err
+		}
+		configMap.Data[managedChart.Name] = string(spec)
+	}
+
+	clusterRepo, err := h.deploymentClient.Get(clusterRepoNamespace, clusterRepoDeploymentName, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		images, err := json.Marshal(containerImages(clusterRepo.Spec.Template.Spec.Containers))
+		if err != nil {
+			return err
+		}
+		configMap.Data[clusterRepoSnapshotKey] = string(images)
+	case !apierrors.IsNotFound(err):
+		return err
+	}
+
+	if _, err := h.configMapClient.Create(configMap); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	upgrade.Status.RollbackSnapshot = configMap.Name
+	return nil
+}
+
+// rollback rolls back the phases of the failed upgrade which are safe to roll back and records the result in the
+// status. The system services are restored from the snapshot if they failed to upgrade, and the nodes which haven't
+// started upgrading are skipped. Everything else is listed as a manual action. The rollback is RollingBack until the
+// restored system services are ready, it's called again until then and returns an error to retry a failed restore.
+func (h *upgradeHandler) rollback(upgrade *cloudweavv1.Upgrade) error {
+	status := &cloudweavv1.UpgradeRollbackStatus{
+		State: cloudweavv1.RollbackStateRolledBack,
+		Time:  time.Now().UTC().Format(time.RFC3339),
+	}
+	if upgrade.Status.Rollback != nil && upgrade.Status.Rollback.Time != "" {
+		status.Time = upgrade.Status.Rollback.Time
+	}
+
+	switch {
+	case cloudweavv1.SystemServicesUpgraded.IsFalse(upgrade):
+		if err := h.rollbackSystemServices(upgrade, status); err != nil {
+			return err
+		}
+	case cloudweavv1.SystemServicesUpgraded.IsTrue(upgrade):
+		status.ManualActions = append(status.ManualActions, "The system services are upgraded and are not rolled back")
+	}
+
+	if cloudweavv1.NodesUpgraded.GetStatus(upgrade) != "" {
+		rollbackNodes(upgrade, status)
+	}
+
+	if status.State != cloudweavv1.RollbackStateRollingBack && len(status.ManualActions) > 0 {
+		status.State = cloudweavv1.RollbackStateManualActionRequired
+	}
+	upgrade.Status.Rollback = status
+	return nil
+}
+
+// rollbackSystemServices restores the cluster repo and then the managed charts from the snapshot. The rollback is
+// RollingBack until they are ready or the rollback times out.
+func (h *upgradeHandler) rollbackSystemServices(upgrade *cloudweavv1.Upgrade, status *cloudweavv1.UpgradeRollbackStatus) error {
+	if upgrade.Status.RollbackSnapshot == "" {
+		status.ManualActions = append(status.ManualActions, "No snapshot of the system services is found, restore them manually")
+		return nil
+	}
+
+	snapshot, err := h.configMapCache.Get(upgradeNamespace, upgrade.Status.RollbackSnapshot)
+	if apierrors.IsNotFound(err) {
+		status.ManualActions = append(status.ManualActions, fmt.Sprintf("The snapshot %s of the system services is not found, restore them manually", upgrade.Status.RollbackSnapshot))
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get the snapshot %s of the system services: %w", upgrade.Status.RollbackSnapshot, err)
+	}
+
+	var pending []string
+	if data, ok := snapshot.Data[clusterRepoSnapshotKey]; ok {
+		ready, err := h.restoreClusterRepo(data)
+		if err != nil {
+			return fmt.Errorf("failed to roll back the cluster repo: %w", err)
+		}
+		if !ready {
+			if isRollbackTimedOut(status.Time) {
+				status.ManualActions = append(status.ManualActions, fmt.Sprintf("The cluster repo %s is not ready after it's rolled back, restore the managed charts manually", clusterRepoDeploymentName))
+				return nil
+			}
+			// the charts are restored once the cluster repo serves them
+			status.State = cloudweavv1.RollbackStateRollingBack
+			return nil
+		}
+		status.RolledBack = append(status.RolledBack, "cluster repo "+clusterRepoDeploymentName)
+	}
+
+	chartNames := make([]string, 0, len(snapshot.Data))
+	for chartName := range snapshot.Data {
+		if chartName != clusterRepoSnapshotKey {
+			chartNames = append(chartNames, chartName)
+		}
+	}
+	sort.Strings(chartNames)
+
+	for _, chartName := range chartNames {
+		ready, err := h.restoreManagedChart(chartName, snapshot.Data[chartName])
+		switch {
+		case apierrors.IsNotFound(err):
+			status.ManualActions = append(status.ManualActions, fmt.Sprintf("Managed chart %s is not found, restore it manually", chartName))
+		case err != nil:
+			return fmt.Errorf("failed to roll back managed chart %s: %w", chartName, err)
+		case ready:
+			status.RolledBack = append(status.RolledBack, fmt.Sprintf("managed chart %s", chartName))
+		default:
+			pending = append(pending, chartName)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if isRollbackTimedOut(status.Time) {
+		for _, chartName := range pending {
+			status.ManualActions = append(status.ManualActions, fmt.Sprintf("Managed chart %s is not ready after it's rolled back, check its bundle", chartName))
+		}
+		return nil
+	}
+	status.State = cloudweavv1.RollbackStateRollingBack
+	return nil
+}
+
+// restoreClusterRepo restores the images of the cluster repo and returns whether it's rolled out
+func (h *upgradeHandler) restoreClusterRepo(data string) (bool, error) {
+	var images map[string]string
+	if err := json.Unmarshal([]byte(data), &images); err != nil {
+		return false, err
+	}
+
+	deployment, err := h.deploymentClient.Get(clusterRepoNamespace, clusterRepoDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	toUpdate := deployment.DeepCopy()
+	for i, container := range toUpdate.Spec.Template.Spec.Containers {
+		if image, ok := images[container.Name]; ok {
+			toUpdate.Spec.Template.Spec.Containers[i].Image = image
+		}
+	}
+	if !reflect.DeepEqual(deployment.Spec, toUpdate.Spec) {
+		_, err := h.deploymentClient.Update(toUpdate)
+		return false, err
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas && deployment.Status.AvailableReplicas == replicas, nil
+}
+
+// restoreManagedChart restores the spec of the managed chart and returns whether its bundle is ready
+func (h *upgradeHandler) restoreManagedChart(chartName, data string) (bool, error) {
+	var spec mgmtv3.ManagedChartSpec
+	if err := json.Unmarshal([]byte(data), &spec); err != nil {
+		return false, err
+	}
+
+	managedChart, err := h.managedChartCache.Get(managedChartNamespace, chartName)
+	if err != nil {
+		return false, err
+	}
+
+	if !reflect.DeepEqual(managedChart.Spec, spec) {
+		toUpdate := managedChart.DeepCopy()
+		toUpdate.Spec = spec
+		_, err = h.managedChartClient.Update(toUpdate)
+		return false, err
+	}
+
+	summary := managedChart.Status.Summary
+	return summary.DesiredReady > 0 && summary.DesiredReady == summary.Ready && managedChart.Status.Unavailable == 0, nil
+}
+
+func isRollbackTimedOut(started string) bool {
+	startTime, err := time.Parse(time.RFC3339, started)
+	return err == nil && time.Since(startTime) > rollbackTimeout
+}
+
+func containerImages(containers []corev1.Container) map[string]string {
+	images := make(map[string]string, len(containers))
+	for _, container := range containers {
+		images[container.Name] = container.Image
+	}
+	return images
+}
+
+// rollbackNodes skips the nodes which haven't started upgrading, the other nodes can't be rolled back
+func rollbackNodes(upgrade *cloudweavv1.Upgrade, status *cloudweavv1.UpgradeRollbackStatus) {
+	nodeNames := make([]string, 0, len(upgrade.Status.NodeStatuses))
+	for nodeName := range upgrade.Status.NodeStatuses {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+
+	for _, nodeName := range nodeNames {
+		nodeStatus := upgrade.Status.NodeStatuses[nodeName]
+		switch {
+		case isNodeWaitingToUpgrade(nodeStatus.State):
+			upgrade.Status.NodeStatuses[nodeName] = cloudweavv1.NodeUpgradeStatus{
+				State:   nodeStateSkipped,
+				Message: "The upgrade failed before the node started upgrading",
+			}
+			status.SkippedNodes = append(status.SkippedNodes, nodeName)
+		case nodeStatus.State == nodeStateSkipped:
+			status.SkippedNodes = append(status.SkippedNodes, nodeName)
+		case nodeStatus.State == StateSucceeded:
+			status.ManualActions = append(status.ManualActions, fmt.Sprintf("Node %s is upgraded and can't be rolled back", nodeName))
+		case nodeStatus.State == StateFailed:
+			status.ManualActions = append(status.ManualActions, fmt.Sprintf("Node %s failed to upgrade, check the node and its upgrade job", nodeName))
+		default:
+			status.ManualActions = append(status.ManualActions, fmt.Sprintf("Node %s is in state %q, check the node and its upgrade job", nodeName, nodeStatus.State))
+		}
+	}
+}
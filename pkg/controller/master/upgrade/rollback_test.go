@@ -0,0 +1,158 @@
+package upgrade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mgmtv3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/fake"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+)
+
+func newTestManagedChart(name, version string) *mgmtv3.ManagedChart {
+	return &mgmtv3.ManagedChart{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: managedChartNamespace},
+		Spec:       mgmtv3.ManagedChartSpec{Chart: name, Version: version},
+	}
+}
+
+func newTestClusterRepo(image string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterRepoDeploymentName, Namespace: clusterRepoNamespace},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "repo", Image: image}}},
+			},
+		},
+	}
+}
+
+func TestRollbackSystemServices(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newTestManagedChart("cloudweav", "1.0.0"), newTestManagedChart("cloudweav-crd", "1.0.0"))
+	k8sclientset := k8sfake.NewSimpleClientset(newTestClusterRepo("cloudweav-cluster-repo:v1.0.0"))
+	handler := &upgradeHandler{
+		managedChartClient: fakeclients.ManagedChartClient(clientset.ManagementV3().ManagedCharts),
+		managedChartCache:  fakeclients.ManagedChartCache(clientset.ManagementV3().ManagedCharts),
+		configMapClient:    fakeclients.ConfigmapClient(k8sclientset.CoreV1().ConfigMaps),
+		configMapCache:     fakeclients.ConfigmapCache(k8sclientset.CoreV1().ConfigMaps),
+		deploymentClient:   fakeclients.DeploymentClient(k8sclientset.AppsV1().Deployments),
+	}
+	managedCharts := clientset.ManagementV3().ManagedCharts(managedChartNamespace)
+	deployments := k8sclientset.AppsV1().Deployments(clusterRepoNamespace)
+
+	upgrade := newTestUpgradeBuilder().Build()
+	upgrade.Spec.RollbackPolicy = cloudweavv1.RollbackPolicyAuto
+	require.NoError(t, handler.snapshotSystemServices(upgrade))
+	assert.NotEmpty(t, upgrade.Status.RollbackSnapshot)
+
+	// the system services upgrade bumps the cluster repo and the charts and fails
+	_, err := deployments.Update(context.TODO(), newTestClusterRepo("cloudweav-cluster-repo:v1.1.0"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	for _, chartName := range []string{"cloudweav", "cloudweav-crd"} {
+		managedChart, err := managedCharts.Get(context.TODO(), chartName, metav1.GetOptions{})
+		require.NoError(t, err)
+		managedChart.Spec.Version = "1.1.0"
+		_, err = managedCharts.Update(context.TODO(), managedChart, metav1.UpdateOptions{})
+		require.NoError(t, err)
+	}
+	setHelmChartUpgradeStatus(upgrade, corev1.ConditionFalse, "", "job failed")
+
+	// the cluster repo is restored first
+	require.NoError(t, handler.rollback(upgrade))
+	assert.Equal(t, cloudweavv1.RollbackStateRollingBack, upgrade.Status.Rollback.State)
+	clusterRepo, err := deployments.Get(context.TODO(), clusterRepoDeploymentName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "cloudweav-cluster-repo:v1.0.0", clusterRepo.Spec.Template.Spec.Containers[0].Image)
+	managedChart, err := managedCharts.Get(context.TODO(), "cloudweav", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", managedChart.Spec.Version, "the charts wait for the cluster repo")
+
+	// the charts are restored once the cluster repo is rolled out
+	clusterRepo.Status = appsv1.DeploymentStatus{UpdatedReplicas: 1, AvailableReplicas: 1}
+	_, err = deployments.UpdateStatus(context.TODO(), clusterRepo, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, handler.rollback(upgrade))
+	assert.Equal(t, cloudweavv1.RollbackStateRollingBack, upgrade.Status.Rollback.State)
+
+	// the rollback is done once the charts are ready
+	for _, chartName := range []string{"cloudweav", "cloudweav-crd"} {
+		managedChart, err := managedCharts.Get(context.TODO(), chartName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", managedChart.Spec.Version, chartName)
+		managedChart.Status.Summary.DesiredReady = 1
+		managedChart.Status.Summary.Ready = 1
+		_, err = managedCharts.UpdateStatus(context.TODO(), managedChart, metav1.UpdateOptions{})
+		require.NoError(t, err)
+	}
+	require.NoError(t, handler.rollback(upgrade))
+	require.NotNil(t, upgrade.Status.Rollback)
+	assert.Equal(t, cloudweavv1.RollbackStateRolledBack, upgrade.Status.Rollback.State)
+	assert.Equal(t, []string{"cluster repo cloudweav-cluster-repo", "managed chart cloudweav", "managed chart cloudweav-crd"}, upgrade.Status.Rollback.RolledBack)
+	assert.Empty(t, upgrade.Status.Rollback.ManualActions)
+}
+
+func TestRollbackSystemServicesTimeout(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newTestManagedChart("cloudweav", "1.1.0"))
+	k8sclientset := k8sfake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-upgrade-rollback", Namespace: upgradeNamespace},
+		Data:       map[string]string{"cloudweav": `{"chart":"cloudweav","version":"1.0.0"}`},
+	})
+	handler := &upgradeHandler{
+		managedChartClient: fakeclients.ManagedChartClient(clientset.ManagementV3().ManagedCharts),
+		managedChartCache:  fakeclients.ManagedChartCache(clientset.ManagementV3().ManagedCharts),
+		configMapCache:     fakeclients.ConfigmapCache(k8sclientset.CoreV1().ConfigMaps),
+	}
+
+	upgrade := newTestUpgradeBuilder().Build()
+	upgrade.Status.RollbackSnapshot = "test-upgrade-rollback"
+	setHelmChartUpgradeStatus(upgrade, corev1.ConditionFalse, "", "job failed")
+	require.NoError(t, handler.rollback(upgrade))
+	assert.Equal(t, cloudweavv1.RollbackStateRollingBack, upgrade.Status.Rollback.State)
+
+	upgrade.Status.Rollback.Time = time.Now().Add(-rollbackTimeout - time.Minute).UTC().Format(time.RFC3339)
+	require.NoError(t, handler.rollback(upgrade))
+	assert.Equal(t, cloudweavv1.RollbackStateManualActionRequired, upgrade.Status.Rollback.State)
+	assert.Len(t, upgrade.Status.Rollback.ManualActions, 1)
+}
+
+func TestRollbackSystemServicesWithoutSnapshot(t *testing.T) {
+	upgrade := newTestUpgradeBuilder().Build()
+	setHelmChartUpgradeStatus(upgrade, corev1.ConditionFalse, "", "job failed")
+
+	handler := &upgradeHandler{}
+	require.NoError(t, handler.rollback(upgrade))
+	require.NotNil(t, upgrade.Status.Rollback)
+	assert.Equal(t, cloudweavv1.RollbackStateManualActionRequired, upgrade.Status.Rollback.State)
+	assert.Len(t, upgrade.Status.Rollback.ManualActions, 1)
+}
+
+func TestRollbackNodes(t *testing.T) {
+	upgrade := newScheduledUpgrade(cloudweavv1.UpgradeSpec{RollbackPolicy: cloudweavv1.RollbackPolicyAuto}, map[string]string{
+		"node1": StateSucceeded,
+		"node2": StateFailed,
+		"node3": nodeStateImagesPreloaded,
+		"node4": nodeStateWaitingBatch,
+	})
+	setHelmChartUpgradeStatus(upgrade, corev1.ConditionTrue, "", "")
+	setNodesUpgradedCondition(upgrade, corev1.ConditionFalse, "", "node2 failed")
+
+	handler := &upgradeHandler{}
+	require.NoError(t, handler.rollback(upgrade))
+	require.NotNil(t, upgrade.Status.Rollback)
+	assert.Equal(t, cloudweavv1.RollbackStateManualActionRequired, upgrade.Status.Rollback.State)
+	assert.Equal(t, []string{"node3", "node4"}, upgrade.Status.Rollback.SkippedNodes)
+	// the system services, node1 and node2
+	assert.Len(t, upgrade.Status.Rollback.ManualActions, 3)
+	assert.Equal(t, nodeStateSkipped, upgrade.Status.NodeStatuses["node3"].State)
+	assert.Equal(t, nodeStateSkipped, upgrade.Status.NodeStatuses["node4"].State)
+	assert.Equal(t, StateSucceeded, upgrade.Status.NodeStatuses["node1"].State)
+}
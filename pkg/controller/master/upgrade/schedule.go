@@ -0,0 +1,221 @@
+package upgrade
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+const (
+	windowTimeLayout = "15:04"
+
+	// pausedAfterNodesAnnotation is the number of the upgraded nodes when the upgrade paused after a node last time
+	pausedAfterNodesAnnotation = "cloudweavhci.io/paused-after-nodes"
+)
+
+// isNodeWaitingToUpgrade returns true if the node has preloaded the images and hasn't started upgrading
+func isNodeWaitingToUpgrade(state string) bool {
+	switch state {
+	case nodeStateImagesPreloaded, nodeStatePaused, nodeStateWaitingWindow, nodeStateWaitingBatch, nodeStateWaitingCanary:
+		return true
+	}
+	return false
+}
+
+// checkNodeSchedule checks whether the node can start upgrading now. If it can't, it returns the state and the
+// message of the node, and how long to wait before checking again if the wait ends at a known time.
+func checkNodeSchedule(upgrade *cloudweavv1.Upgrade, nodeName string, now time.Time) (string, string, time.Duration) {
+	if upgrade.Spec.Paused {
+		return nodeStatePaused, "The upgrade is paused, resume it to continue", 0
+	}
+
+	if canary := upgrade.Spec.Canary; canary != nil && nodeName != canary.Node && !isCanaryFinished(upgrade) {
+		return nodeStateWaitingCanary, fmt.Sprintf("Waiting for the canary node %s to pass the health probes", canary.Node), 0
+	}
+
+	// the canary node goes first whatever its batch is
+	if pending := pendingBatchNodes(upgrade, nodeName); len(pending) > 0 && !isCanaryNode(upgrade, nodeName) {
+		return nodeStateWaitingBatch, fmt.Sprintf("Waiting for nodes %s of the previous batches to be upgraded", strings.Join(pending, ", ")), 0
+	}
+
+	if len(upgrade.Spec.Windows) == 0 {
+		return "", "", 0
+	}
+	opens, err := nextWindow(upgrade.Spec.Windows, now)
+	if err != nil {
+		return nodeStateWaitingWindow, fmt.Sprintf("Invalid upgrade window: %v", err), 0
+	}
+	if opens.After(now) {
+		return nodeStateWaitingWindow, fmt.Sprintf("Waiting for the upgrade window opening at %s", opens.Format(time.RFC3339)), opens.Sub(now)
+	}
+	return "", "", 0
+}
+
+func isCanaryNode(upgrade *cloudweavv1.Upgrade, nodeName string) bool {
+	return upgrade.Spec.Canary != nil && upgrade.Spec.Canary.Node == nodeName
+}
+
+// isCanaryFinished returns true if the health probes of the canary node passed or failed. A failed canary pauses the
+// upgrade, so the other nodes only continue if the upgrade is resumed.
+func isCanaryFinished(upgrade *cloudweavv1.Upgrade) bool {
+	status := upgrade.Status.Canary
+	return status != nil && (status.State == cloudweavv1.CanaryStatePassed || status.State == cloudweavv1.CanaryStateFailed)
+}
+
+// pendingBatchNodes returns the nodes of the previous batches which aren't upgraded
+func pendingBatchNodes(upgrade *cloudweavv1.Upgrade, nodeName string) []string {
+	batch := nodeBatchIndex(upgrade.Spec.NodeBatches, nodeName)
+
+	var pending []string
+	for i := 0; i < batch; i++ {
+		for _, name := range upgrade.Spec.NodeBatches[i].Nodes {
+			status, ok := upgrade.Status.NodeStatuses[name]
+			if ok && status.State != StateSucceeded {
+				pending = append(pending, name)
+			}
+		}
+	}
+	return pending
+}
+
+// nodeBatchIndex returns the index of the batch of the node, the nodes which aren't in any batch are in the last one
+func nodeBatchIndex(batches []cloudweavv1.UpgradeNodeBatch, nodeName string) int {
+	for i, batch := range batches {
+		for _, name := range batch.Nodes {
+			if name == nodeName {
+				return i
+			}
+		}
+	}
+	return len(batches)
+}
+
+// nextWindow returns now if a window is open, otherwise when the next window opens
+func nextWindow(windows []cloudweavv1.UpgradeWindow, now time.Time) (time.Time, error) {
+	var next time.Time
+	for _, window := range windows {
+		opens, err := windowOpens(window, now)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !opens.After(now) {
+			return now, nil
+		}
+		if next.IsZero() || opens.Before(next) {
+			next = opens
+		}
+	}
+	return next, nil
+}
+
+// windowOpens returns when the window opened if it's open, otherwise when it opens next time
+func windowOpens(window cloudweavv1.UpgradeWindow, now time.Time) (time.Time, error) {
+	location, start, end, err := ParseUpgradeWindow(window)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	duration := end - start
+	if duration <= 0 {
+		duration += 24 * time.Hour
+	}
+
+	local := now.In(location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	// the window of yesterday may still be open
+	for day := -1; day <= 7; day++ {
+		date := midnight.AddDate(0, 0, day)
+		if !windowOpensOn(window, date.Weekday()) {
+			continue
+		}
+		opens := date.Add(start)
+		if now.Before(opens.Add(duration)) {
+			return opens, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("window %s-%s never opens", window.Start, window.End)
+}
+
+func windowOpensOn(window cloudweavv1.UpgradeWindow, weekday time.Weekday) bool {
+	if len(window.Days) == 0 {
+		return true
+	}
+	for _, day := range window.Days {
+		if matchWeekday(day, weekday) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseUpgradeWindow returns the time zone of the window, and when the window opens and closes from midnight
+func ParseUpgradeWindow(window cloudweavv1.UpgradeWindow) (*time.Location, time.Duration, time.Duration, error) {
+	location := time.UTC
+	if window.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(window.TimeZone); err != nil {
+			return nil, 0, 0, fmt.Errorf("invalid time zone %s: %w", window.TimeZone, err)
+		}
+	}
+
+	start, err := time.Parse(windowTimeLayout, window.Start)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid start time %s: %w", window.Start, err)
+	}
+	end, err := time.Parse(windowTimeLayout, window.End)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid end time %s: %w", window.End, err)
+	}
+
+	for _, day := range window.Days {
+		if !isWeekday(day) {
+			return nil, 0, 0, fmt.Errorf("invalid day %s", day)
+		}
+	}
+
+	sinceMidnight := func(t time.Time) time.Duration {
+		return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	return location, sinceMidnight(start), sinceMidnight(end), nil
+}
+
+func isWeekday(day string) bool {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if matchWeekday(day, weekday) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWeekday matches the full or the three-letter name of the weekday
+func matchWeekday(day string, weekday time.Weekday) bool {
+	return strings.EqualFold(day, weekday.String()) || strings.EqualFold(day, weekday.String()[:3])
+}
+
+// pauseAfterNode pauses the upgrade before the next node starts upgrading if the upgrade pauses after each node and
+// another node is upgraded since it paused last time. It returns true if the upgrade is paused.
+func pauseAfterNode(upgrade *cloudweavv1.Upgrade) bool {
+	if !upgrade.Spec.PauseAfterEachNode || upgrade.Spec.Paused {
+		return false
+	}
+	upgraded := 0
+	for _, nodeStatus := range upgrade.Status.NodeStatuses {
+		if nodeStatus.State == StateSucceeded {
+			upgraded++
+		}
+	}
+	if upgraded == 0 || upgrade.Annotations[pausedAfterNodesAnnotation] == strconv.Itoa(upgraded) {
+		return false
+	}
+
+	upgrade.Spec.Paused = true
+	if upgrade.Annotations == nil {
+		upgrade.Annotations = make(map[string]string)
+	}
+	upgrade.Annotations[pausedAfterNodesAnnotation] = strconv.Itoa(upgraded)
+	return true
+}
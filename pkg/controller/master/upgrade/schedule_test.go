@@ -0,0 +1,199 @@
+package upgrade
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+func newScheduledUpgrade(spec cloudweavv1.UpgradeSpec, nodeStates map[string]string) *cloudweavv1.Upgrade {
+	upgrade := &cloudweavv1.Upgrade{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-upgrade",
+			Labels: map[string]string{upgradeStateLabel: StateUpgradingNodes},
+		},
+		Spec: spec,
+	}
+	upgrade.Status.NodeStatuses = make(map[string]cloudweavv1.NodeUpgradeStatus)
+	for node, state := range nodeStates {
+		upgrade.Status.NodeStatuses[node] = cloudweavv1.NodeUpgradeStatus{State: state}
+	}
+	return upgrade
+}
+
+func TestCheckNodeSchedule(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+
+	var testCases = []struct {
+		name          string
+		spec          cloudweavv1.UpgradeSpec
+		nodeStates    map[string]string
+		node          string
+		expectedState string
+		expectedAfter time.Duration
+	}{
+		{
+			name:       "no schedule",
+			nodeStates: map[string]string{"node1": nodeStateImagesPreloaded},
+			node:       "node1",
+		},
+		{
+			name:          "paused",
+			spec:          cloudweavv1.UpgradeSpec{Paused: true},
+			nodeStates:    map[string]string{"node1": nodeStateImagesPreloaded},
+			node:          "node1",
+			expectedState: nodeStatePaused,
+		},
+		{
+			name: "previous batch is not upgraded",
+			spec: cloudweavv1.UpgradeSpec{NodeBatches: []cloudweavv1.UpgradeNodeBatch{
+				{Nodes: []string{"node1"}},
+				{Nodes: []string{"node2"}},
+			}},
+			nodeStates:    map[string]string{"node1": nodeStatePreDraining, "node2": nodeStateImagesPreloaded},
+			node:          "node2",
+			expectedState: nodeStateWaitingBatch,
+		},
+		{
+			name: "node not in any batch waits for all batches",
+			spec: cloudweavv1.UpgradeSpec{NodeBatches: []cloudweavv1.UpgradeNodeBatch{
+				{Nodes: []string{"node1"}},
+			}},
+			nodeStates:    map[string]string{"node1": nodeStateImagesPreloaded, "node2": nodeStateImagesPreloaded},
+			node:          "node2",
+			expectedState: nodeStateWaitingBatch,
+		},
+		{
+			name: "previous batch is upgraded",
+			spec: cloudweavv1.UpgradeSpec{NodeBatches: []cloudweavv1.UpgradeNodeBatch{
+				{Nodes: []string{"node1"}},
+				{Nodes: []string{"node2"}},
+			}},
+			nodeStates: map[string]string{"node1": StateSucceeded, "node2": nodeStateImagesPreloaded},
+			node:       "node2",
+		},
+		{
+			name: "window is open",
+			spec: cloudweavv1.UpgradeSpec{Windows: []cloudweavv1.UpgradeWindow{
+				{Start: "09:00", End: "11:00"},
+			}},
+			nodeStates: map[string]string{"node1": nodeStateImagesPreloaded},
+			node:       "node1",
+		},
+		{
+			name: "window opens later today",
+			spec: cloudweavv1.UpgradeSpec{Windows: []cloudweavv1.UpgradeWindow{
+				{Start: "22:00", End: "04:00"},
+			}},
+			nodeStates:    map[string]string{"node1": nodeStateImagesPreloaded},
+			node:          "node1",
+			expectedState: nodeStateWaitingWindow,
+			expectedAfter: 11*time.Hour + 30*time.Minute,
+		},
+		{
+			name: "overnight window of yesterday is open",
+			spec: cloudweavv1.UpgradeSpec{Windows: []cloudweavv1.UpgradeWindow{
+				{Start: "22:00", End: "11:00"},
+			}},
+			nodeStates: map[string]string{"node1": nodeStateImagesPreloaded},
+			node:       "node1",
+		},
+		{
+			name: "weekend window",
+			spec: cloudweavv1.UpgradeSpec{Windows: []cloudweavv1.UpgradeWindow{
+				{Days: []string{"Sat", "sunday"}, Start: "00:00", End: "00:00"},
+			}},
+			nodeStates:    map[string]string{"node1": nodeStateImagesPreloaded},
+			node:          "node1",
+			expectedState: nodeStateWaitingWindow,
+			expectedAfter: 2*24*time.Hour + 13*time.Hour + 30*time.Minute,
+		},
+		{
+			name: "window in another time zone",
+			spec: cloudweavv1.UpgradeSpec{Windows: []cloudweavv1.UpgradeWindow{
+				{Start: "18:00", End: "20:00", TimeZone: "Asia/Taipei"},
+			}},
+			nodeStates: map[string]string{"node1": nodeStateImagesPreloaded},
+			node:       "node1",
+		},
+	}
+
+	for _, tc := range testCases {
+		upgrade := newScheduledUpgrade(tc.spec, tc.nodeStates)
+		state, _, after := checkNodeSchedule(upgrade, tc.node, now)
+		assert.Equal(t, tc.expectedState, state, tc.name)
+		assert.Equal(t, tc.expectedAfter, after, tc.name)
+	}
+}
+
+func TestParseUpgradeWindow(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		window    cloudweavv1.UpgradeWindow
+		expectErr bool
+	}{
+		{
+			name:   "valid window",
+			window: cloudweavv1.UpgradeWindow{Days: []string{"Mon", "Friday"}, Start: "01:00", End: "05:30", TimeZone: "Europe/Berlin"},
+		},
+		{
+			name:      "invalid start",
+			window:    cloudweavv1.UpgradeWindow{Start: "25:00", End: "05:00"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid day",
+			window:    cloudweavv1.UpgradeWindow{Days: []string{"Someday"}, Start: "01:00", End: "05:00"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid time zone",
+			window:    cloudweavv1.UpgradeWindow{Start: "01:00", End: "05:00", TimeZone: "Mars/Olympus"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, _, _, err := ParseUpgradeWindow(tc.window)
+		if tc.expectErr {
+			assert.Error(t, err, tc.name)
+		} else {
+			assert.NoError(t, err, tc.name)
+		}
+	}
+}
+
+func TestPauseAfterEachNode(t *testing.T) {
+	upgrade := newScheduledUpgrade(cloudweavv1.UpgradeSpec{PauseAfterEachNode: true}, map[string]string{
+		"node1": nodeStateImagesPreloaded,
+		"node2": nodeStateImagesPreloaded,
+		"node3": nodeStateImagesPreloaded,
+	})
+	assert.False(t, pauseAfterNode(upgrade), "the first node starts without a pause")
+
+	setNodeUpgradeStatus(upgrade, "node1", StateSucceeded, "", "")
+	assert.False(t, upgrade.Spec.Paused, "the node status doesn't pause the upgrade")
+	assert.True(t, pauseAfterNode(upgrade))
+	assert.True(t, upgrade.Spec.Paused)
+
+	upgrade.Spec.Paused = false
+	assert.False(t, pauseAfterNode(upgrade), "the resumed upgrade continues with the next node")
+
+	setNodeUpgradeStatus(upgrade, "node2", StateSucceeded, "", "")
+	assert.True(t, pauseAfterNode(upgrade))
+}
+
+func TestUpgradingNode(t *testing.T) {
+	upgrade := newScheduledUpgrade(cloudweavv1.UpgradeSpec{}, map[string]string{
+		"node1": StateSucceeded,
+		"node2": nodeStatePostDraining,
+		"node3": nodeStateWaitingBatch,
+	})
+	assert.Equal(t, "node2", upgradingNode(upgrade, "node3"))
+	assert.Equal(t, "", upgradingNode(upgrade, "node2"))
+}
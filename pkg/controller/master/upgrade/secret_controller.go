@@ -2,11 +2,17 @@ package upgrade
 
 import (
 	"fmt"
+	"reflect"
+	"time"
 
 	jobV1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/batch/v1"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
 	ctlclusterv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cluster.x-k8s.io/v1beta1"
@@ -22,12 +28,15 @@ const (
 
 // secretHandler watches pre-drain and pos-drain annotations set by Rancher and create corresponding node jobs
 type secretHandler struct {
-	namespace     string
-	upgradeClient ctlcloudweavv1.UpgradeClient
-	upgradeCache  ctlcloudweavv1.UpgradeCache
-	jobClient     jobV1.JobClient
-	jobCache      jobV1.JobCache
-	machineCache  ctlclusterv1.MachineCache
+	namespace        string
+	upgradeClient    ctlcloudweavv1.UpgradeClient
+	upgradeCache     ctlcloudweavv1.UpgradeCache
+	jobClient        jobV1.JobClient
+	jobCache         jobV1.JobCache
+	machineCache     ctlclusterv1.MachineCache
+	secretController ctlcorev1.SecretController
+	secretCache      ctlcorev1.SecretCache
+	now              func() time.Time
 }
 
 func (h *secretHandler) OnChanged(_ string, secret *v1.Secret) (*v1.Secret, error) {
@@ -74,18 +83,38 @@ func (h *secretHandler) OnChanged(_ string, secret *v1.Secret) (*v1.Secret, erro
 		return secret, nil
 	}
 
-	switch upgrade.Status.NodeStatuses[nodeName].State {
-	case nodeStateImagesPreloaded:
+	switch state := upgrade.Status.NodeStatuses[nodeName].State; {
+	case isNodeWaitingToUpgrade(state):
 		if secret.Annotations[rke2PreDrainAnnotation] != secret.Annotations[preDrainAnnotation] {
-			if err := checkEligibleToDrain(upgrade, nodeName); err != nil {
+			// the nodes requesting the pre-drain hooks at once start one by one, don't decide on a stale upgrade
+			if upgrade, err = h.upgradeClient.Get(upgrade.Namespace, upgrade.Name, metav1.GetOptions{}); err != nil {
 				return nil, err
 			}
+			if !isNodeWaitingToUpgrade(upgrade.Status.NodeStatuses[nodeName].State) {
+				return secret, nil
+			}
+			if toUpdate := upgrade.DeepCopy(); pauseAfterNode(toUpdate) {
+				logrus.Infof("Pause upgrade %s after a node is upgraded", upgrade.Name)
+				if upgrade, err = h.upgradeClient.Update(toUpdate); err != nil {
+					return nil, err
+				}
+			}
+			if waitingState, message, after := checkNodeSchedule(upgrade, nodeName, h.now()); waitingState != "" {
+				if after > 0 {
+					h.secretController.EnqueueAfter(secret.Namespace, secret.Name, after)
+				}
+				return secret, h.holdNode(upgrade, nodeName, waitingState, message)
+			}
+			// rancher requests the pre-drain hooks of the nodes at once, they take their turns in the order of the schedule
+			if upgrading := upgradingNode(upgrade, nodeName); upgrading != "" {
+				return secret, h.holdNode(upgrade, nodeName, nodeStateImagesPreloaded, fmt.Sprintf("Waiting for node %s to be upgraded", upgrading))
+			}
 			logrus.Debugf("Create pre-drain job on %s", nodeName)
 			if err := h.createHookJob(upgrade, nodeName, upgradeJobTypePreDrain, nodeStatePreDraining); err != nil {
 				return nil, err
 			}
 		}
-	case nodeStatePreDrained:
+	case state == nodeStatePreDrained:
 		if secret.Annotations[rke2PostDrainAnnotation] != secret.Annotations[postDrainAnnotation] {
 			if err := checkEligibleToDrain(upgrade, nodeName); err != nil {
 				return nil, err
@@ -100,6 +129,9 @@ func (h *secretHandler) OnChanged(_ string, secret *v1.Secret) (*v1.Secret, erro
 	return secret, nil
 }
 
+// createHookJob moves the node to the next state, then creates the hook job. The status update is the lock of the node
+// upgrades, it fails on a stale upgrade, so two nodes can't both pass the checks on the same upgrade and drain at once.
+// The status is rolled back if the job can't be created.
 func (h *secretHandler) createHookJob(upgrade *cloudweavv1.Upgrade, nodeName string, jobType string, nextState string) error {
 	err := h.checkPendingHookJobs(upgrade.Name)
 	if err != nil {
@@ -111,20 +143,59 @@ func (h *secretHandler) createHookJob(upgrade *cloudweavv1.Upgrade, nodeName str
 		return err
 	}
 
-	_, err = h.jobClient.Create(applyNodeJob(upgrade, repoInfo, nodeName, jobType))
+	toUpdate := upgrade.DeepCopy()
+	setNodeUpgradeStatus(toUpdate, nodeName, nextState, "", "")
+	updated, err := h.upgradeClient.Update(toUpdate)
 	if err != nil {
 		return err
 	}
 
-	toUpdate := upgrade.DeepCopy()
-	setNodeUpgradeStatus(toUpdate, nodeName, nextState, "", "")
-	if _, err := h.upgradeClient.Update(toUpdate); err != nil {
+	if _, err := h.jobClient.Create(applyNodeJob(upgrade, repoInfo, nodeName, jobType)); err != nil {
+		toRollback := updated.DeepCopy()
+		toRollback.Status.NodeStatuses[nodeName] = upgrade.Status.NodeStatuses[nodeName]
+		if _, rollbackErr := h.upgradeClient.Update(toRollback); rollbackErr != nil {
+			logrus.WithError(rollbackErr).Errorf("Failed to roll back the state of node %s", nodeName)
+		}
 		return err
 	}
 
 	return nil
 }
 
+// holdNode keeps the node from upgrading until it's allowed by the schedule of the upgrade
+func (h *secretHandler) holdNode(upgrade *cloudweavv1.Upgrade, nodeName, state, message string) error {
+	toUpdate := upgrade.DeepCopy()
+	setNodeUpgradeStatus(toUpdate, nodeName, state, "", message)
+	if reflect.DeepEqual(upgrade.Status, toUpdate.Status) {
+		return nil
+	}
+	logrus.Infof("Node %s is held from upgrading: %s", nodeName, message)
+	_, err := h.upgradeClient.Update(toUpdate)
+	return err
+}
+
+// ResolveUpgrade enqueues the plan secrets waiting for the pre-drain hooks when the upgrade changes, the nodes held
+// from upgrading may be allowed to upgrade now
+func (h *secretHandler) ResolveUpgrade(_, _ string, obj runtime.Object) ([]relatedresource.Key, error) {
+	upgrade, ok := obj.(*cloudweavv1.Upgrade)
+	if !ok || upgrade.Labels[upgradeStateLabel] != StateUpgradingNodes {
+		return nil, nil
+	}
+
+	secrets, err := h.secretCache.List(rancherPlanSecretNamespace, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var keys []relatedresource.Key
+	for _, secret := range secrets {
+		if secret.Type != rancherPlanSecretType || secret.Annotations[rke2PreDrainAnnotation] == secret.Annotations[preDrainAnnotation] {
+			continue
+		}
+		keys = append(keys, relatedresource.Key{Namespace: secret.Namespace, Name: secret.Name})
+	}
+	return keys, nil
+}
+
 func (h *secretHandler) checkPendingHookJobs(upgrade string) error {
 	sets := labels.Set{
 		cloudweavUpgradeLabel: upgrade,
@@ -144,14 +215,22 @@ func (h *secretHandler) checkPendingHookJobs(upgrade string) error {
 
 func checkEligibleToDrain(upgrade *cloudweavv1.Upgrade, nodeName string) error {
 	// To make sure there will be only one node in the cluster can be put into the pre-drain or post-drain state
+	if name := upgradingNode(upgrade, nodeName); name != "" {
+		return fmt.Errorf("%s is in \"%s\" state so %s is not allowed to run any kind of job", name, upgrade.Status.NodeStatuses[name].State, nodeName)
+	}
+	return nil
+}
+
+// upgradingNode returns another node which is being upgraded
+func upgradingNode(upgrade *cloudweavv1.Upgrade, nodeName string) string {
 	for name, status := range upgrade.Status.NodeStatuses {
 		if name == nodeName {
 			continue
 		}
-		if status.State == StateSucceeded || status.State == nodeStateImagesPreloaded {
+		if status.State == StateSucceeded || isNodeWaitingToUpgrade(status.State) {
 			continue
 		}
-		return fmt.Errorf("%s is in \"%s\" state so %s is not allowed to run any kind of job", name, status, nodeName)
+		return name
 	}
-	return nil
+	return ""
 }
@@ -0,0 +1,75 @@
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/fake"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+)
+
+func TestSecretHandler_createHookJob(t *testing.T) {
+	newHandler := func(upgrade *cloudweavv1.Upgrade) (*secretHandler, *fake.Clientset, *k8sfake.Clientset) {
+		clientset := fake.NewSimpleClientset(upgrade)
+		k8sclientset := k8sfake.NewSimpleClientset()
+		return &secretHandler{
+			namespace:     cloudweavSystemNamespace,
+			upgradeClient: fakeclients.UpgradeClient(clientset.CloudweavhciV1beta1().Upgrades),
+			jobClient:     fakeclients.JobClient(k8sclientset.BatchV1().Jobs),
+			jobCache:      fakeclients.JobCache(k8sclientset.BatchV1().Jobs),
+		}, clientset, k8sclientset
+	}
+	newUpgrade := func() *cloudweavv1.Upgrade {
+		return newTestUpgradeBuilder().WithLabel(upgradeStateLabel, StateUpgradingNodes).
+			NodeUpgradeStatus("node1", nodeStateImagesPreloaded, "", "").
+			NodeUpgradeStatus("node2", nodeStateImagesPreloaded, "", "").Build()
+	}
+	getNodeState := func(clientset *fake.Clientset, nodeName string) string {
+		upgrade, err := clientset.CloudweavhciV1beta1().Upgrades(cloudweavSystemNamespace).Get(context.TODO(), testUpgradeName, metav1.GetOptions{})
+		require.NoError(t, err)
+		return upgrade.Status.NodeStatuses[nodeName].State
+	}
+	listJobs := func(k8sclientset *k8sfake.Clientset) []batchv1.Job {
+		jobs, err := k8sclientset.BatchV1().Jobs(cloudweavSystemNamespace).List(context.TODO(), metav1.ListOptions{})
+		require.NoError(t, err)
+		return jobs.Items
+	}
+
+	// the node moves to the next state, then its job is created
+	upgrade := newUpgrade()
+	handler, clientset, k8sclientset := newHandler(upgrade)
+	require.NoError(t, handler.createHookJob(upgrade, "node1", upgradeJobTypePreDrain, nodeStatePreDraining))
+	assert.Equal(t, nodeStatePreDraining, getNodeState(clientset, "node1"))
+	assert.Len(t, listJobs(k8sclientset), 1)
+
+	// the upgrade changed since it was read, another node may be upgrading, no job is created
+	upgrade = newUpgrade()
+	handler, clientset, k8sclientset = newHandler(upgrade)
+	clientset.PrependReactor("update", "upgrades", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "upgrades"}, testUpgradeName, errors.New("stale upgrade"))
+	})
+	assert.True(t, apierrors.IsConflict(handler.createHookJob(upgrade, "node2", upgradeJobTypePreDrain, nodeStatePreDraining)))
+	assert.Equal(t, nodeStateImagesPreloaded, getNodeState(clientset, "node2"))
+	assert.Empty(t, listJobs(k8sclientset))
+
+	// the job can't be created, the node state is rolled back
+	upgrade = newUpgrade()
+	handler, clientset, k8sclientset = newHandler(upgrade)
+	k8sclientset.PrependReactor("create", "jobs", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("failed to create job")
+	})
+	assert.EqualError(t, handler.createHookJob(upgrade, "node1", upgradeJobTypePreDrain, nodeStatePreDraining), "failed to create job")
+	assert.Equal(t, nodeStateImagesPreloaded, getNodeState(clientset, "node1"))
+}
@@ -12,8 +12,10 @@ import (
 	semverv3 "github.com/Masterminds/semver/v3"
 	provisioningv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
 	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	ctlmgmtv3 "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	provisioningctrl "github.com/rancher/rancher/pkg/generated/controllers/provisioning.cattle.io/v1"
 	"github.com/rancher/wrangler/v3/pkg/condition"
+	ctlappsv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/apps/v1"
 	v1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/batch/v1"
 	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
 	"github.com/sirupsen/logrus"
@@ -22,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
+	policyv1client "k8s.io/client-go/kubernetes/typed/policy/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/retry"
 	kubevirtv1 "kubevirt.io/api/core/v1"
@@ -63,8 +66,15 @@ const (
 	rke2PreDrainAnnotation  = "rke.cattle.io/pre-drain"
 	rke2PostDrainAnnotation = "rke.cattle.io/post-drain"
 
+	// rancher requests the pre-drain hooks of all the nodes of a role at once, the secret handler lets them upgrade one
+	// by one in the order of the upgrade schedule. Rancher still upgrades the control plane nodes first. The node state
+	// is updated before its hook job is created, so the secrets handled at once can't both start a node.
+	rke2UpgradeConcurrency = "100%"
+
 	upgradeComponentRepo = "repo"
 
+	logReadyDisabledReason = "Disabled"
+
 	replicaReplenishmentWaitIntervalSetting  = "replica-replenishment-wait-interval"
 	replicaReplenishmentAnnotation           = "cloudweavhci.io/" + replicaReplenishmentWaitIntervalSetting
 	extendedReplicaReplenishmentWaitInterval = 1800
@@ -106,6 +116,9 @@ type upgradeHandler struct {
 	serviceClient ctlcorev1.ServiceClient
 	pvcClient     ctlcorev1.PersistentVolumeClaimClient
 
+	deploymentClient ctlappsv1.DeploymentClient
+	pdbClient        policyv1client.PodDisruptionBudgetsGetter
+
 	clusterClient provisioningctrl.ClusterClient
 	clusterCache  provisioningctrl.ClusterCache
 
@@ -114,6 +127,11 @@ type upgradeHandler struct {
 
 	kubeVirtCache kubevirtctrl.KubeVirtCache
 
+	managedChartClient ctlmgmtv3.ManagedChartClient
+	managedChartCache  ctlmgmtv3.ManagedChartCache
+	configMapClient    ctlcorev1.ConfigMapClient
+	configMapCache     ctlcorev1.ConfigMapCache
+
 	vmRestClient rest.Interface
 }
 
@@ -143,7 +161,7 @@ func (h *upgradeHandler) OnChanged(_ string, upgrade *cloudweavv1.Upgrade) (*clo
 
 		if !upgrade.Spec.LogEnabled {
 			logrus.Info("Upgrade observability is administratively disabled")
-			setLogReadyCondition(toUpdate, corev1.ConditionFalse, "Disabled", "Upgrade observability is administratively disabled")
+			setLogReadyCondition(toUpdate, corev1.ConditionFalse, logReadyDisabledReason, "Upgrade observability is administratively disabled")
 			toUpdate.Labels[upgradeStateLabel] = StateLoggingInfraPrepared
 			return h.upgradeClient.Update(toUpdate)
 		}
@@ -163,13 +181,20 @@ func (h *upgradeHandler) OnChanged(_ string, upgrade *cloudweavv1.Upgrade) (*clo
 		logrus.Info("Creating upgrade repo image")
 		toUpdate := upgrade.DeepCopy()
 
-		if upgrade.Spec.Image == "" {
-			version, err := h.versionCache.Get(h.namespace, upgrade.Spec.Version)
+		imageName := upgrade.Spec.Image
+		var version *cloudweavv1.Version
+		if imageName == "" {
+			var err error
+			version, err = h.versionCache.Get(h.namespace, upgrade.Spec.Version)
 			if err != nil {
 				setUpgradeCompletedCondition(toUpdate, StateFailed, corev1.ConditionFalse, err.Error(), "")
 				return h.upgradeClient.Update(toUpdate)
 			}
+			// the ISO of an offline version is uploaded as an image
+			imageName = version.Spec.ISOImage
+		}
 
+		if imageName == "" {
 			image, err := repo.CreateImageFromISO(version.Spec.ISOURL, version.Spec.ISOChecksum)
 			if err != nil && apierrors.IsAlreadyExists(err) {
 				image, err = h.vmImageClient.Get(cloudweavSystemNamespace, upgrade.Name, metav1.GetOptions{})
@@ -184,7 +209,7 @@ func (h *upgradeHandler) OnChanged(_ string, upgrade *cloudweavv1.Upgrade) (*clo
 			}
 			toUpdate.Status.ImageID = fmt.Sprintf("%s/%s", image.Namespace, image.Name)
 		} else {
-			image, err := repo.GetImage(upgrade.Spec.Image)
+			image, err := repo.GetImage(imageName)
 			if err != nil {
 				setUpgradeCompletedCondition(toUpdate, StateFailed, corev1.ConditionFalse, err.Error(), "")
 				return h.upgradeClient.Update(toUpdate)
@@ -244,6 +269,23 @@ func (h *upgradeHandler) OnChanged(_ string, upgrade *cloudweavv1.Upgrade) (*clo
 
 	// upgrade failed
 	if cloudweavv1.UpgradeCompleted.IsFalse(upgrade) {
+		if upgrade.Spec.RollbackPolicy == cloudweavv1.RollbackPolicyAuto &&
+			(upgrade.Status.Rollback == nil || upgrade.Status.Rollback.State == cloudweavv1.RollbackStateRollingBack) {
+			if upgrade.Status.Rollback == nil {
+				logrus.Infof("Rolling back upgrade %s/%s", upgrade.Namespace, upgrade.Name)
+			}
+			toUpdate := upgrade.DeepCopy()
+			if err := h.rollback(toUpdate); err != nil {
+				return upgrade, fmt.Errorf("failed to roll back upgrade %s/%s: %w", upgrade.Namespace, upgrade.Name, err)
+			}
+			if toUpdate.Status.Rollback.State == cloudweavv1.RollbackStateRollingBack {
+				h.upgradeController.EnqueueAfter(upgrade.Namespace, upgrade.Name, rollbackCheckInterval)
+			}
+			if reflect.DeepEqual(upgrade.Status, toUpdate.Status) {
+				return upgrade, nil
+			}
+			return h.upgradeClient.Update(toUpdate)
+		}
 		// try to restore vm to the state before upgrade
 		// we didn't wait for KubeVirt to reach the Deployed phase because the upgrade failed,
 		// as a result, some services might not be ready and may never fully start.
@@ -253,7 +295,7 @@ func (h *upgradeHandler) OnChanged(_ string, upgrade *cloudweavv1.Upgrade) (*clo
 	}
 
 	if cloudweavv1.ImageReady.IsTrue(upgrade) && cloudweavv1.RepoProvisioned.GetStatus(upgrade) == "" {
-		logrus.Info("Starting upgrade repo VM")
+		logrus.Info("Starting upgrade repo")
 		toUpdate := upgrade.DeepCopy()
 		if err := repo.Bootstrap(); err != nil && !apierrors.IsAlreadyExists(err) {
 			setUpgradeCompletedCondition(toUpdate, StateFailed, corev1.ConditionFalse, err.Error(), "")
@@ -318,6 +360,13 @@ func (h *upgradeHandler) OnChanged(_ string, upgrade *cloudweavv1.Upgrade) (*clo
 			return h.upgradeClient.Update(toUpdate)
 		}
 
+		if upgrade.Spec.RollbackPolicy == cloudweavv1.RollbackPolicyAuto && upgrade.Status.RollbackSnapshot == "" {
+			if err := h.snapshotSystemServices(toUpdate); err != nil {
+				setUpgradeCompletedCondition(toUpdate, StateFailed, corev1.ConditionFalse, err.Error(), "")
+				return h.upgradeClient.Update(toUpdate)
+			}
+		}
+
 		if _, err := h.jobClient.Create(applyManifestsJob(upgrade, repoInfo)); err != nil && !apierrors.IsAlreadyExists(err) {
 			setUpgradeCompletedCondition(toUpdate, StateFailed, corev1.ConditionFalse, err.Error(), "")
 			return h.upgradeClient.Update(toUpdate)
@@ -403,6 +452,9 @@ func (h *upgradeHandler) cleanupImages(upgrade *cloudweavv1.Upgrade, repo *Repo)
 func (h *upgradeHandler) cleanup(upgrade *cloudweavv1.Upgrade, cleanJobs bool) error {
 	// delete vm and images
 	repo := NewUpgradeRepo(h.ctx, upgrade, h)
+	if err := repo.deleteBundleServer(); err != nil {
+		return err
+	}
 	if err := repo.deleteVM(); err != nil {
 		return err
 	}
@@ -549,8 +601,8 @@ func (h *upgradeHandler) upgradeKubernetes(kubernetesVersion string) error {
 	}
 
 	toUpdate.Spec.RKEConfig.ProvisionGeneration++
-	toUpdate.Spec.RKEConfig.UpgradeStrategy.ControlPlaneConcurrency = "1"
-	toUpdate.Spec.RKEConfig.UpgradeStrategy.WorkerConcurrency = "1"
+	toUpdate.Spec.RKEConfig.UpgradeStrategy.ControlPlaneConcurrency = rke2UpgradeConcurrency
+	toUpdate.Spec.RKEConfig.UpgradeStrategy.WorkerConcurrency = rke2UpgradeConcurrency
 	toUpdate.Spec.RKEConfig.UpgradeStrategy.ControlPlaneDrainOptions.DeleteEmptyDirData = rke2DrainNodes
 	toUpdate.Spec.RKEConfig.UpgradeStrategy.ControlPlaneDrainOptions.Enabled = rke2DrainNodes
 	toUpdate.Spec.RKEConfig.UpgradeStrategy.ControlPlaneDrainOptions.Force = rke2DrainNodes
@@ -693,15 +745,33 @@ func (h *upgradeHandler) prepareNodesForUpgrade(upgrade *cloudweavv1.Upgrade, re
 			// setting the concurrency to 0 is a convenient way to always track the cluster's size
 			imagePreloadConcurrency = len(nodes)
 		}
+	case settings.AdaptiveType:
+		// Concurrency is the ceiling the adaptive strategy scales up to, it starts from a single node
+		maxConcurrency := upgradeConfig.PreloadOption.Strategy.Concurrency
+		if maxConcurrency <= 0 || maxConcurrency > len(nodes) {
+			maxConcurrency = len(nodes)
+		}
+		imagePreloadConcurrency = defaultImagePreloadConcurrency
+		upgrade.Status.ImagePreload = &cloudweavv1.ImagePreloadStatus{MaxConcurrency: maxConcurrency}
 	default:
 		return upgrade, fmt.Errorf("invalid image preload strategy type: %s", upgradeConfig.PreloadOption.Strategy.Type)
 	}
 
-	if _, err := h.planClient.Create(preparePlan(upgrade, imagePreloadConcurrency)); err != nil && !apierrors.IsAlreadyExists(err) {
+	plan := preparePlan(upgrade, imagePreloadConcurrency)
+	if upgradeConfig.PreloadOption.Strategy.Type == settings.AdaptiveType {
+		excludePausedNodes(plan)
+	}
+	if _, err := h.planClient.Create(plan); err != nil && !apierrors.IsAlreadyExists(err) {
 		setUpgradeCompletedCondition(upgrade, StateFailed, corev1.ConditionFalse, err.Error(), "")
 		return h.upgradeClient.Update(upgrade)
 	}
 
+	if upgrade.Status.ImagePreload == nil {
+		upgrade.Status.ImagePreload = &cloudweavv1.ImagePreloadStatus{}
+	}
+	upgrade.Status.ImagePreload.Strategy = string(upgradeConfig.PreloadOption.Strategy.Type)
+	upgrade.Status.ImagePreload.Concurrency = imagePreloadConcurrency
+
 	upgrade.Labels[upgradeStateLabel] = StatePreparingNodes
 	upgrade.Status.RepoInfo = repoInfoStr
 	cloudweavv1.NodesPrepared.CreateUnknownIfNotExists(upgrade)
@@ -399,6 +399,7 @@ func TestUpgradeHandler_prepareNodesForUpgrade(t *testing.T) {
 			expected: output{
 				upgrade: newTestUpgradeBuilder().
 					WithLabel(upgradeStateLabel, StatePreparingNodes).
+					ImagePreloadStatus("sequential", 1, 0).
 					NodesPreparedCondition(v1.ConditionUnknown, "", "").Build(),
 				plan: newPlanBuilder(testPreparePlanName).Concurrency(1).Build(),
 			},
@@ -422,6 +423,7 @@ func TestUpgradeHandler_prepareNodesForUpgrade(t *testing.T) {
 			expected: output{
 				upgrade: newTestUpgradeBuilder().
 					WithLabel(upgradeStateLabel, StatePreparingNodes).
+					ImagePreloadStatus("sequential", 1, 0).
 					NodesPreparedCondition(v1.ConditionUnknown, "", "").Build(),
 				plan: newPlanBuilder(testPreparePlanName).Concurrency(1).Build(),
 			},
@@ -445,6 +447,7 @@ func TestUpgradeHandler_prepareNodesForUpgrade(t *testing.T) {
 			expected: output{
 				upgrade: newTestUpgradeBuilder().
 					WithLabel(upgradeStateLabel, StatePreparingNodes).
+					ImagePreloadStatus("sequential", 1, 0).
 					NodesPreparedCondition(v1.ConditionUnknown, "", "").Build(),
 				plan: newPlanBuilder(testPreparePlanName).Concurrency(1).Build(),
 			},
@@ -468,6 +471,7 @@ func TestUpgradeHandler_prepareNodesForUpgrade(t *testing.T) {
 			expected: output{
 				upgrade: newTestUpgradeBuilder().
 					WithLabel(upgradeStateLabel, StatePreparingNodes).
+					ImagePreloadStatus("parallel", 2, 0).
 					NodesPreparedCondition(v1.ConditionUnknown, "", "").Build(),
 				plan: newPlanBuilder(testPreparePlanName).Concurrency(2).Build(),
 			},
@@ -491,6 +495,7 @@ func TestUpgradeHandler_prepareNodesForUpgrade(t *testing.T) {
 			expected: output{
 				upgrade: newTestUpgradeBuilder().
 					WithLabel(upgradeStateLabel, StatePreparingNodes).
+					ImagePreloadStatus("parallel", 3, 0).
 					NodesPreparedCondition(v1.ConditionUnknown, "", "").Build(),
 				plan: newPlanBuilder(testPreparePlanName).Concurrency(3).Build(),
 			},
@@ -514,10 +519,59 @@ func TestUpgradeHandler_prepareNodesForUpgrade(t *testing.T) {
 			expected: output{
 				upgrade: newTestUpgradeBuilder().
 					WithLabel(upgradeStateLabel, StatePreparingNodes).
+					ImagePreloadStatus("parallel", 3, 0).
 					NodesPreparedCondition(v1.ConditionUnknown, "", "").Build(),
 				plan: newPlanBuilder(testPreparePlanName).Concurrency(3).Build(),
 			},
 		},
+		{
+			name: "set image preload strategy type to adaptive will create the prepare plan with concurrency 1 and the concurrency setting as the ceiling",
+			given: input{
+				upgrade: newTestUpgradeBuilder().Build(),
+				setting: &cloudweavv1.Setting{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: settings.UpgradeConfigSettingName,
+					},
+					Value: `{"imagePreloadOption":{"strategy":{"type":"adaptive","concurrency":2}}}`,
+				},
+				nodes: []*v1.Node{
+					newNodeBuilder(testNodeName1).Build(),
+					newNodeBuilder(testNodeName2).Build(),
+					newNodeBuilder(testNodeName3).Build(),
+				},
+			},
+			expected: output{
+				upgrade: newTestUpgradeBuilder().
+					WithLabel(upgradeStateLabel, StatePreparingNodes).
+					ImagePreloadStatus("adaptive", 1, 2).
+					NodesPreparedCondition(v1.ConditionUnknown, "", "").Build(),
+				plan: newPlanBuilder(testPreparePlanName).Concurrency(1).Build(),
+			},
+		},
+		{
+			name: "set image preload strategy type to adaptive and concurrency to 0 will scale up to the node count",
+			given: input{
+				upgrade: newTestUpgradeBuilder().Build(),
+				setting: &cloudweavv1.Setting{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: settings.UpgradeConfigSettingName,
+					},
+					Value: `{"imagePreloadOption":{"strategy":{"type":"adaptive","concurrency":0}}}`,
+				},
+				nodes: []*v1.Node{
+					newNodeBuilder(testNodeName1).Build(),
+					newNodeBuilder(testNodeName2).Build(),
+					newNodeBuilder(testNodeName3).Build(),
+				},
+			},
+			expected: output{
+				upgrade: newTestUpgradeBuilder().
+					WithLabel(upgradeStateLabel, StatePreparingNodes).
+					ImagePreloadStatus("adaptive", 1, 3).
+					NodesPreparedCondition(v1.ConditionUnknown, "", "").Build(),
+				plan: newPlanBuilder(testPreparePlanName).Concurrency(1).Build(),
+			},
+		},
 		{
 			name: "set image preload strategy type with an invalid type",
 			given: input{
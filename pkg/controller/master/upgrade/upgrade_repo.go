@@ -27,6 +27,7 @@ import (
 	"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
 	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
 	"github.com/cloudweav/cloudweav/pkg/controller/master/upgrade/repoinfo"
+	"github.com/cloudweav/cloudweav/pkg/settings"
 	"github.com/cloudweav/cloudweav/pkg/util"
 )
 
@@ -83,7 +84,16 @@ func (r *Repo) Bootstrap() error {
 		return err
 	}
 
-	_, err = r.createVM(upgradeImage)
+	upgradeConfig, err := settings.DecodeConfig[settings.UpgradeConfig](settings.UpgradeConfigSet.Get())
+	if err != nil {
+		return err
+	}
+	switch upgradeConfig.RepoOption.Provider {
+	case settings.PodRepoProvider:
+		err = r.createBundleServer(upgradeImage)
+	default:
+		_, err = r.createVM(upgradeImage)
+	}
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,258 @@
+package upgrade
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+const (
+	repoBundleServerNamePrefix = "upgrade-repo-"
+	repoBundleServerReplicas   = 2
+	repoBundleServerDevice     = "/dev/cloudweav-iso"
+	// repoBundleServerScript mounts the upgrade ISO read-only and serves it with nginx at the same path as the repo VM
+	repoBundleServerScript = `
+#!/usr/bin/env sh
+set -e
+
+mkdir -p /cloudweav-iso
+mount -t iso9660 -o ro "$ISO_DEVICE" /cloudweav-iso
+
+cat > /tmp/nginx.conf <<'EOF'
+pid /tmp/nginx.pid;
+error_log stderr;
+events {}
+http {
+	include /etc/nginx/mime.types;
+	access_log off;
+	sendfile on;
+	server {
+		listen 80;
+		location /cloudweav-iso/ {
+			alias /cloudweav-iso/;
+			autoindex on;
+		}
+	}
+}
+EOF
+
+exec nginx -c /tmp/nginx.conf -g 'daemon off;'
+`
+)
+
+func (r *Repo) getBundleServerName() string {
+	return fmt.Sprintf("%s%s", repoBundleServerNamePrefix, r.upgrade.Name)
+}
+
+func (r *Repo) bundleServerLabels() map[string]string {
+	return map[string]string{
+		cloudweavUpgradeLabel:          r.upgrade.Name,
+		cloudweavUpgradeComponentLabel: upgradeComponentRepo,
+	}
+}
+
+// createBundleServer serves the upgrade repo with pods instead of a VM. Each pod mounts a volume of the upgrade image,
+// which is served directly from the backing image. With more than one node, the pods are spread across the nodes and
+// a PDB keeps one of them serving while the nodes are drained.
+func (r *Repo) createBundleServer(image *cloudweavv1.VirtualMachineImage) error {
+	nodes, err := r.h.nodeCache.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	replicas := repoBundleServerReplicas
+	if len(nodes) < replicas {
+		replicas = len(nodes)
+	}
+
+	if replicas > 1 {
+		if _, err := r.h.pdbClient.PodDisruptionBudgets(upgradeNamespace).Create(r.ctx, r.bundleServerPDB(), metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	logrus.Infof("Create upgrade repo bundle server %s/%s with %d replicas", upgradeNamespace, r.getBundleServerName(), replicas)
+	_, err = r.h.deploymentClient.Create(r.bundleServerDeployment(image, int32(replicas)))
+	return err
+}
+
+func (r *Repo) bundleServerPDB() *policyv1.PodDisruptionBudget {
+	minAvailable := intstr.FromInt(1)
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: upgradeNamespace,
+			Name:      r.getBundleServerName(),
+			Labels:    r.bundleServerLabels(),
+			OwnerReferences: []metav1.OwnerReference{
+				upgradeReference(r.upgrade),
+			},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: r.bundleServerLabels(),
+			},
+		},
+	}
+}
+
+func (r *Repo) bundleServerDeployment(image *cloudweavv1.VirtualMachineImage, replicas int32) *appsv1.Deployment {
+	volumeMode := corev1.PersistentVolumeBlock
+	storageClassName := image.Status.StorageClassName
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: upgradeNamespace,
+			Name:      r.getBundleServerName(),
+			Labels:    r.bundleServerLabels(),
+			OwnerReferences: []metav1.OwnerReference{
+				upgradeReference(r.upgrade),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: r.bundleServerLabels(),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: r.bundleServerLabels(),
+				},
+				Spec: corev1.PodSpec{
+					Affinity: &corev1.Affinity{
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+								{
+									Weight: 100,
+									PodAffinityTerm: corev1.PodAffinityTerm{
+										LabelSelector: &metav1.LabelSelector{
+											MatchLabels: r.bundleServerLabels(),
+										},
+										TopologyKey: corev1.LabelHostname,
+									},
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    "bundle-server",
+							Image:   fmt.Sprintf("%s:%s", upgradeImageRepository, r.upgrade.Status.PreviousVersion),
+							Command: []string{"sh", "-c", repoBundleServerScript},
+							Env: []corev1.EnvVar{
+								{
+									Name:  "ISO_DEVICE",
+									Value: repoBundleServerDevice,
+								},
+							},
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "http",
+									ContainerPort: 80,
+									Protocol:      corev1.ProtocolTCP,
+								},
+							},
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: "/cloudweav-iso/cloudweav-release.yaml",
+										Port: intstr.FromInt(80),
+									},
+								},
+								PeriodSeconds:    10,
+								TimeoutSeconds:   5,
+								FailureThreshold: 3,
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("100m"),
+									corev1.ResourceMemory: resource.MustParse("64Mi"),
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("256Mi"),
+								},
+							},
+							// mounting the ISO needs the privilege
+							SecurityContext: &corev1.SecurityContext{
+								Privileged: pointer.Bool(true),
+							},
+							VolumeDevices: []corev1.VolumeDevice{
+								{
+									Name:       "iso",
+									DevicePath: repoBundleServerDevice,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "iso",
+							VolumeSource: corev1.VolumeSource{
+								Ephemeral: &corev1.EphemeralVolumeSource{
+									VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+										ObjectMeta: metav1.ObjectMeta{
+											Labels: r.bundleServerLabels(),
+											Annotations: map[string]string{
+												util.AnnotationImageID: fmt.Sprintf("%s/%s", image.Namespace, image.Name),
+											},
+										},
+										Spec: corev1.PersistentVolumeClaimSpec{
+											AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+											Resources: corev1.VolumeResourceRequirements{
+												Requests: corev1.ResourceList{
+													corev1.ResourceStorage: resource.MustParse("10Gi"),
+												},
+											},
+											VolumeMode:       &volumeMode,
+											StorageClassName: &storageClassName,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// deleteBundleServer deletes the bundle server pods and waits for their volumes to be gone, the upgrade image can't be
+// deleted while it's still used by a volume
+func (r *Repo) deleteBundleServer() error {
+	name := r.getBundleServerName()
+	if _, err := r.h.deploymentClient.Get(upgradeNamespace, name, metav1.GetOptions{}); err == nil {
+		logrus.Infof("Delete upgrade repo bundle server %s/%s", upgradeNamespace, name)
+		if err := r.h.deploymentClient.Delete(upgradeNamespace, name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if err := r.h.pdbClient.PodDisruptionBudgets(upgradeNamespace).Delete(r.ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	pvcs, err := r.h.pvcClient.List(upgradeNamespace, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(r.bundleServerLabels()).String(),
+	})
+	if err != nil {
+		return err
+	}
+	if len(pvcs.Items) > 0 {
+		return fmt.Errorf("waiting for %d upgrade repo volumes to be deleted", len(pvcs.Items))
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package upgrade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/util"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+)
+
+func TestRepo_createBundleServer(t *testing.T) {
+	image := &cloudweavv1.VirtualMachineImage{
+		ObjectMeta: metav1.ObjectMeta{Namespace: upgradeNamespace, Name: testUpgradeImage},
+		Status:     cloudweavv1.VirtualMachineImageStatus{StorageClassName: "longhorn-" + testUpgradeImage},
+	}
+
+	var testCases = []struct {
+		name     string
+		nodes    []string
+		replicas int32
+		pdb      bool
+	}{
+		{
+			name:     "spread the pods across nodes and keep one of them during drains",
+			nodes:    []string{testNodeName1, testNodeName2, testNodeName3},
+			replicas: 2,
+			pdb:      true,
+		},
+		{
+			name:     "single node cluster has a single pod",
+			nodes:    []string{testNodeName1},
+			replicas: 1,
+		},
+	}
+	for _, tc := range testCases {
+		var nodes []runtime.Object
+		for _, nodeName := range tc.nodes {
+			nodes = append(nodes, newNodeBuilder(nodeName).Build())
+		}
+		k8sclientset := k8sfake.NewSimpleClientset(nodes...)
+		upgrade := newTestUpgradeBuilder().Build()
+		upgrade.Status.PreviousVersion = "v1.4.0"
+		repo := NewUpgradeRepo(context.TODO(), upgrade, &upgradeHandler{
+			nodeCache:        fakeclients.NodeCache(k8sclientset.CoreV1().Nodes),
+			deploymentClient: fakeclients.DeploymentClient(k8sclientset.AppsV1().Deployments),
+			pdbClient:        k8sclientset.PolicyV1(),
+		})
+
+		require.NoError(t, repo.createBundleServer(image), "case %q", tc.name)
+
+		deployment, err := k8sclientset.AppsV1().Deployments(upgradeNamespace).Get(context.TODO(), "upgrade-repo-"+testUpgradeName, metav1.GetOptions{})
+		require.NoError(t, err, "case %q", tc.name)
+		assert.Equal(t, tc.replicas, *deployment.Spec.Replicas, "case %q", tc.name)
+		podSpec := deployment.Spec.Template.Spec
+		assert.Equal(t, upgradeComponentRepo, deployment.Spec.Template.Labels[cloudweavUpgradeComponentLabel], "case %q", tc.name)
+		assert.Equal(t, "panmeta/cloudweav-upgrade:v1.4.0", podSpec.Containers[0].Image, "case %q", tc.name)
+		claim := podSpec.Volumes[0].Ephemeral.VolumeClaimTemplate
+		assert.Equal(t, upgradeNamespace+"/"+testUpgradeImage, claim.Annotations[util.AnnotationImageID], "case %q", tc.name)
+		assert.Equal(t, "longhorn-"+testUpgradeImage, *claim.Spec.StorageClassName, "case %q", tc.name)
+		assert.Equal(t, corev1.PersistentVolumeBlock, *claim.Spec.VolumeMode, "case %q", tc.name)
+
+		_, err = k8sclientset.PolicyV1().PodDisruptionBudgets(upgradeNamespace).Get(context.TODO(), "upgrade-repo-"+testUpgradeName, metav1.GetOptions{})
+		if tc.pdb {
+			assert.NoError(t, err, "case %q", tc.name)
+		} else {
+			assert.True(t, apierrors.IsNotFound(err), "case %q", tc.name)
+		}
+	}
+}
+
+func TestRepo_deleteBundleServer(t *testing.T) {
+	upgrade := newTestUpgradeBuilder().Build()
+	repo := NewUpgradeRepo(context.TODO(), upgrade, nil)
+	volume := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: upgradeNamespace,
+			Name:      "upgrade-repo-" + testUpgradeName + "-abcde-iso",
+			Labels:    repo.bundleServerLabels(),
+		},
+	}
+	k8sclientset := k8sfake.NewSimpleClientset(repo.bundleServerPDB(), volume)
+	repo.h = &upgradeHandler{
+		deploymentClient: fakeclients.DeploymentClient(k8sclientset.AppsV1().Deployments),
+		pdbClient:        k8sclientset.PolicyV1(),
+		pvcClient:        fakeclients.PersistentVolumeClaimClient(k8sclientset.CoreV1().PersistentVolumeClaims),
+	}
+	_, err := repo.h.deploymentClient.Create(repo.bundleServerDeployment(&cloudweavv1.VirtualMachineImage{}, 2))
+	require.NoError(t, err)
+
+	assert.Error(t, repo.deleteBundleServer(), "wait for the volumes before the image is deleted")
+	_, err = k8sclientset.AppsV1().Deployments(upgradeNamespace).Get(context.TODO(), repo.getBundleServerName(), metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+	_, err = k8sclientset.PolicyV1().PodDisruptionBudgets(upgradeNamespace).Get(context.TODO(), repo.getBundleServerName(), metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	require.NoError(t, k8sclientset.CoreV1().PersistentVolumeClaims(upgradeNamespace).Delete(context.TODO(), volume.Name, metav1.DeleteOptions{}))
+	assert.NoError(t, repo.deleteBundleServer())
+}
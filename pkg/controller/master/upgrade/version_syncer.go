@@ -25,33 +25,20 @@ import (
 	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
 	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
 	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/upgraderesponder"
 	"github.com/cloudweav/cloudweav/pkg/util"
 )
 
 const (
 	syncInterval = time.Hour
-
-	extraInfoNodeCount  = "nodeCount"
-	extraInfoCPUCount   = "cpuCount"
-	extraInfoMemorySize = "memorySize"
-	extraInfoClusterUID = "clusterUID"
 )
 
-type CheckUpgradeRequest struct {
-	AppVersion string            `json:"appVersion"`
-	ExtraInfo  map[string]string `json:"extraInfo"`
-}
-
-type CheckUpgradeResponse struct {
-	Versions []Version `json:"versions"`
-}
-
-type Version struct {
-	Name                 string   `json:"name"` // must be in semantic versioning
-	ReleaseDate          string   `json:"releaseDate"`
-	MinUpgradableVersion string   `json:"minUpgradableVersion,omitempty"`
-	Tags                 []string `json:"tags"`
-}
+// the requests and responses of the upgrade checker are shared with the embedded upgrade responder
+type (
+	CheckUpgradeRequest  = upgraderesponder.CheckUpgradeRequest
+	CheckUpgradeResponse = upgraderesponder.CheckUpgradeResponse
+	Version              = upgraderesponder.Version
+)
 
 type versionSyncer struct {
 	ctx        context.Context
@@ -102,8 +89,9 @@ func (s *versionSyncer) sync() error {
 		return err
 	}
 	req := &CheckUpgradeRequest{
-		AppVersion: settings.ServerVersion.Get(),
-		ExtraInfo:  extraInfo,
+		AppVersion:     settings.ServerVersion.Get(),
+		ReleaseChannel: settings.ReleaseChannel.Get(),
+		ExtraInfo:      extraInfo,
 	}
 	var requestBody bytes.Buffer
 	if err := json.NewEncoder(&requestBody).Encode(req); err != nil {
@@ -127,40 +115,69 @@ func (s *versionSyncer) sync() error {
 	return s.syncVersions(checkResp, current)
 }
 
+// getExtraInfo returns the telemetry fields which are opted in by the upgrade-checker-telemetry setting
 func (s *versionSyncer) getExtraInfo() (map[string]string, error) {
-	nodes, err := s.nodeClient.List(metav1.ListOptions{})
-	if err != nil {
-		return nil, err
+	extraInfo := map[string]string{}
+	fields := telemetryFields(settings.UpgradeCheckerTelemetry.Get())
+	if len(fields) == 0 {
+		return extraInfo, nil
 	}
-	sysNamespace, err := s.namespaceClient.Get(util.CloudweavSystemNamespaceName, metav1.GetOptions{})
-	if err != nil {
-		return nil, err
+
+	if fields[upgraderesponder.ExtraInfoNodeCount] || fields[upgraderesponder.ExtraInfoCPUCount] || fields[upgraderesponder.ExtraInfoMemorySize] {
+		nodes, err := s.nodeClient.List(metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		cpu := resource.NewQuantity(0, resource.BinarySI)
+		memory := resource.NewQuantity(0, resource.BinarySI)
+		for _, node := range nodes.Items {
+			cpu.Add(*node.Status.Capacity.Cpu())
+			memory.Add(*node.Status.Capacity.Memory())
+		}
+		if fields[upgraderesponder.ExtraInfoCPUCount] {
+			extraInfo[upgraderesponder.ExtraInfoCPUCount] = cpu.String()
+		}
+		if fields[upgraderesponder.ExtraInfoMemorySize] {
+			extraInfo[upgraderesponder.ExtraInfoMemorySize] = formatQuantityToGi(memory)
+		}
+		if fields[upgraderesponder.ExtraInfoNodeCount] {
+			extraInfo[upgraderesponder.ExtraInfoNodeCount] = strconv.Itoa(len(nodes.Items))
+		}
 	}
-	extraInfo := map[string]string{}
-	cpu := resource.NewQuantity(0, resource.BinarySI)
-	memory := resource.NewQuantity(0, resource.BinarySI)
-	for _, node := range nodes.Items {
-		cpu.Add(*node.Status.Capacity.Cpu())
-		memory.Add(*node.Status.Capacity.Memory())
+
+	if fields[upgraderesponder.ExtraInfoClusterUID] {
+		sysNamespace, err := s.namespaceClient.Get(util.CloudweavSystemNamespaceName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		extraInfo[upgraderesponder.ExtraInfoClusterUID] = string(sysNamespace.UID)
 	}
-	extraInfo[extraInfoCPUCount] = cpu.String()
-	extraInfo[extraInfoMemorySize] = formatQuantityToGi(memory)
-	extraInfo[extraInfoNodeCount] = strconv.Itoa(len(nodes.Items))
-	extraInfo[extraInfoClusterUID] = string(sysNamespace.UID)
 	return extraInfo, nil
 }
 
+func telemetryFields(value string) map[string]bool {
+	fields := map[string]bool{}
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields[field] = true
+		}
+	}
+	return fields
+}
+
 func (s *versionSyncer) syncVersions(resp CheckUpgradeResponse, currentVersion string) error {
 	if runtime.GOARCH == "arm64" {
 		// wait until https://github.com/cloudweav/cloudweav/issues/6257 is resolved
 		return nil
 	}
-	if err := s.cleanupVersions(currentVersion, resp.Versions); err != nil {
+	// a self-hosted responder may return the versions of all channels
+	versions := upgraderesponder.FilterChannel(resp.Versions, settings.ReleaseChannel.Get())
+	if err := s.cleanupVersions(currentVersion, versions); err != nil {
 		return err
 	}
 
 	// iterate over response and identify if a new version needs to be created
-	for _, v := range resp.Versions {
+	for _, v := range versions {
 		newVersion, err := s.getNewVersion(v)
 		if err != nil {
 			if strings.Contains(err.Error(), "failed to download version") {
@@ -200,6 +217,10 @@ func (s *versionSyncer) cleanupVersions(currentVersion string, remoteVersions []
 
 	for _, version := range remoteVersions {
 		for i, v := range versions.Items {
+			// the versions of the uploaded ISOs aren't synced from the upgrade checker
+			if v.Spec.ISOImage != "" {
+				continue
+			}
 			versionObj := v
 			if !canUpgrade(currentVersion, &versionObj, version) {
 				if err := s.versionClient.Delete(v.Namespace, v.Name, &metav1.DeleteOptions{}); err != nil {
@@ -217,6 +238,23 @@ func (s *versionSyncer) cleanupVersions(currentVersion string, remoteVersions []
 }
 
 func (s *versionSyncer) getNewVersion(v Version) (*cloudweavv1.Version, error) {
+	// the curated versions of a self-hosted responder have the ISO, the version.yaml isn't needed
+	if v.ISOURL != "" {
+		return &cloudweavv1.Version{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      v.Name,
+				Namespace: s.namespace,
+			},
+			Spec: cloudweavv1.VersionSpec{
+				ISOURL:               v.ISOURL,
+				ISOChecksum:          v.ISOChecksum,
+				ReleaseDate:          v.ReleaseDate,
+				MinUpgradableVersion: v.MinUpgradableVersion,
+				Tags:                 v.Tags,
+			},
+		}, nil
+	}
+
 	releaseDownloadURL := settings.ReleaseDownloadURL.Get()
 	var archSuffix string
 	if runtime.GOARCH == "arm64" {
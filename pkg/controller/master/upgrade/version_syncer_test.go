@@ -11,9 +11,11 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 
 	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
 	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/fake"
@@ -310,6 +312,133 @@ func Test_syncVersions(t *testing.T) {
 
 }
 
+func Test_syncVersionsReleaseChannel(t *testing.T) {
+	resp := CheckUpgradeResponse{
+		Versions: []Version{
+			{
+				Name:        "v1.4.2",
+				Tags:        []string{"stable", "lts"},
+				ISOURL:      "https://releases.example.com/v1.4.2/cloudweav.iso",
+				ISOChecksum: "1111",
+			},
+			{
+				Name:                 "v1.5.0",
+				MinUpgradableVersion: "v1.4.0",
+				Tags:                 []string{"latest"},
+				ISOURL:               "https://releases.example.com/v1.5.0/cloudweav.iso",
+				ISOChecksum:          "2222",
+			},
+		},
+	}
+
+	var testCases = []struct {
+		name          string
+		channel       string
+		expectedNames []string
+	}{
+		{
+			name:          "all channels",
+			channel:       "",
+			expectedNames: []string{"v1.4.2", "v1.5.0"},
+		},
+		{
+			name:          "lts channel",
+			channel:       "lts",
+			expectedNames: []string{"v1.4.2"},
+		},
+		{
+			name:          "latest channel",
+			channel:       "latest",
+			expectedNames: []string{"v1.5.0"},
+		},
+	}
+
+	defer func() {
+		_ = settings.ReleaseChannel.Set("")
+	}()
+	for _, tc := range testCases {
+		require.NoError(t, settings.ReleaseChannel.Set(tc.channel))
+		client := fake.NewSimpleClientset()
+		vc := fakeclients.VersionClient(client.CloudweavhciV1beta1().Versions)
+		vs := versionSyncer{
+			ctx:           context.TODO(),
+			namespace:     defaultNamespace,
+			versionClient: vc,
+		}
+		require.NoError(t, vs.syncVersions(resp, "v1.4.0"), tc.name)
+
+		versionList, err := vc.List(defaultNamespace, metav1.ListOptions{})
+		require.NoError(t, err, tc.name)
+		var names []string
+		for _, version := range versionList.Items {
+			names = append(names, version.Name)
+			assert.NotEmpty(t, version.Spec.ISOURL, tc.name)
+			assert.NotEmpty(t, version.Spec.ISOChecksum, tc.name)
+		}
+		assert.ElementsMatch(t, tc.expectedNames, names, tc.name)
+	}
+}
+
+func Test_getExtraInfo(t *testing.T) {
+	newNode := func(name, cpu, memory string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				},
+			},
+		}
+	}
+	k8sclientset := k8sfake.NewSimpleClientset(
+		newNode("node1", "8", "32Gi"),
+		newNode("node2", "16", "64Gi"),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: defaultNamespace, UID: "cluster-uid"}},
+	)
+	vs := versionSyncer{
+		nodeClient:      fakeclients.NodeClient(k8sclientset.CoreV1().Nodes),
+		namespaceClient: fakeclients.NamespaceClient(k8sclientset.CoreV1().Namespaces),
+	}
+
+	var testCases = []struct {
+		name      string
+		telemetry string
+		expected  map[string]string
+	}{
+		{
+			name:      "nothing is sent by default",
+			telemetry: "",
+			expected:  map[string]string{},
+		},
+		{
+			name:      "node count only",
+			telemetry: "nodeCount",
+			expected:  map[string]string{"nodeCount": "2"},
+		},
+		{
+			name:      "all fields",
+			telemetry: "nodeCount, cpuCount,memorySize,clusterUID",
+			expected: map[string]string{
+				"nodeCount":  "2",
+				"cpuCount":   "24",
+				"memorySize": "96Gi",
+				"clusterUID": "cluster-uid",
+			},
+		},
+	}
+
+	defer func() {
+		_ = settings.UpgradeCheckerTelemetry.Set("")
+	}()
+	for _, tc := range testCases {
+		require.NoError(t, settings.UpgradeCheckerTelemetry.Set(tc.telemetry))
+		extraInfo, err := vs.getExtraInfo()
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expected, extraInfo, tc.name)
+	}
+}
+
 type fakeResponder struct {
 	resp CheckUpgradeResponse
 }
@@ -27,6 +27,9 @@ func createCRDs(ctx context.Context, restConfig *rest.Config) error {
 	return factory.
 		BatchCreateCRDsIfNotExisted(
 			crd.NonNamespacedFromGV(cloudweavv1.SchemeGroupVersion, "Setting", cloudweavv1.Setting{}),
+			crd.NonNamespacedFromGV(cloudweavv1.SchemeGroupVersion, "NodeBMC", cloudweavv1.NodeBMC{}),
+			crd.NonNamespacedFromGV(cloudweavv1.SchemeGroupVersion, "MaintenancePlan", cloudweavv1.MaintenancePlan{}),
+			crd.NonNamespacedFromGV(cloudweavv1.SchemeGroupVersion, "NodeHealth", cloudweavv1.NodeHealth{}),
 			crd.NonNamespacedFromGV(rancherv3.SchemeGroupVersion, "APIService", rancherv3.APIService{}),
 			crd.NonNamespacedFromGV(rancherv3.SchemeGroupVersion, "Setting", rancherv3.Setting{}),
 			crd.NonNamespacedFromGV(rancherv3.SchemeGroupVersion, "User", rancherv3.User{}),
@@ -42,6 +45,8 @@ func createCRDs(ctx context.Context, restConfig *rest.Config) error {
 			crd.FromGV(cloudweavv1.SchemeGroupVersion, "KeyPair", cloudweavv1.KeyPair{}),
 			crd.FromGV(cloudweavv1.SchemeGroupVersion, "Upgrade", cloudweavv1.Upgrade{}),
 			crd.FromGV(cloudweavv1.SchemeGroupVersion, "UpgradeLog", cloudweavv1.UpgradeLog{}),
+			crd.FromGV(cloudweavv1.SchemeGroupVersion, "UpgradeReadiness", cloudweavv1.UpgradeReadiness{}),
+			crd.FromGV(cloudweavv1.SchemeGroupVersion, "UpgradeHistory", cloudweavv1.UpgradeHistory{}),
 			crd.FromGV(cloudweavv1.SchemeGroupVersion, "Version", cloudweavv1.Version{}),
 			crd.FromGV(cloudweavv1.SchemeGroupVersion, "VirtualMachineImage", cloudweavv1.VirtualMachineImage{}),
 			crd.FromGV(cloudweavv1.SchemeGroupVersion, "VirtualMachineTemplate", cloudweavv1.VirtualMachineTemplate{}),
@@ -30,13 +30,18 @@ type CloudweavhciV1beta1Interface interface {
 	RESTClient() rest.Interface
 	AddonsGetter
 	KeyPairsGetter
+	MaintenancePlansGetter
+	NodeBMCsGetter
+	NodeHealthsGetter
 	PreferencesGetter
 	ResourceQuotasGetter
 	ScheduleVMBackupsGetter
 	SettingsGetter
 	SupportBundlesGetter
 	UpgradesGetter
+	UpgradeHistoriesGetter
 	UpgradeLogsGetter
+	UpgradeReadinessesGetter
 	VersionsGetter
 	VirtualMachineBackupsGetter
 	VirtualMachineImagesGetter
@@ -58,6 +63,18 @@ func (c *CloudweavhciV1beta1Client) KeyPairs(namespace string) KeyPairInterface
 	return newKeyPairs(c, namespace)
 }
 
+func (c *CloudweavhciV1beta1Client) MaintenancePlans() MaintenancePlanInterface {
+	return newMaintenancePlans(c)
+}
+
+func (c *CloudweavhciV1beta1Client) NodeBMCs() NodeBMCInterface {
+	return newNodeBMCs(c)
+}
+
+func (c *CloudweavhciV1beta1Client) NodeHealths() NodeHealthInterface {
+	return newNodeHealths(c)
+}
+
 func (c *CloudweavhciV1beta1Client) Preferences(namespace string) PreferenceInterface {
 	return newPreferences(c, namespace)
 }
@@ -82,10 +99,18 @@ func (c *CloudweavhciV1beta1Client) Upgrades(namespace string) UpgradeInterface
 	return newUpgrades(c, namespace)
 }
 
+func (c *CloudweavhciV1beta1Client) UpgradeHistories(namespace string) UpgradeHistoryInterface {
+	return newUpgradeHistories(c, namespace)
+}
+
 func (c *CloudweavhciV1beta1Client) UpgradeLogs(namespace string) UpgradeLogInterface {
 	return newUpgradeLogs(c, namespace)
 }
 
+func (c *CloudweavhciV1beta1Client) UpgradeReadinesses(namespace string) UpgradeReadinessInterface {
+	return newUpgradeReadinesses(c, namespace)
+}
+
 func (c *CloudweavhciV1beta1Client) Versions(namespace string) VersionInterface {
 	return newVersions(c, namespace)
 }
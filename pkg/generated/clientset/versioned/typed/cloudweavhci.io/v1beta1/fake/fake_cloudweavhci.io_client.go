@@ -36,6 +36,18 @@ func (c *FakeCloudweavhciV1beta1) KeyPairs(namespace string) v1beta1.KeyPairInte
 	return &FakeKeyPairs{c, namespace}
 }
 
+func (c *FakeCloudweavhciV1beta1) MaintenancePlans() v1beta1.MaintenancePlanInterface {
+	return &FakeMaintenancePlans{c}
+}
+
+func (c *FakeCloudweavhciV1beta1) NodeBMCs() v1beta1.NodeBMCInterface {
+	return &FakeNodeBMCs{c}
+}
+
+func (c *FakeCloudweavhciV1beta1) NodeHealths() v1beta1.NodeHealthInterface {
+	return &FakeNodeHealths{c}
+}
+
 func (c *FakeCloudweavhciV1beta1) Preferences(namespace string) v1beta1.PreferenceInterface {
 	return &FakePreferences{c, namespace}
 }
@@ -60,10 +72,18 @@ func (c *FakeCloudweavhciV1beta1) Upgrades(namespace string) v1beta1.UpgradeInte
 	return &FakeUpgrades{c, namespace}
 }
 
+func (c *FakeCloudweavhciV1beta1) UpgradeHistories(namespace string) v1beta1.UpgradeHistoryInterface {
+	return &FakeUpgradeHistories{c, namespace}
+}
+
 func (c *FakeCloudweavhciV1beta1) UpgradeLogs(namespace string) v1beta1.UpgradeLogInterface {
 	return &FakeUpgradeLogs{c, namespace}
 }
 
+func (c *FakeCloudweavhciV1beta1) UpgradeReadinesses(namespace string) v1beta1.UpgradeReadinessInterface {
+	return &FakeUpgradeReadinesses{c, namespace}
+}
+
 func (c *FakeCloudweavhciV1beta1) Versions(namespace string) v1beta1.VersionInterface {
 	return &FakeVersions{c, namespace}
 }
@@ -22,6 +22,12 @@ type AddonExpansion interface{}
 
 type KeyPairExpansion interface{}
 
+type MaintenancePlanExpansion interface{}
+
+type NodeBMCExpansion interface{}
+
+type NodeHealthExpansion interface{}
+
 type PreferenceExpansion interface{}
 
 type ResourceQuotaExpansion interface{}
@@ -34,8 +40,12 @@ type SupportBundleExpansion interface{}
 
 type UpgradeExpansion interface{}
 
+type UpgradeHistoryExpansion interface{}
+
 type UpgradeLogExpansion interface{}
 
+type UpgradeReadinessExpansion interface{}
+
 type VersionExpansion interface{}
 
 type VirtualMachineBackupExpansion interface{}
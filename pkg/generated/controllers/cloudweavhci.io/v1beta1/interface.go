@@ -33,13 +33,18 @@ func init() {
 type Interface interface {
 	Addon() AddonController
 	KeyPair() KeyPairController
+	MaintenancePlan() MaintenancePlanController
+	NodeBMC() NodeBMCController
+	NodeHealth() NodeHealthController
 	Preference() PreferenceController
 	ResourceQuota() ResourceQuotaController
 	ScheduleVMBackup() ScheduleVMBackupController
 	Setting() SettingController
 	SupportBundle() SupportBundleController
 	Upgrade() UpgradeController
+	UpgradeHistory() UpgradeHistoryController
 	UpgradeLog() UpgradeLogController
+	UpgradeReadiness() UpgradeReadinessController
 	Version() VersionController
 	VirtualMachineBackup() VirtualMachineBackupController
 	VirtualMachineImage() VirtualMachineImageController
@@ -66,6 +71,18 @@ func (v *version) KeyPair() KeyPairController {
 	return generic.NewController[*v1beta1.KeyPair, *v1beta1.KeyPairList](schema.GroupVersionKind{Group: "cloudweavhci.io", Version: "v1beta1", Kind: "KeyPair"}, "keypairs", true, v.controllerFactory)
 }
 
+func (v *version) MaintenancePlan() MaintenancePlanController {
+	return generic.NewNonNamespacedController[*v1beta1.MaintenancePlan, *v1beta1.MaintenancePlanList](schema.GroupVersionKind{Group: "cloudweavhci.io", Version: "v1beta1", Kind: "MaintenancePlan"}, "maintenanceplans", v.controllerFactory)
+}
+
+func (v *version) NodeBMC() NodeBMCController {
+	return generic.NewNonNamespacedController[*v1beta1.NodeBMC, *v1beta1.NodeBMCList](schema.GroupVersionKind{Group: "cloudweavhci.io", Version: "v1beta1", Kind: "NodeBMC"}, "nodebmcs", v.controllerFactory)
+}
+
+func (v *version) NodeHealth() NodeHealthController {
+	return generic.NewNonNamespacedController[*v1beta1.NodeHealth, *v1beta1.NodeHealthList](schema.GroupVersionKind{Group: "cloudweavhci.io", Version: "v1beta1", Kind: "NodeHealth"}, "nodehealths", v.controllerFactory)
+}
+
 func (v *version) Preference() PreferenceController {
 	return generic.NewController[*v1beta1.Preference, *v1beta1.PreferenceList](schema.GroupVersionKind{Group: "cloudweavhci.io", Version: "v1beta1", Kind: "Preference"}, "preferences", true, v.controllerFactory)
 }
@@ -90,10 +107,18 @@ func (v *version) Upgrade() UpgradeController {
 	return generic.NewController[*v1beta1.Upgrade, *v1beta1.UpgradeList](schema.GroupVersionKind{Group: "cloudweavhci.io", Version: "v1beta1", Kind: "Upgrade"}, "upgrades", true, v.controllerFactory)
 }
 
+func (v *version) UpgradeHistory() UpgradeHistoryController {
+	return generic.NewController[*v1beta1.UpgradeHistory, *v1beta1.UpgradeHistoryList](schema.GroupVersionKind{Group: "cloudweavhci.io", Version: "v1beta1", Kind: "UpgradeHistory"}, "upgradehistories", true, v.controllerFactory)
+}
+
 func (v *version) UpgradeLog() UpgradeLogController {
 	return generic.NewController[*v1beta1.UpgradeLog, *v1beta1.UpgradeLogList](schema.GroupVersionKind{Group: "cloudweavhci.io", Version: "v1beta1", Kind: "UpgradeLog"}, "upgradelogs", true, v.controllerFactory)
 }
 
+func (v *version) UpgradeReadiness() UpgradeReadinessController {
+	return generic.NewController[*v1beta1.UpgradeReadiness, *v1beta1.UpgradeReadinessList](schema.GroupVersionKind{Group: "cloudweavhci.io", Version: "v1beta1", Kind: "UpgradeReadiness"}, "upgradereadinesses", true, v.controllerFactory)
+}
+
 func (v *version) Version() VersionController {
 	return generic.NewController[*v1beta1.Version, *v1beta1.VersionList](schema.GroupVersionKind{Group: "cloudweavhci.io", Version: "v1beta1", Kind: "Version"}, "versions", true, v.controllerFactory)
 }
@@ -9,6 +9,7 @@ import (
 	lhv1beta2 "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
 	"github.com/rancher/steve/pkg/server"
 	corev1 "k8s.io/api/core/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
 
 	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
 	"github.com/cloudweav/cloudweav/pkg/config"
@@ -17,13 +18,16 @@ import (
 )
 
 const (
+	ImageByStorageClassNameIndex       = "cloudweavhci.io/image-by-storage-class-name"
 	PVCByDataSourceVolumeSnapshotIndex = "cloudweavhci.io/pvc-by-data-source-volume-snapshot"
+	PVCByStorageClassIndex             = "cloudweavhci.io/pvc-by-storage-class"
 	PodByNodeNameIndex                 = "cloudweavhci.io/pod-by-nodename"
 	PodByPVCIndex                      = "cloudweavhci.io/pod-by-pvc"
 	VolumeByNodeIndex                  = "cloudweavhci.io/volume-by-node"
 	VMBackupBySourceVMUIDIndex         = "cloudweavhci.io/vmbackup-by-source-vm-uid"
 	VMBackupBySourceVMNameIndex        = "cloudweavhci.io/vmbackup-by-source-vm-name"
 	VMTemplateVersionByImageIDIndex    = "cloudweavhci.io/vmtemplateversion-by-image-id"
+	VMByTemplateVersionIndex           = "cloudweavhci.io/vm-by-template-version"
 	VolumeSnapshotBySourcePVCIndex     = "cloudweavhci.io/volumesnapshot-by-source-pvc"
 )
 
@@ -33,6 +37,7 @@ func Setup(ctx context.Context, _ *server.Server, _ *server.Controllers, _ confi
 
 	pvcInformer := management.CoreFactory.Core().V1().PersistentVolumeClaim().Cache()
 	pvcInformer.AddIndexer(PVCByDataSourceVolumeSnapshotIndex, pvcByDataSourceVolumeSnapshot)
+	pvcInformer.AddIndexer(PVCByStorageClassIndex, PVCByStorageClass)
 
 	podInformer := management.CoreFactory.Core().V1().Pod().Cache()
 	podInformer.AddIndexer(PodByNodeNameIndex, PodByNodeName)
@@ -54,6 +59,12 @@ func Setup(ctx context.Context, _ *server.Server, _ *server.Controllers, _ confi
 
 	vmInformer := management.VirtFactory.Kubevirt().V1().VirtualMachine().Cache()
 	vmInformer.AddIndexer(indexeresutil.VMByPVCIndex, indexeresutil.VMByPVC)
+	vmInformer.AddIndexer(indexeresutil.VMByImageIDIndex, indexeresutil.VMByImageID)
+	vmInformer.AddIndexer(indexeresutil.VMByKeyPairIndex, indexeresutil.VMByKeyPair)
+	vmInformer.AddIndexer(VMByTemplateVersionIndex, VMByTemplateVersion)
+
+	vmImageInformer := management.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineImage().Cache()
+	vmImageInformer.AddIndexer(ImageByStorageClassNameIndex, ImageByStorageClassName)
 
 	scInformer := management.StorageFactory.Storage().V1().StorageClass().Cache()
 	scInformer.AddIndexer(indexeresutil.StorageClassBySecretIndex, indexeresutil.StorageClassBySecret)
@@ -74,6 +85,20 @@ func PodByPVC(obj *corev1.Pod) ([]string, error) {
 	return pvcNames, nil
 }
 
+func PVCByStorageClass(obj *corev1.PersistentVolumeClaim) ([]string, error) {
+	if obj.Spec.StorageClassName == nil || *obj.Spec.StorageClassName == "" {
+		return []string{}, nil
+	}
+	return []string{*obj.Spec.StorageClassName}, nil
+}
+
+func ImageByStorageClassName(obj *cloudweavv1.VirtualMachineImage) ([]string, error) {
+	if obj.Status.StorageClassName == "" {
+		return []string{}, nil
+	}
+	return []string{obj.Status.StorageClassName}, nil
+}
+
 func pvcByDataSourceVolumeSnapshot(obj *corev1.PersistentVolumeClaim) ([]string, error) {
 	if obj.Spec.DataSource == nil || obj.Spec.DataSource.Kind != "VolumeSnapshot" {
 		return []string{}, nil
@@ -114,6 +139,14 @@ func VMTemplateVersionByImageID(obj *cloudweavv1.VirtualMachineTemplateVersion)
 	return imageIDs, nil
 }
 
+func VMByTemplateVersion(obj *kubevirtv1.VirtualMachine) ([]string, error) {
+	versionID, ok := obj.Annotations[util.AnnotationTemplateVersionID]
+	if !ok || versionID == "" {
+		return []string{}, nil
+	}
+	return []string{versionID}, nil
+}
+
 func volumeSnapshotBySourcePVC(obj *snapshotv1.VolumeSnapshot) ([]string, error) {
 	if obj.Spec.Source.PersistentVolumeClaimName == nil {
 		return []string{}, nil
@@ -38,6 +38,7 @@ import (
 	"github.com/cloudweav/cloudweav/pkg/data"
 	"github.com/cloudweav/cloudweav/pkg/indexeres"
 	"github.com/cloudweav/cloudweav/pkg/server/ui"
+	"github.com/cloudweav/cloudweav/pkg/upgraderesponder"
 )
 
 type CloudweavServer struct {
@@ -249,7 +250,11 @@ func (s *CloudweavServer) generateSteveServer(options config.Options) error {
 	apiroot.Register(s.steve.BaseSchemas, []string{"v1", "v1/cloudweav"}, "proxy:/apis")
 
 	authMiddleware := steveauth.ToMiddleware(steveauth.AuthenticatorFunc(steveauth.AlwaysAdmin))
-	s.Handler = authMiddleware(s.steve)
+	// the upgrade responder is served to the other clusters of a fleet, which have no user in the cluster
+	publicRoutes := http.NewServeMux()
+	publicRoutes.Handle(upgraderesponder.CheckUpgradePath, upgraderesponder.NewHandler(upgraderesponder.SettingVersions))
+	publicRoutes.Handle("/", authMiddleware(s.steve))
+	s.Handler = publicRoutes
 
 	s.startHooks = []StartHook{
 		indexeres.Setup,
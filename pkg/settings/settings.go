@@ -20,7 +20,7 @@ var (
 	AdditionalCA                           = NewSetting(AdditionalCASettingName, "")
 	APIUIVersion                           = NewSetting("api-ui-version", "1.1.9") // Please update the CLOUDWEAV_API_UI_VERSION in package/Dockerfile when updating the version here.
 	ClusterRegistrationURL                 = NewSetting("cluster-registration-url", "")
-	ServerVersion                          = NewSetting("server-version", "dev")
+	ServerVersion                          = NewSetting(ServerVersionSettingName, "dev")
 	UIIndex                                = NewSetting(UIIndexSettingName, DefaultDashboardUIURL)
 	UIPath                                 = NewSetting(UIPathSettingName, "/usr/share/cloudweav/cloudweav")
 	UISource                               = NewSetting(UISourceSettingName, "auto") // Options are 'auto', 'external' or 'bundled'
@@ -58,6 +58,20 @@ var (
 	NTPServers             = NewSetting(NTPServersSettingName, "")
 	WhiteListedSettings    = []string{"server-version", "default-storage-class", "cloudweav-csi-ccm-versions", "default-vm-termination-grace-period-seconds"}
 	UpgradeConfigSet       = NewSetting(UpgradeConfigSettingName, `{"imagePreloadOption":{"strategy":{"type":"sequential"}}, "restoreVM": false}`)
+	ImageGCPolicySet       = NewSetting(ImageGCPolicySettingName, InitImageGCPolicy())
+	SSHCASet               = NewSetting(SSHCASettingName, InitSSHCAConfig())
+	NodeHealthPolicySet    = NewSetting(NodeHealthPolicySettingName, InitNodeHealthPolicy())
+	// ReleaseChannel is the channel of the versions synced from the upgrade checker, options are stable, latest, lts
+	// or empty for all channels
+	ReleaseChannel = NewSetting(ReleaseChannelSettingName, "")
+	// UpgradeCheckerTelemetry is the comma separated extra info sent to the upgrade checker, nothing is sent by default
+	UpgradeCheckerTelemetry = NewSetting(UpgradeCheckerTelemetrySettingName, "")
+	// UpgradeResponderVersions are the versions served to other clusters by the embedded upgrade responder, the
+	// responder is disabled if it's empty
+	UpgradeResponderVersions = NewSetting(UpgradeResponderVersionsSettingName, "")
+	// UpgradeISOTrustedKeys are the PEM encoded Ed25519 public keys which sign the uploaded upgrade ISOs, versions
+	// aren't created from uploaded ISOs if it's empty
+	UpgradeISOTrustedKeys = NewSetting(UpgradeISOTrustedKeysSettingName, "")
 )
 
 const (
@@ -93,6 +107,14 @@ const (
 	LonghornV2DataEngineSettingName                   = "longhorn-v2-data-engine-enabled"
 	LogLevelSettingName                               = "log-level"
 	AdditionalGuestMemoryOverheadRatioName            = "additional-guest-memory-overhead-ratio"
+	ImageGCPolicySettingName                          = "image-gc-policy"
+	SSHCASettingName                                  = "ssh-ca"
+	NodeHealthPolicySettingName                       = "node-health-policy"
+	ServerVersionSettingName                          = "server-version"
+	ReleaseChannelSettingName                         = "release-channel"
+	UpgradeCheckerTelemetrySettingName                = "upgrade-checker-telemetry"
+	UpgradeResponderVersionsSettingName               = "upgrade-responder-versions"
+	UpgradeISOTrustedKeysSettingName                  = "upgrade-iso-trusted-keys"
 
 	// settings have `default` and `value` string used in many places, replace them with const
 	KeywordDefault = "default"
@@ -3,6 +3,7 @@ package settings
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"reflect"
 	"strconv"
 
@@ -27,10 +28,43 @@ type BackupTarget struct {
 	VirtualHostedStyle bool       `json:"virtualHostedStyle"`
 }
 
+// DefaultVMPriorityIntervalSeconds is the default delay between the reset of the VMs of two priorities
+const DefaultVMPriorityIntervalSeconds = 30
+
 type VMForceResetPolicy struct {
 	Enable bool `json:"enable"`
 	// Period means how many seconds to wait for a node get back.
 	Period int64 `json:"period"`
+	// Fencing makes sure a down node is really dead before its VMs are force reset,
+	// so a partitioned node can't keep running the same VMs.
+	Fencing *NodeFencingPolicy `json:"fencing,omitempty"`
+	// DefaultPriority is the restart priority of the VMs without one in their HA policy.
+	DefaultPriority int `json:"defaultPriority,omitempty"`
+	// PriorityIntervalSeconds delays the reset of the VMs of each lower priority,
+	// so the VMs with a higher priority get the capacity of the surviving nodes first.
+	// It defaults to DefaultVMPriorityIntervalSeconds, 0 resets all the VMs at once.
+	PriorityIntervalSeconds int64 `json:"priorityIntervalSeconds"`
+}
+
+type NodeFencingMode string
+
+const (
+	NodeFencingModeNone    NodeFencingMode = ""
+	NodeFencingModeBMC     NodeFencingMode = "bmc"
+	NodeFencingModeWebhook NodeFencingMode = "webhook"
+)
+
+type NodeFencingPolicy struct {
+	// Mode is bmc to power off the node through its NodeBMC, webhook to call WebhookURL, or empty to skip fencing.
+	Mode NodeFencingMode `json:"mode,omitempty"`
+	// WebhookURL receives a POST request with the name of the node to fence, any 2xx response means the node is fenced.
+	WebhookURL string `json:"webhookURL,omitempty"`
+	// Retries is how many times a failed fencing attempt is retried.
+	Retries int `json:"retries,omitempty"`
+	// RetryIntervalSeconds is how many seconds to wait between two fencing attempts.
+	RetryIntervalSeconds int64 `json:"retryIntervalSeconds,omitempty"`
+	// ResetWithoutFencing force resets the VMs even when all the fencing attempts failed.
+	ResetWithoutFencing bool `json:"resetWithoutFencing,omitempty"`
 }
 
 func InitBackupTargetToString() string {
@@ -65,8 +99,9 @@ func (target *BackupTarget) IsDefaultBackupTarget() bool {
 
 func InitVMForceResetPolicy() string {
 	policy := &VMForceResetPolicy{
-		Enable: true,
-		Period: 5 * 60, // 5 minutes
+		Enable:                  true,
+		Period:                  5 * 60, // 5 minutes
+		PriorityIntervalSeconds: DefaultVMPriorityIntervalSeconds,
 	}
 	policyStr, err := json.Marshal(policy)
 	if err != nil {
@@ -76,7 +111,7 @@ func InitVMForceResetPolicy() string {
 }
 
 func DecodeVMForceResetPolicy(value string) (*VMForceResetPolicy, error) {
-	policy := &VMForceResetPolicy{}
+	policy := &VMForceResetPolicy{PriorityIntervalSeconds: DefaultVMPriorityIntervalSeconds}
 	if err := json.Unmarshal([]byte(value), policy); err != nil {
 		return nil, fmt.Errorf("unmarshal failed, error: %w, value: %s", err, value)
 	}
@@ -85,6 +120,181 @@ func DecodeVMForceResetPolicy(value string) (*VMForceResetPolicy, error) {
 		return nil, fmt.Errorf("period value should be greater than 0, value: %d", policy.Period)
 	}
 
+	if policy.PriorityIntervalSeconds < 0 {
+		return nil, fmt.Errorf("priorityIntervalSeconds value should not be less than 0, value: %d", policy.PriorityIntervalSeconds)
+	}
+
+	if policy.Fencing != nil {
+		if err := policy.Fencing.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
+
+func (fencing *NodeFencingPolicy) validate() error {
+	switch fencing.Mode {
+	case NodeFencingModeNone, NodeFencingModeBMC:
+	case NodeFencingModeWebhook:
+		if fencing.WebhookURL == "" {
+			return fmt.Errorf("fencing webhookURL is required with the %s mode", NodeFencingModeWebhook)
+		}
+		if u, err := url.Parse(fencing.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("fencing webhookURL should be a http or https URL, value: %s", fencing.WebhookURL)
+		}
+	default:
+		return fmt.Errorf("fencing mode should be %s or %s, value: %s", NodeFencingModeBMC, NodeFencingModeWebhook, fencing.Mode)
+	}
+	if fencing.Retries < 0 {
+		return fmt.Errorf("fencing retries value should not be less than 0, value: %d", fencing.Retries)
+	}
+	if fencing.RetryIntervalSeconds < 0 {
+		return fmt.Errorf("fencing retryIntervalSeconds value should not be less than 0, value: %d", fencing.RetryIntervalSeconds)
+	}
+	return nil
+}
+
+type ImageGCPolicy struct {
+	Enable bool `json:"enable"`
+	// UnusedDays means how many days an image must stay unreferenced before it is collected.
+	UnusedDays int `json:"unusedDays"`
+	// DryRun only reports the images that would be collected on their Unused condition.
+	DryRun bool `json:"dryRun"`
+	// Namespaces limits the collection to images in these namespaces, empty means all namespaces.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+func InitImageGCPolicy() string {
+	policy := &ImageGCPolicy{
+		Enable:     false,
+		UnusedDays: 30,
+		DryRun:     true,
+	}
+	policyStr, err := json.Marshal(policy)
+	if err != nil {
+		logrus.Errorf("failed to init %s, error: %s", ImageGCPolicySettingName, err.Error())
+	}
+	return string(policyStr)
+}
+
+func DecodeImageGCPolicy(value string) (*ImageGCPolicy, error) {
+	policy := &ImageGCPolicy{}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, fmt.Errorf("unmarshal failed, error: %w, value: %s", err, value)
+	}
+
+	if policy.UnusedDays <= 0 {
+		return nil, fmt.Errorf("unusedDays value should be greater than 0, value: %d", policy.UnusedDays)
+	}
+
+	return policy, nil
+}
+
+type SSHCAConfig struct {
+	// Enabled makes the cluster SSH certificate authority issue user and host certificates.
+	Enabled bool `json:"enabled"`
+	// DefaultValidMinutes is the validity of the user certificates when none is requested.
+	DefaultValidMinutes int `json:"defaultValidMinutes"`
+	// MaxValidMinutes caps the validity of the user certificates.
+	MaxValidMinutes int `json:"maxValidMinutes"`
+	// HostValidDays is the validity of the host certificates.
+	HostValidDays int `json:"hostValidDays"`
+}
+
+func InitSSHCAConfig() string {
+	config := &SSHCAConfig{
+		Enabled:             false,
+		DefaultValidMinutes: 60,
+		MaxValidMinutes:     480,
+		HostValidDays:       365,
+	}
+	configStr, err := json.Marshal(config)
+	if err != nil {
+		logrus.Errorf("failed to init %s, error: %s", SSHCASettingName, err.Error())
+	}
+	return string(configStr)
+}
+
+func DecodeSSHCAConfig(value string) (*SSHCAConfig, error) {
+	config := &SSHCAConfig{}
+	if err := json.Unmarshal([]byte(value), config); err != nil {
+		return nil, fmt.Errorf("unmarshal failed, error: %w, value: %s", err, value)
+	}
+
+	if config.DefaultValidMinutes <= 0 {
+		return nil, fmt.Errorf("defaultValidMinutes value should be greater than 0, value: %d", config.DefaultValidMinutes)
+	}
+	if config.MaxValidMinutes < config.DefaultValidMinutes {
+		return nil, fmt.Errorf("maxValidMinutes value should not be less than defaultValidMinutes, value: %d", config.MaxValidMinutes)
+	}
+	if config.HostValidDays <= 0 {
+		return nil, fmt.Errorf("hostValidDays value should be greater than 0, value: %d", config.HostValidDays)
+	}
+
+	return config, nil
+}
+
+// NodeHealthPolicy are the thresholds of the hardware health reported in the NodeHealth of the nodes. A zero threshold
+// disables the check.
+type NodeHealthPolicy struct {
+	// Evacuate cordons the node and live migrates its VMs when a threshold is crossed.
+	Evacuate bool `json:"evacuate"`
+	// ReallocatedSectors is the number of reallocated sectors of a disk.
+	ReallocatedSectors int64 `json:"reallocatedSectors"`
+	// PendingSectors is the number of sectors of a disk waiting to be reallocated.
+	PendingSectors int64 `json:"pendingSectors"`
+	// MediaErrors is the number of media errors of a NVMe disk.
+	MediaErrors int64 `json:"mediaErrors"`
+	// CorrectableMemoryErrors is the number of corrected ECC memory errors.
+	CorrectableMemoryErrors int64 `json:"correctableMemoryErrors"`
+	// UncorrectableMemoryErrors is the number of uncorrected ECC memory errors.
+	UncorrectableMemoryErrors int64 `json:"uncorrectableMemoryErrors"`
+	// LinkFlaps is the number of link state changes of a network interface between two reports.
+	LinkFlaps int64 `json:"linkFlaps"`
+	// TemperatureCelsius is the temperature of a sensor or a disk.
+	TemperatureCelsius int64 `json:"temperatureCelsius"`
+}
+
+func InitNodeHealthPolicy() string {
+	policy := &NodeHealthPolicy{
+		Evacuate:                  false,
+		ReallocatedSectors:        10,
+		PendingSectors:            1,
+		MediaErrors:               1,
+		CorrectableMemoryErrors:   100,
+		UncorrectableMemoryErrors: 1,
+		LinkFlaps:                 10,
+		TemperatureCelsius:        90,
+	}
+	policyStr, err := json.Marshal(policy)
+	if err != nil {
+		logrus.Errorf("failed to init %s, error: %s", NodeHealthPolicySettingName, err.Error())
+	}
+	return string(policyStr)
+}
+
+func DecodeNodeHealthPolicy(value string) (*NodeHealthPolicy, error) {
+	policy := &NodeHealthPolicy{}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, fmt.Errorf("unmarshal failed, error: %w, value: %s", err, value)
+	}
+
+	thresholds := map[string]int64{
+		"reallocatedSectors":        policy.ReallocatedSectors,
+		"pendingSectors":            policy.PendingSectors,
+		"mediaErrors":               policy.MediaErrors,
+		"correctableMemoryErrors":   policy.CorrectableMemoryErrors,
+		"uncorrectableMemoryErrors": policy.UncorrectableMemoryErrors,
+		"linkFlaps":                 policy.LinkFlaps,
+		"temperatureCelsius":        policy.TemperatureCelsius,
+	}
+	for name, threshold := range thresholds {
+		if threshold < 0 {
+			return nil, fmt.Errorf("%s value should not be less than 0, value: %d", name, threshold)
+		}
+	}
+
 	return policy, nil
 }
 
@@ -150,6 +360,11 @@ const (
 
 	// Preloading multiple nodes starts at the same time
 	ParallelType StrategyType = "parallel"
+
+	// Preloading starts on one node and scales with the image pull
+	// throughput, nodes short of disk space are paused. The upgrade fails if
+	// a node stays paused for more than an hour.
+	AdaptiveType StrategyType = "adaptive"
 )
 
 type PreloadStrategy struct {
@@ -158,8 +373,16 @@ type PreloadStrategy struct {
 	// Concurrency only takes effect when ParallelType is specified. Default to
 	// 0, which means "full scale." Any value higher than the number of the
 	// cluster nodes will be treated as 0; values lower than 0 will be rejected
-	// by the validator.
+	// by the validator. With AdaptiveType, it's the highest concurrency the
+	// preloading scales up to.
 	Concurrency int `json:"concurrency,omitempty"`
+
+	// MinFreeDiskSpaceGB only takes effect when AdaptiveType is specified.
+	// Preloading is paused on the nodes with less free disk space, or under
+	// disk pressure. Default to 0, which means 30GB. A node which started
+	// preloading is paused once it's under disk pressure or has less than
+	// 5GB left, and resumed once it has this much free space again.
+	MinFreeDiskSpaceGB int `json:"minFreeDiskSpaceGB,omitempty"`
 }
 
 type ImagePreloadOption struct {
@@ -167,9 +390,27 @@ type ImagePreloadOption struct {
 	Strategy PreloadStrategy `json:"strategy,omitempty"`
 }
 
+type RepoProviderType string
+
+const (
+	// The upgrade repo is served by a VM booted from the upgrade ISO
+	VMRepoProvider RepoProviderType = "vm"
+
+	// The upgrade repo is served by lightweight HTTP server pods mounting
+	// the upgrade ISO read-only, they need far less memory than the VM
+	PodRepoProvider RepoProviderType = "pod"
+)
+
+type RepoOption struct {
+	// Provider is the way the upgrade repo is served. Default to "vm".
+	Provider RepoProviderType `json:"provider,omitempty"`
+}
+
 type UpgradeConfig struct {
 	// Options for the Image Preload phase of Cloudweav Upgrade
 	PreloadOption ImagePreloadOption `json:"imagePreloadOption,omitempty"`
+	// Options for the upgrade repo serving the upgrade ISO
+	RepoOption RepoOption `json:"repoOption,omitempty"`
 	// set true to restore vm to the pre-upgrade state, this option only works under single node.
 	RestoreVM bool `json:"restoreVM,omitempty"`
 }
@@ -0,0 +1,60 @@
+// Package diagnostics collects the state of upgrades for support. The collectors only read the cluster, except the
+// orphan cleanup which deletes the orphans unless it's a dry run.
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+const (
+	upgradeLabel             = "cloudweavhci.io/upgrade"
+	upgradeStateLabel        = "cloudweavhci.io/upgradeState"
+	latestUpgradeLabel       = "cloudweavhci.io/latestUpgrade"
+	upgradeComponentLabel    = "cloudweavhci.io/upgradeComponent"
+	pendingOSImageAnnotation = "cloudweavhci.io/pendingOSImage"
+	planLabel                = "upgrade.cattle.io/plan"
+	planNodeLabel            = "upgrade.cattle.io/node"
+
+	upgradeStateSucceeded = "Succeeded"
+	upgradeStateFailed    = "Failed"
+	cleanupComponent      = "cleanup"
+)
+
+// Clients are the clients the collectors read the cluster with
+type Clients struct {
+	Kube      kubernetes.Interface
+	Cloudweav versioned.Interface
+}
+
+// getUpgrade returns the upgrade, or the latest upgrade if the name is empty
+func getUpgrade(ctx context.Context, clients *Clients, name string) (*cloudweavv1.Upgrade, error) {
+	upgrades := clients.Cloudweav.CloudweavhciV1beta1().Upgrades(util.CloudweavSystemNamespaceName)
+	if name != "" {
+		return upgrades.Get(ctx, name, metav1.GetOptions{})
+	}
+
+	list, err := upgrades.List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{latestUpgradeLabel: "true"}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("no upgrade is found")
+	}
+	return &list.Items[0], nil
+}
+
+func isUpgradeCompleted(upgrade *cloudweavv1.Upgrade) bool {
+	state := upgrade.Labels[upgradeStateLabel]
+	return state == upgradeStateSucceeded || state == upgradeStateFailed
+}
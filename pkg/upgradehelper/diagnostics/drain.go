@@ -0,0 +1,183 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/cloudweav/cloudweav/pkg/util/virtualmachineinstance"
+)
+
+const (
+	// DrainActionIgnore is for the pods the drain leaves on the node, like the pods of daemon sets
+	DrainActionIgnore = "ignore"
+	// DrainActionEvict is for the pods evicted by the drain
+	DrainActionEvict = "evict"
+	// DrainActionMigrate is for the VMs live migrated to the other nodes
+	DrainActionMigrate = "migrate"
+	// DrainActionShutdown is for the VMs which can't be live migrated, the upgrade shuts them down before the drain
+	DrainActionShutdown = "shutdown"
+	// DrainActionBlocked is for the pods whose eviction is blocked by a pod disruption budget
+	DrainActionBlocked = "blocked"
+
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+	virtLauncherLabel   = "kubevirt.io"
+	virtLauncherValue   = "virt-launcher"
+)
+
+// DrainInput is what the drain simulation inspects
+type DrainInput struct {
+	Node  string
+	Nodes []*corev1.Node
+	Pods  []*corev1.Pod
+	PDBs  []*policyv1.PodDisruptionBudget
+	VMIs  []*kubevirtv1.VirtualMachineInstance
+}
+
+type DrainPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Action    string `json:"action"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// DrainReport tells what draining the node for the upgrade does to its pods
+type DrainReport struct {
+	Node string     `json:"node"`
+	Pods []DrainPod `json:"pods"`
+	// Blocked is true if the drain can't complete without intervention
+	Blocked bool `json:"blocked"`
+}
+
+// CollectDrainInput reads what the drain simulation of the node inspects
+func CollectDrainInput(ctx context.Context, clients *Clients, nodeName string) (*DrainInput, error) {
+	input := &DrainInput{Node: nodeName}
+
+	nodes, err := clients.Kube.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for i := range nodes.Items {
+		input.Nodes = append(input.Nodes, &nodes.Items[i])
+		found = found || nodes.Items[i].Name == nodeName
+	}
+	if !found {
+		return nil, fmt.Errorf("node %s is not found", nodeName)
+	}
+
+	pods, err := clients.Kube.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		input.Pods = append(input.Pods, &pods.Items[i])
+	}
+
+	pdbs, err := clients.Kube.PolicyV1().PodDisruptionBudgets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range pdbs.Items {
+		input.PDBs = append(input.PDBs, &pdbs.Items[i])
+	}
+
+	vmis, err := clients.Cloudweav.KubevirtV1().VirtualMachineInstances(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{kubevirtv1.NodeNameLabel: nodeName}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range vmis.Items {
+		input.VMIs = append(input.VMIs, &vmis.Items[i])
+	}
+	return input, nil
+}
+
+// SimulateDrain tells what the drain of the upgrade does to the pods of the node. The upgrade drains nodes ignoring
+// daemon sets and deleting the data of empty dirs, so only the pod disruption budgets can block the evictions.
+func SimulateDrain(input *DrainInput) (*DrainReport, error) {
+	report := &DrainReport{
+		Node: input.Node,
+		Pods: []DrainPod{},
+	}
+
+	nonMigratable, err := virtualmachineinstance.GetAllNonLiveMigratableVMINames(input.VMIs, input.Nodes)
+	if err != nil {
+		return nil, err
+	}
+	shutdown := make(map[string]bool, len(nonMigratable))
+	for _, name := range nonMigratable {
+		shutdown[name] = true
+	}
+
+	for _, pod := range input.Pods {
+		result := DrainPod{Namespace: pod.Namespace, Name: pod.Name}
+		switch {
+		case pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed:
+			result.Action, result.Reason = DrainActionIgnore, "pod is completed"
+		case pod.Annotations[mirrorPodAnnotation] != "":
+			result.Action, result.Reason = DrainActionIgnore, "static pod"
+		case isDaemonSetPod(pod):
+			result.Action, result.Reason = DrainActionIgnore, "daemon set pod"
+		case pod.Labels[virtLauncherLabel] == virtLauncherValue:
+			vmName := fmt.Sprintf("%s/%s", pod.Namespace, pod.Labels[kubevirtv1.VirtualMachineNameLabel])
+			if shutdown[vmName] {
+				result.Action, result.Reason = DrainActionShutdown, fmt.Sprintf("VM %s can't be live migrated", vmName)
+			} else {
+				result.Action, result.Reason = DrainActionMigrate, fmt.Sprintf("VM %s is live migrated", vmName)
+			}
+		default:
+			if pdb := blockingPDB(pod, input.PDBs); pdb != nil {
+				result.Action, result.Reason = DrainActionBlocked, fmt.Sprintf("pod disruption budget %s allows no disruption", pdb.Name)
+				report.Blocked = true
+			} else {
+				result.Action = DrainActionEvict
+			}
+		}
+		report.Pods = append(report.Pods, result)
+	}
+
+	sort.Slice(report.Pods, func(i, j int) bool {
+		if report.Pods[i].Namespace != report.Pods[j].Namespace {
+			return report.Pods[i].Namespace < report.Pods[j].Namespace
+		}
+		return report.Pods[i].Name < report.Pods[j].Name
+	})
+	return report, nil
+}
+
+func isDaemonSetPod(pod *corev1.Pod) bool {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// blockingPDB returns the pod disruption budget of the pod which allows no disruption
+func blockingPDB(pod *corev1.Pod, pdbs []*policyv1.PodDisruptionBudget) *policyv1.PodDisruptionBudget {
+	for _, pdb := range pdbs {
+		if pdb.Namespace != pod.Namespace || pdb.Status.DisruptionsAllowed > 0 {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			return pdb
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package diagnostics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+)
+
+func TestSimulateDrain(t *testing.T) {
+	newPod := func(namespace, name string, podLabels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: podLabels},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	daemonPod := newPod("kube-system", "canal-abcde", nil)
+	daemonPod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "canal"}}
+	completedPod := newPod("default", "job-abcde", nil)
+	completedPod.Status.Phase = corev1.PodSucceeded
+
+	input := &DrainInput{
+		Node: "node1",
+		Nodes: []*corev1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+		},
+		Pods: []*corev1.Pod{
+			daemonPod,
+			completedPod,
+			newPod("default", "virt-launcher-vm1-abcde", map[string]string{virtLauncherLabel: virtLauncherValue, kubevirtv1.VirtualMachineNameLabel: "vm1"}),
+			newPod("default", "virt-launcher-vm2-abcde", map[string]string{virtLauncherLabel: virtLauncherValue, kubevirtv1.VirtualMachineNameLabel: "vm2"}),
+			newPod("default", "web-abcde", map[string]string{"app": "web"}),
+			newPod("default", "db-abcde", map[string]string{"app": "db"}),
+		},
+		PDBs: []*policyv1.PodDisruptionBudget{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db"},
+				Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+				Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+				Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+				Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+			},
+		},
+		VMIs: []*kubevirtv1.VirtualMachineInstance{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "vm1"}, Status: kubevirtv1.VirtualMachineInstanceStatus{NodeName: "node1"}},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "vm2"},
+				Spec:       kubevirtv1.VirtualMachineInstanceSpec{NodeSelector: map[string]string{"kubernetes.io/hostname": "node1"}},
+				Status:     kubevirtv1.VirtualMachineInstanceStatus{NodeName: "node1"},
+			},
+		},
+	}
+
+	report, err := SimulateDrain(input)
+	require.NoError(t, err)
+	assert.True(t, report.Blocked)
+
+	actions := make(map[string]string, len(report.Pods))
+	for _, pod := range report.Pods {
+		actions[pod.Namespace+"/"+pod.Name] = pod.Action
+	}
+	assert.Equal(t, map[string]string{
+		"kube-system/canal-abcde":         DrainActionIgnore,
+		"default/job-abcde":               DrainActionIgnore,
+		"default/virt-launcher-vm1-abcde": DrainActionMigrate,
+		"default/virt-launcher-vm2-abcde": DrainActionShutdown,
+		"default/web-abcde":               DrainActionEvict,
+		"default/db-abcde":                DrainActionBlocked,
+	}, actions)
+	assert.Equal(t, "default", report.Pods[0].Namespace, "pods are sorted")
+}
@@ -0,0 +1,193 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+
+	upgradev1 "github.com/rancher/system-upgrade-controller/pkg/apis/upgrade.cattle.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+const (
+	KindVirtualMachine      = "VirtualMachine"
+	KindVirtualMachineImage = "VirtualMachineImage"
+	KindPlan                = "Plan"
+
+	repoComponent = "repo"
+)
+
+// OrphanInput is what the orphan cleanup inspects, the objects carry the label of the upgrade they were created for
+type OrphanInput struct {
+	Upgrades []cloudweavv1.Upgrade
+	VMs      []kubevirtv1.VirtualMachine
+	Images   []cloudweavv1.VirtualMachineImage
+	Plans    []upgradev1.Plan
+}
+
+type Orphan struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Upgrade   string `json:"upgrade"`
+	Reason    string `json:"reason"`
+	Deleted   bool   `json:"deleted"`
+	Error     string `json:"error,omitempty"`
+}
+
+// OrphansReport lists the objects the upgrades left behind, they are not deleted on a dry run
+type OrphansReport struct {
+	DryRun  bool     `json:"dryRun"`
+	Orphans []Orphan `json:"orphans"`
+}
+
+// CollectOrphanInput reads the upgrades and the objects labelled with an upgrade
+func CollectOrphanInput(ctx context.Context, clients *Clients) (*OrphanInput, error) {
+	input := &OrphanInput{}
+	upgraded, err := labels.NewRequirement(upgradeLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	upgradeSelector := labels.NewSelector().Add(*upgraded).String()
+
+	upgrades, err := clients.Cloudweav.CloudweavhciV1beta1().Upgrades(util.CloudweavSystemNamespaceName).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	input.Upgrades = upgrades.Items
+
+	vms, err := clients.Cloudweav.KubevirtV1().VirtualMachines(util.CloudweavSystemNamespaceName).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{upgradeComponentLabel: repoComponent}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	input.VMs = vms.Items
+
+	images, err := clients.Cloudweav.CloudweavhciV1beta1().VirtualMachineImages(util.CloudweavSystemNamespaceName).List(ctx, metav1.ListOptions{
+		LabelSelector: upgradeSelector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	input.Images = images.Items
+
+	plans, err := clients.Cloudweav.UpgradeV1().Plans(sucNamespace).List(ctx, metav1.ListOptions{LabelSelector: upgradeSelector})
+	if err != nil {
+		return nil, err
+	}
+	input.Plans = plans.Items
+	return input, nil
+}
+
+// FindOrphans returns the repo VMs, the repo images and the plans of the upgrades which are gone or completed. The
+// upgrade removes them when it completes, so they are only left behind if the cleanup was interrupted.
+func FindOrphans(input *OrphanInput) []Orphan {
+	upgrades := make(map[string]*cloudweavv1.Upgrade, len(input.Upgrades))
+	for i := range input.Upgrades {
+		upgrades[input.Upgrades[i].Name] = &input.Upgrades[i]
+	}
+
+	orphans := []Orphan{}
+	add := func(kind string, meta metav1.ObjectMeta) {
+		upgradeName := meta.Labels[upgradeLabel]
+		if upgradeName == "" {
+			return
+		}
+		reason, ok := orphanReason(upgrades, upgradeName)
+		if !ok {
+			return
+		}
+		orphans = append(orphans, Orphan{
+			Kind:      kind,
+			Namespace: meta.Namespace,
+			Name:      meta.Name,
+			Upgrade:   upgradeName,
+			Reason:    reason,
+		})
+	}
+
+	for _, vm := range input.VMs {
+		add(KindVirtualMachine, vm.ObjectMeta)
+	}
+	for _, image := range input.Images {
+		// the image is garbage collected with the PVC of the repo VM
+		if hasPVCOwner(image.OwnerReferences) {
+			continue
+		}
+		add(KindVirtualMachineImage, image.ObjectMeta)
+	}
+	for _, plan := range input.Plans {
+		if isPurgingImages(&plan) {
+			continue
+		}
+		add(KindPlan, plan.ObjectMeta)
+	}
+	return orphans
+}
+
+func orphanReason(upgrades map[string]*cloudweavv1.Upgrade, upgradeName string) (string, bool) {
+	upgrade, ok := upgrades[upgradeName]
+	if !ok {
+		return fmt.Sprintf("upgrade %s doesn't exist", upgradeName), true
+	}
+	if isUpgradeCompleted(upgrade) {
+		return fmt.Sprintf("upgrade %s is completed", upgradeName), true
+	}
+	return "", false
+}
+
+// isPurgingImages tells if the plan still purges the images of the previous version, it runs after the upgrade
+func isPurgingImages(plan *upgradev1.Plan) bool {
+	return plan.Labels[upgradeComponentLabel] == cleanupComponent && len(plan.Status.Applying) > 0
+}
+
+func hasPVCOwner(owners []metav1.OwnerReference) bool {
+	for _, owner := range owners {
+		if owner.Kind == "PersistentVolumeClaim" {
+			return true
+		}
+	}
+	return false
+}
+
+// CleanupOrphans finds the orphans and deletes them unless it's a dry run. The VMs are deleted first because their
+// PVCs are based on the images. A failed deletion is reported on the orphan and doesn't stop the others.
+func CleanupOrphans(ctx context.Context, clients *Clients, dryRun bool) (*OrphansReport, error) {
+	input, err := CollectOrphanInput(ctx, clients)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &OrphansReport{
+		DryRun:  dryRun,
+		Orphans: FindOrphans(input),
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	for i := range report.Orphans {
+		orphan := &report.Orphans[i]
+		var err error
+		switch orphan.Kind {
+		case KindVirtualMachine:
+			err = clients.Cloudweav.KubevirtV1().VirtualMachines(orphan.Namespace).Delete(ctx, orphan.Name, metav1.DeleteOptions{})
+		case KindVirtualMachineImage:
+			err = clients.Cloudweav.CloudweavhciV1beta1().VirtualMachineImages(orphan.Namespace).Delete(ctx, orphan.Name, metav1.DeleteOptions{})
+		case KindPlan:
+			err = clients.Cloudweav.UpgradeV1().Plans(orphan.Namespace).Delete(ctx, orphan.Name, metav1.DeleteOptions{})
+		}
+		if err != nil {
+			orphan.Error = err.Error()
+			continue
+		}
+		orphan.Deleted = true
+	}
+	return report, nil
+}
@@ -0,0 +1,49 @@
+package diagnostics
+
+import (
+	"testing"
+
+	upgradev1 "github.com/rancher/system-upgrade-controller/pkg/apis/upgrade.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+func TestFindOrphans(t *testing.T) {
+	newMeta := func(name, upgradeName string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: "cloudweav-system", Name: name, Labels: map[string]string{upgradeLabel: upgradeName}}
+	}
+	cleanupPlan := upgradev1.Plan{ObjectMeta: newMeta("hvst-upgrade-done-cleanup", "hvst-upgrade-done")}
+	cleanupPlan.Labels[upgradeComponentLabel] = cleanupComponent
+	cleanupPlan.Status.Applying = []string{"node1"}
+	gcImage := cloudweavv1.VirtualMachineImage{ObjectMeta: newMeta("hvst-upgrade-done", "hvst-upgrade-done")}
+	gcImage.OwnerReferences = []metav1.OwnerReference{{Kind: "PersistentVolumeClaim", Name: "upgrade-repo-hvst-upgrade-done-disk-0"}}
+
+	input := &OrphanInput{
+		Upgrades: []cloudweavv1.Upgrade{
+			{ObjectMeta: metav1.ObjectMeta{Name: "hvst-upgrade-running", Labels: map[string]string{upgradeStateLabel: "UpgradingNodes"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "hvst-upgrade-done", Labels: map[string]string{upgradeStateLabel: upgradeStateSucceeded}}},
+		},
+		VMs: []kubevirtv1.VirtualMachine{
+			{ObjectMeta: newMeta("upgrade-repo-hvst-upgrade-running", "hvst-upgrade-running")},
+			{ObjectMeta: newMeta("upgrade-repo-hvst-upgrade-gone", "hvst-upgrade-gone")},
+		},
+		Images: []cloudweavv1.VirtualMachineImage{
+			gcImage,
+			{ObjectMeta: newMeta("hvst-upgrade-gone", "hvst-upgrade-gone")},
+		},
+		Plans: []upgradev1.Plan{
+			cleanupPlan,
+			{ObjectMeta: newMeta("hvst-upgrade-done-prepare", "hvst-upgrade-done")},
+			{ObjectMeta: newMeta("hvst-upgrade-running-prepare", "hvst-upgrade-running")},
+		},
+	}
+
+	assert.Equal(t, []Orphan{
+		{Kind: KindVirtualMachine, Namespace: "cloudweav-system", Name: "upgrade-repo-hvst-upgrade-gone", Upgrade: "hvst-upgrade-gone", Reason: "upgrade hvst-upgrade-gone doesn't exist"},
+		{Kind: KindVirtualMachineImage, Namespace: "cloudweav-system", Name: "hvst-upgrade-gone", Upgrade: "hvst-upgrade-gone", Reason: "upgrade hvst-upgrade-gone doesn't exist"},
+		{Kind: KindPlan, Namespace: "cloudweav-system", Name: "hvst-upgrade-done-prepare", Upgrade: "hvst-upgrade-done", Reason: "upgrade hvst-upgrade-done is completed"},
+	}, FindOrphans(input))
+}
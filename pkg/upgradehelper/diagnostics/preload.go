@@ -0,0 +1,153 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+const (
+	sucNamespace = "cattle-system"
+
+	nodeStateImagesPreloading = "Images preloading"
+	nodeStateFailed           = "Failed"
+
+	JobStatusPending   = "Pending"
+	JobStatusRunning   = "Running"
+	JobStatusSucceeded = "Succeeded"
+	JobStatusFailed    = "Failed"
+)
+
+type NodePreloadStatus struct {
+	Node           string `json:"node"`
+	State          string `json:"state,omitempty"`
+	Job            string `json:"job,omitempty"`
+	JobStatus      string `json:"jobStatus"`
+	StartTime      string `json:"startTime,omitempty"`
+	CompletionTime string `json:"completionTime,omitempty"`
+	// Duration is in seconds, it's up to now if the job is still running
+	Duration *int64 `json:"durationSeconds,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// PreloadReport is the progress of the image preloading of an upgrade
+type PreloadReport struct {
+	Upgrade   string              `json:"upgrade"`
+	Plan      string              `json:"plan"`
+	Nodes     []NodePreloadStatus `json:"nodes"`
+	Preloaded int                 `json:"preloaded"`
+}
+
+// CollectPreloadStatus reads the upgrade and the jobs of its prepare plan
+func CollectPreloadStatus(ctx context.Context, clients *Clients, upgradeName string) (*PreloadReport, error) {
+	upgrade, err := getUpgrade(ctx, clients, upgradeName)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := clients.Kube.BatchV1().Jobs(sucNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{planLabel: preparePlanName(upgrade)}.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return PreloadStatus(upgrade, jobs.Items, time.Now()), nil
+}
+
+func preparePlanName(upgrade *cloudweavv1.Upgrade) string {
+	return fmt.Sprintf("%s-prepare", upgrade.Name)
+}
+
+// PreloadStatus merges the node states of the upgrade with the jobs of the prepare plan. A node can have several jobs
+// if the plan was retried, the latest one is reported.
+func PreloadStatus(upgrade *cloudweavv1.Upgrade, jobs []batchv1.Job, now time.Time) *PreloadReport {
+	report := &PreloadReport{
+		Upgrade: upgrade.Name,
+		Plan:    preparePlanName(upgrade),
+		Nodes:   []NodePreloadStatus{},
+	}
+
+	latestJobs := make(map[string]*batchv1.Job, len(jobs))
+	for i := range jobs {
+		job := &jobs[i]
+		node := job.Labels[planNodeLabel]
+		if node == "" {
+			continue
+		}
+		if latest, ok := latestJobs[node]; !ok || latest.CreationTimestamp.Before(&job.CreationTimestamp) {
+			latestJobs[node] = job
+		}
+	}
+
+	nodes := make(map[string]bool, len(upgrade.Status.NodeStatuses)+len(latestJobs))
+	for node := range upgrade.Status.NodeStatuses {
+		nodes[node] = true
+	}
+	for node := range latestJobs {
+		nodes[node] = true
+	}
+
+	for node := range nodes {
+		nodeStatus := upgrade.Status.NodeStatuses[node]
+		status := NodePreloadStatus{
+			Node:      node,
+			State:     nodeStatus.State,
+			JobStatus: JobStatusPending,
+			Message:   nodeStatus.Message,
+		}
+		if job, ok := latestJobs[node]; ok {
+			status.Job = job.Name
+			status.JobStatus = jobStatus(job)
+			if job.Status.StartTime != nil {
+				status.StartTime = job.Status.StartTime.UTC().Format(time.RFC3339)
+				end := now
+				if job.Status.CompletionTime != nil {
+					status.CompletionTime = job.Status.CompletionTime.UTC().Format(time.RFC3339)
+					end = job.Status.CompletionTime.Time
+				}
+				duration := int64(end.Sub(job.Status.StartTime.Time).Seconds())
+				status.Duration = &duration
+			}
+		}
+		if status.JobStatus == JobStatusSucceeded || isPreloaded(nodeStatus.State) {
+			report.Preloaded++
+		}
+		report.Nodes = append(report.Nodes, status)
+	}
+
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		return report.Nodes[i].Node < report.Nodes[j].Node
+	})
+	return report
+}
+
+// isPreloaded tells if the state of the node moved on from the preloading, the jobs may be gone then
+func isPreloaded(state string) bool {
+	return state != "" && state != nodeStateImagesPreloading && state != nodeStateFailed
+}
+
+func jobStatus(job *batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return JobStatusSucceeded
+		case batchv1.JobFailed:
+			return JobStatusFailed
+		}
+	}
+	if job.Status.Active > 0 {
+		return JobStatusRunning
+	}
+	return JobStatusPending
+}
@@ -0,0 +1,63 @@
+package diagnostics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+func TestPreloadStatus(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC)
+	upgrade := &cloudweavv1.Upgrade{
+		ObjectMeta: metav1.ObjectMeta{Name: "hvst-upgrade-abcde"},
+		Status: cloudweavv1.UpgradeStatus{NodeStatuses: map[string]cloudweavv1.NodeUpgradeStatus{
+			"node1": {State: nodeStateImagesPreloading},
+			"node2": {State: nodeStateImagesPreloading},
+			"node3": {State: "Pre-drained"},
+			"node4": {State: nodeStateImagesPreloading},
+		}},
+	}
+	newJob := func(name, node string, created time.Time, status batchv1.JobStatus) batchv1.Job {
+		return batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Labels:            map[string]string{planLabel: "hvst-upgrade-abcde-prepare", planNodeLabel: node},
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Status: status,
+		}
+	}
+	start := metav1.NewTime(now.Add(-10 * time.Minute))
+	completion := metav1.NewTime(now.Add(-5 * time.Minute))
+	jobs := []batchv1.Job{
+		newJob("prepare-node1-old", "node1", start.Add(-time.Hour), batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}},
+		}),
+		newJob("prepare-node1", "node1", start.Time, batchv1.JobStatus{
+			StartTime:      &start,
+			CompletionTime: &completion,
+			Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+		}),
+		newJob("prepare-node2", "node2", start.Time, batchv1.JobStatus{StartTime: &start, Active: 1}),
+	}
+
+	report := PreloadStatus(upgrade, jobs, now)
+	assert.Equal(t, "hvst-upgrade-abcde-prepare", report.Plan)
+	assert.Equal(t, 2, report.Preloaded, "node3 moved on after its job is gone")
+	require.Len(t, report.Nodes, 4)
+
+	assert.Equal(t, "prepare-node1", report.Nodes[0].Job, "latest job is reported")
+	assert.Equal(t, JobStatusSucceeded, report.Nodes[0].JobStatus)
+	assert.EqualValues(t, 300, *report.Nodes[0].Duration)
+	assert.Equal(t, JobStatusRunning, report.Nodes[1].JobStatus)
+	assert.EqualValues(t, 600, *report.Nodes[1].Duration, "running job lasts up to now")
+	assert.Equal(t, JobStatusPending, report.Nodes[3].JobStatus)
+	assert.Nil(t, report.Nodes[3].Duration)
+}
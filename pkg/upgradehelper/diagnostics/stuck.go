@@ -0,0 +1,196 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	upgradev1 "github.com/rancher/system-upgrade-controller/pkg/apis/upgrade.cattle.io/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+const (
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+
+	// a condition or a job taking longer than this is reported as slow
+	slowThreshold = time.Hour
+
+	logReadyDisabledReason = "Disabled"
+)
+
+// StuckInput is what the diagnosis of an upgrade inspects
+type StuckInput struct {
+	Upgrade *cloudweavv1.Upgrade
+	Plans   []upgradev1.Plan
+	Jobs    []batchv1.Job
+	Nodes   []corev1.Node
+}
+
+type Finding struct {
+	Severity string `json:"severity"`
+	Object   string `json:"object"`
+	Message  string `json:"message"`
+}
+
+// StuckReport is the diagnosis of an upgrade, the findings are sorted by severity
+type StuckReport struct {
+	Upgrade  string    `json:"upgrade"`
+	State    string    `json:"state,omitempty"`
+	Findings []Finding `json:"findings"`
+}
+
+// CollectStuckInput reads the upgrade, its plans and jobs, and the nodes
+func CollectStuckInput(ctx context.Context, clients *Clients, upgradeName string) (*StuckInput, error) {
+	upgrade, err := getUpgrade(ctx, clients, upgradeName)
+	if err != nil {
+		return nil, err
+	}
+	input := &StuckInput{Upgrade: upgrade}
+
+	upgradeSelector := labels.Set{upgradeLabel: upgrade.Name}.String()
+	plans, err := clients.Cloudweav.UpgradeV1().Plans(sucNamespace).List(ctx, metav1.ListOptions{LabelSelector: upgradeSelector})
+	if err != nil {
+		return nil, err
+	}
+	input.Plans = plans.Items
+
+	jobs, err := clients.Kube.BatchV1().Jobs(util.CloudweavSystemNamespaceName).List(ctx, metav1.ListOptions{LabelSelector: upgradeSelector})
+	if err != nil {
+		return nil, err
+	}
+	input.Jobs = jobs.Items
+	for _, plan := range plans.Items {
+		planJobs, err := clients.Kube.BatchV1().Jobs(sucNamespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.Set{planLabel: plan.Name}.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		input.Jobs = append(input.Jobs, planJobs.Items...)
+	}
+
+	nodes, err := clients.Kube.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	input.Nodes = nodes.Items
+	return input, nil
+}
+
+// DiagnoseStuck looks for what keeps an upgrade from moving on
+func DiagnoseStuck(input *StuckInput, now time.Time) *StuckReport {
+	upgrade := input.Upgrade
+	report := &StuckReport{
+		Upgrade:  upgrade.Name,
+		State:    upgrade.Labels[upgradeStateLabel],
+		Findings: []Finding{},
+	}
+	add := func(severity, object, format string, args ...interface{}) {
+		report.Findings = append(report.Findings, Finding{Severity: severity, Object: object, Message: fmt.Sprintf(format, args...)})
+	}
+	upgradeObject := "upgrade/" + upgrade.Name
+
+	completed := isUpgradeCompleted(upgrade)
+	if completed {
+		add(SeverityInfo, upgradeObject, "upgrade is completed with state %s", report.State)
+	}
+	if upgrade.Spec.Paused {
+		add(SeverityWarning, upgradeObject, "upgrade is paused, resume it to upgrade the remaining nodes")
+	}
+
+	for _, condition := range upgrade.Status.Conditions {
+		switch condition.Status {
+		case corev1.ConditionFalse:
+			if condition.Reason == logReadyDisabledReason {
+				continue
+			}
+			add(SeverityError, upgradeObject, "condition %s is False: %s", condition.Type, conditionMessage(condition.Reason, condition.Message))
+		case corev1.ConditionUnknown:
+			if since, err := time.Parse(time.RFC3339, condition.LastUpdateTime); err == nil && now.Sub(since) > slowThreshold {
+				add(SeverityWarning, upgradeObject, "condition %s has been in progress since %s", condition.Type, condition.LastUpdateTime)
+			}
+		}
+	}
+
+	nodeNames := make([]string, 0, len(upgrade.Status.NodeStatuses))
+	for name := range upgrade.Status.NodeStatuses {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+	for _, name := range nodeNames {
+		status := upgrade.Status.NodeStatuses[name]
+		switch {
+		case status.State == nodeStateFailed:
+			add(SeverityError, "node/"+name, "node upgrade failed: %s", conditionMessage(status.Reason, status.Message))
+		case strings.HasPrefix(status.State, "Waiting") || status.State == "Paused":
+			add(SeverityInfo, "node/"+name, "node is in state %s", status.State)
+		}
+	}
+
+	for _, plan := range input.Plans {
+		object := "plan/" + plan.Name
+		if completed && !isPurgingImages(&plan) {
+			add(SeverityWarning, object, "plan is left over by the completed upgrade, remove it with cleanup-orphans")
+		}
+		if len(plan.Status.Applying) > 0 {
+			add(SeverityInfo, object, "plan is applying on nodes %s", strings.Join(plan.Status.Applying, ", "))
+		}
+	}
+
+	for _, job := range input.Jobs {
+		object := fmt.Sprintf("job/%s/%s", job.Namespace, job.Name)
+		switch jobStatus(&job) {
+		case JobStatusFailed:
+			add(SeverityError, object, "job failed: %s", jobFailure(&job))
+		case JobStatusRunning:
+			if job.Status.StartTime != nil && now.Sub(job.Status.StartTime.Time) > slowThreshold {
+				add(SeverityWarning, object, "job has been running since %s", job.Status.StartTime.UTC().Format(time.RFC3339))
+			}
+		}
+	}
+
+	for _, node := range input.Nodes {
+		object := "node/" + node.Name
+		if image, ok := node.Annotations[pendingOSImageAnnotation]; ok {
+			add(SeverityWarning, object, "node is waiting to reboot into OS %s, reboot it if the upgrade job has completed", image)
+		}
+		if node.Spec.Unschedulable {
+			add(SeverityWarning, object, "node is cordoned")
+		}
+	}
+
+	severities := map[string]int{SeverityError: 0, SeverityWarning: 1, SeverityInfo: 2}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return severities[report.Findings[i].Severity] < severities[report.Findings[j].Severity]
+	})
+	return report
+}
+
+func conditionMessage(reason, message string) string {
+	if reason == "" {
+		return message
+	}
+	if message == "" {
+		return reason
+	}
+	return fmt.Sprintf("%s: %s", reason, message)
+}
+
+func jobFailure(job *batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return conditionMessage(condition.Reason, condition.Message)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package diagnostics
+
+import (
+	"testing"
+	"time"
+
+	upgradev1 "github.com/rancher/system-upgrade-controller/pkg/apis/upgrade.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+func TestDiagnoseStuck(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC)
+	jobStart := metav1.NewTime(now.Add(-2 * time.Hour))
+	input := &StuckInput{
+		Upgrade: &cloudweavv1.Upgrade{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "hvst-upgrade-abcde",
+				Labels: map[string]string{upgradeStateLabel: "UpgradingNodes"},
+			},
+			Spec: cloudweavv1.UpgradeSpec{Paused: true},
+			Status: cloudweavv1.UpgradeStatus{
+				Conditions: []cloudweavv1.Condition{
+					{Type: cloudweavv1.LogReady, Status: corev1.ConditionFalse, Reason: logReadyDisabledReason},
+					{Type: cloudweavv1.NodesUpgraded, Status: corev1.ConditionUnknown, LastUpdateTime: now.Add(-3 * time.Hour).Format(time.RFC3339)},
+					{Type: cloudweavv1.SystemServicesUpgraded, Status: corev1.ConditionTrue},
+				},
+				NodeStatuses: map[string]cloudweavv1.NodeUpgradeStatus{
+					"node1": {State: "Succeeded"},
+					"node2": {State: nodeStateFailed, Reason: "Failed", Message: "Job has reached the specified backoff limit"},
+					"node3": {State: "Waiting batch"},
+				},
+			},
+		},
+		Plans: []upgradev1.Plan{
+			{ObjectMeta: metav1.ObjectMeta{Name: "hvst-upgrade-abcde-prepare"}},
+		},
+		Jobs: []batchv1.Job{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "cloudweav-system", Name: "hvst-upgrade-abcde-post-drain-node2"},
+				Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"},
+				}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "cattle-system", Name: "apply-hvst-upgrade-abcde-prepare-on-node3"},
+				Status:     batchv1.JobStatus{StartTime: &jobStart, Active: 1},
+			},
+		},
+		Nodes: []corev1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "node2", Annotations: map[string]string{pendingOSImageAnnotation: "Cloudweav v1.5.0"}},
+				Spec:       corev1.NodeSpec{Unschedulable: true},
+			},
+		},
+	}
+
+	report := DiagnoseStuck(input, now)
+	assert.Equal(t, "UpgradingNodes", report.State)
+	assert.Equal(t, []Finding{
+		{Severity: SeverityError, Object: "node/node2", Message: "node upgrade failed: Failed: Job has reached the specified backoff limit"},
+		{Severity: SeverityError, Object: "job/cloudweav-system/hvst-upgrade-abcde-post-drain-node2", Message: "job failed: BackoffLimitExceeded: Job has reached the specified backoff limit"},
+		{Severity: SeverityWarning, Object: "upgrade/hvst-upgrade-abcde", Message: "upgrade is paused, resume it to upgrade the remaining nodes"},
+		{Severity: SeverityWarning, Object: "upgrade/hvst-upgrade-abcde", Message: "condition NodesUpgraded has been in progress since 2026-10-19T07:30:00Z"},
+		{Severity: SeverityWarning, Object: "job/cattle-system/apply-hvst-upgrade-abcde-prepare-on-node3", Message: "job has been running since 2026-10-19T08:30:00Z"},
+		{Severity: SeverityWarning, Object: "node/node2", Message: "node is waiting to reboot into OS Cloudweav v1.5.0, reboot it if the upgrade job has completed"},
+		{Severity: SeverityWarning, Object: "node/node2", Message: "node is cordoned"},
+		{Severity: SeverityInfo, Object: "node/node3", Message: "node is in state Waiting batch"},
+	}, report.Findings)
+
+	input.Upgrade.Labels[upgradeStateLabel] = upgradeStateSucceeded
+	input.Upgrade.Spec.Paused = false
+	input.Upgrade.Status.Conditions = nil
+	input.Upgrade.Status.NodeStatuses = nil
+	input.Jobs, input.Nodes = nil, nil
+	report = DiagnoseStuck(input, now)
+	assert.Equal(t, []Finding{
+		{Severity: SeverityWarning, Object: "plan/hvst-upgrade-abcde-prepare", Message: "plan is left over by the completed upgrade, remove it with cleanup-orphans"},
+		{Severity: SeverityInfo, Object: "upgrade/hvst-upgrade-abcde", Message: "upgrade is completed with state Succeeded"},
+	}, report.Findings)
+}
@@ -0,0 +1,250 @@
+package readiness
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	lhv1beta2 "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/upgradehelper/versionguard"
+	"github.com/cloudweav/cloudweav/pkg/util/virtualmachineinstance"
+)
+
+const (
+	CheckVersion      = "version"
+	CheckNodes        = "nodes"
+	CheckDiskSpace    = "disk-space"
+	CheckVolumes      = "volumes"
+	CheckVMMigration  = "vm-migration"
+	CheckCertificates = "certificates"
+	CheckJobs         = "jobs"
+	CheckAddons       = "addons"
+
+	// a Longhorn disk with less free space than the ratio may fill up with the new images and the replica rebuilds
+	minFreeDiskRatio = 0.15
+	// a certificate expiring within the fail window may expire during the upgrade
+	certificateFailWindow = 7 * 24 * time.Hour
+	certificateWarnWindow = 30 * 24 * time.Hour
+	stuckJobTimeout       = time.Hour
+
+	// listed names are truncated to keep the messages short
+	maxListedNames = 5
+)
+
+func init() {
+	Register(CheckVersion, checkVersion)
+	Register(CheckNodes, checkNodes)
+	Register(CheckDiskSpace, checkDiskSpace)
+	Register(CheckVolumes, checkVolumes)
+	Register(CheckVMMigration, checkVMMigration)
+	Register(CheckCertificates, checkCertificates)
+	Register(CheckJobs, checkJobs)
+	Register(CheckAddons, checkAddons)
+}
+
+func checkVersion(snapshot *Snapshot) cloudweavv1.ReadinessCheck {
+	if snapshot.TargetVersion == nil {
+		return pass("no version to upgrade to is specified")
+	}
+	if err := versionguard.CheckVersion(snapshot.CurrentVersion, snapshot.TargetVersion, true); err != nil {
+		return fail(fmt.Sprintf("version %s can't be upgraded to %s: %v", snapshot.CurrentVersion, snapshot.TargetVersion.Name, err),
+			"upgrade to an intermediate version first")
+	}
+	return pass(fmt.Sprintf("version %s can be upgraded to %s", snapshot.CurrentVersion, snapshot.TargetVersion.Name))
+}
+
+func checkNodes(snapshot *Snapshot) cloudweavv1.ReadinessCheck {
+	var notReady, unschedulable []string
+	for _, node := range snapshot.Nodes {
+		if !isNodeConditionTrue(node, corev1.NodeReady) {
+			notReady = append(notReady, node.Name)
+		}
+		if node.Spec.Unschedulable {
+			unschedulable = append(unschedulable, node.Name)
+		}
+	}
+
+	if len(notReady) > 0 {
+		return fail(fmt.Sprintf("nodes %s are not ready", listNames(notReady)), "wait for the nodes to be ready or remove them from the cluster")
+	}
+	if len(unschedulable) > 0 {
+		return fail(fmt.Sprintf("nodes %s are unschedulable", listNames(unschedulable)), "disable the maintenance mode or uncordon the nodes")
+	}
+	return pass(fmt.Sprintf("all %d nodes are ready", len(snapshot.Nodes)))
+}
+
+func checkDiskSpace(snapshot *Snapshot) cloudweavv1.ReadinessCheck {
+	var pressure []string
+	for _, node := range snapshot.Nodes {
+		if isNodeConditionTrue(node, corev1.NodeDiskPressure) {
+			pressure = append(pressure, node.Name)
+		}
+	}
+	if len(pressure) > 0 {
+		return fail(fmt.Sprintf("nodes %s are under disk pressure", listNames(pressure)), "free up the system partition of the nodes")
+	}
+
+	var nearlyFull []string
+	for _, lhNode := range snapshot.LonghornNodes {
+		for name, disk := range lhNode.Status.DiskStatus {
+			if disk == nil || disk.StorageMaximum == 0 {
+				continue
+			}
+			if float64(disk.StorageAvailable)/float64(disk.StorageMaximum) < minFreeDiskRatio {
+				nearlyFull = append(nearlyFull, lhNode.Name+"/"+name)
+			}
+		}
+	}
+	if len(nearlyFull) > 0 {
+		return warn(fmt.Sprintf("disks %s have less than %.0f%% free space", listNames(nearlyFull), minFreeDiskRatio*100),
+			"delete unused volumes, images and snapshots, or add disks")
+	}
+	return pass("no disk is nearly full")
+}
+
+func checkVolumes(snapshot *Snapshot) cloudweavv1.ReadinessCheck {
+	var degraded, faulted []string
+	for _, volume := range snapshot.Volumes {
+		switch volume.Status.Robustness {
+		case lhv1beta2.VolumeRobustnessDegraded:
+			// a volume of a cluster with less than 3 nodes may be degraded by design
+			if len(snapshot.Nodes) >= 3 {
+				degraded = append(degraded, volume.Name)
+			}
+		case lhv1beta2.VolumeRobustnessFaulted:
+			faulted = append(faulted, volume.Name)
+		}
+	}
+
+	if len(faulted) > 0 {
+		return fail(fmt.Sprintf("volumes %s are faulted", listNames(faulted)), "restore the volumes from a backup or delete them")
+	}
+	if len(degraded) > 0 {
+		return fail(fmt.Sprintf("volumes %s are degraded", listNames(degraded)), "wait for the replicas of the volumes to be rebuilt")
+	}
+	return pass("all volumes are healthy")
+}
+
+func checkVMMigration(snapshot *Snapshot) cloudweavv1.ReadinessCheck {
+	names, err := virtualmachineinstance.GetAllNonLiveMigratableVMINames(snapshot.VMIs, snapshot.Nodes)
+	if err != nil {
+		return fail(fmt.Sprintf("failed to check the VMs: %v", err), "")
+	}
+	if len(names) > 0 {
+		return fail(fmt.Sprintf("VMs %s can't be live migrated", listNames(names)),
+			"shut down the VMs, or remove their node selectors, host devices and local volumes")
+	}
+	return pass("all running VMs can be live migrated")
+}
+
+func checkCertificates(snapshot *Snapshot) cloudweavv1.ReadinessCheck {
+	var expiring, expiringSoon []string
+	for _, secret := range snapshot.Certificates {
+		notAfter, err := certificateNotAfter(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			continue
+		}
+		name := secret.Namespace + "/" + secret.Name
+		switch left := notAfter.Sub(snapshot.Now); {
+		case left < certificateFailWindow:
+			expiring = append(expiring, name)
+		case left < certificateWarnWindow:
+			expiringSoon = append(expiringSoon, name)
+		}
+	}
+
+	if len(expiring) > 0 {
+		return fail(fmt.Sprintf("certificates %s expire within %d days", listNames(expiring), int(certificateFailWindow.Hours()/24)),
+			"rotate the certificates before the upgrade")
+	}
+	if len(expiringSoon) > 0 {
+		return warn(fmt.Sprintf("certificates %s expire within %d days", listNames(expiringSoon), int(certificateWarnWindow.Hours()/24)),
+			"rotate the certificates")
+	}
+	return pass("no certificate is expiring")
+}
+
+func checkJobs(snapshot *Snapshot) cloudweavv1.ReadinessCheck {
+	var stuck []string
+	for _, job := range snapshot.Jobs {
+		if job.Status.Active == 0 || job.Status.StartTime == nil || isJobFinished(job) {
+			continue
+		}
+		if snapshot.Now.Sub(job.Status.StartTime.Time) > stuckJobTimeout {
+			stuck = append(stuck, job.Namespace+"/"+job.Name)
+		}
+	}
+	if len(stuck) > 0 {
+		return warn(fmt.Sprintf("jobs %s have been running for more than %s", listNames(stuck), stuckJobTimeout),
+			"check the logs of the jobs and delete the stuck ones")
+	}
+	return pass("no job is stuck")
+}
+
+func checkAddons(snapshot *Snapshot) cloudweavv1.ReadinessCheck {
+	var inProgress, failed []string
+	for _, addon := range snapshot.Addons {
+		name := addon.Namespace + "/" + addon.Name
+		switch {
+		case cloudweavv1.AddonOperationFailed.IsTrue(addon):
+			failed = append(failed, name)
+		case addon.Status.Status == cloudweavv1.AddonEnabling || addon.Status.Status == cloudweavv1.AddonDisabling ||
+			addon.Status.Status == cloudweavv1.AddonUpdating:
+			inProgress = append(inProgress, name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fail(fmt.Sprintf("the last operation of addons %s failed", listNames(failed)), "fix the addons or disable them")
+	}
+	if len(inProgress) > 0 {
+		return fail(fmt.Sprintf("addons %s are being enabled, disabled or updated", listNames(inProgress)), "wait for the addon operations to complete")
+	}
+	return pass("no addon operation is pending")
+}
+
+func isNodeConditionTrue(node *corev1.Node, conditionType corev1.NodeConditionType) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == conditionType {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func isJobFinished(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// certificateNotAfter returns when the first certificate of the PEM data expires
+func certificateNotAfter(data []byte) (time.Time, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}, fmt.Errorf("no PEM certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+func listNames(names []string) string {
+	sort.Strings(names)
+	if len(names) > maxListedNames {
+		return fmt.Sprintf("%s and %d more", strings.Join(names[:maxListedNames], ", "), len(names)-maxListedNames)
+	}
+	return strings.Join(names, ", ")
+}
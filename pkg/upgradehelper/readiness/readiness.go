@@ -0,0 +1,119 @@
+package readiness
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	lhv1beta2 "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+// Snapshot is the cluster state the checks inspect. The controllers build it from caches,
+// the upgrade helper builds it from clients.
+type Snapshot struct {
+	Now            time.Time
+	CurrentVersion string
+	// TargetVersion is the version to upgrade to, nil if the readiness isn't checked against a version
+	TargetVersion *cloudweavv1.Version
+
+	Nodes         []*corev1.Node
+	Volumes       []*lhv1beta2.Volume
+	LonghornNodes []*lhv1beta2.Node
+	VMIs          []*kubevirtv1.VirtualMachineInstance
+	Jobs          []*batchv1.Job
+	Addons        []*cloudweavv1.Addon
+	// Certificates are the TLS secrets of the system namespaces
+	Certificates []*corev1.Secret
+}
+
+// CheckFunc inspects the snapshot, it returns the result without the check name
+type CheckFunc func(snapshot *Snapshot) cloudweavv1.ReadinessCheck
+
+// Check is a named readiness check
+type Check struct {
+	Name string
+	Run  CheckFunc
+}
+
+var (
+	checksLock sync.RWMutex
+	checks     []Check
+)
+
+// Register adds a check, the checks run in the order they're registered. A check registered with the name of an
+// existing one replaces it.
+func Register(name string, run CheckFunc) {
+	checksLock.Lock()
+	defer checksLock.Unlock()
+
+	for i := range checks {
+		if checks[i].Name == name {
+			checks[i].Run = run
+			return
+		}
+	}
+	checks = append(checks, Check{Name: name, Run: run})
+}
+
+// Checks returns the registered checks
+func Checks() []Check {
+	checksLock.RLock()
+	defer checksLock.RUnlock()
+
+	return append([]Check(nil), checks...)
+}
+
+// Run runs the registered checks against the snapshot. The cluster is ready if no check fails, except the overridden ones.
+func Run(snapshot *Snapshot, overrides []string) ([]cloudweavv1.ReadinessCheck, bool) {
+	overridden := make(map[string]bool, len(overrides))
+	for _, name := range overrides {
+		overridden[name] = true
+	}
+
+	ready := true
+	registered := Checks()
+	results := make([]cloudweavv1.ReadinessCheck, 0, len(registered))
+	for _, check := range registered {
+		result := check.Run(snapshot)
+		result.Name = check.Name
+		result.Overridden = overridden[check.Name]
+		if result.Result == cloudweavv1.ReadinessFail && !result.Overridden {
+			ready = false
+		}
+		results = append(results, result)
+	}
+	return results, ready
+}
+
+// Failures returns the messages of the failed checks which aren't overridden
+func Failures(results []cloudweavv1.ReadinessCheck) []string {
+	var failures []string
+	for _, result := range results {
+		if result.Result != cloudweavv1.ReadinessFail || result.Overridden {
+			continue
+		}
+		message := fmt.Sprintf("%s: %s", result.Name, result.Message)
+		if result.Remediation != "" {
+			message = fmt.Sprintf("%s (%s)", message, result.Remediation)
+		}
+		failures = append(failures, message)
+	}
+	return failures
+}
+
+func pass(message string) cloudweavv1.ReadinessCheck {
+	return cloudweavv1.ReadinessCheck{Result: cloudweavv1.ReadinessPass, Message: message}
+}
+
+func warn(message, remediation string) cloudweavv1.ReadinessCheck {
+	return cloudweavv1.ReadinessCheck{Result: cloudweavv1.ReadinessWarn, Message: message, Remediation: remediation}
+}
+
+func fail(message, remediation string) cloudweavv1.ReadinessCheck {
+	return cloudweavv1.ReadinessCheck{Result: cloudweavv1.ReadinessFail, Message: message, Remediation: remediation}
+}
@@ -0,0 +1,275 @@
+package readiness
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	lhv1beta2 "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+var now = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newNode(name string, ready bool) *corev1.Node {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func newVolume(name string, robustness lhv1beta2.VolumeRobustness) *lhv1beta2.Volume {
+	return &lhv1beta2.Volume{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "longhorn-system"},
+		Status:     lhv1beta2.VolumeStatus{Robustness: robustness},
+	}
+}
+
+func newCertificate(t *testing.T, name string, notAfter time.Time) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "cattle-system"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})},
+	}
+}
+
+func TestRun(t *testing.T) {
+	var tests = []struct {
+		name      string
+		snapshot  *Snapshot
+		overrides []string
+		ready     bool
+		results   map[string]cloudweavv1.ReadinessResult
+	}{
+		{
+			name: "healthy cluster",
+			snapshot: &Snapshot{
+				Now:     now,
+				Nodes:   []*corev1.Node{newNode("node1", true), newNode("node2", true), newNode("node3", true)},
+				Volumes: []*lhv1beta2.Volume{newVolume("vol1", lhv1beta2.VolumeRobustnessHealthy)},
+			},
+			ready: true,
+			results: map[string]cloudweavv1.ReadinessResult{
+				CheckVersion: cloudweavv1.ReadinessPass,
+				CheckNodes:   cloudweavv1.ReadinessPass,
+				CheckVolumes: cloudweavv1.ReadinessPass,
+			},
+		},
+		{
+			name: "not ready node and degraded volume",
+			snapshot: &Snapshot{
+				Now:     now,
+				Nodes:   []*corev1.Node{newNode("node1", true), newNode("node2", false), newNode("node3", true)},
+				Volumes: []*lhv1beta2.Volume{newVolume("vol1", lhv1beta2.VolumeRobustnessDegraded)},
+			},
+			ready: false,
+			results: map[string]cloudweavv1.ReadinessResult{
+				CheckNodes:   cloudweavv1.ReadinessFail,
+				CheckVolumes: cloudweavv1.ReadinessFail,
+			},
+		},
+		{
+			name: "degraded volume of a two node cluster",
+			snapshot: &Snapshot{
+				Now:     now,
+				Nodes:   []*corev1.Node{newNode("node1", true), newNode("node2", true)},
+				Volumes: []*lhv1beta2.Volume{newVolume("vol1", lhv1beta2.VolumeRobustnessDegraded)},
+			},
+			ready: true,
+			results: map[string]cloudweavv1.ReadinessResult{
+				CheckVolumes: cloudweavv1.ReadinessPass,
+			},
+		},
+		{
+			name: "overridden failure",
+			snapshot: &Snapshot{
+				Now:     now,
+				Nodes:   []*corev1.Node{newNode("node1", true)},
+				Volumes: []*lhv1beta2.Volume{newVolume("vol1", lhv1beta2.VolumeRobustnessFaulted)},
+			},
+			overrides: []string{CheckVolumes},
+			ready:     true,
+			results: map[string]cloudweavv1.ReadinessResult{
+				CheckVolumes: cloudweavv1.ReadinessFail,
+			},
+		},
+		{
+			name: "nearly full disk and stuck job",
+			snapshot: &Snapshot{
+				Now:   now,
+				Nodes: []*corev1.Node{newNode("node1", true)},
+				LonghornNodes: []*lhv1beta2.Node{{
+					ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+					Status: lhv1beta2.NodeStatus{DiskStatus: map[string]*lhv1beta2.DiskStatus{
+						"default-disk": {StorageAvailable: 10, StorageMaximum: 100},
+					}},
+				}},
+				Jobs: []*batchv1.Job{{
+					ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "cloudweav-system"},
+					Status:     batchv1.JobStatus{Active: 1, StartTime: &metav1.Time{Time: now.Add(-2 * time.Hour)}},
+				}},
+			},
+			ready: true,
+			results: map[string]cloudweavv1.ReadinessResult{
+				CheckDiskSpace: cloudweavv1.ReadinessWarn,
+				CheckJobs:      cloudweavv1.ReadinessWarn,
+			},
+		},
+		{
+			name: "addon being enabled",
+			snapshot: &Snapshot{
+				Now:   now,
+				Nodes: []*corev1.Node{newNode("node1", true)},
+				Addons: []*cloudweavv1.Addon{{
+					ObjectMeta: metav1.ObjectMeta{Name: "addon1", Namespace: "cloudweav-system"},
+					Status:     cloudweavv1.AddonStatus{Status: cloudweavv1.AddonEnabling},
+				}},
+			},
+			ready: false,
+			results: map[string]cloudweavv1.ReadinessResult{
+				CheckAddons: cloudweavv1.ReadinessFail,
+			},
+		},
+		{
+			name: "downgrade",
+			snapshot: &Snapshot{
+				Now:            now,
+				CurrentVersion: "v1.4.0",
+				TargetVersion:  &cloudweavv1.Version{ObjectMeta: metav1.ObjectMeta{Name: "v1.3.2"}},
+				Nodes:          []*corev1.Node{newNode("node1", true)},
+			},
+			ready: false,
+			results: map[string]cloudweavv1.ReadinessResult{
+				CheckVersion: cloudweavv1.ReadinessFail,
+			},
+		},
+		{
+			name: "minimum upgradable version is met",
+			snapshot: &Snapshot{
+				Now:            now,
+				CurrentVersion: "v1.3.2",
+				TargetVersion: &cloudweavv1.Version{
+					ObjectMeta: metav1.ObjectMeta{Name: "v1.4.0"},
+					Spec:       cloudweavv1.VersionSpec{MinUpgradableVersion: "v1.3.1"},
+				},
+				Nodes: []*corev1.Node{newNode("node1", true)},
+			},
+			ready: true,
+			results: map[string]cloudweavv1.ReadinessResult{
+				CheckVersion: cloudweavv1.ReadinessPass,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		results, ready := Run(tc.snapshot, tc.overrides)
+		assert.Equal(t, tc.ready, ready, tc.name)
+		assert.Len(t, results, len(Checks()), tc.name)
+		for _, result := range results {
+			if expected, ok := tc.results[result.Name]; ok {
+				assert.Equal(t, expected, result.Result, "%s: %s", tc.name, result.Name)
+			}
+		}
+	}
+}
+
+func TestCheckCertificates(t *testing.T) {
+	var tests = []struct {
+		name     string
+		notAfter time.Time
+		expected cloudweavv1.ReadinessResult
+	}{
+		{
+			name:     "valid certificate",
+			notAfter: now.Add(90 * 24 * time.Hour),
+			expected: cloudweavv1.ReadinessPass,
+		},
+		{
+			name:     "certificate expiring within 30 days",
+			notAfter: now.Add(20 * 24 * time.Hour),
+			expected: cloudweavv1.ReadinessWarn,
+		},
+		{
+			name:     "certificate expiring within 7 days",
+			notAfter: now.Add(3 * 24 * time.Hour),
+			expected: cloudweavv1.ReadinessFail,
+		},
+		{
+			name:     "expired certificate",
+			notAfter: now.Add(-time.Hour),
+			expected: cloudweavv1.ReadinessFail,
+		},
+	}
+
+	for _, tc := range tests {
+		snapshot := &Snapshot{Now: now, Certificates: []*corev1.Secret{newCertificate(t, "tls-rancher", tc.notAfter)}}
+		assert.Equal(t, tc.expected, checkCertificates(snapshot).Result, tc.name)
+	}
+}
+
+func TestFailures(t *testing.T) {
+	results := []cloudweavv1.ReadinessCheck{
+		{Name: CheckNodes, Result: cloudweavv1.ReadinessFail, Message: "nodes node1 are not ready", Remediation: "wait"},
+		{Name: CheckVolumes, Result: cloudweavv1.ReadinessFail, Message: "volumes vol1 are faulted", Overridden: true},
+		{Name: CheckJobs, Result: cloudweavv1.ReadinessWarn, Message: "jobs job1 are stuck"},
+		{Name: CheckAddons, Result: cloudweavv1.ReadinessFail, Message: "addons addon1 are being enabled"},
+	}
+	assert.Equal(t, []string{
+		"nodes: nodes node1 are not ready (wait)",
+		"addons: addons addon1 are being enabled",
+	}, Failures(results))
+}
+
+func TestRegister(t *testing.T) {
+	registered := Checks()
+	defer func() {
+		checksLock.Lock()
+		checks = registered
+		checksLock.Unlock()
+	}()
+
+	Register(CheckJobs, func(_ *Snapshot) cloudweavv1.ReadinessCheck {
+		return fail("replaced", "")
+	})
+	Register("custom", func(_ *Snapshot) cloudweavv1.ReadinessCheck {
+		return warn("custom", "")
+	})
+
+	results, ready := Run(&Snapshot{Now: now}, nil)
+	assert.False(t, ready)
+	assert.Len(t, results, len(registered)+1)
+	for _, result := range results {
+		switch result.Name {
+		case CheckJobs:
+			assert.Equal(t, "replaced", result.Message)
+		case "custom":
+			assert.Equal(t, cloudweavv1.ReadinessWarn, result.Result)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package readiness
+
+import (
+	"context"
+	"time"
+
+	ctlbatchv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/batch/v1"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
+	ctllhv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/longhorn.io/v1beta2"
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+// SystemNamespaces are the namespaces whose jobs and certificates are checked
+var SystemNamespaces = []string{
+	util.CloudweavSystemNamespaceName,
+	metav1.NamespaceSystem,
+	util.CattleSystemNamespaceName,
+	util.LonghornSystemNamespaceName,
+}
+
+// Caches builds snapshots from the caches of the controllers and the webhook
+type Caches struct {
+	Nodes         ctlcorev1.NodeCache
+	Volumes       ctllhv1.VolumeCache
+	LonghornNodes ctllhv1.NodeCache
+	VMIs          ctlkubevirtv1.VirtualMachineInstanceCache
+	Jobs          ctlbatchv1.JobCache
+	Addons        ctlcloudweavv1.AddonCache
+	Secrets       ctlcorev1.SecretCache
+}
+
+func (c *Caches) Snapshot(currentVersion string, target *cloudweavv1.Version) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Now:            time.Now(),
+		CurrentVersion: currentVersion,
+		TargetVersion:  target,
+	}
+
+	var err error
+	if snapshot.Nodes, err = c.Nodes.List(labels.Everything()); err != nil {
+		return nil, err
+	}
+	if snapshot.Volumes, err = c.Volumes.List(util.LonghornSystemNamespaceName, labels.Everything()); err != nil {
+		return nil, err
+	}
+	if snapshot.LonghornNodes, err = c.LonghornNodes.List(util.LonghornSystemNamespaceName, labels.Everything()); err != nil {
+		return nil, err
+	}
+	if snapshot.VMIs, err = c.VMIs.List(corev1.NamespaceAll, labels.Everything()); err != nil {
+		return nil, err
+	}
+	if snapshot.Addons, err = c.Addons.List(corev1.NamespaceAll, labels.Everything()); err != nil {
+		return nil, err
+	}
+	for _, namespace := range SystemNamespaces {
+		jobs, err := c.Jobs.List(namespace, labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Jobs = append(snapshot.Jobs, jobs...)
+
+		secrets, err := c.Secrets.List(namespace, labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Certificates = append(snapshot.Certificates, filterCertificates(secrets)...)
+	}
+	return snapshot, nil
+}
+
+// NewSnapshot builds a snapshot with the clients, it's used where no cache is running like the upgrade helper
+func NewSnapshot(ctx context.Context, kubeClient kubernetes.Interface, client versioned.Interface, currentVersion string, target *cloudweavv1.Version) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Now:            time.Now(),
+		CurrentVersion: currentVersion,
+		TargetVersion:  target,
+	}
+
+	nodes, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range nodes.Items {
+		snapshot.Nodes = append(snapshot.Nodes, &nodes.Items[i])
+	}
+
+	volumes, err := client.LonghornV1beta2().Volumes(util.LonghornSystemNamespaceName).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range volumes.Items {
+		snapshot.Volumes = append(snapshot.Volumes, &volumes.Items[i])
+	}
+
+	lhNodes, err := client.LonghornV1beta2().Nodes(util.LonghornSystemNamespaceName).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range lhNodes.Items {
+		snapshot.LonghornNodes = append(snapshot.LonghornNodes, &lhNodes.Items[i])
+	}
+
+	vmis, err := client.KubevirtV1().VirtualMachineInstances(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range vmis.Items {
+		snapshot.VMIs = append(snapshot.VMIs, &vmis.Items[i])
+	}
+
+	addons, err := client.CloudweavhciV1beta1().Addons(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range addons.Items {
+		snapshot.Addons = append(snapshot.Addons, &addons.Items[i])
+	}
+
+	for _, namespace := range SystemNamespaces {
+		jobs, err := kubeClient.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range jobs.Items {
+			snapshot.Jobs = append(snapshot.Jobs, &jobs.Items[i])
+		}
+
+		secrets, err := kubeClient.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{FieldSelector: "type=" + string(corev1.SecretTypeTLS)})
+		if err != nil {
+			return nil, err
+		}
+		for i := range secrets.Items {
+			snapshot.Certificates = append(snapshot.Certificates, &secrets.Items[i])
+		}
+	}
+	return snapshot, nil
+}
+
+func filterCertificates(secrets []*corev1.Secret) []*corev1.Secret {
+	var certificates []*corev1.Secret
+	for _, secret := range secrets {
+		if secret.Type == corev1.SecretTypeTLS {
+			certificates = append(certificates, secret)
+		}
+	}
+	return certificates
+}
@@ -57,3 +57,23 @@ func Check(upgrade *v1beta1.Upgrade, strictMode bool, minUpgradableVersionStr st
 
 	return cloudweavUpgradeVersion.CheckUpgradeEligibility(strictMode)
 }
+
+// CheckVersion checks whether the current version can be upgraded to the version before an upgrade fetches its repo info
+func CheckVersion(currentVersionStr string, target *v1beta1.Version, strictMode bool) error {
+	upgradeVersion, err := version.NewCloudweavVersion(target.Name)
+	if err != nil {
+		return err
+	}
+
+	currentVersion, err := version.NewCloudweavVersion(currentVersionStr)
+	if err != nil {
+		return err
+	}
+
+	minUpgradableVersion, err := version.NewCloudweavVersion(target.Spec.MinUpgradableVersion)
+	if err != nil && !errors.Is(err, version.ErrInvalidVersion) {
+		return err
+	}
+
+	return version.NewCloudweavUpgradeVersion(currentVersion, upgradeVersion, minUpgradableVersion).CheckUpgradeEligibility(strictMode)
+}
@@ -0,0 +1,152 @@
+package upgraderesponder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/rancher/wrangler/v3/pkg/slice"
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/cloudweav/cloudweav/pkg/settings"
+)
+
+const (
+	// ReleaseChannelStable, ReleaseChannelLatest and ReleaseChannelLTS are the release channels, a version is in the
+	// channels of its tags
+	ReleaseChannelStable = "stable"
+	ReleaseChannelLatest = "latest"
+	ReleaseChannelLTS    = "lts"
+
+	ExtraInfoNodeCount  = "nodeCount"
+	ExtraInfoCPUCount   = "cpuCount"
+	ExtraInfoMemorySize = "memorySize"
+	ExtraInfoClusterUID = "clusterUID"
+
+	// CheckUpgradePath is where the responder serves the upgrade checks. It's served without authentication, so it can
+	// be set as the upgrade-checker-url of the other clusters.
+	CheckUpgradePath = "/v1-public/cloudweav/checkupgrade"
+
+	maxRequestSize = 1 << 20
+)
+
+var (
+	ReleaseChannels = []string{ReleaseChannelStable, ReleaseChannelLatest, ReleaseChannelLTS}
+	ExtraInfoFields = []string{ExtraInfoNodeCount, ExtraInfoCPUCount, ExtraInfoMemorySize, ExtraInfoClusterUID}
+)
+
+type CheckUpgradeRequest struct {
+	AppVersion string `json:"appVersion"`
+	// ReleaseChannel is the channel the cluster follows, the versions of all channels are returned if it's empty
+	ReleaseChannel string            `json:"releaseChannel,omitempty"`
+	ExtraInfo      map[string]string `json:"extraInfo"`
+}
+
+type CheckUpgradeResponse struct {
+	Versions []Version `json:"versions"`
+}
+
+type Version struct {
+	Name                 string   `json:"name"` // must be in semantic versioning
+	ReleaseDate          string   `json:"releaseDate"`
+	MinUpgradableVersion string   `json:"minUpgradableVersion,omitempty"`
+	Tags                 []string `json:"tags"`
+	// ISOURL and ISOChecksum are the ISO of the version. The public responder doesn't return them and the clusters
+	// download them in the version.yaml of the release, the curated versions must have them since the clusters of a
+	// fleet may not reach the release download URL.
+	ISOURL      string `json:"isoURL,omitempty"`
+	ISOChecksum string `json:"isoChecksum,omitempty"`
+}
+
+// InChannel returns true if the version is tagged with the channel, every version is in the empty channel
+func (v Version) InChannel(channel string) bool {
+	return channel == "" || slice.ContainsString(v.Tags, channel)
+}
+
+// FilterChannel returns the versions in the channel
+func FilterChannel(versions []Version, channel string) []Version {
+	result := make([]Version, 0, len(versions))
+	for _, version := range versions {
+		if version.InChannel(channel) {
+			result = append(result, version)
+		}
+	}
+	return result
+}
+
+// ParseVersions parses the curated versions in JSON or YAML, the format is the same as the response
+func ParseVersions(data string) (*CheckUpgradeResponse, error) {
+	var versions CheckUpgradeResponse
+	if err := yaml.Unmarshal([]byte(data), &versions); err != nil {
+		return nil, err
+	}
+	for _, version := range versions.Versions {
+		if !strings.HasPrefix(version.Name, "v") {
+			return nil, fmt.Errorf("invalid version name %q, it must be in semantic versioning with the v prefix", version.Name)
+		}
+		if version.ISOURL == "" || version.ISOChecksum == "" {
+			return nil, fmt.Errorf("version %s must have the isoURL and the isoChecksum", version.Name)
+		}
+	}
+	return &versions, nil
+}
+
+// SettingVersions returns the versions of the upgrade-responder-versions setting
+func SettingVersions() (*CheckUpgradeResponse, error) {
+	value := settings.UpgradeResponderVersions.Get()
+	if value == "" {
+		return nil, nil
+	}
+	return ParseVersions(value)
+}
+
+// VersionsFunc returns the versions served by the responder, the responder is disabled if it returns nil
+type VersionsFunc func() (*CheckUpgradeResponse, error)
+
+// Handler serves the upgrade checks of the clusters with curated versions, so a fleet operator can serve the
+// versions to its own clusters instead of the public upgrade responder. The extra info of the requests isn't kept.
+type Handler struct {
+	versions VersionsFunc
+}
+
+func NewHandler(versions VersionsFunc) *Handler {
+	return &Handler{
+		versions: versions,
+	}
+}
+
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(rw, fmt.Sprintf("unsupported method %s", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	versions, err := h.versions()
+	if err != nil {
+		logrus.Errorf("failed to get the versions of the upgrade responder: %v", err)
+		http.Error(rw, "failed to get the versions", http.StatusInternalServerError)
+		return
+	}
+	if versions == nil {
+		http.Error(rw, "upgrade responder is disabled", http.StatusNotFound)
+		return
+	}
+
+	var checkReq CheckUpgradeRequest
+	if err := json.NewDecoder(io.LimitReader(req.Body, maxRequestSize)).Decode(&checkReq); err != nil {
+		http.Error(rw, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	logrus.Debugf("upgrade check from version %s on channel %q", checkReq.AppVersion, checkReq.ReleaseChannel)
+
+	resp := CheckUpgradeResponse{
+		Versions: FilterChannel(versions.Versions, checkReq.ReleaseChannel),
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(resp); err != nil {
+		logrus.Errorf("failed to write the upgrade check response: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package upgraderesponder
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testVersions = `
+versions:
+- name: v1.4.2
+  releaseDate: "2026-03-01T00:00:00Z"
+  tags: [stable, lts]
+  isoURL: https://releases.example.com/v1.4.2/cloudweav-v1.4.2-amd64.iso
+  isoChecksum: "1111"
+- name: v1.5.0
+  releaseDate: "2026-06-01T00:00:00Z"
+  minUpgradableVersion: v1.4.0
+  tags: [latest]
+  isoURL: https://releases.example.com/v1.5.0/cloudweav-v1.5.0-amd64.iso
+  isoChecksum: "2222"
+`
+
+func TestHandler(t *testing.T) {
+	versions, err := ParseVersions(testVersions)
+	require.NoError(t, err)
+
+	var testCases = []struct {
+		name           string
+		versions       *CheckUpgradeResponse
+		method         string
+		request        CheckUpgradeRequest
+		expectedStatus int
+		expectedNames  []string
+	}{
+		{
+			name:           "all channels",
+			versions:       versions,
+			method:         http.MethodPost,
+			request:        CheckUpgradeRequest{AppVersion: "v1.4.0"},
+			expectedStatus: http.StatusOK,
+			expectedNames:  []string{"v1.4.2", "v1.5.0"},
+		},
+		{
+			name:           "lts channel",
+			versions:       versions,
+			method:         http.MethodPost,
+			request:        CheckUpgradeRequest{AppVersion: "v1.4.0", ReleaseChannel: ReleaseChannelLTS},
+			expectedStatus: http.StatusOK,
+			expectedNames:  []string{"v1.4.2"},
+		},
+		{
+			name:           "disabled",
+			method:         http.MethodPost,
+			request:        CheckUpgradeRequest{AppVersion: "v1.4.0"},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "get",
+			versions:       versions,
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		handler := NewHandler(func() (*CheckUpgradeResponse, error) {
+			return tc.versions, nil
+		})
+		body, err := json.Marshal(tc.request)
+		require.NoError(t, err)
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(tc.method, "/v1/checkupgrade", bytes.NewReader(body)))
+
+		assert.Equal(t, tc.expectedStatus, rw.Code, tc.name)
+		if tc.expectedStatus != http.StatusOK {
+			continue
+		}
+		var resp CheckUpgradeResponse
+		require.NoError(t, json.NewDecoder(rw.Body).Decode(&resp), tc.name)
+		var names []string
+		for _, version := range resp.Versions {
+			names = append(names, version.Name)
+			assert.NotEmpty(t, version.ISOURL, tc.name)
+			assert.NotEmpty(t, version.ISOChecksum, tc.name)
+		}
+		assert.Equal(t, tc.expectedNames, names, tc.name)
+	}
+}
+
+func TestParseVersions(t *testing.T) {
+	_, err := ParseVersions(`{"versions":[{"name":"1.5.0","isoURL":"https://releases.example.com/cloudweav.iso","isoChecksum":"2222"}]}`)
+	assert.Error(t, err, "version without the v prefix")
+
+	_, err = ParseVersions(`{"versions":[{"name":"v1.5.0"}]}`)
+	assert.Error(t, err, "version without the ISO")
+
+	versions, err := ParseVersions(`{"versions":[{"name":"v1.5.0","tags":["stable"],"isoURL":"https://releases.example.com/cloudweav.iso","isoChecksum":"2222"}]}`)
+	require.NoError(t, err)
+	assert.True(t, versions.Versions[0].InChannel(ReleaseChannelStable))
+	assert.False(t, versions.Versions[0].InChannel(ReleaseChannelLatest))
+}
@@ -0,0 +1,139 @@
+package bmc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+const (
+	SecretKeyUsername = "username"
+	SecretKeyPassword = "password"
+
+	OperationPowerOn             = "poweron"
+	OperationShutdown            = "shutdown"
+	OperationPowerOff            = "poweroff"
+	OperationReboot              = "reboot"
+	OperationPXEBoot             = "pxeboot"
+	OperationMountVirtualMedia   = "mountvirtualmedia"
+	OperationUnmountVirtualMedia = "unmountvirtualmedia"
+
+	PowerStateOn  = "On"
+	PowerStateOff = "Off"
+
+	HealthOK       = "OK"
+	HealthWarning  = "Warning"
+	HealthCritical = "Critical"
+)
+
+var (
+	Operations = []string{
+		OperationPowerOn,
+		OperationShutdown,
+		OperationPowerOff,
+		OperationReboot,
+		OperationPXEBoot,
+		OperationMountVirtualMedia,
+		OperationUnmountVirtualMedia,
+	}
+
+	ErrUnsupported = errors.New("operation is not supported by the BMC protocol")
+)
+
+type BootDevice string
+
+const (
+	BootDevicePXE BootDevice = "pxe"
+	BootDeviceCD  BootDevice = "cd"
+)
+
+// Status is the power state and health read from the BMC
+type Status struct {
+	PowerState string
+	Health     string
+	Sensors    []cloudweavv1.NodeBMCSensor
+}
+
+// Client controls the power and boot of a node through its BMC
+type Client interface {
+	Status(ctx context.Context) (*Status, error)
+	// Power runs one of the poweron, shutdown, poweroff and reboot operations
+	Power(ctx context.Context, operation string) error
+	// SetBootOnce makes the node boot from the device on the next boot only
+	SetBootOnce(ctx context.Context, device BootDevice) error
+	InsertVirtualMedia(ctx context.Context, image string) error
+	EjectVirtualMedia(ctx context.Context) error
+}
+
+// NewClient returns the client of the BMC protocol with the credentials of the secret
+func NewClient(spec cloudweavv1.NodeBMCSpec, secret *corev1.Secret) (Client, error) {
+	username, password := string(secret.Data[SecretKeyUsername]), string(secret.Data[SecretKeyPassword])
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("secret %s/%s must contain the %s and %s keys", secret.Namespace, secret.Name, SecretKeyUsername, SecretKeyPassword)
+	}
+
+	switch spec.Protocol {
+	case cloudweavv1.NodeBMCProtocolRedfish:
+		return newRedfishClient(spec.Address, spec.SystemID, username, password, spec.InsecureSkipVerify)
+	case cloudweavv1.NodeBMCProtocolIPMI:
+		return newIPMIClient(spec.Address, username, password)
+	default:
+		return nil, fmt.Errorf("unsupported BMC protocol %q", spec.Protocol)
+	}
+}
+
+// Do runs the operation on the node. Booting from PXE sets the one-time boot device before
+// restarting the node, or powering it on when it is off. Mounting a virtual media also makes
+// it the one-time boot device, the node boots from it on the next reboot.
+func Do(ctx context.Context, client Client, operation, image string) error {
+	switch operation {
+	case OperationPowerOn, OperationShutdown, OperationPowerOff, OperationReboot:
+		return client.Power(ctx, operation)
+	case OperationPXEBoot:
+		if err := client.SetBootOnce(ctx, BootDevicePXE); err != nil {
+			return err
+		}
+		status, err := client.Status(ctx)
+		if err != nil {
+			return err
+		}
+		if status.PowerState == PowerStateOff {
+			return client.Power(ctx, OperationPowerOn)
+		}
+		return client.Power(ctx, OperationReboot)
+	case OperationMountVirtualMedia:
+		if image == "" {
+			return errors.New("image is required to mount a virtual media")
+		}
+		if err := client.InsertVirtualMedia(ctx, image); err != nil {
+			return err
+		}
+		return client.SetBootOnce(ctx, BootDeviceCD)
+	case OperationUnmountVirtualMedia:
+		return client.EjectVirtualMedia(ctx)
+	default:
+		return fmt.Errorf("operation %s is not a valid BMC operation. valid values need to be in %v", operation, Operations)
+	}
+}
+
+// WorstHealth returns the most severe of the health values
+func WorstHealth(healths ...string) string {
+	result := ""
+	for _, health := range healths {
+		switch health {
+		case HealthCritical:
+			return HealthCritical
+		case HealthWarning:
+			result = HealthWarning
+		case HealthOK:
+			if result == "" {
+				result = HealthOK
+			}
+		}
+	}
+	return result
+}
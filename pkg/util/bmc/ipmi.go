@@ -0,0 +1,172 @@
+package bmc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+const (
+	ipmitoolCommand = "ipmitool"
+	// ipmitool reads the password of the -E flag from this variable, so it doesn't show in the process list
+	ipmiPasswordEnv = "IPMI_PASSWORD"
+)
+
+var ipmiPowerCommands = map[string]string{
+	OperationPowerOn:  "on",
+	OperationShutdown: "soft",
+	OperationPowerOff: "off",
+	OperationReboot:   "reset",
+}
+
+var ipmiBootDevices = map[BootDevice]string{
+	BootDevicePXE: "pxe",
+	BootDeviceCD:  "cdrom",
+}
+
+type commandRunner func(ctx context.Context, env []string, name string, args ...string) ([]byte, error)
+
+func runCommand(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return output, fmt.Errorf("%s %s failed: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return output, nil
+}
+
+// ipmiClient runs ipmitool over the IPMI v2.0 LAN interface of the BMC
+type ipmiClient struct {
+	host     string
+	port     string
+	username string
+	password string
+	run      commandRunner
+}
+
+func newIPMIClient(address, username, password string) (*ipmiClient, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host, port = address, "623"
+	}
+	if host == "" {
+		return nil, fmt.Errorf("ipmi address %s has no host", address)
+	}
+	return &ipmiClient{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		run:      runCommand,
+	}, nil
+}
+
+func (c *ipmiClient) ipmitool(ctx context.Context, args ...string) (string, error) {
+	args = append([]string{"-I", "lanplus", "-H", c.host, "-p", c.port, "-U", c.username, "-E"}, args...)
+	output, err := c.run(ctx, []string{ipmiPasswordEnv + "=" + c.password}, ipmitoolCommand, args...)
+	return string(output), err
+}
+
+func (c *ipmiClient) Status(ctx context.Context) (*Status, error) {
+	output, err := c.ipmitool(ctx, "chassis", "power", "status")
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{}
+	switch {
+	case strings.Contains(output, "is on"):
+		status.PowerState = PowerStateOn
+	case strings.Contains(output, "is off"):
+		status.PowerState = PowerStateOff
+	default:
+		return nil, fmt.Errorf("unexpected chassis power status %q", strings.TrimSpace(output))
+	}
+
+	output, err = c.ipmitool(ctx, "sdr", "elist", "full")
+	if err != nil {
+		return nil, err
+	}
+	status.Sensors = parseIPMISensors(output)
+	healths := make([]string, 0, len(status.Sensors))
+	for _, sensor := range status.Sensors {
+		healths = append(healths, sensor.Health)
+	}
+	status.Health = WorstHealth(healths...)
+	return status, nil
+}
+
+// parseIPMISensors parses the output of "ipmitool sdr elist full", whose lines look like
+// "CPU Temp         | 30h | ok  |  3.1 | 45 degrees C".
+func parseIPMISensors(output string) []cloudweavv1.NodeBMCSensor {
+	var sensors []cloudweavv1.NodeBMCSensor
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "|")
+		if len(fields) != 5 {
+			continue
+		}
+		state := strings.TrimSpace(fields[2])
+		if state == "ns" {
+			// no reading, the sensor is absent or disabled
+			continue
+		}
+		sensor := cloudweavv1.NodeBMCSensor{
+			Name:   strings.TrimSpace(fields[0]),
+			Health: ipmiSensorHealth(state),
+		}
+		// analog sensors report a number and its units, discrete ones a text
+		reading := strings.Fields(fields[4])
+		if len(reading) > 0 {
+			if _, err := strconv.ParseFloat(reading[0], 64); err == nil {
+				sensor.Reading = reading[0]
+				sensor.Units = strings.Join(reading[1:], " ")
+			} else {
+				sensor.Reading = strings.Join(reading, " ")
+			}
+		}
+		sensors = append(sensors, sensor)
+	}
+	return sensors
+}
+
+func ipmiSensorHealth(state string) string {
+	switch state {
+	case "ok":
+		return HealthOK
+	case "nc":
+		return HealthWarning
+	case "cr", "nr":
+		return HealthCritical
+	default:
+		return ""
+	}
+}
+
+func (c *ipmiClient) Power(ctx context.Context, operation string) error {
+	command, ok := ipmiPowerCommands[operation]
+	if !ok {
+		return fmt.Errorf("operation %s is not a power operation", operation)
+	}
+	_, err := c.ipmitool(ctx, "chassis", "power", command)
+	return err
+}
+
+func (c *ipmiClient) SetBootOnce(ctx context.Context, device BootDevice) error {
+	// without the persistent option, the boot device is only used on the next boot
+	_, err := c.ipmitool(ctx, "chassis", "bootdev", ipmiBootDevices[device])
+	return err
+}
+
+func (c *ipmiClient) InsertVirtualMedia(_ context.Context, _ string) error {
+	return fmt.Errorf("virtual media over IPMI: %w", ErrUnsupported)
+}
+
+func (c *ipmiClient) EjectVirtualMedia(_ context.Context) error {
+	return fmt.Errorf("virtual media over IPMI: %w", ErrUnsupported)
+}
@@ -0,0 +1,54 @@
+package bmc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+const sdrOutput = `CPU Temp         | 30h | ok  |  3.1 | 45 degrees C
+System Temp      | 31h | nc  |  7.1 | 78 degrees C
+FAN1             | 41h | ok  | 29.1 | 4200 RPM
+FAN2             | 42h | ns  | 29.2 | No Reading
+PS1 Status       | C8h | cr  | 10.1 | Presence detected, Failure detected
+`
+
+func TestIPMIStatus(t *testing.T) {
+	client, err := newIPMIClient("10.0.0.5", "admin", "secret")
+	require.NoError(t, err)
+
+	var commands []string
+	client.run = func(_ context.Context, env []string, name string, args ...string) ([]byte, error) {
+		assert.Equal(t, []string{"IPMI_PASSWORD=secret"}, env)
+		assert.NotContains(t, args, "secret")
+		command := strings.Join(args[9:], " ")
+		commands = append(commands, command)
+		switch command {
+		case "chassis power status":
+			return []byte("Chassis Power is on\n"), nil
+		case "sdr elist full":
+			return []byte(sdrOutput), nil
+		}
+		return nil, errors.New("unexpected command")
+	}
+
+	status, err := client.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, PowerStateOn, status.PowerState)
+	assert.Equal(t, HealthCritical, status.Health)
+	assert.Equal(t, []cloudweavv1.NodeBMCSensor{
+		{Name: "CPU Temp", Reading: "45", Units: "degrees C", Health: HealthOK},
+		{Name: "System Temp", Reading: "78", Units: "degrees C", Health: HealthWarning},
+		{Name: "FAN1", Reading: "4200", Units: "RPM", Health: HealthOK},
+		{Name: "PS1 Status", Reading: "Presence detected, Failure detected", Health: HealthCritical},
+	}, status.Sensors)
+
+	assert.ErrorIs(t, Do(context.Background(), client, OperationMountVirtualMedia, "http://10.0.0.1/installer.iso"), ErrUnsupported)
+	assert.Equal(t, []string{"chassis power status", "sdr elist full"}, commands)
+}
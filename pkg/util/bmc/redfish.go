@@ -0,0 +1,335 @@
+package bmc
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+const (
+	redfishServiceRoot = "/redfish/v1"
+	redfishTimeout     = 30 * time.Second
+)
+
+var redfishResetTypes = map[string]string{
+	OperationPowerOn:  "On",
+	OperationShutdown: "GracefulShutdown",
+	OperationPowerOff: "ForceOff",
+	OperationReboot:   "ForceRestart",
+}
+
+var redfishBootTargets = map[BootDevice]string{
+	BootDevicePXE: "Pxe",
+	BootDeviceCD:  "Cd",
+}
+
+// redfishClient talks to the Redfish service of the BMC with basic authentication
+type redfishClient struct {
+	endpoint   string
+	systemID   string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+type redfishLink struct {
+	ID string `json:"@odata.id"`
+}
+
+type redfishCollection struct {
+	Members []redfishLink `json:"Members"`
+}
+
+type redfishStatus struct {
+	Health string `json:"Health"`
+	State  string `json:"State"`
+}
+
+type redfishSystem struct {
+	PowerState string        `json:"PowerState"`
+	Status     redfishStatus `json:"Status"`
+	Links      struct {
+		Chassis   []redfishLink `json:"Chassis"`
+		ManagedBy []redfishLink `json:"ManagedBy"`
+	} `json:"Links"`
+	Actions struct {
+		Reset struct {
+			Target string `json:"target"`
+		} `json:"#ComputerSystem.Reset"`
+	} `json:"Actions"`
+}
+
+type redfishChassis struct {
+	Thermal redfishLink `json:"Thermal"`
+}
+
+type redfishThermal struct {
+	Temperatures []struct {
+		Name           string        `json:"Name"`
+		ReadingCelsius *float64      `json:"ReadingCelsius"`
+		Status         redfishStatus `json:"Status"`
+	} `json:"Temperatures"`
+	Fans []struct {
+		Name         string        `json:"Name"`
+		Reading      *float64      `json:"Reading"`
+		ReadingUnits string        `json:"ReadingUnits"`
+		Status       redfishStatus `json:"Status"`
+	} `json:"Fans"`
+}
+
+type redfishManager struct {
+	VirtualMedia redfishLink `json:"VirtualMedia"`
+}
+
+type redfishVirtualMedia struct {
+	MediaTypes []string `json:"MediaTypes"`
+	Actions    struct {
+		InsertMedia struct {
+			Target string `json:"target"`
+		} `json:"#VirtualMedia.InsertMedia"`
+		EjectMedia struct {
+			Target string `json:"target"`
+		} `json:"#VirtualMedia.EjectMedia"`
+	} `json:"Actions"`
+}
+
+func newRedfishClient(address, systemID, username, password string, insecureSkipVerify bool) (*redfishClient, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("redfish address %s must be a http or https URL", address)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+	return &redfishClient{
+		endpoint: strings.TrimSuffix(u.String(), "/"),
+		systemID: systemID,
+		username: username,
+		password: password,
+		httpClient: &http.Client{
+			Timeout:   redfishTimeout,
+			Transport: transport,
+		},
+	}, nil
+}
+
+func (c *redfishClient) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, reader)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("redfish %s %s failed with status %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// systemPath returns the path of the computer system, the first one of the service if no system ID is configured
+func (c *redfishClient) systemPath(ctx context.Context) (string, error) {
+	if c.systemID != "" {
+		return fmt.Sprintf("%s/Systems/%s", redfishServiceRoot, c.systemID), nil
+	}
+	systems := &redfishCollection{}
+	if err := c.do(ctx, http.MethodGet, redfishServiceRoot+"/Systems", nil, systems); err != nil {
+		return "", err
+	}
+	if len(systems.Members) == 0 {
+		return "", fmt.Errorf("redfish service %s has no computer system", c.endpoint)
+	}
+	return systems.Members[0].ID, nil
+}
+
+func (c *redfishClient) system(ctx context.Context) (string, *redfishSystem, error) {
+	path, err := c.systemPath(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	system := &redfishSystem{}
+	if err := c.do(ctx, http.MethodGet, path, nil, system); err != nil {
+		return "", nil, err
+	}
+	return path, system, nil
+}
+
+func (c *redfishClient) Status(ctx context.Context) (*Status, error) {
+	_, system, err := c.system(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{
+		PowerState: system.PowerState,
+		Health:     system.Status.Health,
+	}
+	if len(system.Links.Chassis) == 0 {
+		return status, nil
+	}
+
+	chassis := &redfishChassis{}
+	if err := c.do(ctx, http.MethodGet, system.Links.Chassis[0].ID, nil, chassis); err != nil {
+		return nil, err
+	}
+	if chassis.Thermal.ID == "" {
+		return status, nil
+	}
+	thermal := &redfishThermal{}
+	if err := c.do(ctx, http.MethodGet, chassis.Thermal.ID, nil, thermal); err != nil {
+		return nil, err
+	}
+	for _, temperature := range thermal.Temperatures {
+		if temperature.Status.State == "Absent" {
+			continue
+		}
+		sensor := cloudweavv1.NodeBMCSensor{
+			Name:   temperature.Name,
+			Units:  "Cel",
+			Health: temperature.Status.Health,
+		}
+		if temperature.ReadingCelsius != nil {
+			sensor.Reading = fmt.Sprintf("%g", *temperature.ReadingCelsius)
+		}
+		status.Sensors = append(status.Sensors, sensor)
+	}
+	for _, fan := range thermal.Fans {
+		if fan.Status.State == "Absent" {
+			continue
+		}
+		sensor := cloudweavv1.NodeBMCSensor{
+			Name:   fan.Name,
+			Units:  fan.ReadingUnits,
+			Health: fan.Status.Health,
+		}
+		if fan.Reading != nil {
+			sensor.Reading = fmt.Sprintf("%g", *fan.Reading)
+		}
+		status.Sensors = append(status.Sensors, sensor)
+	}
+	return status, nil
+}
+
+func (c *redfishClient) Power(ctx context.Context, operation string) error {
+	resetType, ok := redfishResetTypes[operation]
+	if !ok {
+		return fmt.Errorf("operation %s is not a power operation", operation)
+	}
+	path, system, err := c.system(ctx)
+	if err != nil {
+		return err
+	}
+	target := system.Actions.Reset.Target
+	if target == "" {
+		target = path + "/Actions/ComputerSystem.Reset"
+	}
+	return c.do(ctx, http.MethodPost, target, map[string]string{"ResetType": resetType}, nil)
+}
+
+func (c *redfishClient) SetBootOnce(ctx context.Context, device BootDevice) error {
+	path, err := c.systemPath(ctx)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPatch, path, map[string]interface{}{
+		"Boot": map[string]string{
+			"BootSourceOverrideTarget":  redfishBootTargets[device],
+			"BootSourceOverrideEnabled": "Once",
+		},
+	}, nil)
+}
+
+// cdVirtualMedia returns the virtual media of the system manager that accepts CD images
+func (c *redfishClient) cdVirtualMedia(ctx context.Context) (*redfishVirtualMedia, string, error) {
+	_, system, err := c.system(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(system.Links.ManagedBy) == 0 {
+		return nil, "", fmt.Errorf("redfish system has no manager: %w", ErrUnsupported)
+	}
+	manager := &redfishManager{}
+	if err := c.do(ctx, http.MethodGet, system.Links.ManagedBy[0].ID, nil, manager); err != nil {
+		return nil, "", err
+	}
+	if manager.VirtualMedia.ID == "" {
+		return nil, "", fmt.Errorf("redfish manager has no virtual media: %w", ErrUnsupported)
+	}
+	collection := &redfishCollection{}
+	if err := c.do(ctx, http.MethodGet, manager.VirtualMedia.ID, nil, collection); err != nil {
+		return nil, "", err
+	}
+	for _, member := range collection.Members {
+		media := &redfishVirtualMedia{}
+		if err := c.do(ctx, http.MethodGet, member.ID, nil, media); err != nil {
+			return nil, "", err
+		}
+		if slices.Contains(media.MediaTypes, "CD") || slices.Contains(media.MediaTypes, "DVD") {
+			return media, member.ID, nil
+		}
+	}
+	return nil, "", fmt.Errorf("redfish manager has no CD virtual media: %w", ErrUnsupported)
+}
+
+func (c *redfishClient) InsertVirtualMedia(ctx context.Context, image string) error {
+	media, path, err := c.cdVirtualMedia(ctx)
+	if err != nil {
+		return err
+	}
+	target := media.Actions.InsertMedia.Target
+	if target == "" {
+		target = path + "/Actions/VirtualMedia.InsertMedia"
+	}
+	return c.do(ctx, http.MethodPost, target, map[string]interface{}{
+		"Image":          image,
+		"Inserted":       true,
+		"WriteProtected": true,
+	}, nil)
+}
+
+func (c *redfishClient) EjectVirtualMedia(ctx context.Context) error {
+	media, path, err := c.cdVirtualMedia(ctx)
+	if err != nil {
+		return err
+	}
+	target := media.Actions.EjectMedia.Target
+	if target == "" {
+		target = path + "/Actions/VirtualMedia.EjectMedia"
+	}
+	return c.do(ctx, http.MethodPost, target, map[string]interface{}{}, nil)
+}
@@ -0,0 +1,181 @@
+package bmc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+// redfishMock is a minimal Redfish service with one system, one chassis and one manager with a CD virtual media
+type redfishMock struct {
+	mu           sync.Mutex
+	powerState   string
+	bootTarget   string
+	mediaImage   string
+	resetTypes   []string
+	unauthorized int
+}
+
+func (m *redfishMock) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if username, password, ok := req.BasicAuth(); !ok || username != "admin" || password != "secret" {
+		m.unauthorized++
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var body map[string]interface{}
+	if req.Body != nil {
+		_ = json.NewDecoder(req.Body).Decode(&body)
+	}
+
+	var resp interface{}
+	switch req.Method + " " + req.URL.Path {
+	case "GET /redfish/v1/Systems":
+		resp = map[string]interface{}{
+			"Members": []interface{}{map[string]string{"@odata.id": "/redfish/v1/Systems/1"}},
+		}
+	case "GET /redfish/v1/Systems/1":
+		resp = map[string]interface{}{
+			"PowerState": m.powerState,
+			"Status":     map[string]string{"Health": "OK", "State": "Enabled"},
+			"Links": map[string]interface{}{
+				"Chassis":   []interface{}{map[string]string{"@odata.id": "/redfish/v1/Chassis/1"}},
+				"ManagedBy": []interface{}{map[string]string{"@odata.id": "/redfish/v1/Managers/1"}},
+			},
+			"Actions": map[string]interface{}{
+				"#ComputerSystem.Reset": map[string]string{"target": "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset"},
+			},
+		}
+	case "PATCH /redfish/v1/Systems/1":
+		boot := body["Boot"].(map[string]interface{})
+		m.bootTarget = boot["BootSourceOverrideTarget"].(string)
+	case "POST /redfish/v1/Systems/1/Actions/ComputerSystem.Reset":
+		resetType := body["ResetType"].(string)
+		m.resetTypes = append(m.resetTypes, resetType)
+		if resetType == "ForceOff" || resetType == "GracefulShutdown" {
+			m.powerState = PowerStateOff
+		} else {
+			m.powerState = PowerStateOn
+		}
+	case "GET /redfish/v1/Chassis/1":
+		resp = map[string]interface{}{"Thermal": map[string]string{"@odata.id": "/redfish/v1/Chassis/1/Thermal"}}
+	case "GET /redfish/v1/Chassis/1/Thermal":
+		resp = map[string]interface{}{
+			"Temperatures": []interface{}{
+				map[string]interface{}{"Name": "CPU1 Temp", "ReadingCelsius": 45, "Status": map[string]string{"Health": "OK", "State": "Enabled"}},
+				map[string]interface{}{"Name": "CPU2 Temp", "Status": map[string]string{"State": "Absent"}},
+			},
+			"Fans": []interface{}{
+				map[string]interface{}{"Name": "Fan1", "Reading": 4200, "ReadingUnits": "RPM", "Status": map[string]string{"Health": "Warning", "State": "Enabled"}},
+			},
+		}
+	case "GET /redfish/v1/Managers/1":
+		resp = map[string]interface{}{"VirtualMedia": map[string]string{"@odata.id": "/redfish/v1/Managers/1/VirtualMedia"}}
+	case "GET /redfish/v1/Managers/1/VirtualMedia":
+		resp = map[string]interface{}{
+			"Members": []interface{}{
+				map[string]string{"@odata.id": "/redfish/v1/Managers/1/VirtualMedia/Floppy1"},
+				map[string]string{"@odata.id": "/redfish/v1/Managers/1/VirtualMedia/Cd1"},
+			},
+		}
+	case "GET /redfish/v1/Managers/1/VirtualMedia/Floppy1":
+		resp = map[string]interface{}{"MediaTypes": []string{"Floppy", "USBStick"}}
+	case "GET /redfish/v1/Managers/1/VirtualMedia/Cd1":
+		resp = map[string]interface{}{"MediaTypes": []string{"CD", "DVD"}, "Image": m.mediaImage}
+	case "POST /redfish/v1/Managers/1/VirtualMedia/Cd1/Actions/VirtualMedia.InsertMedia":
+		m.mediaImage = body["Image"].(string)
+	case "POST /redfish/v1/Managers/1/VirtualMedia/Cd1/Actions/VirtualMedia.EjectMedia":
+		m.mediaImage = ""
+	default:
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if resp == nil {
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(resp)
+}
+
+func newMockClient(t *testing.T, mock *redfishMock, password string) Client {
+	server := httptest.NewTLSServer(mock)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(cloudweavv1.NodeBMCSpec{
+		Protocol:           cloudweavv1.NodeBMCProtocolRedfish,
+		Address:            server.URL,
+		InsecureSkipVerify: true,
+	}, &corev1.Secret{
+		Data: map[string][]byte{
+			SecretKeyUsername: []byte("admin"),
+			SecretKeyPassword: []byte(password),
+		},
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func TestRedfishStatus(t *testing.T) {
+	mock := &redfishMock{powerState: PowerStateOn}
+	client := newMockClient(t, mock, "secret")
+
+	status, err := client.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, PowerStateOn, status.PowerState)
+	assert.Equal(t, HealthOK, status.Health)
+	assert.Equal(t, []cloudweavv1.NodeBMCSensor{
+		{Name: "CPU1 Temp", Reading: "45", Units: "Cel", Health: HealthOK},
+		{Name: "Fan1", Reading: "4200", Units: "RPM", Health: HealthWarning},
+	}, status.Sensors)
+}
+
+func TestRedfishUnauthorized(t *testing.T) {
+	mock := &redfishMock{powerState: PowerStateOn}
+	client := newMockClient(t, mock, "wrong")
+
+	_, err := client.Status(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, mock.unauthorized)
+}
+
+func TestRedfishOperations(t *testing.T) {
+	ctx := context.Background()
+	mock := &redfishMock{powerState: PowerStateOff}
+	client := newMockClient(t, mock, "secret")
+
+	// the node is off, booting from PXE powers it on
+	require.NoError(t, Do(ctx, client, OperationPXEBoot, ""))
+	assert.Equal(t, "Pxe", mock.bootTarget)
+	assert.Equal(t, []string{"On"}, mock.resetTypes)
+
+	// the node is on, booting from PXE restarts it
+	require.NoError(t, Do(ctx, client, OperationPXEBoot, ""))
+	assert.Equal(t, []string{"On", "ForceRestart"}, mock.resetTypes)
+
+	require.NoError(t, Do(ctx, client, OperationShutdown, ""))
+	assert.Equal(t, PowerStateOff, mock.powerState)
+
+	assert.Error(t, Do(ctx, client, OperationMountVirtualMedia, ""))
+	require.NoError(t, Do(ctx, client, OperationMountVirtualMedia, "http://10.0.0.1/installer.iso"))
+	assert.Equal(t, "http://10.0.0.1/installer.iso", mock.mediaImage)
+	assert.Equal(t, "Cd", mock.bootTarget)
+
+	require.NoError(t, Do(ctx, client, OperationUnmountVirtualMedia, ""))
+	assert.Equal(t, "", mock.mediaImage)
+
+	assert.Error(t, Do(ctx, client, "selfdestruct", ""))
+}
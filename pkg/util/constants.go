@@ -12,7 +12,16 @@ const (
 	AnnotationVolumeClaimTemplates      = prefix + "/volumeClaimTemplates"
 	AnnotationUpgradePatched            = prefix + "/upgrade-patched"
 	AnnotationImageID                   = prefix + "/imageId"
+	AnnotationNodeCacheDisks            = prefix + "/nodeCacheDisks"
+	AnnotationUpgradeISO                = prefix + "/upgradeISO"
+	AnnotationUpgradeISOSignature       = prefix + "/upgradeISOSignature"
+	AnnotationTemplateVersionID         = prefix + "/templateVersionId"
+	AnnotationTemplateParameters        = prefix + "/templateParameters"
 	AnnotationReservedMemory            = prefix + "/reservedMemory"
+	AnnotationSSHNames                  = prefix + "/sshNames"
+	AnnotationSSHKeyPropagationUsers    = prefix + "/sshKeyPropagationUsers"
+	AnnotationHAPolicy                  = prefix + "/haPolicy"
+	AnnotationHAStatus                  = prefix + "/haStatus"
 	AnnotationHash                      = prefix + "/hash"
 	AnnotationRunStrategy               = prefix + "/vmRunStrategy"
 	AnnotationSnapshotFreezeFS          = prefix + "/snapshotFreezeFS"
@@ -147,6 +156,11 @@ const (
 	LabelCPUManagerUpdateNode        = prefix + "/cpu-manager-update-node"
 	LabelCPUManagerUpdatePolicy      = prefix + "/cpu-manager-update-policy"
 	LabelCPUManagerExitCode          = prefix + "/cpu-manager-exit-code"
+	LabelCPUManagerUpdateNUMA        = prefix + "/cpu-manager-update-numa"
+	// LabelNUMAAware is set on the nodes running the static memory manager and the single-numa-node topology manager
+	LabelNUMAAware = prefix + "/numa-aware"
+	// AnnotationNUMATopology is the NUMA topology of the node reported by the node agent
+	AnnotationNUMATopology = prefix + "/numa-topology"
 
 	VClusterNamespace          = "rancher-vcluster"
 	LablelVClusterAppNameKey   = "app"
@@ -17,6 +17,7 @@ import (
 	"k8s.io/kubectl/pkg/drain"
 
 	ctlnode "github.com/cloudweav/cloudweav/pkg/controller/master/node"
+	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
 	"github.com/cloudweav/cloudweav/pkg/util"
 )
 
@@ -30,8 +31,9 @@ const (
 	defaultSkipPodLabels      = "app!=csi-attacher,app!=csi-provisioner"
 	defaultGracePeriodSeconds = 180
 	defaultTimeOut            = 240 * time.Second
-	DrainAnnotation           = "cloudweavhci.io/drain-requested"
+	DrainAnnotation           = ctlnode.DrainRequestedAnnotationKey
 	ForcedDrain               = "cloudweavhci.io/drain-forced"
+	drainTaintKey             = "kubevirt.io/drain"
 	defaultSingleCPCount      = 1
 	defaultHACPCount          = 3
 )
@@ -143,6 +145,57 @@ func DrainPossible(nodeCache ctlcorev1.NodeCache, node *corev1.Node) error {
 	return nil
 }
 
+// DisableMaintenanceMode uncordons the node, and removes the drain taint and the maintenance mode annotations
+func DisableMaintenanceMode(node *corev1.Node) {
+	node.Spec.Unschedulable = false
+	for i, taint := range node.Spec.Taints {
+		if taint.Key == drainTaintKey {
+			node.Spec.Taints = append(node.Spec.Taints[:i], node.Spec.Taints[i+1:]...)
+			break
+		}
+	}
+	delete(node.Annotations, DrainAnnotation)
+	delete(node.Annotations, ForcedDrain)
+	delete(node.Annotations, ctlnode.MaintainStatusAnnotationKey)
+}
+
+// RestartMaintenanceModeVMs starts the VMs that were shut down by the maintenance mode of the node
+// and should be restarted once the maintenance mode is disabled again
+func RestartMaintenanceModeVMs(ctx context.Context, nodeName string, vmCache ctlkubevirtv1.VirtualMachineCache,
+	vmClient ctlkubevirtv1.VirtualMachineClient, virtSubresourceRestClient rest.Interface) error {
+	selector := labels.Set{util.LabelMaintainModeStrategy: util.MaintainModeStrategyShutdownAndRestartAfterDisable}.AsSelector()
+	vmList, err := vmCache.List(corev1.NamespaceAll, selector)
+	if err != nil {
+		return fmt.Errorf("failed to list VMs with labels %s: %w", selector.String(), err)
+	}
+	for _, vm := range vmList {
+		// Make sure that this VM was shut down as part of the maintenance
+		// mode of the given node.
+		if vm.Annotations[util.AnnotationMaintainModeStrategyNodeName] != nodeName {
+			continue
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"namespace":           vm.Namespace,
+			"virtualmachine_name": vm.Name,
+		}).Info("restarting the VM that was shut down in maintenance mode")
+
+		err := virtSubresourceRestClient.Put().Namespace(vm.Namespace).Resource("virtualmachines").SubResource("start").Name(vm.Name).Do(ctx).Error()
+		if err != nil {
+			return fmt.Errorf("failed to start VM %s/%s: %w", vm.Namespace, vm.Name, err)
+		}
+
+		// Remove the annotation that was previously set when the node
+		// went into maintenance mode.
+		vmCopy := vm.DeepCopy()
+		delete(vmCopy.Annotations, util.AnnotationMaintainModeStrategyNodeName)
+		if _, err := vmClient.Update(vmCopy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func maintainModeStrategyFilter(pod corev1.Pod) drain.PodDeleteStatus {
 	// Ignore VMs that should not be migrated in maintenance mode. These
 	// VMs are forcibly shut down when maintenance mode is activated.
@@ -158,3 +158,29 @@ func Test_maintainModeStrategyFilter_Okay_2(t *testing.T) {
 	assert.True(status.Delete)
 	assert.Equal(status.Reason, drain.PodDeleteStatusTypeOkay)
 }
+
+func Test_DisableMaintenanceMode(t *testing.T) {
+	assert := require.New(t)
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+			Annotations: map[string]string{
+				DrainAnnotation:                     "true",
+				ForcedDrain:                         "true",
+				ctlnode.MaintainStatusAnnotationKey: ctlnode.MaintainStatusComplete,
+				"foo":                               "bar",
+			},
+		},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+			Taints: []corev1.Taint{
+				{Key: drainTaintKey, Effect: corev1.TaintEffectNoSchedule},
+				{Key: "foo", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+	}
+	DisableMaintenanceMode(node)
+	assert.False(node.Spec.Unschedulable)
+	assert.Equal([]corev1.Taint{{Key: "foo", Effect: corev1.TaintEffectNoSchedule}}, node.Spec.Taints)
+	assert.Equal(map[string]string{"foo": "bar"}, node.Annotations)
+}
@@ -30,8 +30,8 @@ func (c DeploymentClient) UpdateStatus(*appsv1.Deployment) (*appsv1.Deployment,
 	panic("implement me")
 }
 
-func (c DeploymentClient) Delete(_, _ string, _ *metav1.DeleteOptions) error {
-	panic("implement me")
+func (c DeploymentClient) Delete(namespace, name string, options *metav1.DeleteOptions) error {
+	return c(namespace).Delete(context.TODO(), name, *options)
 }
 
 func (c DeploymentClient) List(_ string, _ metav1.ListOptions) (*appsv1.DeploymentList, error) {
@@ -10,12 +10,13 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	batchv1type "k8s.io/client-go/kubernetes/typed/batch/v1"
+	"k8s.io/client-go/rest"
 )
 
 type JobCache func(string) batchv1type.JobInterface
 
-func (c JobCache) Get(_, _ string) (*batchv1.Job, error) {
-	panic("implement me")
+func (c JobCache) Get(namespace, name string) (*batchv1.Job, error) {
+	return c(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 }
 
 func (c JobCache) List(namespace string, selector labels.Selector) ([]*batchv1.Job, error) {
@@ -46,20 +47,20 @@ type JobClient func(string) batchv1type.JobInterface
 func (c JobClient) Update(job *batchv1.Job) (*batchv1.Job, error) {
 	return c(job.Namespace).Update(context.TODO(), job, metav1.UpdateOptions{})
 }
-func (c JobClient) Get(_, _ string, _ metav1.GetOptions) (*batchv1.Job, error) {
-	panic("implement me")
+func (c JobClient) Get(namespace, name string, options metav1.GetOptions) (*batchv1.Job, error) {
+	return c(namespace).Get(context.TODO(), name, options)
 }
-func (c JobClient) Create(*batchv1.Job) (*batchv1.Job, error) {
-	panic("implement me")
+func (c JobClient) Create(job *batchv1.Job) (*batchv1.Job, error) {
+	return c(job.Namespace).Create(context.TODO(), job, metav1.CreateOptions{})
 }
 func (c JobClient) UpdateStatus(*batchv1.Job) (*batchv1.Job, error) {
 	panic("implement me")
 }
-func (c JobClient) Delete(_, _ string, _ *metav1.DeleteOptions) error {
-	panic("implement me")
+func (c JobClient) Delete(namespace, name string, options *metav1.DeleteOptions) error {
+	return c(namespace).Delete(context.TODO(), name, *options)
 }
-func (c JobClient) List(_ string, _ metav1.ListOptions) (*batchv1.Job, error) {
-	panic("implement me")
+func (c JobClient) List(namespace string, opts metav1.ListOptions) (*batchv1.JobList, error) {
+	return c(namespace).List(context.TODO(), opts)
 }
 func (c JobClient) Watch(_ string, _ metav1.ListOptions) (watch.Interface, error) {
 	panic("implement me")
@@ -67,3 +68,6 @@ func (c JobClient) Watch(_ string, _ metav1.ListOptions) (watch.Interface, error
 func (c JobClient) Patch(_, _ string, _ types.PatchType, _ []byte, _ ...string) (result *batchv1.Job, err error) {
 	panic("implement me")
 }
+func (c JobClient) WithImpersonation(_ rest.ImpersonationConfig) (generic.ClientInterface[*batchv1.Job, *batchv1.JobList], error) {
+	panic("implement me")
+}
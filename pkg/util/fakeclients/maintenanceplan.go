@@ -0,0 +1,68 @@
+package fakeclients
+
+import (
+	"context"
+
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	harv1type "github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/typed/cloudweavhci.io/v1beta1"
+)
+
+type MaintenancePlanClient func() harv1type.MaintenancePlanInterface
+
+func (c MaintenancePlanClient) Create(plan *cloudweavv1.MaintenancePlan) (*cloudweavv1.MaintenancePlan, error) {
+	return c().Create(context.TODO(), plan, metav1.CreateOptions{})
+}
+func (c MaintenancePlanClient) Update(plan *cloudweavv1.MaintenancePlan) (*cloudweavv1.MaintenancePlan, error) {
+	return c().Update(context.TODO(), plan, metav1.UpdateOptions{})
+}
+func (c MaintenancePlanClient) UpdateStatus(plan *cloudweavv1.MaintenancePlan) (*cloudweavv1.MaintenancePlan, error) {
+	return c().UpdateStatus(context.TODO(), plan, metav1.UpdateOptions{})
+}
+func (c MaintenancePlanClient) Delete(name string, options *metav1.DeleteOptions) error {
+	return c().Delete(context.TODO(), name, *options)
+}
+func (c MaintenancePlanClient) Get(name string, options metav1.GetOptions) (*cloudweavv1.MaintenancePlan, error) {
+	return c().Get(context.TODO(), name, options)
+}
+func (c MaintenancePlanClient) List(opts metav1.ListOptions) (*cloudweavv1.MaintenancePlanList, error) {
+	return c().List(context.TODO(), opts)
+}
+func (c MaintenancePlanClient) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	return c().Watch(context.TODO(), opts)
+}
+func (c MaintenancePlanClient) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (*cloudweavv1.MaintenancePlan, error) {
+	return c().Patch(context.TODO(), name, pt, data, metav1.PatchOptions{}, subresources...)
+}
+func (c MaintenancePlanClient) WithImpersonation(_ rest.ImpersonationConfig) (generic.NonNamespacedClientInterface[*cloudweavv1.MaintenancePlan, *cloudweavv1.MaintenancePlanList], error) {
+	panic("implement me")
+}
+
+type MaintenancePlanCache func() harv1type.MaintenancePlanInterface
+
+func (c MaintenancePlanCache) Get(name string) (*cloudweavv1.MaintenancePlan, error) {
+	return c().Get(context.TODO(), name, metav1.GetOptions{})
+}
+func (c MaintenancePlanCache) List(selector labels.Selector) ([]*cloudweavv1.MaintenancePlan, error) {
+	list, err := c().List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*cloudweavv1.MaintenancePlan, 0, len(list.Items))
+	for i := range list.Items {
+		result = append(result, &list.Items[i])
+	}
+	return result, err
+}
+func (c MaintenancePlanCache) AddIndexer(_ string, _ generic.Indexer[*cloudweavv1.MaintenancePlan]) {
+	panic("implement me")
+}
+func (c MaintenancePlanCache) GetByIndex(_, _ string) ([]*cloudweavv1.MaintenancePlan, error) {
+	panic("implement me")
+}
@@ -0,0 +1,68 @@
+package fakeclients
+
+import (
+	"context"
+
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	harv1type "github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/typed/cloudweavhci.io/v1beta1"
+)
+
+type NodeBMCClient func() harv1type.NodeBMCInterface
+
+func (c NodeBMCClient) Create(nodeBMC *cloudweavv1.NodeBMC) (*cloudweavv1.NodeBMC, error) {
+	return c().Create(context.TODO(), nodeBMC, metav1.CreateOptions{})
+}
+func (c NodeBMCClient) Update(nodeBMC *cloudweavv1.NodeBMC) (*cloudweavv1.NodeBMC, error) {
+	return c().Update(context.TODO(), nodeBMC, metav1.UpdateOptions{})
+}
+func (c NodeBMCClient) UpdateStatus(nodeBMC *cloudweavv1.NodeBMC) (*cloudweavv1.NodeBMC, error) {
+	return c().UpdateStatus(context.TODO(), nodeBMC, metav1.UpdateOptions{})
+}
+func (c NodeBMCClient) Delete(name string, options *metav1.DeleteOptions) error {
+	return c().Delete(context.TODO(), name, *options)
+}
+func (c NodeBMCClient) Get(name string, options metav1.GetOptions) (*cloudweavv1.NodeBMC, error) {
+	return c().Get(context.TODO(), name, options)
+}
+func (c NodeBMCClient) List(opts metav1.ListOptions) (*cloudweavv1.NodeBMCList, error) {
+	return c().List(context.TODO(), opts)
+}
+func (c NodeBMCClient) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	return c().Watch(context.TODO(), opts)
+}
+func (c NodeBMCClient) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (*cloudweavv1.NodeBMC, error) {
+	return c().Patch(context.TODO(), name, pt, data, metav1.PatchOptions{}, subresources...)
+}
+func (c NodeBMCClient) WithImpersonation(_ rest.ImpersonationConfig) (generic.NonNamespacedClientInterface[*cloudweavv1.NodeBMC, *cloudweavv1.NodeBMCList], error) {
+	panic("implement me")
+}
+
+type NodeBMCCache func() harv1type.NodeBMCInterface
+
+func (c NodeBMCCache) Get(name string) (*cloudweavv1.NodeBMC, error) {
+	return c().Get(context.TODO(), name, metav1.GetOptions{})
+}
+func (c NodeBMCCache) List(selector labels.Selector) ([]*cloudweavv1.NodeBMC, error) {
+	list, err := c().List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*cloudweavv1.NodeBMC, 0, len(list.Items))
+	for i := range list.Items {
+		result = append(result, &list.Items[i])
+	}
+	return result, err
+}
+func (c NodeBMCCache) AddIndexer(_ string, _ generic.Indexer[*cloudweavv1.NodeBMC]) {
+	panic("implement me")
+}
+func (c NodeBMCCache) GetByIndex(_, _ string) ([]*cloudweavv1.NodeBMC, error) {
+	panic("implement me")
+}
@@ -0,0 +1,69 @@
+package fakeclients
+
+import (
+	"context"
+
+	"github.com/rancher/wrangler/v3/pkg/generic"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	harv1type "github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/typed/cloudweavhci.io/v1beta1"
+)
+
+type UpgradeHistoryClient func(string) harv1type.UpgradeHistoryInterface
+
+func (c UpgradeHistoryClient) Create(history *cloudweavv1.UpgradeHistory) (*cloudweavv1.UpgradeHistory, error) {
+	return c(history.Namespace).Create(context.TODO(), history, metav1.CreateOptions{})
+}
+func (c UpgradeHistoryClient) Update(history *cloudweavv1.UpgradeHistory) (*cloudweavv1.UpgradeHistory, error) {
+	return c(history.Namespace).Update(context.TODO(), history, metav1.UpdateOptions{})
+}
+func (c UpgradeHistoryClient) UpdateStatus(*cloudweavv1.UpgradeHistory) (*cloudweavv1.UpgradeHistory, error) {
+	panic("implement me")
+}
+func (c UpgradeHistoryClient) Delete(namespace, name string, options *metav1.DeleteOptions) error {
+	return c(namespace).Delete(context.TODO(), name, *options)
+}
+func (c UpgradeHistoryClient) Get(namespace, name string, options metav1.GetOptions) (*cloudweavv1.UpgradeHistory, error) {
+	return c(namespace).Get(context.TODO(), name, options)
+}
+func (c UpgradeHistoryClient) List(namespace string, opts metav1.ListOptions) (*cloudweavv1.UpgradeHistoryList, error) {
+	return c(namespace).List(context.TODO(), opts)
+}
+func (c UpgradeHistoryClient) Watch(_ string, _ metav1.ListOptions) (watch.Interface, error) {
+	panic("implement me")
+}
+func (c UpgradeHistoryClient) Patch(_, _ string, _ types.PatchType, _ []byte, _ ...string) (result *cloudweavv1.UpgradeHistory, err error) {
+	panic("implement me")
+}
+
+func (c UpgradeHistoryClient) WithImpersonation(_ rest.ImpersonationConfig) (generic.ClientInterface[*cloudweavv1.UpgradeHistory, *cloudweavv1.UpgradeHistoryList], error) {
+	panic("implement me")
+}
+
+type UpgradeHistoryCache func(string) harv1type.UpgradeHistoryInterface
+
+func (c UpgradeHistoryCache) Get(namespace, name string) (*cloudweavv1.UpgradeHistory, error) {
+	return c(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+func (c UpgradeHistoryCache) List(namespace string, selector labels.Selector) ([]*cloudweavv1.UpgradeHistory, error) {
+	list, err := c(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*cloudweavv1.UpgradeHistory, 0, len(list.Items))
+	for i := range list.Items {
+		result = append(result, &list.Items[i])
+	}
+	return result, err
+}
+func (c UpgradeHistoryCache) AddIndexer(_ string, _ generic.Indexer[*cloudweavv1.UpgradeHistory]) {
+	panic("implement me")
+}
+func (c UpgradeHistoryCache) GetByIndex(_, _ string) ([]*cloudweavv1.UpgradeHistory, error) {
+	panic("implement me")
+}
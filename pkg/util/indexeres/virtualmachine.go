@@ -1,14 +1,21 @@
 package indexeres
 
 import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
 	kubevirtv1 "kubevirt.io/api/core/v1"
 
 	"github.com/cloudweav/cloudweav/pkg/ref"
+	"github.com/cloudweav/cloudweav/pkg/util"
 )
 
 // The file contains the indexers which are used by controller and webhook.
 const (
-	VMByPVCIndex = "cloudweavhci.io/vm-by-pvc"
+	VMByPVCIndex     = "cloudweavhci.io/vm-by-pvc"
+	VMByImageIDIndex = "cloudweavhci.io/vm-by-image-id"
+	VMByKeyPairIndex = "cloudweavhci.io/vm-by-keypair"
 )
 
 func VMByPVC(obj *kubevirtv1.VirtualMachine) ([]string, error) {
@@ -24,3 +31,53 @@ func VMByPVC(obj *kubevirtv1.VirtualMachine) ([]string, error) {
 	}
 	return results, nil
 }
+
+// VMByImageID indexes VMs by the image IDs referenced in their volume claim templates.
+func VMByImageID(obj *kubevirtv1.VirtualMachine) ([]string, error) {
+	volumeClaimTemplateStr, ok := obj.Annotations[util.AnnotationVolumeClaimTemplates]
+	if !ok || volumeClaimTemplateStr == "" {
+		return []string{}, nil
+	}
+
+	var volumeClaimTemplates []corev1.PersistentVolumeClaim
+	if err := json.Unmarshal([]byte(volumeClaimTemplateStr), &volumeClaimTemplates); err != nil {
+		return []string{}, fmt.Errorf("can't unmarshal %s, err: %w", util.AnnotationVolumeClaimTemplates, err)
+	}
+
+	imageIDs := []string{}
+	for _, volumeClaimTemplate := range volumeClaimTemplates {
+		imageID, ok := volumeClaimTemplate.Annotations[util.AnnotationImageID]
+		if !ok || imageID == "" {
+			continue
+		}
+		imageIDs = append(imageIDs, imageID)
+	}
+	return imageIDs, nil
+}
+
+// VMByKeyPair indexes VMs by the IDs of the key pairs referenced in their template annotation,
+// the key pairs without a namespace are in the namespace of the VM.
+func VMByKeyPair(obj *kubevirtv1.VirtualMachine) ([]string, error) {
+	if obj == nil || obj.Spec.Template == nil {
+		return []string{}, nil
+	}
+	sshNamesStr, ok := obj.Spec.Template.ObjectMeta.Annotations[util.AnnotationSSHNames]
+	if !ok || sshNamesStr == "" {
+		return []string{}, nil
+	}
+
+	var sshNames []string
+	if err := json.Unmarshal([]byte(sshNamesStr), &sshNames); err != nil {
+		return []string{}, fmt.Errorf("can't unmarshal %s, err: %w", util.AnnotationSSHNames, err)
+	}
+
+	keyPairIDs := make([]string, 0, len(sshNames))
+	for _, sshName := range sshNames {
+		namespace, name := ref.Parse(sshName)
+		if namespace == "" {
+			namespace = obj.Namespace
+		}
+		keyPairIDs = append(keyPairIDs, ref.Construct(namespace, name))
+	}
+	return keyPairIDs, nil
+}
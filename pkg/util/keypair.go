@@ -1,11 +1,13 @@
 package util
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
 
+	wranglername "github.com/rancher/wrangler/v3/pkg/name"
 	"golang.org/x/crypto/ssh"
 )
 
@@ -41,3 +43,27 @@ func GeneratePublicKey(publicKey *rsa.PublicKey) ([]byte, error) {
 	}
 	return ssh.MarshalAuthorizedKey(publicRsaKey), nil
 }
+
+// GenerateEd25519KeyPair creates an Ed25519 key pair, the private key is encoded in the OpenSSH
+// format and the public key in the authorized_keys format
+func GenerateEd25519KeyPair() (privateKey []byte, publicKey []byte, err error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(private, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	sshPublicKey, err := ssh.NewPublicKey(public)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(block), ssh.MarshalAuthorizedKey(sshPublicKey), nil
+}
+
+// KeyPairPropagationSecretName returns the name of the secret holding the public keys propagated
+// to the VM by the guest agent
+func KeyPairPropagationSecretName(vmName string) string {
+	return wranglername.SafeConcatName(vmName, "keypairs")
+}
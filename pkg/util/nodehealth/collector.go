@@ -0,0 +1,277 @@
+package nodehealth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+const (
+	smartAttributeReallocatedSectors = 5
+	smartAttributePendingSectors     = 197
+)
+
+// Collector reads the hardware health of the node from sysfs and smartctl
+type Collector struct {
+	// SysRoot is where sysfs is mounted, /sys on the host
+	SysRoot string
+	// Smartctl returns the JSON output of smartctl for the disk, like `smartctl -a -j /dev/sda`
+	Smartctl func(disk string) ([]byte, error)
+	Now      func() time.Time
+}
+
+// NewCollector returns a collector of the host sysfs and smartctl
+func NewCollector() *Collector {
+	return &Collector{
+		SysRoot:  "/sys",
+		Smartctl: runSmartctl,
+		Now:      time.Now,
+	}
+}
+
+func runSmartctl(disk string) ([]byte, error) {
+	// smartctl reports the disk problems in the bits of its exit status, the output is still valid
+	output, err := exec.Command("smartctl", "-a", "-j", filepath.Join("/dev", disk)).Output()
+	if len(output) > 0 {
+		return output, nil
+	}
+	return nil, err
+}
+
+// Collect returns the health of the node. The link flaps are counted since the previous status.
+func (c *Collector) Collect(previous *cloudweavv1.NodeHealthStatus) (*cloudweavv1.NodeHealthStatus, error) {
+	status := &cloudweavv1.NodeHealthStatus{
+		LastUpdateTime: c.Now().UTC().Format(time.RFC3339),
+	}
+
+	disks, err := c.collectDisks()
+	if err != nil {
+		return nil, err
+	}
+	status.Disks = disks
+
+	memory, err := c.collectMemory()
+	if err != nil {
+		return nil, err
+	}
+	status.Memory = memory
+
+	links, err := c.collectLinks(previous)
+	if err != nil {
+		return nil, err
+	}
+	status.Links = links
+
+	temperatures, err := c.collectTemperatures()
+	if err != nil {
+		return nil, err
+	}
+	status.Temperatures = temperatures
+	return status, nil
+}
+
+type smartctlOutput struct {
+	ModelName    string `json:"model_name"`
+	SerialNumber string `json:"serial_number"`
+	SmartStatus  *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	ATASmartAttributes struct {
+		Table []struct {
+			ID  int `json:"id"`
+			Raw struct {
+				Value int64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NVMeSmartHealthInformationLog struct {
+		MediaErrors int64 `json:"media_errors"`
+	} `json:"nvme_smart_health_information_log"`
+	Temperature struct {
+		Current int64 `json:"current"`
+	} `json:"temperature"`
+}
+
+// collectDisks returns the SMART data of the physical disks, the block devices with a device
+func (c *Collector) collectDisks() ([]cloudweavv1.DiskHealth, error) {
+	names, err := c.devices(filepath.Join(c.SysRoot, "block"))
+	if err != nil {
+		return nil, err
+	}
+
+	disks := make([]cloudweavv1.DiskHealth, 0, len(names))
+	for _, name := range names {
+		output, err := c.Smartctl(name)
+		if err != nil {
+			logrus.Warnf("failed to get the SMART data of disk %s: %v", name, err)
+			continue
+		}
+		smart := &smartctlOutput{}
+		if err := json.Unmarshal(output, smart); err != nil {
+			logrus.Warnf("failed to parse the SMART data of disk %s: %v", name, err)
+			continue
+		}
+		if smart.SmartStatus == nil {
+			// the disk doesn't support SMART, e.g. a virtual disk
+			continue
+		}
+
+		disk := cloudweavv1.DiskHealth{
+			Name:               name,
+			Model:              smart.ModelName,
+			Serial:             smart.SerialNumber,
+			SMARTPassed:        smart.SmartStatus.Passed,
+			MediaErrors:        smart.NVMeSmartHealthInformationLog.MediaErrors,
+			TemperatureCelsius: smart.Temperature.Current,
+		}
+		for _, attribute := range smart.ATASmartAttributes.Table {
+			switch attribute.ID {
+			case smartAttributeReallocatedSectors:
+				disk.ReallocatedSectors = attribute.Raw.Value
+			case smartAttributePendingSectors:
+				disk.PendingSectors = attribute.Raw.Value
+			}
+		}
+		disks = append(disks, disk)
+	}
+	return disks, nil
+}
+
+// collectMemory sums the error counters of the EDAC memory controllers
+func (c *Collector) collectMemory() (cloudweavv1.MemoryHealth, error) {
+	memory := cloudweavv1.MemoryHealth{}
+	controllers, err := filepath.Glob(filepath.Join(c.SysRoot, "devices", "system", "edac", "mc", "mc*"))
+	if err != nil {
+		return memory, err
+	}
+	for _, controller := range controllers {
+		ce, err := readInt(filepath.Join(controller, "ce_count"))
+		if err != nil {
+			return memory, err
+		}
+		ue, err := readInt(filepath.Join(controller, "ue_count"))
+		if err != nil {
+			return memory, err
+		}
+		memory.CorrectableErrors += ce
+		memory.UncorrectableErrors += ue
+	}
+	return memory, nil
+}
+
+// collectLinks returns the physical network interfaces, the interfaces with a device
+func (c *Collector) collectLinks(previous *cloudweavv1.NodeHealthStatus) ([]cloudweavv1.LinkHealth, error) {
+	names, err := c.devices(filepath.Join(c.SysRoot, "class", "net"))
+	if err != nil {
+		return nil, err
+	}
+	previousChanges := make(map[string]int64)
+	if previous != nil {
+		for _, link := range previous.Links {
+			previousChanges[link.Name] = link.CarrierChanges
+		}
+	}
+
+	links := make([]cloudweavv1.LinkHealth, 0, len(names))
+	for _, name := range names {
+		dir := filepath.Join(c.SysRoot, "class", "net", name)
+		operState, err := readString(filepath.Join(dir, "operstate"))
+		if err != nil {
+			return nil, err
+		}
+		changes, err := readInt(filepath.Join(dir, "carrier_changes"))
+		if err != nil {
+			return nil, err
+		}
+		link := cloudweavv1.LinkHealth{
+			Name:           name,
+			OperState:      operState,
+			CarrierChanges: changes,
+		}
+		if last, ok := previousChanges[name]; ok && changes >= last {
+			link.Flaps = changes - last
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// collectTemperatures returns the temperature sensors of the hardware monitoring devices
+func (c *Collector) collectTemperatures() ([]cloudweavv1.TemperatureHealth, error) {
+	inputs, err := filepath.Glob(filepath.Join(c.SysRoot, "class", "hwmon", "hwmon*", "temp*_input"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(inputs)
+
+	temperatures := make([]cloudweavv1.TemperatureHealth, 0, len(inputs))
+	for _, input := range inputs {
+		dir := filepath.Dir(input)
+		sensor := strings.TrimSuffix(filepath.Base(input), "_input")
+		milliCelsius, err := readInt(input)
+		if err != nil {
+			// a sensor without a reading
+			continue
+		}
+		device, err := readString(filepath.Join(dir, "name"))
+		if err != nil {
+			device = filepath.Base(dir)
+		}
+		if label, err := readString(filepath.Join(dir, sensor+"_label")); err == nil && label != "" {
+			sensor = label
+		}
+		temperatures = append(temperatures, cloudweavv1.TemperatureHealth{
+			Name:    fmt.Sprintf("%s/%s", device, sensor),
+			Celsius: milliCelsius / 1000,
+		})
+	}
+	return temperatures, nil
+}
+
+// devices returns the entries of the sysfs class directory backed by a device, in name order
+func (c *Collector) devices(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if _, err := os.Stat(filepath.Join(dir, entry.Name(), "device")); err == nil {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func readString(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+func readInt(path string) (int64, error) {
+	content, err := readString(path)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseInt(content, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q of %s: %w", content, path, err)
+	}
+	return value, nil
+}
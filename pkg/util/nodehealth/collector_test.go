@@ -0,0 +1,75 @@
+package nodehealth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+func newTestCollector() *Collector {
+	return &Collector{
+		SysRoot: filepath.Join("testdata", "sys"),
+		Smartctl: func(disk string) ([]byte, error) {
+			return os.ReadFile(filepath.Join("testdata", "smartctl", disk+".json"))
+		},
+		Now: func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) },
+	}
+}
+
+func TestCollect(t *testing.T) {
+	previous := &cloudweavv1.NodeHealthStatus{
+		Links: []cloudweavv1.LinkHealth{{Name: "eno1", CarrierChanges: 2}},
+	}
+
+	status, err := newTestCollector().Collect(previous)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01-01T00:00:00Z", status.LastUpdateTime)
+
+	assert.Equal(t, []cloudweavv1.DiskHealth{
+		{
+			Name:               "nvme0n1",
+			Model:              "SAMSUNG MZQL23T8HCLS",
+			Serial:             "S64HNE0T000000",
+			SMARTPassed:        true,
+			TemperatureCelsius: 41,
+		},
+		{
+			Name:               "sda",
+			Model:              "ST4000NM0035",
+			Serial:             "ZC1ABCDE",
+			SMARTPassed:        true,
+			ReallocatedSectors: 48,
+			PendingSectors:     8,
+			TemperatureCelsius: 38,
+		},
+	}, status.Disks, "loop devices have no SMART data")
+
+	assert.Equal(t, cloudweavv1.MemoryHealth{CorrectableErrors: 5, UncorrectableErrors: 1}, status.Memory)
+
+	assert.Equal(t, []cloudweavv1.LinkHealth{
+		{Name: "eno1", OperState: "up", CarrierChanges: 12, Flaps: 10},
+		{Name: "eno2", OperState: "down", CarrierChanges: 1},
+	}, status.Links, "virtual interfaces are skipped, flaps are counted since the previous report")
+
+	assert.Equal(t, []cloudweavv1.TemperatureHealth{
+		{Name: "coretemp/Package id 0", Celsius: 92},
+		{Name: "coretemp/temp2", Celsius: 45},
+	}, status.Temperatures)
+}
+
+func TestCollectWithoutSysfs(t *testing.T) {
+	collector := newTestCollector()
+	collector.SysRoot = t.TempDir()
+
+	status, err := collector.Collect(nil)
+	require.NoError(t, err)
+	assert.Empty(t, status.Disks)
+	assert.Empty(t, status.Links)
+	assert.Equal(t, cloudweavv1.MemoryHealth{}, status.Memory)
+}
@@ -0,0 +1,45 @@
+package nodehealth
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+)
+
+// Report collects the health of the node into its NodeHealth, the node agent calls it periodically
+func (c *Collector) Report(nodeHealths ctlcloudweavv1.NodeHealthClient, nodeName string) error {
+	nodeHealth, err := nodeHealths.Get(nodeName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		nodeHealth, err = nodeHealths.Create(&cloudweavv1.NodeHealth{
+			ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	status, err := c.Collect(&nodeHealth.Status)
+	if err != nil {
+		return err
+	}
+	toUpdate := nodeHealth.DeepCopy()
+	toUpdate.Status = *status
+	_, err = nodeHealths.UpdateStatus(toUpdate)
+	return err
+}
+
+// Run reports the health of the node every interval until the context is done
+func (c *Collector) Run(ctx context.Context, nodeHealths ctlcloudweavv1.NodeHealthClient, nodeName string, interval time.Duration) {
+	wait.UntilWithContext(ctx, func(_ context.Context) {
+		if err := c.Report(nodeHealths, nodeName); err != nil {
+			logrus.Errorf("failed to report the health of node %s: %v", nodeName, err)
+		}
+	}, interval)
+}
@@ -0,0 +1,243 @@
+package numa
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+// Requirements are the placement requirements of a latency-sensitive VM
+type Requirements struct {
+	// DedicatedCPUs is the number of host CPUs pinned to the VM
+	DedicatedCPUs int64
+	// HugepageSize is the page size of the hugepages backing the guest memory, like 2Mi or 1Gi
+	HugepageSize string
+	// HugepageBytes is the guest memory backed by hugepages
+	HugepageBytes int64
+	// GuestNUMA passes the NUMA topology of the pinned CPUs and hugepages through to the guest
+	GuestNUMA bool
+}
+
+// IsEmpty returns whether the VM can run on any node with enough CPU and memory
+func (r Requirements) IsEmpty() bool {
+	return r.DedicatedCPUs == 0 && r.HugepageSize == "" && !r.GuestNUMA
+}
+
+// GetRequirements returns the placement requirements of a VMI spec
+func GetRequirements(spec *kubevirtv1.VirtualMachineInstanceSpec) Requirements {
+	requirements := Requirements{}
+	domain := spec.Domain
+	if domain.CPU != nil && domain.CPU.DedicatedCPUPlacement {
+		requirements.DedicatedCPUs = vCPUs(&domain)
+		if domain.CPU.IsolateEmulatorThread {
+			requirements.DedicatedCPUs++
+		}
+	}
+	if IsGuestNUMA(spec) {
+		requirements.GuestNUMA = true
+	}
+	if pageSize := HugepageSize(spec); pageSize != "" {
+		requirements.HugepageSize = pageSize
+		requirements.HugepageBytes = GuestMemory(spec).Value()
+	}
+	return requirements
+}
+
+// IsGuestNUMA returns whether the VMI requests a guest NUMA topology
+func IsGuestNUMA(spec *kubevirtv1.VirtualMachineInstanceSpec) bool {
+	cpu := spec.Domain.CPU
+	return cpu != nil && cpu.NUMA != nil && cpu.NUMA.GuestMappingPassthrough != nil
+}
+
+// HugepageSize returns the page size of the hugepages backing the VMI memory, empty if it's not backed by hugepages
+func HugepageSize(spec *kubevirtv1.VirtualMachineInstanceSpec) string {
+	memory := spec.Domain.Memory
+	if memory == nil || memory.Hugepages == nil {
+		return ""
+	}
+	return memory.Hugepages.PageSize
+}
+
+// GuestMemory returns the memory of the guest, like KubeVirt computes it
+func GuestMemory(spec *kubevirtv1.VirtualMachineInstanceSpec) *resource.Quantity {
+	domain := spec.Domain
+	if domain.Memory != nil && domain.Memory.Guest != nil {
+		return domain.Memory.Guest
+	}
+	if memory, ok := domain.Resources.Requests[corev1.ResourceMemory]; ok {
+		return &memory
+	}
+	memory := domain.Resources.Limits[corev1.ResourceMemory]
+	return &memory
+}
+
+// vCPUs returns the number of vCPUs of the domain, from its topology or from its CPU resources
+func vCPUs(domain *kubevirtv1.DomainSpec) int64 {
+	if cpu := domain.CPU; cpu != nil && (cpu.Cores != 0 || cpu.Sockets != 0 || cpu.Threads != 0) {
+		return int64(max(cpu.Cores, 1)) * int64(max(cpu.Sockets, 1)) * int64(max(cpu.Threads, 1))
+	}
+	if quantity, ok := domain.Resources.Requests[corev1.ResourceCPU]; ok {
+		return quantity.Value()
+	}
+	if quantity, ok := domain.Resources.Limits[corev1.ResourceCPU]; ok {
+		return quantity.Value()
+	}
+	return 1
+}
+
+// NodeNUMA is the NUMA-aware capacity of a node
+type NodeNUMA struct {
+	CPUManager bool `json:"cpuManager"`
+	NUMAAware  bool `json:"numaAware"`
+	// Cells are the NUMA nodes reported by the node agent
+	Cells      []Cell          `json:"cells,omitempty"`
+	PinnedCPUs PinnedCPUs      `json:"pinnedCPUs"`
+	Hugepages  []HugepageUsage `json:"hugepages,omitempty"`
+}
+
+// PinnedCPUs are the CPUs of the node which can be pinned to VMs
+type PinnedCPUs struct {
+	Allocatable int64 `json:"allocatable"`
+	Used        int64 `json:"used"`
+	Free        int64 `json:"free"`
+}
+
+// HugepageUsage is the memory of the node in hugepages of a page size, in bytes
+type HugepageUsage struct {
+	PageSize    string `json:"pageSize"`
+	Allocatable int64  `json:"allocatable"`
+	Used        int64  `json:"used"`
+	Free        int64  `json:"free"`
+}
+
+// Summarize returns the NUMA-aware capacity of the node used by the VMIs running on it
+func Summarize(node *corev1.Node, vmis []*kubevirtv1.VirtualMachineInstance) (*NodeNUMA, error) {
+	topology, err := GetTopology(node)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &NodeNUMA{
+		CPUManager: isLabelTrue(node, kubevirtv1.CPUManager),
+		NUMAAware:  isLabelTrue(node, util.LabelNUMAAware),
+	}
+	if topology != nil {
+		summary.Cells = topology.Cells
+	}
+
+	usedHugepages := make(map[string]int64)
+	for _, vmi := range vmis {
+		if vmi.IsFinal() {
+			continue
+		}
+		requirements := GetRequirements(&vmi.Spec)
+		summary.PinnedCPUs.Used += requirements.DedicatedCPUs
+		if requirements.HugepageSize != "" {
+			usedHugepages[requirements.HugepageSize] += requirements.HugepageBytes
+		}
+	}
+
+	if summary.CPUManager {
+		// the static CPU manager pins whole CPUs
+		summary.PinnedCPUs.Allocatable = node.Status.Allocatable.Cpu().MilliValue() / 1000
+	}
+	summary.PinnedCPUs.Free = max(summary.PinnedCPUs.Allocatable-summary.PinnedCPUs.Used, 0)
+
+	for name, quantity := range node.Status.Allocatable {
+		if !strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix) {
+			continue
+		}
+		pageSize := strings.TrimPrefix(string(name), corev1.ResourceHugePagesPrefix)
+		usage := HugepageUsage{
+			PageSize:    pageSize,
+			Allocatable: quantity.Value(),
+			Used:        usedHugepages[pageSize],
+		}
+		usage.Free = max(usage.Allocatable-usage.Used, 0)
+		if usage.Allocatable > 0 || usage.Used > 0 {
+			summary.Hugepages = append(summary.Hugepages, usage)
+		}
+	}
+	sort.Slice(summary.Hugepages, func(i, j int) bool {
+		return summary.Hugepages[i].PageSize < summary.Hugepages[j].PageSize
+	})
+	return summary, nil
+}
+
+// Fits returns why a VMI with the requirements can't run on the node with the VMIs already running on it,
+// nothing if it fits
+func Fits(node *corev1.Node, vmis []*kubevirtv1.VirtualMachineInstance, requirements Requirements) ([]string, error) {
+	if requirements.IsEmpty() {
+		return nil, nil
+	}
+	summary, err := Summarize(node, vmis)
+	if err != nil {
+		return nil, err
+	}
+
+	var reasons []string
+	if requirements.DedicatedCPUs > 0 {
+		if !summary.CPUManager {
+			reasons = append(reasons, "CPU manager is not enabled")
+		} else if summary.PinnedCPUs.Free < requirements.DedicatedCPUs {
+			reasons = append(reasons, fmt.Sprintf("insufficient pinned CPUs: requires %d, free %d", requirements.DedicatedCPUs, summary.PinnedCPUs.Free))
+		}
+	}
+
+	if requirements.HugepageSize != "" {
+		free := int64(0)
+		for _, usage := range summary.Hugepages {
+			if usage.PageSize == requirements.HugepageSize {
+				free = usage.Free
+			}
+		}
+		if free < requirements.HugepageBytes {
+			reasons = append(reasons, fmt.Sprintf("insufficient %s hugepages: requires %s, free %s", requirements.HugepageSize,
+				resource.NewQuantity(requirements.HugepageBytes, resource.BinarySI), resource.NewQuantity(free, resource.BinarySI)))
+		}
+	}
+
+	if requirements.GuestNUMA {
+		if !summary.NUMAAware {
+			reasons = append(reasons, "NUMA-aware scheduling is not enabled")
+		} else if len(summary.Cells) > 0 && !fitsInCell(summary.Cells, requirements) {
+			// the single-numa-node topology manager policy admits the VM only if a NUMA node holds all its resources
+			reasons = append(reasons, "no NUMA node has enough CPUs and hugepages")
+		}
+	}
+	return reasons, nil
+}
+
+func fitsInCell(cells []Cell, requirements Requirements) bool {
+	for _, cell := range cells {
+		cpus, err := CountCPUs(cell.CPUs)
+		if err != nil || int64(cpus) < requirements.DedicatedCPUs {
+			continue
+		}
+		if requirements.HugepageSize == "" {
+			return true
+		}
+		pageSize, err := resource.ParseQuantity(requirements.HugepageSize)
+		if err != nil {
+			return false
+		}
+		for _, pool := range cell.Hugepages {
+			if pool.PageSize == requirements.HugepageSize && pool.Free*pageSize.Value() >= requirements.HugepageBytes {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isLabelTrue(node *corev1.Node, key string) bool {
+	value, err := strconv.ParseBool(node.Labels[key])
+	return err == nil && value
+}
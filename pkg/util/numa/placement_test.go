@@ -0,0 +1,147 @@
+package numa
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+func newVMISpec(cores uint32, pageSize, memory string, guestNUMA bool) *kubevirtv1.VirtualMachineInstanceSpec {
+	spec := &kubevirtv1.VirtualMachineInstanceSpec{
+		Domain: kubevirtv1.DomainSpec{
+			Resources: kubevirtv1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+			},
+		},
+	}
+	if cores > 0 {
+		spec.Domain.CPU = &kubevirtv1.CPU{Cores: cores, DedicatedCPUPlacement: true}
+		if guestNUMA {
+			spec.Domain.CPU.NUMA = &kubevirtv1.NUMA{GuestMappingPassthrough: &kubevirtv1.NUMAGuestMappingPassthrough{}}
+		}
+	}
+	if pageSize != "" {
+		spec.Domain.Memory = &kubevirtv1.Memory{Hugepages: &kubevirtv1.Hugepages{PageSize: pageSize}}
+	}
+	return spec
+}
+
+func newNUMANode(t *testing.T, cpuManager bool) *corev1.Node {
+	topology, err := Collect(filepath.Join("testdata", "sys"))
+	require.NoError(t, err)
+	topologyStr, err := json.Marshal(topology)
+	require.NoError(t, err)
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node-1",
+			Labels:      map[string]string{kubevirtv1.CPUManager: "false"},
+			Annotations: map[string]string{util.AnnotationNUMATopology: string(topologyStr)},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:              resource.MustParse("15500m"),
+				corev1.ResourceMemory:           resource.MustParse("64Gi"),
+				"hugepages-2Mi":                 resource.MustParse("4Gi"),
+				"hugepages-1Gi":                 resource.MustParse("4Gi"),
+				corev1.ResourceEphemeralStorage: resource.MustParse("100Gi"),
+			},
+		},
+	}
+	if cpuManager {
+		node.Labels[kubevirtv1.CPUManager] = "true"
+		node.Labels[util.LabelNUMAAware] = "true"
+	}
+	return node
+}
+
+func TestGetRequirements(t *testing.T) {
+	spec := newVMISpec(4, "2Mi", "2Gi", true)
+	spec.Domain.CPU.Sockets = 2
+	spec.Domain.CPU.IsolateEmulatorThread = true
+	assert.Equal(t, Requirements{
+		DedicatedCPUs: 9,
+		HugepageSize:  "2Mi",
+		HugepageBytes: 2 << 30,
+		GuestNUMA:     true,
+	}, GetRequirements(spec))
+
+	assert.True(t, GetRequirements(newVMISpec(0, "", "2Gi", false)).IsEmpty())
+}
+
+func TestFits(t *testing.T) {
+	running := &kubevirtv1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "running"},
+		Spec:       *newVMISpec(4, "2Mi", "2Gi", false),
+		Status:     kubevirtv1.VirtualMachineInstanceStatus{Phase: kubevirtv1.Running},
+	}
+	vmis := []*kubevirtv1.VirtualMachineInstance{running}
+
+	summary, err := Summarize(newNUMANode(t, true), vmis)
+	require.NoError(t, err)
+	assert.Equal(t, PinnedCPUs{Allocatable: 15, Used: 4, Free: 11}, summary.PinnedCPUs)
+	assert.Equal(t, []HugepageUsage{
+		{PageSize: "1Gi", Allocatable: 4 << 30, Free: 4 << 30},
+		{PageSize: "2Mi", Allocatable: 4 << 30, Used: 2 << 30, Free: 2 << 30},
+	}, summary.Hugepages)
+	assert.Len(t, summary.Cells, 2)
+
+	tests := []struct {
+		name       string
+		cpuManager bool
+		spec       *kubevirtv1.VirtualMachineInstanceSpec
+		expected   []string
+	}{
+		{
+			name:       "a VM without requirements fits anywhere",
+			cpuManager: false,
+			spec:       newVMISpec(0, "", "32Gi", false),
+		},
+		{
+			name:       "a guest NUMA VM fits in a NUMA node",
+			cpuManager: true,
+			spec:       newVMISpec(8, "2Mi", "2Gi", true),
+		},
+		{
+			name:       "not enough pinned CPUs",
+			cpuManager: true,
+			spec:       newVMISpec(12, "", "2Gi", true),
+			expected: []string{
+				"insufficient pinned CPUs: requires 12, free 11",
+				"no NUMA node has enough CPUs and hugepages",
+			},
+		},
+		{
+			name:       "not enough hugepages",
+			cpuManager: true,
+			spec:       newVMISpec(2, "1Gi", "8Gi", false),
+			expected:   []string{"insufficient 1Gi hugepages: requires 8Gi, free 4Gi"},
+		},
+		{
+			name:       "the CPU manager and NUMA-aware scheduling are disabled",
+			cpuManager: false,
+			spec:       newVMISpec(2, "2Mi", "1Gi", true),
+			expected: []string{
+				"CPU manager is not enabled",
+				"NUMA-aware scheduling is not enabled",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reasons, err := Fits(newNUMANode(t, tc.cpuManager), vmis, GetRequirements(tc.spec))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, reasons)
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package numa
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+// Topology is the NUMA topology of a node
+type Topology struct {
+	Cells []Cell `json:"cells"`
+}
+
+// Cell is a NUMA node of the host
+type Cell struct {
+	ID int `json:"id"`
+	// CPUs are the logical CPUs of the cell in the cpulist format, like 0-7,16-23
+	CPUs        string         `json:"cpus"`
+	MemoryBytes int64          `json:"memoryBytes"`
+	Hugepages   []HugepagePool `json:"hugepages,omitempty"`
+}
+
+// HugepagePool is the number of hugepages of a page size in a cell
+type HugepagePool struct {
+	PageSize string `json:"pageSize"`
+	Total    int64  `json:"total"`
+	Free     int64  `json:"free"`
+}
+
+// Collect reads the NUMA topology of the host from sysfs mounted at sysRoot
+func Collect(sysRoot string) (*Topology, error) {
+	dirs, err := filepath.Glob(filepath.Join(sysRoot, "devices", "system", "node", "node[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	topology := &Topology{Cells: make([]Cell, 0, len(dirs))}
+	for _, dir := range dirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "node"))
+		if err != nil {
+			continue
+		}
+		cpus, err := readString(filepath.Join(dir, "cpulist"))
+		if err != nil {
+			return nil, err
+		}
+		memory, err := readMemTotal(filepath.Join(dir, "meminfo"))
+		if err != nil {
+			return nil, err
+		}
+		hugepages, err := readHugepages(filepath.Join(dir, "hugepages"))
+		if err != nil {
+			return nil, err
+		}
+		topology.Cells = append(topology.Cells, Cell{
+			ID:          id,
+			CPUs:        cpus,
+			MemoryBytes: memory,
+			Hugepages:   hugepages,
+		})
+	}
+	sort.Slice(topology.Cells, func(i, j int) bool {
+		return topology.Cells[i].ID < topology.Cells[j].ID
+	})
+	return topology, nil
+}
+
+// Report saves the NUMA topology of the host in the annotation of its node, the node agent calls it periodically
+func Report(nodes ctlcorev1.NodeClient, nodeName, sysRoot string) error {
+	topology, err := Collect(sysRoot)
+	if err != nil {
+		return err
+	}
+	node, err := nodes.Get(nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	current, err := GetTopology(node)
+	if err == nil && reflect.DeepEqual(current, topology) {
+		return nil
+	}
+
+	topologyStr, err := json.Marshal(topology)
+	if err != nil {
+		return err
+	}
+	toUpdate := node.DeepCopy()
+	if toUpdate.Annotations == nil {
+		toUpdate.Annotations = make(map[string]string)
+	}
+	toUpdate.Annotations[util.AnnotationNUMATopology] = string(topologyStr)
+	_, err = nodes.Update(toUpdate)
+	return err
+}
+
+// Run reports the NUMA topology of the host every interval until the context is done
+func Run(ctx context.Context, nodes ctlcorev1.NodeClient, nodeName, sysRoot string, interval time.Duration) {
+	wait.UntilWithContext(ctx, func(_ context.Context) {
+		if err := Report(nodes, nodeName, sysRoot); err != nil {
+			logrus.Errorf("failed to report the NUMA topology of node %s: %v", nodeName, err)
+		}
+	}, interval)
+}
+
+// GetTopology returns the NUMA topology reported in the node annotation, or nil if it's not reported yet
+func GetTopology(node *corev1.Node) (*Topology, error) {
+	value := node.Annotations[util.AnnotationNUMATopology]
+	if value == "" {
+		return nil, nil
+	}
+	topology := &Topology{}
+	if err := json.Unmarshal([]byte(value), topology); err != nil {
+		return nil, fmt.Errorf("invalid NUMA topology of node %s: %w", node.Name, err)
+	}
+	return topology, nil
+}
+
+// CountCPUs returns the number of CPUs in a cpulist, like 0-7,16-23
+func CountCPUs(cpuList string) (int, error) {
+	count := 0
+	for _, part := range strings.Split(cpuList, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			return 0, fmt.Errorf("invalid cpulist %q: %w", cpuList, err)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(last); err != nil {
+				return 0, fmt.Errorf("invalid cpulist %q: %w", cpuList, err)
+			}
+		}
+		if end < start {
+			return 0, fmt.Errorf("invalid cpulist %q", cpuList)
+		}
+		count += end - start + 1
+	}
+	return count, nil
+}
+
+// readMemTotal reads the total memory of a cell from its meminfo, like `Node 0 MemTotal: 32768000 kB`
+func readMemTotal(path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[2] != "MemTotal:" {
+			continue
+		}
+		kiB, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid MemTotal of %s: %w", path, err)
+		}
+		return kiB * 1024, nil
+	}
+	return 0, scanner.Err()
+}
+
+// readHugepages reads the hugepage pools of a cell, the directories are named like hugepages-2048kB
+func readHugepages(dir string) ([]HugepagePool, error) {
+	pools, err := filepath.Glob(filepath.Join(dir, "hugepages-*kB"))
+	if err != nil {
+		return nil, err
+	}
+
+	var hugepages []HugepagePool
+	for _, pool := range pools {
+		kiB, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(pool), "hugepages-"), "kB"), 10, 64)
+		if err != nil {
+			continue
+		}
+		total, err := readInt(filepath.Join(pool, "nr_hugepages"))
+		if err != nil {
+			return nil, err
+		}
+		free, err := readInt(filepath.Join(pool, "free_hugepages"))
+		if err != nil {
+			return nil, err
+		}
+		hugepages = append(hugepages, HugepagePool{
+			PageSize: resource.NewQuantity(kiB*1024, resource.BinarySI).String(),
+			Total:    total,
+			Free:     free,
+		})
+	}
+	return hugepages, nil
+}
+
+func readString(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+func readInt(path string) (int64, error) {
+	content, err := readString(path)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseInt(content, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q of %s: %w", content, path, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,62 @@
+package numa
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollect(t *testing.T) {
+	topology, err := Collect(filepath.Join("testdata", "sys"))
+	require.NoError(t, err)
+	assert.Equal(t, &Topology{Cells: []Cell{
+		{
+			ID:          0,
+			CPUs:        "0-3,8-11",
+			MemoryBytes: 32768000 * 1024,
+			Hugepages: []HugepagePool{
+				{PageSize: "1Gi", Total: 4, Free: 4},
+				{PageSize: "2Mi", Total: 1024, Free: 512},
+			},
+		},
+		{
+			ID:          1,
+			CPUs:        "4-7,12-15",
+			MemoryBytes: 33554432 * 1024,
+			Hugepages: []HugepagePool{
+				{PageSize: "2Mi", Total: 1024, Free: 1024},
+			},
+		},
+	}}, topology)
+
+	topology, err = Collect(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, topology.Cells, "a host without NUMA sysfs")
+}
+
+func TestCountCPUs(t *testing.T) {
+	tests := []struct {
+		cpuList  string
+		expected int
+		err      bool
+	}{
+		{cpuList: "0", expected: 1},
+		{cpuList: "0-3,8-11", expected: 8},
+		{cpuList: "0-7, 16", expected: 9},
+		{cpuList: "", expected: 0},
+		{cpuList: "3-1", err: true},
+		{cpuList: "a-b", err: true},
+	}
+
+	for _, tc := range tests {
+		count, err := CountCPUs(tc.cpuList)
+		if tc.err {
+			assert.Error(t, err, tc.cpuList)
+			continue
+		}
+		assert.NoError(t, err, tc.cpuList)
+		assert.Equal(t, tc.expected, count, tc.cpuList)
+	}
+}
@@ -0,0 +1,204 @@
+package sshca
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// SecretName is the secret holding the cluster SSH certificate authority key pair
+	SecretName = "cloudweav-ssh-ca"
+
+	SecretKeyPrivateKey = "ca"
+	SecretKeyPublicKey  = "ca.pub"
+
+	trustedUserCAKeysPath = "/etc/ssh/cloudweav_user_ca.pub"
+	principalsDir         = "/etc/ssh/cloudweav_principals"
+
+	// clockSkew backdates the certificates, so that they are valid on guests whose clock is slightly behind
+	clockSkew = 5 * time.Minute
+)
+
+// userNameRegexp matches the POSIX user names, they name the authorized principals files of the VM
+var userNameRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_-]*[$]?$`)
+
+// ValidateUser checks that the login user is a POSIX user name, so its authorized principals file
+// can't be written outside of the principals directory.
+func ValidateUser(user string) error {
+	if !userNameRegexp.MatchString(user) {
+		return fmt.Errorf("invalid user name %q", user)
+	}
+	return nil
+}
+
+// GenerateCA creates a new ed25519 CA key pair, the private key is encoded in the OpenSSH format
+// and the public key in the authorized_keys format.
+func GenerateCA() (privateKey []byte, publicKey []byte, err error) {
+	return generateKeyPair("cloudweav-ssh-ca")
+}
+
+func generateKeyPair(comment string) ([]byte, []byte, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(private, comment)
+	if err != nil {
+		return nil, nil, err
+	}
+	sshPublicKey, err := ssh.NewPublicKey(public)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(block), ssh.MarshalAuthorizedKey(sshPublicKey), nil
+}
+
+// LoadCA returns the CA signer stored in the secret.
+func LoadCA(secret *corev1.Secret) (ssh.Signer, error) {
+	privateKey, ok := secret.Data[SecretKeyPrivateKey]
+	if !ok || len(privateKey) == 0 {
+		return nil, fmt.Errorf("secret %s/%s has no CA private key", secret.Namespace, secret.Name)
+	}
+	return ssh.ParsePrivateKey(privateKey)
+}
+
+// VMPrincipal is the certificate principal granting access to a VM. The VM only accepts user
+// certificates holding its principal, through the authorized principals files written by cloud-init.
+func VMPrincipal(namespace, name string) string {
+	return fmt.Sprintf("%s.%s", namespace, name)
+}
+
+// SignUserCertificate issues a user certificate for the public key. The certificate identifies the
+// platform user with its key ID, and is only valid for the principals during the validity period.
+func SignUserCertificate(ca ssh.Signer, publicKey ssh.PublicKey, keyID string, principals []string, validity time.Duration, now time.Time) (*ssh.Certificate, error) {
+	cert := &ssh.Certificate{
+		Key:             publicKey,
+		CertType:        ssh.UserCert,
+		KeyId:           keyID,
+		ValidPrincipals: principals,
+		ValidAfter:      uint64(now.Add(-clockSkew).Unix()),
+		ValidBefore:     uint64(now.Add(validity).Unix()),
+		Permissions: ssh.Permissions{
+			Extensions: map[string]string{
+				"permit-pty":              "",
+				"permit-port-forwarding":  "",
+				"permit-agent-forwarding": "",
+			},
+		},
+	}
+	if err := setSerial(cert); err != nil {
+		return nil, err
+	}
+	if err := cert.SignCert(rand.Reader, ca); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// SignHostCertificate issues a host certificate for the host public key, valid for the host names.
+func SignHostCertificate(ca ssh.Signer, publicKey ssh.PublicKey, keyID string, hostNames []string, validity time.Duration, now time.Time) (*ssh.Certificate, error) {
+	cert := &ssh.Certificate{
+		Key:             publicKey,
+		CertType:        ssh.HostCert,
+		KeyId:           keyID,
+		ValidPrincipals: hostNames,
+		ValidAfter:      uint64(now.Add(-clockSkew).Unix()),
+		ValidBefore:     uint64(now.Add(validity).Unix()),
+	}
+	if err := setSerial(cert); err != nil {
+		return nil, err
+	}
+	if err := cert.SignCert(rand.Reader, ca); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+func setSerial(cert *ssh.Certificate) error {
+	var serial [8]byte
+	if _, err := rand.Read(serial[:]); err != nil {
+		return err
+	}
+	for _, b := range serial {
+		cert.Serial = cert.Serial<<8 | uint64(b)
+	}
+	return nil
+}
+
+type cloudConfig struct {
+	SSHKeys    map[string]string `json:"ssh_keys"`
+	WriteFiles []writeFile       `json:"write_files"`
+	RunCmd     []string          `json:"runcmd"`
+}
+
+type writeFile struct {
+	Path        string `json:"path"`
+	Content     string `json:"content"`
+	Permissions string `json:"permissions"`
+}
+
+// HostCloudConfig generates a host key signed by the CA, and returns the cloud-config making the VM
+// present the host certificate, trust the user certificates issued by the CA, and accept the VM
+// principal for the login users. cloud-init configures sshd with the host certificate of ssh_keys.
+func HostCloudConfig(ca ssh.Signer, caPublicKey []byte, namespace, name string, hostNames, users []string, validity time.Duration, now time.Time) (string, error) {
+	for _, user := range users {
+		if err := ValidateUser(user); err != nil {
+			return "", err
+		}
+	}
+
+	hostPrivateKey, hostPublicKey, err := generateKeyPair(VMPrincipal(namespace, name))
+	if err != nil {
+		return "", err
+	}
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(hostPublicKey)
+	if err != nil {
+		return "", err
+	}
+	cert, err := SignHostCertificate(ca, publicKey, VMPrincipal(namespace, name), hostNames, validity, now)
+	if err != nil {
+		return "", err
+	}
+
+	config := cloudConfig{
+		SSHKeys: map[string]string{
+			"ed25519_private":     string(hostPrivateKey),
+			"ed25519_public":      string(hostPublicKey),
+			"ed25519_certificate": string(ssh.MarshalAuthorizedKey(cert)),
+		},
+		WriteFiles: []writeFile{
+			{
+				Path:        trustedUserCAKeysPath,
+				Content:     string(caPublicKey),
+				Permissions: "0644",
+			},
+		},
+		RunCmd: []string{
+			fmt.Sprintf("echo 'TrustedUserCAKeys %s' >> /etc/ssh/sshd_config", trustedUserCAKeysPath),
+			fmt.Sprintf("echo 'AuthorizedPrincipalsFile %s/%%u' >> /etc/ssh/sshd_config", principalsDir),
+			"systemctl restart sshd || systemctl restart ssh",
+		},
+	}
+	for _, user := range users {
+		config.WriteFiles = append(config.WriteFiles, writeFile{
+			Path:        fmt.Sprintf("%s/%s", principalsDir, user),
+			Content:     VMPrincipal(namespace, name) + "\n",
+			Permissions: "0644",
+		})
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return "#cloud-config\n" + strings.TrimSpace(string(data)) + "\n", nil
+}
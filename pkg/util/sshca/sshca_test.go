@@ -0,0 +1,76 @@
+package sshca
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestSignUserCertificate(t *testing.T) {
+	caPrivateKey, caPublicKey, err := GenerateCA()
+	assert.Nil(t, err)
+	ca, err := LoadCA(&corev1.Secret{Data: map[string][]byte{SecretKeyPrivateKey: caPrivateKey}})
+	assert.Nil(t, err)
+
+	_, userPublicKey, err := generateKeyPair("user")
+	assert.Nil(t, err)
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(userPublicKey)
+	assert.Nil(t, err)
+
+	now := time.Now()
+	cert, err := SignUserCertificate(ca, publicKey, "alice", []string{VMPrincipal("default", "web")}, time.Hour, now)
+	assert.Nil(t, err)
+
+	trustedCA, _, _, _, err := ssh.ParseAuthorizedKey(caPublicKey)
+	assert.Nil(t, err)
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return string(auth.Marshal()) == string(trustedCA.Marshal())
+		},
+		Clock: func() time.Time { return now },
+	}
+	assert.Nil(t, checker.CheckCert("default.web", cert))
+	assert.NotNil(t, checker.CheckCert("default.db", cert), "certificate is bound to the VM principal")
+
+	checker.Clock = func() time.Time { return now.Add(2 * time.Hour) }
+	assert.NotNil(t, checker.CheckCert("default.web", cert), "certificate is expired")
+}
+
+func TestHostCloudConfig(t *testing.T) {
+	caPrivateKey, caPublicKey, err := GenerateCA()
+	assert.Nil(t, err)
+	ca, err := LoadCA(&corev1.Secret{Data: map[string][]byte{SecretKeyPrivateKey: caPrivateKey}})
+	assert.Nil(t, err)
+
+	config, err := HostCloudConfig(ca, caPublicKey, "default", "web", []string{"web"}, []string{"ubuntu"}, time.Hour, time.Now())
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(config, "#cloud-config\n"))
+
+	var parsed cloudConfig
+	assert.Nil(t, yaml.Unmarshal([]byte(config), &parsed))
+	assert.Contains(t, parsed.SSHKeys["ed25519_certificate"], "ssh-ed25519-cert-v01@openssh.com")
+	assert.Equal(t, string(caPublicKey), parsed.WriteFiles[0].Content)
+	assert.Equal(t, "/etc/ssh/cloudweav_principals/ubuntu", parsed.WriteFiles[1].Path)
+	assert.Equal(t, "default.web\n", parsed.WriteFiles[1].Content)
+}
+
+func TestValidateUser(t *testing.T) {
+	for _, user := range []string{"ubuntu", "_apt", "svc-user", "machine$"} {
+		assert.Nil(t, ValidateUser(user), user)
+	}
+	for _, user := range []string{"", "../../root/.ssh/authorized_keys", "root/x", "Ubuntu", "1user", "a b", "user\n"} {
+		assert.NotNil(t, ValidateUser(user), user)
+	}
+
+	caPrivateKey, caPublicKey, err := GenerateCA()
+	assert.Nil(t, err)
+	ca, err := LoadCA(&corev1.Secret{Data: map[string][]byte{SecretKeyPrivateKey: caPrivateKey}})
+	assert.Nil(t, err)
+	_, err = HostCloudConfig(ca, caPublicKey, "default", "web", []string{"web"}, []string{"../../root/.ssh/authorized_keys"}, time.Hour, time.Now())
+	assert.NotNil(t, err, "principals file outside of the principals directory")
+}
@@ -0,0 +1,116 @@
+package virtualmachine
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+type HARestartPolicy string
+
+const (
+	// HARestartPolicyRestart restarts the VM on a healthy node when its node fails
+	HARestartPolicyRestart HARestartPolicy = "Restart"
+	// HARestartPolicyDoNotRestart stops the VM when its node fails, e.g. for test VMs
+	HARestartPolicyDoNotRestart HARestartPolicy = "DoNotRestart"
+
+	HAStatePending      = "Pending"
+	HAStateRestarted    = "Restarted"
+	HAStateNotRestarted = "NotRestarted"
+)
+
+// HAPolicy is the high availability policy of a VM, in the AnnotationHAPolicy annotation
+type HAPolicy struct {
+	// Priority orders the restart of the VMs of a failed node, VMs with a higher priority restart first.
+	// The default priority of the vm-force-reset-policy setting is used when it's unset.
+	Priority *int `json:"priority,omitempty"`
+	// RestartPolicy is Restart or DoNotRestart, Restart by default.
+	RestartPolicy HARestartPolicy `json:"restartPolicy,omitempty"`
+	// RestartDelaySeconds delays the restart of the VM after its node is force reset.
+	RestartDelaySeconds int64 `json:"restartDelaySeconds,omitempty"`
+	// MaxRestarts is how many times the VM can be restarted after a node failure within MaxRestartsWindowSeconds,
+	// the VM is stopped instead once it's reached. Zero means no limit.
+	MaxRestarts int `json:"maxRestarts,omitempty"`
+	// MaxRestartsWindowSeconds is the window of MaxRestarts, one hour by default.
+	MaxRestartsWindowSeconds int64 `json:"maxRestartsWindowSeconds,omitempty"`
+}
+
+// HAStatus records the restarts of a VM after node failures, in the AnnotationHAStatus annotation
+type HAStatus struct {
+	State   string `json:"state"`
+	Message string `json:"message,omitempty"`
+	// NodeName is the failed node of the last restart
+	NodeName string `json:"nodeName,omitempty"`
+	// Time is when the VM got its state
+	Time string `json:"time,omitempty"`
+	// Restarts are the times of the restarts within the max restarts window
+	Restarts []string `json:"restarts,omitempty"`
+}
+
+const defaultHAMaxRestartsWindow = time.Hour
+
+// GetHAPolicy returns the HA policy of the VM, the default policy if it has none
+func GetHAPolicy(vm *kubevirtv1.VirtualMachine) (*HAPolicy, error) {
+	policy := &HAPolicy{}
+	value := vm.Annotations[util.AnnotationHAPolicy]
+	if value == "" {
+		return policy, nil
+	}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, fmt.Errorf("invalid HA policy %s: %w", value, err)
+	}
+	return policy, nil
+}
+
+// Validate checks the values of the HA policy
+func (p *HAPolicy) Validate() error {
+	switch p.RestartPolicy {
+	case "", HARestartPolicyRestart, HARestartPolicyDoNotRestart:
+	default:
+		return fmt.Errorf("restartPolicy should be %s or %s, value: %s", HARestartPolicyRestart, HARestartPolicyDoNotRestart, p.RestartPolicy)
+	}
+	if p.RestartDelaySeconds < 0 {
+		return fmt.Errorf("restartDelaySeconds should not be less than 0, value: %d", p.RestartDelaySeconds)
+	}
+	if p.MaxRestarts < 0 {
+		return fmt.Errorf("maxRestarts should not be less than 0, value: %d", p.MaxRestarts)
+	}
+	if p.MaxRestartsWindowSeconds < 0 {
+		return fmt.Errorf("maxRestartsWindowSeconds should not be less than 0, value: %d", p.MaxRestartsWindowSeconds)
+	}
+	return nil
+}
+
+// MaxRestartsWindow returns the window of MaxRestarts
+func (p *HAPolicy) MaxRestartsWindow() time.Duration {
+	if p.MaxRestartsWindowSeconds <= 0 {
+		return defaultHAMaxRestartsWindow
+	}
+	return time.Duration(p.MaxRestartsWindowSeconds) * time.Second
+}
+
+// GetHAStatus returns the HA status of the VM, an empty status if it has none or an invalid one
+func GetHAStatus(vm *kubevirtv1.VirtualMachine) *HAStatus {
+	status := &HAStatus{}
+	if value := vm.Annotations[util.AnnotationHAStatus]; value != "" {
+		if err := json.Unmarshal([]byte(value), status); err != nil {
+			return &HAStatus{}
+		}
+	}
+	return status
+}
+
+// RestartsSince returns the restarts of the status after the time
+func (s *HAStatus) RestartsSince(since time.Time) []string {
+	var restarts []string
+	for _, restart := range s.Restarts {
+		if t, err := time.Parse(time.RFC3339, restart); err == nil && t.After(since) {
+			restarts = append(restarts, restart)
+		}
+	}
+	return restarts
+}
@@ -0,0 +1,46 @@
+package virtualmachine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/cloudweav/cloudweav/pkg/util"
+)
+
+func Test_GetHAPolicy(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    string
+		valid    bool
+		priority *int
+	}{
+		{desc: "no policy", value: "", valid: true},
+		{desc: "priority", value: `{"priority":100,"restartPolicy":"Restart"}`, valid: true, priority: func(i int) *int { return &i }(100)},
+		{desc: "do not restart", value: `{"restartPolicy":"DoNotRestart"}`, valid: true},
+		{desc: "unknown restart policy", value: `{"restartPolicy":"Sometimes"}`, valid: false},
+		{desc: "negative delay", value: `{"restartDelaySeconds":-1}`, valid: false},
+		{desc: "negative max restarts", value: `{"maxRestarts":-1}`, valid: false},
+		{desc: "not json", value: `priority=100`, valid: false},
+	}
+
+	for _, tc := range testCases {
+		vm := &kubevirtv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{util.AnnotationHAPolicy: tc.value},
+			},
+		}
+		policy, err := GetHAPolicy(vm)
+		if err == nil {
+			err = policy.Validate()
+		}
+		if !tc.valid {
+			assert.Error(t, err, tc.desc)
+			continue
+		}
+		assert.NoError(t, err, tc.desc)
+		assert.Equal(t, tc.priority, policy.Priority, tc.desc)
+	}
+}
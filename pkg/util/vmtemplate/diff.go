@@ -0,0 +1,145 @@
+package vmtemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	kubevirtv1 "kubevirt.io/api/core/v1"
+)
+
+// Change is a difference of a single field between two objects. From is nil when the field is added,
+// To is nil when the field is removed.
+type Change struct {
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// Diff returns the field changes from one object to the other, compared by their JSON representation.
+func Diff(from, to interface{}) ([]Change, error) {
+	fromObj, err := toGeneric(from)
+	if err != nil {
+		return nil, err
+	}
+	toObj, err := toGeneric(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	diffObject("", fromObj, toObj, &changes)
+	return changes, nil
+}
+
+func toGeneric(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func diffObject(path string, from, to interface{}, changes *[]Change) {
+	switch fromValue := from.(type) {
+	case map[string]interface{}:
+		toValue, ok := to.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(fromValue)+len(toValue))
+		for key := range fromValue {
+			keys = append(keys, key)
+		}
+		for key := range toValue {
+			if _, ok := fromValue[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			diffObject(joinPath(path, key), fromValue[key], toValue[key], changes)
+		}
+		return
+	case []interface{}:
+		toValue, ok := to.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(fromValue) || i < len(toValue); i++ {
+			var fromItem, toItem interface{}
+			if i < len(fromValue) {
+				fromItem = fromValue[i]
+			}
+			if i < len(toValue) {
+				toItem = toValue[i]
+			}
+			diffObject(fmt.Sprintf("%s[%d]", path, i), fromItem, toItem, changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, Change{Path: path, From: from, To: to})
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Drift returns the changes of the VM spec compared to the expected VM, which is the VM rendered from
+// its template version and mutated the way it is on creation. The fields set on the VM by KubeVirt,
+// the fields derived from the cluster state at creation, and the names of the VM volumes and secrets
+// which are generated per VM, are ignored.
+func Drift(expected, vm *kubevirtv1.VirtualMachine) ([]Change, error) {
+	if expected.Spec.Template == nil || vm.Spec.Template == nil {
+		return nil, nil
+	}
+	return Diff(normalizeForDrift(&expected.Spec.Template.Spec), normalizeForDrift(&vm.Spec.Template.Spec))
+}
+
+func normalizeForDrift(spec *kubevirtv1.VirtualMachineInstanceSpec) *kubevirtv1.VirtualMachineInstanceSpec {
+	normalized := spec.DeepCopy()
+	// the node cache preferences and the overcommitted requests depend on the images and the settings
+	// at the time the VM was created
+	normalized.Affinity = nil
+	normalized.Domain.Resources.Requests = nil
+	normalized.Domain.Firmware = nil
+	normalized.Domain.Machine = nil
+	for i := range normalized.Domain.Devices.Interfaces {
+		normalized.Domain.Devices.Interfaces[i].MacAddress = ""
+	}
+	for i := range normalized.AccessCredentials {
+		credential := &normalized.AccessCredentials[i]
+		if credential.SSHPublicKey != nil && credential.SSHPublicKey.Source.Secret != nil {
+			credential.SSHPublicKey.Source.Secret.SecretName = ""
+		}
+		if credential.UserPassword != nil && credential.UserPassword.Source.Secret != nil {
+			credential.UserPassword.Source.Secret.SecretName = ""
+		}
+	}
+	for i := range normalized.Volumes {
+		volume := &normalized.Volumes[i]
+		if volume.PersistentVolumeClaim != nil {
+			volume.PersistentVolumeClaim.ClaimName = ""
+		}
+		if volume.CloudInitNoCloud != nil {
+			if volume.CloudInitNoCloud.UserDataSecretRef != nil {
+				volume.CloudInitNoCloud.UserDataSecretRef.Name = ""
+			}
+			if volume.CloudInitNoCloud.NetworkDataSecretRef != nil {
+				volume.CloudInitNoCloud.NetworkDataSecretRef.Name = ""
+			}
+		}
+	}
+	return normalized
+}
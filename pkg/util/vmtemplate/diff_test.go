@@ -0,0 +1,76 @@
+package vmtemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+func TestDiff(t *testing.T) {
+	from := cloudweavv1.VirtualMachineTemplateVersionSpec{
+		TemplateID: "default/template",
+		KeyPairIDs: []string{"default/key-1", "default/key-2"},
+		Parameters: []cloudweavv1.VirtualMachineTemplateParameter{{Name: "hostname"}},
+	}
+	to := cloudweavv1.VirtualMachineTemplateVersionSpec{
+		TemplateID:  "default/template",
+		Description: "hardened",
+		KeyPairIDs:  []string{"default/key-1"},
+		Parameters:  []cloudweavv1.VirtualMachineTemplateParameter{{Name: "hostname", Required: true}},
+	}
+
+	changes, err := Diff(from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, []Change{
+		{Path: "description", To: "hardened"},
+		{Path: "keyPairIds[1]", From: "default/key-2"},
+		{Path: "parameters[0].required", To: true},
+	}, changes)
+
+	changes, err = Diff(from, from)
+	assert.Nil(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDrift(t *testing.T) {
+	newSpec := func(cores uint32, claimName, macAddress string) *kubevirtv1.VirtualMachineInstanceTemplateSpec {
+		return &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+			Spec: kubevirtv1.VirtualMachineInstanceSpec{
+				Domain: kubevirtv1.DomainSpec{
+					CPU: &kubevirtv1.CPU{Cores: cores},
+					Devices: kubevirtv1.Devices{
+						Interfaces: []kubevirtv1.Interface{{Name: "default", MacAddress: macAddress}},
+					},
+				},
+				Volumes: []kubevirtv1.Volume{{
+					Name: "rootdisk",
+					VolumeSource: kubevirtv1.VolumeSource{
+						PersistentVolumeClaim: &kubevirtv1.PersistentVolumeClaimVolumeSource{
+							PersistentVolumeClaimVolumeSource: corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	expected := &kubevirtv1.VirtualMachine{
+		Spec: kubevirtv1.VirtualMachineSpec{Template: newSpec(2, "templateversion-v1-disk-0", "")},
+	}
+
+	vm := &kubevirtv1.VirtualMachine{
+		Spec: kubevirtv1.VirtualMachineSpec{Template: newSpec(2, "vm-rootdisk-abcde", "52:54:00:00:00:01")},
+	}
+	changes, err := Drift(expected, vm)
+	assert.Nil(t, err)
+	assert.Empty(t, changes, "generated names and addresses are not drifts")
+
+	vm.Spec.Template.Spec.Domain.CPU.Cores = 4
+	changes, err = Drift(expected, vm)
+	assert.Nil(t, err)
+	assert.Equal(t, []Change{{Path: "domain.cpu.cores", From: float64(2), To: float64(4)}}, changes)
+}
@@ -0,0 +1,283 @@
+package vmtemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+// placeholderRegexp matches the parameter references like ${{ name }}. Only plain substitution is
+// supported, there is no expression or function evaluation in the templates.
+var placeholderRegexp = regexp.MustCompile(`\$\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+var parameterNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateParameters checks the parameter declarations of a template version.
+func ValidateParameters(parameters []cloudweavv1.VirtualMachineTemplateParameter) error {
+	names := make(map[string]struct{}, len(parameters))
+	for _, p := range parameters {
+		if !parameterNameRegexp.MatchString(p.Name) {
+			return fmt.Errorf("invalid parameter name %q", p.Name)
+		}
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("duplicated parameter %q", p.Name)
+		}
+		names[p.Name] = struct{}{}
+
+		switch p.Type {
+		case "", cloudweavv1.TemplateParameterTypeString, cloudweavv1.TemplateParameterTypeInteger, cloudweavv1.TemplateParameterTypeBoolean:
+		default:
+			return fmt.Errorf("parameter %q has unsupported type %q", p.Name, p.Type)
+		}
+
+		if p.Pattern != "" {
+			if _, err := compilePattern(p.Pattern); err != nil {
+				return fmt.Errorf("parameter %q has invalid pattern: %w", p.Name, err)
+			}
+		}
+		if p.Default != "" {
+			if err := validateValue(p, p.Default); err != nil {
+				return fmt.Errorf("invalid default value: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateReferences checks that every parameter referenced in the template version VM spec is declared.
+func ValidateReferences(spec *cloudweavv1.VirtualMachineTemplateVersionSpec) error {
+	data, err := json.Marshal(spec.VM)
+	if err != nil {
+		return err
+	}
+	declared := make(map[string]struct{}, len(spec.Parameters))
+	for _, p := range spec.Parameters {
+		declared[p.Name] = struct{}{}
+	}
+	for _, name := range ReferencedParameters(string(data)) {
+		if _, ok := declared[name]; !ok {
+			return fmt.Errorf("parameter %q is referenced but not declared", name)
+		}
+	}
+	return nil
+}
+
+// ResolveParameters validates the input values against the parameter declarations and returns
+// the values to render, with the defaults applied.
+func ResolveParameters(parameters []cloudweavv1.VirtualMachineTemplateParameter, inputs map[string]string) (map[string]string, error) {
+	values := make(map[string]string, len(parameters))
+	for _, p := range parameters {
+		value, ok := inputs[p.Name]
+		if !ok || value == "" {
+			value = p.Default
+		}
+		if value == "" {
+			if p.Required {
+				return nil, fmt.Errorf("parameter %q is required", p.Name)
+			}
+			values[p.Name] = ""
+			continue
+		}
+		if err := validateValue(p, value); err != nil {
+			return nil, err
+		}
+		values[p.Name] = value
+	}
+
+	for name := range inputs {
+		if _, ok := values[name]; !ok {
+			return nil, fmt.Errorf("unknown parameter %q", name)
+		}
+	}
+	return values, nil
+}
+
+func validateValue(p cloudweavv1.VirtualMachineTemplateParameter, value string) error {
+	// the values are substituted as is in the cloud-init YAML, a line break would let them add
+	// arbitrary keys to the user data
+	if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return fmt.Errorf("parameter %q must not contain line breaks or control characters", p.Name)
+	}
+
+	switch p.Type {
+	case cloudweavv1.TemplateParameterTypeInteger:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("parameter %q must be an integer", p.Name)
+		}
+	case cloudweavv1.TemplateParameterTypeBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("parameter %q must be a boolean", p.Name)
+		}
+	}
+	if p.Pattern != "" {
+		pattern, err := compilePattern(p.Pattern)
+		if err != nil {
+			return fmt.Errorf("parameter %q has invalid pattern: %w", p.Name, err)
+		}
+		if !pattern.MatchString(value) {
+			return fmt.Errorf("parameter %q does not match pattern %s", p.Name, p.Pattern)
+		}
+	}
+	return nil
+}
+
+// compilePattern compiles the pattern of a parameter, it has to match the whole value.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// ReferencedParameters returns the names of the parameters referenced in the text.
+func ReferencedParameters(text string) []string {
+	var names []string
+	seen := map[string]struct{}{}
+	for _, match := range placeholderRegexp.FindAllStringSubmatch(text, -1) {
+		if _, ok := seen[match[1]]; ok {
+			continue
+		}
+		seen[match[1]] = struct{}{}
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// Render replaces the parameter references in the text with their values. References to unknown
+// parameters are kept as is.
+func Render(text string, values map[string]string) string {
+	return placeholderRegexp.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := placeholderRegexp.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// RenderYAML replaces the parameter references in the scalars of a YAML document, like the cloud-init
+// user data. The values are encoded as YAML scalars, so they can't change the structure of the document.
+// The text is rendered as is if it's not a YAML mapping or sequence, like a user data script.
+func RenderYAML(text string, values map[string]string) (string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(text), &doc); err != nil || len(doc.Content) == 0 ||
+		(doc.Content[0].Kind != yaml.MappingNode && doc.Content[0].Kind != yaml.SequenceNode) {
+		return Render(text, values), nil
+	}
+	if !renderNode(&doc, values) {
+		return text, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// renderNode renders the scalars of the node and returns whether any of them changed.
+func renderNode(node *yaml.Node, values map[string]string) bool {
+	rendered := false
+	if node.Kind == yaml.ScalarNode {
+		if value := Render(node.Value, values); value != node.Value {
+			node.Value = value
+			// a plain scalar is typed after its rendered value like the text would be, the encoder quotes
+			// it if needed
+			if node.Style == 0 {
+				node.Tag = ""
+			}
+			rendered = true
+		}
+	}
+	for _, child := range node.Content {
+		if renderNode(child, values) {
+			rendered = true
+		}
+	}
+	return rendered
+}
+
+// RenderVMSource renders the parameter references found in the string fields of the VM source spec.
+// The inline cloud-init data is rendered as YAML.
+func RenderVMSource(source cloudweavv1.VirtualMachineSourceSpec, values map[string]string) (cloudweavv1.VirtualMachineSourceSpec, error) {
+	var rendered cloudweavv1.VirtualMachineSourceSpec
+
+	// the cloud-init data is left out of the plain rendering, so the rendered values aren't rendered again
+	source = *source.DeepCopy()
+	var cloudInits []string
+	for _, data := range cloudInitData(&source) {
+		value, err := RenderYAML(*data, values)
+		if err != nil {
+			return rendered, fmt.Errorf("failed to render the cloud-init data: %w", err)
+		}
+		cloudInits = append(cloudInits, value)
+		*data = ""
+	}
+
+	data, err := json.Marshal(source)
+	if err != nil {
+		return rendered, err
+	}
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return rendered, err
+	}
+	data, err = json.Marshal(renderObject(obj, values))
+	if err != nil {
+		return rendered, err
+	}
+	if err := json.Unmarshal(data, &rendered); err != nil {
+		return rendered, fmt.Errorf("failed to decode the rendered VM: %w", err)
+	}
+	for i, data := range cloudInitData(&rendered) {
+		*data = cloudInits[i]
+	}
+	return rendered, nil
+}
+
+// cloudInitData returns the inline cloud-init user data and network data of the VM volumes.
+func cloudInitData(source *cloudweavv1.VirtualMachineSourceSpec) []*string {
+	if source.Spec.Template == nil {
+		return nil
+	}
+	var data []*string
+	for i := range source.Spec.Template.Spec.Volumes {
+		volume := &source.Spec.Template.Spec.Volumes[i]
+		if cloudInit := volume.CloudInitNoCloud; cloudInit != nil {
+			data = append(data, &cloudInit.UserData, &cloudInit.NetworkData)
+		}
+		if cloudInit := volume.CloudInitConfigDrive; cloudInit != nil {
+			data = append(data, &cloudInit.UserData, &cloudInit.NetworkData)
+		}
+	}
+	return data
+}
+
+func renderObject(obj interface{}, values map[string]string) interface{} {
+	switch v := obj.(type) {
+	case string:
+		return Render(v, values)
+	case []interface{}:
+		for i := range v {
+			v[i] = renderObject(v[i], values)
+		}
+		return v
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = renderObject(value, values)
+		}
+		return v
+	default:
+		return v
+	}
+}
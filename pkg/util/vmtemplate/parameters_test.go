@@ -0,0 +1,159 @@
+package vmtemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+)
+
+func TestValidateParameters(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		parameters  []cloudweavv1.VirtualMachineTemplateParameter
+		expectError bool
+	}{
+		{
+			name: "valid parameters",
+			parameters: []cloudweavv1.VirtualMachineTemplateParameter{
+				{Name: "hostname", Pattern: "^[a-z0-9-]+$", Default: "vm-1"},
+				{Name: "replicas", Type: cloudweavv1.TemplateParameterTypeInteger, Default: "3"},
+			},
+		},
+		{
+			name: "duplicated name",
+			parameters: []cloudweavv1.VirtualMachineTemplateParameter{
+				{Name: "hostname"},
+				{Name: "hostname"},
+			},
+			expectError: true,
+		},
+		{
+			name: "invalid pattern",
+			parameters: []cloudweavv1.VirtualMachineTemplateParameter{
+				{Name: "hostname", Pattern: "[a-z"},
+			},
+			expectError: true,
+		},
+		{
+			name: "default does not match the type",
+			parameters: []cloudweavv1.VirtualMachineTemplateParameter{
+				{Name: "enabled", Type: cloudweavv1.TemplateParameterTypeBoolean, Default: "maybe"},
+			},
+			expectError: true,
+		},
+		{
+			name: "default with a line break",
+			parameters: []cloudweavv1.VirtualMachineTemplateParameter{
+				{Name: "hostname", Default: "vm-1\nruncmd: [reboot]"},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := ValidateParameters(tc.parameters)
+		assert.Equal(t, tc.expectError, err != nil, tc.name)
+	}
+}
+
+func TestResolveParameters(t *testing.T) {
+	parameters := []cloudweavv1.VirtualMachineTemplateParameter{
+		{Name: "hostname", Pattern: "^[a-z0-9-]+$", Required: true},
+		{Name: "port", Type: cloudweavv1.TemplateParameterTypeInteger, Default: "22"},
+	}
+
+	values, err := ResolveParameters(parameters, map[string]string{"hostname": "web-1"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"hostname": "web-1", "port": "22"}, values)
+
+	_, err = ResolveParameters(parameters, map[string]string{})
+	assert.NotNil(t, err, "missing required parameter")
+
+	_, err = ResolveParameters(parameters, map[string]string{"hostname": "Web_1"})
+	assert.NotNil(t, err, "value does not match the pattern")
+
+	_, err = ResolveParameters(parameters, map[string]string{"hostname": "web-1", "port": "ssh"})
+	assert.NotNil(t, err, "value is not an integer")
+
+	_, err = ResolveParameters(parameters, map[string]string{"hostname": "web-1", "unknown": "x"})
+	assert.NotNil(t, err, "undeclared parameter")
+
+	_, err = ResolveParameters([]cloudweavv1.VirtualMachineTemplateParameter{{Name: "hostname", Pattern: "[a-z]+"}},
+		map[string]string{"hostname": "abc: {x: 1} # comment"})
+	assert.NotNil(t, err, "the pattern must match the whole value")
+
+	for _, value := range []string{"web-1\nruncmd: [reboot]", "web-1\r", "web\t1", "web\x001"} {
+		_, err = ResolveParameters([]cloudweavv1.VirtualMachineTemplateParameter{{Name: "hostname"}}, map[string]string{"hostname": value})
+		assert.NotNil(t, err, "value %q must not be rendered in the user data", value)
+	}
+}
+
+func TestRenderVMSource(t *testing.T) {
+	source := cloudweavv1.VirtualMachineSourceSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "${{ app }}"},
+		},
+		Spec: kubevirtv1.VirtualMachineSpec{
+			Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+				Spec: kubevirtv1.VirtualMachineInstanceSpec{
+					Hostname: "${{hostname}}",
+					Volumes: []kubevirtv1.Volume{{
+						Name: "cloudinitdisk",
+						VolumeSource: kubevirtv1.VolumeSource{
+							CloudInitNoCloud: &kubevirtv1.CloudInitNoCloudSource{
+								UserData: "#cloud-config\nhostname: ${{ hostname }}\nruncmd: ${{ unknown }}",
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	rendered, err := RenderVMSource(source, map[string]string{"app": "web", "hostname": "web-1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "web", rendered.ObjectMeta.Labels["app"])
+	assert.Equal(t, "web-1", rendered.Spec.Template.Spec.Hostname)
+	assert.Equal(t, "#cloud-config\nhostname: web-1\nruncmd: ${{ unknown }}\n", rendered.Spec.Template.Spec.Volumes[0].CloudInitNoCloud.UserData)
+	assert.Equal(t, "${{ app }}", source.ObjectMeta.Labels["app"], "source should not be modified")
+	assert.Equal(t, []string{"hostname", "unknown"}, ReferencedParameters(source.Spec.Template.Spec.Volumes[0].CloudInitNoCloud.UserData))
+}
+
+func TestRenderYAML(t *testing.T) {
+	userData := `#cloud-config
+hostname: web-${{ hostname }}
+password: "${{ password }}"
+runcmd:
+  - [echo, "${{ hostname }}"] # greet
+write_files:
+  - path: /etc/port
+    content: ${{ port }}
+`
+	rendered, err := RenderYAML(userData, map[string]string{
+		"hostname": "abc: {x: 1} # comment",
+		"password": `p"ss`,
+		"port":     "8080",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `#cloud-config
+hostname: 'web-abc: {x: 1} # comment'
+password: "p\"ss"
+runcmd:
+  - [echo, "abc: {x: 1} # comment"] # greet
+write_files:
+  - path: /etc/port
+    content: 8080
+`, rendered)
+
+	rendered, err = RenderYAML("#!/bin/sh\necho ${{ hostname }}", map[string]string{"hostname": "web-1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "#!/bin/sh\necho web-1", rendered, "scripts are rendered as is")
+
+	rendered, err = RenderYAML("#cloud-config\nhostname:   web-1", map[string]string{"hostname": "web-2"})
+	assert.Nil(t, err)
+	assert.Equal(t, "#cloud-config\nhostname:   web-1", rendered, "the document is kept as is if nothing is rendered")
+}
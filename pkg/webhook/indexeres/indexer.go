@@ -54,6 +54,7 @@ func RegisterIndexers(clients *clients.Clients) {
 
 	vmInformer := clients.KubevirtFactory.Kubevirt().V1().VirtualMachine().Cache()
 	vmInformer.AddIndexer(indexeresutil.VMByPVCIndex, indexeresutil.VMByPVC)
+	vmInformer.AddIndexer(indexeresutil.VMByImageIDIndex, indexeresutil.VMByImageID)
 
 	svmBackupCache := clients.CloudweavFactory.Cloudweavhci().V1beta1().ScheduleVMBackup().Cache()
 	svmBackupCache.AddIndexer(ScheduleVMBackupBySourceVM, scheduleVMBackupBySourceVM)
@@ -0,0 +1,106 @@
+package maintenanceplan
+
+import (
+	"fmt"
+	"reflect"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	werror "github.com/cloudweav/cloudweav/pkg/webhook/error"
+	"github.com/cloudweav/cloudweav/pkg/webhook/types"
+)
+
+const (
+	fieldNodes        = "spec.nodes"
+	fieldNodeSelector = "spec.nodeSelector"
+	fieldHookImage    = "spec.hook.image"
+	fieldSpec         = "spec"
+)
+
+func NewValidator(plans ctlcloudweavv1.MaintenancePlanCache) types.Validator {
+	return &maintenancePlanValidator{
+		plans: plans,
+	}
+}
+
+type maintenancePlanValidator struct {
+	types.DefaultValidator
+	plans ctlcloudweavv1.MaintenancePlanCache
+}
+
+func (v *maintenancePlanValidator) Resource() types.Resource {
+	return types.Resource{
+		Names:      []string{v1beta1.MaintenancePlanResourceName},
+		Scope:      admissionregv1.ClusterScope,
+		APIGroup:   v1beta1.SchemeGroupVersion.Group,
+		APIVersion: v1beta1.SchemeGroupVersion.Version,
+		ObjectType: &v1beta1.MaintenancePlan{},
+		OperationTypes: []admissionregv1.OperationType{
+			admissionregv1.Create,
+			admissionregv1.Update,
+		},
+	}
+}
+
+func (v *maintenancePlanValidator) Create(_ *types.Request, newObj runtime.Object) error {
+	plan := newObj.(*v1beta1.MaintenancePlan)
+
+	if err := validateSpec(plan); err != nil {
+		return err
+	}
+
+	// the plans would fight over the nodes and the volume rebuilds
+	plans, err := v.plans.List(labels.Everything())
+	if err != nil {
+		return werror.NewInternalError(err.Error())
+	}
+	for _, p := range plans {
+		if p.Status.Phase != v1beta1.MaintenancePlanPhaseSucceeded && p.Status.Phase != v1beta1.MaintenancePlanPhaseFailed {
+			return werror.NewBadRequest(fmt.Sprintf("maintenance plan %s is still in progress", p.Name))
+		}
+	}
+	return nil
+}
+
+func (v *maintenancePlanValidator) Update(_ *types.Request, oldObj runtime.Object, newObj runtime.Object) error {
+	oldPlan := oldObj.(*v1beta1.MaintenancePlan)
+	newPlan := newObj.(*v1beta1.MaintenancePlan)
+
+	if reflect.DeepEqual(oldPlan.Spec, newPlan.Spec) {
+		return nil
+	}
+	if err := validateSpec(newPlan); err != nil {
+		return err
+	}
+
+	// only pausing and resuming the plan is allowed once it started
+	if oldPlan.Status.Phase == "" {
+		return nil
+	}
+	oldSpec := oldPlan.Spec.DeepCopy()
+	oldSpec.Paused = newPlan.Spec.Paused
+	if !reflect.DeepEqual(*oldSpec, newPlan.Spec) {
+		return werror.NewInvalidError("only spec.paused can be changed once the plan started", fieldSpec)
+	}
+	return nil
+}
+
+func validateSpec(plan *v1beta1.MaintenancePlan) error {
+	if len(plan.Spec.Nodes) == 0 && plan.Spec.NodeSelector == nil {
+		return werror.NewInvalidError("nodes or nodeSelector should be specified", fieldNodes)
+	}
+	if plan.Spec.NodeSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(plan.Spec.NodeSelector); err != nil {
+			return werror.NewInvalidError(err.Error(), fieldNodeSelector)
+		}
+	}
+	if plan.Spec.Hook != nil && plan.Spec.Hook.Image == "" {
+		return werror.NewInvalidError("hook image should be specified", fieldHookImage)
+	}
+	return nil
+}
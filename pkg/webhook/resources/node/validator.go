@@ -17,6 +17,7 @@ import (
 	ctlnode "github.com/cloudweav/cloudweav/pkg/controller/master/node"
 	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
 	"github.com/cloudweav/cloudweav/pkg/util"
+	"github.com/cloudweav/cloudweav/pkg/util/numa"
 	"github.com/cloudweav/cloudweav/pkg/util/virtualmachineinstance"
 	werror "github.com/cloudweav/cloudweav/pkg/webhook/error"
 	"github.com/cloudweav/cloudweav/pkg/webhook/types"
@@ -65,6 +66,36 @@ func (v *nodeValidator) Update(_ *types.Request, oldObj runtime.Object, newObj r
 	if err := v.validateCPUManagerOperation(newNode); err != nil {
 		return err
 	}
+	if err := validateRoleRequest(oldNode, newNode, nodeList); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateRoleRequest validates a new promote or demote request, the promote controller checks the etcd quorum
+// before it demotes the node
+func validateRoleRequest(oldNode, newNode *corev1.Node, nodeList []*corev1.Node) error {
+	request := newNode.Annotations[ctlnode.RoleRequestAnnotationKey]
+	if request == "" || request == oldNode.Annotations[ctlnode.RoleRequestAnnotationKey] {
+		return nil
+	}
+
+	switch request {
+	case ctlnode.RoleRequestPromote:
+		if err := ctlnode.CheckPromotion(nodeList, newNode); err != nil {
+			return werror.NewBadRequest(err.Error())
+		}
+	case ctlnode.RoleRequestDemote:
+		if !ctlnode.IsManagementRole(newNode) {
+			return werror.NewBadRequest(fmt.Sprintf("node %s is not in the control plane", newNode.Name))
+		}
+		if ctlnode.GetControlPlanePolicy(newNode) == ctlnode.ControlPlanePolicyPinned {
+			return werror.NewBadRequest(fmt.Sprintf("node %s is pinned to the control plane", newNode.Name))
+		}
+	default:
+		return werror.NewBadRequest(fmt.Sprintf("invalid role request %q, must be %s or %s", request,
+			ctlnode.RoleRequestPromote, ctlnode.RoleRequestDemote))
+	}
 	return nil
 }
 
@@ -113,9 +144,11 @@ func (v *nodeValidator) validateCPUManagerOperation(node *corev1.Node) error {
 	}
 	policy := updateStatus.Policy
 
-	// check if cpu manager policy is the same
-	if err := checkCPUManagerLabel(node, policy); err != nil {
-		return err
+	// check if cpu manager policy is the same, unless the request only toggles NUMA-aware scheduling of the static policy
+	if !isNUMAAwareUpdate(node, updateStatus) {
+		if err := checkCPUManagerLabel(node, policy); err != nil {
+			return err
+		}
 	}
 	// check if there is other job that still updating cpu manager policy to the same node
 	if err := checkCurrentNodeCPUManagerJobs(node, v.jobCache); err != nil {
@@ -130,6 +163,10 @@ func (v *nodeValidator) validateCPUManagerOperation(node *corev1.Node) error {
 	if err := checkCPUPinningVMIs(node, policy, v.vmiCache); err != nil {
 		return err
 	}
+	// check if there is any vm with guest NUMA while NUMA-aware scheduling is going to be disabled
+	if err := checkGuestNUMAVMIs(node, updateStatus, v.vmiCache); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -147,6 +184,20 @@ func checkCPUManagerLabel(node *corev1.Node, policy ctlnode.CPUManagerPolicy) er
 	return nil
 }
 
+// isNUMAAwareUpdate returns whether the static policy is already enabled and the request only enables or disables
+// the topology and memory managers
+func isNUMAAwareUpdate(node *corev1.Node, updateStatus *ctlnode.CPUManagerUpdateStatus) bool {
+	if updateStatus.Policy != ctlnode.CPUManagerStaticPolicy {
+		return false
+	}
+	cpuManagerLabel, err := strconv.ParseBool(node.Labels[kubevirtv1.CPUManager])
+	if err != nil || !cpuManagerLabel {
+		return false
+	}
+	numaAwareLabel, _ := strconv.ParseBool(node.Labels[util.LabelNUMAAware])
+	return numaAwareLabel != updateStatus.NUMAAware
+}
+
 func checkCurrentNodeCPUManagerJobs(node *corev1.Node, jobCache ctlbatchv1.JobCache) error {
 	jobNames, err := getCPUManagerRunningJobNamesOnNodes(jobCache, []string{node.Name})
 	if err != nil {
@@ -211,6 +262,27 @@ func checkCPUPinningVMIs(node *corev1.Node, policy ctlnode.CPUManagerPolicy, vmi
 	return nil
 }
 
+func checkGuestNUMAVMIs(node *corev1.Node, updateStatus *ctlnode.CPUManagerUpdateStatus, vmiCache ctlkubevirtv1.VirtualMachineInstanceCache) error {
+	if updateStatus.NUMAAware {
+		return nil
+	}
+	if numaAwareLabel, _ := strconv.ParseBool(node.Labels[util.LabelNUMAAware]); !numaAwareLabel {
+		return nil
+	}
+
+	vmis, err := virtualmachineinstance.ListByNode(node, labels.NewSelector(), vmiCache)
+	if err != nil {
+		return werror.NewInternalError(err.Error())
+	}
+
+	for _, vmi := range vmis {
+		if numa.IsGuestNUMA(&vmi.Spec) {
+			return werror.NewBadRequest("there should not be any running VMs with guest NUMA when disabling NUMA-aware scheduling")
+		}
+	}
+	return nil
+}
+
 func getCPUManagerRunningJobNamesOnNodes(jobCache ctlbatchv1.JobCache, nodeNames []string) ([]string, error) {
 	jobs, err := ctlnode.GetCPUManagerRunningJobsOnNodes(jobCache, nodeNames)
 	if err != nil {
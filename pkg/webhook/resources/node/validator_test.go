@@ -626,3 +626,186 @@ func TestCheckCPUPinningVMIs(t *testing.T) {
 		}
 	}
 }
+
+func TestIsNUMAAwareUpdate(t *testing.T) {
+	nodeWithLabels := func(labels map[string]string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: labels,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		updateStatus *ctlnode.CPUManagerUpdateStatus
+		node         *corev1.Node
+		expected     bool
+	}{
+		{
+			name:         "enable NUMA-aware scheduling on a node with the static policy",
+			updateStatus: &ctlnode.CPUManagerUpdateStatus{Policy: ctlnode.CPUManagerStaticPolicy, NUMAAware: true},
+			node:         nodeWithLabels(map[string]string{kubevirtv1.CPUManager: "true"}),
+			expected:     true,
+		},
+		{
+			name:         "disable NUMA-aware scheduling on a node with the static policy",
+			updateStatus: &ctlnode.CPUManagerUpdateStatus{Policy: ctlnode.CPUManagerStaticPolicy},
+			node:         nodeWithLabels(map[string]string{kubevirtv1.CPUManager: "true", util.LabelNUMAAware: "true"}),
+			expected:     true,
+		},
+		{
+			name:         "NUMA-aware scheduling is already enabled",
+			updateStatus: &ctlnode.CPUManagerUpdateStatus{Policy: ctlnode.CPUManagerStaticPolicy, NUMAAware: true},
+			node:         nodeWithLabels(map[string]string{kubevirtv1.CPUManager: "true", util.LabelNUMAAware: "true"}),
+			expected:     false,
+		},
+		{
+			name:         "the CPU manager is not enabled yet",
+			updateStatus: &ctlnode.CPUManagerUpdateStatus{Policy: ctlnode.CPUManagerStaticPolicy, NUMAAware: true},
+			node:         nodeWithLabels(map[string]string{kubevirtv1.CPUManager: "false"}),
+			expected:     false,
+		},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, isNUMAAwareUpdate(tc.node, tc.updateStatus), tc.name)
+	}
+}
+
+func TestCheckGuestNUMAVMIs(t *testing.T) {
+	numaNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-0",
+			Labels: map[string]string{kubevirtv1.CPUManager: "true", util.LabelNUMAAware: "true"},
+		},
+	}
+	guestNUMAVMI := &kubevirtv1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "vm-0",
+			Labels: map[string]string{
+				util.LabelNodeNameKey: "node-0",
+			},
+		},
+		Spec: kubevirtv1.VirtualMachineInstanceSpec{
+			Domain: kubevirtv1.DomainSpec{
+				CPU: &kubevirtv1.CPU{
+					DedicatedCPUPlacement: true,
+					NUMA:                  &kubevirtv1.NUMA{GuestMappingPassthrough: &kubevirtv1.NUMAGuestMappingPassthrough{}},
+				},
+			},
+		},
+	}
+	testCases := []struct {
+		name         string
+		updateStatus *ctlnode.CPUManagerUpdateStatus
+		vmis         []*kubevirtv1.VirtualMachineInstance
+		errMsg       string
+	}{
+		{
+			name:         "valid update: NUMA-aware scheduling stays enabled",
+			updateStatus: &ctlnode.CPUManagerUpdateStatus{Policy: ctlnode.CPUManagerStaticPolicy, NUMAAware: true},
+			vmis:         []*kubevirtv1.VirtualMachineInstance{guestNUMAVMI},
+		},
+		{
+			name:         "valid update: no guest NUMA vm in node-0",
+			updateStatus: &ctlnode.CPUManagerUpdateStatus{Policy: ctlnode.CPUManagerStaticPolicy},
+		},
+		{
+			name:         "invalid update: there is a guest NUMA vm in node-0",
+			updateStatus: &ctlnode.CPUManagerUpdateStatus{Policy: ctlnode.CPUManagerStaticPolicy},
+			vmis:         []*kubevirtv1.VirtualMachineInstance{guestNUMAVMI},
+			errMsg:       "there should not be any running VMs with guest NUMA when disabling NUMA-aware scheduling",
+		},
+	}
+	for _, tc := range testCases {
+		var clientset = fake.NewSimpleClientset()
+		for _, vmi := range tc.vmis {
+			err := clientset.Tracker().Add(vmi)
+			assert.Nil(t, err, "Mock resource should add into fake controller tracker")
+		}
+		vmiCache := fakeclients.VirtualMachineInstanceCache(clientset.KubevirtV1().VirtualMachineInstances)
+		err := checkGuestNUMAVMIs(numaNode, tc.updateStatus, vmiCache)
+		if tc.errMsg != "" {
+			assert.NotNil(t, err, tc.name)
+			assert.Equal(t, tc.errMsg, err.Error(), tc.name)
+		} else {
+			assert.Nil(t, err, tc.name)
+		}
+	}
+}
+
+func TestValidateRoleRequest(t *testing.T) {
+	management := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-0",
+			Labels: map[string]string{ctlnode.KubeControlPlaneNodeLabelKey: "true"},
+		},
+	}
+	pinned := management.DeepCopy()
+	pinned.Labels[ctlnode.CloudweavMgmtNodeLabelKey] = "true"
+	worker := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-1",
+			Labels: map[string]string{ctlnode.CloudweavManagedNodeLabelKey: "true"},
+		},
+	}
+	excluded := worker.DeepCopy()
+	excluded.Labels[ctlnode.CloudweavWorkerNodeLabelKey] = "true"
+
+	withRequest := func(node *corev1.Node, request string) *corev1.Node {
+		node = node.DeepCopy()
+		node.Annotations = map[string]string{ctlnode.RoleRequestAnnotationKey: request}
+		return node
+	}
+
+	testCases := []struct {
+		name    string
+		oldNode *corev1.Node
+		newNode *corev1.Node
+		errMsg  string
+	}{
+		{
+			name:    "valid update: promote a worker",
+			oldNode: worker,
+			newNode: withRequest(worker, ctlnode.RoleRequestPromote),
+		},
+		{
+			name:    "valid update: demote a management node",
+			oldNode: management,
+			newNode: withRequest(management, ctlnode.RoleRequestDemote),
+		},
+		{
+			name:    "invalid update: promote an excluded node",
+			oldNode: excluded,
+			newNode: withRequest(excluded, ctlnode.RoleRequestPromote),
+			errMsg:  "node node-1 is excluded from the control plane",
+		},
+		{
+			name:    "invalid update: demote a worker",
+			oldNode: worker,
+			newNode: withRequest(worker, ctlnode.RoleRequestDemote),
+			errMsg:  "node node-1 is not in the control plane",
+		},
+		{
+			name:    "invalid update: demote a pinned node",
+			oldNode: pinned,
+			newNode: withRequest(pinned, ctlnode.RoleRequestDemote),
+			errMsg:  "node node-0 is pinned to the control plane",
+		},
+		{
+			name:    "invalid update: unknown request",
+			oldNode: worker,
+			newNode: withRequest(worker, "upgrade"),
+			errMsg:  `invalid role request "upgrade", must be promote or demote`,
+		},
+	}
+	for _, tc := range testCases {
+		err := validateRoleRequest(tc.oldNode, tc.newNode, []*corev1.Node{management, worker})
+		if tc.errMsg != "" {
+			assert.NotNil(t, err, tc.name)
+			assert.Equal(t, tc.errMsg, err.Error(), tc.name)
+		} else {
+			assert.Nil(t, err, tc.name)
+		}
+	}
+}
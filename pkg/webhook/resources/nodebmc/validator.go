@@ -0,0 +1,113 @@
+package nodebmc
+
+import (
+	"fmt"
+
+	admissionregv1 "k8s.io/api/admissionregistration/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+
+	"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/util"
+	werror "github.com/cloudweav/cloudweav/pkg/webhook/error"
+	"github.com/cloudweav/cloudweav/pkg/webhook/types"
+)
+
+const fieldCredentialsSecret = "spec.credentialsSecret"
+
+func NewValidator(sar authorizationv1client.SubjectAccessReviewInterface) types.Validator {
+	return &nodeBMCValidator{
+		sar: sar,
+	}
+}
+
+type nodeBMCValidator struct {
+	types.DefaultValidator
+
+	sar authorizationv1client.SubjectAccessReviewInterface
+}
+
+func (v *nodeBMCValidator) Resource() types.Resource {
+	return types.Resource{
+		Names:      []string{v1beta1.NodeBMCResourceName},
+		Scope:      admissionregv1.ClusterScope,
+		APIGroup:   v1beta1.SchemeGroupVersion.Group,
+		APIVersion: v1beta1.SchemeGroupVersion.Version,
+		ObjectType: &v1beta1.NodeBMC{},
+		OperationTypes: []admissionregv1.OperationType{
+			admissionregv1.Create,
+			admissionregv1.Update,
+		},
+	}
+}
+
+func (v *nodeBMCValidator) Create(request *types.Request, newObj runtime.Object) error {
+	nodeBMC := newObj.(*v1beta1.NodeBMC)
+	if err := checkCredentialsSecret(nodeBMC); err != nil {
+		return err
+	}
+	return v.checkSecretAccess(request, nodeBMC)
+}
+
+func (v *nodeBMCValidator) Update(request *types.Request, oldObj runtime.Object, newObj runtime.Object) error {
+	oldNodeBMC := oldObj.(*v1beta1.NodeBMC)
+	newNodeBMC := newObj.(*v1beta1.NodeBMC)
+	if err := checkCredentialsSecret(newNodeBMC); err != nil {
+		return err
+	}
+	// pointing the BMC to another address sends the credentials there as well
+	if oldNodeBMC.Spec.CredentialsSecret == newNodeBMC.Spec.CredentialsSecret && oldNodeBMC.Spec.Address == newNodeBMC.Spec.Address {
+		return nil
+	}
+	return v.checkSecretAccess(request, newNodeBMC)
+}
+
+// checkCredentialsSecret only accepts secrets in the cloudweav-system namespace. The controller reads
+// the secret with its own service account, a secret in another namespace would let a user able to
+// create a NodeBMC send the credentials of any secret to a BMC address of their choice.
+func checkCredentialsSecret(nodeBMC *v1beta1.NodeBMC) error {
+	secret := nodeBMC.Spec.CredentialsSecret
+	if secret.Name == "" {
+		return werror.NewInvalidError("credentials secret name is required", fieldCredentialsSecret+".name")
+	}
+	if secret.Namespace != util.CloudweavSystemNamespaceName {
+		return werror.NewInvalidError(fmt.Sprintf("credentials secret must be in the %s namespace", util.CloudweavSystemNamespaceName),
+			fieldCredentialsSecret+".namespace")
+	}
+	return nil
+}
+
+// checkSecretAccess requires the user to be able to get the credentials secret, otherwise any secret of
+// the cloudweav-system namespace, like the cluster CA or TLS secrets, could be sent to the BMC address.
+func (v *nodeBMCValidator) checkSecretAccess(request *types.Request, nodeBMC *v1beta1.NodeBMC) error {
+	secret := nodeBMC.Spec.CredentialsSecret
+	extra := make(map[string]authorizationv1.ExtraValue, len(request.UserInfo.Extra))
+	for key, value := range request.UserInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	review, err := v.sar.Create(request.Context, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: secret.Namespace,
+				Verb:      "get",
+				Version:   "v1",
+				Resource:  "secrets",
+				Name:      secret.Name,
+			},
+			User:   request.UserInfo.Username,
+			Groups: request.UserInfo.Groups,
+			UID:    request.UserInfo.UID,
+			Extra:  extra,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return werror.NewInternalError(fmt.Sprintf("failed to check user permission, error: %s", err.Error()))
+	}
+	if !review.Status.Allowed || review.Status.Denied {
+		return werror.NewInvalidError(fmt.Sprintf("user has no permission to get the credentials secret %s/%s", secret.Namespace, secret.Name),
+			fieldCredentialsSecret)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package nodebmc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rancher/wrangler/v3/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	"github.com/cloudweav/cloudweav/pkg/webhook/types"
+)
+
+func TestCheckCredentialsSecret(t *testing.T) {
+	tests := []struct {
+		name        string
+		secret      corev1.SecretReference
+		expectedErr bool
+	}{
+		{
+			name:   "secret in cloudweav-system",
+			secret: corev1.SecretReference{Namespace: "cloudweav-system", Name: "node1-bmc"},
+		},
+		{
+			name:        "secret in another namespace",
+			secret:      corev1.SecretReference{Namespace: "default", Name: "node1-bmc"},
+			expectedErr: true,
+		},
+		{
+			name:        "secret without a namespace",
+			secret:      corev1.SecretReference{Name: "node1-bmc"},
+			expectedErr: true,
+		},
+		{
+			name:        "secret without a name",
+			secret:      corev1.SecretReference{Namespace: "cloudweav-system"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		nodeBMC := &v1beta1.NodeBMC{
+			Spec: v1beta1.NodeBMCSpec{
+				Protocol:          v1beta1.NodeBMCProtocolRedfish,
+				Address:           "https://10.0.0.5",
+				CredentialsSecret: tc.secret,
+			},
+		}
+		err := checkCredentialsSecret(nodeBMC)
+		assert.Equal(t, tc.expectedErr, err != nil, tc.name)
+	}
+}
+
+func TestNodeBMCValidator_checkSecretAccess(t *testing.T) {
+	clientSet := k8sfake.NewSimpleClientset()
+	var reviews []authorizationv1.SubjectAccessReviewSpec
+	clientSet.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		reviews = append(reviews, review.Spec)
+		// the user can only get the BMC credentials
+		review.Status.Allowed = review.Spec.ResourceAttributes.Name == "node1-bmc"
+		return true, review, nil
+	})
+	validator := NewValidator(clientSet.AuthorizationV1().SubjectAccessReviews())
+	request := types.NewRequest(&webhook.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			UserInfo: authenticationv1.UserInfo{Username: "alice", Groups: []string{"admins"}},
+		},
+		Context: context.TODO(),
+	}, nil)
+	newNodeBMC := func(secretName, address string) *v1beta1.NodeBMC {
+		return &v1beta1.NodeBMC{
+			Spec: v1beta1.NodeBMCSpec{
+				Protocol:          v1beta1.NodeBMCProtocolRedfish,
+				Address:           address,
+				CredentialsSecret: corev1.SecretReference{Namespace: "cloudweav-system", Name: secretName},
+			},
+		}
+	}
+
+	assert.Nil(t, validator.Create(request, newNodeBMC("node1-bmc", "https://10.0.0.5")))
+	assert.Equal(t, "alice", reviews[0].User)
+	assert.Equal(t, []string{"admins"}, reviews[0].Groups)
+	assert.Equal(t, &authorizationv1.ResourceAttributes{
+		Namespace: "cloudweav-system",
+		Verb:      "get",
+		Version:   "v1",
+		Resource:  "secrets",
+		Name:      "node1-bmc",
+	}, reviews[0].ResourceAttributes)
+
+	assert.NotNil(t, validator.Create(request, newNodeBMC("tls-cloudweav", "https://10.0.0.5")), "secret the user can't get")
+
+	// the secret isn't checked again unless the secret or the address changes
+	reviews = nil
+	existing := newNodeBMC("tls-cloudweav", "https://10.0.0.5")
+	assert.Nil(t, validator.Update(request, existing, newNodeBMC("tls-cloudweav", "https://10.0.0.5")))
+	assert.Empty(t, reviews)
+	assert.NotNil(t, validator.Update(request, existing, newNodeBMC("tls-cloudweav", "https://attacker.example.com")))
+	assert.NotNil(t, validator.Update(request, newNodeBMC("node1-bmc", "https://10.0.0.5"), existing))
+}
@@ -33,6 +33,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	mapset "github.com/deckarep/golang-set/v2"
 
@@ -43,12 +44,14 @@ import (
 	nodectl "github.com/cloudweav/cloudweav/pkg/controller/master/node"
 	settingctl "github.com/cloudweav/cloudweav/pkg/controller/master/setting"
 	storagenetworkctl "github.com/cloudweav/cloudweav/pkg/controller/master/storagenetwork"
+	"github.com/cloudweav/cloudweav/pkg/controller/master/upgrade/repoinfo"
 	ctlv1beta1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
 	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
 	ctllhv1b2 "github.com/cloudweav/cloudweav/pkg/generated/controllers/longhorn.io/v1beta2"
 	ctlnetworkv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/network.cloudweavhci.io/v1beta1"
 	ctlsnapshotv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/snapshot.storage.k8s.io/v1"
 	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/upgraderesponder"
 	"github.com/cloudweav/cloudweav/pkg/util"
 	tlsutil "github.com/cloudweav/cloudweav/pkg/util/tls"
 	vmUtil "github.com/cloudweav/cloudweav/pkg/util/virtualmachine"
@@ -96,6 +99,13 @@ var validateSettingFuncs = map[string]validateSettingFunc{
 	settings.AutoRotateRKE2CertsSettingName:                    validateAutoRotateRKE2Certs,
 	settings.KubeconfigDefaultTokenTTLMinutesSettingName:       validateKubeConfigTTLSetting,
 	settings.AdditionalGuestMemoryOverheadRatioName:            validateAdditionalGuestMemoryOverheadRatio,
+	settings.ImageGCPolicySettingName:                          validateImageGCPolicy,
+	settings.SSHCASettingName:                                  validateSSHCA,
+	settings.NodeHealthPolicySettingName:                       validateNodeHealthPolicy,
+	settings.ReleaseChannelSettingName:                         validateReleaseChannel,
+	settings.UpgradeCheckerTelemetrySettingName:                validateUpgradeCheckerTelemetry,
+	settings.UpgradeResponderVersionsSettingName:               validateUpgradeResponderVersions,
+	settings.UpgradeISOTrustedKeysSettingName:                  validateUpgradeISOTrustedKeys,
 }
 
 type validateSettingUpdateFunc func(oldSetting *v1beta1.Setting, newSetting *v1beta1.Setting) error
@@ -116,6 +126,13 @@ var validateSettingUpdateFuncs = map[string]validateSettingUpdateFunc{
 	settings.AutoRotateRKE2CertsSettingName:                    validateUpdateAutoRotateRKE2Certs,
 	settings.KubeconfigDefaultTokenTTLMinutesSettingName:       validateUpdateKubeConfigTTLSetting,
 	settings.AdditionalGuestMemoryOverheadRatioName:            validateUpdateAdditionalGuestMemoryOverheadRatio,
+	settings.ImageGCPolicySettingName:                          validateUpdateImageGCPolicy,
+	settings.SSHCASettingName:                                  validateUpdateSSHCA,
+	settings.NodeHealthPolicySettingName:                       validateUpdateNodeHealthPolicy,
+	settings.ReleaseChannelSettingName:                         validateUpdateReleaseChannel,
+	settings.UpgradeCheckerTelemetrySettingName:                validateUpdateUpgradeCheckerTelemetry,
+	settings.UpgradeResponderVersionsSettingName:               validateUpdateUpgradeResponderVersions,
+	settings.UpgradeISOTrustedKeysSettingName:                  validateUpdateUpgradeISOTrustedKeys,
 }
 
 type validateSettingDeleteFunc func(setting *v1beta1.Setting) error
@@ -408,6 +425,190 @@ func validateUpdateVMForceResetPolicy(_ *v1beta1.Setting, newSetting *v1beta1.Se
 	return validateVMForceResetPolicy(newSetting)
 }
 
+func validateImageGCPolicyHelper(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	policy, err := settings.DecodeImageGCPolicy(value)
+	if err != nil {
+		return err
+	}
+
+	for _, namespace := range policy.Namespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return fmt.Errorf("invalid namespace %q: %s", namespace, strings.Join(errs, ", "))
+		}
+	}
+
+	return nil
+}
+
+func validateImageGCPolicy(setting *v1beta1.Setting) error {
+	if err := validateImageGCPolicyHelper(setting.Default); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordDefault)
+	}
+
+	if err := validateImageGCPolicyHelper(setting.Value); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordValue)
+	}
+
+	return nil
+}
+
+func validateUpdateImageGCPolicy(_ *v1beta1.Setting, newSetting *v1beta1.Setting) error {
+	return validateImageGCPolicy(newSetting)
+}
+
+func validateSSHCAHelper(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if _, err := settings.DecodeSSHCAConfig(value); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateSSHCA(setting *v1beta1.Setting) error {
+	if err := validateSSHCAHelper(setting.Default); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordDefault)
+	}
+
+	if err := validateSSHCAHelper(setting.Value); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordValue)
+	}
+
+	return nil
+}
+
+func validateUpdateSSHCA(_ *v1beta1.Setting, newSetting *v1beta1.Setting) error {
+	return validateSSHCA(newSetting)
+}
+
+func validateNodeHealthPolicyHelper(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if _, err := settings.DecodeNodeHealthPolicy(value); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateNodeHealthPolicy(setting *v1beta1.Setting) error {
+	if err := validateNodeHealthPolicyHelper(setting.Default); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordDefault)
+	}
+
+	if err := validateNodeHealthPolicyHelper(setting.Value); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordValue)
+	}
+
+	return nil
+}
+
+func validateUpdateNodeHealthPolicy(_ *v1beta1.Setting, newSetting *v1beta1.Setting) error {
+	return validateNodeHealthPolicy(newSetting)
+}
+
+func validateReleaseChannelHelper(value string) error {
+	if value == "" || slices.Contains(upgraderesponder.ReleaseChannels, value) {
+		return nil
+	}
+	return fmt.Errorf("invalid release channel %s, it must be one of %s", value, strings.Join(upgraderesponder.ReleaseChannels, ", "))
+}
+
+func validateReleaseChannel(setting *v1beta1.Setting) error {
+	if err := validateReleaseChannelHelper(setting.Default); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordDefault)
+	}
+
+	if err := validateReleaseChannelHelper(setting.Value); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordValue)
+	}
+
+	return nil
+}
+
+func validateUpdateReleaseChannel(_ *v1beta1.Setting, newSetting *v1beta1.Setting) error {
+	return validateReleaseChannel(newSetting)
+}
+
+func validateUpgradeCheckerTelemetryHelper(value string) error {
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" && !slices.Contains(upgraderesponder.ExtraInfoFields, field) {
+			return fmt.Errorf("invalid telemetry field %s, it must be one of %s", field, strings.Join(upgraderesponder.ExtraInfoFields, ", "))
+		}
+	}
+	return nil
+}
+
+func validateUpgradeCheckerTelemetry(setting *v1beta1.Setting) error {
+	if err := validateUpgradeCheckerTelemetryHelper(setting.Default); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordDefault)
+	}
+
+	if err := validateUpgradeCheckerTelemetryHelper(setting.Value); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordValue)
+	}
+
+	return nil
+}
+
+func validateUpdateUpgradeCheckerTelemetry(_ *v1beta1.Setting, newSetting *v1beta1.Setting) error {
+	return validateUpgradeCheckerTelemetry(newSetting)
+}
+
+func validateUpgradeResponderVersionsHelper(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if _, err := upgraderesponder.ParseVersions(value); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateUpgradeResponderVersions(setting *v1beta1.Setting) error {
+	if err := validateUpgradeResponderVersionsHelper(setting.Default); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordDefault)
+	}
+
+	if err := validateUpgradeResponderVersionsHelper(setting.Value); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordValue)
+	}
+
+	return nil
+}
+
+func validateUpdateUpgradeResponderVersions(_ *v1beta1.Setting, newSetting *v1beta1.Setting) error {
+	return validateUpgradeResponderVersions(newSetting)
+}
+
+func validateUpgradeISOTrustedKeys(setting *v1beta1.Setting) error {
+	if _, err := repoinfo.ParseTrustedKeys(setting.Default); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordDefault)
+	}
+
+	if _, err := repoinfo.ParseTrustedKeys(setting.Value); err != nil {
+		return werror.NewInvalidError(err.Error(), settings.KeywordValue)
+	}
+
+	return nil
+}
+
+func validateUpdateUpgradeISOTrustedKeys(_ *v1beta1.Setting, newSetting *v1beta1.Setting) error {
+	return validateUpgradeISOTrustedKeys(newSetting)
+}
+
 // chech if this backup target is updated again by controller to strip secret information
 func (v *settingValidator) isUpdatedS3BackupTarget(target *settings.BackupTarget) bool {
 	if target.Type != settings.S3BackupType || target.SecretAccessKey != "" || target.AccessKeyID != "" {
@@ -1448,7 +1649,7 @@ func validateUpgradeConfigFields(setting *v1beta1.Setting, isSingleNode bool) er
 
 	// Validate the image preload strategy type field
 	switch strategyType {
-	case settings.SkipType, settings.SequentialType, settings.ParallelType:
+	case settings.SkipType, settings.SequentialType, settings.ParallelType, settings.AdaptiveType:
 	default:
 		return fmt.Errorf("invalid image preload strategy type: %s", strategyType)
 	}
@@ -1459,6 +1660,19 @@ func validateUpgradeConfigFields(setting *v1beta1.Setting, isSingleNode bool) er
 		return fmt.Errorf("invalid image preload concurrency: %d", concurrency)
 	}
 
+	// Validate the image preload strategy free disk space field
+	minFreeDiskSpaceGB := upgradeConfig.PreloadOption.Strategy.MinFreeDiskSpaceGB
+	if minFreeDiskSpaceGB < 0 {
+		return fmt.Errorf("invalid image preload min free disk space: %d", minFreeDiskSpaceGB)
+	}
+
+	// Validate the upgrade repo provider field
+	switch upgradeConfig.RepoOption.Provider {
+	case "", settings.VMRepoProvider, settings.PodRepoProvider:
+	default:
+		return fmt.Errorf("invalid upgrade repo provider: %s", upgradeConfig.RepoOption.Provider)
+	}
+
 	// Validate the restore VM field
 	if upgradeConfig.RestoreVM && !isSingleNode {
 		return fmt.Errorf("restoreVM is only supported in single node cluster")
@@ -992,6 +992,38 @@ func Test_validateUpgradeConfig(t *testing.T) {
 			},
 			expectedErr: false,
 		},
+		{
+			name: "do image preload adaptively - value",
+			args: &v1beta1.Setting{
+				ObjectMeta: metav1.ObjectMeta{Name: settings.UpgradeConfigSettingName},
+				Value:      `{"imagePreloadOption":{"strategy":{"type":"adaptive","concurrency":4,"minFreeDiskSpaceGB":50}}}`,
+			},
+			expectedErr: false,
+		},
+		{
+			name: "do image preload adaptively with negative value for min free disk space - value",
+			args: &v1beta1.Setting{
+				ObjectMeta: metav1.ObjectMeta{Name: settings.UpgradeConfigSettingName},
+				Value:      `{"imagePreloadOption":{"strategy":{"type":"adaptive","minFreeDiskSpaceGB":-1}}}`,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "serve upgrade repo with pods - value",
+			args: &v1beta1.Setting{
+				ObjectMeta: metav1.ObjectMeta{Name: settings.UpgradeConfigSettingName},
+				Value:      `{"imagePreloadOption":{"strategy":{"type":"sequential"}},"repoOption":{"provider":"pod"}}`,
+			},
+			expectedErr: false,
+		},
+		{
+			name: "serve upgrade repo with invalid provider - value",
+			args: &v1beta1.Setting{
+				ObjectMeta: metav1.ObjectMeta{Name: settings.UpgradeConfigSettingName},
+				Value:      `{"imagePreloadOption":{"strategy":{"type":"sequential"}},"repoOption":{"provider":"container"}}`,
+			},
+			expectedErr: true,
+		},
 		{
 			name: "enable restoreVM under single node cluster",
 			args: &v1beta1.Setting{
@@ -1110,3 +1142,59 @@ func Test_validateAdditionalGuestMemoryOverheadRatio(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateImageGCPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        *v1beta1.Setting
+		expectedErr bool
+	}{
+		{
+			name: "empty input",
+			args: &v1beta1.Setting{
+				ObjectMeta: metav1.ObjectMeta{Name: settings.ImageGCPolicySettingName},
+			},
+			expectedErr: false,
+		},
+		{
+			name: "invalid json default",
+			args: &v1beta1.Setting{
+				ObjectMeta: metav1.ObjectMeta{Name: settings.ImageGCPolicySettingName},
+				Default:    "not json",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "zero unused days",
+			args: &v1beta1.Setting{
+				ObjectMeta: metav1.ObjectMeta{Name: settings.ImageGCPolicySettingName},
+				Value:      `{"enable":true,"unusedDays":0}`,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid namespace",
+			args: &v1beta1.Setting{
+				ObjectMeta: metav1.ObjectMeta{Name: settings.ImageGCPolicySettingName},
+				Value:      `{"enable":true,"unusedDays":7,"namespaces":["Team_A"]}`,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "valid policy",
+			args: &v1beta1.Setting{
+				ObjectMeta: metav1.ObjectMeta{Name: settings.ImageGCPolicySettingName},
+				Default:    settings.InitImageGCPolicy(),
+				Value:      `{"enable":true,"unusedDays":7,"dryRun":false,"namespaces":["default","team-a"]}`,
+			},
+			expectedErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateImageGCPolicy(tt.args)
+			assert.Equal(t, tt.expectedErr, err != nil)
+		})
+	}
+}
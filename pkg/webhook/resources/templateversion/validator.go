@@ -2,6 +2,7 @@ package templateversion
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/sirupsen/logrus"
 	admissionregv1 "k8s.io/api/admissionregistration/v1"
@@ -11,14 +12,17 @@ import (
 	"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
 	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
 	"github.com/cloudweav/cloudweav/pkg/ref"
+	vmtemplateutil "github.com/cloudweav/cloudweav/pkg/util/vmtemplate"
 	werror "github.com/cloudweav/cloudweav/pkg/webhook/error"
 	"github.com/cloudweav/cloudweav/pkg/webhook/types"
 )
 
 const (
-	fieldTemplateID      = "spec.templateId"
-	fieldKeyPairIDs      = "spec.keyPairIds"
-	fieldResourcesLimits = "spec.vm.spec.template.spec.domain.resources.limits"
+	fieldTemplateID        = "spec.templateId"
+	fieldKeyPairIDs        = "spec.keyPairIds"
+	fieldResourcesLimits   = "spec.vm.spec.template.spec.domain.resources.limits"
+	fieldParameters        = "spec.parameters"
+	fieldDeprecationReason = "spec.deprecation.reason"
 )
 
 func NewValidator(templateCache ctlcloudweavv1.VirtualMachineTemplateCache, templateVersionCache ctlcloudweavv1.VirtualMachineTemplateVersionCache, keypairs ctlcloudweavv1.KeyPairCache) types.Validator {
@@ -75,6 +79,13 @@ func (v *templateVersionValidator) Create(_ *types.Request, newObj runtime.Objec
 		}
 	}
 
+	if err := vmtemplateutil.ValidateParameters(vmTemplVersion.Spec.Parameters); err != nil {
+		return werror.NewInvalidError(err.Error(), fieldParameters)
+	}
+	if err := vmtemplateutil.ValidateReferences(&vmTemplVersion.Spec); err != nil {
+		return werror.NewInvalidError(err.Error(), fieldParameters)
+	}
+
 	template := vmTemplVersion.Spec.VM.Spec.Template
 	if template != nil {
 		limits := template.Spec.Domain.Resources.Limits
@@ -92,10 +103,23 @@ func (v *templateVersionValidator) Create(_ *types.Request, newObj runtime.Objec
 	return nil
 }
 
-func (v *templateVersionValidator) Update(request *types.Request, _ runtime.Object, _ runtime.Object) error {
+func (v *templateVersionValidator) Update(request *types.Request, oldObj runtime.Object, newObj runtime.Object) error {
 	if request.IsFromController() {
 		return nil
 	}
+
+	// users are only allowed to deprecate or undeprecate a template version
+	oldVersion := oldObj.(*v1beta1.VirtualMachineTemplateVersion)
+	newVersion := newObj.(*v1beta1.VirtualMachineTemplateVersion)
+	oldSpec := oldVersion.Spec.DeepCopy()
+	oldSpec.Deprecation = newVersion.Spec.Deprecation
+	if reflect.DeepEqual(*oldSpec, newVersion.Spec) {
+		if newVersion.Spec.Deprecation != nil && newVersion.Spec.Deprecation.Reason == "" {
+			return werror.NewInvalidError("Deprecation reason is required", fieldDeprecationReason)
+		}
+		return nil
+	}
+
 	logrus.Infof("not allow for user %s", request.UserInfo.Username)
 	return werror.NewMethodNotAllowed("Update templateVersion is not supported")
 }
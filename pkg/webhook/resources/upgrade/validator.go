@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -19,18 +20,21 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/cache"
 	kubeletconfigv1 "k8s.io/kubelet/config/v1beta1"
 	kubeletstatsv1 "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
 	"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlnode "github.com/cloudweav/cloudweav/pkg/controller/master/node"
 	"github.com/cloudweav/cloudweav/pkg/controller/master/upgrade"
 	ctlclusterv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cluster.x-k8s.io/v1beta1"
 	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
 	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
 	ctllhv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/longhorn.io/v1beta2"
+	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/upgradehelper/readiness"
 	"github.com/cloudweav/cloudweav/pkg/util"
-	"github.com/cloudweav/cloudweav/pkg/util/virtualmachineinstance"
 	werror "github.com/cloudweav/cloudweav/pkg/webhook/error"
 	"github.com/cloudweav/cloudweav/pkg/webhook/indexeres"
 	versionWebhook "github.com/cloudweav/cloudweav/pkg/webhook/resources/version"
@@ -46,6 +50,9 @@ const (
 	defaultNewImageSize                uint64 = 13 * 1024 * 1024 * 1024 // 13GB, this value aggregates all tarball image sizes. It may change in the future.
 	defaultImageGCHighThresholdPercent        = 85.0                    // default value in kubelet config
 	freeSystemPartitionMsg                    = "df -h '/usr/local/'"
+	skipVersionCheckAnnotation                = "cloudweavhci.io/skip-version-check"
+	// readinessOverridesAnnotation lists the readiness checks whose failures don't block the upgrade, separated by commas
+	readinessOverridesAnnotation = "cloudweavhci.io/readiness-overrides"
 )
 
 func NewValidator(
@@ -59,6 +66,8 @@ func NewValidator(
 	vmBackupCache ctlcloudweavv1.VirtualMachineBackupCache,
 	svmbackupCache ctlcloudweavv1.ScheduleVMBackupCache,
 	vmiCache ctlkubevirtv1.VirtualMachineInstanceCache,
+	settingCache ctlcloudweavv1.SettingCache,
+	readinessCaches *readiness.Caches,
 	httpClient *http.Client,
 	bearToken string,
 ) types.Validator {
@@ -73,6 +82,8 @@ func NewValidator(
 		vmBackupCache:     vmBackupCache,
 		svmbackupCache:    svmbackupCache,
 		vmiCache:          vmiCache,
+		settingCache:      settingCache,
+		readinessCaches:   readinessCaches,
 		httpClient:        httpClient,
 		bearToken:         bearToken,
 	}
@@ -91,6 +102,8 @@ type upgradeValidator struct {
 	vmBackupCache     ctlcloudweavv1.VirtualMachineBackupCache
 	svmbackupCache    ctlcloudweavv1.ScheduleVMBackupCache
 	vmiCache          ctlkubevirtv1.VirtualMachineInstanceCache
+	settingCache      ctlcloudweavv1.SettingCache
+	readinessCaches   *readiness.Caches
 	httpClient        *http.Client
 	bearToken         string
 }
@@ -104,6 +117,7 @@ func (v *upgradeValidator) Resource() types.Resource {
 		ObjectType: &v1beta1.Upgrade{},
 		OperationTypes: []admissionregv1.OperationType{
 			admissionregv1.Create,
+			admissionregv1.Update,
 			admissionregv1.Delete,
 		},
 	}
@@ -116,6 +130,10 @@ func (v *upgradeValidator) Create(_ *types.Request, newObj runtime.Object) error
 		return werror.NewBadRequest("version or image field are not specified.")
 	}
 
+	if err := v.checkSchedule(newUpgrade); err != nil {
+		return err
+	}
+
 	var version *v1beta1.Version
 	var err error
 	if newUpgrade.Spec.Version != "" && newUpgrade.Spec.Image == "" {
@@ -145,17 +163,12 @@ func (v *upgradeValidator) Create(_ *types.Request, newObj runtime.Object) error
 		}
 	}
 
-	return v.checkResources(version)
+	return v.checkResources(newUpgrade, version)
 }
 
-func (v *upgradeValidator) checkResources(version *v1beta1.Version) error {
-	hasDegradedVolume, err := v.hasDegradedVolume()
-	if err != nil {
-		return werror.NewInternalError(err.Error())
-	}
-
-	if hasDegradedVolume {
-		return werror.NewBadRequest("there are degraded volumes, please check all volumes are healthy")
+func (v *upgradeValidator) checkResources(upgrade *v1beta1.Upgrade, version *v1beta1.Version) error {
+	if err := v.checkReadiness(upgrade, version); err != nil {
+		return err
 	}
 
 	cluster, err := v.clusters.Get(util.FleetLocalNamespaceName, util.LocalClusterName)
@@ -187,35 +200,45 @@ func (v *upgradeValidator) checkResources(version *v1beta1.Version) error {
 		return err
 	}
 
-	if err := v.checkSingleReplicaVolumes(); err != nil {
-		return err
-	}
-
-	return v.checkNonLiveMigratableVMs()
+	return v.checkSingleReplicaVolumes()
 }
 
-func (v *upgradeValidator) hasDegradedVolume() (bool, error) {
-	nodes, err := v.nodes.List(labels.Everything())
+// checkReadiness runs the readiness checks, which are shared with the UpgradeReadiness controller and the upgrade helper
+func (v *upgradeValidator) checkReadiness(upgrade *v1beta1.Upgrade, version *v1beta1.Version) error {
+	serverVersion, err := v.settingCache.Get(settings.ServerVersionSettingName)
 	if err != nil {
-		return false, err
+		return werror.NewInternalError(fmt.Sprintf("can't get %s setting, err: %+v", settings.ServerVersionSettingName, err))
 	}
-
-	if len(nodes) < 3 {
-		return false, nil
+	currentVersion := serverVersion.Value
+	if currentVersion == "" {
+		currentVersion = serverVersion.Default
 	}
 
-	volumes, err := v.lhVolumes.List(util.LonghornSystemNamespaceName, labels.Everything())
+	snapshot, err := v.readinessCaches.Snapshot(currentVersion, version)
 	if err != nil {
-		return false, err
+		return werror.NewInternalError(fmt.Sprintf("can't get the cluster state for the readiness checks, err: %+v", err))
 	}
 
-	for _, volume := range volumes {
-		if volume.Status.Robustness == lhv1beta2.VolumeRobustnessDegraded {
-			return true, nil
-		}
+	checks, ready := readiness.Run(snapshot, readinessOverrides(upgrade))
+	if ready {
+		return nil
 	}
+	return werror.NewBadRequest(fmt.Sprintf("the cluster isn't ready for the upgrade, the failed checks can be overridden with the %s annotation: %s",
+		readinessOverridesAnnotation, strings.Join(readiness.Failures(checks), "; ")))
+}
 
-	return false, nil
+func readinessOverrides(upgrade *v1beta1.Upgrade) []string {
+	var overrides []string
+	for _, name := range strings.Split(upgrade.Annotations[readinessOverridesAnnotation], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			overrides = append(overrides, name)
+		}
+	}
+	// the upgrade controller skips the version check as well
+	if skip, err := strconv.ParseBool(upgrade.Annotations[skipVersionCheckAnnotation]); err == nil && skip {
+		overrides = append(overrides, readiness.CheckVersion)
+	}
+	return overrides
 }
 
 func (v *upgradeValidator) checkManagedCharts() error {
@@ -288,20 +311,8 @@ func (v *upgradeValidator) checkNodes(version *v1beta1.Version) error {
 		}
 	}
 
+	// the node readiness and schedulability are checked by the readiness checks
 	for _, node := range nodes {
-		for _, condition := range node.Status.Conditions {
-			if condition.Type == corev1.NodeReady {
-				if condition.Status != corev1.ConditionTrue {
-					return werror.NewBadRequest(fmt.Sprintf("node %s is not ready, please wait for it to be ready", node.Name))
-				}
-				break
-			}
-		}
-
-		if node.Spec.Unschedulable {
-			return werror.NewBadRequest(fmt.Sprintf("node %s is unschedulable, please wait for it to be schedulable", node.Name))
-		}
-
 		if err := v.checkDiskSpace(node, minFreeDiskSpace); err != nil {
 			return err
 		}
@@ -406,28 +417,105 @@ func (v *upgradeValidator) checkSingleReplicaVolumes() error {
 	return nil
 }
 
-func (v *upgradeValidator) checkNonLiveMigratableVMs() error {
-	allVMIs, err := v.vmiCache.List(corev1.NamespaceAll, labels.Everything())
-	if err != nil {
-		return err
+func (v *upgradeValidator) Update(_ *types.Request, oldObj runtime.Object, newObj runtime.Object) error {
+	oldUpgrade := oldObj.(*v1beta1.Upgrade)
+	newUpgrade := newObj.(*v1beta1.Upgrade)
+
+	if reflect.DeepEqual(oldUpgrade.Spec.Windows, newUpgrade.Spec.Windows) &&
+		reflect.DeepEqual(oldUpgrade.Spec.NodeBatches, newUpgrade.Spec.NodeBatches) &&
+		reflect.DeepEqual(oldUpgrade.Spec.Canary, newUpgrade.Spec.Canary) {
+		return nil
 	}
+	return v.checkSchedule(newUpgrade)
+}
 
-	allNodes, err := v.nodes.List(labels.Everything())
-	if err != nil {
+// checkSchedule checks the upgrade windows, the node batches and the canary
+func (v *upgradeValidator) checkSchedule(newUpgrade *v1beta1.Upgrade) error {
+	for _, window := range newUpgrade.Spec.Windows {
+		if _, _, _, err := upgrade.ParseUpgradeWindow(window); err != nil {
+			return werror.NewInvalidError(err.Error(), "spec.windows")
+		}
+	}
+
+	if err := v.checkCanary(newUpgrade.Spec.Canary); err != nil {
 		return err
 	}
 
-	nonLiveMigratableVMNames, err := virtualmachineinstance.GetAllNonLiveMigratableVMINames(allVMIs, allNodes)
+	if len(newUpgrade.Spec.NodeBatches) == 0 {
+		return nil
+	}
+	batched := make(map[string]int)
+	for i, batch := range newUpgrade.Spec.NodeBatches {
+		if len(batch.Nodes) == 0 {
+			return werror.NewInvalidError("node batch is empty", "spec.nodeBatches")
+		}
+		for _, nodeName := range batch.Nodes {
+			if _, ok := batched[nodeName]; ok {
+				return werror.NewInvalidError(fmt.Sprintf("node %s is in more than one batch", nodeName), "spec.nodeBatches")
+			}
+			batched[nodeName] = i
+			if _, err := v.nodes.Get(nodeName); err != nil {
+				return werror.NewInvalidError(fmt.Sprintf("node %s is not found", nodeName), "spec.nodeBatches")
+			}
+		}
+	}
+
+	nodes, err := v.nodes.List(labels.Everything())
 	if err != nil {
 		return err
 	}
+	return checkBatchRoles(newUpgrade.Spec.NodeBatches, batched, nodes)
+}
 
-	if len(nonLiveMigratableVMNames) > 0 {
-		return werror.NewInvalidError(
-			fmt.Sprintf("There are non-live migratable VMs that need to be shut off before initiating the upgrade: %s", strings.Join(nonLiveMigratableVMNames, ", ")), "",
-		)
+// checkBatchRoles checks that no worker node is in an earlier batch than a control plane node. Rancher upgrades the
+// control plane nodes before the worker nodes, the control plane nodes would wait for the worker nodes forever.
+func checkBatchRoles(batches []v1beta1.UpgradeNodeBatch, batched map[string]int, nodes []*corev1.Node) error {
+	var lastControlPlane, firstWorker string
+	lastControlPlaneBatch, firstWorkerBatch := -1, len(batches)+1
+	for _, node := range nodes {
+		batch, ok := batched[node.Name]
+		if !ok {
+			// the nodes which aren't in any batch are in the last one
+			batch = len(batches)
+		}
+		if ctlnode.IsManagementRole(node) {
+			if batch > lastControlPlaneBatch {
+				lastControlPlane, lastControlPlaneBatch = node.Name, batch
+			}
+		} else if batch < firstWorkerBatch {
+			firstWorker, firstWorkerBatch = node.Name, batch
+		}
 	}
+	if firstWorkerBatch < lastControlPlaneBatch {
+		return werror.NewInvalidError(fmt.Sprintf("worker node %s can't be upgraded before control plane node %s, the control plane nodes are upgraded first",
+			firstWorker, lastControlPlane), "spec.nodeBatches")
+	}
+	return nil
+}
 
+func (v *upgradeValidator) checkCanary(canary *v1beta1.UpgradeCanary) error {
+	if canary == nil {
+		return nil
+	}
+	if canary.Node == "" {
+		return werror.NewInvalidError("canary node is not specified", "spec.canary.node")
+	}
+	node, err := v.nodes.Get(canary.Node)
+	if err != nil {
+		return werror.NewInvalidError(fmt.Sprintf("node %s is not found", canary.Node), "spec.canary.node")
+	}
+	// Rancher upgrades the control plane nodes before the worker nodes, a worker canary would wait for them forever
+	if !ctlnode.IsManagementRole(node) {
+		return werror.NewInvalidError(fmt.Sprintf("canary node %s must be a control plane node", canary.Node), "spec.canary.node")
+	}
+	if canary.SoakSeconds < 0 {
+		return werror.NewInvalidError("soak period can't be negative", "spec.canary.soakSeconds")
+	}
+	for _, sampleVM := range canary.SampleVMs {
+		if namespace, name, err := cache.SplitMetaNamespaceKey(sampleVM); err != nil || namespace == "" || name == "" {
+			return werror.NewInvalidError(fmt.Sprintf("sample VM %s must be in the format of namespace/name", sampleVM), "spec.canary.sampleVMs")
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,118 @@
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
+	ctlnode "github.com/cloudweav/cloudweav/pkg/controller/master/node"
+	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
+)
+
+func TestCheckBatchRoles(t *testing.T) {
+	newNode := func(name string, controlPlane bool) *corev1.Node {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+		if controlPlane {
+			node.Labels[ctlnode.KubeControlPlaneNodeLabelKey] = "true"
+		}
+		return node
+	}
+	nodes := []*corev1.Node{newNode("cp-1", true), newNode("cp-2", true), newNode("worker-1", false), newNode("worker-2", false)}
+
+	var testCases = []struct {
+		name    string
+		batches [][]string
+		errMsg  string
+	}{
+		{
+			name:    "control plane nodes first",
+			batches: [][]string{{"cp-1"}, {"cp-2", "worker-1"}, {"worker-2"}},
+		},
+		{
+			name:    "worker nodes in the last batch",
+			batches: [][]string{{"cp-2"}},
+		},
+		{
+			name:    "worker node before control plane node",
+			batches: [][]string{{"cp-1"}, {"worker-1"}, {"cp-2"}},
+			errMsg:  "worker node worker-1 can't be upgraded before control plane node cp-2",
+		},
+		{
+			name:    "control plane node in the last batch",
+			batches: [][]string{{"cp-1"}, {"worker-1"}},
+			errMsg:  "worker node worker-1 can't be upgraded before control plane node cp-2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var batches []v1beta1.UpgradeNodeBatch
+			batched := make(map[string]int)
+			for i, nodeNames := range tc.batches {
+				batches = append(batches, v1beta1.UpgradeNodeBatch{Nodes: nodeNames})
+				for _, nodeName := range nodeNames {
+					batched[nodeName] = i
+				}
+			}
+
+			err := checkBatchRoles(batches, batched, nodes)
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestCheckCanary(t *testing.T) {
+	clientset := k8sfake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cp-1", Labels: map[string]string{ctlnode.KubeControlPlaneNodeLabelKey: "true"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+	)
+	v := &upgradeValidator{nodes: fakeclients.NodeCache(clientset.CoreV1().Nodes)}
+
+	var testCases = []struct {
+		name   string
+		canary *v1beta1.UpgradeCanary
+		errMsg string
+	}{
+		{
+			name: "no canary",
+		},
+		{
+			name:   "control plane canary",
+			canary: &v1beta1.UpgradeCanary{Node: "cp-1", SampleVMs: []string{"default/vm1"}},
+		},
+		{
+			name:   "worker canary",
+			canary: &v1beta1.UpgradeCanary{Node: "worker-1"},
+			errMsg: "canary node worker-1 must be a control plane node",
+		},
+		{
+			name:   "canary not found",
+			canary: &v1beta1.UpgradeCanary{Node: "cp-2"},
+			errMsg: "node cp-2 is not found",
+		},
+		{
+			name:   "invalid sample VM",
+			canary: &v1beta1.UpgradeCanary{Node: "cp-1", SampleVMs: []string{"vm1"}},
+			errMsg: "sample VM vm1 must be in the format of namespace/name",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := v.checkCanary(tc.canary)
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
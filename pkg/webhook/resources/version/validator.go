@@ -67,6 +67,9 @@ func checkVersion(version *v1beta1.Version) error {
 	if err := checkAnnotations(version); err != nil {
 		return err
 	}
+	if version.Spec.ISOURL == "" && version.Spec.ISOImage == "" {
+		return werror.NewInvalidError("isoURL or isoImage is required", "spec.isoURL")
+	}
 	return checkISOChecksum(version)
 }
 
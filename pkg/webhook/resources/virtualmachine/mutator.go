@@ -3,6 +3,8 @@ package virtualmachine
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -15,8 +17,11 @@ import (
 
 	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
 	ctlcniv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/k8s.cni.cncf.io/v1"
+	"github.com/cloudweav/cloudweav/pkg/ref"
 	"github.com/cloudweav/cloudweav/pkg/settings"
 	"github.com/cloudweav/cloudweav/pkg/util"
+	indexeresutil "github.com/cloudweav/cloudweav/pkg/util/indexeres"
+	"github.com/cloudweav/cloudweav/pkg/util/numa"
 	"github.com/cloudweav/cloudweav/pkg/webhook/types"
 )
 
@@ -27,15 +32,20 @@ const (
 	memory10M  = 10485760
 	memory100M = 104857600
 	memory256M = 268435456
+
+	// imageNodeCacheAffinityWeight is the weight of the preference for nodes holding a cached copy of the VM images
+	imageNodeCacheAffinityWeight = 50
 )
 
 func NewMutator(
 	setting ctlcloudweavv1.SettingCache,
 	nad ctlcniv1.NetworkAttachmentDefinitionCache,
+	vmImage ctlcloudweavv1.VirtualMachineImageCache,
 ) types.Mutator {
 	return &vmMutator{
 		setting: setting,
 		nad:     nad,
+		vmImage: vmImage,
 	}
 }
 
@@ -43,6 +53,7 @@ type vmMutator struct {
 	types.DefaultMutator
 	setting ctlcloudweavv1.SettingCache
 	nad     ctlcniv1.NetworkAttachmentDefinitionCache
+	vmImage ctlcloudweavv1.VirtualMachineImageCache
 }
 
 func (m *vmMutator) Resource() types.Resource {
@@ -69,6 +80,10 @@ func (m *vmMutator) Create(_ *types.Request, newObj runtime.Object) (types.Patch
 		return patchOps, err
 	}
 
+	if err := m.addImageNodeCachePreference(vm); err != nil {
+		return nil, err
+	}
+
 	patchOps, err = m.patchAffinity(vm, patchOps)
 	if err != nil {
 		return nil, err
@@ -79,6 +94,11 @@ func (m *vmMutator) Create(_ *types.Request, newObj runtime.Object) (types.Patch
 		return nil, err
 	}
 
+	patchOps, err = patchSSHKeyPropagation(vm, patchOps)
+	if err != nil {
+		return nil, err
+	}
+
 	return patchOps, nil
 }
 
@@ -117,6 +137,11 @@ func (m *vmMutator) Update(_ *types.Request, oldObj runtime.Object, newObj runti
 		return nil, err
 	}
 
+	patchOps, err = patchSSHKeyPropagation(newVM, patchOps)
+	if err != nil {
+		return nil, err
+	}
+
 	return patchOps, nil
 }
 
@@ -180,6 +205,9 @@ func needUpdateResourceOvercommit(oldVM, newVM *kubevirtv1.VirtualMachine) bool
 	if isDedicatedCPU(oldVM) != isDedicatedCPU(newVM) {
 		return true
 	}
+	if isHugepages(oldVM) != isHugepages(newVM) {
+		return true
+	}
 
 	return hostDevicesOvercommitNeeded(oldVM, newVM)
 }
@@ -247,12 +275,14 @@ func generateMemoryPatch(vm *kubevirtv1.VirtualMachine, mem *resource.Quantity,
 
 	// Reserve 100MiB (104857600 Bytes) for QEMU on guest memory
 	// Ref: https://github.com/cloudweav/cloudweav/issues/1234
-	// TODO: handle hugepage memory
 	reservedMemory := *resource.NewQuantity(memory100M, resource.BinarySI)
 	useReservedMemory := true
 
-	// user has set AnnotationReservedMemory, then use it anyway
-	if vm.Annotations != nil && vm.Annotations[util.AnnotationReservedMemory] != "" {
+	if isHugepages(vm) {
+		// the guest memory is backed by hugepages outside of the pod memory, and has to stay a multiple of the page size
+		useReservedMemory = false
+	} else if vm.Annotations != nil && vm.Annotations[util.AnnotationReservedMemory] != "" {
+		// user has set AnnotationReservedMemory, then use it anyway
 		reservedMemory, err = resource.ParseQuantity(vm.Annotations[util.AnnotationReservedMemory])
 		if err != nil {
 			return patchOps, fmt.Errorf("annotation %v can't be converted to memory unit", vm.Annotations[util.AnnotationReservedMemory])
@@ -287,8 +317,8 @@ func generateMemoryPatch(vm *kubevirtv1.VirtualMachine, mem *resource.Quantity,
 		return patchOps, fmt.Errorf("guest memory is under the minimum requirement (10 Mi), original: %v, final: %v", mem.Value(), guestMemory.Value())
 	}
 
-	if isDedicatedCPU(vm) {
-		// do not apply overcommitted resource since dedicated CPU requires guaranteed QoS
+	if isDedicatedCPU(vm) || isHugepages(vm) {
+		// do not apply overcommitted resource since dedicated CPU requires guaranteed QoS, and hugepages can't be overcommitted
 		// more info, please check https://github.com/kubevirt/kubevirt/blob/8fe1d71accd7d6f5837de514d6b9ddc782c5dd41/pkg/virt-api/webhooks/validating-webhook/admitters/vmi-create-admitter.go#L619
 		quantity = mem
 	} else if hostDevicesPresent(vm) {
@@ -366,6 +396,15 @@ func (m *vmMutator) patchAffinity(vm *kubevirtv1.VirtualMachine, patchOps types.
 		return patchOps, err
 	}
 
+	// a guest NUMA topology is only admitted by the nodes running the single-numa-node topology manager
+	if numa.IsGuestNUMA(&vm.Spec.Template.Spec) {
+		addNodeSelectorRequirement(requiredNodeSelector, v1.NodeSelectorRequirement{
+			Key:      util.LabelNUMAAware,
+			Operator: v1.NodeSelectorOpIn,
+			Values:   []string{"true"},
+		})
+	}
+
 	// The .spec.affinity could not be like `{nodeAffinity:requireDuringSchedulingIgnoreDuringExecution:[]}` if there is not any rules.
 	if len(requiredNodeSelector.NodeSelectorTerms) == 0 {
 		if len(preferredNodeSelector) == 0 {
@@ -382,6 +421,113 @@ func (m *vmMutator) patchAffinity(vm *kubevirtv1.VirtualMachine, patchOps types.
 	return append(patchOps, fmt.Sprintf(`{"op":"replace","path":"/spec/template/spec/affinity","value":%s}`, string(bytes))), nil
 }
 
+// addImageNodeCachePreference makes the scheduler prefer the nodes already holding a cached copy of
+// the images used by the VM volume claim templates. It only runs on creation, the preference is then
+// kept as part of the VM affinity.
+func (m *vmMutator) addImageNodeCachePreference(vm *kubevirtv1.VirtualMachine) error {
+	if vm == nil || vm.Spec.Template == nil {
+		return nil
+	}
+
+	imageIDs, err := indexeresutil.VMByImageID(vm)
+	if err != nil {
+		return err
+	}
+
+	var nodeNames []string
+	for _, imageID := range imageIDs {
+		namespace, name := ref.Parse(imageID)
+		image, err := m.vmImage.Get(namespace, name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		for _, nodeCache := range image.Status.NodeCache {
+			if nodeCache.Ready && !slices.Contains(nodeNames, nodeCache.NodeName) {
+				nodeNames = append(nodeNames, nodeCache.NodeName)
+			}
+		}
+	}
+	if len(nodeNames) == 0 {
+		return nil
+	}
+
+	affinity := vm.Spec.Template.Spec.Affinity
+	if affinity == nil {
+		affinity = &v1.Affinity{}
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &v1.NodeAffinity{}
+	}
+	affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, v1.PreferredSchedulingTerm{
+		Weight: imageNodeCacheAffinityWeight,
+		Preference: v1.NodeSelectorTerm{
+			MatchExpressions: []v1.NodeSelectorRequirement{{
+				Key:      v1.LabelHostname,
+				Operator: v1.NodeSelectorOpIn,
+				Values:   nodeNames,
+			}},
+		},
+	})
+	vm.Spec.Template.Spec.Affinity = affinity
+	return nil
+}
+
+// patchSSHKeyPropagation makes the guest agent propagate the key pairs of the VM to the users listed in the
+// sshKeyPropagationUsers annotation. The keys are read from the secret maintained by the key pair controller,
+// so rotated keys reach the running VM without recreating it.
+func patchSSHKeyPropagation(vm *kubevirtv1.VirtualMachine, patchOps types.PatchOps) (types.PatchOps, error) {
+	if vm == nil || vm.Spec.Template == nil {
+		return patchOps, nil
+	}
+
+	secretName := util.KeyPairPropagationSecretName(vm.Name)
+	credentials := make([]kubevirtv1.AccessCredential, 0, len(vm.Spec.Template.Spec.AccessCredentials)+1)
+	for _, credential := range vm.Spec.Template.Spec.AccessCredentials {
+		if credential.SSHPublicKey != nil && credential.SSHPublicKey.Source.Secret != nil &&
+			credential.SSHPublicKey.Source.Secret.SecretName == secretName {
+			continue
+		}
+		credentials = append(credentials, credential)
+	}
+
+	var users []string
+	for _, user := range strings.Split(vm.Annotations[util.AnnotationSSHKeyPropagationUsers], ",") {
+		if user = strings.TrimSpace(user); user != "" && !slices.Contains(users, user) {
+			users = append(users, user)
+		}
+	}
+	if len(users) > 0 {
+		credentials = append(credentials, kubevirtv1.AccessCredential{
+			SSHPublicKey: &kubevirtv1.SSHPublicKeyAccessCredential{
+				Source: kubevirtv1.SSHPublicKeyAccessCredentialSource{
+					Secret: &kubevirtv1.AccessCredentialSecretSource{
+						SecretName: secretName,
+					},
+				},
+				PropagationMethod: kubevirtv1.SSHPublicKeyAccessCredentialPropagationMethod{
+					QemuGuestAgent: &kubevirtv1.QemuGuestAgentSSHPublicKeyAccessCredentialPropagation{
+						Users: users,
+					},
+				},
+			},
+		})
+	}
+
+	existing := vm.Spec.Template.Spec.AccessCredentials
+	if (len(credentials) == 0 && len(existing) == 0) || reflect.DeepEqual(credentials, existing) {
+		return patchOps, nil
+	}
+
+	bytes, err := json.Marshal(credentials)
+	if err != nil {
+		return patchOps, err
+	}
+	return append(patchOps, fmt.Sprintf(`{"op":"add","path":"/spec/template/spec/accessCredentials","value":%s}`, string(bytes))), nil
+}
+
 func (m *vmMutator) getNodeSelectorRequirementFromNetwork(defaultNamespace string, network kubevirtv1.Network) (*v1.NodeSelectorRequirement, error) {
 	if network.Multus == nil || network.Multus.NetworkName == "" {
 		return nil, nil
@@ -436,12 +582,13 @@ func makeAffinityFromVMTemplate(template *kubevirtv1.VirtualMachineInstanceTempl
 		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &v1.NodeSelector{}
 	}
 
-	// clear node selector terms whose key contains the prefix "network.cloudweavhci.io"
+	// clear node selector terms whose key contains the prefix "network.cloudweavhci.io" or is the NUMA-aware label,
+	// they are added again according to the VM spec
 	nodeSelectorTerms := make([]v1.NodeSelectorTerm, 0, len(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms))
 	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
 		expressions := make([]v1.NodeSelectorRequirement, 0, len(term.MatchExpressions))
 		for _, expression := range term.MatchExpressions {
-			if !strings.HasPrefix(expression.Key, networkGroup) {
+			if !strings.HasPrefix(expression.Key, networkGroup) && expression.Key != util.LabelNUMAAware {
 				expressions = append(expressions, expression)
 			}
 		}
@@ -464,26 +611,29 @@ func (m *vmMutator) addNodeSelectorTerms(defaultNamespace string, nodeSelector *
 		if nodeSelectorRequirement == nil {
 			continue
 		}
-		// Since the terms of node selector are ANDed and the requirements of every term are ORed, we have to add
-		// cluster network requirements to all terms if they are existing.
-		for i, term := range nodeSelector.NodeSelectorTerms {
-			if _, ok := isContainTargetNodeSelectorRequirement(term, *nodeSelectorRequirement); !ok {
-				term.MatchExpressions = append(term.MatchExpressions, *nodeSelectorRequirement)
-				nodeSelector.NodeSelectorTerms[i] = term
-			}
-		}
-		// If there is no term, initialize one with the cluster network requirement to prove that the cluster network
-		// requirement is added
-		if len(nodeSelector.NodeSelectorTerms) == 0 {
-			nodeSelector.NodeSelectorTerms = []v1.NodeSelectorTerm{{
-				MatchExpressions: []v1.NodeSelectorRequirement{*nodeSelectorRequirement},
-			}}
-		}
+		addNodeSelectorRequirement(nodeSelector, *nodeSelectorRequirement)
 	}
 
 	return nil
 }
 
+func addNodeSelectorRequirement(nodeSelector *v1.NodeSelector, nodeSelectorRequirement v1.NodeSelectorRequirement) {
+	// Since the terms of node selector are ANDed and the requirements of every term are ORed, we have to add
+	// the requirement to all terms if they are existing.
+	for i, term := range nodeSelector.NodeSelectorTerms {
+		if _, ok := isContainTargetNodeSelectorRequirement(term, nodeSelectorRequirement); !ok {
+			term.MatchExpressions = append(term.MatchExpressions, nodeSelectorRequirement)
+			nodeSelector.NodeSelectorTerms[i] = term
+		}
+	}
+	// If there is no term, initialize one with the requirement to prove that the requirement is added
+	if len(nodeSelector.NodeSelectorTerms) == 0 {
+		nodeSelector.NodeSelectorTerms = []v1.NodeSelectorTerm{{
+			MatchExpressions: []v1.NodeSelectorRequirement{nodeSelectorRequirement},
+		}}
+	}
+}
+
 func (m *vmMutator) patchTerminationGracePeriodSeconds(vm *kubevirtv1.VirtualMachine, patchOps types.PatchOps) (types.PatchOps, error) {
 	if vm == nil || vm.Spec.Template == nil {
 		return patchOps, nil
@@ -540,3 +690,7 @@ func hostDevicesOvercommitNeeded(oldVM, newVM *kubevirtv1.VirtualMachine) bool {
 func isDedicatedCPU(vm *kubevirtv1.VirtualMachine) bool {
 	return vm.Spec.Template.Spec.Domain.CPU != nil && vm.Spec.Template.Spec.Domain.CPU.DedicatedCPUPlacement
 }
+
+func isHugepages(vm *kubevirtv1.VirtualMachine) bool {
+	return numa.HugepageSize(&vm.Spec.Template.Spec) != ""
+}
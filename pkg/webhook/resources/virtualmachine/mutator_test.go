@@ -17,6 +17,7 @@ import (
 	cloudweavv1 "github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
 	"github.com/cloudweav/cloudweav/pkg/generated/clientset/versioned/fake"
 	"github.com/cloudweav/cloudweav/pkg/settings"
+	"github.com/cloudweav/cloudweav/pkg/util"
 	"github.com/cloudweav/cloudweav/pkg/util/fakeclients"
 	"github.com/cloudweav/cloudweav/pkg/webhook/types"
 )
@@ -268,7 +269,8 @@ func TestPatchResourceOvercommit(t *testing.T) {
 			err := clientset.Tracker().Add(settingCpy)
 			assert.Nil(t, err, "Mock resource should add into fake controller tracker")
 			mutator := NewMutator(fakeclients.CloudweavSettingCache(clientset.CloudweavhciV1beta1().Settings),
-				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions))
+				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+				fakeclients.VirtualMachineImageCache(clientset.CloudweavhciV1beta1().VirtualMachineImages))
 			vm := &kubevirtv1.VirtualMachine{
 				Spec: kubevirtv1.VirtualMachineSpec{
 					Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
@@ -303,7 +305,8 @@ func TestPatchResourceOvercommit(t *testing.T) {
 			err := clientset.Tracker().Add(settingCpy)
 			assert.Nil(t, err, "Mock resource should add into fake controller tracker")
 			mutator := NewMutator(fakeclients.CloudweavSettingCache(clientset.CloudweavhciV1beta1().Settings),
-				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions))
+				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+				fakeclients.VirtualMachineImageCache(clientset.CloudweavhciV1beta1().VirtualMachineImages))
 			vm := &kubevirtv1.VirtualMachine{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
@@ -343,7 +346,8 @@ func TestPatchResourceOvercommit(t *testing.T) {
 			err := clientset.Tracker().Add(settingCpy)
 			assert.Nil(t, err, "Mock resource should add into fake controller tracker")
 			mutator := NewMutator(fakeclients.CloudweavSettingCache(clientset.CloudweavhciV1beta1().Settings),
-				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions))
+				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+				fakeclients.VirtualMachineImageCache(clientset.CloudweavhciV1beta1().VirtualMachineImages))
 			vm := &kubevirtv1.VirtualMachine{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
@@ -380,7 +384,8 @@ func TestPatchResourceOvercommit(t *testing.T) {
 			err := clientset.Tracker().Add(settingCpy)
 			assert.Nil(t, err, "Mock resource should add into fake controller tracker")
 			mutator := NewMutator(fakeclients.CloudweavSettingCache(clientset.CloudweavhciV1beta1().Settings),
-				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions))
+				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+				fakeclients.VirtualMachineImageCache(clientset.CloudweavhciV1beta1().VirtualMachineImages))
 			vm := &kubevirtv1.VirtualMachine{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
@@ -568,7 +573,8 @@ func TestPatchResourceOvercommitWithAdditionalGuestMemoryOverheadRatio(t *testin
 			clientset := fake.NewSimpleClientset()
 			setConfig(clientset, &tc) // #nosec G601
 			mutator := NewMutator(fakeclients.CloudweavSettingCache(clientset.CloudweavhciV1beta1().Settings),
-				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions))
+				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+				fakeclients.VirtualMachineImageCache(clientset.CloudweavhciV1beta1().VirtualMachineImages))
 			vm := &kubevirtv1.VirtualMachine{
 				Spec: kubevirtv1.VirtualMachineSpec{
 					Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
@@ -599,7 +605,8 @@ func TestPatchResourceOvercommitWithAdditionalGuestMemoryOverheadRatio(t *testin
 			clientset := fake.NewSimpleClientset()
 			setConfig(clientset, &tc) // #nosec G601
 			mutator := NewMutator(fakeclients.CloudweavSettingCache(clientset.CloudweavhciV1beta1().Settings),
-				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions))
+				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+				fakeclients.VirtualMachineImageCache(clientset.CloudweavhciV1beta1().VirtualMachineImages))
 			vm := &kubevirtv1.VirtualMachine{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
@@ -634,7 +641,8 @@ func TestPatchResourceOvercommitWithAdditionalGuestMemoryOverheadRatio(t *testin
 			clientset := fake.NewSimpleClientset()
 			setConfig(clientset, &tc) // #nosec G601
 			mutator := NewMutator(fakeclients.CloudweavSettingCache(clientset.CloudweavhciV1beta1().Settings),
-				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions))
+				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+				fakeclients.VirtualMachineImageCache(clientset.CloudweavhciV1beta1().VirtualMachineImages))
 			vm := &kubevirtv1.VirtualMachine{
 				ObjectMeta: metav1.ObjectMeta{},
 				Spec: kubevirtv1.VirtualMachineSpec{
@@ -662,7 +670,8 @@ func TestPatchResourceOvercommitWithAdditionalGuestMemoryOverheadRatio(t *testin
 			clientset := fake.NewSimpleClientset()
 			setConfig(clientset, &tc) // #nosec G601
 			mutator := NewMutator(fakeclients.CloudweavSettingCache(clientset.CloudweavhciV1beta1().Settings),
-				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions))
+				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+				fakeclients.VirtualMachineImageCache(clientset.CloudweavhciV1beta1().VirtualMachineImages))
 			vm := &kubevirtv1.VirtualMachine{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
@@ -694,7 +703,8 @@ func TestPatchResourceOvercommitWithAdditionalGuestMemoryOverheadRatio(t *testin
 			clientset := fake.NewSimpleClientset()
 			setConfig(clientset, &tc) // #nosec G601
 			mutator := NewMutator(fakeclients.CloudweavSettingCache(clientset.CloudweavhciV1beta1().Settings),
-				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions))
+				fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+				fakeclients.VirtualMachineImageCache(clientset.CloudweavhciV1beta1().VirtualMachineImages))
 			vm := &kubevirtv1.VirtualMachine{
 				ObjectMeta: metav1.ObjectMeta{},
 				Spec: kubevirtv1.VirtualMachineSpec{
@@ -750,7 +760,8 @@ func TestPatchResourceOvercommitWithDedicatedCPUPlacement(t *testing.T) {
 	clientset := fake.NewSimpleClientset()
 	clientset.Tracker().Add(setting)
 	mutator := NewMutator(fakeclients.CloudweavSettingCache(clientset.CloudweavhciV1beta1().Settings),
-		fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions))
+		fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+		fakeclients.VirtualMachineImageCache(clientset.CloudweavhciV1beta1().VirtualMachineImages))
 	actual, err := mutator.(*vmMutator).patchResourceOvercommit(vm)
 	assert.Nil(t, err)
 	assert.Equal(t,
@@ -760,6 +771,125 @@ func TestPatchResourceOvercommitWithDedicatedCPUPlacement(t *testing.T) {
 		actual)
 }
 
+func TestPatchResourceOvercommitWithHugepages(t *testing.T) {
+	vm := &kubevirtv1.VirtualMachine{
+		Spec: kubevirtv1.VirtualMachineSpec{
+			Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: kubevirtv1.VirtualMachineInstanceSpec{
+					Domain: kubevirtv1.DomainSpec{
+						Resources: kubevirtv1.ResourceRequirements{
+							Limits: map[v1.ResourceName]resource.Quantity{
+								v1.ResourceCPU:    *resource.NewQuantity(int64(4), resource.DecimalSI),
+								v1.ResourceMemory: *resource.NewQuantity(int64(2*math.Pow(2, 30)), resource.BinarySI), // 2Gi
+							},
+						},
+						Memory: &kubevirtv1.Memory{
+							Hugepages: &kubevirtv1.Hugepages{PageSize: "2Mi"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	setting := &cloudweavv1.Setting{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "overcommit-config",
+		},
+		Default: `{"cpu":200,"memory":400,"storage":800}`,
+	}
+	clientset := fake.NewSimpleClientset()
+	clientset.Tracker().Add(setting)
+	mutator := NewMutator(fakeclients.CloudweavSettingCache(clientset.CloudweavhciV1beta1().Settings),
+		fakeclients.NetworkAttachmentDefinitionCache(clientset.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+		fakeclients.VirtualMachineImageCache(clientset.CloudweavhciV1beta1().VirtualMachineImages))
+	actual, err := mutator.(*vmMutator).patchResourceOvercommit(vm)
+	assert.Nil(t, err)
+	// the hugepages are neither overcommitted nor reserved for QEMU, only the CPU is overcommitted
+	assert.Equal(t,
+		[]string{
+			"{\"op\": \"replace\", \"path\": \"/spec/template/spec/domain/memory/guest\", \"value\": \"2Gi\"}",
+			"{\"op\": \"replace\", \"path\": \"/spec/template/spec/domain/resources/requests\", \"value\": {\"cpu\":\"2\",\"memory\":\"2Gi\"}}"},
+		actual)
+}
+
+func TestPatchAffinityWithGuestNUMA(t *testing.T) {
+	numaRequirement := v1.NodeSelectorRequirement{
+		Key:      util.LabelNUMAAware,
+		Operator: v1.NodeSelectorOpIn,
+		Values:   []string{"true"},
+	}
+	hostRequirement := v1.NodeSelectorRequirement{
+		Key:      v1.LabelHostname,
+		Operator: v1.NodeSelectorOpIn,
+		Values:   []string{"node-1"},
+	}
+	newVM := func(guestNUMA bool) *kubevirtv1.VirtualMachine {
+		vm := &kubevirtv1.VirtualMachine{
+			Spec: kubevirtv1.VirtualMachineSpec{
+				Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+					Spec: kubevirtv1.VirtualMachineInstanceSpec{
+						Affinity: &v1.Affinity{
+							NodeAffinity: &v1.NodeAffinity{
+								RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+									NodeSelectorTerms: []v1.NodeSelectorTerm{{
+										MatchExpressions: []v1.NodeSelectorRequirement{hostRequirement, numaRequirement},
+									}},
+								},
+							},
+						},
+						Domain: kubevirtv1.DomainSpec{
+							CPU: &kubevirtv1.CPU{DedicatedCPUPlacement: true},
+						},
+					},
+				},
+			},
+		}
+		if guestNUMA {
+			vm.Spec.Template.Spec.Domain.CPU.NUMA = &kubevirtv1.NUMA{GuestMappingPassthrough: &kubevirtv1.NUMAGuestMappingPassthrough{}}
+		}
+		return vm
+	}
+
+	tests := []struct {
+		name         string
+		vm           *kubevirtv1.VirtualMachine
+		requirements []v1.NodeSelectorRequirement
+	}{
+		{
+			name:         "the NUMA-aware requirement is kept once for a guest NUMA VM",
+			vm:           newVM(true),
+			requirements: []v1.NodeSelectorRequirement{hostRequirement, numaRequirement},
+		},
+		{
+			name:         "the NUMA-aware requirement is removed without guest NUMA",
+			vm:           newVM(false),
+			requirements: []v1.NodeSelectorRequirement{hostRequirement},
+		},
+	}
+
+	clientSet := fake.NewSimpleClientset()
+	for _, tc := range tests {
+		mutator := NewMutator(fakeclients.CloudweavSettingCache(clientSet.CloudweavhciV1beta1().Settings),
+			fakeclients.NetworkAttachmentDefinitionCache(clientSet.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+			fakeclients.VirtualMachineImageCache(clientSet.CloudweavhciV1beta1().VirtualMachineImages))
+		patchOps, err := mutator.(*vmMutator).patchAffinity(tc.vm, nil)
+		assert.Nil(t, err, tc.name)
+
+		affinity := &v1.Affinity{
+			NodeAffinity: &v1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+					NodeSelectorTerms: []v1.NodeSelectorTerm{{MatchExpressions: tc.requirements}},
+				},
+			},
+		}
+		bytes, err := json.Marshal(affinity)
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, types.PatchOps{fmt.Sprintf(`{"op":"%s","path":"%s","value":%s}`, replaceOP, nodeAffinityPath, bytes)}, patchOps, tc.name)
+	}
+}
+
 func TestPatchAffinity(t *testing.T) {
 	vm1 := &kubevirtv1.VirtualMachine{
 		Spec: kubevirtv1.VirtualMachineSpec{
@@ -1155,7 +1285,8 @@ func TestPatchAffinity(t *testing.T) {
 
 	for _, tc := range tests {
 		mutator := NewMutator(fakeclients.CloudweavSettingCache(clientSet.CloudweavhciV1beta1().Settings),
-			fakeclients.NetworkAttachmentDefinitionCache(clientSet.K8sCniCncfIoV1().NetworkAttachmentDefinitions))
+			fakeclients.NetworkAttachmentDefinitionCache(clientSet.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+			fakeclients.VirtualMachineImageCache(clientSet.CloudweavhciV1beta1().VirtualMachineImages))
 		patchOps, err := mutator.(*vmMutator).patchAffinity(tc.vm, nil)
 		assert.Nil(t, err, tc.name)
 
@@ -1172,3 +1303,165 @@ func TestPatchAffinity(t *testing.T) {
 		assert.Equal(t, types.PatchOps{string(bytes)}, patchOps)
 	}
 }
+
+func TestPatchSSHKeyPropagation(t *testing.T) {
+	propagation := kubevirtv1.AccessCredential{
+		SSHPublicKey: &kubevirtv1.SSHPublicKeyAccessCredential{
+			Source: kubevirtv1.SSHPublicKeyAccessCredentialSource{
+				Secret: &kubevirtv1.AccessCredentialSecretSource{SecretName: "vm-keypairs"},
+			},
+			PropagationMethod: kubevirtv1.SSHPublicKeyAccessCredentialPropagationMethod{
+				QemuGuestAgent: &kubevirtv1.QemuGuestAgentSSHPublicKeyAccessCredentialPropagation{
+					Users: []string{"ubuntu", "root"},
+				},
+			},
+		},
+	}
+	userPassword := kubevirtv1.AccessCredential{
+		UserPassword: &kubevirtv1.UserPasswordAccessCredential{
+			Source: kubevirtv1.UserPasswordAccessCredentialSource{
+				Secret: &kubevirtv1.AccessCredentialSecretSource{SecretName: "passwords"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		credentials []kubevirtv1.AccessCredential
+		expected    []kubevirtv1.AccessCredential
+		patched     bool
+	}{
+		{
+			name:    "no propagation users",
+			patched: false,
+		},
+		{
+			name:        "add guest agent propagation",
+			annotations: map[string]string{"cloudweavhci.io/sshKeyPropagationUsers": "ubuntu, root,ubuntu"},
+			credentials: []kubevirtv1.AccessCredential{userPassword},
+			expected:    []kubevirtv1.AccessCredential{userPassword, propagation},
+			patched:     true,
+		},
+		{
+			name:        "propagation is up to date",
+			annotations: map[string]string{"cloudweavhci.io/sshKeyPropagationUsers": "ubuntu,root"},
+			credentials: []kubevirtv1.AccessCredential{propagation},
+			patched:     false,
+		},
+		{
+			name:        "remove propagation with the annotation",
+			credentials: []kubevirtv1.AccessCredential{userPassword, propagation},
+			expected:    []kubevirtv1.AccessCredential{userPassword},
+			patched:     true,
+		},
+	}
+
+	for _, tc := range tests {
+		vm := &kubevirtv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "vm",
+				Namespace:   "default",
+				Annotations: tc.annotations,
+			},
+			Spec: kubevirtv1.VirtualMachineSpec{
+				Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{
+					Spec: kubevirtv1.VirtualMachineInstanceSpec{
+						AccessCredentials: tc.credentials,
+					},
+				},
+			},
+		}
+		patchOps, err := patchSSHKeyPropagation(vm, nil)
+		assert.Nil(t, err, tc.name)
+		if !tc.patched {
+			assert.Empty(t, patchOps, tc.name)
+			continue
+		}
+
+		bytes, err := json.Marshal(tc.expected)
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, types.PatchOps{fmt.Sprintf(`{"op":"add","path":"/spec/template/spec/accessCredentials","value":%s}`, string(bytes))}, patchOps, tc.name)
+	}
+}
+
+func TestAddImageNodeCachePreference(t *testing.T) {
+	newImage := func(name string, nodeCache ...cloudweavv1.VirtualMachineImageNodeCacheStatus) *cloudweavv1.VirtualMachineImage {
+		return &cloudweavv1.VirtualMachineImage{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     cloudweavv1.VirtualMachineImageStatus{NodeCache: nodeCache},
+		}
+	}
+	clientSet := fake.NewSimpleClientset(
+		newImage("image-a",
+			cloudweavv1.VirtualMachineImageNodeCacheStatus{NodeName: "node1", Ready: true},
+			cloudweavv1.VirtualMachineImageNodeCacheStatus{NodeName: "node2", Ready: false}),
+		newImage("image-b",
+			cloudweavv1.VirtualMachineImageNodeCacheStatus{NodeName: "node1", Ready: true},
+			cloudweavv1.VirtualMachineImageNodeCacheStatus{NodeName: "node3", Ready: true}),
+		newImage("image-c"),
+	)
+	mutator := NewMutator(fakeclients.CloudweavSettingCache(clientSet.CloudweavhciV1beta1().Settings),
+		fakeclients.NetworkAttachmentDefinitionCache(clientSet.K8sCniCncfIoV1().NetworkAttachmentDefinitions),
+		fakeclients.VirtualMachineImageCache(clientSet.CloudweavhciV1beta1().VirtualMachineImages))
+
+	newVM := func(imageIDs ...string) *kubevirtv1.VirtualMachine {
+		var volumeClaimTemplates []v1.PersistentVolumeClaim
+		for _, imageID := range imageIDs {
+			volumeClaimTemplates = append(volumeClaimTemplates, v1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{util.AnnotationImageID: imageID}},
+			})
+		}
+		bytes, err := json.Marshal(volumeClaimTemplates)
+		assert.Nil(t, err)
+		return &kubevirtv1.VirtualMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "vm",
+				Namespace:   "default",
+				Annotations: map[string]string{util.AnnotationVolumeClaimTemplates: string(bytes)},
+			},
+			Spec: kubevirtv1.VirtualMachineSpec{
+				Template: &kubevirtv1.VirtualMachineInstanceTemplateSpec{},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		vm       *kubevirtv1.VirtualMachine
+		expected *v1.Affinity
+	}{
+		{
+			name: "no image",
+			vm:   newVM(),
+		},
+		{
+			name: "image without a cached copy",
+			vm:   newVM("default/image-c", "default/not-found"),
+		},
+		{
+			name: "prefer the nodes with a ready copy of any image",
+			vm:   newVM("default/image-a", "default/image-b"),
+			expected: &v1.Affinity{
+				NodeAffinity: &v1.NodeAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []v1.PreferredSchedulingTerm{{
+						Weight: imageNodeCacheAffinityWeight,
+						Preference: v1.NodeSelectorTerm{
+							MatchExpressions: []v1.NodeSelectorRequirement{{
+								Key:      v1.LabelHostname,
+								Operator: v1.NodeSelectorOpIn,
+								Values:   []string{"node1", "node3"},
+							}},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		err := mutator.(*vmMutator).addImageNodeCachePreference(tc.vm)
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tc.expected, tc.vm.Spec.Template.Spec.Affinity, tc.name)
+	}
+}
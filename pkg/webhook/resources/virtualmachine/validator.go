@@ -18,6 +18,7 @@ import (
 	"github.com/cloudweav/cloudweav/pkg/ref"
 	"github.com/cloudweav/cloudweav/pkg/util"
 	indexeresutil "github.com/cloudweav/cloudweav/pkg/util/indexeres"
+	"github.com/cloudweav/cloudweav/pkg/util/numa"
 	"github.com/cloudweav/cloudweav/pkg/util/resourcequota"
 	vmUtil "github.com/cloudweav/cloudweav/pkg/util/virtualmachine"
 	werror "github.com/cloudweav/cloudweav/pkg/webhook/error"
@@ -25,6 +26,11 @@ import (
 	webhookutil "github.com/cloudweav/cloudweav/pkg/webhook/util"
 )
 
+const (
+	hugepageSize2Mi = "2Mi"
+	hugepageSize1Gi = "1Gi"
+)
+
 func NewValidator(
 	nsCache v1.NamespaceCache,
 	podCache v1.PodCache,
@@ -130,6 +136,12 @@ func (v *vmValidator) checkVMSpec(vm *kubevirtv1.VirtualMachine) error {
 	if err := v.checkReservedMemoryAnnotation(vm); err != nil {
 		return err
 	}
+	if err := v.checkHAPolicyAnnotation(vm); err != nil {
+		return err
+	}
+	if err := v.checkNUMAPlacement(vm); err != nil {
+		return err
+	}
 	return v.rqCalculator.CheckIfVMCanStartByResourceQuota(vm)
 }
 
@@ -293,6 +305,52 @@ func (v *vmValidator) checkReservedMemoryAnnotation(vm *kubevirtv1.VirtualMachin
 	return nil
 }
 
+func (v *vmValidator) checkHAPolicyAnnotation(vm *kubevirtv1.VirtualMachine) error {
+	if vm.Annotations[util.AnnotationHAPolicy] == "" {
+		return nil
+	}
+
+	field := fmt.Sprintf("metadata.annotations[%s]", util.AnnotationHAPolicy)
+	policy, err := vmUtil.GetHAPolicy(vm)
+	if err != nil {
+		return werror.NewInvalidError(err.Error(), field)
+	}
+	if err := policy.Validate(); err != nil {
+		return werror.NewInvalidError(err.Error(), field)
+	}
+	return nil
+}
+
+// checkNUMAPlacement checks the hugepages and the guest NUMA topology of a latency-sensitive VM
+func (v *vmValidator) checkNUMAPlacement(vm *kubevirtv1.VirtualMachine) error {
+	spec := &vm.Spec.Template.Spec
+	if pageSize := numa.HugepageSize(spec); pageSize != "" {
+		field := "spec.template.spec.domain.memory.hugepages.pageSize"
+		if pageSize != hugepageSize2Mi && pageSize != hugepageSize1Gi {
+			return werror.NewInvalidError(fmt.Sprintf("hugepages page size must be %s or %s", hugepageSize2Mi, hugepageSize1Gi), field)
+		}
+		size := resource.MustParse(pageSize)
+		if mem := spec.Domain.Resources.Limits.Memory(); !mem.IsZero() && mem.Value()%size.Value() != 0 {
+			return werror.NewInvalidError(fmt.Sprintf("limits.memory %s must be a multiple of the hugepages page size %s", mem, pageSize), field)
+		}
+		if vm.Annotations[util.AnnotationReservedMemory] != "" {
+			return werror.NewInvalidError("reservedMemory can't be set for a VM backed by hugepages",
+				fmt.Sprintf("metadata.annotations[%s]", util.AnnotationReservedMemory))
+		}
+	}
+
+	if numa.IsGuestNUMA(spec) {
+		field := "spec.template.spec.domain.cpu.numa.guestMappingPassthrough"
+		if !spec.Domain.CPU.DedicatedCPUPlacement {
+			return werror.NewInvalidError("guest NUMA topology requires dedicated CPU placement", field)
+		}
+		if numa.HugepageSize(spec) == "" {
+			return werror.NewInvalidError("guest NUMA topology requires hugepages", field)
+		}
+	}
+	return nil
+}
+
 func (v *vmValidator) checkStorageResourceQuota(vm *kubevirtv1.VirtualMachine, oldVM *kubevirtv1.VirtualMachine) error {
 	return v.rqCalculator.CheckStorageResourceQuota(vm, oldVM)
 }
@@ -18,7 +18,10 @@ import (
 
 	"github.com/cloudweav/cloudweav/pkg/apis/cloudweavhci.io/v1beta1"
 	ctlcloudweavv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/cloudweavhci.io/v1beta1"
+	ctlkubevirtv1 "github.com/cloudweav/cloudweav/pkg/generated/controllers/kubevirt.io/v1"
+	"github.com/cloudweav/cloudweav/pkg/ref"
 	"github.com/cloudweav/cloudweav/pkg/util"
+	indexeresutil "github.com/cloudweav/cloudweav/pkg/util/indexeres"
 	werror "github.com/cloudweav/cloudweav/pkg/webhook/error"
 	"github.com/cloudweav/cloudweav/pkg/webhook/indexeres"
 	"github.com/cloudweav/cloudweav/pkg/webhook/types"
@@ -35,7 +38,8 @@ func NewValidator(
 	vmTemplateVersionCache ctlcloudweavv1.VirtualMachineTemplateVersionCache,
 	secretCache ctlcorev1.SecretCache,
 	storageClassCache ctlstoragev1.StorageClassCache,
-	vmBackupCache ctlcloudweavv1.VirtualMachineBackupCache) types.Validator {
+	vmBackupCache ctlcloudweavv1.VirtualMachineBackupCache,
+	vmCache ctlkubevirtv1.VirtualMachineCache) types.Validator {
 	return &virtualMachineImageValidator{
 		vmimages:               vmimages,
 		pvcCache:               pvcCache,
@@ -44,6 +48,7 @@ func NewValidator(
 		secretCache:            secretCache,
 		storageClassCache:      storageClassCache,
 		vmBackupCache:          vmBackupCache,
+		vmCache:                vmCache,
 	}
 }
 
@@ -57,6 +62,7 @@ type virtualMachineImageValidator struct {
 	secretCache            ctlcorev1.SecretCache
 	storageClassCache      ctlstoragev1.StorageClassCache
 	vmBackupCache          ctlcloudweavv1.VirtualMachineBackupCache
+	vmCache                ctlkubevirtv1.VirtualMachineCache
 }
 
 func (v *virtualMachineImageValidator) Resource() types.Resource {
@@ -84,9 +90,29 @@ func (v *virtualMachineImageValidator) Create(request *types.Request, newObj run
 		return err
 	}
 
+	if err := checkImageNodeCache(newImage); err != nil {
+		return err
+	}
+
 	return v.CheckImagePVC(request, newImage)
 }
 
+func checkImageNodeCache(newImage *v1beta1.VirtualMachineImage) error {
+	if newImage.Spec.NodeCache == nil {
+		return nil
+	}
+
+	if newImage.Spec.NodeCache.NodeSelector == nil {
+		return werror.NewInvalidError("nodeSelector is required", "spec.nodeCache.nodeSelector")
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(newImage.Spec.NodeCache.NodeSelector); err != nil {
+		return werror.NewInvalidError(fmt.Sprintf("invalid nodeSelector: %v", err), "spec.nodeCache.nodeSelector")
+	}
+
+	return nil
+}
+
 func (v *virtualMachineImageValidator) CheckImageDisplayNameAndURL(newImage *v1beta1.VirtualMachineImage) error {
 	if newImage.Spec.DisplayName == "" {
 		return werror.NewInvalidError("displayName is required", fieldDisplayName)
@@ -269,6 +295,10 @@ func (v *virtualMachineImageValidator) Update(_ *types.Request, oldObj runtime.O
 		return werror.NewInvalidError("securityParameters cannot be modified", "spec.securityParameters")
 	}
 
+	if err := checkImageNodeCache(newImage); err != nil {
+		return err
+	}
+
 	return v.CheckImageDisplayNameAndURL(newImage)
 }
 
@@ -293,6 +323,16 @@ func (v *virtualMachineImageValidator) Delete(_ *types.Request, oldObj runtime.O
 		}
 	}
 
+	vms, err := v.vmCache.GetByIndex(indexeresutil.VMByImageIDIndex, ref.Construct(image.Namespace, image.Name))
+	if err != nil {
+		return werror.NewInternalError(err.Error())
+	}
+
+	if len(vms) > 0 {
+		message := fmt.Sprintf("Cannot delete image %s/%s: being used by VM %s/%s", image.Namespace, image.Spec.DisplayName, vms[0].Namespace, vms[0].Name)
+		return werror.NewInvalidError(message, "")
+	}
+
 	pvcs, err := v.pvcCache.List(corev1.NamespaceAll, labels.Everything())
 	if err != nil {
 		return err
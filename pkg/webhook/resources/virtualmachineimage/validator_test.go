@@ -195,7 +195,7 @@ func Test_virtualMachineImageValidator_CheckImageSecurityParameters(t *testing.T
 	fakeSecretCache := fakeclients.SecretCache(coreclientset.CoreV1().Secrets)
 	fakeStorageClassCache := fakeclients.StorageClassCache(coreclientset.StorageV1().StorageClasses)
 	fakeVMBackupCache := fakeclients.VMBackupCache(cloudweavClientSet.CloudweavhciV1beta1().VirtualMachineBackups)
-	validator := NewValidator(fakeVMIMageCache, nil, nil, nil, fakeSecretCache, fakeStorageClassCache, fakeVMBackupCache).(*virtualMachineImageValidator)
+	validator := NewValidator(fakeVMIMageCache, nil, nil, nil, fakeSecretCache, fakeStorageClassCache, fakeVMBackupCache, nil).(*virtualMachineImageValidator)
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
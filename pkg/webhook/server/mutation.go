@@ -23,10 +23,11 @@ func Mutation(clients *clients.Clients, options *config.Options) (http.Handler,
 	storageClassCache := clients.StorageFactory.Storage().V1().StorageClass().Cache()
 	nadCache := clients.CNIFactory.K8s().V1().NetworkAttachmentDefinition().Cache()
 	vmBackupCache := clients.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineBackup().Cache()
+	vmImageCache := clients.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineImage().Cache()
 	mutators := []types.Mutator{
 		pod.NewMutator(settingCache),
 		templateversion.NewMutator(),
-		virtualmachine.NewMutator(settingCache, nadCache),
+		virtualmachine.NewMutator(settingCache, nadCache, vmImageCache),
 		virtualmachineimage.NewMutator(storageClassCache),
 		virtualmachinebackup.NewMutator(vmBackupCache),
 	}
@@ -7,15 +7,18 @@ import (
 
 	"github.com/rancher/wrangler/v3/pkg/webhook"
 
+	"github.com/cloudweav/cloudweav/pkg/upgradehelper/readiness"
 	"github.com/cloudweav/cloudweav/pkg/webhook/clients"
 	"github.com/cloudweav/cloudweav/pkg/webhook/config"
 	"github.com/cloudweav/cloudweav/pkg/webhook/resources/addon"
 	"github.com/cloudweav/cloudweav/pkg/webhook/resources/bundle"
 	"github.com/cloudweav/cloudweav/pkg/webhook/resources/bundledeployment"
 	"github.com/cloudweav/cloudweav/pkg/webhook/resources/keypair"
+	"github.com/cloudweav/cloudweav/pkg/webhook/resources/maintenanceplan"
 	"github.com/cloudweav/cloudweav/pkg/webhook/resources/managedchart"
 	"github.com/cloudweav/cloudweav/pkg/webhook/resources/namespace"
 	"github.com/cloudweav/cloudweav/pkg/webhook/resources/node"
+	"github.com/cloudweav/cloudweav/pkg/webhook/resources/nodebmc"
 	"github.com/cloudweav/cloudweav/pkg/webhook/resources/persistentvolumeclaim"
 	"github.com/cloudweav/cloudweav/pkg/webhook/resources/resourcequota"
 	"github.com/cloudweav/cloudweav/pkg/webhook/resources/schedulevmbackup"
@@ -71,7 +74,8 @@ func Validation(clients *clients.Clients, options *config.Options) (http.Handler
 			clients.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineTemplateVersion().Cache(),
 			clients.Core.Secret().Cache(),
 			clients.StorageFactory.Storage().V1().StorageClass().Cache(),
-			clients.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineBackup().Cache()),
+			clients.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineBackup().Cache(),
+			clients.KubevirtFactory.Kubevirt().V1().VirtualMachine().Cache()),
 		upgrade.NewValidator(
 			clients.CloudweavFactory.Cloudweavhci().V1beta1().Upgrade().Cache(),
 			clients.Core.Node().Cache(),
@@ -83,6 +87,16 @@ func Validation(clients *clients.Clients, options *config.Options) (http.Handler
 			clients.CloudweavFactory.Cloudweavhci().V1beta1().VirtualMachineBackup().Cache(),
 			clients.CloudweavFactory.Cloudweavhci().V1beta1().ScheduleVMBackup().Cache(),
 			clients.KubevirtFactory.Kubevirt().V1().VirtualMachineInstance().Cache(),
+			clients.CloudweavFactory.Cloudweavhci().V1beta1().Setting().Cache(),
+			&readiness.Caches{
+				Nodes:         clients.Core.Node().Cache(),
+				Volumes:       clients.LonghornFactory.Longhorn().V1beta2().Volume().Cache(),
+				LonghornNodes: clients.LonghornFactory.Longhorn().V1beta2().Node().Cache(),
+				VMIs:          clients.KubevirtFactory.Kubevirt().V1().VirtualMachineInstance().Cache(),
+				Jobs:          clients.Batch.Job().Cache(),
+				Addons:        clients.CloudweavFactory.Cloudweavhci().V1beta1().Addon().Cache(),
+				Secrets:       clients.Core.Secret().Cache(),
+			},
 			&http.Client{
 				Transport: transport,
 				Timeout:   time.Second * 20,
@@ -155,6 +169,8 @@ func Validation(clients *clients.Clients, options *config.Options) (http.Handler
 			clients.CloudweavFactory.Cloudweavhci().V1beta1().ScheduleVMBackup().Cache(),
 		),
 		secret.NewValidator(clients.StorageFactory.Storage().V1().StorageClass().Cache()),
+		maintenanceplan.NewValidator(clients.CloudweavFactory.Cloudweavhci().V1beta1().MaintenancePlan().Cache()),
+		nodebmc.NewValidator(clients.K8s.AuthorizationV1().SubjectAccessReviews()),
 	}
 
 	router := webhook.NewRouter()